@@ -28,19 +28,30 @@ import (
 	"github.com/autobrr/qui/internal/config"
 	"github.com/autobrr/qui/internal/database"
 	"github.com/autobrr/qui/internal/domain"
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/metrics"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/polar"
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/internal/services/arr"
 	"github.com/autobrr/qui/internal/services/automations"
+	"github.com/autobrr/qui/internal/services/bulkjobs"
+	"github.com/autobrr/qui/internal/services/categorysync"
 	"github.com/autobrr/qui/internal/services/crossseed"
+	"github.com/autobrr/qui/internal/services/duplicates"
 	"github.com/autobrr/qui/internal/services/filesmanager"
+	"github.com/autobrr/qui/internal/services/hnr"
+	"github.com/autobrr/qui/internal/services/integrity"
 	"github.com/autobrr/qui/internal/services/jackett"
 	"github.com/autobrr/qui/internal/services/license"
+	"github.com/autobrr/qui/internal/services/notifications"
 	"github.com/autobrr/qui/internal/services/orphanscan"
 	"github.com/autobrr/qui/internal/services/reannounce"
+	"github.com/autobrr/qui/internal/services/stats"
 	"github.com/autobrr/qui/internal/services/trackericons"
+	"github.com/autobrr/qui/internal/services/trackerrotation"
+	"github.com/autobrr/qui/internal/services/uploadhistory"
+	"github.com/autobrr/qui/internal/services/watchfolder"
 	"github.com/autobrr/qui/internal/update"
 	"github.com/autobrr/qui/pkg/sqlite3store"
 )
@@ -67,6 +78,7 @@ multiple qBittorrent instances with support for 10k+ torrents.`,
 	rootCmd.AddCommand(RunGenerateConfigCommand())
 	rootCmd.AddCommand(RunCreateUserCommand())
 	rootCmd.AddCommand(RunChangePasswordCommand())
+	rootCmd.AddCommand(RunResetTwoFactorCommand())
 	rootCmd.AddCommand(RunUpdateCommand())
 
 	if err := rootCmd.Execute(); err != nil {
@@ -351,11 +363,22 @@ If no --config-dir is specified, uses the OS-specific default location:
 			}
 
 			userStore = models.NewUserStore(db)
-			if err = userStore.UpdatePassword(ctx, hashedPassword); err != nil {
+			if err = userStore.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
 				return fmt.Errorf("failed to update password: %w", err)
 			}
 
+			// Sign the user out everywhere in case the old password leaked
+			sessionManager := scs.New()
+			sessionManager.Store = sqlite3store.New(db, sqlite3store.WithCleanupInterval(0))
+			revoked, err := auth.RevokeUserSessions(ctx, sessionManager, user.ID, "")
+			if err != nil {
+				return fmt.Errorf("failed to revoke sessions: %w", err)
+			}
+
 			cmd.Printf("Password changed successfully for user '%s'\n", user.Username)
+			if revoked > 0 {
+				cmd.Printf("Signed out %d active session(s)\n", revoked)
+			}
 			return nil
 		},
 	}
@@ -372,6 +395,78 @@ If no --config-dir is specified, uses the OS-specific default location:
 	return command
 }
 
+func RunResetTwoFactorCommand() *cobra.Command {
+	var configDir, dataDir, username string
+
+	command := &cobra.Command{
+		Use:   "reset-2fa",
+		Short: "Disable two-factor authentication for a user",
+		Long: `Disable two-factor authentication for a user account.
+
+Use this when a user has lost their authenticator app and recovery codes.
+Their TOTP secret and recovery codes are removed; they can log in with
+their password and enroll again.
+
+If no --config-dir is specified, uses the OS-specific default location:
+- Linux/macOS: ~/.config/qui/config.toml  
+- Windows: %APPDATA%\qui\config.toml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New(configDir, buildinfo.Version)
+			if err != nil {
+				return fmt.Errorf("failed to initialize configuration: %w", err)
+			}
+
+			if dataDir != "" {
+				cfg.SetDataDir(dataDir)
+			}
+
+			dbPath := cfg.GetDatabasePath()
+			if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+				return fmt.Errorf("database not found at %s", dbPath)
+			}
+
+			db, err := database.New(dbPath)
+			if err != nil {
+				return fmt.Errorf("failed to initialize database: %w", err)
+			}
+			defer db.Close()
+
+			if username == "" {
+				fmt.Print("Enter username: ")
+				if _, err := fmt.Scanln(&username); err != nil {
+					return fmt.Errorf("failed to read username: %w", err)
+				}
+			}
+			username = strings.TrimSpace(username)
+
+			authService := auth.NewService(db)
+			if err := authService.ResetTwoFactor(context.Background(), username); err != nil {
+				switch {
+				case errors.Is(err, models.ErrUserNotFound):
+					return fmt.Errorf("username '%s' not found", username)
+				case errors.Is(err, auth.ErrTwoFactorNotEnabled):
+					cmd.Printf("Two-factor authentication is not enabled for user '%s'\n", username)
+					return nil
+				default:
+					return err
+				}
+			}
+
+			cmd.Printf("Two-factor authentication reset for user '%s'\n", username)
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&configDir, "config-dir", "",
+		"config directory or file path (defaults to OS-specific location)")
+	command.Flags().StringVar(&dataDir, "data-dir", "",
+		"data directory path (defaults to next to config file)")
+	command.Flags().StringVar(&username, "username", "",
+		"user whose two-factor authentication should be reset")
+
+	return command
+}
+
 func RunUpdateCommand() *cobra.Command {
 	var command = &cobra.Command{
 		Use:                   "update",
@@ -496,6 +591,16 @@ func (app *Application) runServer() {
 		log.Fatal().Err(err).Msg("Failed to initialize ARR instance store")
 	}
 	arrIDCacheStore := models.NewArrIDCacheStore(db)
+	notificationProviderStore, err := models.NewNotificationProviderStore(db, cfg.GetEncryptionKey())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize notification provider store")
+	}
+	notificationService := notifications.NewService(notificationProviderStore, instanceStore)
+
+	// Internal event bus: services publish, notifications (and others) subscribe
+	eventBus := events.NewBus()
+	defer eventBus.Close()
+	notificationService.Subscribe(eventBus)
 	errorStore := models.NewInstanceErrorStore(db)
 
 	// Initialize services
@@ -513,6 +618,7 @@ func (app *Application) runServer() {
 		log.Fatal().Err(err).Msg("Failed to initialize client pool")
 	}
 	defer clientPool.Close()
+	clientPool.SetEventBus(eventBus)
 
 	// Initialize managers
 	syncManager := qbittorrent.NewSyncManager(clientPool)
@@ -564,6 +670,7 @@ func (app *Application) runServer() {
 		}),
 		jackett.WithSearchHistory(0),   // Use default capacity (500 entries)
 		jackett.WithIndexerOutcomes(0), // Use default capacity (1000 entries)
+		jackett.WithEventBus(eventBus),
 	)
 	log.Info().Msg("Torznab/Jackett service initialized")
 
@@ -574,7 +681,9 @@ func (app *Application) runServer() {
 	// Initialize cross-seed automation store and service
 	crossSeedStore := models.NewCrossSeedStore(db)
 	instanceCrossSeedCompletionStore := models.NewInstanceCrossSeedCompletionStore(db)
-	crossSeedService := crossseed.NewService(instanceStore, syncManager, filesManagerService, crossSeedStore, jackettService, arrService, externalProgramStore, instanceCrossSeedCompletionStore, trackerCustomizationStore, cfg.Config.CrossSeedRecoverErroredTorrents)
+	crossSeedBudgetStore := models.NewCrossSeedBudgetStore(db)
+	crossSeedBlocklistStore := models.NewCrossSeedBlocklistStore(db)
+	crossSeedService := crossseed.NewService(instanceStore, syncManager, filesManagerService, crossSeedStore, jackettService, arrService, externalProgramStore, instanceCrossSeedCompletionStore, trackerCustomizationStore, crossSeedBudgetStore, crossSeedBlocklistStore, cfg.Config.CrossSeedRecoverErroredTorrents)
 	reannounceService := reannounce.NewService(reannounce.DefaultConfig(), instanceStore, instanceReannounceStore, reannounceSettingsCache, clientPool, syncManager)
 	automationActivityStore := models.NewAutomationActivityStore(db)
 	automationService := automations.NewService(automations.DefaultConfig(), instanceStore, automationStore, automationActivityStore, trackerCustomizationStore, syncManager)
@@ -582,7 +691,19 @@ func (app *Application) runServer() {
 	orphanScanStore := models.NewOrphanScanStore(db)
 	orphanScanService := orphanscan.NewService(orphanscan.DefaultConfig(), instanceStore, orphanScanStore, syncManager)
 
+	integrityStore := models.NewIntegrityStore(db)
+	integrityService := integrity.NewService(integrity.DefaultConfig(), instanceStore, integrityStore, syncManager)
+
+	watchFolderStore := models.NewWatchFolderStore(db)
+	watchFolderService := watchfolder.NewService(watchfolder.DefaultConfig(), watchFolderStore, syncManager, crossSeedService)
+
 	syncManager.SetTorrentCompletionHandler(crossSeedService.HandleTorrentCompletion)
+	crossSeedService.SetEventBus(eventBus)
+	automationService.SetEventBus(eventBus)
+	orphanScanService.SetEventBus(eventBus)
+	reannounceService.SetEventBus(eventBus)
+	reannounceService.SetHistoryStore(models.NewReannounceHistoryStore(db))
+	reannounceService.SetTrackerCustomizationStore(trackerCustomizationStore)
 
 	automationCtx, automationCancel := context.WithCancel(context.Background())
 	defer func() {
@@ -602,11 +723,52 @@ func (app *Application) runServer() {
 	defer orphanScanCancel()
 	orphanScanService.Start(orphanScanCtx)
 
+	integrityCtx, integrityCancel := context.WithCancel(context.Background())
+	defer integrityCancel()
+	integrityService.Start(integrityCtx)
+
+	watchFolderCtx, watchFolderCancel := context.WithCancel(context.Background())
+	defer watchFolderCancel()
+	watchFolderService.Start(watchFolderCtx)
+
+	statsStore := models.NewStatsStore(db)
+	if cfg.Config.StatsEnabled {
+		statsService := stats.NewService(stats.Config{
+			Interval:        time.Duration(cfg.Config.StatsSampleInterval) * time.Second,
+			RawRetention:    time.Duration(cfg.Config.StatsRawRetentionDays) * 24 * time.Hour,
+			HourlyRetention: time.Duration(cfg.Config.StatsHourlyRetentionDays) * 24 * time.Hour,
+			DailyRetention:  time.Duration(cfg.Config.StatsDailyRetentionDays) * 24 * time.Hour,
+		}, instanceStore, statsStore, syncManager)
+		statsCtx, statsCancel := context.WithCancel(context.Background())
+		defer statsCancel()
+		statsService.Start(statsCtx)
+	}
+
+	hnrProfileStore := models.NewHnRProfileStore(db)
+	hnrService := hnr.NewService(hnrProfileStore, instanceStore, syncManager)
+	if err := hnrService.Reload(context.Background()); err != nil {
+		log.Error().Err(err).Msg("Failed to load hit-and-run profiles")
+	}
+	syncManager.SetHnRProvider(hnrService)
+
+	uploadHistoryService := uploadhistory.NewService(uploadhistory.DefaultConfig(), instanceStore, models.NewTorrentUploadHistoryStore(db), syncManager)
+	syncManager.SetUploadHistoryProvider(uploadHistoryService)
+	uploadHistoryCtx, uploadHistoryCancel := context.WithCancel(context.Background())
+	defer uploadHistoryCancel()
+	uploadHistoryService.Start(uploadHistoryCtx)
+
 	backupStore := models.NewBackupStore(db)
 	backupService := backups.NewService(backupStore, syncManager, jackettService, backups.Config{DataDir: cfg.GetDataDir()})
+	backupService.SetEventBus(eventBus)
 	backupService.Start(context.Background())
 	defer backupService.Stop()
 
+	trackerRotationService := trackerrotation.NewService(instanceStore, syncManager, backupService)
+	bulkJobsService := bulkjobs.NewService(syncManager)
+	categoryTemplateStore := models.NewCategoryTemplateStore(db)
+	categorySyncService := categorysync.NewService(categoryTemplateStore, instanceStore, syncManager)
+	duplicatesService := duplicates.NewService(instanceStore, syncManager)
+
 	updateService := update.NewService(log.Logger, cfg.Config.CheckForUpdates, buildinfo.Version, buildinfo.UserAgent)
 	cfg.RegisterReloadListener(func(conf *domain.Config) {
 		updateService.SetEnabled(conf.CheckForUpdates)
@@ -694,8 +856,22 @@ func (app *Application) runServer() {
 		InstanceCrossSeedCompletionStore: instanceCrossSeedCompletionStore,
 		OrphanScanStore:                  orphanScanStore,
 		OrphanScanService:                orphanScanService,
+		IntegrityStore:                   integrityStore,
+		IntegrityService:                 integrityService,
+		WatchFolderStore:                 watchFolderStore,
+		WatchFolderService:               watchFolderService,
 		ArrInstanceStore:                 arrInstanceStore,
 		ArrService:                       arrService,
+		NotificationProviderStore:        notificationProviderStore,
+		NotificationService:              notificationService,
+		StatsStore:                       statsStore,
+		HnRProfileStore:                  hnrProfileStore,
+		HnRService:                       hnrService,
+		TrackerRotationService:           trackerRotationService,
+		BulkJobsService:                  bulkJobsService,
+		CategoryTemplateStore:            categoryTemplateStore,
+		CategorySyncService:              categorySyncService,
+		DuplicatesService:                duplicatesService,
 	})
 
 	// Reconcile any cross-seed runs left in 'running' status from a previous crash/restart.
@@ -721,6 +897,7 @@ func (app *Application) runServer() {
 
 	if cfg.Config.MetricsEnabled {
 		metricsManager := metrics.NewMetricsManager(syncManager, clientPool)
+		metricsManager.SubscribeEvents(eventBus)
 
 		// Start metrics server on separate port
 		go func() {
@@ -55,7 +55,7 @@ func TestCreateUserCommandSkipsWhenUserExists(t *testing.T) {
 
 	db := openDatabase(t, databasePath(configDir))
 	userStore := models.NewUserStore(db)
-	userBefore, err := userStore.Get(ctx)
+	userBefore, err := userStore.GetByUsername(ctx, "testuser")
 	require.NoError(t, err)
 	initialHash := userBefore.PasswordHash
 	require.NoError(t, db.Close())
@@ -71,7 +71,7 @@ func TestCreateUserCommandSkipsWhenUserExists(t *testing.T) {
 	db = openDatabase(t, databasePath(configDir))
 	t.Cleanup(func() { _ = db.Close() })
 
-	userAfter, err := models.NewUserStore(db).Get(ctx)
+	userAfter, err := models.NewUserStore(db).GetByUsername(ctx, "testuser")
 	require.NoError(t, err)
 	assert.Equal(t, initialHash, userAfter.PasswordHash)
 }
@@ -89,7 +89,7 @@ func TestChangePasswordCommandUpdatesStoredHash(t *testing.T) {
 
 	db := openDatabase(t, databasePath(configDir))
 	userStore := models.NewUserStore(db)
-	userBefore, err := userStore.Get(ctx)
+	userBefore, err := userStore.GetByUsername(ctx, "testuser")
 	require.NoError(t, err)
 	oldHash := userBefore.PasswordHash
 	require.NoError(t, db.Close())
@@ -105,7 +105,7 @@ func TestChangePasswordCommandUpdatesStoredHash(t *testing.T) {
 	db = openDatabase(t, databasePath(configDir))
 	t.Cleanup(func() { _ = db.Close() })
 
-	userAfter, err := models.NewUserStore(db).Get(ctx)
+	userAfter, err := models.NewUserStore(db).GetByUsername(ctx, "testuser")
 	require.NoError(t, err)
 	assert.NotEqual(t, oldHash, userAfter.PasswordHash)
 	assert.Contains(t, userAfter.PasswordHash, "$argon2id$")
@@ -142,3 +142,43 @@ func openDatabase(t *testing.T, path string) *database.DB {
 	require.NoError(t, err)
 	return db
 }
+
+func TestResetTwoFactorCommandDisablesTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	configDir := filepath.Join(t.TempDir(), "config")
+	prepareConfigDir(t, configDir)
+
+	mustRunUserCommand(t, RunCreateUserCommand(),
+		"--config-dir", configDir,
+		"--username", "testuser",
+		"--password", "initialpass123",
+	)
+
+	db := openDatabase(t, databasePath(configDir))
+	userStore := models.NewUserStore(db)
+	user, err := userStore.GetByUsername(ctx, "testuser")
+	require.NoError(t, err)
+	require.NoError(t, userStore.SetTOTPSecret(ctx, user.ID, "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, userStore.EnableTOTP(ctx, user.ID, []string{models.HashRecoveryCode("aaaaa-bbbbb")}))
+	require.NoError(t, db.Close())
+
+	output := mustRunUserCommand(t, RunResetTwoFactorCommand(),
+		"--config-dir", configDir,
+		"--username", "testuser",
+	)
+	assert.Contains(t, output, "Two-factor authentication reset for user 'testuser'")
+
+	db = openDatabase(t, databasePath(configDir))
+	t.Cleanup(func() { _ = db.Close() })
+
+	userAfter, err := models.NewUserStore(db).GetByUsername(ctx, "testuser")
+	require.NoError(t, err)
+	assert.False(t, userAfter.TwoFactorEnabled)
+	assert.Empty(t, userAfter.TOTPSecret)
+
+	output = mustRunUserCommand(t, RunResetTwoFactorCommand(),
+		"--config-dir", configDir,
+		"--username", "testuser",
+	)
+	assert.Contains(t, output, "not enabled")
+}
@@ -30,6 +30,7 @@ type AuthHandler struct {
 	instanceStore  *models.InstanceStore
 	clientPool     *qbittorrent.ClientPool
 	syncManager    *qbittorrent.SyncManager
+	loginLimiter   *auth.LoginLimiter
 }
 
 func NewAuthHandler(
@@ -46,11 +47,16 @@ func NewAuthHandler(
 		clientPool:     clientPool,
 		syncManager:    syncManager,
 		config:         config,
+		loginLimiter: auth.NewLoginLimiter(auth.LoginLimits{
+			MaxAttempts:      config.LoginMaxAttempts,
+			MaxAttemptsPerIP: config.LoginMaxAttemptsPerIP,
+			Lockout:          time.Duration(config.LoginLockoutMinutes) * time.Minute,
+		}),
 	}
 
 	// Initialize OIDC handler if enabled
 	if config.OIDCEnabled {
-		oidcHandler, err := NewOIDCHandler(config, sessionManager)
+		oidcHandler, err := NewOIDCHandler(config, sessionManager, authService)
 		if err != nil {
 			return nil, fmt.Errorf("init OIDC handler: %w", err)
 		}
@@ -133,6 +139,7 @@ func (h *AuthHandler) Setup(w http.ResponseWriter, r *http.Request) {
 	h.sessionManager.Put(r.Context(), "authenticated", true)
 	h.sessionManager.Put(r.Context(), "user_id", user.ID)
 	h.sessionManager.Put(r.Context(), "username", user.Username)
+	auth.StartSession(h.sessionManager, r)
 
 	RespondJSON(w, http.StatusCreated, map[string]any{
 		"message": "Setup completed successfully",
@@ -232,10 +239,17 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ip := auth.ClientIP(r)
+	if wait := h.loginLimiter.Check(req.Username, ip); wait > 0 {
+		respondLoginLocked(w, wait)
+		return
+	}
+
 	// Validate credentials
 	user, err := h.authService.Login(r.Context(), req.Username, req.Password)
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
+			h.recordLoginFailure(req.Username, ip)
 			RespondError(w, http.StatusUnauthorized, "Invalid credentials")
 			return
 		}
@@ -248,6 +262,29 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Accounts with two-factor enabled finish logging in at /auth/login/2fa
+	if user.TwoFactorEnabled {
+		h.startTwoFactorLogin(w, r, user, req.RememberMe)
+		return
+	}
+
+	h.completeLogin(w, r, user, req.RememberMe)
+}
+
+// recordLoginFailure counts a failed password or two-factor code
+func (h *AuthHandler) recordLoginFailure(username, ip string) {
+	if wait := h.loginLimiter.Failure(username, ip); wait > 0 {
+		log.Warn().Str("username", username).Str("ip", ip).Dur("lockout", wait).Msg("Too many failed logins, locking out")
+	}
+}
+
+func respondLoginLocked(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds()+0.5)))
+	RespondError(w, http.StatusTooManyRequests, "Too many failed login attempts, try again later")
+}
+
+// completeLogin creates the authenticated session for a password login
+func (h *AuthHandler) completeLogin(w http.ResponseWriter, r *http.Request, user *models.User, rememberMe bool) {
 	// Create session using SCS
 	// Renew token to prevent session fixation attacks
 	if err := h.sessionManager.RenewToken(r.Context()); err != nil {
@@ -258,9 +295,12 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	h.sessionManager.Put(r.Context(), "user_id", user.ID)
 	h.sessionManager.Put(r.Context(), "username", user.Username)
 	h.sessionManager.Put(r.Context(), "auth_method", "password")
+	auth.StartSession(h.sessionManager, r)
 
 	// Handle remember_me functionality
-	h.sessionManager.RememberMe(r.Context(), req.RememberMe)
+	h.sessionManager.RememberMe(r.Context(), rememberMe)
+
+	h.loginLimiter.Success(user.Username)
 
 	// Warm the session by prefetching data in the background
 	// Use a detached context since this should continue even after the HTTP request completes
@@ -304,17 +344,20 @@ func (h *AuthHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// For OIDC users, we might not have a user_id
-	userID := h.sessionManager.GetInt(r.Context(), "user_id")
 	authMethod := h.sessionManager.GetString(r.Context(), "auth_method")
 
 	response := map[string]any{
 		"username": username,
 	}
 
-	// Only include ID if it exists (for built-in auth users)
-	if userID != 0 {
-		response["id"] = userID
+	// Include the account's role and instance grants so the UI can hide what the user can't do
+	if user, ok := auth.UserFromContext(r.Context()); ok {
+		response["id"] = user.ID
+		response["username"] = user.Username
+		response["role"] = user.Role
+		response["allInstances"] = user.AllInstances
+		response["instanceIds"] = user.InstanceIDs
+		response["twoFactorEnabled"] = user.TwoFactorEnabled
 	}
 
 	// Include auth method if available
@@ -373,8 +416,14 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok || user.ID == 0 {
+		RespondError(w, http.StatusForbidden, "Password can only be changed for user accounts")
+		return
+	}
+
 	// Change password
-	if err := h.authService.ChangePassword(r.Context(), req.CurrentPassword, req.NewPassword); err != nil {
+	if err := h.authService.ChangePassword(r.Context(), user.ID, req.CurrentPassword, req.NewPassword); err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
 			RespondError(w, http.StatusUnauthorized, "Invalid current password")
 			return
@@ -384,6 +433,11 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Sign out everywhere else in case the old password leaked
+	if _, err := auth.RevokeUserSessions(r.Context(), h.sessionManager, user.ID, h.sessionManager.Token(r.Context())); err != nil {
+		log.Error().Err(err).Msg("Failed to revoke other sessions after password change")
+	}
+
 	RespondJSON(w, http.StatusOK, map[string]string{
 		"message": "Password changed successfully",
 	})
@@ -391,9 +445,14 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 // API Key Management
 
-// CreateAPIKeyRequest represents a request to create an API key
+// CreateAPIKeyRequest represents a request to create an API key.
+// Without scopes the key gets full (admin) access.
 type CreateAPIKeyRequest struct {
-	Name string `json:"name"`
+	Name        string               `json:"name"`
+	Scopes      []models.APIKeyScope `json:"scopes"`
+	InstanceIDs []int                `json:"instanceIds"`
+	AllowedIPs  []string             `json:"allowedIps"`
+	ExpiresAt   *time.Time           `json:"expiresAt"`
 }
 
 // CreateAPIKey creates a new API key
@@ -409,8 +468,32 @@ func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	opts := models.APIKeyOptions{
+		Scopes:      req.Scopes,
+		InstanceIDs: req.InstanceIDs,
+		AllowedIPs:  req.AllowedIPs,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if len(opts.Scopes) == 0 {
+		opts.Scopes = []models.APIKeyScope{models.APIKeyScopeAdmin}
+	}
+	if err := opts.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.ExpiresAt != nil && !opts.ExpiresAt.After(time.Now()) {
+		RespondError(w, http.StatusBadRequest, "Expiry must be in the future")
+		return
+	}
+	for _, instanceID := range opts.InstanceIDs {
+		if _, err := h.instanceStore.Get(r.Context(), instanceID); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("Unknown instance ID %d", instanceID))
+			return
+		}
+	}
+
 	// Create API key
-	rawKey, apiKey, err := h.authService.CreateAPIKey(r.Context(), req.Name)
+	rawKey, apiKey, err := h.authService.CreateScopedAPIKey(r.Context(), req.Name, opts)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create API key")
 		RespondError(w, http.StatusInternalServerError, "Failed to create API key")
@@ -418,11 +501,15 @@ func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	RespondJSON(w, http.StatusCreated, map[string]any{
-		"id":        apiKey.ID,
-		"name":      apiKey.Name,
-		"key":       rawKey, // Only shown once
-		"createdAt": apiKey.CreatedAt,
-		"message":   "Save this key securely - it will not be shown again",
+		"id":          apiKey.ID,
+		"name":        apiKey.Name,
+		"key":         rawKey, // Only shown once
+		"createdAt":   apiKey.CreatedAt,
+		"scopes":      apiKey.Scopes,
+		"instanceIds": apiKey.InstanceIDs,
+		"allowedIps":  apiKey.AllowedIPs,
+		"expiresAt":   apiKey.ExpiresAt,
+		"message":     "Save this key securely - it will not be shown again",
 	})
 }
 
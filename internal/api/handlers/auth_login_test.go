@@ -0,0 +1,121 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/domain"
+	"github.com/autobrr/qui/internal/models"
+)
+
+func newLoginTestHandler(t *testing.T, cfg *domain.Config) (*AuthHandler, *auth.Service, http.Handler) {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	instanceStore, err := models.NewInstanceStore(db, bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	authService := auth.NewService(db)
+	_, err = authService.SetupUser(t.Context(), "admin", "password123")
+	require.NoError(t, err)
+
+	handler, err := NewAuthHandler(authService, scs.New(), cfg, instanceStore, nil, nil)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", handler.Login)
+	mux.HandleFunc("POST /login/2fa", handler.LoginTwoFactor)
+	return handler, authService, handler.sessionManager.LoadAndSave(mux)
+}
+
+// postJSON sends body from remoteAddr, carrying over any session cookie.
+func postJSON(t *testing.T, h http.Handler, path, body, remoteAddr string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = remoteAddr
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLoginLockout(t *testing.T) {
+	_, _, h := newLoginTestHandler(t, &domain.Config{
+		LoginMaxAttempts:      3,
+		LoginMaxAttemptsPerIP: 10,
+		LoginLockoutMinutes:   15,
+	})
+
+	for range 3 {
+		rec := postJSON(t, h, "/login", `{"username":"admin","password":"wrong"}`, "10.0.0.1:1000", nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+
+	// Locked even with the right password, from any address
+	rec := postJSON(t, h, "/login", `{"username":"admin","password":"password123"}`, "10.0.0.2:1000", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "900", rec.Header().Get("Retry-After"))
+}
+
+func TestLoginWithTwoFactor(t *testing.T) {
+	ctx := t.Context()
+	handler, authService, h := newLoginTestHandler(t, &domain.Config{
+		LoginMaxAttempts:    5,
+		LoginLockoutMinutes: 15,
+	})
+
+	enrollment, err := authService.BeginTOTPEnrollment(ctx, 1)
+	require.NoError(t, err)
+	previous, err := totp.GenerateCode(enrollment.Secret, time.Now().Add(-30*time.Second))
+	require.NoError(t, err)
+	_, err = authService.EnableTOTP(ctx, 1, previous)
+	require.NoError(t, err)
+
+	rec := postJSON(t, h, "/login", `{"username":"admin","password":"password123"}`, "10.0.0.1:1000", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"twoFactorRequired":true`)
+	cookies := rec.Result().Cookies()
+
+	rec = postJSON(t, h, "/login/2fa", `{"code":"000000"}`, "10.0.0.1:1000", cookies)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	rec = postJSON(t, h, "/login/2fa", `{"code":"`+code+`"}`, "10.0.0.1:1000", cookies)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Login successful")
+
+	sessions, err := auth.ListSessions(ctx, handler.sessionManager, 1, "")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "10.0.0.1", sessions[0].IP)
+	assert.Equal(t, "password", sessions[0].AuthMethod)
+
+	// Without a pending login the second step is rejected
+	rec = postJSON(t, h, "/login/2fa", `{"code":"`+code+`"}`, "10.0.0.3:1000", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
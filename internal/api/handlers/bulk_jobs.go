@@ -0,0 +1,196 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/qbittorrent"
+	"github.com/autobrr/qui/internal/services/bulkjobs"
+)
+
+type BulkJobsHandler struct {
+	service     *bulkjobs.Service
+	syncManager *qbittorrent.SyncManager
+}
+
+func NewBulkJobsHandler(service *bulkjobs.Service, syncManager *qbittorrent.SyncManager) *BulkJobsHandler {
+	return &BulkJobsHandler{
+		service:     service,
+		syncManager: syncManager,
+	}
+}
+
+func parseBulkJobID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	jobID, err := strconv.ParseInt(chi.URLParam(r, "jobID"), 10, 64)
+	if err != nil || jobID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid job ID")
+		return 0, false
+	}
+	return jobID, true
+}
+
+// SubmitJob queues a bulk action to run in the background. It accepts the same body as
+// the synchronous bulk action endpoint.
+func (h *BulkJobsHandler) SubmitJob(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	var req BulkActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !req.SelectAll && len(req.Hashes) == 0 {
+		RespondError(w, http.StatusBadRequest, "No torrents selected")
+		return
+	}
+
+	if req.SelectAll && len(req.Hashes) > 0 {
+		RespondError(w, http.StatusBadRequest, "Cannot specify both hashes and selectAll")
+		return
+	}
+
+	spec := bulkjobs.Spec{
+		Action:                   req.Action,
+		DeleteFiles:              req.DeleteFiles,
+		Tags:                     req.Tags,
+		Category:                 req.Category,
+		Enable:                   req.Enable,
+		RatioLimit:               req.RatioLimit,
+		SeedingTimeLimit:         req.SeedingTimeLimit,
+		InactiveSeedingTimeLimit: req.InactiveSeedingTimeLimit,
+		UploadLimit:              req.UploadLimit,
+		DownloadLimit:            req.DownloadLimit,
+		Location:                 req.Location,
+		TrackerOldURL:            req.TrackerOldURL,
+		TrackerNewURL:            req.TrackerNewURL,
+		TrackerURLs:              req.TrackerURLs,
+	}
+	if err := spec.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	targetHashes, ok := resolveBulkTargets(w, r, h.syncManager, instanceID, &req)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.Submit(instanceID, spec, targetHashes)
+	if err != nil {
+		if errors.Is(err, bulkjobs.ErrJobRunning) {
+			RespondError(w, http.StatusConflict, "A bulk job is already running for this instance")
+			return
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Str("action", req.Action).Msg("Failed to submit bulk job")
+		RespondError(w, http.StatusInternalServerError, "Failed to submit bulk job")
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, job)
+}
+
+// ListJobs returns recent bulk jobs for an instance, newest first, without per-torrent errors.
+func (h *BulkJobsHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, h.service.ListJobs(instanceID))
+}
+
+// GetJob returns the progress and per-torrent errors of a bulk job.
+func (h *BulkJobsHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	jobID, ok := parseBulkJobID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(instanceID, jobID)
+	if err != nil {
+		if errors.Is(err, bulkjobs.ErrJobNotFound) {
+			RespondError(w, http.StatusNotFound, "Bulk job not found")
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, "Failed to load bulk job")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, job)
+}
+
+// CancelJob stops a running bulk job after its current chunk.
+func (h *BulkJobsHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	jobID, ok := parseBulkJobID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Cancel(instanceID, jobID); err != nil {
+		switch {
+		case errors.Is(err, bulkjobs.ErrJobNotFound):
+			RespondError(w, http.StatusNotFound, "Bulk job not found")
+		case errors.Is(err, bulkjobs.ErrJobNotRunning):
+			RespondError(w, http.StatusConflict, "Bulk job is not running")
+		default:
+			log.Error().Err(err).Int64("jobID", jobID).Msg("Failed to cancel bulk job")
+			RespondError(w, http.StatusInternalServerError, "Failed to cancel bulk job")
+		}
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{"status": "canceled"})
+}
+
+// UndoJob restores the state recorded by a finished bulk job in a new job.
+func (h *BulkJobsHandler) UndoJob(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	jobID, ok := parseBulkJobID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.Undo(instanceID, jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, bulkjobs.ErrJobNotFound):
+			RespondError(w, http.StatusNotFound, "Bulk job not found")
+		case errors.Is(err, bulkjobs.ErrNotUndoable):
+			RespondError(w, http.StatusConflict, "Bulk job cannot be undone")
+		case errors.Is(err, bulkjobs.ErrJobRunning):
+			RespondError(w, http.StatusConflict, "A bulk job is already running for this instance")
+		default:
+			log.Error().Err(err).Int64("jobID", jobID).Msg("Failed to undo bulk job")
+			RespondError(w, http.StatusInternalServerError, "Failed to undo bulk job")
+		}
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, job)
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/categorysync"
+)
+
+type CategorySyncHandler struct {
+	store         *models.CategoryTemplateStore
+	instanceStore *models.InstanceStore
+	service       *categorysync.Service
+}
+
+func NewCategorySyncHandler(store *models.CategoryTemplateStore, instanceStore *models.InstanceStore, service *categorysync.Service) *CategorySyncHandler {
+	return &CategorySyncHandler{
+		store:         store,
+		instanceStore: instanceStore,
+		service:       service,
+	}
+}
+
+// CategoryTemplatesPayload is the request body for replacing the templates.
+type CategoryTemplatesPayload struct {
+	Categories []struct {
+		Name     string `json:"name"`
+		SavePath string `json:"savePath"`
+	} `json:"categories"`
+	Tags []string `json:"tags"`
+}
+
+// CategorySyncTargetsPayload is the request body for replacing the sync targets.
+type CategorySyncTargetsPayload struct {
+	Targets []struct {
+		InstanceID     int    `json:"instanceId"`
+		SavePathPrefix string `json:"savePathPrefix"`
+	} `json:"targets"`
+}
+
+// GetTemplates returns the category and tag templates.
+func (h *CategorySyncHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.store.GetTemplates(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("categorysync: failed to load templates")
+		RespondError(w, http.StatusInternalServerError, "Failed to load templates")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, templates)
+}
+
+// UpdateTemplates replaces the category and tag templates.
+func (h *CategorySyncHandler) UpdateTemplates(w http.ResponseWriter, r *http.Request) {
+	var payload CategoryTemplatesPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	categories := make([]models.CategoryTemplate, 0, len(payload.Categories))
+	seenCategories := make(map[string]struct{}, len(payload.Categories))
+	for _, category := range payload.Categories {
+		name := strings.TrimSpace(category.Name)
+		if name == "" {
+			RespondError(w, http.StatusBadRequest, "Category name is required")
+			return
+		}
+		if _, ok := seenCategories[name]; ok {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("Duplicate category %q", name))
+			return
+		}
+		seenCategories[name] = struct{}{}
+		categories = append(categories, models.CategoryTemplate{Name: name, SavePath: strings.TrimSpace(category.SavePath)})
+	}
+
+	tags := make([]string, 0, len(payload.Tags))
+	seenTags := make(map[string]struct{}, len(payload.Tags))
+	for _, tag := range payload.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if strings.Contains(tag, ",") {
+			RespondError(w, http.StatusBadRequest, "Tags must not contain commas")
+			return
+		}
+		if _, ok := seenTags[tag]; ok {
+			continue
+		}
+		seenTags[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+
+	templates, err := h.store.ReplaceTemplates(r.Context(), categories, tags)
+	if err != nil {
+		log.Error().Err(err).Msg("categorysync: failed to save templates")
+		RespondError(w, http.StatusInternalServerError, "Failed to save templates")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, templates)
+}
+
+// GetTargets returns the instances that receive the templates.
+func (h *CategorySyncHandler) GetTargets(w http.ResponseWriter, r *http.Request) {
+	targets, err := h.store.ListTargets(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("categorysync: failed to load targets")
+		RespondError(w, http.StatusInternalServerError, "Failed to load targets")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, targets)
+}
+
+// UpdateTargets replaces the instances that receive the templates.
+func (h *CategorySyncHandler) UpdateTargets(w http.ResponseWriter, r *http.Request) {
+	var payload CategorySyncTargetsPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	targets := make([]models.CategorySyncTarget, 0, len(payload.Targets))
+	seen := make(map[int]struct{}, len(payload.Targets))
+	for _, target := range payload.Targets {
+		if _, ok := seen[target.InstanceID]; ok {
+			continue
+		}
+		seen[target.InstanceID] = struct{}{}
+
+		if _, err := h.instanceStore.Get(r.Context(), target.InstanceID); err != nil {
+			if errors.Is(err, models.ErrInstanceNotFound) {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("Instance %d not found", target.InstanceID))
+				return
+			}
+			log.Error().Err(err).Int("instanceID", target.InstanceID).Msg("categorysync: failed to get instance")
+			RespondError(w, http.StatusInternalServerError, "Failed to get instance")
+			return
+		}
+		targets = append(targets, models.CategorySyncTarget{
+			InstanceID:     target.InstanceID,
+			SavePathPrefix: strings.TrimSpace(target.SavePathPrefix),
+		})
+	}
+
+	saved, err := h.store.ReplaceTargets(r.Context(), targets)
+	if err != nil {
+		log.Error().Err(err).Msg("categorysync: failed to save targets")
+		RespondError(w, http.StatusInternalServerError, "Failed to save targets")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, saved)
+}
+
+// GetDrift compares every sync target against the templates.
+func (h *CategorySyncHandler) GetDrift(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.Drift(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("categorysync: failed to compute drift")
+		RespondError(w, http.StatusInternalServerError, "Failed to compute drift")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, reports)
+}
+
+// Reconcile applies the templates to the requested sync targets, or to all of them
+// when no instance IDs are given.
+func (h *CategorySyncHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		InstanceIDs []int `json:"instanceIds"`
+		RemoveExtra bool  `json:"removeExtra"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	instanceIDs := payload.InstanceIDs
+	if len(instanceIDs) == 0 {
+		targets, err := h.store.ListTargets(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("categorysync: failed to load targets")
+			RespondError(w, http.StatusInternalServerError, "Failed to load targets")
+			return
+		}
+		for _, target := range targets {
+			instanceIDs = append(instanceIDs, target.InstanceID)
+		}
+	}
+
+	results := make([]*categorysync.ReconcileResult, 0, len(instanceIDs))
+	for _, instanceID := range instanceIDs {
+		result, err := h.service.Reconcile(r.Context(), instanceID, payload.RemoveExtra)
+		if err != nil {
+			if errors.Is(err, categorysync.ErrNotTarget) {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("Instance %d is not a sync target", instanceID))
+				return
+			}
+			log.Error().Err(err).Int("instanceID", instanceID).Msg("categorysync: failed to reconcile")
+			RespondError(w, http.StatusInternalServerError, "Failed to reconcile")
+			return
+		}
+		results = append(results, result)
+	}
+
+	RespondJSON(w, http.StatusOK, results)
+}
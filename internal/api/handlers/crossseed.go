@@ -5,6 +5,7 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -85,6 +86,8 @@ type automationSettingsPatchRequest struct {
 	SkipAutoResumeWebhook        *bool `json:"skipAutoResumeWebhook,omitempty"`
 	SkipRecheck                  *bool `json:"skipRecheck,omitempty"`
 	SkipPieceBoundarySafetyCheck *bool `json:"skipPieceBoundarySafetyCheck,omitempty"`
+	// Run reports
+	RunReportWebhookURL *string `json:"runReportWebhookUrl,omitempty"`
 }
 
 type optionalString struct {
@@ -176,7 +179,8 @@ func (r automationSettingsPatchRequest) isEmpty() bool {
 		r.SkipAutoResumeCompletion == nil &&
 		r.SkipAutoResumeWebhook == nil &&
 		r.SkipRecheck == nil &&
-		r.SkipPieceBoundarySafetyCheck == nil
+		r.SkipPieceBoundarySafetyCheck == nil &&
+		r.RunReportWebhookURL == nil
 }
 
 func applyAutomationSettingsPatch(settings *models.CrossSeedAutomationSettings, patch automationSettingsPatchRequest) {
@@ -292,6 +296,9 @@ func applyAutomationSettingsPatch(settings *models.CrossSeedAutomationSettings,
 	if patch.SkipPieceBoundarySafetyCheck != nil {
 		settings.SkipPieceBoundarySafetyCheck = *patch.SkipPieceBoundarySafetyCheck
 	}
+	if patch.RunReportWebhookURL != nil {
+		settings.RunReportWebhookURL = *patch.RunReportWebhookURL
+	}
 }
 
 type automationRunRequest struct {
@@ -338,6 +345,7 @@ func (h *CrossSeedHandler) Routes(r chi.Router) {
 		r.Put("/settings", h.UpdateAutomationSettings)
 		r.Get("/status", h.GetAutomationStatus)
 		r.Get("/runs", h.ListAutomationRuns)
+		r.Get("/runs/{runID}/report", h.GetAutomationRunReport)
 		r.Post("/run", h.TriggerAutomationRun)
 		r.Post("/run/cancel", h.CancelAutomationRun)
 		r.Route("/search", func(r chi.Router) {
@@ -347,11 +355,23 @@ func (h *CrossSeedHandler) Routes(r chi.Router) {
 			r.Post("/run", h.StartSearchRun)
 			r.Post("/run/cancel", h.CancelSearchRun)
 			r.Get("/runs", h.ListSearchRunHistory)
+			r.Get("/runs/{runID}/report", h.GetSearchRunReport)
 		})
 		r.Route("/completion", func(r chi.Router) {
 			r.Get("/{instanceID}", h.GetInstanceCompletionSettings)
 			r.Put("/{instanceID}", h.UpdateInstanceCompletionSettings)
 		})
+		r.Route("/budgets", func(r chi.Router) {
+			r.Get("/", h.ListIndexerBudgets)
+			r.Put("/{indexerID}", h.UpsertIndexerBudget)
+			r.Delete("/{indexerID}", h.DeleteIndexerBudget)
+		})
+		r.Route("/blocklist", func(r chi.Router) {
+			r.Get("/", h.ListBlocklist)
+			r.Post("/", h.CreateBlocklistEntry)
+			r.Post("/from-cross-seed", h.BlocklistFromCrossSeed)
+			r.Delete("/{entryID}", h.DeleteBlocklistEntry)
+		})
 		r.Route("/webhook", func(r chi.Router) {
 			r.Post("/check", h.WebhookCheck)
 		})
@@ -1364,3 +1384,318 @@ func (h *CrossSeedHandler) UpdateInstanceCompletionSettings(w http.ResponseWrite
 
 	RespondJSON(w, http.StatusOK, toInstanceCompletionSettingsResponse(saved))
 }
+
+// indexerBudgetRequest is the payload for creating or updating an indexer snatch budget.
+type indexerBudgetRequest struct {
+	MaxDownloadsPerDay   int `json:"maxDownloadsPerDay"`
+	MaxInjectionsPerDay  int `json:"maxInjectionsPerDay"`
+	MinReleaseAgeMinutes int `json:"minReleaseAgeMinutes"`
+}
+
+// ListIndexerBudgets godoc
+// @Summary List per-indexer cross-seed budgets
+// @Description Returns the daily download/injection caps and minimum release age configured per indexer
+// @Tags cross-seed
+// @Produce json
+// @Success 200 {array} models.CrossSeedIndexerBudget
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/budgets [get]
+func (h *CrossSeedHandler) ListIndexerBudgets(w http.ResponseWriter, r *http.Request) {
+	budgets, err := h.service.ListIndexerBudgets(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list cross-seed indexer budgets")
+		RespondError(w, http.StatusInternalServerError, "Failed to list indexer budgets")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, budgets)
+}
+
+// UpsertIndexerBudget godoc
+// @Summary Create or update a per-indexer cross-seed budget
+// @Description Sets the daily download/injection caps and minimum release age for an indexer. Zero means unlimited.
+// @Tags cross-seed
+// @Accept json
+// @Produce json
+// @Param indexerID path int true "Torznab indexer ID"
+// @Param request body indexerBudgetRequest true "Budget limits"
+// @Success 200 {object} models.CrossSeedIndexerBudget
+// @Failure 400 {object} httphelpers.ErrorResponse
+// @Failure 404 {object} httphelpers.ErrorResponse
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/budgets/{indexerID} [put]
+func (h *CrossSeedHandler) UpsertIndexerBudget(w http.ResponseWriter, r *http.Request) {
+	indexerID, err := strconv.Atoi(chi.URLParam(r, "indexerID"))
+	if err != nil || indexerID <= 0 {
+		RespondError(w, http.StatusBadRequest, "indexerID must be a positive integer")
+		return
+	}
+
+	var req indexerBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	budget, err := h.service.UpsertIndexerBudget(r.Context(), &models.CrossSeedIndexerBudget{
+		IndexerID:            indexerID,
+		MaxDownloadsPerDay:   req.MaxDownloadsPerDay,
+		MaxInjectionsPerDay:  req.MaxInjectionsPerDay,
+		MinReleaseAgeMinutes: req.MinReleaseAgeMinutes,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, crossseed.ErrInvalidRequest):
+			RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, sql.ErrNoRows):
+			RespondError(w, http.StatusNotFound, "Indexer not found")
+		default:
+			log.Error().Err(err).Int("indexerID", indexerID).Msg("Failed to update cross-seed indexer budget")
+			RespondError(w, http.StatusInternalServerError, "Failed to update indexer budget")
+		}
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, budget)
+}
+
+// DeleteIndexerBudget godoc
+// @Summary Delete a per-indexer cross-seed budget
+// @Description Removes all snatch limits for an indexer
+// @Tags cross-seed
+// @Param indexerID path int true "Torznab indexer ID"
+// @Success 204
+// @Failure 400 {object} httphelpers.ErrorResponse
+// @Failure 404 {object} httphelpers.ErrorResponse
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/budgets/{indexerID} [delete]
+func (h *CrossSeedHandler) DeleteIndexerBudget(w http.ResponseWriter, r *http.Request) {
+	indexerID, err := strconv.Atoi(chi.URLParam(r, "indexerID"))
+	if err != nil || indexerID <= 0 {
+		RespondError(w, http.StatusBadRequest, "indexerID must be a positive integer")
+		return
+	}
+
+	if err := h.service.DeleteIndexerBudget(r.Context(), indexerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Indexer budget not found")
+			return
+		}
+		log.Error().Err(err).Int("indexerID", indexerID).Msg("Failed to delete cross-seed indexer budget")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete indexer budget")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// blocklistEntryRequest is the payload for creating a blocklist entry.
+type blocklistEntryRequest struct {
+	Kind      models.CrossSeedBlocklistKind `json:"kind"`
+	Value     string                        `json:"value"`
+	IndexerID *int                          `json:"indexerId"`
+	Reason    string                        `json:"reason"`
+}
+
+// ListBlocklist godoc
+// @Summary List cross-seed blocklist entries
+// @Description Returns releases that cross-seed automation will never add
+// @Tags cross-seed
+// @Produce json
+// @Success 200 {array} models.CrossSeedBlocklistEntry
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/blocklist [get]
+func (h *CrossSeedHandler) ListBlocklist(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.service.ListBlocklist(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list cross-seed blocklist")
+		RespondError(w, http.StatusInternalServerError, "Failed to list blocklist")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, entries)
+}
+
+// CreateBlocklistEntry godoc
+// @Summary Add a cross-seed blocklist entry
+// @Description Blocks releases by infohash, exact name, regex or release group, optionally scoped to one indexer
+// @Tags cross-seed
+// @Accept json
+// @Produce json
+// @Param request body blocklistEntryRequest true "Blocklist entry"
+// @Success 201 {object} models.CrossSeedBlocklistEntry
+// @Failure 400 {object} httphelpers.ErrorResponse
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/blocklist [post]
+func (h *CrossSeedHandler) CreateBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	var req blocklistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	entry, err := h.service.AddBlocklistEntry(r.Context(), &models.CrossSeedBlocklistEntry{
+		Kind:      req.Kind,
+		Value:     req.Value,
+		IndexerID: req.IndexerID,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		if errors.Is(err, crossseed.ErrInvalidRequest) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to create cross-seed blocklist entry")
+		RespondError(w, http.StatusInternalServerError, "Failed to create blocklist entry")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, entry)
+}
+
+// BlocklistFromCrossSeed godoc
+// @Summary Blocklist a failed or removed cross-seed
+// @Description Creates blocklist entries from a cross-seed so it is not added again on the next RSS poll or search
+// @Tags cross-seed
+// @Accept json
+// @Produce json
+// @Param request body crossseed.BlocklistFromCrossSeedRequest true "Cross-seed to blocklist"
+// @Success 201 {array} models.CrossSeedBlocklistEntry
+// @Failure 400 {object} httphelpers.ErrorResponse
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/blocklist/from-cross-seed [post]
+func (h *CrossSeedHandler) BlocklistFromCrossSeed(w http.ResponseWriter, r *http.Request) {
+	var req crossseed.BlocklistFromCrossSeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	entries, err := h.service.BlocklistFromCrossSeed(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, crossseed.ErrInvalidRequest) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Str("hash", req.InfoHash).Msg("Failed to blocklist cross-seed")
+		RespondError(w, http.StatusInternalServerError, "Failed to blocklist cross-seed")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, entries)
+}
+
+// DeleteBlocklistEntry godoc
+// @Summary Delete a cross-seed blocklist entry
+// @Tags cross-seed
+// @Param entryID path int true "Blocklist entry ID"
+// @Success 204
+// @Failure 400 {object} httphelpers.ErrorResponse
+// @Failure 404 {object} httphelpers.ErrorResponse
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/blocklist/{entryID} [delete]
+func (h *CrossSeedHandler) DeleteBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := strconv.ParseInt(chi.URLParam(r, "entryID"), 10, 64)
+	if err != nil || entryID <= 0 {
+		RespondError(w, http.StatusBadRequest, "entryID must be a positive integer")
+		return
+	}
+
+	if err := h.service.DeleteBlocklistEntry(r.Context(), entryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Blocklist entry not found")
+			return
+		}
+		log.Error().Err(err).Int64("entryID", entryID).Msg("Failed to delete cross-seed blocklist entry")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete blocklist entry")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAutomationRunReport godoc
+// @Summary Download an RSS automation run report
+// @Description Returns every feed item considered during the run with its decision, indexer, matched local torrent, bytes saved and time taken
+// @Tags cross-seed
+// @Produce json
+// @Produce text/csv
+// @Param runID path int true "Run ID"
+// @Param format query string false "Report format: json (default) or csv"
+// @Success 200 {object} crossseed.RunReport
+// @Failure 400 {object} httphelpers.ErrorResponse
+// @Failure 404 {object} httphelpers.ErrorResponse
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/runs/{runID}/report [get]
+func (h *CrossSeedHandler) GetAutomationRunReport(w http.ResponseWriter, r *http.Request) {
+	h.respondRunReport(w, r, h.service.GetAutomationRunReport)
+}
+
+// GetSearchRunReport godoc
+// @Summary Download a seeded search run report
+// @Description Returns every torrent and search result considered during the run with its decision, indexer, bytes saved and time taken
+// @Tags cross-seed
+// @Produce json
+// @Produce text/csv
+// @Param runID path int true "Run ID"
+// @Param format query string false "Report format: json (default) or csv"
+// @Success 200 {object} crossseed.RunReport
+// @Failure 400 {object} httphelpers.ErrorResponse
+// @Failure 404 {object} httphelpers.ErrorResponse
+// @Failure 500 {object} httphelpers.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/cross-seed/search/runs/{runID}/report [get]
+func (h *CrossSeedHandler) GetSearchRunReport(w http.ResponseWriter, r *http.Request) {
+	h.respondRunReport(w, r, h.service.GetSearchRunReport)
+}
+
+func (h *CrossSeedHandler) respondRunReport(w http.ResponseWriter, r *http.Request, load func(context.Context, int64) (*crossseed.RunReport, error)) {
+	runID, err := strconv.ParseInt(chi.URLParam(r, "runID"), 10, 64)
+	if err != nil || runID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid run ID")
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format != "" && format != "json" && format != "csv" {
+		RespondError(w, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	report, err := load(r.Context(), runID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Run not found")
+			return
+		}
+		log.Error().Err(err).Int64("runID", runID).Msg("Failed to build cross-seed run report")
+		RespondError(w, http.StatusInternalServerError, "Failed to build run report")
+		return
+	}
+	if report == nil {
+		RespondError(w, http.StatusNotFound, "Run not found")
+		return
+	}
+
+	filename := fmt.Sprintf("cross-seed-%s-run-%d", report.Kind, report.RunID)
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		w.WriteHeader(http.StatusOK)
+		if err := report.WriteCSV(w); err != nil {
+			log.Error().Err(err).Int64("runID", runID).Msg("Failed to write cross-seed run report CSV")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+	RespondJSON(w, http.StatusOK, report)
+}
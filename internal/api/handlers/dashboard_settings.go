@@ -9,6 +9,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/auth"
 	"github.com/autobrr/qui/internal/models"
 )
 
@@ -22,8 +23,24 @@ func NewDashboardSettingsHandler(store *models.DashboardSettingsStore) *Dashboar
 	}
 }
 
+// settingsUserID returns the account the settings belong to. API keys have no
+// account, so they can't read or change anyone's layout.
+func settingsUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok || user.ID == 0 {
+		RespondError(w, http.StatusForbidden, "Dashboard settings require a user account")
+		return 0, false
+	}
+	return user.ID, true
+}
+
 func (h *DashboardSettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
-	settings, err := h.store.GetByUserID(r.Context(), 1)
+	userID, ok := settingsUserID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.store.GetByUserID(r.Context(), userID)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get dashboard settings")
 		RespondError(w, http.StatusInternalServerError, "Failed to load dashboard settings")
@@ -34,6 +51,11 @@ func (h *DashboardSettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *DashboardSettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := settingsUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var input models.DashboardSettingsInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		log.Warn().Err(err).Msg("failed to decode dashboard settings request")
@@ -41,7 +63,7 @@ func (h *DashboardSettingsHandler) Update(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	settings, err := h.store.Update(r.Context(), 1, &input)
+	settings, err := h.store.Update(r.Context(), userID, &input)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to update dashboard settings")
 		RespondError(w, http.StatusInternalServerError, "Failed to update dashboard settings")
@@ -0,0 +1,105 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/services/duplicates"
+)
+
+type DuplicatesHandler struct {
+	service *duplicates.Service
+}
+
+func NewDuplicatesHandler(service *duplicates.Service) *DuplicatesHandler {
+	return &DuplicatesHandler{service: service}
+}
+
+// DuplicateDeleteRequest removes one member of a group.
+type DuplicateDeleteRequest struct {
+	InstanceID  int    `json:"instanceId"`
+	Hash        string `json:"hash"`
+	DeleteFiles bool   `json:"deleteFiles"`
+}
+
+// DuplicateHardlinkRequest converts a member into a hardlinked cross-seed of another.
+type DuplicateHardlinkRequest struct {
+	InstanceID       int    `json:"instanceId"`
+	Hash             string `json:"hash"`
+	SourceInstanceID int    `json:"sourceInstanceId"`
+	SourceHash       string `json:"sourceHash"`
+}
+
+// respondDuplicateError maps service errors to responses.
+func respondDuplicateError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, duplicates.ErrNotInGroup):
+		RespondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, duplicates.ErrSharedData), errors.Is(err, duplicates.ErrLocalAccessRequired),
+		errors.Is(err, duplicates.ErrUnresolvedFiles):
+		RespondError(w, http.StatusConflict, err.Error())
+	default:
+		log.Error().Err(err).Msgf("duplicates: failed to %s", action)
+		RespondError(w, http.StatusInternalServerError, "Failed to "+action+": "+err.Error())
+	}
+}
+
+// GetReport groups torrents across all instances by content identity.
+func (h *DuplicatesHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.Report(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("duplicates: failed to build report")
+		RespondError(w, http.StatusInternalServerError, "Failed to build duplicate report")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, report)
+}
+
+// Delete removes a torrent from a group, optionally with its files.
+func (h *DuplicatesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req DuplicateDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if req.InstanceID <= 0 || strings.TrimSpace(req.Hash) == "" {
+		RespondError(w, http.StatusBadRequest, "instanceId and hash are required")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), req.InstanceID, req.Hash, req.DeleteFiles); err != nil {
+		respondDuplicateError(w, err, "delete torrent")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Hardlink replaces a duplicate's files with hardlinks to another member's files.
+func (h *DuplicatesHandler) Hardlink(w http.ResponseWriter, r *http.Request) {
+	var req DuplicateHardlinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if req.InstanceID <= 0 || strings.TrimSpace(req.Hash) == "" || req.SourceInstanceID <= 0 || strings.TrimSpace(req.SourceHash) == "" {
+		RespondError(w, http.StatusBadRequest, "instanceId, hash, sourceInstanceId and sourceHash are required")
+		return
+	}
+
+	result, err := h.service.Hardlink(r.Context(), req.InstanceID, req.Hash, req.SourceInstanceID, req.SourceHash)
+	if err != nil {
+		respondDuplicateError(w, err, "convert to hardlinked cross-seed")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/hnr"
+)
+
+type HnRHandler struct {
+	store   *models.HnRProfileStore
+	service *hnr.Service
+}
+
+func NewHnRHandler(store *models.HnRProfileStore, service *hnr.Service) *HnRHandler {
+	return &HnRHandler{store: store, service: service}
+}
+
+type HnRProfilePayload struct {
+	Name        string   `json:"name"`
+	Domains     []string `json:"domains"`
+	MinSeedTime int64    `json:"minSeedTime"`
+	MinRatio    float64  `json:"minRatio"`
+	GracePeriod int64    `json:"gracePeriod"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+// validate returns a user-facing error message, or "" when the payload is valid.
+func (p *HnRProfilePayload) validate() string {
+	switch {
+	case strings.TrimSpace(p.Name) == "":
+		return "Name is required"
+	case len(normalizeDomains(p.Domains)) == 0:
+		return "At least one domain is required"
+	case p.MinSeedTime < 0 || p.MinRatio < 0 || p.GracePeriod < 0:
+		return "Requirements cannot be negative"
+	case p.MinSeedTime == 0 && p.MinRatio == 0:
+		return "A minimum seed time or ratio is required"
+	default:
+		return ""
+	}
+}
+
+func (p *HnRProfilePayload) toModel(id int) *models.HnRProfile {
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+	return &models.HnRProfile{
+		ID:          id,
+		Name:        strings.TrimSpace(p.Name),
+		Domains:     normalizeDomains(p.Domains),
+		MinSeedTime: p.MinSeedTime,
+		MinRatio:    p.MinRatio,
+		GracePeriod: p.GracePeriod,
+		Enabled:     enabled,
+	}
+}
+
+func (h *HnRHandler) reload(r *http.Request) {
+	if err := h.service.Reload(r.Context()); err != nil {
+		log.Error().Err(err).Msg("failed to reload hit-and-run profiles")
+	}
+}
+
+func (h *HnRHandler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := h.store.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list hit-and-run profiles")
+		RespondError(w, http.StatusInternalServerError, "Failed to load hit-and-run profiles")
+		return
+	}
+	if profiles == nil {
+		profiles = []*models.HnRProfile{}
+	}
+
+	RespondJSON(w, http.StatusOK, profiles)
+}
+
+func (h *HnRHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
+	var payload HnRProfilePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if msg := payload.validate(); msg != "" {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	profile, err := h.store.Create(r.Context(), payload.toModel(0))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create hit-and-run profile")
+		RespondError(w, http.StatusInternalServerError, "Failed to create hit-and-run profile")
+		return
+	}
+	h.reload(r)
+
+	RespondJSON(w, http.StatusCreated, profile)
+}
+
+func (h *HnRHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid profile ID")
+		return
+	}
+
+	var payload HnRProfilePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if msg := payload.validate(); msg != "" {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	profile, err := h.store.Update(r.Context(), payload.toModel(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Hit-and-run profile not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to update hit-and-run profile")
+		RespondError(w, http.StatusInternalServerError, "Failed to update hit-and-run profile")
+		return
+	}
+	h.reload(r)
+
+	RespondJSON(w, http.StatusOK, profile)
+}
+
+func (h *HnRHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid profile ID")
+		return
+	}
+
+	if err := h.store.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Hit-and-run profile not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to delete hit-and-run profile")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete hit-and-run profile")
+		return
+	}
+	h.reload(r)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AtRisk lists torrents at risk of a hit-and-run, optionally for one instance.
+func (h *HnRHandler) AtRisk(w http.ResponseWriter, r *http.Request) {
+	var instanceID int
+	if v := r.URL.Query().Get("instanceId"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+			return
+		}
+		instanceID = id
+	}
+
+	torrents, err := h.service.AtRisk(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list hit-and-run risks")
+		RespondError(w, http.StatusInternalServerError, "Failed to load hit-and-run risks")
+		return
+	}
+	torrents = slices.DeleteFunc(torrents, func(torrent hnr.AtRiskTorrent) bool {
+		return !auth.CanAccessInstance(r.Context(), torrent.InstanceID)
+	})
+	if torrents == nil {
+		torrents = []hnr.AtRiskTorrent{}
+	}
+
+	RespondJSON(w, http.StatusOK, torrents)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/auth"
 	"github.com/autobrr/qui/internal/domain"
 	"github.com/autobrr/qui/internal/models"
 	internalqbittorrent "github.com/autobrr/qui/internal/qbittorrent"
@@ -97,7 +98,7 @@ func (h *InstancesHandler) GetReannounceActivity(w http.ResponseWriter, r *http.
 			limit = parsed
 		}
 	}
-	events := h.reannounceSvc.GetActivity(instanceID, limit)
+	events := h.reannounceSvc.GetActivity(r.Context(), instanceID, limit)
 	if events == nil {
 		events = []reannounce.ActivityEvent{}
 	}
@@ -109,6 +110,42 @@ func (h *InstancesHandler) GetReannounceActivity(w http.ResponseWriter, r *http.
 	RespondJSON(w, http.StatusOK, normalized)
 }
 
+// GetReannounceStats returns per-tracker reannounce statistics for an instance
+// over the last `days` days (default 7).
+func (h *InstancesHandler) GetReannounceStats(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+	if h.reannounceSvc == nil {
+		RespondJSON(w, http.StatusOK, []reannounce.TrackerStats{})
+		return
+	}
+
+	days := 7
+	if daysParam := strings.TrimSpace(r.URL.Query().Get("days")); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 || parsed > 365 {
+			RespondError(w, http.StatusBadRequest, "days must be between 1 and 365")
+			return
+		}
+		days = parsed
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	stats, err := h.reannounceSvc.GetTrackerStats(r.Context(), instanceID, since)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to load reannounce stats")
+		RespondError(w, http.StatusInternalServerError, "Failed to load reannounce stats")
+		return
+	}
+	if stats == nil {
+		stats = []reannounce.TrackerStats{}
+	}
+	RespondJSON(w, http.StatusOK, stats)
+}
+
 // GetReannounceCandidates returns torrents that currently fall within the
 // reannounce monitoring scope and either have tracker problems or are still
 // waiting for their initial tracker contact.
@@ -406,6 +443,8 @@ type InstanceReannounceSettingsPayload struct {
 	Tags                      []string `json:"tags"`
 	ExcludeTrackers           bool     `json:"excludeTrackers"`
 	Trackers                  []string `json:"trackers"`
+	// TrackerProfiles is left unchanged when omitted.
+	TrackerProfiles []models.ReannounceTrackerProfile `json:"trackerProfiles"`
 }
 
 // TestConnectionResponse represents connection test results
@@ -441,6 +480,9 @@ func (p *InstanceReannounceSettingsPayload) toModel(instanceID int, base *models
 	target.Tags = append([]string{}, p.Tags...)
 	target.ExcludeTrackers = p.ExcludeTrackers
 	target.Trackers = append([]string{}, p.Trackers...)
+	if p.TrackerProfiles != nil {
+		target.TrackerProfiles = append([]models.ReannounceTrackerProfile{}, p.TrackerProfiles...)
+	}
 	return target
 }
 
@@ -462,6 +504,7 @@ func payloadFromModel(settings *models.InstanceReannounceSettings) InstanceReann
 		Tags:                      append([]string{}, settings.Tags...),
 		ExcludeTrackers:           settings.ExcludeTrackers,
 		Trackers:                  append([]string{}, settings.Trackers...),
+		TrackerProfiles:           append([]models.ReannounceTrackerProfile{}, settings.TrackerProfiles...),
 	}
 }
 
@@ -479,6 +522,11 @@ func (h *InstancesHandler) ListInstances(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Only list the instances the user was granted
+	instances = slices.DeleteFunc(instances, func(instance *models.Instance) bool {
+		return !auth.CanAccessInstance(r.Context(), instance.ID)
+	})
+
 	response := h.buildInstanceResponsesParallel(r.Context(), instances)
 
 	RespondJSON(w, http.StatusOK, response)
@@ -0,0 +1,302 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/integrity"
+)
+
+type IntegrityHandler struct {
+	store         *models.IntegrityStore
+	instanceStore *models.InstanceStore
+	service       *integrity.Service
+}
+
+func NewIntegrityHandler(store *models.IntegrityStore, instanceStore *models.InstanceStore, service *integrity.Service) *IntegrityHandler {
+	return &IntegrityHandler{
+		store:         store,
+		instanceStore: instanceStore,
+		service:       service,
+	}
+}
+
+func (h *IntegrityHandler) requireLocalAccess(w http.ResponseWriter, r *http.Request, instanceID int) bool {
+	instance, err := h.instanceStore.Get(r.Context(), instanceID)
+	if err != nil {
+		if errors.Is(err, models.ErrInstanceNotFound) {
+			RespondError(w, http.StatusNotFound, "Instance not found")
+			return false
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("integrity: failed to get instance")
+		RespondError(w, http.StatusInternalServerError, "Failed to get instance")
+		return false
+	}
+
+	if !instance.HasLocalFilesystemAccess {
+		RespondError(w, http.StatusForbidden, "Integrity checks require local filesystem access. Enable 'Local Filesystem Access' in instance settings first.")
+		return false
+	}
+
+	return true
+}
+
+// IntegritySettingsPayload is the request body for updating integrity settings.
+type IntegritySettingsPayload struct {
+	Enabled             *bool   `json:"enabled"`
+	IntervalMinutes     *int    `json:"intervalMinutes"`
+	TorrentsPerRun      *int    `json:"torrentsPerRun"`
+	RecheckAfterDays    *int    `json:"recheckAfterDays"`
+	MaxReadMiBPerSecond *int    `json:"maxReadMiBPerSecond"`
+	FailureTag          *string `json:"failureTag"`
+}
+
+// GetSettings returns the integrity settings for an instance.
+func (h *IntegrityHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	settings, err := h.store.GetSettings(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("integrity: failed to get settings")
+		RespondError(w, http.StatusInternalServerError, "Failed to get settings")
+		return
+	}
+	if settings == nil {
+		settings = integrity.DefaultSettings(instanceID)
+	}
+
+	RespondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings updates the integrity settings for an instance.
+func (h *IntegrityHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	var payload IntegritySettingsPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Msg("integrity: failed to decode settings payload")
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	settings, err := h.store.GetSettings(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("integrity: failed to get existing settings")
+		RespondError(w, http.StatusInternalServerError, "Failed to get settings")
+		return
+	}
+	if settings == nil {
+		settings = integrity.DefaultSettings(instanceID)
+	}
+
+	if payload.Enabled != nil {
+		settings.Enabled = *payload.Enabled
+	}
+	if payload.IntervalMinutes != nil {
+		if *payload.IntervalMinutes < 5 {
+			RespondError(w, http.StatusBadRequest, "Interval must be at least 5 minutes")
+			return
+		}
+		settings.IntervalMinutes = *payload.IntervalMinutes
+	}
+	if payload.TorrentsPerRun != nil {
+		if *payload.TorrentsPerRun < 1 {
+			RespondError(w, http.StatusBadRequest, "Torrents per run must be at least 1")
+			return
+		}
+		settings.TorrentsPerRun = *payload.TorrentsPerRun
+	}
+	if payload.RecheckAfterDays != nil {
+		if *payload.RecheckAfterDays < 1 {
+			RespondError(w, http.StatusBadRequest, "Recheck interval must be at least 1 day")
+			return
+		}
+		settings.RecheckAfterDays = *payload.RecheckAfterDays
+	}
+	if payload.MaxReadMiBPerSecond != nil {
+		if *payload.MaxReadMiBPerSecond < 0 {
+			RespondError(w, http.StatusBadRequest, "Read rate limit must be non-negative")
+			return
+		}
+		settings.MaxReadMiBPerSecond = *payload.MaxReadMiBPerSecond
+	}
+	if payload.FailureTag != nil {
+		tag := strings.TrimSpace(*payload.FailureTag)
+		if strings.Contains(tag, ",") {
+			RespondError(w, http.StatusBadRequest, "Failure tag must not contain commas")
+			return
+		}
+		settings.FailureTag = tag
+	}
+
+	saved, err := h.store.UpsertSettings(r.Context(), settings)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("integrity: failed to save settings")
+		RespondError(w, http.StatusInternalServerError, "Failed to save settings")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, saved)
+}
+
+// TriggerCheck starts verifying one torrent's data.
+func (h *IntegrityHandler) TriggerCheck(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	var payload struct {
+		Hash string `json:"hash"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || strings.TrimSpace(payload.Hash) == "" {
+		RespondError(w, http.StatusBadRequest, "A torrent hash is required")
+		return
+	}
+
+	checkID, err := h.service.TriggerCheck(r.Context(), instanceID, strings.TrimSpace(payload.Hash), integrity.TriggerManual)
+	if err != nil {
+		switch {
+		case errors.Is(err, integrity.ErrCheckInProgress):
+			RespondError(w, http.StatusConflict, "An integrity check is already running for this instance")
+		case errors.Is(err, integrity.ErrTorrentNotFound):
+			RespondError(w, http.StatusNotFound, "Torrent not found")
+		default:
+			log.Error().Err(err).Int("instanceID", instanceID).Msg("integrity: failed to start check")
+			RespondError(w, http.StatusInternalServerError, "Failed to start integrity check")
+		}
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, map[string]int64{"checkId": checkID})
+}
+
+// ListChecks returns recent integrity checks for an instance.
+func (h *IntegrityHandler) ListChecks(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 200 {
+			limit = parsed
+		}
+	}
+
+	checks, err := h.store.ListChecks(r.Context(), instanceID, limit)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("integrity: failed to list checks")
+		RespondError(w, http.StatusInternalServerError, "Failed to list checks")
+		return
+	}
+	if checks == nil {
+		checks = []*models.IntegrityCheck{}
+	}
+
+	RespondJSON(w, http.StatusOK, checks)
+}
+
+func parseCheckID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	checkID, err := strconv.ParseInt(chi.URLParam(r, "checkID"), 10, 64)
+	if err != nil || checkID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid check ID")
+		return 0, false
+	}
+	return checkID, true
+}
+
+// GetCheck returns one integrity check with its affected files.
+func (h *IntegrityHandler) GetCheck(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	checkID, ok := parseCheckID(w, r)
+	if !ok {
+		return
+	}
+
+	check, err := h.store.GetCheckByInstance(r.Context(), instanceID, checkID)
+	if err != nil {
+		log.Error().Err(err).Int64("checkID", checkID).Msg("integrity: failed to get check")
+		RespondError(w, http.StatusInternalServerError, "Failed to get check")
+		return
+	}
+	if check == nil {
+		RespondError(w, http.StatusNotFound, "Check not found")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, check)
+}
+
+// CancelCheck stops a running integrity check.
+func (h *IntegrityHandler) CancelCheck(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	checkID, ok := parseCheckID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CancelCheck(r.Context(), instanceID, checkID); err != nil {
+		switch {
+		case errors.Is(err, integrity.ErrCheckNotFound):
+			RespondError(w, http.StatusNotFound, "Check not found")
+		case errors.Is(err, integrity.ErrCheckNotRunning):
+			RespondError(w, http.StatusConflict, "Check is not running")
+		default:
+			log.Error().Err(err).Int64("checkID", checkID).Msg("integrity: failed to cancel check")
+			RespondError(w, http.StatusInternalServerError, "Failed to cancel check")
+		}
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{"status": "canceled"})
+}
@@ -716,6 +716,11 @@ func (h *JackettHandler) TestIndexer(w http.ResponseWriter, r *http.Request) {
 		Str("indexer_name", indexer.Name).
 		Msg("Testing torznab indexer connectivity")
 
+	// Use a detached context for test searches - the HTTP request lifecycle should not
+	// cancel the scheduler task since SearchGeneric returns immediately after scheduling.
+	// The context is released once the async search completes, or by the timeout.
+	testCtx, cancelTest := context.WithTimeout(context.Background(), 30*time.Second)
+
 	// Run a lightweight search via the service to validate connectivity
 	// Use CacheModeBypass and SkipHistory to prevent test searches from cluttering search history
 	testReq := &jackett.TorznabSearchRequest{
@@ -726,19 +731,15 @@ func (h *JackettHandler) TestIndexer(w http.ResponseWriter, r *http.Request) {
 		SkipHistory: true,
 		OnAllComplete: func(*jackett.SearchResponse, error) {
 			// Ignore results for connectivity test
+			cancelTest()
 		},
 	}
 
-	// Use a detached context for test searches - the HTTP request lifecycle should not
-	// cancel the scheduler task since SearchGeneric returns immediately after scheduling.
-	// Note: We intentionally don't defer cancel() here because the search is async.
-	// The context will be cleaned up when the timeout expires.
-	testCtx, _ := context.WithTimeout(context.Background(), 30*time.Second)
-
 	err = h.service.SearchGeneric(testCtx, testReq)
 
 	// Update test status in database
 	if err != nil {
+		cancelTest()
 		errorMsg := err.Error()
 		if updateErr := h.updateTestStatusWithTimeout(id, "error", &errorMsg); updateErr != nil {
 			h.logTestStatusUpdateError(updateErr, id, "error")
@@ -0,0 +1,254 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/notifications"
+)
+
+// NotificationsHandler handles notification provider management endpoints
+type NotificationsHandler struct {
+	store         *models.NotificationProviderStore
+	instanceStore *models.InstanceStore
+	service       *notifications.Service
+}
+
+// NewNotificationsHandler creates a new notifications handler
+func NewNotificationsHandler(store *models.NotificationProviderStore, instanceStore *models.InstanceStore, service *notifications.Service) *NotificationsHandler {
+	return &NotificationsHandler{
+		store:         store,
+		instanceStore: instanceStore,
+		service:       service,
+	}
+}
+
+// NotificationProviderPayload is the request body for creating/updating a notification provider.
+// Token is write-only: omit it on update to keep the stored value, send "" to clear it.
+type NotificationProviderPayload struct {
+	Name          string                          `json:"name"`
+	Type          models.NotificationProviderType `json:"type"`
+	URL           string                          `json:"url"`
+	Token         *string                         `json:"token"`
+	Options       map[string]string               `json:"options"`
+	Events        []models.NotificationEventType  `json:"events"`
+	InstanceIDs   []int                           `json:"instanceIds"`
+	TitleTemplate string                          `json:"titleTemplate"`
+	BodyTemplate  string                          `json:"bodyTemplate"`
+	Enabled       *bool                           `json:"enabled"`
+}
+
+// notificationTestResponse is the response for the test endpoint
+type notificationTestResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// toModel validates the payload and converts it to a provider.
+// Returns a user-facing error message when validation fails.
+func (h *NotificationsHandler) toModel(r *http.Request, p *NotificationProviderPayload) (*models.NotificationProvider, string) {
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		return nil, "Name is required"
+	}
+
+	if !p.Type.IsValid() {
+		return nil, "Type must be one of webhook, discord, ntfy, gotify or apprise"
+	}
+
+	rawURL := strings.TrimSpace(p.URL)
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, "URL must be an absolute http(s) URL"
+	}
+
+	events := make([]models.NotificationEventType, 0, len(p.Events))
+	for _, event := range p.Events {
+		if !event.IsValid() {
+			return nil, "Unknown event type: " + string(event)
+		}
+		events = append(events, event)
+	}
+
+	instanceIDs := make([]int, 0, len(p.InstanceIDs))
+	for _, instanceID := range p.InstanceIDs {
+		if _, err := h.instanceStore.Get(r.Context(), instanceID); err != nil {
+			return nil, "Unknown instance ID: " + strconv.Itoa(instanceID)
+		}
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+
+	if err := notifications.ValidateTemplates(p.TitleTemplate, p.BodyTemplate); err != nil {
+		return nil, err.Error()
+	}
+
+	options := make(map[string]string, len(p.Options))
+	for key, value := range p.Options {
+		if key = strings.TrimSpace(key); key != "" {
+			options[key] = strings.TrimSpace(value)
+		}
+	}
+
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+
+	return &models.NotificationProvider{
+		Name:          name,
+		Type:          p.Type,
+		URL:           rawURL,
+		Options:       options,
+		Events:        events,
+		InstanceIDs:   instanceIDs,
+		TitleTemplate: p.TitleTemplate,
+		BodyTemplate:  p.BodyTemplate,
+		Enabled:       enabled,
+	}, ""
+}
+
+func parseNotificationProviderID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid provider ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// ListEvents handles GET /api/notifications/events
+func (h *NotificationsHandler) ListEvents(w http.ResponseWriter, _ *http.Request) {
+	RespondJSON(w, http.StatusOK, models.NotificationEventTypes())
+}
+
+// ListProviders handles GET /api/notifications/providers
+func (h *NotificationsHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	providers, err := h.store.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list notification providers")
+		RespondError(w, http.StatusInternalServerError, "Failed to list notification providers")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, providers)
+}
+
+// CreateProvider handles POST /api/notifications/providers
+func (h *NotificationsHandler) CreateProvider(w http.ResponseWriter, r *http.Request) {
+	var payload NotificationProviderPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	provider, msg := h.toModel(r, &payload)
+	if provider == nil {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	token := ""
+	if payload.Token != nil {
+		token = strings.TrimSpace(*payload.Token)
+	}
+
+	created, err := h.store.Create(r.Context(), provider, token)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create notification provider")
+		RespondError(w, http.StatusInternalServerError, "Failed to create notification provider")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateProvider handles PUT /api/notifications/providers/{id}
+func (h *NotificationsHandler) UpdateProvider(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseNotificationProviderID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload NotificationProviderPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	provider, msg := h.toModel(r, &payload)
+	if provider == nil {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	provider.ID = id
+
+	var token *string
+	if payload.Token != nil {
+		trimmed := strings.TrimSpace(*payload.Token)
+		token = &trimmed
+	}
+
+	updated, err := h.store.Update(r.Context(), provider, token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Notification provider not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("Failed to update notification provider")
+		RespondError(w, http.StatusInternalServerError, "Failed to update notification provider")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteProvider handles DELETE /api/notifications/providers/{id}
+func (h *NotificationsHandler) DeleteProvider(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseNotificationProviderID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Notification provider not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("Failed to delete notification provider")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete notification provider")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TestProvider handles POST /api/notifications/providers/{id}/test
+func (h *NotificationsHandler) TestProvider(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseNotificationProviderID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SendTest(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Notification provider not found")
+			return
+		}
+		RespondJSON(w, http.StatusOK, notificationTestResponse{Success: false, Error: err.Error()})
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, notificationTestResponse{Success: true})
+}
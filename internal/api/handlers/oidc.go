@@ -7,9 +7,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,7 +22,9 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2"
 
+	"github.com/autobrr/qui/internal/auth"
 	"github.com/autobrr/qui/internal/domain"
+	"github.com/autobrr/qui/internal/models"
 )
 
 const (
@@ -39,6 +43,7 @@ type OIDCHandler struct {
 	verifier       *oidc.IDTokenVerifier
 	oauthConfig    *oauth2.Config
 	sessionManager *scs.SessionManager
+	authService    *auth.Service
 }
 
 // OIDCClaims represents the claims returned from the OIDC provider
@@ -61,7 +66,7 @@ type OIDCConfigResponse struct {
 	IssuerURL           string `json:"issuerUrl"`
 }
 
-func NewOIDCHandler(cfg *domain.Config, sessionManager *scs.SessionManager) (*OIDCHandler, error) {
+func NewOIDCHandler(cfg *domain.Config, sessionManager *scs.SessionManager, authService *auth.Service) (*OIDCHandler, error) {
 	log.Debug().
 		Bool("oidc_enabled", cfg.OIDCEnabled).
 		Str("oidc_issuer", cfg.OIDCIssuer).
@@ -132,6 +137,7 @@ func NewOIDCHandler(cfg *domain.Config, sessionManager *scs.SessionManager) (*OI
 		provider:       provider,
 		verifier:       provider.Verifier(oidcConfig),
 		sessionManager: sessionManager,
+		authService:    authService,
 		oauthConfig: &oauth2.Config{
 			ClientID:     cfg.OIDCClientID,
 			ClientSecret: cfg.OIDCClientSecret,
@@ -149,6 +155,7 @@ func (h *OIDCHandler) Routes(r chi.Router) {
 	// Re-apply throttling because chi.Route creates a fresh middleware stack.
 	r.Use(middleware.ThrottleBacklog(1, 1, time.Second))
 	r.Get("/config", h.getConfig)
+	r.Get("/link", h.getLinkConfig)
 	r.Get("/callback", h.handleCallback)
 }
 
@@ -197,6 +204,23 @@ func (h *OIDCHandler) getConfig(w http.ResponseWriter, r *http.Request) {
 	// Store state in session for later validation
 	// This is needed even if user is already authenticated, in case they're re-authenticating
 	h.sessionManager.Put(r.Context(), "oidc_state", config.State)
+	h.sessionManager.Remove(r.Context(), "oidc_link_user_id")
+
+	RespondJSON(w, http.StatusOK, config)
+}
+
+// getLinkConfig starts an OIDC login that links the identity to the signed-in
+// account instead of resolving it by subject or username.
+func (h *OIDCHandler) getLinkConfig(w http.ResponseWriter, r *http.Request) {
+	userID := h.sessionManager.GetInt(r.Context(), "user_id")
+	if !h.sessionManager.GetBool(r.Context(), "authenticated") || userID == 0 {
+		RespondError(w, http.StatusUnauthorized, "sign in before linking an OIDC login")
+		return
+	}
+
+	config := h.GetConfigResponse()
+	h.sessionManager.Put(r.Context(), "oidc_state", config.State)
+	h.sessionManager.Put(r.Context(), "oidc_link_user_id", userID)
 
 	RespondJSON(w, http.StatusOK, config)
 }
@@ -224,6 +248,12 @@ func (h *OIDCHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
 
 	// Clear the state from session after successful validation
 	h.sessionManager.Remove(r.Context(), "oidc_state")
+	linkUserID := h.sessionManager.PopInt(r.Context(), "oidc_link_user_id")
+	if linkUserID != 0 && (!h.sessionManager.GetBool(r.Context(), "authenticated") || h.sessionManager.GetInt(r.Context(), "user_id") != linkUserID) {
+		log.Warn().Msg("OIDC link requested for a session that is no longer signed in")
+		RespondError(w, http.StatusUnauthorized, "sign in before linking an OIDC login")
+		return
+	}
 
 	code := r.URL.Query().Get("code")
 	if code == "" {
@@ -260,6 +290,12 @@ func (h *OIDCHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var rawClaims map[string]any
+	if err := idToken.Claims(&rawClaims); err != nil {
+		log.Warn().Err(err).Msg("failed to parse raw claims from ID token")
+	}
+	groups := oidcGroups(rawClaims, h.config.OIDCGroupsClaim)
+
 	// Try to get additional claims from userinfo endpoint
 	userInfo, err := h.provider.UserInfo(r.Context(), oauth2.StaticTokenSource(oauth2Token))
 	if err != nil {
@@ -301,6 +337,13 @@ func (h *OIDCHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
 				claims.Picture = userInfoClaims.Picture
 			}
 		}
+
+		var rawUserInfoClaims map[string]any
+		if err := userInfo.Claims(&rawUserInfoClaims); err == nil {
+			if userInfoGroups := oidcGroups(rawUserInfoClaims, h.config.OIDCGroupsClaim); len(userInfoGroups) > 0 {
+				groups = userInfoGroups
+			}
+		}
 	}
 
 	// Determine username from claims
@@ -325,8 +368,51 @@ func (h *OIDCHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
 		Str("nickname", claims.Nickname).
 		Str("name", claims.Name).
 		Str("sub", claims.Sub).
+		Strs("groups", groups).
 		Msg("successfully processed OIDC claims")
 
+	role, allowed := oidcRole(h.config, groups)
+	if !allowed {
+		log.Warn().Msgf("Auth: OIDC user [%s] is not in any group mapped to a role ip: %s", username, r.RemoteAddr)
+		RespondError(w, http.StatusForbidden, "your account is not allowed to access qui")
+		return
+	}
+
+	subject := claims.Sub
+	if subject == "" {
+		subject = idToken.Subject
+	}
+	if linkUserID != 0 {
+		if _, err := h.authService.LinkOIDCUser(r.Context(), linkUserID, subject); err != nil {
+			if errors.Is(err, models.ErrUserAlreadyExists) {
+				log.Warn().Err(err).Msgf("Auth: OIDC login [%s] could not be linked", username)
+				RespondError(w, http.StatusConflict, "this account or OIDC login is already linked")
+				return
+			}
+			log.Error().Err(err).Msgf("Auth: failed to link OIDC login [%s]", username)
+			RespondError(w, http.StatusInternalServerError, "failed to link OIDC login")
+			return
+		}
+	}
+
+	user, err := h.authService.ResolveOIDCUser(r.Context(), subject, username, role)
+	if err != nil {
+		if errors.Is(err, auth.ErrOIDCLinkRequired) {
+			log.Warn().Err(err).Msgf("Auth: OIDC username [%s] matches an unlinked account", username)
+			RespondError(w, http.StatusConflict, "username belongs to an existing account; sign in to it and link your OIDC login under Settings → Security")
+			return
+		}
+		if errors.Is(err, models.ErrUserAlreadyExists) {
+			log.Warn().Err(err).Msgf("Auth: OIDC username [%s] conflicts with another account", username)
+			RespondError(w, http.StatusConflict, "username is already used by another account")
+			return
+		}
+		log.Error().Err(err).Msgf("Auth: failed to resolve OIDC user [%s]", username)
+		RespondError(w, http.StatusInternalServerError, "failed to resolve user account")
+		return
+	}
+	username = user.Username
+
 	// Create new session
 	if err := h.sessionManager.RenewToken(r.Context()); err != nil {
 		log.Error().Err(err).Msgf("Auth: Failed to renew session token for username: [%s] ip: %s", username, r.RemoteAddr)
@@ -350,10 +436,12 @@ func (h *OIDCHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
 
 	// Set session values using sessionManager
 	h.sessionManager.Put(r.Context(), "authenticated", true)
+	h.sessionManager.Put(r.Context(), "user_id", user.ID)
 	h.sessionManager.Put(r.Context(), "username", username)
 	h.sessionManager.Put(r.Context(), "created", time.Now().Unix())
 	h.sessionManager.Put(r.Context(), "auth_method", "oidc")
 	h.sessionManager.Put(r.Context(), "profile_picture", claims.Picture)
+	auth.StartSession(h.sessionManager, r)
 	h.sessionManager.RememberMe(r.Context(), true)
 
 	// Redirect to the frontend
@@ -429,3 +517,74 @@ func (h *OIDCHandler) GetConfigResponse() OIDCConfigResponse {
 		IssuerURL:           h.config.OIDCIssuer,
 	}
 }
+
+// oidcGroups reads group names from a claim holding either a list or a single string.
+func oidcGroups(claims map[string]any, claim string) []string {
+	if claim == "" {
+		claim = "groups"
+	}
+
+	switch v := claims[claim].(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []any:
+		groups := make([]string, 0, len(v))
+		for _, item := range v {
+			if group, ok := item.(string); ok && group != "" {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
+// oidcRole maps OIDC groups to a role, preferring the most privileged match.
+// The role is empty when no groups are mapped, leaving roles to be managed in
+// qui. allowed is false when groups are mapped, none match, and there is no
+// default role.
+func oidcRole(cfg *domain.Config, groups []string) (role models.UserRole, allowed bool) {
+	mappings := []struct {
+		role   models.UserRole
+		groups []string
+	}{
+		{models.RoleAdmin, splitList(cfg.OIDCAdminGroups)},
+		{models.RoleOperator, splitList(cfg.OIDCOperatorGroups)},
+		{models.RoleReadOnly, splitList(cfg.OIDCReadOnlyGroups)},
+	}
+
+	configured := false
+	for _, mapping := range mappings {
+		if len(mapping.groups) == 0 {
+			continue
+		}
+		configured = true
+		for _, group := range groups {
+			if slices.Contains(mapping.groups, group) {
+				return mapping.role, true
+			}
+		}
+	}
+
+	if !configured {
+		return "", true
+	}
+
+	defaultRole := models.UserRole(strings.TrimSpace(cfg.OIDCDefaultRole))
+	if defaultRole.Valid() {
+		return defaultRole, true
+	}
+	return "", false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/qui/internal/domain"
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestOIDCGroups(t *testing.T) {
+	claims := map[string]any{
+		"groups": []any{"qui-admins", 42, ""},
+		"role":   "media",
+	}
+
+	assert.Equal(t, []string{"qui-admins"}, oidcGroups(claims, ""))
+	assert.Equal(t, []string{"media"}, oidcGroups(claims, "role"))
+	assert.Nil(t, oidcGroups(claims, "missing"))
+}
+
+func TestOIDCRole(t *testing.T) {
+	mapped := &domain.Config{
+		OIDCAdminGroups:    "qui-admins",
+		OIDCOperatorGroups: "media, seedbox",
+		OIDCReadOnlyGroups: "family",
+	}
+
+	tests := []struct {
+		name        string
+		cfg         *domain.Config
+		groups      []string
+		wantRole    models.UserRole
+		wantAllowed bool
+	}{
+		{"no mapping keeps roles managed in qui", &domain.Config{}, []string{"anything"}, "", true},
+		{"most privileged group wins", mapped, []string{"family", "seedbox", "qui-admins"}, models.RoleAdmin, true},
+		{"operator group", mapped, []string{"seedbox"}, models.RoleOperator, true},
+		{"read-only group", mapped, []string{"family"}, models.RoleReadOnly, true},
+		{"no matching group is refused", mapped, []string{"guests"}, "", false},
+		{
+			"no matching group falls back to default role",
+			&domain.Config{OIDCAdminGroups: "qui-admins", OIDCDefaultRole: "read_only"},
+			nil, models.RoleReadOnly, true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, allowed := oidcRole(tt.cfg, tt.groups)
+			assert.Equal(t, tt.wantRole, role)
+			assert.Equal(t, tt.wantAllowed, allowed)
+		})
+	}
+}
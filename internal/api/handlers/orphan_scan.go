@@ -7,7 +7,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
@@ -53,13 +55,19 @@ func (h *OrphanScanHandler) requireLocalAccess(w http.ResponseWriter, r *http.Re
 
 // OrphanScanSettingsPayload is the request body for creating/updating orphan scan settings.
 type OrphanScanSettingsPayload struct {
-	Enabled             *bool    `json:"enabled"`
-	GracePeriodMinutes  *int     `json:"gracePeriodMinutes"`
-	IgnorePaths         []string `json:"ignorePaths"`
-	ScanIntervalHours   *int     `json:"scanIntervalHours"`
-	MaxFilesPerRun      *int     `json:"maxFilesPerRun"`
-	AutoCleanupEnabled  *bool    `json:"autoCleanupEnabled"`
-	AutoCleanupMaxFiles *int     `json:"autoCleanupMaxFiles"`
+	Enabled                        *bool                             `json:"enabled"`
+	GracePeriodMinutes             *int                              `json:"gracePeriodMinutes"`
+	IgnorePaths                    []string                          `json:"ignorePaths"`
+	ScanIntervalHours              *int                              `json:"scanIntervalHours"`
+	MaxFilesPerRun                 *int                              `json:"maxFilesPerRun"`
+	AutoCleanupEnabled             *bool                             `json:"autoCleanupEnabled"`
+	AutoCleanupMaxFiles            *int                              `json:"autoCleanupMaxFiles"`
+	AutoCleanupUnique              *bool                             `json:"autoCleanupUnique"`
+	AutoCleanupHardlinkedOrphans   *bool                             `json:"autoCleanupHardlinkedOrphans"`
+	AutoCleanupHardlinkedElsewhere *bool                             `json:"autoCleanupHardlinkedElsewhere"`
+	QuarantineEnabled              *bool                             `json:"quarantineEnabled"`
+	QuarantineRetentionDays        *int                              `json:"quarantineRetentionDays"`
+	SharedInstances                []models.OrphanScanSharedInstance `json:"sharedInstances"`
 }
 
 // GetSettings returns the orphan scan settings for an instance.
@@ -84,14 +92,20 @@ func (h *OrphanScanHandler) GetSettings(w http.ResponseWriter, r *http.Request)
 	if settings == nil {
 		defaults := orphanscan.DefaultSettings()
 		settings = &models.OrphanScanSettings{
-			InstanceID:          instanceID,
-			Enabled:             defaults.Enabled,
-			GracePeriodMinutes:  defaults.GracePeriodMinutes,
-			IgnorePaths:         defaults.IgnorePaths,
-			ScanIntervalHours:   defaults.ScanIntervalHours,
-			MaxFilesPerRun:      defaults.MaxFilesPerRun,
-			AutoCleanupEnabled:  defaults.AutoCleanupEnabled,
-			AutoCleanupMaxFiles: defaults.AutoCleanupMaxFiles,
+			InstanceID:                     instanceID,
+			Enabled:                        defaults.Enabled,
+			GracePeriodMinutes:             defaults.GracePeriodMinutes,
+			IgnorePaths:                    defaults.IgnorePaths,
+			ScanIntervalHours:              defaults.ScanIntervalHours,
+			MaxFilesPerRun:                 defaults.MaxFilesPerRun,
+			AutoCleanupEnabled:             defaults.AutoCleanupEnabled,
+			AutoCleanupMaxFiles:            defaults.AutoCleanupMaxFiles,
+			AutoCleanupUnique:              defaults.AutoCleanupUnique,
+			AutoCleanupHardlinkedOrphans:   defaults.AutoCleanupHardlinkedOrphans,
+			AutoCleanupHardlinkedElsewhere: defaults.AutoCleanupHardlinkedElsewhere,
+			QuarantineEnabled:              defaults.QuarantineEnabled,
+			QuarantineRetentionDays:        defaults.QuarantineRetentionDays,
+			SharedInstances:                []models.OrphanScanSharedInstance{},
 		}
 	}
 
@@ -127,14 +141,20 @@ func (h *OrphanScanHandler) UpdateSettings(w http.ResponseWriter, r *http.Reques
 	if settings == nil {
 		defaults := orphanscan.DefaultSettings()
 		settings = &models.OrphanScanSettings{
-			InstanceID:          instanceID,
-			Enabled:             defaults.Enabled,
-			GracePeriodMinutes:  defaults.GracePeriodMinutes,
-			IgnorePaths:         defaults.IgnorePaths,
-			ScanIntervalHours:   defaults.ScanIntervalHours,
-			MaxFilesPerRun:      defaults.MaxFilesPerRun,
-			AutoCleanupEnabled:  defaults.AutoCleanupEnabled,
-			AutoCleanupMaxFiles: defaults.AutoCleanupMaxFiles,
+			InstanceID:                     instanceID,
+			Enabled:                        defaults.Enabled,
+			GracePeriodMinutes:             defaults.GracePeriodMinutes,
+			IgnorePaths:                    defaults.IgnorePaths,
+			ScanIntervalHours:              defaults.ScanIntervalHours,
+			MaxFilesPerRun:                 defaults.MaxFilesPerRun,
+			AutoCleanupEnabled:             defaults.AutoCleanupEnabled,
+			AutoCleanupMaxFiles:            defaults.AutoCleanupMaxFiles,
+			AutoCleanupUnique:              defaults.AutoCleanupUnique,
+			AutoCleanupHardlinkedOrphans:   defaults.AutoCleanupHardlinkedOrphans,
+			AutoCleanupHardlinkedElsewhere: defaults.AutoCleanupHardlinkedElsewhere,
+			QuarantineEnabled:              defaults.QuarantineEnabled,
+			QuarantineRetentionDays:        defaults.QuarantineRetentionDays,
+			SharedInstances:                []models.OrphanScanSharedInstance{},
 		}
 	}
 
@@ -176,6 +196,34 @@ func (h *OrphanScanHandler) UpdateSettings(w http.ResponseWriter, r *http.Reques
 		}
 		settings.AutoCleanupMaxFiles = *payload.AutoCleanupMaxFiles
 	}
+	if payload.AutoCleanupUnique != nil {
+		settings.AutoCleanupUnique = *payload.AutoCleanupUnique
+	}
+	if payload.AutoCleanupHardlinkedOrphans != nil {
+		settings.AutoCleanupHardlinkedOrphans = *payload.AutoCleanupHardlinkedOrphans
+	}
+	if payload.AutoCleanupHardlinkedElsewhere != nil {
+		settings.AutoCleanupHardlinkedElsewhere = *payload.AutoCleanupHardlinkedElsewhere
+	}
+	if payload.QuarantineEnabled != nil {
+		settings.QuarantineEnabled = *payload.QuarantineEnabled
+	}
+	if payload.QuarantineRetentionDays != nil {
+		if *payload.QuarantineRetentionDays < 1 {
+			RespondError(w, http.StatusBadRequest, "Quarantine retention must be at least 1 day")
+			return
+		}
+		settings.QuarantineRetentionDays = *payload.QuarantineRetentionDays
+	}
+
+	if payload.SharedInstances != nil {
+		shared, err := h.normalizeSharedInstances(r, instanceID, payload.SharedInstances)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		settings.SharedInstances = shared
+	}
 
 	// Validate and normalize ignore paths
 	if len(settings.IgnorePaths) > 0 {
@@ -197,6 +245,45 @@ func (h *OrphanScanHandler) UpdateSettings(w http.ResponseWriter, r *http.Reques
 	RespondJSON(w, http.StatusOK, savedSettings)
 }
 
+// normalizeSharedInstances validates shared instance references and cleans their path mappings.
+func (h *OrphanScanHandler) normalizeSharedInstances(r *http.Request, instanceID int, shared []models.OrphanScanSharedInstance) ([]models.OrphanScanSharedInstance, error) {
+	result := make([]models.OrphanScanSharedInstance, 0, len(shared))
+	seen := make(map[int]struct{}, len(shared))
+
+	for _, member := range shared {
+		if member.InstanceID == instanceID {
+			return nil, errors.New("an instance cannot share storage with itself")
+		}
+		if _, ok := seen[member.InstanceID]; ok {
+			return nil, fmt.Errorf("instance %d is listed more than once", member.InstanceID)
+		}
+		seen[member.InstanceID] = struct{}{}
+
+		if _, err := h.instanceStore.Get(r.Context(), member.InstanceID); err != nil {
+			if errors.Is(err, models.ErrInstanceNotFound) {
+				return nil, fmt.Errorf("instance %d not found", member.InstanceID)
+			}
+			return nil, err
+		}
+
+		mappings := make([]models.OrphanScanPathMapping, 0, len(member.PathMappings))
+		for _, m := range member.PathMappings {
+			from, to := filepath.Clean(m.From), filepath.Clean(m.To)
+			if !filepath.IsAbs(from) || !filepath.IsAbs(to) {
+				return nil, fmt.Errorf("path mappings must use absolute paths: %s -> %s", m.From, m.To)
+			}
+			mappings = append(mappings, models.OrphanScanPathMapping{From: from, To: to})
+		}
+
+		result = append(result, models.OrphanScanSharedInstance{
+			InstanceID:   member.InstanceID,
+			PathMappings: mappings,
+		})
+	}
+
+	return result, nil
+}
+
 // TriggerScan starts a manual orphan scan for an instance.
 func (h *OrphanScanHandler) TriggerScan(w http.ResponseWriter, r *http.Request) {
 	instanceID, err := parseInstanceID(w, r)
@@ -451,3 +538,94 @@ func (h *OrphanScanHandler) CancelRun(w http.ResponseWriter, r *http.Request) {
 
 	RespondJSON(w, http.StatusOK, map[string]string{"status": "canceled"})
 }
+
+// RestoreRun moves all quarantined files of a run back to their original locations.
+func (h *OrphanScanHandler) RestoreRun(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	runIDStr := chi.URLParam(r, "runID")
+	runID, err := strconv.ParseInt(runIDStr, 10, 64)
+	if err != nil || runID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid run ID")
+		return
+	}
+
+	if h.service == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Orphan scan service not available")
+		return
+	}
+
+	result, err := h.service.RestoreRun(r.Context(), instanceID, runID)
+	if err != nil {
+		if errors.Is(err, orphanscan.ErrRunNotFound) {
+			RespondError(w, http.StatusNotFound, "Run not found")
+			return
+		}
+		if errors.Is(err, orphanscan.ErrScanInProgress) {
+			RespondError(w, http.StatusConflict, "A scan or deletion is already in progress for this instance")
+			return
+		}
+		log.Error().Err(err).Int64("runID", runID).Msg("orphanscan: failed to restore run")
+		RespondError(w, http.StatusInternalServerError, "Failed to restore files")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, result)
+}
+
+// RestoreFile moves a single quarantined file back to its original location.
+func (h *OrphanScanHandler) RestoreFile(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireLocalAccess(w, r, instanceID) {
+		return
+	}
+
+	runIDStr := chi.URLParam(r, "runID")
+	runID, err := strconv.ParseInt(runIDStr, 10, 64)
+	if err != nil || runID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid run ID")
+		return
+	}
+
+	fileIDStr := chi.URLParam(r, "fileID")
+	fileID, err := strconv.ParseInt(fileIDStr, 10, 64)
+	if err != nil || fileID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid file ID")
+		return
+	}
+
+	if h.service == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Orphan scan service not available")
+		return
+	}
+
+	if err := h.service.RestoreFile(r.Context(), instanceID, runID, fileID); err != nil {
+		switch {
+		case errors.Is(err, orphanscan.ErrRunNotFound):
+			RespondError(w, http.StatusNotFound, "Run not found")
+		case errors.Is(err, orphanscan.ErrFileNotFound):
+			RespondError(w, http.StatusNotFound, "File not found")
+		case errors.Is(err, orphanscan.ErrFileNotQuarantined):
+			RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, orphanscan.ErrRestoreTargetExists), errors.Is(err, orphanscan.ErrScanInProgress):
+			RespondError(w, http.StatusConflict, err.Error())
+		default:
+			log.Error().Err(err).Int64("runID", runID).Int64("fileID", fileID).Msg("orphanscan: failed to restore file")
+			RespondError(w, http.StatusInternalServerError, "Failed to restore file")
+		}
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{"status": "restored"})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/auth"
+)
+
+// sessionUserID returns the signed-in account, or 0 for API keys which have no sessions
+func sessionUserID(w http.ResponseWriter, r *http.Request) int {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok || user.ID == 0 {
+		RespondError(w, http.StatusForbidden, "Sessions are only available for user accounts")
+		return 0
+	}
+	return user.ID
+}
+
+// ListSessions returns the current user's active sessions
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID := sessionUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	sessions, err := auth.ListSessions(r.Context(), h.sessionManager, userID, h.sessionManager.Token(r.Context()))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list sessions")
+		RespondError(w, http.StatusInternalServerError, "Failed to list sessions")
+		return
+	}
+	if sessions == nil {
+		sessions = []auth.SessionInfo{}
+	}
+
+	RespondJSON(w, http.StatusOK, sessions)
+}
+
+// RevokeSession signs out one of the current user's sessions
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID := sessionUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == auth.SessionID(h.sessionManager.Token(r.Context())) {
+		RespondError(w, http.StatusBadRequest, "Use logout to end the current session")
+		return
+	}
+
+	found, err := auth.RevokeSession(r.Context(), h.sessionManager, userID, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to revoke session")
+		RespondError(w, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	if !found {
+		RespondError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAllSessions logs the current user out everywhere. With ?others=true
+// the current session stays signed in.
+func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID := sessionUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	keepCurrent := r.URL.Query().Get("others") == "true"
+	currentToken := h.sessionManager.Token(r.Context())
+
+	revoked, err := auth.RevokeUserSessions(r.Context(), h.sessionManager, userID, currentToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to revoke sessions")
+		RespondError(w, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	if !keepCurrent {
+		if err := h.sessionManager.Destroy(r.Context()); err != nil {
+			log.Error().Err(err).Msg("Failed to destroy session")
+			RespondError(w, http.StatusInternalServerError, "Failed to revoke sessions")
+			return
+		}
+		revoked++
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]int{
+		"revoked": revoked,
+	})
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	defaultStatsRange   = 24 * time.Hour
+	maxRawStatsRange    = 2 * 24 * time.Hour
+	maxHourlyStatsRange = 60 * 24 * time.Hour
+)
+
+// StatsHandler serves the historical statistics store.
+type StatsHandler struct {
+	store *models.StatsStore
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(store *models.StatsStore) *StatsHandler {
+	return &StatsHandler{store: store}
+}
+
+// statsRange is a parsed time-range query.
+type statsRange struct {
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"`
+	Resolution models.StatsResolution `json:"resolution"`
+}
+
+type instanceStatsResponse struct {
+	statsRange
+	Points []models.InstanceStatsPoint `json:"points"`
+}
+
+type trackerStatsResponse struct {
+	statsRange
+	Points []models.TrackerStatsPoint `json:"points"`
+}
+
+type trackerTotalsResponse struct {
+	statsRange
+	Trackers []models.TrackerStatsTotal `json:"trackers"`
+}
+
+// parseStatsRange reads from, to and resolution. Times are RFC3339; the range
+// defaults to the last 24 hours and the resolution to the finest tier that
+// suits the range. Returns a user-facing error message on failure.
+func parseStatsRange(r *http.Request) (statsRange, string) {
+	query := r.URL.Query()
+	rng := statsRange{To: time.Now().UTC()}
+
+	if v := query.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return rng, "to must be an RFC3339 timestamp"
+		}
+		rng.To = to.UTC()
+	}
+	rng.From = rng.To.Add(-defaultStatsRange)
+	if v := query.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return rng, "from must be an RFC3339 timestamp"
+		}
+		rng.From = from.UTC()
+	}
+	if !rng.From.Before(rng.To) {
+		return rng, "from must be before to"
+	}
+
+	rng.Resolution = models.StatsResolution(query.Get("resolution"))
+	switch {
+	case rng.Resolution == "":
+		span := rng.To.Sub(rng.From)
+		switch {
+		case span <= maxRawStatsRange:
+			rng.Resolution = models.StatsResolutionRaw
+		case span <= maxHourlyStatsRange:
+			rng.Resolution = models.StatsResolutionHour
+		default:
+			rng.Resolution = models.StatsResolutionDay
+		}
+	case !rng.Resolution.IsValid():
+		return rng, "resolution must be raw, hour or day"
+	}
+
+	return rng, ""
+}
+
+// parseTrackerStatsQuery reads the shared tracker query parameters.
+func parseTrackerStatsQuery(r *http.Request) (models.TrackerStatsQuery, statsRange, string) {
+	rng, msg := parseStatsRange(r)
+	if msg != "" {
+		return models.TrackerStatsQuery{}, rng, msg
+	}
+
+	query := models.TrackerStatsQuery{
+		Tracker:    r.URL.Query().Get("tracker"),
+		Resolution: rng.Resolution,
+		From:       rng.From,
+		To:         rng.To,
+	}
+	if v := r.URL.Query().Get("instanceId"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			return query, rng, "Invalid instance ID"
+		}
+		query.InstanceID = id
+	}
+	return query, rng, ""
+}
+
+// scopeTrackerStatsQuery limits a tracker query to the instances the caller may
+// access. It responds and returns false when the requested instance is not
+// accessible, and reports empty when the caller has no instances at all.
+func scopeTrackerStatsQuery(w http.ResponseWriter, r *http.Request, query *models.TrackerStatsQuery) (ok, empty bool) {
+	if query.InstanceID > 0 {
+		if !auth.CanAccessInstance(r.Context(), query.InstanceID) {
+			RespondError(w, http.StatusForbidden, "Forbidden")
+			return false, false
+		}
+		return true, false
+	}
+
+	user, found := auth.UserFromContext(r.Context())
+	if !found {
+		RespondError(w, http.StatusForbidden, "Forbidden")
+		return false, false
+	}
+	if user.Role == models.RoleAdmin || user.AllInstances {
+		return true, false
+	}
+	query.InstanceIDs = user.InstanceIDs
+	return true, len(user.InstanceIDs) == 0
+}
+
+// InstanceHistory returns an instance's transfer, speed and torrent count history.
+func (h *StatsHandler) InstanceHistory(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	rng, msg := parseStatsRange(r)
+	if msg != "" {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	points, err := h.store.InstanceSeries(r.Context(), instanceID, rng.Resolution, rng.From, rng.To)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to load instance stats history")
+		RespondError(w, http.StatusInternalServerError, "Failed to load stats history")
+		return
+	}
+	if points == nil {
+		points = []models.InstanceStatsPoint{}
+	}
+
+	RespondJSON(w, http.StatusOK, instanceStatsResponse{statsRange: rng, Points: points})
+}
+
+// TrackerHistory returns per-tracker transfer history, optionally narrowed to
+// one instance or tracker. Without an instance it covers the caller's instances.
+func (h *StatsHandler) TrackerHistory(w http.ResponseWriter, r *http.Request) {
+	query, rng, msg := parseTrackerStatsQuery(r)
+	if msg != "" {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	ok, empty := scopeTrackerStatsQuery(w, r, &query)
+	if !ok {
+		return
+	}
+	if empty {
+		RespondJSON(w, http.StatusOK, trackerStatsResponse{statsRange: rng, Points: []models.TrackerStatsPoint{}})
+		return
+	}
+
+	points, err := h.store.TrackerSeries(r.Context(), query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load tracker stats history")
+		RespondError(w, http.StatusInternalServerError, "Failed to load stats history")
+		return
+	}
+	if points == nil {
+		points = []models.TrackerStatsPoint{}
+	}
+
+	RespondJSON(w, http.StatusOK, trackerStatsResponse{statsRange: rng, Points: points})
+}
+
+// TrackerTotals returns each tracker's transfer summed over the range.
+func (h *StatsHandler) TrackerTotals(w http.ResponseWriter, r *http.Request) {
+	query, rng, msg := parseTrackerStatsQuery(r)
+	if msg != "" {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	ok, empty := scopeTrackerStatsQuery(w, r, &query)
+	if !ok {
+		return
+	}
+	if empty {
+		RespondJSON(w, http.StatusOK, trackerTotalsResponse{statsRange: rng, Trackers: []models.TrackerStatsTotal{}})
+		return
+	}
+
+	totals, err := h.store.TrackerTotals(r.Context(), query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load tracker stats totals")
+		RespondError(w, http.StatusInternalServerError, "Failed to load stats totals")
+		return
+	}
+	if totals == nil {
+		totals = []models.TrackerStatsTotal{}
+	}
+
+	RespondJSON(w, http.StatusOK, trackerTotalsResponse{statsRange: rng, Trackers: totals})
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestTrackerStatsRespectInstanceAccess(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.New(filepath.Join(t.TempDir(), "stats.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	instances, err := models.NewInstanceStore(db, make([]byte, 32))
+	require.NoError(t, err)
+	granted, err := instances.Create(ctx, "granted", "http://localhost:8080", "admin", "password", nil, nil, false, nil)
+	require.NoError(t, err)
+	other, err := instances.Create(ctx, "other", "http://localhost:8081", "admin", "password", nil, nil, false, nil)
+	require.NoError(t, err)
+
+	store := models.NewStatsStore(db)
+	now := time.Now().UTC().Add(-time.Hour)
+	for _, sample := range []models.StatsSample{
+		{InstanceID: granted.ID, Time: now, Trackers: []models.TrackerStatsSample{{Tracker: "tracker.example", Uploaded: 10}}},
+		{InstanceID: other.ID, Time: now, Trackers: []models.TrackerStatsSample{{Tracker: "tracker.example", Uploaded: 500}}},
+	} {
+		require.NoError(t, store.Record(ctx, sample, time.Minute))
+	}
+
+	handler := NewStatsHandler(store)
+	viewer := &models.User{ID: 2, Role: models.RoleReadOnly, InstanceIDs: []int{granted.ID}}
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+
+	totals := func(user *models.User, instanceID int) (*httptest.ResponseRecorder, []models.TrackerStatsTotal) {
+		target := "/api/stats/trackers/totals"
+		if instanceID > 0 {
+			target += "?instanceId=" + strconv.Itoa(instanceID)
+		}
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(auth.WithUser(req.Context(), user))
+		rec := httptest.NewRecorder()
+		handler.TrackerTotals(rec, req)
+
+		var body struct {
+			Trackers []models.TrackerStatsTotal `json:"trackers"`
+		}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		}
+		return rec, body.Trackers
+	}
+
+	rec, trackers := totals(viewer, granted.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []models.TrackerStatsTotal{{Tracker: "tracker.example", Uploaded: 10}}, trackers)
+
+	rec, _ = totals(viewer, other.ID)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, trackers = totals(viewer, 0)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []models.TrackerStatsTotal{{Tracker: "tracker.example", Uploaded: 10}}, trackers, "unscoped totals only cover granted instances")
+
+	rec, trackers = totals(&models.User{ID: 3, Role: models.RoleReadOnly}, 0)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, trackers)
+
+	rec, trackers = totals(admin, 0)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []models.TrackerStatsTotal{{Tracker: "tracker.example", Uploaded: 510}}, trackers)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats/trackers?instanceId="+strconv.Itoa(other.ID), nil)
+	req = req.WithContext(auth.WithUser(req.Context(), viewer))
+	historyRec := httptest.NewRecorder()
+	handler.TrackerHistory(historyRec, req)
+	assert.Equal(t, http.StatusForbidden, historyRec.Code)
+}
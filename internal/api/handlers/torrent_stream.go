@@ -0,0 +1,103 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+// StreamTorrents streams torrent list deltas for an instance via SSE.
+//
+// The stream opens with a "snapshot" event holding the filtered torrent list,
+// followed by "delta" events. A "resync" event means the client fell behind and
+// should reconnect to get a fresh snapshot.
+func (h *TorrentsHandler) StreamTorrents(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	var filters qbittorrent.FilterOptions
+	if f := r.URL.Query().Get("filters"); f != "" {
+		if err := json.Unmarshal([]byte(f), &filters); err != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid filters")
+			return
+		}
+	}
+	search := r.URL.Query().Get("search")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		RespondError(w, http.StatusInternalServerError, errStreamingNotSupported.Error())
+		return
+	}
+
+	stream, snapshot, err := h.syncManager.SubscribeTorrentDeltas(r.Context(), instanceID, filters, search)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to start torrent stream")
+		RespondError(w, http.StatusServiceUnavailable, "Failed to start torrent stream")
+		return
+	}
+	defer stream.Close()
+
+	// The stream outlives the server's write timeout; keepalives detect dead clients instead.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	if err := writeSSEEvent(w, "snapshot", snapshot); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case delta, ok := <-stream.Updates():
+			if !ok {
+				if errors.Is(stream.Err(), qbittorrent.ErrTorrentStreamOverflow) {
+					_ = writeSSEEvent(w, "resync", map[string]string{"reason": stream.Err().Error()})
+					flusher.Flush()
+				}
+				return
+			}
+			if err := writeSSEEvent(w, "delta", delta); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			if err := writeSSEComment(w, "keepalive"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", event, err)
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err //nolint:wrapcheck // SSE write errors are terminal; wrapping adds no value
+}
@@ -715,51 +715,8 @@ func (h *TorrentsHandler) BulkAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If selectAll is true, get all torrent hashes matching the filters
-	var targetHashes []string
-	if req.SelectAll {
-		// Default to empty filters if not provided
-		if req.Filters == nil {
-			req.Filters = &qbittorrent.FilterOptions{}
-		}
-
-		// Get all torrents matching the current filters and search
-		// Use a very large limit to get all torrents (backend will handle this properly)
-		response, err := h.syncManager.GetTorrentsWithFilters(r.Context(), instanceID, 100000, 0, "added_on", "desc", req.Search, *req.Filters)
-		if err != nil {
-			if respondIfInstanceDisabled(w, err, instanceID, "torrents:selectAll") {
-				return
-			}
-			// Record error for user visibility
-			errorStore := h.syncManager.GetErrorStore()
-			if recordErr := errorStore.RecordError(r.Context(), instanceID, err); recordErr != nil {
-				log.Error().Err(recordErr).Int("instanceID", instanceID).Msg("Failed to record torrent error")
-			}
-
-			log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to get torrents for selectAll operation")
-			RespondError(w, http.StatusInternalServerError, "Failed to get torrents for bulk action")
-			return
-		}
-
-		// Extract all hashes and filter out excluded ones
-		excludeSet := make(map[string]bool)
-		for _, hash := range req.ExcludeHashes {
-			excludeSet[hash] = true
-		}
-
-		for _, torrent := range response.Torrents {
-			if !excludeSet[torrent.Hash] {
-				targetHashes = append(targetHashes, torrent.Hash)
-			}
-		}
-
-		log.Debug().Int("instanceID", instanceID).Int("totalFound", len(response.Torrents)).Int("excluded", len(req.ExcludeHashes)).Int("targetCount", len(targetHashes)).Str("action", req.Action).Msg("SelectAll bulk action")
-	} else {
-		targetHashes = req.Hashes
-	}
-
-	if len(targetHashes) == 0 {
-		RespondError(w, http.StatusBadRequest, "No torrents match the selection criteria")
+	targetHashes, ok := resolveBulkTargets(w, r, h.syncManager, instanceID, &req)
+	if !ok {
 		return
 	}
 
@@ -844,6 +801,61 @@ func (h *TorrentsHandler) BulkAction(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// resolveBulkTargets returns the hashes a bulk request applies to, expanding selectAll
+// into every torrent matching the request filters. It writes the error response and
+// returns false when no targets can be resolved.
+func resolveBulkTargets(w http.ResponseWriter, r *http.Request, syncManager *qbittorrent.SyncManager, instanceID int, req *BulkActionRequest) ([]string, bool) {
+	// If selectAll is true, get all torrent hashes matching the filters
+	var targetHashes []string
+	if req.SelectAll {
+		// Default to empty filters if not provided
+		if req.Filters == nil {
+			req.Filters = &qbittorrent.FilterOptions{}
+		}
+
+		// Get all torrents matching the current filters and search
+		// Use a very large limit to get all torrents (backend will handle this properly)
+		response, err := syncManager.GetTorrentsWithFilters(r.Context(), instanceID, 100000, 0, "added_on", "desc", req.Search, *req.Filters)
+		if err != nil {
+			if respondIfInstanceDisabled(w, err, instanceID, "torrents:selectAll") {
+				return nil, false
+			}
+			// Record error for user visibility
+			errorStore := syncManager.GetErrorStore()
+			if recordErr := errorStore.RecordError(r.Context(), instanceID, err); recordErr != nil {
+				log.Error().Err(recordErr).Int("instanceID", instanceID).Msg("Failed to record torrent error")
+			}
+
+			log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to get torrents for selectAll operation")
+			RespondError(w, http.StatusInternalServerError, "Failed to get torrents for bulk action")
+			return nil, false
+		}
+
+		// Extract all hashes and filter out excluded ones
+		excludeSet := make(map[string]bool)
+		for _, hash := range req.ExcludeHashes {
+			excludeSet[hash] = true
+		}
+
+		for _, torrent := range response.Torrents {
+			if !excludeSet[torrent.Hash] {
+				targetHashes = append(targetHashes, torrent.Hash)
+			}
+		}
+
+		log.Debug().Int("instanceID", instanceID).Int("totalFound", len(response.Torrents)).Int("excluded", len(req.ExcludeHashes)).Int("targetCount", len(targetHashes)).Str("action", req.Action).Msg("SelectAll bulk action")
+	} else {
+		targetHashes = req.Hashes
+	}
+
+	if len(targetHashes) == 0 {
+		RespondError(w, http.StatusBadRequest, "No torrents match the selection criteria")
+		return nil, false
+	}
+
+	return targetHashes, true
+}
+
 // GetCategories returns all categories
 func (h *TorrentsHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
 	// Get instance ID from URL
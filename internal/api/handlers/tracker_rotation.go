@@ -0,0 +1,101 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/services/trackerrotation"
+)
+
+type TrackerRotationHandler struct {
+	service *trackerrotation.Service
+}
+
+func NewTrackerRotationHandler(service *trackerrotation.Service) *TrackerRotationHandler {
+	return &TrackerRotationHandler{service: service}
+}
+
+func decodeRotationSpec(w http.ResponseWriter, r *http.Request) (trackerrotation.Spec, bool) {
+	var spec trackerrotation.Spec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return spec, false
+	}
+	if err := spec.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return spec, false
+	}
+	return spec, true
+}
+
+// Preview lists the torrents and backup blobs a rotation would change.
+func (h *TrackerRotationHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	spec, ok := decodeRotationSpec(w, r)
+	if !ok {
+		return
+	}
+
+	preview, err := h.service.Preview(r.Context(), spec)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to preview tracker rotation")
+		RespondError(w, http.StatusInternalServerError, "Failed to preview tracker rotation")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, preview)
+}
+
+// StartJob applies a rotation in the background.
+func (h *TrackerRotationHandler) StartJob(w http.ResponseWriter, r *http.Request) {
+	spec, ok := decodeRotationSpec(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.Start(spec)
+	if err != nil {
+		if errors.Is(err, trackerrotation.ErrJobRunning) {
+			RespondError(w, http.StatusConflict, "A tracker rotation is already running")
+			return
+		}
+		log.Error().Err(err).Msg("failed to start tracker rotation")
+		RespondError(w, http.StatusInternalServerError, "Failed to start tracker rotation")
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, job)
+}
+
+// ListJobs returns recent rotation jobs, newest first.
+func (h *TrackerRotationHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	RespondJSON(w, http.StatusOK, h.service.ListJobs())
+}
+
+// GetJob returns the progress and results of a rotation job.
+func (h *TrackerRotationHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "jobID"), 10, 64)
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	job, err := h.service.GetJob(id)
+	if err != nil {
+		if errors.Is(err, trackerrotation.ErrJobNotFound) {
+			RespondError(w, http.StatusNotFound, "Tracker rotation job not found")
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, "Failed to load tracker rotation job")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	// twoFactorLoginTimeout is how long the second login step stays open after the password is accepted.
+	twoFactorLoginTimeout = 5 * time.Minute
+	// twoFactorMaxAttempts is how many wrong codes end the pending login.
+	twoFactorMaxAttempts = 5
+)
+
+// Session keys for a login waiting on its second step
+const (
+	sessionPendingUserID   = "pending_2fa_user_id"
+	sessionPendingUsername = "pending_2fa_username"
+	sessionPendingExpires  = "pending_2fa_expires"
+	sessionPendingRemember = "pending_2fa_remember"
+	sessionPendingAttempts = "pending_2fa_attempts"
+)
+
+// TwoFactorCodeRequest carries a TOTP code or a recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// startTwoFactorLogin remembers a user whose password was accepted and asks
+// for their second factor. The session isn't authenticated yet.
+func (h *AuthHandler) startTwoFactorLogin(w http.ResponseWriter, r *http.Request, user *models.User, rememberMe bool) {
+	if err := h.sessionManager.RenewToken(r.Context()); err != nil {
+		log.Error().Err(err).Msg("Failed to renew session token")
+	}
+
+	h.sessionManager.Put(r.Context(), sessionPendingUserID, user.ID)
+	h.sessionManager.Put(r.Context(), sessionPendingUsername, user.Username)
+	h.sessionManager.Put(r.Context(), sessionPendingExpires, time.Now().Add(twoFactorLoginTimeout).Unix())
+	h.sessionManager.Put(r.Context(), sessionPendingRemember, rememberMe)
+	h.sessionManager.Put(r.Context(), sessionPendingAttempts, 0)
+
+	RespondJSON(w, http.StatusOK, map[string]any{
+		"message":           "Two-factor code required",
+		"twoFactorRequired": true,
+	})
+}
+
+func (h *AuthHandler) clearTwoFactorLogin(r *http.Request) {
+	h.sessionManager.Remove(r.Context(), sessionPendingUserID)
+	h.sessionManager.Remove(r.Context(), sessionPendingUsername)
+	h.sessionManager.Remove(r.Context(), sessionPendingExpires)
+	h.sessionManager.Remove(r.Context(), sessionPendingRemember)
+	h.sessionManager.Remove(r.Context(), sessionPendingAttempts)
+}
+
+// LoginTwoFactor completes a login with a TOTP or recovery code
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID := h.sessionManager.GetInt(r.Context(), sessionPendingUserID)
+	expires := h.sessionManager.GetInt64(r.Context(), sessionPendingExpires)
+	if userID == 0 || time.Now().Unix() > expires {
+		h.clearTwoFactorLogin(r)
+		RespondError(w, http.StatusUnauthorized, "Login expired, please sign in again")
+		return
+	}
+
+	username := h.sessionManager.GetString(r.Context(), sessionPendingUsername)
+	ip := auth.ClientIP(r)
+	if wait := h.loginLimiter.Check(username, ip); wait > 0 {
+		respondLoginLocked(w, wait)
+		return
+	}
+
+	user, err := h.authService.VerifyTwoFactor(r.Context(), userID, req.Code)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidTwoFactorCode) {
+			h.recordLoginFailure(username, ip)
+			attempts := h.sessionManager.GetInt(r.Context(), sessionPendingAttempts) + 1
+			if attempts >= twoFactorMaxAttempts {
+				log.Warn().Int("userID", userID).Msg("Too many invalid two-factor codes, ending login")
+				h.clearTwoFactorLogin(r)
+				RespondError(w, http.StatusUnauthorized, "Too many invalid codes, please sign in again")
+				return
+			}
+			h.sessionManager.Put(r.Context(), sessionPendingAttempts, attempts)
+			RespondError(w, http.StatusUnauthorized, "Invalid two-factor code")
+			return
+		}
+		if errors.Is(err, auth.ErrTwoFactorNotEnabled) || errors.Is(err, models.ErrUserNotFound) {
+			h.clearTwoFactorLogin(r)
+			RespondError(w, http.StatusUnauthorized, "Login expired, please sign in again")
+			return
+		}
+		log.Error().Err(err).Msg("Two-factor verification failed")
+		RespondError(w, http.StatusInternalServerError, "Login failed")
+		return
+	}
+
+	rememberMe := h.sessionManager.GetBool(r.Context(), sessionPendingRemember)
+	h.clearTwoFactorLogin(r)
+	h.completeLogin(w, r, user, rememberMe)
+}
+
+// twoFactorUserID returns the signed-in account, or 0 for API keys which have no account
+func twoFactorUserID(w http.ResponseWriter, r *http.Request) int {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok || user.ID == 0 {
+		RespondError(w, http.StatusForbidden, "Two-factor authentication is only available for user accounts")
+		return 0
+	}
+	return user.ID
+}
+
+// respondTwoFactorError maps two-factor service errors to responses
+func respondTwoFactorError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidTwoFactorCode):
+		RespondError(w, http.StatusUnauthorized, "Invalid two-factor code")
+	case errors.Is(err, auth.ErrTwoFactorNotEnabled):
+		RespondError(w, http.StatusBadRequest, "Two-factor authentication is not enabled")
+	case errors.Is(err, auth.ErrTwoFactorAlreadyActive):
+		RespondError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+	case errors.Is(err, auth.ErrTwoFactorNotPending):
+		RespondError(w, http.StatusBadRequest, "Start two-factor setup first")
+	case errors.Is(err, auth.ErrTwoFactorUnavailable):
+		RespondError(w, http.StatusBadRequest, "Two-factor authentication is only available for password logins")
+	default:
+		log.Error().Err(err).Msg("Failed to " + action)
+		RespondError(w, http.StatusInternalServerError, "Failed to "+action)
+	}
+}
+
+// GetTwoFactorStatus returns whether two-factor is enabled for the current user
+func (h *AuthHandler) GetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userID := twoFactorUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	status, err := h.authService.GetTwoFactorStatus(r.Context(), userID)
+	if err != nil {
+		respondTwoFactorError(w, err, "load two-factor status")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, status)
+}
+
+// SetupTwoFactor starts TOTP enrollment and returns the secret and QR code
+func (h *AuthHandler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := twoFactorUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	enrollment, err := h.authService.BeginTOTPEnrollment(r.Context(), userID)
+	if err != nil {
+		respondTwoFactorError(w, err, "start two-factor setup")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, enrollment)
+}
+
+// EnableTwoFactor confirms enrollment and returns the recovery codes
+func (h *AuthHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := twoFactorUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	codes, err := h.authService.EnableTOTP(r.Context(), userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(w, err, "enable two-factor authentication")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]any{
+		"message":       "Two-factor authentication enabled",
+		"recoveryCodes": codes,
+	})
+}
+
+// DisableTwoFactor turns off two-factor after checking a current code
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := twoFactorUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.authService.DisableTOTP(r.Context(), userID, req.Code); err != nil {
+		respondTwoFactorError(w, err, "disable two-factor authentication")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID := twoFactorUserID(w, r)
+	if userID == 0 {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(w, err, "regenerate recovery codes")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]any{
+		"recoveryCodes": codes,
+	})
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/models"
+)
+
+type UsersHandler struct {
+	authService    *auth.Service
+	instanceStore  *models.InstanceStore
+	sessionManager *scs.SessionManager
+}
+
+func NewUsersHandler(authService *auth.Service, instanceStore *models.InstanceStore, sessionManager *scs.SessionManager) *UsersHandler {
+	return &UsersHandler{authService: authService, instanceStore: instanceStore, sessionManager: sessionManager}
+}
+
+type UserPayload struct {
+	Username string          `json:"username"`
+	Password string          `json:"password,omitempty"`
+	Role     models.UserRole `json:"role"`
+	// AllInstances grants every instance, including ones added later.
+	AllInstances bool  `json:"allInstances"`
+	InstanceIDs  []int `json:"instanceIds"`
+}
+
+// validate returns a user-facing error message, or "" when the payload is valid.
+func (h *UsersHandler) validate(r *http.Request, p *UserPayload) string {
+	p.Username = strings.TrimSpace(p.Username)
+	switch {
+	case p.Username == "":
+		return "Username is required"
+	case !p.Role.Valid():
+		return "Role must be one of admin, operator or read_only"
+	case p.Password != "" && len(p.Password) < 8:
+		return "Password must be at least 8 characters long"
+	}
+
+	if len(p.InstanceIDs) == 0 {
+		return ""
+	}
+	instances, err := h.instanceStore.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list instances for user grants")
+		return "Failed to validate instance permissions"
+	}
+	known := make(map[int]struct{}, len(instances))
+	for _, instance := range instances {
+		known[instance.ID] = struct{}{}
+	}
+	for _, id := range p.InstanceIDs {
+		if _, ok := known[id]; !ok {
+			return "Unknown instance ID " + strconv.Itoa(id)
+		}
+	}
+	return ""
+}
+
+func (p *UserPayload) toModel(id int) *models.User {
+	return &models.User{
+		ID:           id,
+		Username:     p.Username,
+		Role:         p.Role,
+		AllInstances: p.AllInstances,
+		InstanceIDs:  p.InstanceIDs,
+	}
+}
+
+func (h *UsersHandler) List(w http.ResponseWriter, r *http.Request) {
+	users, err := h.authService.ListUsers(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list users")
+		RespondError(w, http.StatusInternalServerError, "Failed to load users")
+		return
+	}
+	if users == nil {
+		users = []*models.User{}
+	}
+
+	RespondJSON(w, http.StatusOK, users)
+}
+
+func (h *UsersHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var payload UserPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if payload.Password == "" {
+		RespondError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+	if msg := h.validate(r, &payload); msg != "" {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	user, err := h.authService.CreateUser(r.Context(), payload.toModel(0), payload.Password)
+	if err != nil {
+		if errors.Is(err, models.ErrUserAlreadyExists) {
+			RespondError(w, http.StatusConflict, "Username is already taken")
+			return
+		}
+		log.Error().Err(err).Msg("failed to create user")
+		RespondError(w, http.StatusInternalServerError, "Failed to create user")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, user)
+}
+
+func (h *UsersHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var payload UserPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if msg := h.validate(r, &payload); msg != "" {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	user, err := h.authService.UpdateUser(r.Context(), payload.toModel(id), payload.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			RespondError(w, http.StatusNotFound, "User not found")
+		case errors.Is(err, models.ErrUserAlreadyExists):
+			RespondError(w, http.StatusConflict, "Username is already taken")
+		case errors.Is(err, auth.ErrLastAdmin):
+			RespondError(w, http.StatusConflict, "At least one admin account is required")
+		default:
+			log.Error().Err(err).Int("id", id).Msg("failed to update user")
+			RespondError(w, http.StatusInternalServerError, "Failed to update user")
+		}
+		return
+	}
+
+	// A new password signs the user out everywhere except the admin's own session
+	if payload.Password != "" {
+		if _, err := auth.RevokeUserSessions(r.Context(), h.sessionManager, id, h.sessionManager.Token(r.Context())); err != nil {
+			log.Error().Err(err).Int("id", id).Msg("failed to revoke sessions after password reset")
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, user)
+}
+
+func (h *UsersHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if current, ok := auth.UserFromContext(r.Context()); ok && current.ID == id {
+		RespondError(w, http.StatusBadRequest, "You can't delete your own account")
+		return
+	}
+
+	if err := h.authService.DeleteUser(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			RespondError(w, http.StatusNotFound, "User not found")
+		case errors.Is(err, auth.ErrLastAdmin):
+			RespondError(w, http.StatusConflict, "At least one admin account is required")
+		default:
+			log.Error().Err(err).Int("id", id).Msg("failed to delete user")
+			RespondError(w, http.StatusInternalServerError, "Failed to delete user")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
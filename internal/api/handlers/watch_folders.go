@@ -0,0 +1,239 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/watchfolder"
+)
+
+type WatchFolderHandler struct {
+	store         *models.WatchFolderStore
+	instanceStore *models.InstanceStore
+	service       *watchfolder.Service
+}
+
+func NewWatchFolderHandler(store *models.WatchFolderStore, instanceStore *models.InstanceStore, service *watchfolder.Service) *WatchFolderHandler {
+	return &WatchFolderHandler{
+		store:         store,
+		instanceStore: instanceStore,
+		service:       service,
+	}
+}
+
+// WatchFolderPayload is the request body for creating/updating a watch folder.
+type WatchFolderPayload struct {
+	Path        string                 `json:"path"`
+	Mode        models.WatchFolderMode `json:"mode"`
+	Category    string                 `json:"category"`
+	Tags        []string               `json:"tags"`
+	StartPaused bool                   `json:"startPaused"`
+	Enabled     *bool                  `json:"enabled"`
+}
+
+// toModel validates the payload and converts it to a watch folder.
+// Returns a user-facing error message when validation fails.
+func (p *WatchFolderPayload) toModel(instanceID int) (*models.WatchFolder, string) {
+	path := strings.TrimSpace(p.Path)
+	if path == "" {
+		return nil, "Path is required"
+	}
+	if !filepath.IsAbs(path) {
+		return nil, "Path must be absolute"
+	}
+	path = filepath.Clean(path)
+
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return nil, "Path must be an existing directory"
+	}
+
+	mode := p.Mode
+	if mode == "" {
+		mode = models.WatchFolderModeAdd
+	}
+	if !mode.IsValid() {
+		return nil, "Mode must be 'add' or 'cross_seed'"
+	}
+
+	tags := make([]string, 0, len(p.Tags))
+	for _, tag := range p.Tags {
+		if trimmed := strings.TrimSpace(tag); trimmed != "" {
+			tags = append(tags, trimmed)
+		}
+	}
+
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+
+	return &models.WatchFolder{
+		InstanceID:  instanceID,
+		Path:        path,
+		Mode:        mode,
+		Category:    strings.TrimSpace(p.Category),
+		Tags:        tags,
+		StartPaused: p.StartPaused,
+		Enabled:     enabled,
+	}, ""
+}
+
+func (h *WatchFolderHandler) requireInstance(w http.ResponseWriter, r *http.Request, instanceID int) bool {
+	if _, err := h.instanceStore.Get(r.Context(), instanceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Instance not found")
+			return false
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("watchfolder: failed to get instance")
+		RespondError(w, http.StatusInternalServerError, "Failed to get instance")
+		return false
+	}
+	return true
+}
+
+func parseWatchFolderID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil || folderID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid watch folder ID")
+		return 0, false
+	}
+	return folderID, true
+}
+
+// List returns the watch folders configured for an instance.
+func (h *WatchFolderHandler) List(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	folders, err := h.store.ListByInstance(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("watchfolder: failed to list folders")
+		RespondError(w, http.StatusInternalServerError, "Failed to list watch folders")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, folders)
+}
+
+// Create adds a watch folder to an instance.
+func (h *WatchFolderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	if !h.requireInstance(w, r, instanceID) {
+		return
+	}
+
+	var payload WatchFolderPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	folder, msg := payload.toModel(instanceID)
+	if folder == nil {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	created, err := h.store.Create(r.Context(), folder)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			RespondError(w, http.StatusConflict, "Path is already watched")
+			return
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("watchfolder: failed to create folder")
+		RespondError(w, http.StatusInternalServerError, "Failed to create watch folder")
+		return
+	}
+
+	h.service.Reload()
+	RespondJSON(w, http.StatusCreated, created)
+}
+
+// Update replaces a watch folder's configuration.
+func (h *WatchFolderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	folderID, ok := parseWatchFolderID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload WatchFolderPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	folder, msg := payload.toModel(instanceID)
+	if folder == nil {
+		RespondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	folder.ID = folderID
+
+	updated, err := h.store.Update(r.Context(), folder)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			RespondError(w, http.StatusNotFound, "Watch folder not found")
+		case strings.Contains(err.Error(), "UNIQUE constraint failed"):
+			RespondError(w, http.StatusConflict, "Path is already watched")
+		default:
+			log.Error().Err(err).Int64("folderID", folderID).Msg("watchfolder: failed to update folder")
+			RespondError(w, http.StatusInternalServerError, "Failed to update watch folder")
+		}
+		return
+	}
+
+	h.service.Reload()
+	RespondJSON(w, http.StatusOK, updated)
+}
+
+// Delete removes a watch folder. Files already in the folder are left untouched.
+func (h *WatchFolderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	folderID, ok := parseWatchFolderID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Delete(r.Context(), instanceID, folderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Watch folder not found")
+			return
+		}
+		log.Error().Err(err).Int64("folderID", folderID).Msg("watchfolder: failed to delete folder")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete watch folder")
+		return
+	}
+
+	h.service.Reload()
+	w.WriteHeader(http.StatusNoContent)
+}
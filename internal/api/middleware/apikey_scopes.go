@@ -0,0 +1,66 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// readScopePrefixes are the API areas an API key with the read scope may GET.
+// Everything else, such as users, keys and integrations, needs the admin scope.
+var readScopePrefixes = []string{
+	"instances",
+	"torrents/",
+	"stats/",
+	"hnr/",
+	"tracker-icons",
+	"tracker-customizations",
+	"version/",
+	"license",
+	"auth/me",
+}
+
+var (
+	backupsPath       = regexp.MustCompile(`^instances/\d+/backups(/|$)`)
+	torrentsWritePath = regexp.MustCompile(`^instances/\d+/(torrents|categories|tags|torrent-creator|alternative-speed-limits)(/|$)`)
+)
+
+// requiredAPIKeyScope returns the scope an API key needs for the request.
+func requiredAPIKeyScope(r *http.Request) models.APIKeyScope {
+	path := apiPath(r.URL.Path)
+
+	switch {
+	case strings.HasPrefix(path, "cross-seed/webhook/") || path == "cross-seed/apply":
+		return models.APIKeyScopeCrossSeedApply
+	case backupsPath.MatchString(path):
+		return models.APIKeyScopeBackups
+	case isReadOnlyMethod(r.Method) && hasAnyPrefix(path, readScopePrefixes):
+		return models.APIKeyScopeRead
+	case !isReadOnlyMethod(r.Method) && torrentsWritePath.MatchString(path):
+		return models.APIKeyScopeTorrentsWrite
+	default:
+		return models.APIKeyScopeAdmin
+	}
+}
+
+// apiPath strips the base URL and /api/ prefix from a request path.
+func apiPath(path string) string {
+	if idx := strings.Index(path, "/api/"); idx >= 0 {
+		path = path[idx+len("/api/"):]
+	}
+	return strings.TrimPrefix(path, "/")
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestRequiredAPIKeyScope(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   models.APIKeyScope
+	}{
+		{http.MethodPost, "/api/cross-seed/apply", models.APIKeyScopeCrossSeedApply},
+		{http.MethodPost, "/qui/api/cross-seed/webhook/check", models.APIKeyScopeCrossSeedApply},
+		{http.MethodGet, "/api/instances", models.APIKeyScopeRead},
+		{http.MethodGet, "/api/instances/1/torrents", models.APIKeyScopeRead},
+		{http.MethodPost, "/api/instances/1/torrents/bulk-action", models.APIKeyScopeTorrentsWrite},
+		{http.MethodDelete, "/api/instances/1/tags", models.APIKeyScopeTorrentsWrite},
+		{http.MethodGet, "/api/instances/1/backups/runs", models.APIKeyScopeBackups},
+		{http.MethodPost, "/api/instances/1/backups/run", models.APIKeyScopeBackups},
+		{http.MethodPost, "/api/instances", models.APIKeyScopeAdmin},
+		{http.MethodDelete, "/api/instances/1", models.APIKeyScopeAdmin},
+		{http.MethodGet, "/api/api-keys", models.APIKeyScopeAdmin},
+		{http.MethodGet, "/api/users", models.APIKeyScopeAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			assert.Equal(t, tt.want, requiredAPIKeyScope(req))
+		})
+	}
+}
+
+func TestIsAuthenticated_ScopedAPIKey(t *testing.T) {
+	ctx := t.Context()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	authService := auth.NewService(db)
+	sessionManager := scs.New()
+
+	readKey, _, err := authService.CreateScopedAPIKey(ctx, "reader", models.APIKeyOptions{
+		Scopes:     []models.APIKeyScope{models.APIKeyScopeRead},
+		AllowedIPs: []string{"10.0.0.0/8"},
+	})
+	require.NoError(t, err)
+
+	var principal *models.User
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = auth.UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := sessionManager.LoadAndSave(IsAuthenticated(authService, sessionManager)(okHandler))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		remoteAddr string
+		want       int
+	}{
+		{"read allowed", http.MethodGet, "/api/instances/1/torrents", "10.1.2.3:5000", http.StatusOK},
+		{"write needs scope", http.MethodPost, "/api/instances/1/torrents/bulk-action", "10.1.2.3:5000", http.StatusForbidden},
+		{"admin needs scope", http.MethodGet, "/api/api-keys", "10.1.2.3:5000", http.StatusForbidden},
+		{"address not allowed", http.MethodGet, "/api/instances/1/torrents", "192.168.1.2:5000", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal = nil
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-API-Key", readKey)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusOK {
+				require.NotNil(t, principal)
+				assert.Equal(t, models.RoleReadOnly, principal.Role)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 
 	"github.com/autobrr/qui/internal/auth"
 	"github.com/autobrr/qui/internal/domain"
+	"github.com/autobrr/qui/internal/models"
 )
 
 // IsAuthenticated middleware checks if the user is authenticated
@@ -29,17 +31,26 @@ func IsAuthenticated(authService *auth.Service, sessionManager *scs.SessionManag
 				}
 			}
 			if apiKey != "" {
-				// Validate API key
-				apiKeyModel, err := authService.ValidateAPIKey(r.Context(), apiKey)
+				// Validate API key, including its expiry and address allowlist
+				apiKeyModel, err := authService.ValidateAPIKey(r.Context(), apiKey, auth.ClientIP(r))
 				if err != nil {
-					log.Warn().Err(err).Msg("Invalid API key")
+					log.Warn().Err(err).Str("ip", auth.ClientIP(r)).Msg("Invalid API key")
 					http.Error(w, "Unauthorized", http.StatusUnauthorized)
 					return
 				}
 
+				// Check the key's scopes cover this endpoint
+				scope := requiredAPIKeyScope(r)
+				if !apiKeyModel.HasScope(scope) {
+					log.Warn().Int("apiKeyID", apiKeyModel.ID).Str("name", apiKeyModel.Name).Str("scope", string(scope)).Str("path", r.URL.Path).Msg("API key missing required scope")
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+
 				// Set API key info in context (optional, for logging)
 				log.Debug().Int("apiKeyID", apiKeyModel.ID).Str("name", apiKeyModel.Name).Msg("API key authenticated")
-				next.ServeHTTP(w, r)
+				ctx := auth.WithUser(r.Context(), auth.APIKeyUser(apiKeyModel))
+				next.ServeHTTP(w, r.WithContext(auth.WithAPIKey(ctx, apiKeyModel)))
 				return
 			}
 
@@ -49,9 +60,33 @@ func IsAuthenticated(authService *auth.Service, sessionManager *scs.SessionManag
 				return
 			}
 
-			username := sessionManager.GetString(r.Context(), "username")
-			ctx := context.WithValue(r.Context(), "username", username)
-			r = r.WithContext(ctx)
+			// Sessions created before multi-user support (OIDC) carry no user ID;
+			// those users need to log in again to be matched to an account.
+			userID := sessionManager.GetInt(r.Context(), "user_id")
+			if userID == 0 {
+				http.Error(w, "Unauthorized", http.StatusForbidden)
+				return
+			}
+
+			// Load the user on every request so role and permission changes apply immediately
+			user, err := authService.GetUser(r.Context(), userID)
+			if err != nil {
+				if errors.Is(err, models.ErrUserNotFound) {
+					if err := sessionManager.Destroy(r.Context()); err != nil {
+						log.Error().Err(err).Msg("Failed to destroy session of deleted user")
+					}
+					http.Error(w, "Unauthorized", http.StatusForbidden)
+					return
+				}
+				log.Error().Err(err).Int("userID", userID).Msg("Failed to load session user")
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			auth.TouchSession(sessionManager, r)
+
+			ctx := context.WithValue(r.Context(), "username", user.Username)
+			r = r.WithContext(auth.WithUser(ctx, user))
 
 			next.ServeHTTP(w, r)
 		})
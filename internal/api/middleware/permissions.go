@@ -0,0 +1,73 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/models"
+)
+
+// RequireRole rejects requests from users whose role is below min. API keys
+// are checked against their scopes by IsAuthenticated instead.
+// Must run after IsAuthenticated.
+func RequireRole(min models.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := auth.APIKeyFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, ok := auth.UserFromContext(r.Context())
+			if !ok || !user.Role.AtLeast(min) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireWriteRole rejects state-changing requests (anything but GET, HEAD and
+// OPTIONS) from users whose role is below min. Must run after IsAuthenticated.
+func RequireWriteRole(min models.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isReadOnlyMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			RequireRole(min)(next).ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireInstanceAccess rejects requests for instances the user hasn't been
+// granted, reading the instance ID from the instanceID URL parameter.
+// Must run after IsAuthenticated.
+func RequireInstanceAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+		if err != nil {
+			// Let the handler report the malformed ID
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !auth.CanAccessInstance(r.Context(), instanceID) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
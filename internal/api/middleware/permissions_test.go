@@ -0,0 +1,155 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/auth"
+	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestRoleAndInstancePermissions(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	router := chi.NewRouter()
+	router.With(RequireRole(models.RoleAdmin)).Get("/settings", okHandler)
+	router.Route("/instances/{instanceID}", func(r chi.Router) {
+		r.Use(RequireInstanceAccess)
+		r.Use(RequireWriteRole(models.RoleOperator))
+		r.Get("/torrents", okHandler)
+		r.Post("/torrents", okHandler)
+	})
+
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	operator := &models.User{ID: 2, Role: models.RoleOperator, InstanceIDs: []int{1}}
+	viewer := &models.User{ID: 3, Role: models.RoleReadOnly, AllInstances: true}
+
+	tests := []struct {
+		name   string
+		user   *models.User
+		method string
+		path   string
+		want   int
+	}{
+		{"admin settings", admin, http.MethodGet, "/settings", http.StatusOK},
+		{"operator settings", operator, http.MethodGet, "/settings", http.StatusForbidden},
+		{"anonymous settings", nil, http.MethodGet, "/settings", http.StatusForbidden},
+		{"operator granted instance write", operator, http.MethodPost, "/instances/1/torrents", http.StatusOK},
+		{"operator other instance", operator, http.MethodGet, "/instances/2/torrents", http.StatusForbidden},
+		{"read-only view", viewer, http.MethodGet, "/instances/2/torrents", http.StatusOK},
+		{"read-only write", viewer, http.MethodPost, "/instances/2/torrents", http.StatusForbidden},
+		{"admin any instance", admin, http.MethodPost, "/instances/9/torrents", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.user != nil {
+				req = req.WithContext(auth.WithUser(req.Context(), tt.user))
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestIsAuthenticated_LoadsSessionUser(t *testing.T) {
+	ctx := t.Context()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	authService := auth.NewService(db)
+	user, err := authService.CreateUser(ctx, &models.User{Username: "viewer", Role: models.RoleReadOnly}, "password123")
+	require.NoError(t, err)
+
+	sessionManager := scs.New()
+	var sessionUserID int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		sessionManager.Put(r.Context(), "authenticated", true)
+		sessionManager.Put(r.Context(), "user_id", sessionUserID)
+	})
+	mux.Handle("/me", IsAuthenticated(authService, sessionManager)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current, ok := auth.UserFromContext(r.Context())
+		require.True(t, ok)
+		w.Write([]byte(current.Role))
+	})))
+	handler := sessionManager.LoadAndSave(mux)
+
+	request := func(userID int) *httptest.ResponseRecorder {
+		sessionUserID = userID
+		login := httptest.NewRecorder()
+		handler.ServeHTTP(login, httptest.NewRequest(http.MethodPost, "/login", nil))
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		for _, cookie := range login.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request(user.ID)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, string(models.RoleReadOnly), rec.Body.String())
+
+	assert.Equal(t, http.StatusForbidden, request(0).Code, "sessions without a user ID must log in again")
+	assert.Equal(t, http.StatusForbidden, request(user.ID+100).Code, "deleted users lose their session")
+}
+
+func TestIsAuthenticated_RestrictedAdminAPIKey(t *testing.T) {
+	ctx := t.Context()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	authService := auth.NewService(db)
+	rawKey, _, err := authService.CreateScopedAPIKey(ctx, "restricted", models.APIKeyOptions{
+		Scopes:      []models.APIKeyScope{models.APIKeyScopeAdmin},
+		InstanceIDs: []int{1},
+	})
+	require.NoError(t, err)
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router := chi.NewRouter()
+	router.Use(IsAuthenticated(authService, scs.New()))
+	router.Route("/api/instances/{instanceID}", func(r chi.Router) {
+		r.Use(RequireInstanceAccess)
+		r.Get("/torrents", okHandler)
+	})
+
+	request := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", rawKey)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("/api/instances/1/torrents"))
+	assert.Equal(t, http.StatusForbidden, request("/api/instances/2/torrents"), "admin scope must not lift the instance restriction")
+}
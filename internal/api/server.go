@@ -29,13 +29,21 @@ import (
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/internal/services/arr"
 	"github.com/autobrr/qui/internal/services/automations"
+	"github.com/autobrr/qui/internal/services/bulkjobs"
+	"github.com/autobrr/qui/internal/services/categorysync"
 	"github.com/autobrr/qui/internal/services/crossseed"
+	"github.com/autobrr/qui/internal/services/duplicates"
 	"github.com/autobrr/qui/internal/services/filesmanager"
+	"github.com/autobrr/qui/internal/services/hnr"
+	"github.com/autobrr/qui/internal/services/integrity"
 	"github.com/autobrr/qui/internal/services/jackett"
 	"github.com/autobrr/qui/internal/services/license"
+	"github.com/autobrr/qui/internal/services/notifications"
 	"github.com/autobrr/qui/internal/services/orphanscan"
 	"github.com/autobrr/qui/internal/services/reannounce"
 	"github.com/autobrr/qui/internal/services/trackericons"
+	"github.com/autobrr/qui/internal/services/trackerrotation"
+	"github.com/autobrr/qui/internal/services/watchfolder"
 	"github.com/autobrr/qui/internal/update"
 	"github.com/autobrr/qui/internal/web"
 	"github.com/autobrr/qui/internal/web/swagger"
@@ -75,8 +83,22 @@ type Server struct {
 	instanceCrossSeedCompletionStore *models.InstanceCrossSeedCompletionStore
 	orphanScanStore                  *models.OrphanScanStore
 	orphanScanService                *orphanscan.Service
+	integrityStore                   *models.IntegrityStore
+	integrityService                 *integrity.Service
+	watchFolderStore                 *models.WatchFolderStore
+	watchFolderService               *watchfolder.Service
 	arrInstanceStore                 *models.ArrInstanceStore
 	arrService                       *arr.Service
+	notificationProviderStore        *models.NotificationProviderStore
+	notificationService              *notifications.Service
+	statsStore                       *models.StatsStore
+	hnrProfileStore                  *models.HnRProfileStore
+	hnrService                       *hnr.Service
+	trackerRotationService           *trackerrotation.Service
+	bulkJobsService                  *bulkjobs.Service
+	categoryTemplateStore            *models.CategoryTemplateStore
+	categorySyncService              *categorysync.Service
+	duplicatesService                *duplicates.Service
 }
 
 type Dependencies struct {
@@ -110,8 +132,22 @@ type Dependencies struct {
 	InstanceCrossSeedCompletionStore *models.InstanceCrossSeedCompletionStore
 	OrphanScanStore                  *models.OrphanScanStore
 	OrphanScanService                *orphanscan.Service
+	IntegrityStore                   *models.IntegrityStore
+	IntegrityService                 *integrity.Service
+	WatchFolderStore                 *models.WatchFolderStore
+	WatchFolderService               *watchfolder.Service
 	ArrInstanceStore                 *models.ArrInstanceStore
 	ArrService                       *arr.Service
+	NotificationProviderStore        *models.NotificationProviderStore
+	NotificationService              *notifications.Service
+	StatsStore                       *models.StatsStore
+	HnRProfileStore                  *models.HnRProfileStore
+	HnRService                       *hnr.Service
+	TrackerRotationService           *trackerrotation.Service
+	BulkJobsService                  *bulkjobs.Service
+	CategoryTemplateStore            *models.CategoryTemplateStore
+	CategorySyncService              *categorysync.Service
+	DuplicatesService                *duplicates.Service
 }
 
 func NewServer(deps *Dependencies) *Server {
@@ -152,8 +188,22 @@ func NewServer(deps *Dependencies) *Server {
 		instanceCrossSeedCompletionStore: deps.InstanceCrossSeedCompletionStore,
 		orphanScanStore:                  deps.OrphanScanStore,
 		orphanScanService:                deps.OrphanScanService,
+		integrityStore:                   deps.IntegrityStore,
+		integrityService:                 deps.IntegrityService,
+		watchFolderStore:                 deps.WatchFolderStore,
+		watchFolderService:               deps.WatchFolderService,
 		arrInstanceStore:                 deps.ArrInstanceStore,
 		arrService:                       deps.ArrService,
+		notificationProviderStore:        deps.NotificationProviderStore,
+		notificationService:              deps.NotificationService,
+		statsStore:                       deps.StatsStore,
+		hnrProfileStore:                  deps.HnRProfileStore,
+		hnrService:                       deps.HnRService,
+		trackerRotationService:           deps.TrackerRotationService,
+		bulkJobsService:                  deps.BulkJobsService,
+		categoryTemplateStore:            deps.CategoryTemplateStore,
+		categorySyncService:              deps.CategorySyncService,
+		duplicatesService:                deps.DuplicatesService,
 	}
 
 	return &s
@@ -283,6 +333,14 @@ func (s *Server) Handler() (*chi.Mux, error) {
 	clientAPIKeysHandler := handlers.NewClientAPIKeysHandler(s.clientAPIKeyStore, s.instanceStore, s.config.Config.BaseURL)
 	externalProgramsHandler := handlers.NewExternalProgramsHandler(s.externalProgramStore, s.clientPool, s.config.Config)
 	arrHandler := handlers.NewArrHandler(s.arrInstanceStore, s.arrService)
+	notificationsHandler := handlers.NewNotificationsHandler(s.notificationProviderStore, s.instanceStore, s.notificationService)
+	statsHandler := handlers.NewStatsHandler(s.statsStore)
+	hnrHandler := handlers.NewHnRHandler(s.hnrProfileStore, s.hnrService)
+	trackerRotationHandler := handlers.NewTrackerRotationHandler(s.trackerRotationService)
+	bulkJobsHandler := handlers.NewBulkJobsHandler(s.bulkJobsService, s.syncManager)
+	categorySyncHandler := handlers.NewCategorySyncHandler(s.categoryTemplateStore, s.instanceStore, s.categorySyncService)
+	duplicatesHandler := handlers.NewDuplicatesHandler(s.duplicatesService)
+	usersHandler := handlers.NewUsersHandler(s.authService, s.instanceStore, s.sessionManager)
 	versionHandler := handlers.NewVersionHandler(s.updateService)
 	qbittorrentInfoHandler := handlers.NewQBittorrentInfoHandler(s.clientPool)
 	backupsHandler := handlers.NewBackupsHandler(s.backupService)
@@ -292,6 +350,8 @@ func (s *Server) Handler() (*chi.Mux, error) {
 	crossSeedHandler := handlers.NewCrossSeedHandler(s.crossSeedService, s.instanceCrossSeedCompletionStore, s.instanceStore)
 	automationsHandler := handlers.NewAutomationHandler(s.automationStore, s.automationActivityStore, s.instanceStore, s.automationService)
 	orphanScanHandler := handlers.NewOrphanScanHandler(s.orphanScanStore, s.instanceStore, s.orphanScanService)
+	integrityHandler := handlers.NewIntegrityHandler(s.integrityStore, s.instanceStore, s.integrityService)
+	watchFolderHandler := handlers.NewWatchFolderHandler(s.watchFolderStore, s.instanceStore, s.watchFolderService)
 	trackerCustomizationHandler := handlers.NewTrackerCustomizationHandler(s.trackerCustomizationStore)
 	dashboardSettingsHandler := handlers.NewDashboardSettingsHandler(s.dashboardSettingsStore)
 	logExclusionsHandler := handlers.NewLogExclusionsHandler(s.logExclusionsStore)
@@ -319,6 +379,7 @@ func (s *Server) Handler() (*chi.Mux, error) {
 
 			r.Post("/setup", authHandler.Setup)
 			r.Post("/login", authHandler.Login)
+			r.Post("/login/2fa", authHandler.LoginTwoFactor)
 			r.Get("/check-setup", authHandler.CheckSetupRequired)
 			r.Get("/validate", authHandler.Validate)
 
@@ -338,92 +399,196 @@ func (s *Server) Handler() (*chi.Mux, error) {
 			r.Post("/auth/logout", authHandler.Logout)
 			r.Get("/auth/me", authHandler.GetCurrentUser)
 			r.Put("/auth/change-password", authHandler.ChangePassword)
+			r.Get("/auth/sessions", authHandler.ListSessions)
+			r.Delete("/auth/sessions", authHandler.RevokeAllSessions)
+			r.Delete("/auth/sessions/{id}", authHandler.RevokeSession)
+			r.Route("/auth/2fa", func(r chi.Router) {
+				r.Get("/", authHandler.GetTwoFactorStatus)
+				r.Post("/setup", authHandler.SetupTwoFactor)
+				r.Post("/enable", authHandler.EnableTwoFactor)
+				r.Post("/disable", authHandler.DisableTwoFactor)
+				r.Post("/recovery-codes", authHandler.RegenerateRecoveryCodes)
+			})
 
-			r.Route("/license", licenseHandler.Routes)
+			// Dashboard settings (per-user layout preferences)
+			r.Get("/dashboard-settings", dashboardSettingsHandler.Get)
+			r.Put("/dashboard-settings", dashboardSettingsHandler.Update)
 
-			// Cross-seed routes
-			crossSeedHandler.Routes(r)
+			// Version endpoint for update checks
+			r.Get("/version/latest", versionHandler.GetLatestVersion)
 
-			// Jackett routes (if configured)
-			if jackettHandler != nil {
-				jackettHandler.Routes(r)
-			}
+			// Shared, read-mostly data; changes are admin only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireWriteRole(models.RoleAdmin))
 
-			// API key management
-			r.Route("/api-keys", func(r chi.Router) {
-				r.Get("/", authHandler.ListAPIKeys)
-				r.Post("/", authHandler.CreateAPIKey)
-				r.Delete("/{id}", authHandler.DeleteAPIKey)
-			})
+				// Licenses unlock premium themes for everyone
+				r.Route("/license", licenseHandler.Routes)
 
-			// Client API key management
-			r.Route("/client-api-keys", func(r chi.Router) {
-				r.Get("/", clientAPIKeysHandler.ListClientAPIKeys)
-				r.Post("/", clientAPIKeysHandler.CreateClientAPIKey)
-				r.Delete("/{id}", clientAPIKeysHandler.DeleteClientAPIKey)
-			})
+				// Historical transfer statistics
+				r.Route("/stats", func(r chi.Router) {
+					r.Get("/trackers", statsHandler.TrackerHistory)
+					r.Get("/trackers/totals", statsHandler.TrackerTotals)
+				})
 
-			// External programs management
-			r.Route("/external-programs", func(r chi.Router) {
-				r.Get("/", externalProgramsHandler.ListExternalPrograms)
-				r.Post("/", externalProgramsHandler.CreateExternalProgram)
-				r.Put("/{id}", externalProgramsHandler.UpdateExternalProgram)
-				r.Delete("/{id}", externalProgramsHandler.DeleteExternalProgram)
-				r.Post("/execute", externalProgramsHandler.ExecuteExternalProgram)
-			})
+				// Tracker customizations (nicknames and merged domains)
+				r.Route("/tracker-customizations", func(r chi.Router) {
+					r.Get("/", trackerCustomizationHandler.List)
+					r.Post("/", trackerCustomizationHandler.Create)
+					r.Put("/{id}", trackerCustomizationHandler.Update)
+					r.Delete("/{id}", trackerCustomizationHandler.Delete)
+				})
 
-			// ARR (Sonarr/Radarr) instance management
-			r.Route("/arr", func(r chi.Router) {
-				r.Get("/instances", arrHandler.ListInstances)
-				r.Post("/instances", arrHandler.CreateInstance)
-				r.Get("/instances/{id}", arrHandler.GetInstance)
-				r.Put("/instances/{id}", arrHandler.UpdateInstance)
-				r.Delete("/instances/{id}", arrHandler.DeleteInstance)
-				r.Post("/instances/{id}/test", arrHandler.TestInstance)
-				r.Post("/test", arrHandler.TestConnection)
-				r.Post("/resolve", arrHandler.Resolve)
-			})
+				// Hit-and-run requirement profiles and compliance
+				r.Route("/hnr", func(r chi.Router) {
+					r.Get("/profiles", hnrHandler.ListProfiles)
+					r.Post("/profiles", hnrHandler.CreateProfile)
+					r.Put("/profiles/{id}", hnrHandler.UpdateProfile)
+					r.Delete("/profiles/{id}", hnrHandler.DeleteProfile)
+					r.Get("/at-risk", hnrHandler.AtRisk)
+				})
 
-			// Tracker customizations (nicknames and merged domains)
-			r.Route("/tracker-customizations", func(r chi.Router) {
-				r.Get("/", trackerCustomizationHandler.List)
-				r.Post("/", trackerCustomizationHandler.Create)
-				r.Put("/{id}", trackerCustomizationHandler.Update)
-				r.Delete("/{id}", trackerCustomizationHandler.Delete)
+				// Global torrent operations (cross-instance, limited to granted instances)
+				r.Route("/torrents", func(r chi.Router) {
+					r.Get("/cross-instance", torrentsHandler.ListCrossInstanceTorrents)
+				})
 			})
 
-			// Dashboard settings (per-user layout preferences)
-			r.Get("/dashboard-settings", dashboardSettingsHandler.Get)
-			r.Put("/dashboard-settings", dashboardSettingsHandler.Update)
+			// Application settings and integrations are admin only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(models.RoleAdmin))
 
-			// Log exclusions (muted log message patterns)
-			r.Get("/log-exclusions", logExclusionsHandler.Get)
-			r.Put("/log-exclusions", logExclusionsHandler.Update)
+				// Cross-seed routes
+				crossSeedHandler.Routes(r)
 
-			// Log settings and streaming
-			logsHandler.Routes(r)
+				// Jackett routes (if configured)
+				if jackettHandler != nil {
+					jackettHandler.Routes(r)
+				}
 
-			// Version endpoint for update checks
-			r.Get("/version/latest", versionHandler.GetLatestVersion)
+				// User accounts, roles and instance permissions
+				r.Route("/users", func(r chi.Router) {
+					r.Get("/", usersHandler.List)
+					r.Post("/", usersHandler.Create)
+					r.Put("/{id}", usersHandler.Update)
+					r.Delete("/{id}", usersHandler.Delete)
+				})
+
+				// API key management
+				r.Route("/api-keys", func(r chi.Router) {
+					r.Get("/", authHandler.ListAPIKeys)
+					r.Post("/", authHandler.CreateAPIKey)
+					r.Delete("/{id}", authHandler.DeleteAPIKey)
+				})
+
+				// Client API key management
+				r.Route("/client-api-keys", func(r chi.Router) {
+					r.Get("/", clientAPIKeysHandler.ListClientAPIKeys)
+					r.Post("/", clientAPIKeysHandler.CreateClientAPIKey)
+					r.Delete("/{id}", clientAPIKeysHandler.DeleteClientAPIKey)
+				})
+
+				// External programs management
+				r.Route("/external-programs", func(r chi.Router) {
+					r.Get("/", externalProgramsHandler.ListExternalPrograms)
+					r.Post("/", externalProgramsHandler.CreateExternalProgram)
+					r.Put("/{id}", externalProgramsHandler.UpdateExternalProgram)
+					r.Delete("/{id}", externalProgramsHandler.DeleteExternalProgram)
+					r.Post("/execute", externalProgramsHandler.ExecuteExternalProgram)
+				})
+
+				// ARR (Sonarr/Radarr) instance management
+				r.Route("/arr", func(r chi.Router) {
+					r.Get("/instances", arrHandler.ListInstances)
+					r.Post("/instances", arrHandler.CreateInstance)
+					r.Get("/instances/{id}", arrHandler.GetInstance)
+					r.Put("/instances/{id}", arrHandler.UpdateInstance)
+					r.Delete("/instances/{id}", arrHandler.DeleteInstance)
+					r.Post("/instances/{id}/test", arrHandler.TestInstance)
+					r.Post("/test", arrHandler.TestConnection)
+					r.Post("/resolve", arrHandler.Resolve)
+				})
+
+				// Notification providers and event subscriptions
+				r.Route("/notifications", func(r chi.Router) {
+					r.Get("/events", notificationsHandler.ListEvents)
+					r.Get("/providers", notificationsHandler.ListProviders)
+					r.Post("/providers", notificationsHandler.CreateProvider)
+					r.Put("/providers/{id}", notificationsHandler.UpdateProvider)
+					r.Delete("/providers/{id}", notificationsHandler.DeleteProvider)
+					r.Post("/providers/{id}/test", notificationsHandler.TestProvider)
+				})
+
+				// Tracker URL/passkey rotation across all instances and backups
+				r.Route("/tracker-rotation", func(r chi.Router) {
+					r.Post("/preview", trackerRotationHandler.Preview)
+					r.Get("/jobs", trackerRotationHandler.ListJobs)
+					r.Post("/jobs", trackerRotationHandler.StartJob)
+					r.Get("/jobs/{jobID}", trackerRotationHandler.GetJob)
+				})
+
+				// Category and tag templates pushed to selected instances
+				r.Route("/category-sync", func(r chi.Router) {
+					r.Get("/templates", categorySyncHandler.GetTemplates)
+					r.Put("/templates", categorySyncHandler.UpdateTemplates)
+					r.Get("/targets", categorySyncHandler.GetTargets)
+					r.Put("/targets", categorySyncHandler.UpdateTargets)
+					r.Get("/drift", categorySyncHandler.GetDrift)
+					r.Post("/reconcile", categorySyncHandler.Reconcile)
+				})
+
+				// Fleet-wide duplicate and redundant-content report
+				r.Route("/duplicates", func(r chi.Router) {
+					r.Get("/", duplicatesHandler.GetReport)
+					r.Post("/delete", duplicatesHandler.Delete)
+					r.Post("/hardlink", duplicatesHandler.Hardlink)
+				})
+
+				// Log exclusions (muted log message patterns)
+				r.Get("/log-exclusions", logExclusionsHandler.Get)
+				r.Put("/log-exclusions", logExclusionsHandler.Update)
+
+				// Log settings and streaming
+				logsHandler.Routes(r)
+			})
 
 			// Instance management
 			r.Route("/instances", func(r chi.Router) {
+				// Lists only the instances the user was granted
 				r.Get("/", instancesHandler.ListInstances)
-				r.Post("/", instancesHandler.CreateInstance)
-				r.Put("/order", instancesHandler.UpdateInstanceOrder)
+				r.With(middleware.RequireRole(models.RoleAdmin)).Post("/", instancesHandler.CreateInstance)
+				r.With(middleware.RequireRole(models.RoleAdmin)).Put("/order", instancesHandler.UpdateInstanceOrder)
 
 				r.Route("/{instanceID}", func(r chi.Router) {
-					r.Put("/status", instancesHandler.UpdateInstanceStatus)
-					r.Put("/", instancesHandler.UpdateInstance)
-					r.Delete("/", instancesHandler.DeleteInstance)
-					r.Post("/test", instancesHandler.TestConnection)
+					// Users only reach instances they were granted; read-only users can't change anything
+					r.Use(middleware.RequireInstanceAccess)
+					r.Use(middleware.RequireWriteRole(models.RoleOperator))
+
+					// Instance configuration is admin only
+					r.Group(func(r chi.Router) {
+						r.Use(middleware.RequireRole(models.RoleAdmin))
+						r.Put("/status", instancesHandler.UpdateInstanceStatus)
+						r.Put("/", instancesHandler.UpdateInstance)
+						r.Delete("/", instancesHandler.DeleteInstance)
+						r.Post("/test", instancesHandler.TestConnection)
+						r.Patch("/preferences", preferencesHandler.UpdatePreferences)
+					})
 
 					// Torrent operations
 					r.Route("/torrents", func(r chi.Router) {
 						r.Get("/", torrentsHandler.ListTorrents)
+						r.Get("/stream", torrentsHandler.StreamTorrents)
 						r.Post("/", torrentsHandler.AddTorrent)
 						r.Post("/check-duplicates", torrentsHandler.CheckDuplicates)
 						r.Post("/bulk-action", torrentsHandler.BulkAction)
+						r.Route("/bulk-jobs", func(r chi.Router) {
+							r.Get("/", bulkJobsHandler.ListJobs)
+							r.Post("/", bulkJobsHandler.SubmitJob)
+							r.Route("/{jobID}", func(r chi.Router) {
+								r.Get("/", bulkJobsHandler.GetJob)
+								r.Delete("/", bulkJobsHandler.CancelJob)
+								r.Post("/undo", bulkJobsHandler.UndoJob)
+							})
+						})
 						r.Post("/add-peers", torrentsHandler.AddPeers)
 						r.Post("/ban-peers", torrentsHandler.BanPeers)
 
@@ -446,7 +611,9 @@ func (s *Server) Handler() (*chi.Mux, error) {
 					})
 
 					r.Get("/capabilities", instancesHandler.GetInstanceCapabilities)
+					r.Get("/stats/history", statsHandler.InstanceHistory)
 					r.Get("/reannounce/activity", instancesHandler.GetReannounceActivity)
+					r.Get("/reannounce/stats", instancesHandler.GetReannounceStats)
 					r.Get("/reannounce/candidates", instancesHandler.GetReannounceCandidates)
 
 					// Torrent creator
@@ -490,7 +657,6 @@ func (s *Server) Handler() (*chi.Mux, error) {
 
 					// Preferences
 					r.Get("/preferences", preferencesHandler.GetPreferences)
-					r.Patch("/preferences", preferencesHandler.UpdatePreferences)
 
 					// Alternative speed limits
 					r.Get("/alternative-speed-limits", preferencesHandler.GetAlternativeSpeedLimitsMode)
@@ -503,6 +669,7 @@ func (s *Server) Handler() (*chi.Mux, error) {
 					r.Get("/getDirectoryContent", torrentsHandler.GetDirectoryContent)
 
 					r.Route("/backups", func(r chi.Router) {
+						r.Use(middleware.RequireRole(models.RoleAdmin))
 						r.Get("/settings", backupsHandler.GetSettings)
 						r.Put("/settings", backupsHandler.UpdateSettings)
 						r.Post("/import", backupsHandler.ImportManifest)
@@ -519,6 +686,7 @@ func (s *Server) Handler() (*chi.Mux, error) {
 
 					// Orphan file scanning
 					r.Route("/orphan-scan", func(r chi.Router) {
+						r.Use(middleware.RequireRole(models.RoleAdmin))
 						r.Get("/settings", orphanScanHandler.GetSettings)
 						r.Put("/settings", orphanScanHandler.UpdateSettings)
 						r.Post("/scan", orphanScanHandler.TriggerScan)
@@ -526,15 +694,34 @@ func (s *Server) Handler() (*chi.Mux, error) {
 						r.Route("/runs/{runID}", func(r chi.Router) {
 							r.Get("/", orphanScanHandler.GetRun)
 							r.Post("/confirm", orphanScanHandler.ConfirmDeletion)
+							r.Post("/restore", orphanScanHandler.RestoreRun)
+							r.Post("/files/{fileID}/restore", orphanScanHandler.RestoreFile)
 							r.Delete("/", orphanScanHandler.CancelRun)
 						})
 					})
-				})
-			})
 
-			// Global torrent operations (cross-instance)
-			r.Route("/torrents", func(r chi.Router) {
-				r.Get("/cross-instance", torrentsHandler.ListCrossInstanceTorrents)
+					// Out-of-band piece verification
+					r.Route("/integrity", func(r chi.Router) {
+						r.Use(middleware.RequireRole(models.RoleAdmin))
+						r.Get("/settings", integrityHandler.GetSettings)
+						r.Put("/settings", integrityHandler.UpdateSettings)
+						r.Get("/checks", integrityHandler.ListChecks)
+						r.Post("/checks", integrityHandler.TriggerCheck)
+						r.Route("/checks/{checkID}", func(r chi.Router) {
+							r.Get("/", integrityHandler.GetCheck)
+							r.Delete("/", integrityHandler.CancelCheck)
+						})
+					})
+
+					// Watch folders for .torrent ingestion
+					r.Route("/watch-folders", func(r chi.Router) {
+						r.Use(middleware.RequireRole(models.RoleAdmin))
+						r.Get("/", watchFolderHandler.List)
+						r.Post("/", watchFolderHandler.Create)
+						r.Put("/{folderID}", watchFolderHandler.Update)
+						r.Delete("/{folderID}", watchFolderHandler.Delete)
+					})
+				})
 			})
 
 		})
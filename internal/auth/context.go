@@ -0,0 +1,65 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type userContextKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user set by the auth middleware.
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(*models.User)
+	return user, ok && user != nil
+}
+
+// CanAccessInstance reports whether the request's user may access an instance.
+func CanAccessInstance(ctx context.Context, instanceID int) bool {
+	user, ok := UserFromContext(ctx)
+	return ok && user.CanAccessInstance(instanceID)
+}
+
+type apiKeyContextKey struct{}
+
+// WithAPIKey returns a copy of ctx carrying the API key that authenticated the request.
+func WithAPIKey(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// APIKeyFromContext returns the API key that authenticated the request, if any.
+func APIKeyFromContext(ctx context.Context) (*models.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*models.APIKey)
+	return key, ok && key != nil
+}
+
+// APIKeyUser is the principal for requests authenticated with an API key. Its
+// role mirrors the key's scopes and its instances the key's restriction. Route
+// access for API keys is decided by their scopes, not by this role.
+func APIKeyUser(key *models.APIKey) *models.User {
+	// Admins can always reach every instance, so restricted keys never map to admin
+	role := models.RoleReadOnly
+	switch {
+	case key.HasScope(models.APIKeyScopeAdmin) && len(key.InstanceIDs) == 0:
+		role = models.RoleAdmin
+	case key.HasScope(models.APIKeyScopeAdmin):
+		role = models.RoleOperator
+	case key.HasScope(models.APIKeyScopeTorrentsWrite):
+		role = models.RoleOperator
+	}
+
+	return &models.User{
+		Username:     "api-key:" + key.Name,
+		Role:         role,
+		AllInstances: len(key.InstanceIDs) == 0,
+		InstanceIDs:  key.InstanceIDs,
+	}
+}
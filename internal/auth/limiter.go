@@ -0,0 +1,140 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLockout caps the escalating lockout for repeat offenders.
+const maxLockout = 24 * time.Hour
+
+// LoginLimits configures login throttling. A zero attempt limit disables that check.
+type LoginLimits struct {
+	// MaxAttempts is the number of failed logins per username before it's locked.
+	MaxAttempts int
+	// MaxAttemptsPerIP is the number of failed logins per client address before it's locked.
+	MaxAttemptsPerIP int
+	// Lockout is the first lockout duration; it doubles with each further lockout
+	// of the same username or address. Failures older than this are forgotten.
+	Lockout time.Duration
+}
+
+type loginAttempts struct {
+	failures    int
+	lockouts    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// LoginLimiter tracks failed logins per username and per client address in
+// memory and locks them out once the configured limits are reached.
+type LoginLimiter struct {
+	limits LoginLimits
+	now    func() time.Time
+
+	mu        sync.Mutex
+	usernames map[string]*loginAttempts
+	ips       map[string]*loginAttempts
+	lastPrune time.Time
+}
+
+func NewLoginLimiter(limits LoginLimits) *LoginLimiter {
+	return &LoginLimiter{
+		limits:    limits,
+		now:       time.Now,
+		usernames: make(map[string]*loginAttempts),
+		ips:       make(map[string]*loginAttempts),
+	}
+}
+
+// Check returns how long the username or address is still locked out for, or 0.
+func (l *LoginLimiter) Check(username, ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	if a := l.usernames[normalizeLoginName(username)]; a != nil && a.lockedUntil.After(now) {
+		wait = a.lockedUntil.Sub(now)
+	}
+	if a := l.ips[ip]; a != nil && a.lockedUntil.After(now) {
+		wait = max(wait, a.lockedUntil.Sub(now))
+	}
+	return wait
+}
+
+// Failure records a failed login and returns the lockout it triggered, or 0.
+func (l *LoginLimiter) Failure(username, ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	var wait time.Duration
+	if l.limits.MaxAttempts > 0 {
+		wait = l.record(l.usernames, normalizeLoginName(username), l.limits.MaxAttempts, now)
+	}
+	if l.limits.MaxAttemptsPerIP > 0 && ip != "" {
+		wait = max(wait, l.record(l.ips, ip, l.limits.MaxAttemptsPerIP, now))
+	}
+	return wait
+}
+
+// Success forgets failed logins for the username. The address keeps its
+// count so one valid account can't be used to reset it.
+func (l *LoginLimiter) Success(username string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.usernames, normalizeLoginName(username))
+}
+
+func (l *LoginLimiter) record(attempts map[string]*loginAttempts, key string, limit int, now time.Time) time.Duration {
+	a := attempts[key]
+	if a == nil {
+		a = &loginAttempts{}
+		attempts[key] = a
+	}
+
+	if now.Sub(a.lastFailure) > l.limits.Lockout {
+		a.failures = 0
+	}
+	a.failures++
+	a.lastFailure = now
+
+	if a.failures < limit {
+		return 0
+	}
+
+	lockout := l.limits.Lockout << min(a.lockouts, 10)
+	lockout = min(lockout, maxLockout)
+	a.lockouts++
+	a.failures = 0
+	a.lockedUntil = now.Add(lockout)
+	return lockout
+}
+
+// prune drops records that have been quiet for a day, at most once a minute.
+func (l *LoginLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+
+	for _, attempts := range []map[string]*loginAttempts{l.usernames, l.ips} {
+		for key, a := range attempts {
+			if now.Sub(a.lastFailure) > maxLockout && now.After(a.lockedUntil) {
+				delete(attempts, key)
+			}
+		}
+	}
+}
+
+func normalizeLoginName(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
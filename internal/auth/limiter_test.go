@@ -0,0 +1,59 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLimiter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewLoginLimiter(LoginLimits{MaxAttempts: 3, MaxAttemptsPerIP: 5, Lockout: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	t.Run("username lockout escalates", func(t *testing.T) {
+		assert.Zero(t, limiter.Failure("Admin", "10.0.0.1"))
+		assert.Zero(t, limiter.Failure("admin ", "10.0.0.2"))
+		assert.Equal(t, time.Minute, limiter.Failure("admin", "10.0.0.3"))
+		assert.Equal(t, time.Minute, limiter.Check("ADMIN", "10.0.0.9"))
+
+		now = now.Add(time.Minute + time.Second)
+		assert.Zero(t, limiter.Check("admin", "10.0.0.9"))
+
+		limiter.Failure("admin", "10.0.0.4")
+		limiter.Failure("admin", "10.0.0.5")
+		assert.Equal(t, 2*time.Minute, limiter.Failure("admin", "10.0.0.6"))
+
+		limiter.Success("admin")
+		assert.Zero(t, limiter.Check("admin", "10.0.0.9"))
+	})
+
+	t.Run("address lockout covers every username", func(t *testing.T) {
+		for i := range 4 {
+			assert.Zero(t, limiter.Failure("user"+string(rune('a'+i)), "192.168.1.1"))
+		}
+		assert.Equal(t, time.Minute, limiter.Failure("usere", "192.168.1.1"))
+		assert.Positive(t, limiter.Check("someone-else", "192.168.1.1"))
+
+		limiter.Success("someone-else")
+		assert.Positive(t, limiter.Check("someone-else", "192.168.1.1"), "a valid login doesn't reset the address")
+	})
+
+	t.Run("old failures are forgotten", func(t *testing.T) {
+		limiter.Failure("slow", "172.16.0.1")
+		limiter.Failure("slow", "172.16.0.1")
+		now = now.Add(2 * time.Minute)
+		assert.Zero(t, limiter.Failure("slow", "172.16.0.1"))
+	})
+
+	t.Run("zero limits disable checks", func(t *testing.T) {
+		disabled := NewLoginLimiter(LoginLimits{Lockout: time.Minute})
+		for range 50 {
+			assert.Zero(t, disabled.Failure("admin", "10.0.0.1"))
+		}
+	})
+}
@@ -21,6 +21,9 @@ const (
 var (
 	ErrInvalidCredentials = errors.New("invalid username or password")
 	ErrNotSetup           = errors.New("initial setup required")
+	ErrLastAdmin          = errors.New("at least one admin account is required")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrOIDCLinkRequired   = errors.New("OIDC login must be linked from the existing account")
 )
 
 type Service struct {
@@ -87,6 +90,11 @@ func (s *Service) Login(ctx context.Context, username, password string) (*models
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	// Accounts provisioned through OIDC have no password
+	if user.PasswordHash == "" {
+		return nil, ErrInvalidCredentials
+	}
+
 	// Verify password
 	valid, err := VerifyPassword(password, user.PasswordHash)
 	if err != nil {
@@ -99,14 +107,18 @@ func (s *Service) Login(ctx context.Context, username, password string) (*models
 	return user, nil
 }
 
-// ChangePassword updates the user's password
-func (s *Service) ChangePassword(ctx context.Context, oldPassword, newPassword string) error {
+// ChangePassword updates the given user's password
+func (s *Service) ChangePassword(ctx context.Context, userID int, oldPassword, newPassword string) error {
 	// Get the current user
-	user, err := s.userStore.Get(ctx)
+	user, err := s.userStore.GetByID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
+	if user.PasswordHash == "" {
+		return ErrInvalidCredentials
+	}
+
 	// Verify old password
 	valid, err := VerifyPassword(oldPassword, user.PasswordHash)
 	if err != nil {
@@ -128,24 +140,30 @@ func (s *Service) ChangePassword(ctx context.Context, oldPassword, newPassword s
 	}
 
 	// Update password
-	if err := s.userStore.UpdatePassword(ctx, hashedPassword); err != nil {
+	if err := s.userStore.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
-	log.Info().Msg("Password changed successfully")
+	log.Info().Msgf("Password changed successfully for user '%s'", user.Username)
 	return nil
 }
 
 // API Key Management
 
-// CreateAPIKey generates a new API key
+// CreateAPIKey generates a new API key with full access
 func (s *Service) CreateAPIKey(ctx context.Context, name string) (string, *models.APIKey, error) {
 	return s.apiKeyStore.Create(ctx, name)
 }
 
-// ValidateAPIKey checks if an API key is valid
-func (s *Service) ValidateAPIKey(ctx context.Context, key string) (*models.APIKey, error) {
-	return s.apiKeyStore.ValidateAPIKey(ctx, key)
+// CreateScopedAPIKey generates a new API key limited by scopes, instances,
+// addresses and an optional expiry
+func (s *Service) CreateScopedAPIKey(ctx context.Context, name string, opts models.APIKeyOptions) (string, *models.APIKey, error) {
+	return s.apiKeyStore.CreateWithOptions(ctx, name, opts)
+}
+
+// ValidateAPIKey checks if an API key is valid for use from clientIP
+func (s *Service) ValidateAPIKey(ctx context.Context, key, clientIP string) (*models.APIKey, error) {
+	return s.apiKeyStore.ValidateAPIKey(ctx, key, clientIP)
 }
 
 // ListAPIKeys returns all API keys
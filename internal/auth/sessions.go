@@ -0,0 +1,159 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+)
+
+// Session keys describing where and when a session was used
+const (
+	sessionCreatedAt = "created_at"
+	sessionLastSeen  = "last_seen"
+	sessionIP        = "ip"
+	sessionUserAgent = "user_agent"
+)
+
+// sessionTouchInterval limits how often last-seen is written back to the store.
+const sessionTouchInterval = time.Minute
+
+// SessionInfo describes an active login session. ID is derived from the
+// session token, which is never exposed.
+type SessionInfo struct {
+	ID         string     `json:"id"`
+	AuthMethod string     `json:"authMethod,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+	IP         string     `json:"ip,omitempty"`
+	UserAgent  string     `json:"userAgent,omitempty"`
+	// Current is true for the session making the request.
+	Current bool `json:"current"`
+}
+
+// ClientIP returns the request's remote address without the port. The
+// RealIP middleware has already applied any forwarding headers.
+func ClientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// StartSession records when and from where a session was created. Call it
+// after a successful login, once the session token has been renewed.
+func StartSession(sm *scs.SessionManager, r *http.Request) {
+	now := time.Now().Unix()
+	sm.Put(r.Context(), sessionCreatedAt, now)
+	sm.Put(r.Context(), sessionLastSeen, now)
+	sm.Put(r.Context(), sessionIP, ClientIP(r))
+	sm.Put(r.Context(), sessionUserAgent, r.UserAgent())
+}
+
+// TouchSession updates a session's last-seen time and address. Writes are
+// limited to once a minute so every request doesn't save the session.
+func TouchSession(sm *scs.SessionManager, r *http.Request) {
+	now := time.Now()
+	lastSeen := sm.GetInt64(r.Context(), sessionLastSeen)
+	ip := ClientIP(r)
+	if now.Unix()-lastSeen < int64(sessionTouchInterval.Seconds()) && sm.GetString(r.Context(), sessionIP) == ip {
+		return
+	}
+	sm.Put(r.Context(), sessionLastSeen, now.Unix())
+	sm.Put(r.Context(), sessionIP, ip)
+}
+
+// SessionID returns the public identifier for a session token.
+func SessionID(token string) string {
+	if token == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:8])
+}
+
+// ListSessions returns the user's active sessions, most recently used first.
+// currentToken marks the caller's own session.
+func ListSessions(ctx context.Context, sm *scs.SessionManager, userID int, currentToken string) ([]SessionInfo, error) {
+	var sessions []SessionInfo
+	err := sm.Iterate(ctx, func(ctx context.Context) error {
+		if !sm.GetBool(ctx, "authenticated") || sm.GetInt(ctx, "user_id") != userID {
+			return nil
+		}
+
+		token := sm.Token(ctx)
+		sessions = append(sessions, SessionInfo{
+			ID:         SessionID(token),
+			AuthMethod: sm.GetString(ctx, "auth_method"),
+			CreatedAt:  unixTime(sm.GetInt64(ctx, sessionCreatedAt)),
+			LastSeenAt: unixTime(sm.GetInt64(ctx, sessionLastSeen)),
+			IP:         sm.GetString(ctx, sessionIP),
+			UserAgent:  sm.GetString(ctx, sessionUserAgent),
+			Current:    token == currentToken,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return timeValue(sessions[i].LastSeenAt).After(timeValue(sessions[j].LastSeenAt))
+	})
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions by ID. It reports whether a
+// matching session was found.
+func RevokeSession(ctx context.Context, sm *scs.SessionManager, userID int, id string) (bool, error) {
+	found := false
+	err := sm.Iterate(ctx, func(ctx context.Context) error {
+		if found || sm.GetInt(ctx, "user_id") != userID || SessionID(sm.Token(ctx)) != id {
+			return nil
+		}
+		found = true
+		return sm.Destroy(ctx)
+	})
+	return found, err
+}
+
+// RevokeUserSessions ends all of the user's sessions except keepToken, which
+// may be empty, and returns how many were ended.
+func RevokeUserSessions(ctx context.Context, sm *scs.SessionManager, userID int, keepToken string) (int, error) {
+	revoked := 0
+	err := sm.Iterate(ctx, func(ctx context.Context) error {
+		// Sessions part-way through a two-factor login belong to the user too
+		if sm.GetInt(ctx, "user_id") != userID && sm.GetInt(ctx, "pending_2fa_user_id") != userID {
+			return nil
+		}
+		if keepToken != "" && sm.Token(ctx) == keepToken {
+			return nil
+		}
+		revoked++
+		return sm.Destroy(ctx)
+	})
+	return revoked, err
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSession commits a signed-in session for userID and returns its token.
+func newTestSession(t *testing.T, sm *scs.SessionManager, userID int, ip string) string {
+	t.Helper()
+
+	ctx, err := sm.Load(context.Background(), "")
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	r.RemoteAddr = ip + ":1234"
+	r.Header.Set("User-Agent", "test-agent")
+
+	sm.Put(ctx, "authenticated", true)
+	sm.Put(ctx, "user_id", userID)
+	StartSession(sm, r)
+
+	token, _, err := sm.Commit(ctx)
+	require.NoError(t, err)
+	return token
+}
+
+func TestSessionListAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	sm := scs.New()
+	sm.Lifetime = time.Hour
+
+	current := newTestSession(t, sm, 1, "10.0.0.1")
+	other := newTestSession(t, sm, 1, "10.0.0.2")
+	third := newTestSession(t, sm, 1, "10.0.0.3")
+	otherUser := newTestSession(t, sm, 2, "10.0.0.4")
+
+	sessions, err := ListSessions(ctx, sm, 1, current)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+
+	var currentCount int
+	for _, session := range sessions {
+		assert.NotEmpty(t, session.ID)
+		assert.NotContains(t, []string{current, other, third}, session.ID, "tokens are never exposed")
+		assert.Equal(t, "test-agent", session.UserAgent)
+		assert.NotNil(t, session.CreatedAt)
+		if session.Current {
+			currentCount++
+			assert.Equal(t, "10.0.0.1", session.IP)
+		}
+	}
+	assert.Equal(t, 1, currentCount)
+
+	found, err := RevokeSession(ctx, sm, 2, SessionID(other))
+	require.NoError(t, err)
+	assert.False(t, found, "users can't revoke each other's sessions")
+
+	found, err = RevokeSession(ctx, sm, 1, SessionID(other))
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	revoked, err := RevokeUserSessions(ctx, sm, 1, current)
+	require.NoError(t, err)
+	assert.Equal(t, 1, revoked)
+
+	sessions, err = ListSessions(ctx, sm, 1, current)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Current)
+
+	_, exists, err := sm.Store.Find(otherUser)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
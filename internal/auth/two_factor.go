@@ -0,0 +1,293 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	totpIssuer        = "qui"
+	totpPeriod        = 30
+	totpSkew          = 1
+	recoveryCodeCount = 10
+	qrCodeSize        = 256
+)
+
+var (
+	ErrInvalidTwoFactorCode   = errors.New("invalid two-factor code")
+	ErrTwoFactorNotEnabled    = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyActive = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotPending    = errors.New("two-factor enrollment has not been started")
+	ErrTwoFactorUnavailable   = errors.New("two-factor authentication requires a password login")
+)
+
+// TOTPEnrollment is what an authenticator app needs to add the account.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URL is the otpauth:// provisioning URI encoded in the QR code.
+	URL string `json:"url"`
+	// QRCode is a PNG data URI of URL.
+	QRCode string `json:"qrCode"`
+}
+
+// TwoFactorStatus describes a user's two-factor setup.
+type TwoFactorStatus struct {
+	Enabled                bool `json:"enabled"`
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+}
+
+// GetTwoFactorStatus reports whether two-factor is on and how many recovery codes are left.
+func (s *Service) GetTwoFactorStatus(ctx context.Context, userID int) (*TwoFactorStatus, error) {
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Enabled: user.TwoFactorEnabled}
+	if user.TwoFactorEnabled {
+		if status.RecoveryCodesRemaining, err = s.userStore.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to count recovery codes: %w", err)
+		}
+	}
+	return status, nil
+}
+
+// BeginTOTPEnrollment generates a new secret for the user. Two-factor stays
+// off until EnableTOTP confirms a code from the authenticator app.
+func (s *Service) BeginTOTPEnrollment(ctx context.Context, userID int) (*TOTPEnrollment, error) {
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.PasswordHash == "" {
+		return nil, ErrTwoFactorUnavailable
+	}
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyActive
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: user.Username,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+
+	qrCode, err := qrCodeDataURI(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userStore.SetTOTPSecret(ctx, userID, key.Secret()); err != nil {
+		return nil, fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+
+	return &TOTPEnrollment{Secret: key.Secret(), URL: key.URL(), QRCode: qrCode}, nil
+}
+
+// EnableTOTP confirms enrollment with a code from the authenticator app and
+// returns the recovery codes. They are only shown this once.
+func (s *Service) EnableTOTP(ctx context.Context, userID int, code string) ([]string, error) {
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyActive
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotPending
+	}
+
+	if err := s.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userStore.EnableTOTP(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	log.Info().Msgf("Two-factor authentication enabled for user '%s'", user.Username)
+	return codes, nil
+}
+
+// DisableTOTP turns off two-factor after checking a current TOTP or recovery code.
+func (s *Service) DisableTOTP(ctx context.Context, userID int, code string) error {
+	user, err := s.VerifyTwoFactor(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if err := s.userStore.DisableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+
+	log.Info().Msgf("Two-factor authentication disabled for user '%s'", user.Username)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a
+// current TOTP or recovery code.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error) {
+	if _, err := s.VerifyTwoFactor(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userStore.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// VerifyTwoFactor checks the second login step. code is either a TOTP code
+// or an unused recovery code, which is consumed.
+func (s *Service) VerifyTwoFactor(ctx context.Context, userID int, code string) (*models.User, error) {
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		if err := s.verifyTOTP(ctx, user, code); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	used, err := s.userStore.UseRecoveryCode(ctx, userID, models.HashRecoveryCode(code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check recovery code: %w", err)
+	}
+	if !used {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	log.Info().Msgf("Recovery code used for user '%s'", user.Username)
+	return user, nil
+}
+
+// ResetTwoFactor turns off two-factor for a user without a code. It's the
+// escape hatch for lost authenticators and is only reachable from the CLI.
+func (s *Service) ResetTwoFactor(ctx context.Context, username string) error {
+	user, err := s.userStore.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled && user.TOTPSecret == "" {
+		return ErrTwoFactorNotEnabled
+	}
+	if err := s.userStore.DisableTOTP(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to reset two-factor authentication: %w", err)
+	}
+
+	log.Warn().Msgf("Two-factor authentication reset for user '%s'", user.Username)
+	return nil
+}
+
+// verifyTOTP accepts a code from the current period or one either side of it.
+// A code's time step can only be used once.
+func (s *Service) verifyTOTP(ctx context.Context, user *models.User, code string) error {
+	if !isTOTPCode(code) {
+		return ErrInvalidTwoFactorCode
+	}
+
+	now := time.Now()
+	current := now.Unix() / totpPeriod
+	for offset := -totpSkew; offset <= totpSkew; offset++ {
+		step := current + int64(offset)
+		expected, err := totp.GenerateCodeCustom(user.TOTPSecret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate TOTP code: %w", err)
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+
+		fresh, err := s.userStore.UseTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			return fmt.Errorf("failed to record TOTP code: %w", err)
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// generateRecoveryCodes returns codes formatted as xxxxx-xxxxx and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := strings.ToLower(encoding.EncodeToString(buf))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, models.HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func qrCodeDataURI(key *otp.Key) (string, error) {
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to render QR code: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
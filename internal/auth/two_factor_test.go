@@ -0,0 +1,118 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/database"
+)
+
+func setupTwoFactorTest(t *testing.T) (*Service, int) {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "auth.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	service := NewService(db)
+	user, err := service.SetupUser(context.Background(), "admin", "password123")
+	require.NoError(t, err)
+	return service, user.ID
+}
+
+func TestTwoFactorEnrollmentAndLogin(t *testing.T) {
+	ctx := context.Background()
+	service, userID := setupTwoFactorTest(t)
+
+	enrollment, err := service.BeginTOTPEnrollment(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enrollment.URL, "otpauth://totp/qui:admin"))
+	assert.True(t, strings.HasPrefix(enrollment.QRCode, "data:image/png;base64,"))
+
+	// Not enforced until a code is confirmed
+	user, err := service.Login(ctx, "admin", "password123")
+	require.NoError(t, err)
+	assert.False(t, user.TwoFactorEnabled)
+
+	_, err = service.EnableTOTP(ctx, userID, "000000")
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	// Use the previous period's code so the current one is still fresh below
+	previous, err := totp.GenerateCode(enrollment.Secret, time.Now().Add(-totpPeriod*time.Second))
+	require.NoError(t, err)
+	codes, err := service.EnableTOTP(ctx, userID, previous)
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+
+	user, err = service.Login(ctx, "admin", "password123")
+	require.NoError(t, err)
+	assert.True(t, user.TwoFactorEnabled)
+
+	// A code can't be used twice
+	_, err = service.VerifyTwoFactor(ctx, userID, previous)
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	current, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	_, err = service.VerifyTwoFactor(ctx, userID, current)
+	require.NoError(t, err)
+
+	// Recovery codes work once, in any case and with or without the dash
+	recovery := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	_, err = service.VerifyTwoFactor(ctx, userID, recovery)
+	require.NoError(t, err)
+	_, err = service.VerifyTwoFactor(ctx, userID, codes[0])
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	status, err := service.GetTwoFactorStatus(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, recoveryCodeCount-1, status.RecoveryCodesRemaining)
+
+	_, err = service.BeginTOTPEnrollment(ctx, userID)
+	require.ErrorIs(t, err, ErrTwoFactorAlreadyActive)
+
+	newCodes, err := service.RegenerateRecoveryCodes(ctx, userID, codes[1])
+	require.NoError(t, err)
+	_, err = service.VerifyTwoFactor(ctx, userID, codes[2])
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode, "old codes are discarded")
+
+	require.NoError(t, service.DisableTOTP(ctx, userID, newCodes[0]))
+	status, err = service.GetTwoFactorStatus(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, status.Enabled)
+	assert.Zero(t, status.RecoveryCodesRemaining)
+}
+
+func TestResetTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	service, userID := setupTwoFactorTest(t)
+
+	require.ErrorIs(t, service.ResetTwoFactor(ctx, "admin"), ErrTwoFactorNotEnabled)
+
+	enrollment, err := service.BeginTOTPEnrollment(ctx, userID)
+	require.NoError(t, err)
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	_, err = service.EnableTOTP(ctx, userID, code)
+	require.NoError(t, err)
+
+	require.NoError(t, service.ResetTwoFactor(ctx, "admin"))
+
+	user, err := service.Login(ctx, "admin", "password123")
+	require.NoError(t, err)
+	assert.False(t, user.TwoFactorEnabled)
+	assert.Empty(t, user.TOTPSecret)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// User Management
+
+// GetUser returns a user with their instance grants
+func (s *Service) GetUser(ctx context.Context, id int) (*models.User, error) {
+	return s.userStore.GetByID(ctx, id)
+}
+
+// ListUsers returns all user accounts
+func (s *Service) ListUsers(ctx context.Context) ([]*models.User, error) {
+	return s.userStore.List(ctx)
+}
+
+// CreateUser creates a local account with a password, role and instance grants
+func (s *Service) CreateUser(ctx context.Context, user *models.User, password string) (*models.User, error) {
+	if !user.Role.Valid() {
+		return nil, ErrInvalidRole
+	}
+	if len(password) < 8 {
+		return nil, errors.New("password must be at least 8 characters long")
+	}
+
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = hashedPassword
+
+	created, err := s.userStore.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Msgf("User '%s' created with role %s", created.Username, created.Role)
+	return created, nil
+}
+
+// UpdateUser changes a user's username, role and instance grants, and their
+// password when one is given. The last admin can't be demoted.
+func (s *Service) UpdateUser(ctx context.Context, user *models.User, password string) (*models.User, error) {
+	if !user.Role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	existing, err := s.userStore.GetByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Role == models.RoleAdmin && user.Role != models.RoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var hashedPassword string
+	if password != "" {
+		if len(password) < 8 {
+			return nil, errors.New("password must be at least 8 characters long")
+		}
+		if hashedPassword, err = HashPassword(password); err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+	}
+
+	updated, err := s.userStore.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if hashedPassword != "" {
+		if err := s.userStore.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+			return nil, fmt.Errorf("failed to update password: %w", err)
+		}
+	}
+
+	return updated, nil
+}
+
+// DeleteUser removes a user account. The last admin can't be deleted.
+func (s *Service) DeleteUser(ctx context.Context, id int) error {
+	existing, err := s.userStore.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.Role == models.RoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userStore.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	log.Info().Msgf("User '%s' deleted", existing.Username)
+	return nil
+}
+
+func (s *Service) ensureAnotherAdmin(ctx context.Context) error {
+	admins, err := s.userStore.CountAdmins(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// ResolveOIDCUser returns the account for an OIDC identity, provisioning it on
+// first login. A username already used by an unlinked account is refused with
+// ErrOIDCLinkRequired; the account owner has to link the identity with
+// LinkOIDCUser. When role is set it replaces the account's role, keeping OIDC
+// group changes in sync.
+func (s *Service) ResolveOIDCUser(ctx context.Context, subject, username string, role models.UserRole) (*models.User, error) {
+	if subject == "" {
+		return nil, errors.New("OIDC subject is required")
+	}
+	if role != "" && !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.userStore.GetByOIDCSubject(ctx, subject)
+	if errors.Is(err, models.ErrUserNotFound) {
+		user, err = s.userStore.GetByUsername(ctx, username)
+		switch {
+		case err == nil && user.OIDCSubject == "":
+			return nil, fmt.Errorf("username '%s' belongs to a local account: %w", username, ErrOIDCLinkRequired)
+		case err == nil:
+			return nil, fmt.Errorf("username '%s' is linked to another OIDC account: %w", username, models.ErrUserAlreadyExists)
+		case errors.Is(err, models.ErrUserNotFound):
+			admins, err := s.userStore.CountAdmins(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count admins: %w", err)
+			}
+			switch {
+			case admins == 0:
+				// The first account on an OIDC-only install must be able to manage the others.
+				role = models.RoleAdmin
+			case role == "":
+				role = models.RoleReadOnly
+			}
+			user, err = s.userStore.CreateUser(ctx, &models.User{
+				Username:     username,
+				Role:         role,
+				AllInstances: true,
+				OIDCSubject:  subject,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create OIDC user: %w", err)
+			}
+			log.Info().Msgf("OIDC user '%s' created with role %s", user.Username, user.Role)
+			return user, nil
+		default:
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if role != "" && role != user.Role {
+		if user.Role == models.RoleAdmin {
+			if err := s.ensureAnotherAdmin(ctx); err != nil {
+				log.Warn().Msgf("OIDC user '%s' is the last admin, keeping admin role instead of %s", user.Username, role)
+				return user, nil
+			}
+		}
+		if err := s.userStore.UpdateRole(ctx, user.ID, role); err != nil {
+			return nil, fmt.Errorf("failed to update OIDC user role: %w", err)
+		}
+		log.Info().Msgf("OIDC user '%s' role changed from %s to %s", user.Username, user.Role, role)
+		user.Role = role
+	}
+
+	return user, nil
+}
+
+// LinkOIDCUser ties an OIDC identity to an existing account. It is called for a
+// signed-in user completing an OIDC login, so the identity can never be claimed
+// by matching usernames alone.
+func (s *Service) LinkOIDCUser(ctx context.Context, userID int, subject string) (*models.User, error) {
+	if subject == "" {
+		return nil, errors.New("OIDC subject is required")
+	}
+
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OIDCSubject == subject {
+		return user, nil
+	}
+	if user.OIDCSubject != "" {
+		return nil, fmt.Errorf("user '%s' is linked to another OIDC account: %w", user.Username, models.ErrUserAlreadyExists)
+	}
+
+	linked, err := s.userStore.GetByOIDCSubject(ctx, subject)
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("OIDC account is linked to user '%s': %w", linked.Username, models.ErrUserAlreadyExists)
+	case !errors.Is(err, models.ErrUserNotFound):
+		return nil, err
+	}
+
+	if err := s.userStore.LinkOIDCSubject(ctx, user.ID, subject); err != nil {
+		return nil, fmt.Errorf("failed to link OIDC account: %w", err)
+	}
+	user.OIDCSubject = subject
+	user.OIDC = true
+	log.Info().Msgf("Linked OIDC login to user '%s'", user.Username)
+	return user, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/models"
+)
+
+func newUsersTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "auth.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	return NewService(db)
+}
+
+func TestResolveOIDCUserRoles(t *testing.T) {
+	ctx := context.Background()
+	service := newUsersTestService(t)
+
+	// The first account is an admin even without a mapped role
+	first, err := service.ResolveOIDCUser(ctx, "sub-1", "alice", models.RoleReadOnly)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, first.Role)
+
+	// Unmapped users get the least privileged role once an admin exists
+	second, err := service.ResolveOIDCUser(ctx, "sub-2", "bob", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleReadOnly, second.Role)
+
+	mapped, err := service.ResolveOIDCUser(ctx, "sub-3", "carol", models.RoleOperator)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleOperator, mapped.Role)
+
+	// An unmapped login keeps the role managed in qui
+	again, err := service.ResolveOIDCUser(ctx, "sub-3", "carol", "")
+	require.NoError(t, err)
+	assert.Equal(t, mapped.ID, again.ID)
+	assert.Equal(t, models.RoleOperator, again.Role)
+}
+
+func TestResolveOIDCUserRequiresExplicitLink(t *testing.T) {
+	ctx := context.Background()
+	service := newUsersTestService(t)
+	local, err := service.SetupUser(ctx, "admin", "password123")
+	require.NoError(t, err)
+
+	_, err = service.ResolveOIDCUser(ctx, "attacker", "admin", models.RoleAdmin)
+	require.ErrorIs(t, err, ErrOIDCLinkRequired)
+
+	unlinked, err := service.GetUser(ctx, local.ID)
+	require.NoError(t, err)
+	assert.False(t, unlinked.OIDC)
+
+	linked, err := service.LinkOIDCUser(ctx, local.ID, "sub-admin")
+	require.NoError(t, err)
+	assert.True(t, linked.OIDC)
+
+	user, err := service.ResolveOIDCUser(ctx, "sub-admin", "renamed-at-idp", "")
+	require.NoError(t, err)
+	assert.Equal(t, local.ID, user.ID)
+	assert.Equal(t, models.RoleAdmin, user.Role)
+
+	// Neither side of an existing link can be claimed again
+	_, err = service.LinkOIDCUser(ctx, local.ID, "sub-other")
+	require.ErrorIs(t, err, models.ErrUserAlreadyExists)
+
+	other, err := service.ResolveOIDCUser(ctx, "sub-other", "other", "")
+	require.NoError(t, err)
+	_, err = service.LinkOIDCUser(ctx, other.ID, "sub-admin")
+	require.ErrorIs(t, err, models.ErrUserAlreadyExists)
+}
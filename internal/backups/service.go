@@ -24,6 +24,7 @@ import (
 	qbt "github.com/autobrr/go-qbittorrent"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/internal/services/jackett"
@@ -31,7 +32,8 @@ import (
 )
 
 var (
-	// ErrInstanceBusy is returned when a backup is already running for the instance.
+	// ErrInstanceBusy is returned when a backup is already running for the instance,
+	// or while cached torrent blobs are being rewritten.
 	ErrInstanceBusy = errors.New("backup already running for this instance")
 )
 
@@ -69,11 +71,15 @@ type Service struct {
 
 	inflight   map[int]int64
 	inflightMu sync.Mutex
+	// rewriting is set while cached blobs are rewritten; guarded by inflightMu.
+	rewriting bool
 
 	progress   map[int64]*BackupProgress
 	progressMu sync.RWMutex
 
 	now func() time.Time
+
+	eventBus *events.Bus
 }
 
 type job struct {
@@ -428,6 +434,7 @@ func (s *Service) handleJob(ctx context.Context, j job) {
 		})
 		s.clearInstance(j.instanceID, j.runID)
 		log.Error().Int("instanceID", j.instanceID).Msg("Backup run failed: sync manager not configured")
+		s.publishFinished(j, nil, msg)
 		return
 	}
 
@@ -455,6 +462,7 @@ func (s *Service) handleJob(ctx context.Context, j job) {
 			return nil
 		})
 		log.Error().Err(execErr).Int("instanceID", j.instanceID).Int64("runID", j.runID).Msg("Backup run failed")
+		s.publishFinished(j, nil, msg)
 	} else {
 		now := s.now()
 		_ = s.store.UpdateRunMetadata(ctx, j.runID, func(run *models.BackupRun) error {
@@ -472,6 +480,8 @@ func (s *Service) handleJob(ctx context.Context, j job) {
 			return nil
 		})
 
+		s.publishFinished(j, result, "")
+
 		if len(result.items) > 0 {
 			if err := s.store.InsertItems(ctx, j.runID, result.items); err != nil {
 				log.Warn().Err(err).Int64("runID", j.runID).Msg("Failed to persist backup manifest items")
@@ -488,6 +498,28 @@ func (s *Service) handleJob(ctx context.Context, j job) {
 	s.clearInstance(j.instanceID, j.runID)
 }
 
+// SetEventBus sets the bus finished backup runs are published to.
+func (s *Service) SetEventBus(bus *events.Bus) {
+	s.eventBus = bus
+}
+
+func (s *Service) publishFinished(j job, result *backupResult, errMsg string) {
+	payload := events.BackupResult{
+		RunID:   j.runID,
+		Kind:    string(j.kind),
+		Success: result != nil,
+		Error:   errMsg,
+	}
+	if result != nil {
+		payload.TorrentCount = result.torrentCount
+	}
+	s.eventBus.Publish(events.Event{
+		Type:       events.BackupFinished,
+		InstanceID: j.instanceID,
+		Payload:    payload,
+	})
+}
+
 type backupResult struct {
 	manifestRelPath *string
 	totalBytes      int64
@@ -662,25 +694,10 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 		uniquePath := ensureUniquePath(archivePath, usedPaths)
 
 		if blobRelPath == nil && s.cacheDir != "" {
-			sum := sha256.Sum256(data)
-			hash := hex.EncodeToString(sum[:])
-			blobName := hash + ".torrent"
-			subdir := ""
-			if len(hash) >= 6 {
-				subdir = filepath.Join(hash[0:2], hash[2:4], hash[4:6])
-			}
-			absBlob := filepath.Join(s.cacheDir, subdir, blobName)
-			if _, err := os.Stat(absBlob); errors.Is(err, os.ErrNotExist) {
-				if subdir != "" {
-					if err := os.MkdirAll(filepath.Dir(absBlob), 0o755); err != nil {
-						return nil, fmt.Errorf("create torrent cache subdir: %w", err)
-					}
-				}
-				if err := os.WriteFile(absBlob, data, 0o644); err != nil && !errors.Is(err, os.ErrExist) {
-					return nil, fmt.Errorf("cache torrent blob: %w", err)
-				}
+			rel, err := s.storeTorrentBlob(data)
+			if err != nil {
+				return nil, err
 			}
-			rel := filepath.ToSlash(filepath.Join("backups", "torrents", subdir, blobName))
 			blobRelPath = &rel
 		}
 
@@ -907,7 +924,7 @@ func (s *Service) GetProgress(runID int64) *BackupProgress {
 func (s *Service) markInstance(instanceID int, runID int64) bool {
 	s.inflightMu.Lock()
 	defer s.inflightMu.Unlock()
-	if _, exists := s.inflight[instanceID]; exists {
+	if _, exists := s.inflight[instanceID]; exists || s.rewriting {
 		return false
 	}
 	s.inflight[instanceID] = runID
@@ -1619,6 +1636,30 @@ func (s *Service) DataDir() string {
 	return s.cfg.DataDir
 }
 
+// storeTorrentBlob writes data to the content-addressed torrent cache, skipping the
+// write when an identical blob exists, and returns its path relative to the data dir.
+func (s *Service) storeTorrentBlob(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	blobName := hash + ".torrent"
+	subdir := ""
+	if len(hash) >= 6 {
+		subdir = filepath.Join(hash[0:2], hash[2:4], hash[4:6])
+	}
+	absBlob := filepath.Join(s.cacheDir, subdir, blobName)
+	if _, err := os.Stat(absBlob); errors.Is(err, os.ErrNotExist) {
+		if subdir != "" {
+			if err := os.MkdirAll(filepath.Dir(absBlob), 0o755); err != nil {
+				return "", fmt.Errorf("create torrent cache subdir: %w", err)
+			}
+		}
+		if err := os.WriteFile(absBlob, data, 0o644); err != nil && !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("cache torrent blob: %w", err)
+		}
+	}
+	return filepath.ToSlash(filepath.Join("backups", "torrents", subdir, blobName)), nil
+}
+
 type cachedTorrent struct {
 	data    []byte
 	relPath string
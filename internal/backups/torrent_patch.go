@@ -66,6 +66,68 @@ func patchTorrentTrackers(data []byte, trackers []string) ([]byte, bool, error)
 	return buf.Bytes(), true, nil
 }
 
+// rewriteTorrentTrackerURLs applies rewrite to the announce URL and every announce-list
+// entry, keeping the tier layout intact. It returns the (possibly mutated) payload and
+// whether any URL changed.
+func rewriteTorrentTrackerURLs(data []byte, rewrite func(string) (string, bool)) ([]byte, bool, error) {
+	var root map[string]any
+	if err := bencode.Unmarshal(data, &root); err != nil {
+		return data, false, fmt.Errorf("decode torrent: %w", err)
+	}
+
+	changed := false
+
+	if announce, ok := rewriteTrackerValue(root["announce"], rewrite); ok {
+		root["announce"] = announce
+		changed = true
+	}
+
+	if tiers, ok := root["announce-list"].([]any); ok {
+		for i, tier := range tiers {
+			entries, ok := tier.([]any)
+			if !ok {
+				continue
+			}
+			for j, entry := range entries {
+				if rewritten, ok := rewriteTrackerValue(entry, rewrite); ok {
+					entries[j] = rewritten
+					changed = true
+				}
+			}
+			tiers[i] = entries
+		}
+	}
+
+	if !changed {
+		return data, false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := bencode.NewEncoder(&buf).Encode(root); err != nil {
+		return data, false, fmt.Errorf("encode torrent: %w", err)
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+func rewriteTrackerValue(value any, rewrite func(string) (string, bool)) (string, bool) {
+	var current string
+	switch v := value.(type) {
+	case string:
+		current = v
+	case []byte:
+		current = string(v)
+	default:
+		return "", false
+	}
+
+	rewritten, ok := rewrite(current)
+	if !ok || rewritten == current {
+		return "", false
+	}
+	return rewritten, true
+}
+
 func shouldInjectTrackerMetadata(apiVersion string) bool {
 	apiVersion = strings.TrimSpace(apiVersion)
 	if apiVersion == "" {
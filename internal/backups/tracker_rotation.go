@@ -0,0 +1,171 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrBackupsRunning is returned when cached blobs cannot be rewritten because a backup is in progress.
+var ErrBackupsRunning = errors.New("backups are currently running")
+
+// TrackerRewriteResult summarizes rewriting tracker URLs in cached torrent blobs.
+type TrackerRewriteResult struct {
+	BlobsScanned   int   `json:"blobsScanned"`
+	BlobsMatched   int   `json:"blobsMatched"`
+	BlobsRewritten int   `json:"blobsRewritten"`
+	BlobsFailed    int   `json:"blobsFailed"`
+	ItemsUpdated   int64 `json:"itemsUpdated"`
+}
+
+// RewriteTrackerURLs applies rewrite to the trackers of every cached torrent blob. Changed
+// blobs are stored under their new content hash, backup items are repointed at them and
+// the old blob is removed. With dryRun set, matching blobs are only counted. progress,
+// when non-nil, is called after each blob.
+func (s *Service) RewriteTrackerURLs(ctx context.Context, rewrite func(string) (string, bool), dryRun bool, progress func(done, total int)) (*TrackerRewriteResult, error) {
+	result := &TrackerRewriteResult{}
+	if s.cacheDir == "" {
+		return result, nil
+	}
+
+	if !dryRun {
+		if !s.startRewrite() {
+			return nil, ErrBackupsRunning
+		}
+		defer s.finishRewrite()
+	}
+
+	paths, err := s.store.ListTorrentBlobPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rel := range paths {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if progress != nil {
+			progress(i, len(paths))
+		}
+
+		data, err := s.readTorrentBlob(rel)
+		if err != nil {
+			log.Warn().Err(err).Str("blob", rel).Msg("Failed to read cached torrent blob for tracker rewrite")
+			result.BlobsFailed++
+			continue
+		}
+		if data == nil {
+			continue
+		}
+		result.BlobsScanned++
+
+		patched, changed, err := rewriteTorrentTrackerURLs(data, rewrite)
+		if err != nil {
+			log.Warn().Err(err).Str("blob", rel).Msg("Failed to rewrite cached torrent blob trackers")
+			result.BlobsFailed++
+			continue
+		}
+		if !changed {
+			continue
+		}
+		result.BlobsMatched++
+		if dryRun {
+			continue
+		}
+
+		newRel, err := s.storeTorrentBlob(patched)
+		if err != nil {
+			log.Warn().Err(err).Str("blob", rel).Msg("Failed to store rewritten torrent blob")
+			result.BlobsFailed++
+			continue
+		}
+
+		updated, err := s.store.ReplaceTorrentBlobPath(ctx, rel, newRel)
+		if err != nil {
+			log.Warn().Err(err).Str("blob", rel).Str("newBlob", newRel).Msg("Failed to repoint backup items at rewritten torrent blob")
+			result.BlobsFailed++
+			continue
+		}
+		result.BlobsRewritten++
+		result.ItemsUpdated += updated
+
+		s.removeUnreferencedBlob(ctx, rel)
+	}
+
+	if progress != nil {
+		progress(len(paths), len(paths))
+	}
+
+	return result, nil
+}
+
+// startRewrite blocks new backups until finishRewrite, so no backup reads or
+// stores blobs while they are being replaced. It fails when a backup or another
+// rewrite is already running.
+func (s *Service) startRewrite() bool {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+	if s.rewriting || len(s.inflight) > 0 {
+		return false
+	}
+	s.rewriting = true
+	return true
+}
+
+func (s *Service) finishRewrite() {
+	s.inflightMu.Lock()
+	s.rewriting = false
+	s.inflightMu.Unlock()
+}
+
+// torrentBlobPath returns the absolute path of the blob stored at rel, which older
+// installs recorded relative to the backups directory, or "" when it no longer exists.
+func (s *Service) torrentBlobPath(rel string) (string, error) {
+	candidates := []string{
+		filepath.Join(s.cfg.DataDir, rel),
+		filepath.Join(s.cfg.DataDir, "backups", rel),
+	}
+	for _, abs := range candidates {
+		_, err := os.Stat(abs)
+		if err == nil {
+			return abs, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// readTorrentBlob returns the blob stored at rel, or nil when it no longer exists.
+func (s *Service) readTorrentBlob(rel string) ([]byte, error) {
+	abs, err := s.torrentBlobPath(rel)
+	if err != nil || abs == "" {
+		return nil, err
+	}
+	return os.ReadFile(abs)
+}
+
+func (s *Service) removeUnreferencedBlob(ctx context.Context, rel string) {
+	count, err := s.store.CountBlobReferences(ctx, rel)
+	if err != nil {
+		log.Warn().Err(err).Str("blob", rel).Msg("Failed to count torrent blob references")
+		return
+	}
+	if count > 0 {
+		return
+	}
+	abs, err := s.torrentBlobPath(rel)
+	if err == nil && abs != "" {
+		err = os.Remove(abs)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).Str("blob", rel).Msg("Failed to remove replaced torrent blob")
+	}
+}
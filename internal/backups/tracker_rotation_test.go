@@ -0,0 +1,151 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func rotatePasskey(url string) (string, bool) {
+	if !strings.Contains(url, "oldkey") {
+		return "", false
+	}
+	return strings.ReplaceAll(url, "oldkey", "newkey"), true
+}
+
+func TestRewriteTorrentTrackerURLsKeepsTiersAndInfo(t *testing.T) {
+	root := decodeTorrent(t, loadTorrentFixture(t))
+	root["announce"] = "https://tracker.example/oldkey/announce"
+	root["announce-list"] = []any{
+		[]any{"https://tracker.example/oldkey/announce", "https://backup.example/oldkey/announce"},
+		[]any{"https://other.example/announce"},
+	}
+	data := encodeTorrent(t, root)
+
+	patched, changed, err := rewriteTorrentTrackerURLs(data, rotatePasskey)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	patchedRoot := decodeTorrent(t, patched)
+	require.Equal(t, "https://tracker.example/newkey/announce", bencodeString(patchedRoot["announce"]))
+	require.Equal(t, []any{
+		[]any{"https://tracker.example/newkey/announce", "https://backup.example/newkey/announce"},
+		[]any{"https://other.example/announce"},
+	}, normalizeBencode(patchedRoot["announce-list"]))
+
+	before, err := metainfo.Load(bytes.NewReader(data))
+	require.NoError(t, err)
+	after, err := metainfo.Load(bytes.NewReader(patched))
+	require.NoError(t, err)
+	require.Equal(t, before.HashInfoBytes(), after.HashInfoBytes(), "info hash must not change")
+
+	_, changed, err = rewriteTorrentTrackerURLs(patched, rotatePasskey)
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestRewriteTrackerURLsRepointsBackupItems(t *testing.T) {
+	db := setupTestBackupDB(t)
+	ctx := context.Background()
+	instanceID := insertTestInstance(t, db, "test-instance")
+
+	store := models.NewBackupStore(db)
+	svc := NewService(store, nil, nil, Config{DataDir: t.TempDir(), WorkerCount: 1})
+
+	root := decodeTorrent(t, loadTorrentFixture(t))
+	root["announce"] = "https://tracker.example/oldkey/announce"
+	delete(root, "announce-list")
+	oldRel, err := svc.storeTorrentBlob(encodeTorrent(t, root))
+	require.NoError(t, err)
+
+	run := &models.BackupRun{
+		InstanceID:  instanceID,
+		Kind:        models.BackupRunKindManual,
+		Status:      models.BackupRunStatusSuccess,
+		RequestedBy: "test",
+		RequestedAt: time.Now().UTC(),
+	}
+	require.NoError(t, store.CreateRun(ctx, run))
+	require.NoError(t, store.InsertItems(ctx, run.ID, []models.BackupItem{
+		{RunID: run.ID, TorrentHash: "hash1", Name: "torrent", TorrentBlobPath: &oldRel},
+	}))
+
+	preview, err := svc.RewriteTrackerURLs(ctx, rotatePasskey, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, preview.BlobsMatched)
+	require.Zero(t, preview.BlobsRewritten)
+	require.FileExists(t, filepath.Join(svc.DataDir(), oldRel))
+
+	result, err := svc.RewriteTrackerURLs(ctx, rotatePasskey, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.BlobsRewritten)
+	require.EqualValues(t, 1, result.ItemsUpdated)
+
+	item, err := store.GetItemByHash(ctx, run.ID, "hash1")
+	require.NoError(t, err)
+	require.NotNil(t, item.TorrentBlobPath)
+	require.NotEqual(t, oldRel, *item.TorrentBlobPath)
+
+	_, err = os.Stat(filepath.Join(svc.DataDir(), oldRel))
+	require.ErrorIs(t, err, os.ErrNotExist, "replaced blob should be removed")
+
+	data, err := os.ReadFile(filepath.Join(svc.DataDir(), *item.TorrentBlobPath))
+	require.NoError(t, err)
+	require.Equal(t, "https://tracker.example/newkey/announce", bencodeString(decodeTorrent(t, data)["announce"]))
+}
+
+func TestRewriteTrackerURLsBlocksBackupsAndRemovesLegacyBlobs(t *testing.T) {
+	db := setupTestBackupDB(t)
+	ctx := context.Background()
+	instanceID := insertTestInstance(t, db, "test-instance")
+
+	store := models.NewBackupStore(db)
+	svc := NewService(store, nil, nil, Config{DataDir: t.TempDir(), WorkerCount: 1})
+
+	root := decodeTorrent(t, loadTorrentFixture(t))
+	root["announce"] = "https://tracker.example/oldkey/announce"
+	delete(root, "announce-list")
+	storedRel, err := svc.storeTorrentBlob(encodeTorrent(t, root))
+	require.NoError(t, err)
+	// Older installs recorded blob paths relative to the backups directory
+	legacyRel := strings.TrimPrefix(storedRel, "backups/")
+
+	run := &models.BackupRun{
+		InstanceID:  instanceID,
+		Kind:        models.BackupRunKindManual,
+		Status:      models.BackupRunStatusSuccess,
+		RequestedBy: "test",
+		RequestedAt: time.Now().UTC(),
+	}
+	require.NoError(t, store.CreateRun(ctx, run))
+	require.NoError(t, store.InsertItems(ctx, run.ID, []models.BackupItem{
+		{RunID: run.ID, TorrentHash: "hash1", Name: "torrent", TorrentBlobPath: &legacyRel},
+	}))
+
+	var queueErr error
+	result, err := svc.RewriteTrackerURLs(ctx, rotatePasskey, false, func(done, total int) {
+		if done == 0 {
+			_, queueErr = svc.QueueRun(ctx, instanceID, models.BackupRunKindManual, "test")
+		}
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.BlobsRewritten)
+	require.ErrorIs(t, queueErr, ErrInstanceBusy, "backups must wait for the rewrite")
+
+	_, err = os.Stat(filepath.Join(svc.DataDir(), storedRel))
+	require.ErrorIs(t, err, os.ErrNotExist, "replaced legacy blob should be removed")
+
+	require.True(t, svc.markInstance(instanceID, 0), "backups can run once the rewrite finished")
+}
@@ -114,6 +114,14 @@ func (c *AppConfig) defaults() {
 	c.viper.SetDefault("metricsHost", "127.0.0.1")
 	c.viper.SetDefault("metricsPort", 9074)
 	c.viper.SetDefault("metricsBasicAuthUsers", "")
+	c.viper.SetDefault("statsEnabled", true)
+	c.viper.SetDefault("statsSampleInterval", 60)
+	c.viper.SetDefault("statsRawRetentionDays", 7)
+	c.viper.SetDefault("statsHourlyRetentionDays", 90)
+	c.viper.SetDefault("statsDailyRetentionDays", 0)
+	c.viper.SetDefault("loginMaxAttempts", 5)
+	c.viper.SetDefault("loginMaxAttemptsPerIp", 20)
+	c.viper.SetDefault("loginLockoutMinutes", 15)
 	c.viper.SetDefault("externalProgramAllowList", []string{})
 
 	// OIDC defaults
@@ -123,6 +131,11 @@ func (c *AppConfig) defaults() {
 	c.viper.SetDefault("oidcClientSecret", "")
 	c.viper.SetDefault("oidcRedirectUrl", "")
 	c.viper.SetDefault("oidcDisableBuiltInLogin", false)
+	c.viper.SetDefault("oidcGroupsClaim", "groups")
+	c.viper.SetDefault("oidcAdminGroups", "")
+	c.viper.SetDefault("oidcOperatorGroups", "")
+	c.viper.SetDefault("oidcReadOnlyGroups", "")
+	c.viper.SetDefault("oidcDefaultRole", "")
 }
 
 func (c *AppConfig) load(configDirOrPath string) error {
@@ -199,6 +212,14 @@ func (c *AppConfig) loadFromEnv() {
 	c.viper.BindEnv("metricsHost", envPrefix+"METRICS_HOST")
 	c.viper.BindEnv("metricsPort", envPrefix+"METRICS_PORT")
 	c.viper.BindEnv("metricsBasicAuthUsers", envPrefix+"METRICS_BASIC_AUTH_USERS")
+	c.viper.BindEnv("statsEnabled", envPrefix+"STATS_ENABLED")
+	c.viper.BindEnv("statsSampleInterval", envPrefix+"STATS_SAMPLE_INTERVAL")
+	c.viper.BindEnv("statsRawRetentionDays", envPrefix+"STATS_RAW_RETENTION_DAYS")
+	c.viper.BindEnv("statsHourlyRetentionDays", envPrefix+"STATS_HOURLY_RETENTION_DAYS")
+	c.viper.BindEnv("statsDailyRetentionDays", envPrefix+"STATS_DAILY_RETENTION_DAYS")
+	c.viper.BindEnv("loginMaxAttempts", envPrefix+"LOGIN_MAX_ATTEMPTS")
+	c.viper.BindEnv("loginMaxAttemptsPerIp", envPrefix+"LOGIN_MAX_ATTEMPTS_PER_IP")
+	c.viper.BindEnv("loginLockoutMinutes", envPrefix+"LOGIN_LOCKOUT_MINUTES")
 
 	// OIDC environment variables
 	c.viper.BindEnv("oidcEnabled", envPrefix+"OIDC_ENABLED")
@@ -207,6 +228,11 @@ func (c *AppConfig) loadFromEnv() {
 	c.bindOrReadFromFile("oidcClientSecret", envPrefix+"OIDC_CLIENT_SECRET")
 	c.viper.BindEnv("oidcRedirectUrl", envPrefix+"OIDC_REDIRECT_URL")
 	c.viper.BindEnv("oidcDisableBuiltInLogin", envPrefix+"OIDC_DISABLE_BUILT_IN_LOGIN")
+	c.viper.BindEnv("oidcGroupsClaim", envPrefix+"OIDC_GROUPS_CLAIM")
+	c.viper.BindEnv("oidcAdminGroups", envPrefix+"OIDC_ADMIN_GROUPS")
+	c.viper.BindEnv("oidcOperatorGroups", envPrefix+"OIDC_OPERATOR_GROUPS")
+	c.viper.BindEnv("oidcReadOnlyGroups", envPrefix+"OIDC_READ_ONLY_GROUPS")
+	c.viper.BindEnv("oidcDefaultRole", envPrefix+"OIDC_DEFAULT_ROLE")
 }
 
 func (c *AppConfig) watchConfig() {
@@ -260,6 +286,16 @@ func (c *AppConfig) hydrateConfigFromViper() {
 	c.Config.MetricsPort = c.viper.GetInt("metricsPort")
 	c.Config.MetricsBasicAuthUsers = c.viper.GetString("metricsBasicAuthUsers")
 
+	c.Config.StatsEnabled = c.viper.GetBool("statsEnabled")
+	c.Config.StatsSampleInterval = c.viper.GetInt("statsSampleInterval")
+	c.Config.StatsRawRetentionDays = c.viper.GetInt("statsRawRetentionDays")
+	c.Config.StatsHourlyRetentionDays = c.viper.GetInt("statsHourlyRetentionDays")
+	c.Config.StatsDailyRetentionDays = c.viper.GetInt("statsDailyRetentionDays")
+
+	c.Config.LoginMaxAttempts = c.viper.GetInt("loginMaxAttempts")
+	c.Config.LoginMaxAttemptsPerIP = c.viper.GetInt("loginMaxAttemptsPerIp")
+	c.Config.LoginLockoutMinutes = c.viper.GetInt("loginLockoutMinutes")
+
 	c.Config.ExternalProgramAllowList = c.viper.GetStringSlice("externalProgramAllowList")
 
 	c.Config.OIDCEnabled = c.viper.GetBool("oidcEnabled")
@@ -268,6 +304,11 @@ func (c *AppConfig) hydrateConfigFromViper() {
 	c.Config.OIDCClientSecret = c.viper.GetString("oidcClientSecret")
 	c.Config.OIDCRedirectURL = c.viper.GetString("oidcRedirectUrl")
 	c.Config.OIDCDisableBuiltInLogin = c.viper.GetBool("oidcDisableBuiltInLogin")
+	c.Config.OIDCGroupsClaim = c.viper.GetString("oidcGroupsClaim")
+	c.Config.OIDCAdminGroups = c.viper.GetString("oidcAdminGroups")
+	c.Config.OIDCOperatorGroups = c.viper.GetString("oidcOperatorGroups")
+	c.Config.OIDCReadOnlyGroups = c.viper.GetString("oidcReadOnlyGroups")
+	c.Config.OIDCDefaultRole = c.viper.GetString("oidcDefaultRole")
 }
 
 // RegisterReloadListener registers a callback that's invoked when the configuration file is reloaded.
@@ -390,6 +431,38 @@ logLevel = "{{ .logLevel }}"
 # Leave empty to disable authentication (default)
 #metricsBasicAuthUsers = ""
 
+# Statistics history (requires restart)
+# Samples per-instance and per-tracker transfer into the database for dashboard charts.
+# Samples are downsampled into hourly and daily rollups; retention is in days (0 keeps forever).
+# Default: true
+#statsEnabled = true
+
+# Seconds between samples
+# Default: 60
+#statsSampleInterval = 60
+
+# Default: 7
+#statsRawRetentionDays = 7
+
+# Default: 90
+#statsHourlyRetentionDays = 90
+
+# Default: 0
+#statsDailyRetentionDays = 0
+
+# Login throttling (requires restart)
+# Failed logins are counted per username and per client address. Reaching a limit
+# locks it out for loginLockoutMinutes, doubling on each repeat lockout (up to 24 hours).
+# Set a limit to 0 to disable it.
+# Default: 5
+#loginMaxAttempts = 5
+
+# Default: 20
+#loginMaxAttemptsPerIp = 20
+
+# Default: 15
+#loginLockoutMinutes = 15
+
 # External program allow list
 # Restrict which executables can be started from qui.
 # Provide absolute paths to binaries or directories. Leave commented to allow any program.
@@ -416,6 +489,17 @@ logLevel = "{{ .logLevel }}"
 
 # Disable Built-In Login Form (only works when OIDC is enabled)
 #oidcDisableBuiltInLogin = false
+
+# Map OIDC groups to qui roles (comma-separated group names).
+# Without any mapped groups new OIDC users are read_only until an admin changes their role.
+#oidcGroupsClaim = "groups"
+#oidcAdminGroups = ""
+#oidcOperatorGroups = ""
+#oidcReadOnlyGroups = ""
+
+# Role for OIDC users in none of the mapped groups: admin, operator or read_only.
+# Leave empty to refuse their login.
+#oidcDefaultRole = ""
 `
 
 	// Prepare template data
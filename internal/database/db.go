@@ -1226,6 +1226,10 @@ const referencedStringsInsertQuery = `
 	SELECT name_id AS string_id FROM arr_instances WHERE name_id IS NOT NULL
 	UNION ALL
 	SELECT base_url_id AS string_id FROM arr_instances WHERE base_url_id IS NOT NULL
+	UNION ALL
+	SELECT tracker_id AS string_id FROM tracker_stats
+	UNION ALL
+	SELECT hash_id AS string_id FROM torrent_upload_history
 `
 
 func (db *DB) CleanupUnusedStrings(ctx context.Context) (int64, error) {
@@ -160,8 +160,14 @@ var expectedSchema = map[string][]columnSpec{
 		{Name: "id", Type: "INTEGER", PrimaryKey: true},
 		{Name: "username", Type: "TEXT"},
 		{Name: "password_hash", Type: "TEXT"},
+		{Name: "role", Type: "TEXT"},
+		{Name: "all_instances", Type: "BOOLEAN"},
+		{Name: "oidc_subject", Type: "TEXT"},
 		{Name: "created_at", Type: "TIMESTAMP"},
 		{Name: "updated_at", Type: "TIMESTAMP"},
+		{Name: "totp_secret", Type: "TEXT"},
+		{Name: "totp_enabled", Type: "BOOLEAN"},
+		{Name: "totp_last_step", Type: "INTEGER"},
 	},
 	"api_keys": {
 		{Name: "id", Type: "INTEGER", PrimaryKey: true},
@@ -169,6 +175,11 @@ var expectedSchema = map[string][]columnSpec{
 		{Name: "name_id", Type: "INTEGER"},
 		{Name: "created_at", Type: "TIMESTAMP"},
 		{Name: "last_used_at", Type: "TIMESTAMP"},
+		{Name: "scopes", Type: "TEXT"},
+		{Name: "instance_ids", Type: "TEXT"},
+		{Name: "allowed_ips", Type: "TEXT"},
+		{Name: "expires_at", Type: "TIMESTAMP"},
+		{Name: "last_used_ip", Type: "TEXT"},
 	},
 	"instances": {
 		{Name: "id", Type: "INTEGER", PrimaryKey: true},
@@ -270,6 +281,17 @@ var expectedSchema = map[string][]columnSpec{
 		{Name: "details", Type: "TEXT"},
 		{Name: "created_at", Type: "DATETIME"},
 	},
+	"reannounce_history": {
+		{Name: "id", Type: "INTEGER", PrimaryKey: true},
+		{Name: "instance_id", Type: "INTEGER"},
+		{Name: "hash", Type: "TEXT"},
+		{Name: "torrent_name", Type: "TEXT"},
+		{Name: "trackers", Type: "TEXT"},
+		{Name: "outcome", Type: "TEXT"},
+		{Name: "reason", Type: "TEXT"},
+		{Name: "torrent_age_seconds", Type: "INTEGER"},
+		{Name: "created_at", Type: "DATETIME"},
+	},
 }
 
 var expectedIndexes = map[string][]string{
@@ -282,6 +304,7 @@ var expectedIndexes = map[string][]string{
 	"torrent_files_sync":  {"idx_torrent_files_sync_last_synced"},
 	"automations":         {"idx_automations_instance"},
 	"automation_activity": {"idx_automation_activity_instance_created"},
+	"reannounce_history":  {"idx_reannounce_history_instance_created", "idx_reannounce_history_created"},
 }
 
 var expectedTriggers = []string{
@@ -0,0 +1,31 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Per-indexer cross-seed snatch budgets. A value of 0 means unlimited.
+CREATE TABLE IF NOT EXISTS cross_seed_indexer_budgets (
+    indexer_id                INTEGER PRIMARY KEY,
+    max_downloads_per_day     INTEGER NOT NULL DEFAULT 0,
+    max_injections_per_day    INTEGER NOT NULL DEFAULT 0,
+    min_release_age_minutes   INTEGER NOT NULL DEFAULT 0,
+    created_at                DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at                DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (indexer_id) REFERENCES torznab_indexers(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_cross_seed_indexer_budgets_updated
+AFTER UPDATE ON cross_seed_indexer_budgets
+BEGIN
+    UPDATE cross_seed_indexer_budgets SET updated_at = CURRENT_TIMESTAMP WHERE indexer_id = NEW.indexer_id;
+END;
+
+-- Usage ledger used to enforce rolling 24h budgets across restarts
+CREATE TABLE IF NOT EXISTS cross_seed_indexer_usage (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    indexer_id  INTEGER NOT NULL,
+    kind        TEXT NOT NULL,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (indexer_id) REFERENCES torznab_indexers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cross_seed_indexer_usage_lookup
+    ON cross_seed_indexer_usage(indexer_id, kind, created_at);
@@ -0,0 +1,17 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Releases that cross-seed automation must never add again.
+-- indexer_id scopes an entry to a single indexer; NULL applies it everywhere.
+CREATE TABLE IF NOT EXISTS cross_seed_blocklist (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind        TEXT NOT NULL CHECK (kind IN ('infohash', 'name', 'regex', 'release_group')),
+    value       TEXT NOT NULL,
+    indexer_id  INTEGER,
+    reason      TEXT NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (indexer_id) REFERENCES torznab_indexers(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cross_seed_blocklist_unique
+    ON cross_seed_blocklist(kind, value, COALESCE(indexer_id, 0));
@@ -0,0 +1,26 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Directories watched for dropped .torrent files.
+-- mode 'add' adds torrents directly; 'cross_seed' only adds them as cross-seeds of existing data.
+CREATE TABLE IF NOT EXISTS watch_folders (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    instance_id   INTEGER NOT NULL,
+    path          TEXT NOT NULL UNIQUE,
+    mode          TEXT NOT NULL DEFAULT 'add' CHECK (mode IN ('add', 'cross_seed')),
+    category      TEXT NOT NULL DEFAULT '',
+    tags          TEXT NOT NULL DEFAULT '[]',
+    start_paused  BOOLEAN NOT NULL DEFAULT 0,
+    enabled       BOOLEAN NOT NULL DEFAULT 1,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_watch_folders_instance ON watch_folders(instance_id);
+
+CREATE TRIGGER IF NOT EXISTS trg_watch_folders_updated
+AFTER UPDATE ON watch_folders
+BEGIN
+    UPDATE watch_folders SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
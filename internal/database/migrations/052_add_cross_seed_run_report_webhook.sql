@@ -0,0 +1,5 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Optional URL that receives a JSON summary when a cross-seed run finishes.
+ALTER TABLE cross_seed_settings ADD COLUMN run_report_webhook_url TEXT NOT NULL DEFAULT '';
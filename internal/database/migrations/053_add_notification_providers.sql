@@ -0,0 +1,25 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Notification targets. Empty events/instance_ids lists subscribe to everything.
+CREATE TABLE IF NOT EXISTS notification_providers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('webhook', 'discord', 'ntfy', 'gotify', 'apprise')),
+    url TEXT NOT NULL,
+    token_encrypted TEXT NOT NULL DEFAULT '',
+    options TEXT NOT NULL DEFAULT '{}',
+    events TEXT NOT NULL DEFAULT '[]',
+    instance_ids TEXT NOT NULL DEFAULT '[]',
+    title_template TEXT NOT NULL DEFAULT '',
+    body_template TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_notification_providers_updated
+AFTER UPDATE ON notification_providers
+BEGIN
+    UPDATE notification_providers SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
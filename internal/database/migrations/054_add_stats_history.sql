@@ -0,0 +1,41 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Historical transfer statistics. Every sample is accumulated into each
+-- resolution: 'raw' buckets follow the sampling interval, 'hour' and 'day'
+-- are downsampled rollups. Transfer columns hold bytes moved during the bucket;
+-- speed sums divided by samples give the bucket's average speed; counts and
+-- ratio reflect the latest sample in the bucket.
+CREATE TABLE IF NOT EXISTS instance_stats (
+    instance_id INTEGER NOT NULL,
+    resolution TEXT NOT NULL CHECK (resolution IN ('raw', 'hour', 'day')),
+    bucket INTEGER NOT NULL,
+    uploaded INTEGER NOT NULL DEFAULT 0,
+    downloaded INTEGER NOT NULL DEFAULT 0,
+    upload_speed_sum INTEGER NOT NULL DEFAULT 0,
+    download_speed_sum INTEGER NOT NULL DEFAULT 0,
+    samples INTEGER NOT NULL DEFAULT 0,
+    torrents INTEGER NOT NULL DEFAULT 0,
+    seeding INTEGER NOT NULL DEFAULT 0,
+    downloading INTEGER NOT NULL DEFAULT 0,
+    ratio REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (instance_id, resolution, bucket),
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_instance_stats_resolution_bucket ON instance_stats(resolution, bucket);
+
+CREATE TABLE IF NOT EXISTS tracker_stats (
+    instance_id INTEGER NOT NULL,
+    tracker_id INTEGER NOT NULL REFERENCES string_pool(id),
+    resolution TEXT NOT NULL CHECK (resolution IN ('raw', 'hour', 'day')),
+    bucket INTEGER NOT NULL,
+    uploaded INTEGER NOT NULL DEFAULT 0,
+    downloaded INTEGER NOT NULL DEFAULT 0,
+    torrents INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (instance_id, tracker_id, resolution, bucket),
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_tracker_stats_resolution_bucket ON tracker_stats(resolution, bucket);
+CREATE INDEX IF NOT EXISTS idx_tracker_stats_tracker ON tracker_stats(tracker_id, resolution, bucket);
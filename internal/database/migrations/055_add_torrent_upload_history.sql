@@ -0,0 +1,18 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Per-torrent upload snapshots. A row is written only when a torrent's lifetime
+-- uploaded counter changed, and at most one row is kept per torrent per UTC day:
+-- later changes on the same day overwrite it. recorded_at is when the stored
+-- value was observed.
+CREATE TABLE IF NOT EXISTS torrent_upload_history (
+    instance_id INTEGER NOT NULL,
+    hash_id INTEGER NOT NULL REFERENCES string_pool(id),
+    day INTEGER NOT NULL,
+    uploaded INTEGER NOT NULL,
+    recorded_at INTEGER NOT NULL,
+    PRIMARY KEY (instance_id, hash_id, day),
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_torrent_upload_history_recorded_at ON torrent_upload_history(recorded_at);
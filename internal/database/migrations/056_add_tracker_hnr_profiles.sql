@@ -0,0 +1,23 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Hit-and-run requirement profiles per tracker. A torrent is compliant once it
+-- meets either requirement; zero disables a requirement. grace_period is how
+-- long after completion the requirement must be met (0 = no deadline).
+CREATE TABLE IF NOT EXISTS tracker_hnr_profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    domains TEXT NOT NULL,
+    min_seed_time INTEGER NOT NULL DEFAULT 0,
+    min_ratio REAL NOT NULL DEFAULT 0,
+    grace_period INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_tracker_hnr_profiles_updated
+AFTER UPDATE ON tracker_hnr_profiles
+BEGIN
+    UPDATE tracker_hnr_profiles SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
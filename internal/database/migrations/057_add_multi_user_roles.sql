@@ -0,0 +1,45 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Allow multiple user accounts with roles and per-instance permission grants.
+-- SQLite can't drop the single-row CHECK constraint in place, so recreate the table.
+-- The existing account keeps its id and becomes an admin.
+
+PRAGMA foreign_keys=off;
+
+CREATE TABLE user_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL DEFAULT '',
+    role TEXT NOT NULL DEFAULT 'admin' CHECK (role IN ('admin', 'operator', 'read_only')),
+    all_instances BOOLEAN NOT NULL DEFAULT 1,
+    oidc_subject TEXT UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO user_new (id, username, password_hash, role, all_instances, created_at, updated_at)
+SELECT id, username, password_hash, 'admin', 1, created_at, updated_at
+FROM user;
+DROP TABLE user;
+ALTER TABLE user_new RENAME TO user;
+
+CREATE TRIGGER IF NOT EXISTS update_user_updated_at
+AFTER UPDATE ON user
+BEGIN
+    UPDATE user SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Instances a user may access when all_instances is off. Admins always see every instance.
+CREATE TABLE IF NOT EXISTS user_instance_permissions (
+    user_id INTEGER NOT NULL,
+    instance_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, instance_id),
+    FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_instance_permissions_instance ON user_instance_permissions(instance_id);
+
+PRAGMA foreign_keys=on;
@@ -0,0 +1,27 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Scoped, expiring API keys. Existing keys keep full access (the admin scope).
+-- scopes, instance_ids and allowed_ips are comma-separated; empty instance_ids
+-- and allowed_ips mean no restriction.
+ALTER TABLE api_keys ADD COLUMN scopes TEXT NOT NULL DEFAULT 'admin';
+ALTER TABLE api_keys ADD COLUMN instance_ids TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_keys ADD COLUMN allowed_ips TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_keys ADD COLUMN expires_at TIMESTAMP;
+ALTER TABLE api_keys ADD COLUMN last_used_ip TEXT;
+
+DROP VIEW IF EXISTS api_keys_view;
+CREATE VIEW api_keys_view AS
+SELECT
+    ak.id,
+    ak.key_hash,
+    sp.value AS name,
+    ak.created_at,
+    ak.last_used_at,
+    ak.scopes,
+    ak.instance_ids,
+    ak.allowed_ips,
+    ak.expires_at,
+    ak.last_used_ip
+FROM api_keys ak
+INNER JOIN string_pool sp ON ak.name_id = sp.id;
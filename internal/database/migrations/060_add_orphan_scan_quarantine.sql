@@ -0,0 +1,14 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Optional quarantine (recycle bin) for confirmed orphan files
+ALTER TABLE orphan_scan_settings ADD COLUMN quarantine_enabled INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE orphan_scan_settings ADD COLUMN quarantine_retention_days INTEGER NOT NULL DEFAULT 7;
+
+-- Files currently held in quarantine; they move to files_deleted/bytes_reclaimed once purged
+ALTER TABLE orphan_scan_runs ADD COLUMN files_quarantined INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE orphan_scan_runs ADD COLUMN bytes_quarantined INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE orphan_scan_files ADD COLUMN quarantine_path TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_orphan_scan_files_run_status ON orphan_scan_files(run_id, status);
@@ -0,0 +1,5 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Other instances whose torrents live on the same storage (JSON array with optional path mappings)
+ALTER TABLE orphan_scan_settings ADD COLUMN shared_instances TEXT;
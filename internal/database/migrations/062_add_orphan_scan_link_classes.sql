@@ -0,0 +1,13 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Hardlink classification of orphan files: unique, hardlinked_orphan, hardlinked_elsewhere
+ALTER TABLE orphan_scan_files ADD COLUMN link_class TEXT NOT NULL DEFAULT 'unique';
+
+-- Bytes that deleting the run's orphans would actually free (bytes_reclaimed holds apparent size until deletion)
+ALTER TABLE orphan_scan_runs ADD COLUMN bytes_reclaimable INTEGER NOT NULL DEFAULT 0;
+
+-- Per-class auto-cleanup policy
+ALTER TABLE orphan_scan_settings ADD COLUMN auto_cleanup_unique INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE orphan_scan_settings ADD COLUMN auto_cleanup_hardlinked_orphans INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE orphan_scan_settings ADD COLUMN auto_cleanup_hardlinked_elsewhere INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Persisted reannounce outcomes. Skipped attempts stay in memory only.
+CREATE TABLE IF NOT EXISTS reannounce_history (
+    id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+    instance_id         INTEGER NOT NULL,
+    hash                TEXT NOT NULL,
+    torrent_name        TEXT,
+    trackers            TEXT,
+    outcome             TEXT NOT NULL,
+    reason              TEXT,
+    torrent_age_seconds INTEGER,
+    created_at          DATETIME NOT NULL,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reannounce_history_instance_created
+    ON reannounce_history(instance_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_reannounce_history_created
+    ON reannounce_history(created_at);
@@ -0,0 +1,5 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Per-tracker overrides of instance reannounce timing, stored as a JSON array
+ALTER TABLE instance_reannounce_settings ADD COLUMN tracker_profiles_json TEXT;
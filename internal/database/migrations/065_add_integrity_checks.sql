@@ -0,0 +1,49 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Per-instance settings for out-of-band piece verification
+CREATE TABLE IF NOT EXISTS integrity_settings (
+    id                      INTEGER PRIMARY KEY AUTOINCREMENT,
+    instance_id             INTEGER NOT NULL UNIQUE,
+    enabled                 INTEGER NOT NULL DEFAULT 0,
+    interval_minutes        INTEGER NOT NULL DEFAULT 60,
+    torrents_per_run        INTEGER NOT NULL DEFAULT 1,
+    recheck_after_days      INTEGER NOT NULL DEFAULT 30,
+    max_read_mib_per_second INTEGER NOT NULL DEFAULT 20,
+    failure_tag             TEXT NOT NULL DEFAULT 'integrity-failed',
+    created_at              DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at              DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_integrity_settings_updated
+AFTER UPDATE ON integrity_settings
+BEGIN
+    UPDATE integrity_settings SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- One row per verified torrent
+CREATE TABLE IF NOT EXISTS integrity_checks (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    instance_id    INTEGER NOT NULL,
+    hash           TEXT NOT NULL,
+    torrent_name   TEXT,
+    status         TEXT NOT NULL,
+    triggered_by   TEXT NOT NULL,
+    pieces_total   INTEGER NOT NULL DEFAULT 0,
+    pieces_checked INTEGER NOT NULL DEFAULT 0,
+    corrupt_pieces INTEGER NOT NULL DEFAULT 0,
+    missing_pieces INTEGER NOT NULL DEFAULT 0,
+    bytes_checked  INTEGER NOT NULL DEFAULT 0,
+    bad_files      TEXT,
+    error_message  TEXT,
+    started_at     DATETIME DEFAULT CURRENT_TIMESTAMP,
+    completed_at   DATETIME,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_integrity_checks_instance_started
+    ON integrity_checks(instance_id, started_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_integrity_checks_instance_hash
+    ON integrity_checks(instance_id, hash);
@@ -0,0 +1,40 @@
+-- Copyright (c) 2025, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Categories defined once in qui and pushed to every sync target
+CREATE TABLE IF NOT EXISTS category_templates (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT NOT NULL UNIQUE,
+    save_path  TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_category_templates_updated
+AFTER UPDATE ON category_templates
+BEGIN
+    UPDATE category_templates SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Tags defined once in qui and pushed to every sync target
+CREATE TABLE IF NOT EXISTS tag_templates (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Instances that receive the templates. Relative template save paths are joined
+-- onto save_path_prefix when it is set.
+CREATE TABLE IF NOT EXISTS category_sync_targets (
+    instance_id      INTEGER PRIMARY KEY,
+    save_path_prefix TEXT NOT NULL DEFAULT '',
+    created_at       DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at       DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_category_sync_targets_updated
+AFTER UPDATE ON category_sync_targets
+BEGIN
+    UPDATE category_sync_targets SET updated_at = CURRENT_TIMESTAMP WHERE instance_id = NEW.instance_id;
+END;
@@ -23,6 +23,21 @@ type Config struct {
 	MetricsBasicAuthUsers    string `toml:"metricsBasicAuthUsers" mapstructure:"metricsBasicAuthUsers"`
 	TrackerIconsFetchEnabled bool   `toml:"trackerIconsFetchEnabled" mapstructure:"trackerIconsFetchEnabled"`
 
+	// Statistics history sampling. The interval is in seconds and retention is
+	// in days per resolution; a retention of 0 keeps that resolution forever.
+	StatsEnabled             bool `toml:"statsEnabled" mapstructure:"statsEnabled"`
+	StatsSampleInterval      int  `toml:"statsSampleInterval" mapstructure:"statsSampleInterval"`
+	StatsRawRetentionDays    int  `toml:"statsRawRetentionDays" mapstructure:"statsRawRetentionDays"`
+	StatsHourlyRetentionDays int  `toml:"statsHourlyRetentionDays" mapstructure:"statsHourlyRetentionDays"`
+	StatsDailyRetentionDays  int  `toml:"statsDailyRetentionDays" mapstructure:"statsDailyRetentionDays"`
+
+	// Login throttling. Failed logins are counted per username and per client
+	// address; reaching a limit locks it out for LoginLockoutMinutes, doubling
+	// on each repeat lockout. A limit of 0 disables that check.
+	LoginMaxAttempts      int `toml:"loginMaxAttempts" mapstructure:"loginMaxAttempts"`
+	LoginMaxAttemptsPerIP int `toml:"loginMaxAttemptsPerIp" mapstructure:"loginMaxAttemptsPerIp"`
+	LoginLockoutMinutes   int `toml:"loginLockoutMinutes" mapstructure:"loginLockoutMinutes"`
+
 	ExternalProgramAllowList []string `toml:"externalProgramAllowList" mapstructure:"externalProgramAllowList"`
 
 	// CrossSeedRecoverErroredTorrents enables recovery attempts for errored/missingFiles torrents
@@ -38,4 +53,14 @@ type Config struct {
 	OIDCClientSecret        string `toml:"oidcClientSecret" mapstructure:"oidcClientSecret"`
 	OIDCRedirectURL         string `toml:"oidcRedirectUrl" mapstructure:"oidcRedirectUrl"`
 	OIDCDisableBuiltInLogin bool   `toml:"oidcDisableBuiltInLogin" mapstructure:"oidcDisableBuiltInLogin"`
+
+	// OIDC group to role mapping. Group lists are comma-separated. When no groups
+	// are mapped new OIDC users are read_only and roles are managed in qui;
+	// otherwise users matching no group get OIDCDefaultRole, or are refused when
+	// it is empty.
+	OIDCGroupsClaim    string `toml:"oidcGroupsClaim" mapstructure:"oidcGroupsClaim"`
+	OIDCAdminGroups    string `toml:"oidcAdminGroups" mapstructure:"oidcAdminGroups"`
+	OIDCOperatorGroups string `toml:"oidcOperatorGroups" mapstructure:"oidcOperatorGroups"`
+	OIDCReadOnlyGroups string `toml:"oidcReadOnlyGroups" mapstructure:"oidcReadOnlyGroups"`
+	OIDCDefaultRole    string `toml:"oidcDefaultRole" mapstructure:"oidcDefaultRole"`
 }
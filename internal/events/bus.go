@@ -0,0 +1,184 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultSubscriberBuffer = 256
+
+// Handler consumes events. The context is canceled when the bus is closed.
+type Handler func(ctx context.Context, event Event)
+
+type subscriber struct {
+	name    string
+	types   map[Type]struct{}
+	handler Handler
+	ch      chan Event
+	done    chan struct{}
+}
+
+func (s *subscriber) wants(t Type) bool {
+	if len(s.types) == 0 {
+		return true
+	}
+	_, ok := s.types[t]
+	return ok
+}
+
+// Bus fans published events out to subscribers. Each subscriber gets its own
+// buffered queue and goroutine, so a slow consumer never blocks publishers;
+// events are dropped for a subscriber whose queue is full.
+//
+// A nil *Bus is valid and discards everything, so services can publish
+// unconditionally.
+type Bus struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu          sync.RWMutex
+	subscribers map[uint64]*subscriber
+	nextID      uint64
+	closed      bool
+	wg          sync.WaitGroup
+
+	now func() time.Time
+}
+
+// NewBus creates an event bus.
+func NewBus() *Bus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bus{
+		ctx:         ctx,
+		cancel:      cancel,
+		subscribers: make(map[uint64]*subscriber),
+		now:         time.Now,
+	}
+}
+
+// Subscribe registers handler for the given event types, or for every event
+// when no types are given. The returned function unsubscribes.
+func (b *Bus) Subscribe(name string, handler Handler, types ...Type) func() {
+	if b == nil || handler == nil {
+		return func() {}
+	}
+
+	sub := &subscriber{
+		name:    name,
+		types:   make(map[Type]struct{}, len(types)),
+		handler: handler,
+		ch:      make(chan Event, defaultSubscriberBuffer),
+		done:    make(chan struct{}),
+	}
+	for _, t := range types {
+		sub.types[t] = struct{}{}
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.wg.Add(1)
+	b.mu.Unlock()
+
+	go b.run(sub)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			if _, ok := b.subscribers[id]; ok {
+				delete(b.subscribers, id)
+				close(sub.done)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Publish delivers the event to every interested subscriber without blocking.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = b.now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.subscribers {
+		if !sub.wants(event.Type) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			log.Warn().
+				Str("subscriber", sub.name).
+				Str("event", string(event.Type)).
+				Msg("events: subscriber queue full, dropping event")
+		}
+	}
+}
+
+// Close stops delivery and waits for in-flight handlers to return.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for id, sub := range b.subscribers {
+		delete(b.subscribers, id)
+		close(sub.done)
+	}
+	b.mu.Unlock()
+
+	b.cancel()
+	b.wg.Wait()
+}
+
+func (b *Bus) run(sub *subscriber) {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-sub.done:
+			return
+		case event := <-sub.ch:
+			b.dispatch(sub, event)
+		}
+	}
+}
+
+func (b *Bus) dispatch(sub *subscriber, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().
+				Interface("panic", r).
+				Str("subscriber", sub.name).
+				Str("event", string(event.Type)).
+				Msg("events: subscriber panicked")
+		}
+	}()
+	sub.handler(b.ctx, event)
+}
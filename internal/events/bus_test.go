@@ -0,0 +1,136 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collect(t *testing.T, bus *Bus, types ...Type) (func() []Event, func()) {
+	t.Helper()
+	var mu sync.Mutex
+	var got []Event
+	unsubscribe := bus.Subscribe(t.Name(), func(_ context.Context, event Event) {
+		mu.Lock()
+		got = append(got, event)
+		mu.Unlock()
+	}, types...)
+	return func() []Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Event(nil), got...)
+	}, unsubscribe
+}
+
+func TestBus_FiltersByType(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	all, _ := collect(t, bus)
+	backups, _ := collect(t, bus, BackupFinished)
+
+	bus.Publish(Event{Type: TorrentAdded, InstanceID: 1, Payload: Torrent{Hash: "A"}})
+	bus.Publish(Event{Type: BackupFinished, InstanceID: 1, Payload: BackupResult{RunID: 3, Success: true}})
+
+	require.Eventually(t, func() bool { return len(all()) == 2 && len(backups()) == 1 }, time.Second, 5*time.Millisecond)
+
+	event := backups()[0]
+	assert.False(t, event.Time.IsZero(), "publish stamps the event time")
+	result, ok := PayloadAs[BackupResult](event)
+	require.True(t, ok)
+	assert.Equal(t, int64(3), result.RunID)
+
+	_, ok = PayloadAs[Torrent](event)
+	assert.False(t, ok)
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	got, unsubscribe := collect(t, bus)
+	bus.Publish(Event{Type: InstanceUp})
+	require.Eventually(t, func() bool { return len(got()) == 1 }, time.Second, 5*time.Millisecond)
+
+	unsubscribe()
+	unsubscribe() // idempotent
+	bus.Publish(Event{Type: InstanceUp})
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, got(), 1)
+}
+
+func TestBus_SlowSubscriberDoesNotBlockPublisher(t *testing.T) {
+	bus := NewBus()
+
+	release := make(chan struct{})
+	bus.Subscribe("slow", func(ctx context.Context, _ Event) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		for range defaultSubscriberBuffer * 2 {
+			bus.Publish(Event{Type: TorrentCompleted})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a slow subscriber")
+	}
+
+	close(release)
+	bus.Close()
+}
+
+func TestBus_PanickingSubscriberKeepsRunning(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	var mu sync.Mutex
+	calls := 0
+	bus.Subscribe("panics", func(context.Context, Event) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		panic("boom")
+	})
+
+	bus.Publish(Event{Type: InstanceDown})
+	bus.Publish(Event{Type: InstanceDown})
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBus_NilAndClosed(t *testing.T) {
+	var nilBus *Bus
+	assert.NotPanics(t, func() {
+		nilBus.Publish(Event{Type: TorrentAdded})
+		nilBus.Subscribe("x", func(context.Context, Event) {})()
+		nilBus.Close()
+	})
+
+	bus := NewBus()
+	bus.Close()
+	bus.Close()
+	assert.NotPanics(t, func() {
+		bus.Publish(Event{Type: TorrentAdded})
+		bus.Subscribe("late", func(context.Context, Event) {})()
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package events provides a typed in-process event bus. Services publish what
+// happened; notifications, metrics and other consumers subscribe without the
+// publishers having to know about them.
+package events
+
+import "time"
+
+// Type identifies an event.
+type Type string
+
+const (
+	TorrentAdded           Type = "torrent.added"
+	TorrentCompleted       Type = "torrent.completed"
+	TorrentDeleted         Type = "torrent.deleted"
+	TorrentReannounced     Type = "torrent.reannounced"
+	AutomationRuleApplied  Type = "automation.rule_applied"
+	CrossSeedInjected      Type = "crossseed.injected"
+	BackupFinished         Type = "backup.finished"
+	OrphanScanPreviewReady Type = "orphanscan.preview_ready"
+	InstanceUp             Type = "instance.up"
+	InstanceDown           Type = "instance.down"
+	IndexerCooledDown      Type = "indexer.cooled_down"
+)
+
+// Event is a single published event. Payload holds one of the payload types
+// below, matching Type.
+type Event struct {
+	Type       Type
+	InstanceID int
+	Time       time.Time
+	Payload    any
+}
+
+// PayloadAs returns the event payload as T.
+func PayloadAs[T any](e Event) (T, bool) {
+	payload, ok := e.Payload.(T)
+	return payload, ok
+}
+
+// Torrent is the payload for TorrentAdded, TorrentCompleted and TorrentDeleted.
+// Name is empty for deletions since the torrent is already gone.
+type Torrent struct {
+	Hash string
+	Name string
+}
+
+// Reannounce is the payload for TorrentReannounced.
+type Reannounce struct {
+	Hash      string
+	Name      string
+	Trackers  string
+	Succeeded bool
+	Reason    string
+}
+
+// Actions reported in RuleApplied.
+const (
+	RuleActionPaused          = "paused"
+	RuleActionCategoryChanged = "category_changed"
+	RuleActionDeleted         = "deleted"
+)
+
+// RuleApplied is the payload for AutomationRuleApplied. Hashes and Names are
+// index-aligned. DeleteMode is only set for RuleActionDeleted.
+type RuleApplied struct {
+	Action     string
+	DeleteMode string
+	RuleNames  []string
+	Hashes     []string
+	Names      []string
+}
+
+// CrossSeedInjection is the payload for CrossSeedInjected.
+type CrossSeedInjection struct {
+	InstanceName string
+	TorrentName  string
+	IndexerName  string
+	MatchedHash  string
+	MatchedName  string
+}
+
+// BackupResult is the payload for BackupFinished.
+type BackupResult struct {
+	RunID        int64
+	Kind         string
+	Success      bool
+	Error        string
+	TorrentCount int
+}
+
+// OrphanScanPreview is the payload for OrphanScanPreviewReady.
+type OrphanScanPreview struct {
+	RunID     int64
+	Files     int
+	Bytes     int64
+	Truncated bool
+}
+
+// InstanceStatus is the payload for InstanceUp and InstanceDown.
+type InstanceStatus struct {
+	Error string
+}
+
+// IndexerCooldown is the payload for IndexerCooledDown.
+type IndexerCooldown struct {
+	IndexerID   int
+	IndexerName string
+	Until       time.Time
+	Reason      string
+}
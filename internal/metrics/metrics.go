@@ -4,11 +4,14 @@
 package metrics
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/rs/zerolog/log"
 
 	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/metrics/collector"
 	"github.com/autobrr/qui/internal/qbittorrent"
 )
@@ -16,6 +19,7 @@ import (
 type MetricsManager struct {
 	registry         *prometheus.Registry
 	torrentCollector *collector.TorrentCollector
+	eventsTotal      *prometheus.CounterVec
 }
 
 func NewMetricsManager(syncManager *qbittorrent.SyncManager, clientPool *qbittorrent.ClientPool) *MetricsManager {
@@ -30,14 +34,29 @@ func NewMetricsManager(syncManager *qbittorrent.SyncManager, clientPool *qbittor
 	registry.MustRegister(torrentCollector)
 	registry.MustRegister(database.NewMetricsCollector())
 
+	eventsTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "qui",
+		Name:      "events_total",
+		Help:      "Events published on the internal event bus, by type",
+	}, []string{"type"})
+	registry.MustRegister(eventsTotal)
+
 	log.Info().Msg("Metrics manager initialized with collectors")
 
 	return &MetricsManager{
 		registry:         registry,
 		torrentCollector: torrentCollector,
+		eventsTotal:      eventsTotal,
 	}
 }
 
+// SubscribeEvents counts every event published on the bus.
+func (m *MetricsManager) SubscribeEvents(bus *events.Bus) func() {
+	return bus.Subscribe("metrics", func(_ context.Context, event events.Event) {
+		m.eventsTotal.WithLabelValues(string(event.Type)).Inc()
+	})
+}
+
 func (m *MetricsManager) GetRegistry() *prometheus.Registry {
 	return m.registry
 }
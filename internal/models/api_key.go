@@ -11,6 +11,10 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/autobrr/qui/internal/dbinterface"
@@ -18,6 +22,33 @@ import (
 
 var ErrAPIKeyNotFound = errors.New("api key not found")
 var ErrInvalidAPIKey = errors.New("invalid api key")
+var ErrAPIKeyExpired = errors.New("api key expired")
+var ErrAPIKeyIPNotAllowed = errors.New("api key not allowed from this address")
+
+// APIKeyScope limits what an API key may do.
+type APIKeyScope string
+
+const (
+	// APIKeyScopeRead allows viewing instances, torrents and statistics.
+	APIKeyScopeRead APIKeyScope = "read"
+	// APIKeyScopeTorrentsWrite allows adding and changing torrents, categories and tags.
+	APIKeyScopeTorrentsWrite APIKeyScope = "torrents:write"
+	// APIKeyScopeCrossSeedApply allows the cross-seed webhook and apply endpoints.
+	APIKeyScopeCrossSeedApply APIKeyScope = "crossseed:apply"
+	// APIKeyScopeBackups allows managing and restoring instance backups.
+	APIKeyScopeBackups APIKeyScope = "backups"
+	// APIKeyScopeAdmin allows everything.
+	APIKeyScopeAdmin APIKeyScope = "admin"
+)
+
+// APIKeyScopes lists every valid scope.
+var APIKeyScopes = []APIKeyScope{
+	APIKeyScopeRead,
+	APIKeyScopeTorrentsWrite,
+	APIKeyScopeCrossSeedApply,
+	APIKeyScopeBackups,
+	APIKeyScopeAdmin,
+}
 
 type APIKey struct {
 	ID         int        `json:"id"`
@@ -25,6 +56,164 @@ type APIKey struct {
 	Name       string     `json:"name"`
 	CreatedAt  time.Time  `json:"createdAt"`
 	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty"`
+	APIKeyOptions
+}
+
+// APIKeyOptions restrict an API key. The zero value never expires, has no
+// scopes and no instance or address restrictions.
+type APIKeyOptions struct {
+	Scopes []APIKeyScope `json:"scopes"`
+	// InstanceIDs restricts the key to these instances; empty allows all.
+	InstanceIDs []int `json:"instanceIds"`
+	// AllowedIPs restricts the key to these addresses or CIDR ranges; empty allows all.
+	AllowedIPs []string   `json:"allowedIps"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Validate checks the scopes and address ranges.
+func (o *APIKeyOptions) Validate() error {
+	if len(o.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range o.Scopes {
+		if !slices.Contains(APIKeyScopes, scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	for _, entry := range o.AllowedIPs {
+		if _, err := parseIPPrefix(entry); err != nil {
+			return fmt.Errorf("invalid IP or CIDR %q", entry)
+		}
+	}
+	return nil
+}
+
+// HasScope reports whether the key grants scope. The admin scope grants all.
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, APIKeyScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+// Expired reports whether the key's expiry has passed.
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// AllowsIP reports whether the key may be used from the given address.
+func (k *APIKey) AllowsIP(ip string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, entry := range k.AllowedIPs {
+		if prefix, err := parseIPPrefix(entry); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIPPrefix accepts a single address or a CIDR range.
+func parseIPPrefix(entry string) (netip.Prefix, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func joinAPIKeyScopes(scopes []APIKeyScope) string {
+	values := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(values, string(scope)) {
+			values = append(values, string(scope))
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func splitAPIKeyScopes(value string) []APIKeyScope {
+	scopes := []APIKeyScope{}
+	for _, item := range splitCommaList(value) {
+		scopes = append(scopes, APIKeyScope(item))
+	}
+	return scopes
+}
+
+func joinInstanceIDs(ids []int) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range normalizeInstanceIDs(ids) {
+		values = append(values, strconv.Itoa(id))
+	}
+	return strings.Join(values, ",")
+}
+
+func splitInstanceIDs(value string) []int {
+	ids := []int{}
+	for _, item := range splitCommaList(value) {
+		if id, err := strconv.Atoi(item); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func splitCommaList(value string) []string {
+	items := []string{}
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+const apiKeyColumns = `id, key_hash, name, created_at, last_used_at, scopes, instance_ids, allowed_ips, expires_at, last_used_ip`
+
+func scanAPIKey(row interface{ Scan(...any) error }) (*APIKey, error) {
+	var (
+		apiKey                           APIKey
+		createdAt, lastUsedAt, expiresAt sql.NullTime
+		scopes, instanceIDs, allowedIPs  string
+		lastUsedIP                       sql.NullString
+	)
+	if err := row.Scan(
+		&apiKey.ID,
+		&apiKey.KeyHash,
+		&apiKey.Name,
+		&createdAt,
+		&lastUsedAt,
+		&scopes,
+		&instanceIDs,
+		&allowedIPs,
+		&expiresAt,
+		&lastUsedIP,
+	); err != nil {
+		return nil, err
+	}
+
+	apiKey.CreatedAt = createdAt.Time
+	if lastUsedAt.Valid {
+		apiKey.LastUsedAt = &lastUsedAt.Time
+	}
+	if expiresAt.Valid {
+		apiKey.ExpiresAt = &expiresAt.Time
+	}
+	apiKey.LastUsedIP = lastUsedIP.String
+	apiKey.Scopes = splitAPIKeyScopes(scopes)
+	apiKey.InstanceIDs = splitInstanceIDs(instanceIDs)
+	apiKey.AllowedIPs = splitCommaList(allowedIPs)
+	return &apiKey, nil
 }
 
 type APIKeyStore struct {
@@ -50,7 +239,18 @@ func HashAPIKey(key string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// Create creates an API key with full access that never expires.
 func (s *APIKeyStore) Create(ctx context.Context, name string) (string, *APIKey, error) {
+	return s.CreateWithOptions(ctx, name, APIKeyOptions{Scopes: []APIKeyScope{APIKeyScopeAdmin}})
+}
+
+// CreateWithOptions creates an API key restricted by opts. The raw key is
+// returned once; only its hash is stored.
+func (s *APIKeyStore) CreateWithOptions(ctx context.Context, name string, opts APIKeyOptions) (string, *APIKey, error) {
+	if err := opts.Validate(); err != nil {
+		return "", nil, err
+	}
+
 	// Generate new API key
 	rawKey, err := GenerateAPIKey()
 	if err != nil {
@@ -73,20 +273,28 @@ func (s *APIKeyStore) Create(ctx context.Context, name string) (string, *APIKey,
 		return "", nil, fmt.Errorf("failed to intern name: %w", err)
 	}
 
+	allowedIPs := make([]string, 0, len(opts.AllowedIPs))
+	for _, entry := range opts.AllowedIPs {
+		allowedIPs = append(allowedIPs, strings.TrimSpace(entry))
+	}
+
+	var expiresAt any
+	if opts.ExpiresAt != nil {
+		expiresAt = opts.ExpiresAt.UTC()
+	}
+
 	// Insert the API key
-	apiKey := &APIKey{}
-	var createdAt, lastUsedAt sql.NullTime
+	var id int
 	err = tx.QueryRowContext(ctx, `
-		INSERT INTO api_keys (key_hash, name_id) 
-		VALUES (?, ?)
-		RETURNING id, key_hash, created_at, last_used_at
-	`, keyHash, ids[0]).Scan(
-		&apiKey.ID,
-		&apiKey.KeyHash,
-		&createdAt,
-		&lastUsedAt,
-	)
+		INSERT INTO api_keys (key_hash, name_id, scopes, instance_ids, allowed_ips, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, keyHash, ids[0], joinAPIKeyScopes(opts.Scopes), joinInstanceIDs(opts.InstanceIDs), strings.Join(allowedIPs, ","), expiresAt).Scan(&id)
+	if err != nil {
+		return "", nil, err
+	}
 
+	apiKey, err := scanAPIKey(tx.QueryRowContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys_view WHERE id = ?`, id))
 	if err != nil {
 		return "", nil, err
 	}
@@ -95,36 +303,18 @@ func (s *APIKeyStore) Create(ctx context.Context, name string) (string, *APIKey,
 		return "", nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	apiKey.Name = name
-	apiKey.CreatedAt = createdAt.Time
-	if lastUsedAt.Valid {
-		apiKey.LastUsedAt = &lastUsedAt.Time
-	}
-
 	// Return both the raw key (to show user once) and the model
 	return rawKey, apiKey, nil
 }
 
 func (s *APIKeyStore) GetByHash(ctx context.Context, keyHash string) (*APIKey, error) {
 	query := `
-		SELECT id, key_hash, name, created_at, last_used_at 
+		SELECT ` + apiKeyColumns + `
 		FROM api_keys_view 
 		WHERE key_hash = ?
 	`
 
-	var id int
-	var keyHashResult, name string
-	var createdAt sql.NullTime
-	var lastUsedAt sql.NullTime
-
-	err := s.db.QueryRowContext(ctx, query, keyHash).Scan(
-		&id,
-		&keyHashResult,
-		&name,
-		&createdAt,
-		&lastUsedAt,
-	)
-
+	apiKey, err := scanAPIKey(s.db.QueryRowContext(ctx, query, keyHash))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrAPIKeyNotFound
@@ -133,23 +323,12 @@ func (s *APIKeyStore) GetByHash(ctx context.Context, keyHash string) (*APIKey, e
 		return nil, err
 	}
 
-	apiKey := &APIKey{
-		ID:        id,
-		KeyHash:   keyHashResult,
-		Name:      name,
-		CreatedAt: createdAt.Time,
-	}
-
-	if lastUsedAt.Valid {
-		apiKey.LastUsedAt = &lastUsedAt.Time
-	}
-
 	return apiKey, nil
 }
 
 func (s *APIKeyStore) List(ctx context.Context) ([]*APIKey, error) {
 	query := `
-		SELECT id, key_hash, name, created_at, last_used_at 
+		SELECT ` + apiKeyColumns + `
 		FROM api_keys_view 
 		ORDER BY created_at DESC
 	`
@@ -162,33 +341,11 @@ func (s *APIKeyStore) List(ctx context.Context) ([]*APIKey, error) {
 
 	keys := make([]*APIKey, 0)
 	for rows.Next() {
-		var id int
-		var keyHash, name string
-		var createdAt sql.NullTime
-		var lastUsedAt sql.NullTime
-
-		err := rows.Scan(
-			&id,
-			&keyHash,
-			&name,
-			&createdAt,
-			&lastUsedAt,
-		)
+		apiKey, err := scanAPIKey(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		apiKey := &APIKey{
-			ID:        id,
-			KeyHash:   keyHash,
-			Name:      name,
-			CreatedAt: createdAt.Time,
-		}
-
-		if lastUsedAt.Valid {
-			apiKey.LastUsedAt = &lastUsedAt.Time
-		}
-
 		keys = append(keys, apiKey)
 	}
 
@@ -199,7 +356,7 @@ func (s *APIKeyStore) List(ctx context.Context) ([]*APIKey, error) {
 	return keys, nil
 }
 
-func (s *APIKeyStore) UpdateLastUsed(ctx context.Context, id int) error {
+func (s *APIKeyStore) UpdateLastUsed(ctx context.Context, id int, ip string) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -208,11 +365,11 @@ func (s *APIKeyStore) UpdateLastUsed(ctx context.Context, id int) error {
 
 	query := `
 		UPDATE api_keys 
-		SET last_used_at = CURRENT_TIMESTAMP 
+		SET last_used_at = CURRENT_TIMESTAMP, last_used_ip = ?
 		WHERE id = ?
 	`
 
-	result, err := tx.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, ip, id)
 	if err != nil {
 		return err
 	}
@@ -263,8 +420,9 @@ func (s *APIKeyStore) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-// ValidateAPIKey validates a raw API key and returns the associated APIKey if valid
-func (s *APIKeyStore) ValidateAPIKey(ctx context.Context, rawKey string) (*APIKey, error) {
+// ValidateAPIKey validates a raw API key used from clientIP and returns the
+// associated APIKey if valid. Expired keys and disallowed addresses are rejected.
+func (s *APIKeyStore) ValidateAPIKey(ctx context.Context, rawKey, clientIP string) (*APIKey, error) {
 	keyHash := HashAPIKey(rawKey)
 
 	apiKey, err := s.GetByHash(ctx, keyHash)
@@ -275,9 +433,16 @@ func (s *APIKeyStore) ValidateAPIKey(ctx context.Context, rawKey string) (*APIKe
 		return nil, err
 	}
 
+	if apiKey.Expired(time.Now()) {
+		return nil, ErrAPIKeyExpired
+	}
+	if !apiKey.AllowsIP(clientIP) {
+		return nil, ErrAPIKeyIPNotAllowed
+	}
+
 	// Update last used timestamp asynchronously
 	go func() {
-		_ = s.UpdateLastUsed(ctx, apiKey.ID)
+		_ = s.UpdateLastUsed(context.WithoutCancel(ctx), apiKey.ID, clientIP)
 	}()
 
 	return apiKey, nil
@@ -0,0 +1,96 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestAPIKeyStore_ScopedKeys(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewAPIKeyStore(db)
+	ctx := context.Background()
+
+	instanceID := insertTestInstance(t, db, "restricted")
+
+	rawAdmin, adminKey, err := store.Create(ctx, "full")
+	require.NoError(t, err)
+	assert.Equal(t, []models.APIKeyScope{models.APIKeyScopeAdmin}, adminKey.Scopes)
+	assert.Nil(t, adminKey.ExpiresAt)
+	assert.True(t, adminKey.HasScope(models.APIKeyScopeBackups))
+
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	rawScoped, scoped, err := store.CreateWithOptions(ctx, "autobrr", models.APIKeyOptions{
+		Scopes:      []models.APIKeyScope{models.APIKeyScopeCrossSeedApply, models.APIKeyScopeRead},
+		InstanceIDs: []int{instanceID},
+		AllowedIPs:  []string{"10.0.0.0/24", " 192.168.1.5 "},
+		ExpiresAt:   &expiry,
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.APIKeyScope{models.APIKeyScopeCrossSeedApply, models.APIKeyScopeRead}, scoped.Scopes)
+	assert.Equal(t, []int{instanceID}, scoped.InstanceIDs)
+	assert.Equal(t, []string{"10.0.0.0/24", "192.168.1.5"}, scoped.AllowedIPs)
+	require.NotNil(t, scoped.ExpiresAt)
+	assert.True(t, expiry.Equal(*scoped.ExpiresAt))
+	assert.False(t, scoped.HasScope(models.APIKeyScopeTorrentsWrite))
+
+	_, err = store.ValidateAPIKey(ctx, rawScoped, "10.0.1.1")
+	require.ErrorIs(t, err, models.ErrAPIKeyIPNotAllowed)
+	_, err = store.ValidateAPIKey(ctx, rawScoped, "10.0.0.42")
+	require.NoError(t, err)
+	_, err = store.ValidateAPIKey(ctx, rawScoped, "::ffff:192.168.1.5")
+	require.NoError(t, err)
+	_, err = store.ValidateAPIKey(ctx, rawAdmin, "203.0.113.9")
+	require.NoError(t, err)
+
+	require.NoError(t, store.UpdateLastUsed(ctx, scoped.ID, "10.0.0.42"))
+	keys, err := store.List(ctx)
+	require.NoError(t, err)
+	for _, key := range keys {
+		if key.ID == scoped.ID {
+			assert.Equal(t, "10.0.0.42", key.LastUsedIP)
+			assert.NotNil(t, key.LastUsedAt)
+		}
+	}
+
+	past := time.Now().Add(-time.Minute)
+	rawExpired, _, err := store.CreateWithOptions(ctx, "expired", models.APIKeyOptions{
+		Scopes:    []models.APIKeyScope{models.APIKeyScopeRead},
+		ExpiresAt: &past,
+	})
+	require.NoError(t, err)
+	_, err = store.ValidateAPIKey(ctx, rawExpired, "127.0.0.1")
+	require.ErrorIs(t, err, models.ErrAPIKeyExpired)
+}
+
+func TestAPIKeyOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    models.APIKeyOptions
+		wantErr bool
+	}{
+		{"valid", models.APIKeyOptions{Scopes: []models.APIKeyScope{models.APIKeyScopeRead}, AllowedIPs: []string{"::1", "fd00::/8"}}, false},
+		{"no scopes", models.APIKeyOptions{}, true},
+		{"unknown scope", models.APIKeyOptions{Scopes: []models.APIKeyScope{"everything"}}, true},
+		{"bad address", models.APIKeyOptions{Scopes: []models.APIKeyScope{models.APIKeyScopeRead}, AllowedIPs: []string{"10.0.0.0/33"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
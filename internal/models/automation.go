@@ -355,6 +355,11 @@ const (
 	FieldCompletionOnAge ConditionField = "COMPLETION_ON_AGE"
 	FieldLastActivityAge ConditionField = "LAST_ACTIVITY_AGE"
 
+	// Upload history fields (derived from recorded per-torrent upload snapshots)
+	FieldUploadedLast7d  ConditionField = "UPLOADED_LAST_7D"
+	FieldUploadedLast30d ConditionField = "UPLOADED_LAST_30D"
+	FieldLastUploadAge   ConditionField = "LAST_UPLOAD_AGE"
+
 	// Numeric fields (float64)
 	FieldRatio        ConditionField = "RATIO"
 	FieldProgress     ConditionField = "PROGRESS"
@@ -480,8 +485,10 @@ type PauseAction struct {
 
 // DeleteAction configures deletion with mode and conditions.
 type DeleteAction struct {
-	Enabled   bool           `json:"enabled"`
-	Mode      string         `json:"mode"` // "delete", "deleteWithFiles", "deleteWithFilesPreserveCrossSeeds"
+	Enabled bool   `json:"enabled"`
+	Mode    string `json:"mode"` // "delete", "deleteWithFiles", "deleteWithFilesPreserveCrossSeeds"
+	// IgnoreHnR allows deleting torrents that haven't met their tracker's hit-and-run requirements.
+	IgnoreHnR bool           `json:"ignoreHnr,omitempty"`
 	Condition *RuleCondition `json:"condition,omitempty"`
 }
 
@@ -1230,6 +1230,82 @@ func (s *BackupStore) countBlobReferencesChunk(ctx context.Context, relPaths []s
 	return result, nil
 }
 
+// ListTorrentBlobPaths returns every distinct cached torrent blob referenced by backup items.
+func (s *BackupStore) ListTorrentBlobPaths(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT torrent_blob_path
+		FROM instance_backup_items_view
+		WHERE torrent_blob_path IS NOT NULL AND torrent_blob_path != ''
+		ORDER BY torrent_blob_path
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// ReplaceTorrentBlobPath points every backup item referencing oldPath at newPath and
+// returns the number of items updated.
+func (s *BackupStore) ReplaceTorrentBlobPath(ctx context.Context, oldPath, newPath string) (int64, error) {
+	if oldPath == "" || newPath == "" || oldPath == newPath {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	oldIDs, err := dbinterface.GetStringID(ctx, tx, oldPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up blob path: %w", err)
+	}
+	if !oldIDs[0].Valid {
+		return 0, nil
+	}
+
+	newIDs, err := dbinterface.InternStrings(ctx, tx, newPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to intern blob path: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE instance_backup_items
+		SET torrent_blob_path_id = ?
+		WHERE torrent_blob_path_id = ?
+	`, newIDs[0], oldIDs[0].Int64)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
 func (s *BackupStore) GetInstanceName(ctx context.Context, instanceID int) (string, error) {
 	var name string
 	err := s.db.QueryRowContext(ctx, `
@@ -0,0 +1,195 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// CategoryTemplate is a category that is kept identical on every sync target.
+type CategoryTemplate struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// SavePath is either absolute or relative. Relative paths are joined onto a
+	// target's save path prefix, or left for qBittorrent to resolve against its
+	// default save path when the target has no prefix.
+	SavePath  string    `json:"savePath"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CategoryTemplates is the full set of categories and tags pushed to sync targets.
+type CategoryTemplates struct {
+	Categories []CategoryTemplate `json:"categories"`
+	Tags       []string           `json:"tags"`
+}
+
+// CategorySyncTarget is an instance that receives the templates.
+type CategorySyncTarget struct {
+	InstanceID     int       `json:"instanceId"`
+	SavePathPrefix string    `json:"savePathPrefix"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type CategoryTemplateStore struct {
+	db dbinterface.Querier
+}
+
+func NewCategoryTemplateStore(db dbinterface.Querier) *CategoryTemplateStore {
+	return &CategoryTemplateStore{db: db}
+}
+
+// GetTemplates returns every category template and tag template, ordered by name.
+func (s *CategoryTemplateStore) GetTemplates(ctx context.Context) (*CategoryTemplates, error) {
+	templates := &CategoryTemplates{
+		Categories: []CategoryTemplate{},
+		Tags:       []string{},
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, save_path, created_at, updated_at
+		FROM category_templates
+		ORDER BY name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category CategoryTemplate
+		if err := rows.Scan(&category.ID, &category.Name, &category.SavePath, &category.CreatedAt, &category.UpdatedAt); err != nil {
+			return nil, err
+		}
+		templates.Categories = append(templates.Categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagRows, err := s.db.QueryContext(ctx, `SELECT name FROM tag_templates ORDER BY name COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+
+	for tagRows.Next() {
+		var tag string
+		if err := tagRows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		templates.Tags = append(templates.Tags, tag)
+	}
+	return templates, tagRows.Err()
+}
+
+// ReplaceTemplates replaces every category and tag template. Categories that keep
+// their name keep their ID and creation time.
+func (s *CategoryTemplateStore) ReplaceTemplates(ctx context.Context, categories []CategoryTemplate, tags []string) (*CategoryTemplates, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	names := make([]any, 0, len(categories))
+	for _, category := range categories {
+		names = append(names, category.Name)
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO category_templates (name, save_path)
+			VALUES (?, ?)
+			ON CONFLICT(name) DO UPDATE SET save_path = excluded.save_path
+			WHERE category_templates.save_path != excluded.save_path
+		`, category.Name, category.SavePath); err != nil {
+			return nil, fmt.Errorf("failed to save category template %q: %w", category.Name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM category_templates`+notInClause("name", len(names)), names...); err != nil {
+		return nil, fmt.Errorf("failed to remove category templates: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tag_templates`); err != nil {
+		return nil, fmt.Errorf("failed to clear tag templates: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO tag_templates (name) VALUES (?)`, tag); err != nil {
+			return nil, fmt.Errorf("failed to save tag template %q: %w", tag, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetTemplates(ctx)
+}
+
+// ListTargets returns every sync target ordered by instance ID.
+func (s *CategoryTemplateStore) ListTargets(ctx context.Context) ([]CategorySyncTarget, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT instance_id, save_path_prefix, created_at, updated_at
+		FROM category_sync_targets
+		ORDER BY instance_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := []CategorySyncTarget{}
+	for rows.Next() {
+		var target CategorySyncTarget
+		if err := rows.Scan(&target.InstanceID, &target.SavePathPrefix, &target.CreatedAt, &target.UpdatedAt); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, rows.Err()
+}
+
+// ReplaceTargets replaces the set of sync targets.
+func (s *CategoryTemplateStore) ReplaceTargets(ctx context.Context, targets []CategorySyncTarget) ([]CategorySyncTarget, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]any, 0, len(targets))
+	for _, target := range targets {
+		ids = append(ids, target.InstanceID)
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO category_sync_targets (instance_id, save_path_prefix)
+			VALUES (?, ?)
+			ON CONFLICT(instance_id) DO UPDATE SET save_path_prefix = excluded.save_path_prefix
+			WHERE category_sync_targets.save_path_prefix != excluded.save_path_prefix
+		`, target.InstanceID, target.SavePathPrefix); err != nil {
+			return nil, fmt.Errorf("failed to save sync target %d: %w", target.InstanceID, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM category_sync_targets`+notInClause("instance_id", len(ids)), ids...); err != nil {
+		return nil, fmt.Errorf("failed to remove sync targets: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.ListTargets(ctx)
+}
+
+// notInClause returns a WHERE clause excluding n bound values of column, or an empty
+// clause when n is zero so every row matches.
+func notInClause(column string, n int) string {
+	if n == 0 {
+		return ""
+	}
+	return " WHERE " + column + " NOT IN (" + strings.TrimSuffix(strings.Repeat("?,", n), ",") + ")"
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestCategoryTemplateStore_ReplaceTemplates(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewCategoryTemplateStore(db)
+	ctx := context.Background()
+
+	templates, err := store.GetTemplates(ctx)
+	require.NoError(t, err)
+	require.Empty(t, templates.Categories)
+	require.Empty(t, templates.Tags)
+
+	templates, err = store.ReplaceTemplates(ctx, []models.CategoryTemplate{
+		{Name: "tv", SavePath: "tv"},
+		{Name: "Movies", SavePath: "/data/movies"},
+	}, []string{"radarr", "sonarr"})
+	require.NoError(t, err)
+	require.Len(t, templates.Categories, 2)
+	require.Equal(t, "Movies", templates.Categories[0].Name)
+	require.Equal(t, "tv", templates.Categories[1].Name)
+	require.Equal(t, []string{"radarr", "sonarr"}, templates.Tags)
+	tvID := templates.Categories[1].ID
+
+	templates, err = store.ReplaceTemplates(ctx, []models.CategoryTemplate{
+		{Name: "tv", SavePath: "shows"},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, templates.Categories, 1)
+	require.Equal(t, tvID, templates.Categories[0].ID)
+	require.Equal(t, "shows", templates.Categories[0].SavePath)
+	require.Empty(t, templates.Tags)
+
+	templates, err = store.ReplaceTemplates(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, templates.Categories)
+}
+
+func TestCategoryTemplateStore_ReplaceTargets(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	first := insertTestInstance(t, db, "first")
+	second := insertTestInstance(t, db, "second")
+	store := models.NewCategoryTemplateStore(db)
+	ctx := context.Background()
+
+	targets, err := store.ReplaceTargets(ctx, []models.CategorySyncTarget{
+		{InstanceID: first},
+		{InstanceID: second, SavePathPrefix: "/mnt/seedbox"},
+	})
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+	require.Equal(t, "/mnt/seedbox", targets[1].SavePathPrefix)
+
+	targets, err = store.ReplaceTargets(ctx, []models.CategorySyncTarget{
+		{InstanceID: second, SavePathPrefix: "/data"},
+	})
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	require.Equal(t, second, targets[0].InstanceID)
+	require.Equal(t, "/data", targets[0].SavePathPrefix)
+
+	_, err = db.ExecContext(ctx, "DELETE FROM instances WHERE id = ?", second)
+	require.NoError(t, err)
+	targets, err = store.ListTargets(ctx)
+	require.NoError(t, err)
+	require.Empty(t, targets)
+}
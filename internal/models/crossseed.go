@@ -71,6 +71,9 @@ type CrossSeedAutomationSettings struct {
 	SkipRecheck                  bool `json:"skipRecheck"`                  // Skip cross-seed matches that require a recheck
 	SkipPieceBoundarySafetyCheck bool `json:"skipPieceBoundarySafetyCheck"` // Skip piece boundary safety check (risky: may corrupt existing seeded data)
 
+	// Run reports: a JSON summary is POSTed here when an RSS or seeded search run finishes.
+	RunReportWebhookURL string `json:"runReportWebhookUrl"`
+
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -188,6 +191,9 @@ type CrossSeedRunResult struct {
 	Message            string  `json:"message,omitempty"`
 	MatchedTorrentHash *string `json:"matchedTorrentHash,omitempty"`
 	MatchedTorrentName *string `json:"matchedTorrentName,omitempty"`
+	ReleaseTitle       string  `json:"releaseTitle,omitempty"`
+	BytesSaved         int64   `json:"bytesSaved,omitempty"` // Size of the data reused instead of downloaded
+	DurationMs         int64   `json:"durationMs,omitempty"` // Time spent evaluating the feed item
 }
 
 // CrossSeedRun stores the persisted automation run metadata.
@@ -234,6 +240,8 @@ type CrossSeedSearchResult struct {
 	Added        bool      `json:"added"`
 	Message      string    `json:"message,omitempty"`
 	ProcessedAt  time.Time `json:"processedAt"`
+	BytesSaved   int64     `json:"bytesSaved,omitempty"` // Size of the data reused instead of downloaded
+	DurationMs   int64     `json:"durationMs,omitempty"` // Time spent on this result
 }
 
 // CrossSeedSearchRun stores metadata for library search automation runs.
@@ -308,6 +316,7 @@ func (s *CrossSeedStore) GetSettings(ctx context.Context) (*CrossSeedAutomationS
 		       skip_auto_resume_rss, skip_auto_resume_seeded_search,
 		       skip_auto_resume_completion, skip_auto_resume_webhook,
 		       skip_recheck, skip_piece_boundary_safety_check,
+		       run_report_webhook_url,
 		       created_at, updated_at
 		FROM cross_seed_settings
 		WHERE id = 1
@@ -358,6 +367,7 @@ func (s *CrossSeedStore) GetSettings(ctx context.Context) (*CrossSeedAutomationS
 		&settings.SkipAutoResumeWebhook,
 		&settings.SkipRecheck,
 		&settings.SkipPieceBoundarySafetyCheck,
+		&settings.RunReportWebhookURL,
 		&createdAt,
 		&updatedAt,
 	)
@@ -522,9 +532,10 @@ func (s *CrossSeedStore) UpsertSettings(ctx context.Context, settings *CrossSeed
 			use_custom_category, custom_category,
 			skip_auto_resume_rss, skip_auto_resume_seeded_search,
 			skip_auto_resume_completion, skip_auto_resume_webhook,
-			skip_recheck, skip_piece_boundary_safety_check
+			skip_recheck, skip_piece_boundary_safety_check,
+			run_report_webhook_url
 		) VALUES (
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 		)
 		ON CONFLICT(id) DO UPDATE SET
 			enabled = excluded.enabled,
@@ -559,7 +570,8 @@ func (s *CrossSeedStore) UpsertSettings(ctx context.Context, settings *CrossSeed
 			skip_auto_resume_completion = excluded.skip_auto_resume_completion,
 			skip_auto_resume_webhook = excluded.skip_auto_resume_webhook,
 			skip_recheck = excluded.skip_recheck,
-			skip_piece_boundary_safety_check = excluded.skip_piece_boundary_safety_check
+			skip_piece_boundary_safety_check = excluded.skip_piece_boundary_safety_check,
+			run_report_webhook_url = excluded.run_report_webhook_url
 	`
 
 	// Convert *int to any for proper SQL handling
@@ -608,6 +620,7 @@ func (s *CrossSeedStore) UpsertSettings(ctx context.Context, settings *CrossSeed
 		settings.SkipAutoResumeWebhook,
 		settings.SkipRecheck,
 		settings.SkipPieceBoundarySafetyCheck,
+		settings.RunReportWebhookURL,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("upsert settings: %w", err)
@@ -0,0 +1,189 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// CrossSeedBlocklistKind identifies how a blocklist entry is matched against a release.
+type CrossSeedBlocklistKind string
+
+const (
+	// CrossSeedBlocklistInfoHash matches the torrent infohash exactly.
+	CrossSeedBlocklistInfoHash CrossSeedBlocklistKind = "infohash"
+	// CrossSeedBlocklistName matches the release name exactly (case-insensitive).
+	CrossSeedBlocklistName CrossSeedBlocklistKind = "name"
+	// CrossSeedBlocklistRegex matches the release name against a regular expression.
+	CrossSeedBlocklistRegex CrossSeedBlocklistKind = "regex"
+	// CrossSeedBlocklistReleaseGroup matches the parsed release group (case-insensitive).
+	CrossSeedBlocklistReleaseGroup CrossSeedBlocklistKind = "release_group"
+)
+
+// IsValid reports whether the kind is one of the supported blocklist kinds.
+func (k CrossSeedBlocklistKind) IsValid() bool {
+	switch k {
+	case CrossSeedBlocklistInfoHash, CrossSeedBlocklistName, CrossSeedBlocklistRegex, CrossSeedBlocklistReleaseGroup:
+		return true
+	default:
+		return false
+	}
+}
+
+// CrossSeedBlocklistEntry prevents matching releases from being cross-seeded.
+// A nil IndexerID applies the entry to every indexer.
+type CrossSeedBlocklistEntry struct {
+	ID          int64                  `json:"id"`
+	Kind        CrossSeedBlocklistKind `json:"kind"`
+	Value       string                 `json:"value"`
+	IndexerID   *int                   `json:"indexerId,omitempty"`
+	IndexerName string                 `json:"indexerName,omitempty"`
+	Reason      string                 `json:"reason,omitempty"`
+	CreatedAt   time.Time              `json:"createdAt"`
+}
+
+// CrossSeedBlocklistStore persists cross-seed blocklist entries.
+type CrossSeedBlocklistStore struct {
+	db dbinterface.Querier
+}
+
+// NewCrossSeedBlocklistStore constructs a new blocklist store.
+func NewCrossSeedBlocklistStore(db dbinterface.Querier) *CrossSeedBlocklistStore {
+	return &CrossSeedBlocklistStore{db: db}
+}
+
+const crossSeedBlocklistSelect = `
+	SELECT b.id, b.kind, b.value, b.indexer_id, COALESCE(i.name, ''), b.reason, b.created_at
+	FROM cross_seed_blocklist b
+	LEFT JOIN torznab_indexers_view i ON i.id = b.indexer_id
+`
+
+// List returns all blocklist entries, newest first.
+func (s *CrossSeedBlocklistStore) List(ctx context.Context) ([]*CrossSeedBlocklistEntry, error) {
+	rows, err := s.db.QueryContext(ctx, crossSeedBlocklistSelect+` ORDER BY b.created_at DESC, b.id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list blocklist: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*CrossSeedBlocklistEntry
+	for rows.Next() {
+		entry, err := scanCrossSeedBlocklistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Get returns a single blocklist entry or sql.ErrNoRows.
+func (s *CrossSeedBlocklistStore) Get(ctx context.Context, id int64) (*CrossSeedBlocklistEntry, error) {
+	row := s.db.QueryRowContext(ctx, crossSeedBlocklistSelect+` WHERE b.id = ?`, id)
+	return scanCrossSeedBlocklistEntry(row)
+}
+
+// Create inserts a blocklist entry. Adding an entry that already exists updates
+// its reason and returns the existing row.
+func (s *CrossSeedBlocklistStore) Create(ctx context.Context, entry *CrossSeedBlocklistEntry) (*CrossSeedBlocklistEntry, error) {
+	if entry == nil {
+		return nil, errors.New("blocklist entry is nil")
+	}
+	if !entry.Kind.IsValid() {
+		return nil, fmt.Errorf("invalid blocklist kind %q", entry.Kind)
+	}
+	if entry.Value == "" {
+		return nil, errors.New("blocklist entry must include a value")
+	}
+
+	var indexerID sql.NullInt64
+	if entry.IndexerID != nil {
+		indexerID = sql.NullInt64{Int64: int64(*entry.IndexerID), Valid: true}
+	}
+
+	var existingID int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id FROM cross_seed_blocklist
+		WHERE kind = ? AND value = ? AND COALESCE(indexer_id, 0) = COALESCE(?, 0)
+	`, string(entry.Kind), entry.Value, indexerID).Scan(&existingID)
+	switch {
+	case err == nil:
+		if _, err := s.db.ExecContext(ctx, `UPDATE cross_seed_blocklist SET reason = ? WHERE id = ?`, entry.Reason, existingID); err != nil {
+			return nil, fmt.Errorf("update blocklist entry: %w", err)
+		}
+		return s.Get(ctx, existingID)
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("lookup blocklist entry: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO cross_seed_blocklist (kind, value, indexer_id, reason)
+		VALUES (?, ?, ?, ?)
+	`, string(entry.Kind), entry.Value, indexerID, entry.Reason)
+	if err != nil {
+		return nil, fmt.Errorf("insert blocklist entry: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("fetch blocklist entry id: %w", err)
+	}
+
+	return s.Get(ctx, id)
+}
+
+// Delete removes a blocklist entry.
+func (s *CrossSeedBlocklistStore) Delete(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM cross_seed_blocklist WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanCrossSeedBlocklistEntry(scanner interface {
+	Scan(dest ...any) error
+}) (*CrossSeedBlocklistEntry, error) {
+	var (
+		entry     CrossSeedBlocklistEntry
+		kind      string
+		indexerID sql.NullInt64
+	)
+
+	if err := scanner.Scan(
+		&entry.ID,
+		&kind,
+		&entry.Value,
+		&indexerID,
+		&entry.IndexerName,
+		&entry.Reason,
+		&entry.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	entry.Kind = CrossSeedBlocklistKind(kind)
+	if indexerID.Valid {
+		id := int(indexerID.Int64)
+		entry.IndexerID = &id
+	}
+
+	return &entry, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestCrossSeedBlocklistStore_CreateListDelete(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewCrossSeedBlocklistStore(db)
+	ctx := context.Background()
+
+	indexerID := insertTestTorznabIndexer(t, db, "TrackerA", "https://tracker-a.example")
+
+	global, err := store.Create(ctx, &models.CrossSeedBlocklistEntry{
+		Kind:   models.CrossSeedBlocklistReleaseGroup,
+		Value:  "BADGRP",
+		Reason: "bad encodes",
+	})
+	require.NoError(t, err)
+	assert.Nil(t, global.IndexerID)
+	assert.Empty(t, global.IndexerName)
+
+	scoped, err := store.Create(ctx, &models.CrossSeedBlocklistEntry{
+		Kind:      models.CrossSeedBlocklistReleaseGroup,
+		Value:     "BADGRP",
+		IndexerID: &indexerID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, scoped.IndexerID)
+	assert.Equal(t, indexerID, *scoped.IndexerID)
+	assert.Equal(t, "TrackerA", scoped.IndexerName)
+	assert.NotEqual(t, global.ID, scoped.ID)
+
+	// Re-adding an existing global entry updates it instead of duplicating it.
+	again, err := store.Create(ctx, &models.CrossSeedBlocklistEntry{
+		Kind:   models.CrossSeedBlocklistReleaseGroup,
+		Value:  "BADGRP",
+		Reason: "still bad",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, global.ID, again.ID)
+	assert.Equal(t, "still bad", again.Reason)
+
+	_, err = store.Create(ctx, &models.CrossSeedBlocklistEntry{Kind: "bogus", Value: "x"})
+	require.Error(t, err)
+
+	entries, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	require.NoError(t, store.Delete(ctx, global.ID))
+	require.ErrorIs(t, store.Delete(ctx, global.ID), sql.ErrNoRows)
+
+	entries, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, scoped.ID, entries[0].ID)
+}
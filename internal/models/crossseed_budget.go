@@ -0,0 +1,233 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// CrossSeedUsageKind identifies which budget a usage record counts against.
+type CrossSeedUsageKind string
+
+const (
+	// CrossSeedUsageDownload counts .torrent downloads from an indexer.
+	CrossSeedUsageDownload CrossSeedUsageKind = "download"
+	// CrossSeedUsageInjection counts cross-seeds added to a qBittorrent instance.
+	CrossSeedUsageInjection CrossSeedUsageKind = "injection"
+)
+
+// CrossSeedIndexerBudget limits how aggressively cross-seed snatches from a single indexer.
+// Zero values mean "unlimited" for every field.
+type CrossSeedIndexerBudget struct {
+	IndexerID            int       `json:"indexerId"`
+	IndexerName          string    `json:"indexerName"`
+	MaxDownloadsPerDay   int       `json:"maxDownloadsPerDay"`
+	MaxInjectionsPerDay  int       `json:"maxInjectionsPerDay"`
+	MinReleaseAgeMinutes int       `json:"minReleaseAgeMinutes"`
+	CreatedAt            time.Time `json:"createdAt"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+// IsUnlimited reports whether the budget imposes no restrictions.
+func (b *CrossSeedIndexerBudget) IsUnlimited() bool {
+	return b == nil || (b.MaxDownloadsPerDay <= 0 && b.MaxInjectionsPerDay <= 0 && b.MinReleaseAgeMinutes <= 0)
+}
+
+// CrossSeedIndexerUsage holds usage counts for an indexer within a window.
+type CrossSeedIndexerUsage struct {
+	Downloads  int `json:"downloads"`
+	Injections int `json:"injections"`
+}
+
+// CrossSeedBudgetStore persists per-indexer snatch budgets and the usage ledger.
+type CrossSeedBudgetStore struct {
+	db dbinterface.Querier
+}
+
+// NewCrossSeedBudgetStore constructs a new budget store.
+func NewCrossSeedBudgetStore(db dbinterface.Querier) *CrossSeedBudgetStore {
+	return &CrossSeedBudgetStore{db: db}
+}
+
+// List returns all configured budgets ordered by indexer name.
+func (s *CrossSeedBudgetStore) List(ctx context.Context) ([]*CrossSeedIndexerBudget, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT b.indexer_id, i.name, b.max_downloads_per_day, b.max_injections_per_day,
+		       b.min_release_age_minutes, b.created_at, b.updated_at
+		FROM cross_seed_indexer_budgets b
+		JOIN torznab_indexers_view i ON i.id = b.indexer_id
+		ORDER BY i.name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list indexer budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var budgets []*CrossSeedIndexerBudget
+	for rows.Next() {
+		budget, err := scanCrossSeedIndexerBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// Get returns the budget for an indexer or sql.ErrNoRows when none is configured.
+func (s *CrossSeedBudgetStore) Get(ctx context.Context, indexerID int) (*CrossSeedIndexerBudget, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT b.indexer_id, i.name, b.max_downloads_per_day, b.max_injections_per_day,
+		       b.min_release_age_minutes, b.created_at, b.updated_at
+		FROM cross_seed_indexer_budgets b
+		JOIN torznab_indexers_view i ON i.id = b.indexer_id
+		WHERE b.indexer_id = ?
+	`, indexerID)
+
+	return scanCrossSeedIndexerBudget(row)
+}
+
+// Upsert creates or replaces the budget for an indexer.
+// Returns sql.ErrNoRows when the indexer does not exist.
+func (s *CrossSeedBudgetStore) Upsert(ctx context.Context, budget *CrossSeedIndexerBudget) (*CrossSeedIndexerBudget, error) {
+	if budget == nil {
+		return nil, errors.New("budget is nil")
+	}
+	if budget.IndexerID <= 0 {
+		return nil, errors.New("budget must include indexer ID")
+	}
+
+	var exists int
+	if err := s.db.QueryRowContext(ctx, `SELECT 1 FROM torznab_indexers WHERE id = ?`, budget.IndexerID).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO cross_seed_indexer_budgets (
+			indexer_id, max_downloads_per_day, max_injections_per_day, min_release_age_minutes
+		) VALUES (?, ?, ?, ?)
+		ON CONFLICT(indexer_id) DO UPDATE SET
+			max_downloads_per_day = excluded.max_downloads_per_day,
+			max_injections_per_day = excluded.max_injections_per_day,
+			min_release_age_minutes = excluded.min_release_age_minutes
+	`, budget.IndexerID, max(budget.MaxDownloadsPerDay, 0), max(budget.MaxInjectionsPerDay, 0), max(budget.MinReleaseAgeMinutes, 0))
+	if err != nil {
+		return nil, fmt.Errorf("upsert indexer budget: %w", err)
+	}
+
+	return s.Get(ctx, budget.IndexerID)
+}
+
+// Delete removes the budget for an indexer.
+func (s *CrossSeedBudgetStore) Delete(ctx context.Context, indexerID int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM cross_seed_indexer_budgets WHERE indexer_id = ?`, indexerID)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RecordUsage appends a usage event for an indexer.
+func (s *CrossSeedBudgetStore) RecordUsage(ctx context.Context, indexerID int, kind CrossSeedUsageKind, at time.Time) error {
+	if indexerID <= 0 {
+		return errors.New("usage must include indexer ID")
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO cross_seed_indexer_usage (indexer_id, kind, created_at)
+		VALUES (?, ?, ?)
+	`, indexerID, string(kind), at.UTC())
+	if err != nil {
+		return fmt.Errorf("record indexer usage: %w", err)
+	}
+
+	return nil
+}
+
+// UsageSince returns download and injection counts per indexer recorded at or after since.
+func (s *CrossSeedBudgetStore) UsageSince(ctx context.Context, since time.Time) (map[int]CrossSeedIndexerUsage, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT indexer_id, kind, COUNT(*)
+		FROM cross_seed_indexer_usage
+		WHERE created_at >= ?
+		GROUP BY indexer_id, kind
+	`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query indexer usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := make(map[int]CrossSeedIndexerUsage)
+	for rows.Next() {
+		var indexerID, count int
+		var kind string
+		if err := rows.Scan(&indexerID, &kind, &count); err != nil {
+			return nil, err
+		}
+
+		entry := usage[indexerID]
+		switch CrossSeedUsageKind(kind) {
+		case CrossSeedUsageDownload:
+			entry.Downloads = count
+		case CrossSeedUsageInjection:
+			entry.Injections = count
+		}
+		usage[indexerID] = entry
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// PruneUsage removes usage events older than the provided cutoff.
+func (s *CrossSeedBudgetStore) PruneUsage(ctx context.Context, olderThan time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM cross_seed_indexer_usage WHERE created_at < ?`, olderThan.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("prune indexer usage: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, nil
+	}
+
+	return rows, nil
+}
+
+func scanCrossSeedIndexerBudget(scanner interface {
+	Scan(dest ...any) error
+}) (*CrossSeedIndexerBudget, error) {
+	var b CrossSeedIndexerBudget
+	if err := scanner.Scan(
+		&b.IndexerID,
+		&b.IndexerName,
+		&b.MaxDownloadsPerDay,
+		&b.MaxInjectionsPerDay,
+		&b.MinReleaseAgeMinutes,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
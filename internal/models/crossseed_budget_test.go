@@ -0,0 +1,84 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestCrossSeedBudgetStore_UpsertListDelete(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewCrossSeedBudgetStore(db)
+	ctx := context.Background()
+
+	indexerID := insertTestTorznabIndexer(t, db, "TrackerA", "https://tracker-a.example")
+
+	budget, err := store.Upsert(ctx, &models.CrossSeedIndexerBudget{
+		IndexerID:            indexerID,
+		MaxDownloadsPerDay:   10,
+		MaxInjectionsPerDay:  5,
+		MinReleaseAgeMinutes: -3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "TrackerA", budget.IndexerName)
+	assert.Equal(t, 10, budget.MaxDownloadsPerDay)
+	assert.Equal(t, 5, budget.MaxInjectionsPerDay)
+	assert.Equal(t, 0, budget.MinReleaseAgeMinutes, "negative values are clamped")
+
+	budget, err = store.Upsert(ctx, &models.CrossSeedIndexerBudget{IndexerID: indexerID, MaxDownloadsPerDay: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, budget.MaxDownloadsPerDay)
+	assert.Equal(t, 0, budget.MaxInjectionsPerDay)
+
+	budgets, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, budgets, 1)
+	assert.Equal(t, indexerID, budgets[0].IndexerID)
+
+	_, err = store.Upsert(ctx, &models.CrossSeedIndexerBudget{IndexerID: indexerID + 100, MaxDownloadsPerDay: 1})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, store.Delete(ctx, indexerID))
+	require.ErrorIs(t, store.Delete(ctx, indexerID), sql.ErrNoRows)
+
+	_, err = store.Get(ctx, indexerID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCrossSeedBudgetStore_UsageWindowAndPrune(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewCrossSeedBudgetStore(db)
+	ctx := context.Background()
+
+	trackerA := insertTestTorznabIndexer(t, db, "TrackerA", "https://tracker-a.example")
+	trackerB := insertTestTorznabIndexer(t, db, "TrackerB", "https://tracker-b.example")
+
+	now := time.Now().UTC()
+	require.NoError(t, store.RecordUsage(ctx, trackerA, models.CrossSeedUsageDownload, now.Add(-time.Hour)))
+	require.NoError(t, store.RecordUsage(ctx, trackerA, models.CrossSeedUsageDownload, now.Add(-2*time.Hour)))
+	require.NoError(t, store.RecordUsage(ctx, trackerA, models.CrossSeedUsageInjection, now.Add(-time.Hour)))
+	require.NoError(t, store.RecordUsage(ctx, trackerA, models.CrossSeedUsageDownload, now.Add(-30*time.Hour)))
+	require.NoError(t, store.RecordUsage(ctx, trackerB, models.CrossSeedUsageInjection, now.Add(-time.Minute)))
+
+	usage, err := store.UsageSince(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, models.CrossSeedIndexerUsage{Downloads: 2, Injections: 1}, usage[trackerA])
+	assert.Equal(t, models.CrossSeedIndexerUsage{Injections: 1}, usage[trackerB])
+
+	pruned, err := store.PruneUsage(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	usage, err = store.UsageSince(ctx, now.Add(-48*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage[trackerA].Downloads)
+}
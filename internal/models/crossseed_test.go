@@ -87,6 +87,7 @@ func TestCrossSeedStore_SettingsRoundTrip(t *testing.T) {
 		TargetInstanceIDs: []int{1, 2},
 		TargetIndexerIDs:     []int{11, 42},
 		MaxResultsPerRun:     25,
+		RunReportWebhookURL:  "https://hooks.example/cross-seed",
 	})
 	require.NoError(t, err)
 
@@ -102,6 +103,7 @@ func TestCrossSeedStore_SettingsRoundTrip(t *testing.T) {
 	assert.ElementsMatch(t, []int{1, 2}, updated.TargetInstanceIDs)
 	assert.ElementsMatch(t, []int{11, 42}, updated.TargetIndexerIDs)
 	assert.Equal(t, 25, updated.MaxResultsPerRun)
+	assert.Equal(t, "https://hooks.example/cross-seed", updated.RunReportWebhookURL)
 
 	reloaded, err := store.GetSettings(ctx)
 	require.NoError(t, err)
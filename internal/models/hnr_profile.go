@@ -0,0 +1,156 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// HnRProfile describes a tracker's hit-and-run rules. A torrent satisfies the
+// profile once it meets either requirement; a zero requirement is unused.
+type HnRProfile struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Domains []string `json:"domains"`
+	// MinSeedTime is the required seeding time in seconds.
+	MinSeedTime int64   `json:"minSeedTime"`
+	MinRatio    float64 `json:"minRatio"`
+	// GracePeriod is how long after completion the requirement must be met, in
+	// seconds. Zero means there is no deadline.
+	GracePeriod int64     `json:"gracePeriod"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// MatchesDomain reports whether the profile covers a tracker domain.
+func (p *HnRProfile) MatchesDomain(domain string) bool {
+	for _, d := range p.Domains {
+		if strings.EqualFold(d, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+type HnRProfileStore struct {
+	db dbinterface.Querier
+}
+
+func NewHnRProfileStore(db dbinterface.Querier) *HnRProfileStore {
+	return &HnRProfileStore{db: db}
+}
+
+const hnrProfileColumns = `id, name, domains, min_seed_time, min_ratio, grace_period, enabled, created_at, updated_at`
+
+func scanHnRProfile(scanner interface{ Scan(...any) error }) (*HnRProfile, error) {
+	var p HnRProfile
+	var domainsStr string
+	if err := scanner.Scan(&p.ID, &p.Name, &domainsStr, &p.MinSeedTime, &p.MinRatio, &p.GracePeriod, &p.Enabled, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	p.Domains = splitDomains(domainsStr)
+	return &p, nil
+}
+
+func (s *HnRProfileStore) List(ctx context.Context) ([]*HnRProfile, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+hnrProfileColumns+`
+		FROM tracker_hnr_profiles
+		ORDER BY name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*HnRProfile
+	for rows.Next() {
+		p, err := scanHnRProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+func (s *HnRProfileStore) Get(ctx context.Context, id int) (*HnRProfile, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+hnrProfileColumns+`
+		FROM tracker_hnr_profiles
+		WHERE id = ?
+	`, id)
+	return scanHnRProfile(row)
+}
+
+func (s *HnRProfileStore) Create(ctx context.Context, p *HnRProfile) (*HnRProfile, error) {
+	if p == nil {
+		return nil, errors.New("profile is nil")
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO tracker_hnr_profiles (name, domains, min_seed_time, min_ratio, grace_period, enabled)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, p.Name, joinDomains(p.Domains), p.MinSeedTime, p.MinRatio, p.GracePeriod, p.Enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, int(id))
+}
+
+func (s *HnRProfileStore) Update(ctx context.Context, p *HnRProfile) (*HnRProfile, error) {
+	if p == nil {
+		return nil, errors.New("profile is nil")
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE tracker_hnr_profiles
+		SET name = ?, domains = ?, min_seed_time = ?, min_ratio = ?, grace_period = ?, enabled = ?
+		WHERE id = ?
+	`, p.Name, joinDomains(p.Domains), p.MinSeedTime, p.MinRatio, p.GracePeriod, p.Enabled, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.Get(ctx, p.ID)
+}
+
+func (s *HnRProfileStore) Delete(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM tracker_hnr_profiles WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestHnRProfileStore_CRUD(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewHnRProfileStore(db)
+	ctx := context.Background()
+
+	created, err := store.Create(ctx, &models.HnRProfile{
+		Name:        "Tracker",
+		Domains:     []string{"tracker.example", "tracker.example", "alt.example"},
+		MinSeedTime: 72 * 3600,
+		MinRatio:    1,
+		GracePeriod: 14 * 24 * 3600,
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tracker.example", "alt.example"}, created.Domains)
+	assert.True(t, created.MatchesDomain("ALT.example"))
+	assert.False(t, created.MatchesDomain("other.example"))
+
+	created.MinRatio = 0
+	created.Enabled = false
+	updated, err := store.Update(ctx, created)
+	require.NoError(t, err)
+	assert.Zero(t, updated.MinRatio)
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, int64(72*3600), updated.MinSeedTime)
+
+	profiles, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+
+	require.NoError(t, store.Delete(ctx, created.ID))
+	assert.ErrorIs(t, store.Delete(ctx, created.ID), sql.ErrNoRows)
+	_, err = store.Update(ctx, created)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
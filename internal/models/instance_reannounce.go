@@ -6,8 +6,10 @@ package models
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/autobrr/qui/internal/dbinterface"
@@ -25,21 +27,55 @@ const (
 
 // InstanceReannounceSettings stores per-instance tracker reannounce configuration.
 type InstanceReannounceSettings struct {
-	InstanceID                int       `json:"instanceId"`
-	Enabled                   bool      `json:"enabled"`
-	InitialWaitSeconds        int       `json:"initialWaitSeconds"`
-	ReannounceIntervalSeconds int       `json:"reannounceIntervalSeconds"`
-	MaxAgeSeconds             int       `json:"maxAgeSeconds"`
-	MaxRetries                int       `json:"maxRetries"`
-	Aggressive                bool      `json:"aggressive"`
-	MonitorAll                bool      `json:"monitorAll"`
-	ExcludeCategories         bool      `json:"excludeCategories"`
-	Categories                []string  `json:"categories"`
-	ExcludeTags               bool      `json:"excludeTags"`
-	Tags                      []string  `json:"tags"`
-	ExcludeTrackers           bool      `json:"excludeTrackers"`
-	Trackers                  []string  `json:"trackers"`
-	UpdatedAt                 time.Time `json:"updatedAt"`
+	InstanceID                int      `json:"instanceId"`
+	Enabled                   bool     `json:"enabled"`
+	InitialWaitSeconds        int      `json:"initialWaitSeconds"`
+	ReannounceIntervalSeconds int      `json:"reannounceIntervalSeconds"`
+	MaxAgeSeconds             int      `json:"maxAgeSeconds"`
+	MaxRetries                int      `json:"maxRetries"`
+	Aggressive                bool     `json:"aggressive"`
+	MonitorAll                bool     `json:"monitorAll"`
+	ExcludeCategories         bool     `json:"excludeCategories"`
+	Categories                []string `json:"categories"`
+	ExcludeTags               bool     `json:"excludeTags"`
+	Tags                      []string `json:"tags"`
+	ExcludeTrackers           bool     `json:"excludeTrackers"`
+	Trackers                  []string `json:"trackers"`
+	// TrackerProfiles override timing for torrents on specific trackers.
+	TrackerProfiles []ReannounceTrackerProfile `json:"trackerProfiles"`
+	UpdatedAt       time.Time                  `json:"updatedAt"`
+}
+
+// ReannounceTrackerProfile overrides instance reannounce timing for one tracker.
+// Tracker matches a tracker domain or a tracker customization display name.
+// Nil fields inherit the instance setting.
+type ReannounceTrackerProfile struct {
+	Tracker                   string `json:"tracker"`
+	InitialWaitSeconds        *int   `json:"initialWaitSeconds,omitempty"`
+	ReannounceIntervalSeconds *int   `json:"reannounceIntervalSeconds,omitempty"`
+	MaxRetries                *int   `json:"maxRetries,omitempty"`
+	Aggressive                *bool  `json:"aggressive,omitempty"`
+}
+
+// WithProfile returns a copy of the settings with the profile's overrides applied.
+func (s *InstanceReannounceSettings) WithProfile(profile *ReannounceTrackerProfile) *InstanceReannounceSettings {
+	clone := *s
+	if profile == nil {
+		return &clone
+	}
+	if profile.InitialWaitSeconds != nil {
+		clone.InitialWaitSeconds = *profile.InitialWaitSeconds
+	}
+	if profile.ReannounceIntervalSeconds != nil {
+		clone.ReannounceIntervalSeconds = *profile.ReannounceIntervalSeconds
+	}
+	if profile.MaxRetries != nil {
+		clone.MaxRetries = *profile.MaxRetries
+	}
+	if profile.Aggressive != nil {
+		clone.Aggressive = *profile.Aggressive
+	}
+	return &clone
 }
 
 // InstanceReannounceStore manages persistence for InstanceReannounceSettings.
@@ -69,6 +105,7 @@ func DefaultInstanceReannounceSettings(instanceID int) *InstanceReannounceSettin
 		Tags:                      []string{},
 		ExcludeTrackers:           false,
 		Trackers:                  []string{},
+		TrackerProfiles:           []ReannounceTrackerProfile{},
 	}
 }
 
@@ -76,7 +113,7 @@ func DefaultInstanceReannounceSettings(instanceID int) *InstanceReannounceSettin
 func (s *InstanceReannounceStore) Get(ctx context.Context, instanceID int) (*InstanceReannounceSettings, error) {
 	const query = `SELECT instance_id, enabled, initial_wait_seconds, reannounce_interval_seconds,
 		max_age_seconds, max_retries, aggressive, monitor_all, categories_json, tags_json, trackers_json, updated_at,
-		exclude_categories, exclude_tags, exclude_trackers
+		exclude_categories, exclude_tags, exclude_trackers, tracker_profiles_json
 		FROM instance_reannounce_settings WHERE instance_id = ?`
 
 	row := s.db.QueryRowContext(ctx, query, instanceID)
@@ -94,7 +131,7 @@ func (s *InstanceReannounceStore) Get(ctx context.Context, instanceID int) (*Ins
 func (s *InstanceReannounceStore) List(ctx context.Context) ([]*InstanceReannounceSettings, error) {
 	const query = `SELECT instance_id, enabled, initial_wait_seconds, reannounce_interval_seconds,
 		max_age_seconds, max_retries, aggressive, monitor_all, categories_json, tags_json, trackers_json, updated_at,
-		exclude_categories, exclude_tags, exclude_trackers
+		exclude_categories, exclude_tags, exclude_trackers, tracker_profiles_json
 		FROM instance_reannounce_settings`
 
 	rows, err := s.db.QueryContext(ctx, query)
@@ -138,12 +175,16 @@ func (s *InstanceReannounceStore) Upsert(ctx context.Context, settings *Instance
 	if err != nil {
 		return nil, err
 	}
+	profilesJSON, err := json.Marshal(coerced.TrackerProfiles)
+	if err != nil {
+		return nil, err
+	}
 
 	const stmt = `INSERT INTO instance_reannounce_settings (
 		instance_id, enabled, initial_wait_seconds, reannounce_interval_seconds,
 		max_age_seconds, max_retries, aggressive, monitor_all, categories_json, tags_json, trackers_json,
-		exclude_categories, exclude_tags, exclude_trackers)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		exclude_categories, exclude_tags, exclude_trackers, tracker_profiles_json)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(instance_id) DO UPDATE SET
 		enabled = excluded.enabled,
 		initial_wait_seconds = excluded.initial_wait_seconds,
@@ -157,7 +198,8 @@ func (s *InstanceReannounceStore) Upsert(ctx context.Context, settings *Instance
 		trackers_json = excluded.trackers_json,
 		exclude_categories = excluded.exclude_categories,
 		exclude_tags = excluded.exclude_tags,
-		exclude_trackers = excluded.exclude_trackers`
+		exclude_trackers = excluded.exclude_trackers,
+		tracker_profiles_json = excluded.tracker_profiles_json`
 
 	_, err = s.db.ExecContext(ctx, stmt,
 		coerced.InstanceID,
@@ -174,6 +216,7 @@ func (s *InstanceReannounceStore) Upsert(ctx context.Context, settings *Instance
 		BoolToSQLite(coerced.ExcludeCategories),
 		BoolToSQLite(coerced.ExcludeTags),
 		BoolToSQLite(coerced.ExcludeTrackers),
+		string(profilesJSON),
 	)
 	if err != nil {
 		return nil, err
@@ -209,9 +252,41 @@ func sanitizeInstanceReannounceSettings(s *InstanceReannounceSettings) *Instance
 	clone.Categories = SanitizeStringSlice(clone.Categories)
 	clone.Tags = SanitizeStringSlice(clone.Tags)
 	clone.Trackers = SanitizeStringSlice(clone.Trackers)
+	clone.TrackerProfiles = sanitizeReannounceTrackerProfiles(clone.TrackerProfiles)
 	return &clone
 }
 
+// sanitizeReannounceTrackerProfiles trims tracker names, drops empty or duplicate
+// entries (case-insensitive, first wins) and clamps overrides to valid ranges.
+func sanitizeReannounceTrackerProfiles(profiles []ReannounceTrackerProfile) []ReannounceTrackerProfile {
+	result := make([]ReannounceTrackerProfile, 0, len(profiles))
+	seen := make(map[string]struct{}, len(profiles))
+	for _, profile := range profiles {
+		profile.Tracker = strings.TrimSpace(profile.Tracker)
+		key := strings.ToLower(profile.Tracker)
+		if key == "" {
+			continue
+		}
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if profile.InitialWaitSeconds != nil && *profile.InitialWaitSeconds < 0 {
+			profile.InitialWaitSeconds = nil
+		}
+		if profile.ReannounceIntervalSeconds != nil && *profile.ReannounceIntervalSeconds <= 0 {
+			profile.ReannounceIntervalSeconds = nil
+		}
+		if profile.MaxRetries != nil {
+			retries := min(max(*profile.MaxRetries, minMaxRetries), maxMaxRetries)
+			profile.MaxRetries = &retries
+		}
+		result = append(result, profile)
+	}
+	return result
+}
+
 func scanInstanceReannounceSettings(scanner interface {
 	Scan(dest ...any) error
 }) (*InstanceReannounceSettings, error) {
@@ -231,6 +306,7 @@ func scanInstanceReannounceSettings(scanner interface {
 		excludeCategoriesInt int
 		excludeTagsInt       int
 		excludeTrackersInt   int
+		profilesJSON         sql.NullString
 	)
 
 	if err := scanner.Scan(
@@ -249,6 +325,7 @@ func scanInstanceReannounceSettings(scanner interface {
 		&excludeCategoriesInt,
 		&excludeTagsInt,
 		&excludeTrackersInt,
+		&profilesJSON,
 	); err != nil {
 		return nil, err
 	}
@@ -265,6 +342,12 @@ func scanInstanceReannounceSettings(scanner interface {
 	if err != nil {
 		return nil, fmt.Errorf("decode trackers: %w", err)
 	}
+	var profiles []ReannounceTrackerProfile
+	if profilesJSON.Valid && strings.TrimSpace(profilesJSON.String) != "" {
+		if err := json.Unmarshal([]byte(profilesJSON.String), &profiles); err != nil {
+			return nil, fmt.Errorf("decode tracker profiles: %w", err)
+		}
+	}
 
 	settings := &InstanceReannounceSettings{
 		InstanceID:                instanceID,
@@ -281,6 +364,7 @@ func scanInstanceReannounceSettings(scanner interface {
 		Tags:                      tags,
 		ExcludeTrackers:           excludeTrackersInt == 1,
 		Trackers:                  trackers,
+		TrackerProfiles:           profiles,
 	}
 
 	if updatedAt.Valid {
@@ -0,0 +1,46 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestInstanceReannounceStore_TrackerProfilesRoundTrip(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	instanceID := insertTestInstance(t, db, "reannounce-profiles")
+	store := models.NewInstanceReannounceStore(db)
+	ctx := context.Background()
+
+	wait := 120
+	retries := 500
+	quick := false
+	settings := models.DefaultInstanceReannounceSettings(instanceID)
+	settings.TrackerProfiles = []models.ReannounceTrackerProfile{
+		{Tracker: " Slow Tracker ", InitialWaitSeconds: &wait, MaxRetries: &retries, Aggressive: &quick},
+		{Tracker: "slow tracker"}, // duplicate, dropped
+		{Tracker: "   "},          // empty, dropped
+	}
+
+	saved, err := store.Upsert(ctx, settings)
+	require.NoError(t, err)
+	require.Len(t, saved.TrackerProfiles, 1)
+
+	profile := saved.TrackerProfiles[0]
+	require.Equal(t, "Slow Tracker", profile.Tracker)
+	require.Equal(t, 120, *profile.InitialWaitSeconds)
+	require.Equal(t, 50, *profile.MaxRetries, "max retries is clamped")
+	require.False(t, *profile.Aggressive)
+	require.Nil(t, profile.ReannounceIntervalSeconds)
+
+	effective := saved.WithProfile(&profile)
+	require.Equal(t, 120, effective.InitialWaitSeconds)
+	require.Equal(t, saved.ReannounceIntervalSeconds, effective.ReannounceIntervalSeconds)
+	require.Equal(t, 15, saved.InitialWaitSeconds, "WithProfile must not modify the instance settings")
+}
@@ -0,0 +1,363 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// Integrity check statuses.
+const (
+	IntegrityCheckStatusRunning  = "running"
+	IntegrityCheckStatusPassed   = "passed"
+	IntegrityCheckStatusFailed   = "failed"
+	IntegrityCheckStatusError    = "error"
+	IntegrityCheckStatusCanceled = "canceled"
+)
+
+// ErrIntegrityCheckActive is returned when an instance already has a running check.
+var ErrIntegrityCheckActive = errors.New("an integrity check is already running for this instance")
+
+// IntegritySettings controls scheduled out-of-band piece verification for an instance.
+type IntegritySettings struct {
+	ID         int64 `json:"id"`
+	InstanceID int   `json:"instanceId"`
+	Enabled    bool  `json:"enabled"`
+	// IntervalMinutes is how often the scheduler samples torrents to verify.
+	IntervalMinutes int `json:"intervalMinutes"`
+	// TorrentsPerRun caps how many torrents a scheduled pass verifies.
+	TorrentsPerRun int `json:"torrentsPerRun"`
+	// RecheckAfterDays is how long a verified torrent is skipped by the scheduler.
+	RecheckAfterDays int `json:"recheckAfterDays"`
+	// MaxReadMiBPerSecond throttles disk reads; 0 disables throttling.
+	MaxReadMiBPerSecond int `json:"maxReadMiBPerSecond"`
+	// FailureTag is added to torrents that fail verification; empty disables tagging.
+	FailureTag string    `json:"failureTag"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// IntegrityCheck is the result of verifying one torrent's data against its piece hashes.
+type IntegrityCheck struct {
+	ID            int64              `json:"id"`
+	InstanceID    int                `json:"instanceId"`
+	Hash          string             `json:"hash"`
+	TorrentName   string             `json:"torrentName"`
+	Status        string             `json:"status"` // running, passed, failed, error, canceled
+	TriggeredBy   string             `json:"triggeredBy"`
+	PiecesTotal   int                `json:"piecesTotal"`
+	PiecesChecked int                `json:"piecesChecked"`
+	CorruptPieces int                `json:"corruptPieces"`
+	MissingPieces int                `json:"missingPieces"`
+	BytesChecked  int64              `json:"bytesChecked"`
+	BadFiles      []IntegrityBadFile `json:"badFiles"`
+	ErrorMessage  string             `json:"errorMessage,omitempty"`
+	StartedAt     time.Time          `json:"startedAt"`
+	CompletedAt   *time.Time         `json:"completedAt,omitempty"`
+}
+
+// IntegrityBadFile lists a file touched by corrupt or missing pieces.
+type IntegrityBadFile struct {
+	Path          string `json:"path"`
+	CorruptPieces int    `json:"corruptPieces"`
+	MissingPieces int    `json:"missingPieces"`
+}
+
+// IntegrityStore handles database operations for integrity checks.
+type IntegrityStore struct {
+	db dbinterface.Querier
+}
+
+// NewIntegrityStore creates a new IntegrityStore.
+func NewIntegrityStore(db dbinterface.Querier) *IntegrityStore {
+	return &IntegrityStore{db: db}
+}
+
+// GetSettings retrieves integrity settings for an instance.
+// Returns nil if no settings exist.
+func (s *IntegrityStore) GetSettings(ctx context.Context, instanceID int) (*IntegritySettings, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, instance_id, enabled, interval_minutes, torrents_per_run, recheck_after_days,
+		       max_read_mib_per_second, failure_tag, created_at, updated_at
+		FROM integrity_settings
+		WHERE instance_id = ?
+	`, instanceID)
+
+	var settings IntegritySettings
+	err := row.Scan(
+		&settings.ID,
+		&settings.InstanceID,
+		&settings.Enabled,
+		&settings.IntervalMinutes,
+		&settings.TorrentsPerRun,
+		&settings.RecheckAfterDays,
+		&settings.MaxReadMiBPerSecond,
+		&settings.FailureTag,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpsertSettings creates or updates integrity settings for an instance.
+func (s *IntegrityStore) UpsertSettings(ctx context.Context, settings *IntegritySettings) (*IntegritySettings, error) {
+	if settings == nil {
+		return nil, errors.New("settings is nil")
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO integrity_settings
+			(instance_id, enabled, interval_minutes, torrents_per_run, recheck_after_days,
+			 max_read_mib_per_second, failure_tag)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(instance_id) DO UPDATE SET
+			enabled = excluded.enabled,
+			interval_minutes = excluded.interval_minutes,
+			torrents_per_run = excluded.torrents_per_run,
+			recheck_after_days = excluded.recheck_after_days,
+			max_read_mib_per_second = excluded.max_read_mib_per_second,
+			failure_tag = excluded.failure_tag
+	`, settings.InstanceID, boolToInt(settings.Enabled), settings.IntervalMinutes,
+		settings.TorrentsPerRun, settings.RecheckAfterDays, settings.MaxReadMiBPerSecond,
+		settings.FailureTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSettings(ctx, settings.InstanceID)
+}
+
+// CreateCheckIfNoActive atomically starts a check unless the instance already has one running.
+func (s *IntegrityStore) CreateCheckIfNoActive(ctx context.Context, instanceID int, hash, torrentName, triggeredBy string) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO integrity_checks (instance_id, hash, torrent_name, status, triggered_by)
+		SELECT ?, ?, ?, 'running', ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM integrity_checks
+			WHERE instance_id = ? AND status = 'running'
+		)
+	`, instanceID, hash, torrentName, triggeredBy, instanceID)
+	if err != nil {
+		return 0, fmt.Errorf("insert integrity check: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return 0, ErrIntegrityCheckActive
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateCheckProgress records how far a running check has got.
+func (s *IntegrityStore) UpdateCheckProgress(ctx context.Context, checkID int64, piecesTotal, piecesChecked int, bytesChecked int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE integrity_checks
+		SET pieces_total = ?, pieces_checked = ?, bytes_checked = ?
+		WHERE id = ?
+	`, piecesTotal, piecesChecked, bytesChecked, checkID)
+	return err
+}
+
+// CompleteCheck stores the final status and counters of a check.
+func (s *IntegrityStore) CompleteCheck(ctx context.Context, check *IntegrityCheck) error {
+	if check == nil {
+		return errors.New("check is nil")
+	}
+
+	badFiles := check.BadFiles
+	if badFiles == nil {
+		badFiles = []IntegrityBadFile{}
+	}
+	badFilesJSON, err := json.Marshal(badFiles)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE integrity_checks
+		SET status = ?, pieces_total = ?, pieces_checked = ?, corrupt_pieces = ?, missing_pieces = ?,
+		    bytes_checked = ?, bad_files = ?, error_message = ?, completed_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, check.Status, check.PiecesTotal, check.PiecesChecked, check.CorruptPieces, check.MissingPieces,
+		check.BytesChecked, string(badFilesJSON), check.ErrorMessage, check.ID)
+	return err
+}
+
+const integrityCheckColumns = `
+	id, instance_id, hash, torrent_name, status, triggered_by, pieces_total, pieces_checked,
+	corrupt_pieces, missing_pieces, bytes_checked, bad_files, error_message, started_at, completed_at`
+
+// GetCheckByInstance retrieves a specific check for an instance.
+// Returns nil if the check does not exist.
+func (s *IntegrityStore) GetCheckByInstance(ctx context.Context, instanceID int, checkID int64) (*IntegrityCheck, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+integrityCheckColumns+`
+		FROM integrity_checks
+		WHERE id = ? AND instance_id = ?
+	`, checkID, instanceID)
+
+	check, err := scanIntegrityCheck(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return check, err
+}
+
+// ListChecks lists recent checks for an instance, newest first.
+func (s *IntegrityStore) ListChecks(ctx context.Context, instanceID int, limit int) ([]*IntegrityCheck, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+integrityCheckColumns+`
+		FROM integrity_checks
+		WHERE instance_id = ?
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`, instanceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []*IntegrityCheck
+	for rows.Next() {
+		check, err := scanIntegrityCheck(rows)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
+
+// GetLastVerifiedTimes returns when each torrent of an instance last finished
+// verification with a pass or fail verdict, keyed by hash.
+func (s *IntegrityStore) GetLastVerifiedTimes(ctx context.Context, instanceID int) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT hash, started_at
+		FROM integrity_checks
+		WHERE instance_id = ? AND status IN ('passed', 'failed')
+		ORDER BY started_at
+	`, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	verified := make(map[string]time.Time)
+	for rows.Next() {
+		var hash string
+		var startedAt time.Time
+		if err := rows.Scan(&hash, &startedAt); err != nil {
+			return nil, err
+		}
+		verified[hash] = startedAt
+	}
+	return verified, rows.Err()
+}
+
+// GetLastStartedAt returns when the latest check with the given trigger started.
+// Returns nil if there is none.
+func (s *IntegrityStore) GetLastStartedAt(ctx context.Context, instanceID int, triggeredBy string) (*time.Time, error) {
+	var startedAt time.Time
+	err := s.db.QueryRowContext(ctx, `
+		SELECT started_at
+		FROM integrity_checks
+		WHERE instance_id = ? AND triggered_by = ?
+		ORDER BY started_at DESC
+		LIMIT 1
+	`, instanceID, triggeredBy).Scan(&startedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &startedAt, nil
+}
+
+// MarkRunningChecksInterrupted fails checks left running by a previous process.
+func (s *IntegrityStore) MarkRunningChecksInterrupted(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE integrity_checks
+		SET status = 'error', error_message = 'Interrupted by restart', completed_at = CURRENT_TIMESTAMP
+		WHERE status = 'running'
+	`)
+	return err
+}
+
+// PruneChecks keeps the newest keep checks per instance and deletes the rest.
+func (s *IntegrityStore) PruneChecks(ctx context.Context, instanceID int, keep int) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM integrity_checks
+		WHERE instance_id = ? AND status != 'running' AND id NOT IN (
+			SELECT id FROM integrity_checks
+			WHERE instance_id = ?
+			ORDER BY started_at DESC, id DESC
+			LIMIT ?
+		)
+	`, instanceID, instanceID, keep)
+	return err
+}
+
+func scanIntegrityCheck(row interface{ Scan(dest ...any) error }) (*IntegrityCheck, error) {
+	var check IntegrityCheck
+	var torrentName sql.NullString
+	var badFilesJSON sql.NullString
+	var errorMessage sql.NullString
+	var completedAt sql.NullTime
+
+	if err := row.Scan(
+		&check.ID,
+		&check.InstanceID,
+		&check.Hash,
+		&torrentName,
+		&check.Status,
+		&check.TriggeredBy,
+		&check.PiecesTotal,
+		&check.PiecesChecked,
+		&check.CorruptPieces,
+		&check.MissingPieces,
+		&check.BytesChecked,
+		&badFilesJSON,
+		&errorMessage,
+		&check.StartedAt,
+		&completedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	check.TorrentName = torrentName.String
+	check.ErrorMessage = errorMessage.String
+	if badFilesJSON.Valid && badFilesJSON.String != "" {
+		if err := json.Unmarshal([]byte(badFilesJSON.String), &check.BadFiles); err != nil {
+			return nil, err
+		}
+	}
+	if check.BadFiles == nil {
+		check.BadFiles = []IntegrityBadFile{}
+	}
+	if completedAt.Valid {
+		check.CompletedAt = &completedAt.Time
+	}
+
+	return &check, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestIntegrityStore_CheckLifecycle(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	instanceID := insertTestInstance(t, db, "integrity")
+	store := models.NewIntegrityStore(db)
+	ctx := context.Background()
+
+	settings, err := store.GetSettings(ctx, instanceID)
+	require.NoError(t, err)
+	require.Nil(t, settings)
+
+	settings, err = store.UpsertSettings(ctx, &models.IntegritySettings{
+		InstanceID:          instanceID,
+		Enabled:             true,
+		IntervalMinutes:     30,
+		TorrentsPerRun:      2,
+		RecheckAfterDays:    14,
+		MaxReadMiBPerSecond: 50,
+		FailureTag:          "bad-data",
+	})
+	require.NoError(t, err)
+	require.True(t, settings.Enabled)
+	require.Equal(t, "bad-data", settings.FailureTag)
+
+	checkID, err := store.CreateCheckIfNoActive(ctx, instanceID, "aaa", "Torrent A", "manual")
+	require.NoError(t, err)
+
+	_, err = store.CreateCheckIfNoActive(ctx, instanceID, "bbb", "Torrent B", "manual")
+	require.ErrorIs(t, err, models.ErrIntegrityCheckActive)
+
+	require.NoError(t, store.UpdateCheckProgress(ctx, checkID, 10, 4, 4096))
+	check, err := store.GetCheckByInstance(ctx, instanceID, checkID)
+	require.NoError(t, err)
+	require.Equal(t, models.IntegrityCheckStatusRunning, check.Status)
+	require.Equal(t, 4, check.PiecesChecked)
+	require.Empty(t, check.BadFiles)
+
+	require.NoError(t, store.CompleteCheck(ctx, &models.IntegrityCheck{
+		ID:            checkID,
+		Status:        models.IntegrityCheckStatusFailed,
+		PiecesTotal:   10,
+		PiecesChecked: 10,
+		CorruptPieces: 1,
+		BytesChecked:  10240,
+		BadFiles:      []models.IntegrityBadFile{{Path: "a/file.bin", CorruptPieces: 1}},
+	}))
+
+	check, err = store.GetCheckByInstance(ctx, instanceID, checkID)
+	require.NoError(t, err)
+	require.Equal(t, models.IntegrityCheckStatusFailed, check.Status)
+	require.NotNil(t, check.CompletedAt)
+	require.Equal(t, []models.IntegrityBadFile{{Path: "a/file.bin", CorruptPieces: 1}}, check.BadFiles)
+
+	verified, err := store.GetLastVerifiedTimes(ctx, instanceID)
+	require.NoError(t, err)
+	require.Contains(t, verified, "aaa")
+
+	lastScheduled, err := store.GetLastStartedAt(ctx, instanceID, "scheduled")
+	require.NoError(t, err)
+	require.Nil(t, lastScheduled)
+
+	// A finished check frees the slot; a restart fails what was left running.
+	secondID, err := store.CreateCheckIfNoActive(ctx, instanceID, "bbb", "Torrent B", "scheduled")
+	require.NoError(t, err)
+	require.NoError(t, store.MarkRunningChecksInterrupted(ctx))
+
+	check, err = store.GetCheckByInstance(ctx, instanceID, secondID)
+	require.NoError(t, err)
+	require.Equal(t, models.IntegrityCheckStatusError, check.Status)
+
+	require.NoError(t, store.PruneChecks(ctx, instanceID, 1))
+	checks, err := store.ListChecks(ctx, instanceID, 10)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, secondID, checks[0].ID)
+
+	missing, err := store.GetCheckByInstance(ctx, instanceID+1, secondID)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
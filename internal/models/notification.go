@@ -0,0 +1,409 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// NotificationProviderType identifies how a notification is delivered.
+type NotificationProviderType string
+
+const (
+	NotificationProviderWebhook NotificationProviderType = "webhook"
+	NotificationProviderDiscord NotificationProviderType = "discord"
+	NotificationProviderNtfy    NotificationProviderType = "ntfy"
+	NotificationProviderGotify  NotificationProviderType = "gotify"
+	NotificationProviderApprise NotificationProviderType = "apprise"
+)
+
+// IsValid reports whether the provider type is supported.
+func (t NotificationProviderType) IsValid() bool {
+	switch t {
+	case NotificationProviderWebhook, NotificationProviderDiscord, NotificationProviderNtfy,
+		NotificationProviderGotify, NotificationProviderApprise:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationEventType identifies what happened.
+type NotificationEventType string
+
+const (
+	NotificationEventInstanceError          NotificationEventType = "instance_error"
+	NotificationEventCrossSeedInjected      NotificationEventType = "cross_seed_injected"
+	NotificationEventAutomationDeleted      NotificationEventType = "automation_deleted"
+	NotificationEventBackupFailed           NotificationEventType = "backup_failed"
+	NotificationEventOrphanScanPreviewReady NotificationEventType = "orphan_scan_preview_ready"
+	NotificationEventTest                   NotificationEventType = "test"
+)
+
+// NotificationEventTypes lists the events providers can subscribe to.
+func NotificationEventTypes() []NotificationEventType {
+	return []NotificationEventType{
+		NotificationEventInstanceError,
+		NotificationEventCrossSeedInjected,
+		NotificationEventAutomationDeleted,
+		NotificationEventBackupFailed,
+		NotificationEventOrphanScanPreviewReady,
+	}
+}
+
+// IsValid reports whether the event type can be subscribed to.
+func (e NotificationEventType) IsValid() bool {
+	return slices.Contains(NotificationEventTypes(), e)
+}
+
+// NotificationProvider is a configured notification target.
+type NotificationProvider struct {
+	ID             int                      `json:"id"`
+	Name           string                   `json:"name"`
+	Type           NotificationProviderType `json:"type"`
+	URL            string                   `json:"url"`
+	TokenEncrypted string                   `json:"-"`
+	HasToken       bool                     `json:"hasToken"`
+	Options        map[string]string        `json:"options"`
+	Events         []NotificationEventType  `json:"events"`      // empty means all events
+	InstanceIDs    []int                    `json:"instanceIds"` // empty means all instances
+	TitleTemplate  string                   `json:"titleTemplate"`
+	BodyTemplate   string                   `json:"bodyTemplate"`
+	Enabled        bool                     `json:"enabled"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	UpdatedAt      time.Time                `json:"updatedAt"`
+}
+
+// Subscribed reports whether the provider wants the event. Instance-less events
+// (instanceID 0) are delivered regardless of the instance filter.
+func (p *NotificationProvider) Subscribed(event NotificationEventType, instanceID int) bool {
+	if !p.Enabled {
+		return false
+	}
+	if len(p.Events) > 0 && !slices.Contains(p.Events, event) {
+		return false
+	}
+	if instanceID > 0 && len(p.InstanceIDs) > 0 && !slices.Contains(p.InstanceIDs, instanceID) {
+		return false
+	}
+	return true
+}
+
+// NotificationProviderStore manages notification providers in the database.
+type NotificationProviderStore struct {
+	db            dbinterface.Querier
+	encryptionKey []byte
+}
+
+// NewNotificationProviderStore creates a new NotificationProviderStore.
+func NewNotificationProviderStore(db dbinterface.Querier, encryptionKey []byte) (*NotificationProviderStore, error) {
+	if len(encryptionKey) != 32 {
+		return nil, errors.New("encryption key must be 32 bytes")
+	}
+
+	return &NotificationProviderStore{
+		db:            db,
+		encryptionKey: encryptionKey,
+	}, nil
+}
+
+// encrypt encrypts a string using AES-GCM
+func (s *NotificationProviderStore) encrypt(plaintext string) (string, error) {
+	block, err := aes.NewCipher(s.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decrypt decrypts a string encrypted with encrypt
+func (s *NotificationProviderStore) decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(s.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("malformed ciphertext")
+	}
+
+	nonce, ciphertextBytes := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertextBytes, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+const notificationProviderSelect = `
+	SELECT id, name, type, url, token_encrypted, options, events, instance_ids,
+	       title_template, body_template, enabled, created_at, updated_at
+	FROM notification_providers
+`
+
+// List returns all notification providers.
+func (s *NotificationProviderStore) List(ctx context.Context) ([]*NotificationProvider, error) {
+	return s.query(ctx, notificationProviderSelect+` ORDER BY name`)
+}
+
+// ListEnabled returns the providers that should receive notifications.
+func (s *NotificationProviderStore) ListEnabled(ctx context.Context) ([]*NotificationProvider, error) {
+	return s.query(ctx, notificationProviderSelect+` WHERE enabled = 1 ORDER BY name`)
+}
+
+// Get returns a provider by ID or sql.ErrNoRows.
+func (s *NotificationProviderStore) Get(ctx context.Context, id int) (*NotificationProvider, error) {
+	row := s.db.QueryRowContext(ctx, notificationProviderSelect+` WHERE id = ?`, id)
+	return scanNotificationProvider(row)
+}
+
+// Create inserts a provider. The token is stored encrypted.
+func (s *NotificationProviderStore) Create(ctx context.Context, provider *NotificationProvider, token string) (*NotificationProvider, error) {
+	if provider == nil {
+		return nil, errors.New("provider is nil")
+	}
+
+	tokenEncrypted, err := s.encryptToken(token)
+	if err != nil {
+		return nil, err
+	}
+	options, events, instanceIDs, err := encodeNotificationProviderLists(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO notification_providers (name, type, url, token_encrypted, options, events, instance_ids, title_template, body_template, enabled)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, provider.Name, string(provider.Type), provider.URL, tokenEncrypted, options, events, instanceIDs,
+		provider.TitleTemplate, provider.BodyTemplate, provider.Enabled)
+	if err != nil {
+		return nil, fmt.Errorf("insert notification provider: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("fetch notification provider id: %w", err)
+	}
+
+	return s.Get(ctx, int(id))
+}
+
+// Update replaces a provider's configuration. A nil token keeps the stored token;
+// an empty token clears it.
+func (s *NotificationProviderStore) Update(ctx context.Context, provider *NotificationProvider, token *string) (*NotificationProvider, error) {
+	if provider == nil {
+		return nil, errors.New("provider is nil")
+	}
+
+	options, events, instanceIDs, err := encodeNotificationProviderLists(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE notification_providers
+		SET name = ?, type = ?, url = ?, options = ?, events = ?, instance_ids = ?,
+		    title_template = ?, body_template = ?, enabled = ?
+	`
+	args := []any{provider.Name, string(provider.Type), provider.URL, options, events, instanceIDs,
+		provider.TitleTemplate, provider.BodyTemplate, provider.Enabled}
+
+	if token != nil {
+		tokenEncrypted, err := s.encryptToken(*token)
+		if err != nil {
+			return nil, err
+		}
+		query += `, token_encrypted = ?`
+		args = append(args, tokenEncrypted)
+	}
+
+	query += ` WHERE id = ?`
+	args = append(args, provider.ID)
+
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("update notification provider: %w", err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.Get(ctx, provider.ID)
+}
+
+// Delete removes a provider.
+func (s *NotificationProviderStore) Delete(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notification_providers WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetDecryptedToken returns the provider's decrypted token, or "" when none is set.
+func (s *NotificationProviderStore) GetDecryptedToken(provider *NotificationProvider) (string, error) {
+	if provider == nil || provider.TokenEncrypted == "" {
+		return "", nil
+	}
+	return s.decrypt(provider.TokenEncrypted)
+}
+
+func (s *NotificationProviderStore) encryptToken(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	encrypted, err := s.encrypt(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt token: %w", err)
+	}
+	return encrypted, nil
+}
+
+func (s *NotificationProviderStore) query(ctx context.Context, query string, args ...any) ([]*NotificationProvider, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list notification providers: %w", err)
+	}
+	defer rows.Close()
+
+	providers := []*NotificationProvider{}
+	for rows.Next() {
+		provider, err := scanNotificationProvider(rows)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+func encodeNotificationProviderLists(provider *NotificationProvider) (options, events, instanceIDs string, err error) {
+	opts := provider.Options
+	if opts == nil {
+		opts = map[string]string{}
+	}
+	evts := provider.Events
+	if evts == nil {
+		evts = []NotificationEventType{}
+	}
+	ids := provider.InstanceIDs
+	if ids == nil {
+		ids = []int{}
+	}
+
+	optionsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return "", "", "", fmt.Errorf("marshal options: %w", err)
+	}
+	eventsJSON, err := json.Marshal(evts)
+	if err != nil {
+		return "", "", "", fmt.Errorf("marshal events: %w", err)
+	}
+	idsJSON, err := json.Marshal(ids)
+	if err != nil {
+		return "", "", "", fmt.Errorf("marshal instance ids: %w", err)
+	}
+
+	return string(optionsJSON), string(eventsJSON), string(idsJSON), nil
+}
+
+func scanNotificationProvider(scanner interface {
+	Scan(dest ...any) error
+}) (*NotificationProvider, error) {
+	var (
+		provider                     NotificationProvider
+		providerType                 string
+		options, events, instanceIDs string
+	)
+
+	if err := scanner.Scan(
+		&provider.ID,
+		&provider.Name,
+		&providerType,
+		&provider.URL,
+		&provider.TokenEncrypted,
+		&options,
+		&events,
+		&instanceIDs,
+		&provider.TitleTemplate,
+		&provider.BodyTemplate,
+		&provider.Enabled,
+		&provider.CreatedAt,
+		&provider.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	provider.Type = NotificationProviderType(providerType)
+	provider.HasToken = provider.TokenEncrypted != ""
+
+	if err := json.Unmarshal([]byte(options), &provider.Options); err != nil {
+		return nil, fmt.Errorf("unmarshal notification options: %w", err)
+	}
+	if err := json.Unmarshal([]byte(events), &provider.Events); err != nil {
+		return nil, fmt.Errorf("unmarshal notification events: %w", err)
+	}
+	if err := json.Unmarshal([]byte(instanceIDs), &provider.InstanceIDs); err != nil {
+		return nil, fmt.Errorf("unmarshal notification instance ids: %w", err)
+	}
+	if provider.Options == nil {
+		provider.Options = map[string]string{}
+	}
+	if provider.Events == nil {
+		provider.Events = []NotificationEventType{}
+	}
+	if provider.InstanceIDs == nil {
+		provider.InstanceIDs = []int{}
+	}
+
+	return &provider, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func newTestNotificationProviderStore(t *testing.T) (*models.NotificationProviderStore, int) {
+	t.Helper()
+	db := setupCrossSeedTestDB(t)
+	store, err := models.NewNotificationProviderStore(db, make([]byte, 32))
+	require.NoError(t, err)
+	return store, insertTestInstance(t, db, "notify-instance")
+}
+
+func TestNewNotificationProviderStore_RequiresKey(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	_, err := models.NewNotificationProviderStore(db, []byte("short"))
+	require.Error(t, err)
+}
+
+func TestNotificationProviderStore_CRUD(t *testing.T) {
+	store, instanceID := newTestNotificationProviderStore(t)
+	ctx := context.Background()
+
+	created, err := store.Create(ctx, &models.NotificationProvider{
+		Name:          "ntfy",
+		Type:          models.NotificationProviderNtfy,
+		URL:           "https://ntfy.example/qui",
+		Options:       map[string]string{"priority": "high"},
+		Events:        []models.NotificationEventType{models.NotificationEventBackupFailed},
+		InstanceIDs:   []int{instanceID},
+		TitleTemplate: "{{ .Title }}",
+		Enabled:       true,
+	}, "secret-token")
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.True(t, created.HasToken)
+	assert.NotEqual(t, "secret-token", created.TokenEncrypted)
+	assert.Equal(t, map[string]string{"priority": "high"}, created.Options)
+	assert.Equal(t, []models.NotificationEventType{models.NotificationEventBackupFailed}, created.Events)
+	assert.Equal(t, []int{instanceID}, created.InstanceIDs)
+
+	token, err := store.GetDecryptedToken(created)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", token)
+
+	_, err = store.Create(ctx, &models.NotificationProvider{
+		Name: "disabled",
+		Type: models.NotificationProviderWebhook,
+		URL:  "https://hooks.example/x",
+	}, "")
+	require.NoError(t, err)
+
+	all, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	enabled, err := store.ListEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 1)
+	assert.Equal(t, created.ID, enabled[0].ID)
+
+	// A nil token keeps the stored value.
+	created.Name = "ntfy-renamed"
+	updated, err := store.Update(ctx, created, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ntfy-renamed", updated.Name)
+	token, err = store.GetDecryptedToken(updated)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", token)
+
+	// An empty token clears it.
+	empty := ""
+	updated, err = store.Update(ctx, created, &empty)
+	require.NoError(t, err)
+	assert.False(t, updated.HasToken)
+
+	_, err = store.Update(ctx, &models.NotificationProvider{ID: 9999, Name: "x", Type: models.NotificationProviderWebhook}, nil)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, store.Delete(ctx, created.ID))
+	require.ErrorIs(t, store.Delete(ctx, created.ID), sql.ErrNoRows)
+	_, err = store.Get(ctx, created.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestNotificationProvider_Subscribed(t *testing.T) {
+	provider := &models.NotificationProvider{
+		Enabled:     true,
+		Events:      []models.NotificationEventType{models.NotificationEventInstanceError},
+		InstanceIDs: []int{1},
+	}
+
+	assert.True(t, provider.Subscribed(models.NotificationEventInstanceError, 1))
+	assert.False(t, provider.Subscribed(models.NotificationEventInstanceError, 2))
+	assert.False(t, provider.Subscribed(models.NotificationEventBackupFailed, 1))
+	assert.True(t, provider.Subscribed(models.NotificationEventInstanceError, 0), "instance-less events ignore the instance filter")
+
+	provider.Events = nil
+	provider.InstanceIDs = nil
+	assert.True(t, provider.Subscribed(models.NotificationEventOrphanScanPreviewReady, 5))
+
+	provider.Enabled = false
+	assert.False(t, provider.Subscribed(models.NotificationEventInstanceError, 1))
+}
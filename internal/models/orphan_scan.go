@@ -16,34 +16,66 @@ import (
 
 // OrphanScanSettings represents orphan scan settings for an instance.
 type OrphanScanSettings struct {
-	ID                  int64     `json:"id"`
-	InstanceID          int       `json:"instanceId"`
-	Enabled             bool      `json:"enabled"`
-	GracePeriodMinutes  int       `json:"gracePeriodMinutes"`
-	IgnorePaths         []string  `json:"ignorePaths"`
-	ScanIntervalHours   int       `json:"scanIntervalHours"`
-	MaxFilesPerRun      int       `json:"maxFilesPerRun"`
-	AutoCleanupEnabled  bool      `json:"autoCleanupEnabled"`
-	AutoCleanupMaxFiles int       `json:"autoCleanupMaxFiles"`
-	CreatedAt           time.Time `json:"createdAt"`
-	UpdatedAt           time.Time `json:"updatedAt"`
+	ID                  int64    `json:"id"`
+	InstanceID          int      `json:"instanceId"`
+	Enabled             bool     `json:"enabled"`
+	GracePeriodMinutes  int      `json:"gracePeriodMinutes"`
+	IgnorePaths         []string `json:"ignorePaths"`
+	ScanIntervalHours   int      `json:"scanIntervalHours"`
+	MaxFilesPerRun      int      `json:"maxFilesPerRun"`
+	AutoCleanupEnabled  bool     `json:"autoCleanupEnabled"`
+	AutoCleanupMaxFiles int      `json:"autoCleanupMaxFiles"`
+	// Per link-class auto-cleanup policy; classes that are off are left for manual review.
+	AutoCleanupUnique              bool `json:"autoCleanupUnique"`
+	AutoCleanupHardlinkedOrphans   bool `json:"autoCleanupHardlinkedOrphans"`
+	AutoCleanupHardlinkedElsewhere bool `json:"autoCleanupHardlinkedElsewhere"`
+	// QuarantineEnabled moves confirmed orphans into a per-root quarantine
+	// directory instead of deleting them; they are purged after QuarantineRetentionDays.
+	QuarantineEnabled       bool `json:"quarantineEnabled"`
+	QuarantineRetentionDays int  `json:"quarantineRetentionDays"`
+	// SharedInstances lists other instances storing torrents under this instance's
+	// scan roots; their files are never treated as orphans.
+	SharedInstances []OrphanScanSharedInstance `json:"sharedInstances"`
+	CreatedAt       time.Time                  `json:"createdAt"`
+	UpdatedAt       time.Time                  `json:"updatedAt"`
+}
+
+// OrphanScanSharedInstance is another instance whose torrents share storage with
+// the scanned instance.
+type OrphanScanSharedInstance struct {
+	InstanceID int `json:"instanceId"`
+	// PathMappings translate the shared instance's save paths into the paths
+	// seen by the scanned instance when the disk is mounted differently.
+	PathMappings []OrphanScanPathMapping `json:"pathMappings"`
+}
+
+// OrphanScanPathMapping rewrites paths starting with From to start with To.
+type OrphanScanPathMapping struct {
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 // OrphanScanRun represents an orphan scan run.
 type OrphanScanRun struct {
-	ID             int64      `json:"id"`
-	InstanceID     int        `json:"instanceId"`
-	Status         string     `json:"status"` // pending, scanning, preview_ready, deleting, completed, failed, canceled
-	TriggeredBy    string     `json:"triggeredBy"`
-	ScanPaths      []string   `json:"scanPaths"`
-	FilesFound     int        `json:"filesFound"`
-	FilesDeleted   int        `json:"filesDeleted"`
-	FoldersDeleted int        `json:"foldersDeleted"`
-	BytesReclaimed int64      `json:"bytesReclaimed"`
-	Truncated      bool       `json:"truncated"`
-	ErrorMessage   string     `json:"errorMessage,omitempty"`
-	StartedAt      time.Time  `json:"startedAt"`
-	CompletedAt    *time.Time `json:"completedAt,omitempty"`
+	ID             int64    `json:"id"`
+	InstanceID     int      `json:"instanceId"`
+	Status         string   `json:"status"` // pending, scanning, preview_ready, deleting, completed, failed, canceled
+	TriggeredBy    string   `json:"triggeredBy"`
+	ScanPaths      []string `json:"scanPaths"`
+	FilesFound     int      `json:"filesFound"`
+	FilesDeleted   int      `json:"filesDeleted"`
+	FoldersDeleted int      `json:"foldersDeleted"`
+	BytesReclaimed int64    `json:"bytesReclaimed"`
+	// BytesReclaimable estimates the space deleting the run's orphans frees, excluding
+	// files whose data is still referenced by hardlinks outside the orphan set.
+	BytesReclaimable int64 `json:"bytesReclaimable"`
+	// FilesQuarantined and BytesQuarantined count files still held in quarantine.
+	FilesQuarantined int        `json:"filesQuarantined"`
+	BytesQuarantined int64      `json:"bytesQuarantined"`
+	Truncated        bool       `json:"truncated"`
+	ErrorMessage     string     `json:"errorMessage,omitempty"`
+	StartedAt        time.Time  `json:"startedAt"`
+	CompletedAt      *time.Time `json:"completedAt,omitempty"`
 }
 
 // OrphanScanFile represents an orphan file found in a scan.
@@ -53,8 +85,11 @@ type OrphanScanFile struct {
 	FilePath     string     `json:"filePath"`
 	FileSize     int64      `json:"fileSize"`
 	ModifiedAt   *time.Time `json:"modifiedAt,omitempty"`
-	Status       string     `json:"status"` // pending, deleted, skipped, failed
+	Status       string     `json:"status"`    // pending, deleted, skipped, failed, quarantined, restored
+	LinkClass    string     `json:"linkClass"` // unique, hardlinked_orphan, hardlinked_elsewhere
 	ErrorMessage string     `json:"errorMessage,omitempty"`
+	// QuarantinePath is where the file was moved while quarantined.
+	QuarantinePath string `json:"quarantinePath,omitempty"`
 }
 
 // OrphanScanStore handles database operations for orphan scan.
@@ -73,13 +108,16 @@ func (s *OrphanScanStore) GetSettings(ctx context.Context, instanceID int) (*Orp
 	row := s.db.QueryRowContext(ctx, `
 		SELECT id, instance_id, enabled, grace_period_minutes, ignore_paths,
 		       scan_interval_hours, max_files_per_run, auto_cleanup_enabled,
-		       auto_cleanup_max_files, created_at, updated_at
+		       auto_cleanup_max_files, auto_cleanup_unique, auto_cleanup_hardlinked_orphans,
+		       auto_cleanup_hardlinked_elsewhere, quarantine_enabled, quarantine_retention_days,
+		       shared_instances, created_at, updated_at
 		FROM orphan_scan_settings
 		WHERE instance_id = ?
 	`, instanceID)
 
 	var settings OrphanScanSettings
 	var ignorePathsJSON sql.NullString
+	var sharedInstancesJSON sql.NullString
 
 	err := row.Scan(
 		&settings.ID,
@@ -91,6 +129,12 @@ func (s *OrphanScanStore) GetSettings(ctx context.Context, instanceID int) (*Orp
 		&settings.MaxFilesPerRun,
 		&settings.AutoCleanupEnabled,
 		&settings.AutoCleanupMaxFiles,
+		&settings.AutoCleanupUnique,
+		&settings.AutoCleanupHardlinkedOrphans,
+		&settings.AutoCleanupHardlinkedElsewhere,
+		&settings.QuarantineEnabled,
+		&settings.QuarantineRetentionDays,
+		&sharedInstancesJSON,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
 	)
@@ -110,6 +154,15 @@ func (s *OrphanScanStore) GetSettings(ctx context.Context, instanceID int) (*Orp
 		settings.IgnorePaths = []string{}
 	}
 
+	if sharedInstancesJSON.Valid && sharedInstancesJSON.String != "" {
+		if err := json.Unmarshal([]byte(sharedInstancesJSON.String), &settings.SharedInstances); err != nil {
+			return nil, err
+		}
+	}
+	if settings.SharedInstances == nil {
+		settings.SharedInstances = []OrphanScanSharedInstance{}
+	}
+
 	return &settings, nil
 }
 
@@ -124,11 +177,22 @@ func (s *OrphanScanStore) UpsertSettings(ctx context.Context, settings *OrphanSc
 		return nil, err
 	}
 
+	sharedInstances := settings.SharedInstances
+	if sharedInstances == nil {
+		sharedInstances = []OrphanScanSharedInstance{}
+	}
+	sharedInstancesJSON, err := json.Marshal(sharedInstances)
+	if err != nil {
+		return nil, err
+	}
+
 	_, err = s.db.ExecContext(ctx, `
 		INSERT INTO orphan_scan_settings
 			(instance_id, enabled, grace_period_minutes, ignore_paths, scan_interval_hours,
-			 max_files_per_run, auto_cleanup_enabled, auto_cleanup_max_files)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			 max_files_per_run, auto_cleanup_enabled, auto_cleanup_max_files,
+			 auto_cleanup_unique, auto_cleanup_hardlinked_orphans, auto_cleanup_hardlinked_elsewhere,
+			 quarantine_enabled, quarantine_retention_days, shared_instances)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(instance_id) DO UPDATE SET
 			enabled = excluded.enabled,
 			grace_period_minutes = excluded.grace_period_minutes,
@@ -136,10 +200,20 @@ func (s *OrphanScanStore) UpsertSettings(ctx context.Context, settings *OrphanSc
 			scan_interval_hours = excluded.scan_interval_hours,
 			max_files_per_run = excluded.max_files_per_run,
 			auto_cleanup_enabled = excluded.auto_cleanup_enabled,
-			auto_cleanup_max_files = excluded.auto_cleanup_max_files
+			auto_cleanup_max_files = excluded.auto_cleanup_max_files,
+			auto_cleanup_unique = excluded.auto_cleanup_unique,
+			auto_cleanup_hardlinked_orphans = excluded.auto_cleanup_hardlinked_orphans,
+			auto_cleanup_hardlinked_elsewhere = excluded.auto_cleanup_hardlinked_elsewhere,
+			quarantine_enabled = excluded.quarantine_enabled,
+			quarantine_retention_days = excluded.quarantine_retention_days,
+			shared_instances = excluded.shared_instances
 	`, settings.InstanceID, boolToInt(settings.Enabled), settings.GracePeriodMinutes,
 		string(ignorePathsJSON), settings.ScanIntervalHours, settings.MaxFilesPerRun,
-		boolToInt(settings.AutoCleanupEnabled), settings.AutoCleanupMaxFiles)
+		boolToInt(settings.AutoCleanupEnabled), settings.AutoCleanupMaxFiles,
+		boolToInt(settings.AutoCleanupUnique), boolToInt(settings.AutoCleanupHardlinkedOrphans),
+		boolToInt(settings.AutoCleanupHardlinkedElsewhere),
+		boolToInt(settings.QuarantineEnabled), settings.QuarantineRetentionDays,
+		string(sharedInstancesJSON))
 	if err != nil {
 		return nil, err
 	}
@@ -202,8 +276,8 @@ func (s *OrphanScanStore) CreateRunIfNoActive(ctx context.Context, instanceID in
 func (s *OrphanScanStore) GetRun(ctx context.Context, runID int64) (*OrphanScanRun, error) {
 	row := s.db.QueryRowContext(ctx, `
 		SELECT id, instance_id, status, triggered_by, scan_paths, files_found,
-		       files_deleted, folders_deleted, bytes_reclaimed, truncated,
-		       error_message, started_at, completed_at
+		       files_deleted, folders_deleted, bytes_reclaimed, bytes_reclaimable, files_quarantined,
+		       bytes_quarantined, truncated, error_message, started_at, completed_at
 		FROM orphan_scan_runs
 		WHERE id = ?
 	`, runID)
@@ -215,8 +289,8 @@ func (s *OrphanScanStore) GetRun(ctx context.Context, runID int64) (*OrphanScanR
 func (s *OrphanScanStore) GetRunByInstance(ctx context.Context, instanceID int, runID int64) (*OrphanScanRun, error) {
 	row := s.db.QueryRowContext(ctx, `
 		SELECT id, instance_id, status, triggered_by, scan_paths, files_found,
-		       files_deleted, folders_deleted, bytes_reclaimed, truncated,
-		       error_message, started_at, completed_at
+		       files_deleted, folders_deleted, bytes_reclaimed, bytes_reclaimable, files_quarantined,
+		       bytes_quarantined, truncated, error_message, started_at, completed_at
 		FROM orphan_scan_runs
 		WHERE id = ? AND instance_id = ?
 	`, runID, instanceID)
@@ -240,6 +314,9 @@ func (s *OrphanScanStore) scanRun(row *sql.Row) (*OrphanScanRun, error) {
 		&run.FilesDeleted,
 		&run.FoldersDeleted,
 		&run.BytesReclaimed,
+		&run.BytesReclaimable,
+		&run.FilesQuarantined,
+		&run.BytesQuarantined,
 		&run.Truncated,
 		&errorMessage,
 		&run.StartedAt,
@@ -274,8 +351,8 @@ func (s *OrphanScanStore) scanRun(row *sql.Row) (*OrphanScanRun, error) {
 func (s *OrphanScanStore) ListRuns(ctx context.Context, instanceID int, limit int) ([]*OrphanScanRun, error) {
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT id, instance_id, status, triggered_by, scan_paths, files_found,
-		       files_deleted, folders_deleted, bytes_reclaimed, truncated,
-		       error_message, started_at, completed_at
+		       files_deleted, folders_deleted, bytes_reclaimed, bytes_reclaimable, files_quarantined,
+		       bytes_quarantined, truncated, error_message, started_at, completed_at
 		FROM orphan_scan_runs
 		WHERE instance_id = ?
 		ORDER BY started_at DESC
@@ -303,6 +380,9 @@ func (s *OrphanScanStore) ListRuns(ctx context.Context, instanceID int, limit in
 			&run.FilesDeleted,
 			&run.FoldersDeleted,
 			&run.BytesReclaimed,
+			&run.BytesReclaimable,
+			&run.FilesQuarantined,
+			&run.BytesQuarantined,
 			&run.Truncated,
 			&errorMessage,
 			&run.StartedAt,
@@ -336,8 +416,8 @@ func (s *OrphanScanStore) ListRuns(ctx context.Context, instanceID int, limit in
 func (s *OrphanScanStore) GetLastCompletedRun(ctx context.Context, instanceID int) (*OrphanScanRun, error) {
 	row := s.db.QueryRowContext(ctx, `
 		SELECT id, instance_id, status, triggered_by, scan_paths, files_found,
-		       files_deleted, folders_deleted, bytes_reclaimed, truncated,
-		       error_message, started_at, completed_at
+		       files_deleted, folders_deleted, bytes_reclaimed, bytes_reclaimable, files_quarantined,
+		       bytes_quarantined, truncated, error_message, started_at, completed_at
 		FROM orphan_scan_runs
 		WHERE instance_id = ? AND status = 'completed'
 		ORDER BY completed_at DESC
@@ -404,6 +484,64 @@ func (s *OrphanScanStore) UpdateRunFoundStats(ctx context.Context, runID int64,
 	return err
 }
 
+// UpdateRunReclaimable sets the bytes deleting the run's orphans is expected to free.
+func (s *OrphanScanStore) UpdateRunReclaimable(ctx context.Context, runID int64, bytesReclaimable int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE orphan_scan_runs SET bytes_reclaimable = ? WHERE id = ?
+	`, bytesReclaimable, runID)
+	return err
+}
+
+// CountPendingFilesByLinkClass returns the number of pending files per link class for a run.
+func (s *OrphanScanStore) CountPendingFilesByLinkClass(ctx context.Context, runID int64) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT link_class, COUNT(*)
+		FROM orphan_scan_files
+		WHERE run_id = ? AND status = 'pending'
+		GROUP BY link_class
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var class string
+		var count int
+		if err := rows.Scan(&class, &count); err != nil {
+			return nil, err
+		}
+		counts[class] = count
+	}
+	return counts, rows.Err()
+}
+
+// SkipPendingFilesByLinkClass marks a run's pending files of the given link classes as skipped.
+func (s *OrphanScanStore) SkipPendingFilesByLinkClass(ctx context.Context, runID int64, classes []string, reason string) error {
+	if len(classes) == 0 {
+		return nil
+	}
+
+	placeholders := ""
+	args := make([]interface{}, 0, len(classes)+2)
+	args = append(args, reason, runID)
+	for i, class := range classes {
+		if i > 0 {
+			placeholders += ", "
+		}
+		placeholders += "?"
+		args = append(args, class)
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE orphan_scan_files
+		SET status = 'skipped', error_message = ?
+		WHERE run_id = ? AND status = 'pending' AND link_class IN (`+placeholders+`)
+	`, args...)
+	return err
+}
+
 // UpdateRunCompleted marks a run as completed with stats.
 func (s *OrphanScanStore) UpdateRunCompleted(ctx context.Context, runID int64, filesDeleted, foldersDeleted int, bytesReclaimed int64) error {
 	_, err := s.db.ExecContext(ctx, `
@@ -414,6 +552,87 @@ func (s *OrphanScanStore) UpdateRunCompleted(ctx context.Context, runID int64, f
 	return err
 }
 
+// UpdateRunQuarantined marks a run as completed after its files were moved into quarantine.
+// bytes_reclaimed stays at zero until the quarantine is purged.
+func (s *OrphanScanStore) UpdateRunQuarantined(ctx context.Context, runID int64, filesQuarantined, foldersDeleted int, bytesQuarantined int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE orphan_scan_runs
+		SET status = 'completed', files_deleted = 0, folders_deleted = ?, bytes_reclaimed = 0,
+		    files_quarantined = ?, bytes_quarantined = ?, completed_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, foldersDeleted, filesQuarantined, bytesQuarantined, runID)
+	return err
+}
+
+// RecordQuarantinePurged moves purged files from the quarantine counters to the deleted totals.
+// quarantinedBytes is the apparent size removed from quarantine; freedBytes is the space
+// actually released, which is lower when purged files were still hardlinked elsewhere.
+func (s *OrphanScanStore) RecordQuarantinePurged(ctx context.Context, runID int64, files int, quarantinedBytes, freedBytes int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE orphan_scan_runs
+		SET files_quarantined = MAX(files_quarantined - ?, 0),
+		    bytes_quarantined = MAX(bytes_quarantined - ?, 0),
+		    files_deleted = files_deleted + ?,
+		    bytes_reclaimed = bytes_reclaimed + ?
+		WHERE id = ?
+	`, files, quarantinedBytes, files, freedBytes, runID)
+	return err
+}
+
+// RecordQuarantineRestored removes restored files from the quarantine counters.
+func (s *OrphanScanStore) RecordQuarantineRestored(ctx context.Context, runID int64, files int, bytes int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE orphan_scan_runs
+		SET files_quarantined = MAX(files_quarantined - ?, 0),
+		    bytes_quarantined = MAX(bytes_quarantined - ?, 0)
+		WHERE id = ?
+	`, files, bytes, runID)
+	return err
+}
+
+// ListQuarantinedRuns returns completed runs for an instance that still hold
+// quarantined files and finished before the cutoff.
+func (s *OrphanScanStore) ListQuarantinedRuns(ctx context.Context, instanceID int, completedBefore time.Time) ([]*OrphanScanRun, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id
+		FROM orphan_scan_runs
+		WHERE instance_id = ? AND status = 'completed' AND files_quarantined > 0
+		  AND completed_at < ?
+		ORDER BY completed_at
+	`, instanceID, completedBefore.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	runs := make([]*OrphanScanRun, 0, len(ids))
+	for _, id := range ids {
+		run, err := s.GetRun(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if run != nil {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
 // UpdateRunFailed marks a run as failed with an error message.
 func (s *OrphanScanStore) UpdateRunFailed(ctx context.Context, runID int64, errorMessage string) error {
 	_, err := s.db.ExecContext(ctx, `
@@ -477,18 +696,22 @@ func (s *OrphanScanStore) InsertFiles(ctx context.Context, runID int64, files []
 		}
 		batch := files[i:end]
 
-		query := `INSERT INTO orphan_scan_files (run_id, file_path, file_size, modified_at, status) VALUES `
-		args := make([]interface{}, 0, len(batch)*5)
+		query := `INSERT INTO orphan_scan_files (run_id, file_path, file_size, modified_at, status, link_class) VALUES `
+		args := make([]interface{}, 0, len(batch)*6)
 		for j, f := range batch {
 			if j > 0 {
 				query += ", "
 			}
-			query += "(?, ?, ?, ?, ?)"
+			query += "(?, ?, ?, ?, ?, ?)"
 			var modifiedAt interface{}
 			if f.ModifiedAt != nil {
 				modifiedAt = *f.ModifiedAt
 			}
-			args = append(args, runID, f.FilePath, f.FileSize, modifiedAt, f.Status)
+			linkClass := f.LinkClass
+			if linkClass == "" {
+				linkClass = "unique"
+			}
+			args = append(args, runID, f.FilePath, f.FileSize, modifiedAt, f.Status, linkClass)
 		}
 
 		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
@@ -502,7 +725,7 @@ func (s *OrphanScanStore) InsertFiles(ctx context.Context, runID int64, files []
 // ListFiles lists orphan files for a run with pagination.
 func (s *OrphanScanStore) ListFiles(ctx context.Context, runID int64, limit, offset int) ([]*OrphanScanFile, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, run_id, file_path, file_size, modified_at, status, error_message
+		SELECT id, run_id, file_path, file_size, modified_at, status, error_message, quarantine_path, link_class
 		FROM orphan_scan_files
 		WHERE run_id = ?
 		ORDER BY file_size DESC
@@ -518,6 +741,7 @@ func (s *OrphanScanStore) ListFiles(ctx context.Context, runID int64, limit, off
 		var f OrphanScanFile
 		var modifiedAt sql.NullTime
 		var errorMessage sql.NullString
+		var quarantinePath sql.NullString
 
 		if err := rows.Scan(
 			&f.ID,
@@ -527,6 +751,8 @@ func (s *OrphanScanStore) ListFiles(ctx context.Context, runID int64, limit, off
 			&modifiedAt,
 			&f.Status,
 			&errorMessage,
+			&quarantinePath,
+			&f.LinkClass,
 		); err != nil {
 			return nil, err
 		}
@@ -537,6 +763,9 @@ func (s *OrphanScanStore) ListFiles(ctx context.Context, runID int64, limit, off
 		if errorMessage.Valid {
 			f.ErrorMessage = errorMessage.String
 		}
+		if quarantinePath.Valid {
+			f.QuarantinePath = quarantinePath.String
+		}
 
 		files = append(files, &f)
 	}
@@ -549,7 +778,7 @@ func (s *OrphanScanStore) ListFiles(ctx context.Context, runID int64, limit, off
 // large orphan sets, consider adding batched retrieval here.
 func (s *OrphanScanStore) GetFilesForDeletion(ctx context.Context, runID int64) ([]*OrphanScanFile, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, run_id, file_path, file_size, modified_at, status, error_message
+		SELECT id, run_id, file_path, file_size, modified_at, status, error_message, quarantine_path, link_class
 		FROM orphan_scan_files
 		WHERE run_id = ? AND status = 'pending'
 		ORDER BY file_path
@@ -564,6 +793,7 @@ func (s *OrphanScanStore) GetFilesForDeletion(ctx context.Context, runID int64)
 		var f OrphanScanFile
 		var modifiedAt sql.NullTime
 		var errorMessage sql.NullString
+		var quarantinePath sql.NullString
 
 		if err := rows.Scan(
 			&f.ID,
@@ -573,6 +803,8 @@ func (s *OrphanScanStore) GetFilesForDeletion(ctx context.Context, runID int64)
 			&modifiedAt,
 			&f.Status,
 			&errorMessage,
+			&quarantinePath,
+			&f.LinkClass,
 		); err != nil {
 			return nil, err
 		}
@@ -583,6 +815,9 @@ func (s *OrphanScanStore) GetFilesForDeletion(ctx context.Context, runID int64)
 		if errorMessage.Valid {
 			f.ErrorMessage = errorMessage.String
 		}
+		if quarantinePath.Valid {
+			f.QuarantinePath = quarantinePath.String
+		}
 
 		files = append(files, &f)
 	}
@@ -590,6 +825,86 @@ func (s *OrphanScanStore) GetFilesForDeletion(ctx context.Context, runID int64)
 	return files, rows.Err()
 }
 
+// GetFilesByStatus returns all files for a run with the given status.
+func (s *OrphanScanStore) GetFilesByStatus(ctx context.Context, runID int64, status string) ([]*OrphanScanFile, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, run_id, file_path, file_size, modified_at, status, error_message, quarantine_path, link_class
+		FROM orphan_scan_files
+		WHERE run_id = ? AND status = ?
+		ORDER BY file_path
+	`, runID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []*OrphanScanFile
+	for rows.Next() {
+		f, err := scanOrphanScanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	return files, rows.Err()
+}
+
+// GetFile returns a single file belonging to a run, or nil if it does not exist.
+func (s *OrphanScanStore) GetFile(ctx context.Context, runID, fileID int64) (*OrphanScanFile, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, run_id, file_path, file_size, modified_at, status, error_message, quarantine_path, link_class
+		FROM orphan_scan_files
+		WHERE id = ? AND run_id = ?
+	`, fileID, runID)
+
+	f, err := scanOrphanScanFile(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return f, err
+}
+
+func scanOrphanScanFile(row interface{ Scan(dest ...any) error }) (*OrphanScanFile, error) {
+	var f OrphanScanFile
+	var modifiedAt sql.NullTime
+	var errorMessage sql.NullString
+	var quarantinePath sql.NullString
+
+	if err := row.Scan(
+		&f.ID,
+		&f.RunID,
+		&f.FilePath,
+		&f.FileSize,
+		&modifiedAt,
+		&f.Status,
+		&errorMessage,
+		&quarantinePath,
+		&f.LinkClass,
+	); err != nil {
+		return nil, err
+	}
+
+	if modifiedAt.Valid {
+		f.ModifiedAt = &modifiedAt.Time
+	}
+	if errorMessage.Valid {
+		f.ErrorMessage = errorMessage.String
+	}
+	if quarantinePath.Valid {
+		f.QuarantinePath = quarantinePath.String
+	}
+	return &f, nil
+}
+
+// MarkFileQuarantined records that a file was moved into quarantine.
+func (s *OrphanScanStore) MarkFileQuarantined(ctx context.Context, fileID int64, quarantinePath string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE orphan_scan_files SET status = 'quarantined', quarantine_path = ?, error_message = NULL WHERE id = ?
+	`, quarantinePath, fileID)
+	return err
+}
+
 // UpdateFileStatus updates the status of a single file.
 func (s *OrphanScanStore) UpdateFileStatus(ctx context.Context, fileID int64, status string, errorMessage string) error {
 	var errMsg interface{}
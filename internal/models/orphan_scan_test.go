@@ -0,0 +1,137 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestOrphanScanStore_QuarantineLifecycle(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	instanceID := insertTestInstance(t, db, "orphan-quarantine")
+	store := models.NewOrphanScanStore(db)
+	ctx := context.Background()
+
+	runID, err := store.CreateRun(ctx, instanceID, "manual")
+	require.NoError(t, err)
+	require.NoError(t, store.InsertFiles(ctx, runID, []models.OrphanScanFile{
+		{FilePath: "/data/a.mkv", FileSize: 100, Status: "pending"},
+		{FilePath: "/data/b.mkv", FileSize: 50, Status: "pending"},
+	}))
+	require.NoError(t, store.UpdateRunFoundStats(ctx, runID, 2, false, 150))
+
+	files, err := store.GetFilesForDeletion(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		require.NoError(t, store.MarkFileQuarantined(ctx, f.ID, "/data/.qui-quarantine/1"+f.FilePath[5:]))
+	}
+	require.NoError(t, store.UpdateRunQuarantined(ctx, runID, 2, 0, 150))
+
+	run, err := store.GetRun(ctx, runID)
+	require.NoError(t, err)
+	require.Equal(t, 2, run.FilesQuarantined)
+	require.Equal(t, int64(150), run.BytesQuarantined)
+	require.Zero(t, run.BytesReclaimed, "quarantined bytes must not count as reclaimed")
+
+	quarantined, err := store.GetFilesByStatus(ctx, runID, "quarantined")
+	require.NoError(t, err)
+	require.Len(t, quarantined, 2)
+	require.Equal(t, "/data/.qui-quarantine/1/a.mkv", quarantined[0].QuarantinePath)
+
+	// Not yet past retention
+	due, err := store.ListQuarantinedRuns(ctx, instanceID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	due, err = store.ListQuarantinedRuns(ctx, instanceID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	require.NoError(t, store.RecordQuarantineRestored(ctx, runID, 1, 50))
+	require.NoError(t, store.RecordQuarantinePurged(ctx, runID, 1, 100, 100))
+
+	run, err = store.GetRun(ctx, runID)
+	require.NoError(t, err)
+	require.Zero(t, run.FilesQuarantined)
+	require.Zero(t, run.BytesQuarantined)
+	require.Equal(t, 1, run.FilesDeleted)
+	require.Equal(t, int64(100), run.BytesReclaimed)
+
+	due, err = store.ListQuarantinedRuns(ctx, instanceID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, due)
+}
+
+func TestOrphanScanStore_SharedInstancesRoundTrip(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	instanceID := insertTestInstance(t, db, "orphan-shared-a")
+	peerID := insertTestInstance(t, db, "orphan-shared-b")
+	store := models.NewOrphanScanStore(db)
+	ctx := context.Background()
+
+	saved, err := store.UpsertSettings(ctx, &models.OrphanScanSettings{
+		InstanceID:         instanceID,
+		GracePeriodMinutes: 10,
+		ScanIntervalHours:  24,
+		MaxFilesPerRun:     100,
+		SharedInstances: []models.OrphanScanSharedInstance{
+			{InstanceID: peerID, PathMappings: []models.OrphanScanPathMapping{{From: "/downloads", To: "/mnt/shared"}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, saved.SharedInstances, 1)
+	require.Equal(t, peerID, saved.SharedInstances[0].InstanceID)
+	require.Equal(t, "/mnt/shared", saved.SharedInstances[0].PathMappings[0].To)
+
+	saved.SharedInstances = nil
+	saved, err = store.UpsertSettings(ctx, saved)
+	require.NoError(t, err)
+	require.NotNil(t, saved.SharedInstances)
+	require.Empty(t, saved.SharedInstances)
+}
+
+func TestOrphanScanStore_LinkClasses(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	instanceID := insertTestInstance(t, db, "orphan-link-classes")
+	store := models.NewOrphanScanStore(db)
+	ctx := context.Background()
+
+	runID, err := store.CreateRun(ctx, instanceID, "scheduled")
+	require.NoError(t, err)
+	require.NoError(t, store.InsertFiles(ctx, runID, []models.OrphanScanFile{
+		{FilePath: "/data/a.mkv", FileSize: 100, Status: "pending"},
+		{FilePath: "/data/b.mkv", FileSize: 50, Status: "pending", LinkClass: "hardlinked_orphan"},
+		{FilePath: "/data/c.mkv", FileSize: 25, Status: "pending", LinkClass: "hardlinked_elsewhere"},
+	}))
+	require.NoError(t, store.UpdateRunReclaimable(ctx, runID, 150))
+
+	run, err := store.GetRun(ctx, runID)
+	require.NoError(t, err)
+	require.Equal(t, int64(150), run.BytesReclaimable)
+
+	counts, err := store.CountPendingFilesByLinkClass(ctx, runID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"unique": 1, "hardlinked_orphan": 1, "hardlinked_elsewhere": 1}, counts)
+
+	require.NoError(t, store.SkipPendingFilesByLinkClass(ctx, runID, []string{"hardlinked_elsewhere"}, "kept"))
+
+	files, err := store.GetFilesForDeletion(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		require.NotEqual(t, "hardlinked_elsewhere", f.LinkClass)
+	}
+
+	skipped, err := store.GetFilesByStatus(ctx, runID, "skipped")
+	require.NoError(t, err)
+	require.Len(t, skipped, 1)
+	require.Equal(t, "/data/c.mkv", skipped[0].FilePath)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// ReannounceHistoryEntry is a persisted reannounce outcome for a torrent.
+type ReannounceHistoryEntry struct {
+	ID          int64  `json:"id"`
+	InstanceID  int    `json:"instanceId"`
+	Hash        string `json:"hash"`
+	TorrentName string `json:"torrentName"`
+	Trackers    string `json:"trackers"`
+	Outcome     string `json:"outcome"`
+	Reason      string `json:"reason"`
+	// TorrentAgeSeconds is the time since the torrent was added, when known.
+	TorrentAgeSeconds *int64    `json:"torrentAgeSeconds,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type ReannounceHistoryStore struct {
+	db dbinterface.Querier
+}
+
+func NewReannounceHistoryStore(db dbinterface.Querier) *ReannounceHistoryStore {
+	return &ReannounceHistoryStore{db: db}
+}
+
+func (s *ReannounceHistoryStore) Create(ctx context.Context, entry *ReannounceHistoryEntry) error {
+	if entry == nil {
+		return nil
+	}
+
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	var age sql.NullInt64
+	if entry.TorrentAgeSeconds != nil {
+		age = sql.NullInt64{Int64: *entry.TorrentAgeSeconds, Valid: true}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO reannounce_history
+			(instance_id, hash, torrent_name, trackers, outcome, reason, torrent_age_seconds, created_at)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.InstanceID, entry.Hash, entry.TorrentName, entry.Trackers, entry.Outcome, entry.Reason, age, createdAt.UTC())
+
+	return err
+}
+
+// ListByInstance returns the most recent entries for an instance, newest first.
+func (s *ReannounceHistoryStore) ListByInstance(ctx context.Context, instanceID int, limit int) ([]*ReannounceHistoryEntry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, instance_id, hash, torrent_name, trackers, outcome, reason, torrent_age_seconds, created_at
+		FROM reannounce_history
+		WHERE instance_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`, instanceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReannounceHistory(rows)
+}
+
+// ListSince returns all entries for an instance created at or after since, oldest first.
+func (s *ReannounceHistoryStore) ListSince(ctx context.Context, instanceID int, since time.Time) ([]*ReannounceHistoryEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, instance_id, hash, torrent_name, trackers, outcome, reason, torrent_age_seconds, created_at
+		FROM reannounce_history
+		WHERE instance_id = ? AND created_at >= ?
+		ORDER BY created_at ASC, id ASC
+	`, instanceID, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReannounceHistory(rows)
+}
+
+// Prune deletes entries created before the cutoff.
+func (s *ReannounceHistoryStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM reannounce_history
+		WHERE created_at < ?
+	`, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func scanReannounceHistory(rows *sql.Rows) ([]*ReannounceHistoryEntry, error) {
+	var entries []*ReannounceHistoryEntry
+	for rows.Next() {
+		var e ReannounceHistoryEntry
+		var torrentName, trackers, reason sql.NullString
+		var age sql.NullInt64
+
+		if err := rows.Scan(
+			&e.ID,
+			&e.InstanceID,
+			&e.Hash,
+			&torrentName,
+			&trackers,
+			&e.Outcome,
+			&reason,
+			&age,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		e.TorrentName = torrentName.String
+		e.Trackers = trackers.String
+		e.Reason = reason.String
+		if age.Valid {
+			value := age.Int64
+			e.TorrentAgeSeconds = &value
+		}
+
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestReannounceHistoryStore_ListAndPrune(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	instanceID := insertTestInstance(t, db, "reannounce-history")
+	store := models.NewReannounceHistoryStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	age := int64(90)
+	entries := []*models.ReannounceHistoryEntry{
+		{InstanceID: instanceID, Hash: "OLD", Trackers: "a.example", Outcome: "failed", CreatedAt: now.Add(-40 * 24 * time.Hour)},
+		{InstanceID: instanceID, Hash: "MID", Trackers: "a.example", Outcome: "succeeded", TorrentAgeSeconds: &age, CreatedAt: now.Add(-2 * time.Hour)},
+		{InstanceID: instanceID, Hash: "NEW", TorrentName: "New", Trackers: "b.example", Outcome: "failed", Reason: "timeout", CreatedAt: now},
+	}
+	for _, entry := range entries {
+		require.NoError(t, store.Create(ctx, entry))
+	}
+
+	recent, err := store.ListByInstance(ctx, instanceID, 2)
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	require.Equal(t, "NEW", recent[0].Hash)
+	require.Equal(t, "New", recent[0].TorrentName)
+	require.Equal(t, "timeout", recent[0].Reason)
+	require.Nil(t, recent[0].TorrentAgeSeconds)
+	require.Equal(t, "MID", recent[1].Hash)
+	require.NotNil(t, recent[1].TorrentAgeSeconds)
+	require.Equal(t, age, *recent[1].TorrentAgeSeconds)
+
+	since, err := store.ListSince(ctx, instanceID, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, since, 2)
+	require.Equal(t, "MID", since[0].Hash, "ListSince returns oldest first")
+
+	pruned, err := store.Prune(ctx, now.Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), pruned)
+
+	remaining, err := store.ListByInstance(ctx, instanceID, 0)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// StatsResolution identifies a downsampling tier of the statistics history.
+type StatsResolution string
+
+const (
+	StatsResolutionRaw  StatsResolution = "raw"
+	StatsResolutionHour StatsResolution = "hour"
+	StatsResolutionDay  StatsResolution = "day"
+)
+
+// IsValid reports whether the resolution is a known tier.
+func (r StatsResolution) IsValid() bool {
+	switch r {
+	case StatsResolutionRaw, StatsResolutionHour, StatsResolutionDay:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatsSample is one measurement of an instance. Uploaded and Downloaded are
+// the bytes transferred since the previous sample.
+type StatsSample struct {
+	InstanceID    int
+	Time          time.Time
+	Uploaded      int64
+	Downloaded    int64
+	UploadSpeed   int64
+	DownloadSpeed int64
+	Torrents      int
+	Seeding       int
+	Downloading   int
+	Ratio         float64
+	Trackers      []TrackerStatsSample
+}
+
+// TrackerStatsSample is the per-tracker part of a StatsSample.
+type TrackerStatsSample struct {
+	Tracker    string
+	Uploaded   int64
+	Downloaded int64
+	Torrents   int
+}
+
+// InstanceStatsPoint is one bucket of an instance's history.
+type InstanceStatsPoint struct {
+	Time          time.Time `json:"time"`
+	Uploaded      int64     `json:"uploaded"`
+	Downloaded    int64     `json:"downloaded"`
+	UploadSpeed   int64     `json:"uploadSpeed"`
+	DownloadSpeed int64     `json:"downloadSpeed"`
+	Torrents      int       `json:"torrents"`
+	Seeding       int       `json:"seeding"`
+	Downloading   int       `json:"downloading"`
+	Ratio         float64   `json:"ratio"`
+}
+
+// TrackerStatsPoint is one bucket of a tracker's history.
+type TrackerStatsPoint struct {
+	Time       time.Time `json:"time"`
+	Tracker    string    `json:"tracker"`
+	Uploaded   int64     `json:"uploaded"`
+	Downloaded int64     `json:"downloaded"`
+	Torrents   int       `json:"torrents"`
+}
+
+// TrackerStatsTotal is a tracker's transfer summed over a time range.
+type TrackerStatsTotal struct {
+	Tracker    string `json:"tracker"`
+	Uploaded   int64  `json:"uploaded"`
+	Downloaded int64  `json:"downloaded"`
+}
+
+// TrackerStatsQuery selects tracker history. Zero InstanceID covers every
+// instance, or only InstanceIDs when they are set, and an empty Tracker covers
+// every tracker.
+type TrackerStatsQuery struct {
+	InstanceID  int
+	InstanceIDs []int
+	Tracker     string
+	Resolution StatsResolution
+	From       time.Time
+	To         time.Time
+}
+
+// StatsStore persists the statistics history.
+type StatsStore struct {
+	db dbinterface.Querier
+}
+
+// NewStatsStore creates a new StatsStore.
+func NewStatsStore(db dbinterface.Querier) *StatsStore {
+	return &StatsStore{db: db}
+}
+
+// StatsBucket returns the start of the bucket t falls into for a resolution.
+// Raw buckets are rawInterval wide.
+func StatsBucket(resolution StatsResolution, t time.Time, rawInterval time.Duration) time.Time {
+	t = t.UTC()
+	switch resolution {
+	case StatsResolutionHour:
+		return t.Truncate(time.Hour)
+	case StatsResolutionDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	default:
+		if rawInterval <= 0 {
+			rawInterval = time.Minute
+		}
+		return t.Truncate(rawInterval)
+	}
+}
+
+// Record accumulates a sample into every resolution.
+func (s *StatsStore) Record(ctx context.Context, sample StatsSample, rawInterval time.Duration) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	trackerIDs := make([]int64, len(sample.Trackers))
+	if len(sample.Trackers) > 0 {
+		names := make([]string, len(sample.Trackers))
+		for i, tracker := range sample.Trackers {
+			names[i] = tracker.Tracker
+		}
+		if trackerIDs, err = dbinterface.InternStrings(ctx, tx, names...); err != nil {
+			return fmt.Errorf("failed to intern tracker names: %w", err)
+		}
+	}
+
+	for _, resolution := range []StatsResolution{StatsResolutionRaw, StatsResolutionHour, StatsResolutionDay} {
+		bucket := StatsBucket(resolution, sample.Time, rawInterval).Unix()
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO instance_stats (
+				instance_id, resolution, bucket, uploaded, downloaded,
+				upload_speed_sum, download_speed_sum, samples, torrents, seeding, downloading, ratio
+			) VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?, ?)
+			ON CONFLICT(instance_id, resolution, bucket) DO UPDATE SET
+				uploaded = uploaded + excluded.uploaded,
+				downloaded = downloaded + excluded.downloaded,
+				upload_speed_sum = upload_speed_sum + excluded.upload_speed_sum,
+				download_speed_sum = download_speed_sum + excluded.download_speed_sum,
+				samples = samples + 1,
+				torrents = excluded.torrents,
+				seeding = excluded.seeding,
+				downloading = excluded.downloading,
+				ratio = excluded.ratio
+		`, sample.InstanceID, resolution, bucket, sample.Uploaded, sample.Downloaded,
+			sample.UploadSpeed, sample.DownloadSpeed, sample.Torrents, sample.Seeding, sample.Downloading, sample.Ratio)
+		if err != nil {
+			return fmt.Errorf("failed to record instance stats: %w", err)
+		}
+
+		for i, tracker := range sample.Trackers {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO tracker_stats (instance_id, tracker_id, resolution, bucket, uploaded, downloaded, torrents)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(instance_id, tracker_id, resolution, bucket) DO UPDATE SET
+					uploaded = uploaded + excluded.uploaded,
+					downloaded = downloaded + excluded.downloaded,
+					torrents = excluded.torrents
+			`, sample.InstanceID, trackerIDs[i], resolution, bucket, tracker.Uploaded, tracker.Downloaded, tracker.Torrents)
+			if err != nil {
+				return fmt.Errorf("failed to record tracker stats: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// InstanceSeries returns an instance's history between from and to, oldest first.
+func (s *StatsStore) InstanceSeries(ctx context.Context, instanceID int, resolution StatsResolution, from, to time.Time) ([]InstanceStatsPoint, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT bucket, uploaded, downloaded,
+			upload_speed_sum / MAX(samples, 1), download_speed_sum / MAX(samples, 1),
+			torrents, seeding, downloading, ratio
+		FROM instance_stats
+		WHERE instance_id = ? AND resolution = ? AND bucket >= ? AND bucket <= ?
+		ORDER BY bucket
+	`, instanceID, resolution, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query instance stats: %w", err)
+	}
+	defer rows.Close()
+
+	var points []InstanceStatsPoint
+	for rows.Next() {
+		var point InstanceStatsPoint
+		var bucket int64
+		if err := rows.Scan(&bucket, &point.Uploaded, &point.Downloaded, &point.UploadSpeed, &point.DownloadSpeed,
+			&point.Torrents, &point.Seeding, &point.Downloading, &point.Ratio); err != nil {
+			return nil, fmt.Errorf("failed to scan instance stats: %w", err)
+		}
+		point.Time = time.Unix(bucket, 0).UTC()
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate instance stats: %w", err)
+	}
+	return points, nil
+}
+
+func (q TrackerStatsQuery) where() (string, []any) {
+	clauses := []string{"ts.resolution = ?", "ts.bucket >= ?", "ts.bucket <= ?"}
+	args := []any{q.Resolution, q.From.Unix(), q.To.Unix()}
+	if q.InstanceID > 0 {
+		clauses = append(clauses, "ts.instance_id = ?")
+		args = append(args, q.InstanceID)
+	} else if len(q.InstanceIDs) > 0 {
+		clauses = append(clauses, "ts.instance_id IN (?"+strings.Repeat(", ?", len(q.InstanceIDs)-1)+")")
+		for _, id := range q.InstanceIDs {
+			args = append(args, id)
+		}
+	}
+	if q.Tracker != "" {
+		clauses = append(clauses, "sp.value = ?")
+		args = append(args, q.Tracker)
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// TrackerSeries returns per-tracker history, summed across instances when the
+// query is not limited to one, ordered by time then tracker.
+func (s *StatsStore) TrackerSeries(ctx context.Context, query TrackerStatsQuery) ([]TrackerStatsPoint, error) {
+	where, args := query.where()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ts.bucket, sp.value, SUM(ts.uploaded), SUM(ts.downloaded), SUM(ts.torrents)
+		FROM tracker_stats ts
+		JOIN string_pool sp ON sp.id = ts.tracker_id
+		WHERE `+where+`
+		GROUP BY ts.bucket, sp.value
+		ORDER BY ts.bucket, sp.value
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracker stats: %w", err)
+	}
+	defer rows.Close()
+
+	var points []TrackerStatsPoint
+	for rows.Next() {
+		var point TrackerStatsPoint
+		var bucket int64
+		if err := rows.Scan(&bucket, &point.Tracker, &point.Uploaded, &point.Downloaded, &point.Torrents); err != nil {
+			return nil, fmt.Errorf("failed to scan tracker stats: %w", err)
+		}
+		point.Time = time.Unix(bucket, 0).UTC()
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tracker stats: %w", err)
+	}
+	return points, nil
+}
+
+// TrackerTotals sums each tracker's transfer over the query range, largest
+// upload first.
+func (s *StatsStore) TrackerTotals(ctx context.Context, query TrackerStatsQuery) ([]TrackerStatsTotal, error) {
+	where, args := query.where()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT sp.value, SUM(ts.uploaded), SUM(ts.downloaded)
+		FROM tracker_stats ts
+		JOIN string_pool sp ON sp.id = ts.tracker_id
+		WHERE `+where+`
+		GROUP BY sp.value
+		ORDER BY SUM(ts.uploaded) DESC, sp.value
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracker totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []TrackerStatsTotal
+	for rows.Next() {
+		var total TrackerStatsTotal
+		if err := rows.Scan(&total.Tracker, &total.Uploaded, &total.Downloaded); err != nil {
+			return nil, fmt.Errorf("failed to scan tracker totals: %w", err)
+		}
+		totals = append(totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tracker totals: %w", err)
+	}
+	return totals, nil
+}
+
+// Prune deletes buckets of a resolution that started before the cutoff.
+func (s *StatsStore) Prune(ctx context.Context, resolution StatsResolution, before time.Time) (int64, error) {
+	var deleted int64
+	for _, table := range []string{"instance_stats", "tracker_stats"} {
+		result, err := s.db.ExecContext(ctx, "DELETE FROM "+table+" WHERE resolution = ? AND bucket < ?", resolution, before.Unix())
+		if err != nil {
+			return deleted, fmt.Errorf("failed to prune %s: %w", table, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		deleted += affected
+	}
+	return deleted, nil
+}
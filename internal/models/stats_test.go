@@ -0,0 +1,105 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestStatsBucket(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 13, 47, 31, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 3, 4, 13, 45, 0, 0, time.UTC), models.StatsBucket(models.StatsResolutionRaw, ts, 5*time.Minute))
+	assert.Equal(t, time.Date(2025, 3, 4, 13, 47, 0, 0, time.UTC), models.StatsBucket(models.StatsResolutionRaw, ts, 0))
+	assert.Equal(t, time.Date(2025, 3, 4, 13, 0, 0, 0, time.UTC), models.StatsBucket(models.StatsResolutionHour, ts, time.Minute))
+	assert.Equal(t, time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), models.StatsBucket(models.StatsResolutionDay, ts, time.Minute))
+}
+
+func TestStatsStore_RecordAndDownsample(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewStatsStore(db)
+	ctx := context.Background()
+	first := insertTestInstance(t, db, "stats-a")
+	second := insertTestInstance(t, db, "stats-b")
+
+	base := time.Date(2025, 3, 4, 13, 0, 0, 0, time.UTC)
+	samples := []models.StatsSample{
+		{
+			InstanceID: first, Time: base, Uploaded: 100, Downloaded: 10, UploadSpeed: 4, DownloadSpeed: 2,
+			Torrents: 5, Seeding: 4, Downloading: 1, Ratio: 1.5,
+			Trackers: []models.TrackerStatsSample{{Tracker: "tracker.example", Uploaded: 60, Downloaded: 5, Torrents: 3}},
+		},
+		{
+			InstanceID: first, Time: base.Add(time.Minute), Uploaded: 200, Downloaded: 20, UploadSpeed: 8, DownloadSpeed: 4,
+			Torrents: 6, Seeding: 5, Downloading: 1, Ratio: 1.6,
+			Trackers: []models.TrackerStatsSample{{Tracker: "tracker.example", Uploaded: 150, Downloaded: 15, Torrents: 4}},
+		},
+		{
+			InstanceID: second, Time: base.Add(time.Minute), Uploaded: 50,
+			Trackers: []models.TrackerStatsSample{
+				{Tracker: "tracker.example", Uploaded: 40, Torrents: 1},
+				{Tracker: "other.example", Uploaded: 10, Torrents: 1},
+			},
+		},
+	}
+	for _, sample := range samples {
+		require.NoError(t, store.Record(ctx, sample, time.Minute))
+	}
+
+	raw, err := store.InstanceSeries(ctx, first, models.StatsResolutionRaw, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, raw, 2)
+	assert.Equal(t, base, raw[0].Time)
+	assert.Equal(t, int64(200), raw[1].Uploaded)
+
+	hourly, err := store.InstanceSeries(ctx, first, models.StatsResolutionHour, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, hourly, 1)
+	assert.Equal(t, models.InstanceStatsPoint{
+		Time: base, Uploaded: 300, Downloaded: 30, UploadSpeed: 6, DownloadSpeed: 3,
+		Torrents: 6, Seeding: 5, Downloading: 1, Ratio: 1.6,
+	}, hourly[0])
+
+	totals, err := store.TrackerTotals(ctx, models.TrackerStatsQuery{
+		Resolution: models.StatsResolutionDay,
+		From:       base.Add(-24 * time.Hour),
+		To:         base.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.TrackerStatsTotal{
+		{Tracker: "tracker.example", Uploaded: 250, Downloaded: 20},
+		{Tracker: "other.example", Uploaded: 10},
+	}, totals)
+
+	series, err := store.TrackerSeries(ctx, models.TrackerStatsQuery{
+		InstanceID: first,
+		Tracker:    "tracker.example",
+		Resolution: models.StatsResolutionRaw,
+		From:       base,
+		To:         base.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	assert.Equal(t, int64(150), series[1].Uploaded)
+	assert.Equal(t, 4, series[1].Torrents)
+
+	deleted, err := store.Prune(ctx, models.StatsResolutionRaw, base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted, "one instance row and one tracker row")
+
+	raw, err = store.InstanceSeries(ctx, first, models.StatsResolutionRaw, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, raw, 1)
+
+	hourly, err = store.InstanceSeries(ctx, first, models.StatsResolutionHour, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, hourly, 1, "pruning one resolution leaves the others")
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+const uploadHistoryDeleteBatch = 500
+
+// TorrentUploadSnapshot is a torrent's lifetime uploaded bytes as observed at
+// RecordedAt.
+type TorrentUploadSnapshot struct {
+	InstanceID int
+	Hash       string
+	Uploaded   int64
+	RecordedAt time.Time
+}
+
+// UploadHistoryDay returns the UTC day number a snapshot taken at t is stored under.
+func UploadHistoryDay(t time.Time) int64 {
+	return t.Unix() / int64(24*time.Hour/time.Second)
+}
+
+// TorrentUploadHistoryStore persists per-torrent upload snapshots.
+type TorrentUploadHistoryStore struct {
+	db dbinterface.Querier
+}
+
+// NewTorrentUploadHistoryStore creates a new TorrentUploadHistoryStore.
+func NewTorrentUploadHistoryStore(db dbinterface.Querier) *TorrentUploadHistoryStore {
+	return &TorrentUploadHistoryStore{db: db}
+}
+
+// Record stores snapshots, replacing any snapshot already stored for the same
+// torrent on the same day.
+func (s *TorrentUploadHistoryStore) Record(ctx context.Context, snapshots []TorrentUploadSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	hashes := make([]string, len(snapshots))
+	for i, snapshot := range snapshots {
+		hashes[i] = snapshot.Hash
+	}
+	hashIDs, err := dbinterface.InternStrings(ctx, tx, hashes...)
+	if err != nil {
+		return fmt.Errorf("failed to intern torrent hashes: %w", err)
+	}
+
+	for i, snapshot := range snapshots {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO torrent_upload_history (instance_id, hash_id, day, uploaded, recorded_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(instance_id, hash_id, day) DO UPDATE SET
+				uploaded = excluded.uploaded,
+				recorded_at = excluded.recorded_at
+		`, snapshot.InstanceID, hashIDs[i], UploadHistoryDay(snapshot.RecordedAt), snapshot.Uploaded, snapshot.RecordedAt.Unix())
+		if err != nil {
+			return fmt.Errorf("failed to record upload snapshot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// List returns every stored snapshot ordered by instance, hash and time.
+func (s *TorrentUploadHistoryStore) List(ctx context.Context) ([]TorrentUploadSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT h.instance_id, sp.value, h.uploaded, h.recorded_at
+		FROM torrent_upload_history h
+		JOIN string_pool sp ON sp.id = h.hash_id
+		ORDER BY h.instance_id, sp.value, h.day
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upload history: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []TorrentUploadSnapshot
+	for rows.Next() {
+		var snapshot TorrentUploadSnapshot
+		var recordedAt int64
+		if err := rows.Scan(&snapshot.InstanceID, &snapshot.Hash, &snapshot.Uploaded, &recordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan upload history: %w", err)
+		}
+		snapshot.RecordedAt = time.Unix(recordedAt, 0).UTC()
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate upload history: %w", err)
+	}
+	return snapshots, nil
+}
+
+// DeleteTorrents removes the history of torrents that no longer exist on an instance.
+func (s *TorrentUploadHistoryStore) DeleteTorrents(ctx context.Context, instanceID int, hashes []string) (int64, error) {
+	var deleted int64
+	for start := 0; start < len(hashes); start += uploadHistoryDeleteBatch {
+		batch := hashes[start:min(start+uploadHistoryDeleteBatch, len(hashes))]
+		args := make([]any, 0, len(batch)+1)
+		args = append(args, instanceID)
+		for _, hash := range batch {
+			args = append(args, hash)
+		}
+
+		result, err := s.db.ExecContext(ctx, `
+			DELETE FROM torrent_upload_history
+			WHERE instance_id = ? AND hash_id IN (
+				SELECT id FROM string_pool WHERE value IN (`+strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")+`)
+			)
+		`, args...)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete upload history: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		deleted += affected
+	}
+	return deleted, nil
+}
+
+// Prune deletes snapshots recorded before the cutoff, except each torrent's
+// newest one before it, which still anchors windows that start at the cutoff.
+func (s *TorrentUploadHistoryStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM torrent_upload_history
+		WHERE recorded_at < ?
+			AND EXISTS (
+				SELECT 1 FROM torrent_upload_history newer
+				WHERE newer.instance_id = torrent_upload_history.instance_id
+					AND newer.hash_id = torrent_upload_history.hash_id
+					AND newer.day > torrent_upload_history.day
+					AND newer.recorded_at < ?
+			)
+	`, before.Unix(), before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune upload history: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
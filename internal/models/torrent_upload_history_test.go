@@ -0,0 +1,56 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestTorrentUploadHistoryStore(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewTorrentUploadHistoryStore(db)
+	ctx := context.Background()
+	instanceID := insertTestInstance(t, db, "upload-history")
+
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Record(ctx, []models.TorrentUploadSnapshot{
+		{InstanceID: instanceID, Hash: "aaa", Uploaded: 100, RecordedAt: base},
+		{InstanceID: instanceID, Hash: "bbb", Uploaded: 5, RecordedAt: base},
+	}))
+	require.NoError(t, store.Record(ctx, []models.TorrentUploadSnapshot{
+		{InstanceID: instanceID, Hash: "aaa", Uploaded: 150, RecordedAt: base.Add(time.Hour)},
+	}))
+	require.NoError(t, store.Record(ctx, []models.TorrentUploadSnapshot{
+		{InstanceID: instanceID, Hash: "aaa", Uploaded: 200, RecordedAt: base.Add(48 * time.Hour)},
+		{InstanceID: instanceID, Hash: "aaa", Uploaded: 300, RecordedAt: base.Add(96 * time.Hour)},
+	}))
+
+	snapshots, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 4)
+	assert.Equal(t, models.TorrentUploadSnapshot{
+		InstanceID: instanceID, Hash: "aaa", Uploaded: 150, RecordedAt: base.Add(time.Hour),
+	}, snapshots[0], "same-day snapshots replace each other")
+
+	deleted, err := store.Prune(ctx, base.Add(72*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted, "the newest snapshot before the cutoff is kept")
+
+	deleted, err = store.DeleteTorrents(ctx, instanceID, []string{"bbb"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	snapshots, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(200), snapshots[0].Uploaded)
+	assert.Equal(t, int64(300), snapshots[1].Uploaded)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
 
 	"github.com/autobrr/qui/internal/dbinterface"
 	"modernc.org/sqlite"
@@ -16,10 +17,64 @@ import (
 var ErrUserNotFound = errors.New("user not found")
 var ErrUserAlreadyExists = errors.New("user already exists")
 
+// UserRole controls what a user may do. Roles are ordered: admin > operator > read_only.
+type UserRole string
+
+const (
+	// RoleAdmin has full access, including users and application settings.
+	RoleAdmin UserRole = "admin"
+	// RoleOperator can manage torrents on the instances granted to them.
+	RoleOperator UserRole = "operator"
+	// RoleReadOnly can only view the instances granted to them.
+	RoleReadOnly UserRole = "read_only"
+)
+
+// Valid reports whether r is a known role.
+func (r UserRole) Valid() bool {
+	return r.rank() > 0
+}
+
+// AtLeast reports whether r grants at least the permissions of min.
+func (r UserRole) AtLeast(min UserRole) bool {
+	return r.rank() >= min.rank() && r.Valid()
+}
+
+func (r UserRole) rank() int {
+	switch r {
+	case RoleAdmin:
+		return 3
+	case RoleOperator:
+		return 2
+	case RoleReadOnly:
+		return 1
+	default:
+		return 0
+	}
+}
+
 type User struct {
-	ID           int    `json:"id"`
-	Username     string `json:"username"`
-	PasswordHash string `json:"-"`
+	ID           int      `json:"id"`
+	Username     string   `json:"username"`
+	PasswordHash string   `json:"-"`
+	Role         UserRole `json:"role"`
+	// AllInstances grants access to every instance, including ones added later.
+	// Otherwise only InstanceIDs are accessible. Admins always see every instance.
+	AllInstances bool   `json:"allInstances"`
+	InstanceIDs  []int  `json:"instanceIds"`
+	OIDCSubject  string `json:"-"`
+	// OIDC is true for accounts provisioned from an OIDC login.
+	OIDC bool `json:"oidc"`
+	// TOTPSecret is set once enrollment starts; TwoFactorEnabled only after a code is confirmed.
+	TOTPSecret       string `json:"-"`
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+}
+
+// CanAccessInstance reports whether the user may see the given instance.
+func (u *User) CanAccessInstance(instanceID int) bool {
+	if u.Role == RoleAdmin || u.AllInstances {
+		return true
+	}
+	return slices.Contains(u.InstanceIDs, instanceID)
 }
 
 type UserStore struct {
@@ -30,106 +85,229 @@ func NewUserStore(db dbinterface.Querier) *UserStore {
 	return &UserStore{db: db}
 }
 
+const userColumns = `id, username, password_hash, role, all_instances, COALESCE(oidc_subject, ''), totp_secret, totp_enabled`
+
+func scanUser(row interface{ Scan(...any) error }) (*User, error) {
+	user := &User{}
+	if err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Role,
+		&user.AllInstances,
+		&user.OIDCSubject,
+		&user.TOTPSecret,
+		&user.TwoFactorEnabled,
+	); err != nil {
+		return nil, err
+	}
+	user.OIDC = user.OIDCSubject != ""
+	return user, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var sqlErr *sqlite.Error
+	return errors.As(err, &sqlErr) && sqlErr.Code() == lib.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// Create creates the initial admin account.
 func (s *UserStore) Create(ctx context.Context, username, passwordHash string) (*User, error) {
+	return s.CreateUser(ctx, &User{
+		Username:     username,
+		PasswordHash: passwordHash,
+		Role:         RoleAdmin,
+		AllInstances: true,
+	})
+}
+
+// CreateUser creates an account with the user's role and instance grants.
+func (s *UserStore) CreateUser(ctx context.Context, user *User) (*User, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	query := `
-		INSERT INTO user (id, username, password_hash) 
-		VALUES (1, ?, ?)
-		RETURNING id, username, password_hash
-	`
+	var subject any
+	if user.OIDCSubject != "" {
+		subject = user.OIDCSubject
+	}
 
-	user := &User{}
-	err = tx.QueryRowContext(ctx, query, username, passwordHash).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-	)
+	query := `
+		INSERT INTO user (username, password_hash, role, all_instances, oidc_subject)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING ` + userColumns
 
+	created, err := scanUser(tx.QueryRowContext(ctx, query, user.Username, user.PasswordHash, user.Role, user.AllInstances, subject))
 	if err != nil {
-		var sqlErr *sqlite.Error
-		if errors.As(err, &sqlErr) {
-			// UNIQUE constraint on username or CHECK constraint on id = 1
-			if sqlErr.Code() == lib.SQLITE_CONSTRAINT_UNIQUE || sqlErr.Code() == lib.SQLITE_CONSTRAINT_CHECK {
-				return nil, ErrUserAlreadyExists
-			}
+		if isUniqueViolation(err) {
+			return nil, ErrUserAlreadyExists
 		}
 		return nil, err
 	}
 
+	if err := setInstanceGrants(ctx, tx, created.ID, user.InstanceIDs); err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	created.InstanceIDs = normalizeInstanceIDs(user.InstanceIDs)
+	return created, nil
 }
 
-func (s *UserStore) Get(ctx context.Context) (*User, error) {
-	query := `
-		SELECT id, username, password_hash 
-		FROM user 
-		WHERE id = 1
-	`
+// GetByID returns a user with their instance grants.
+func (s *UserStore) GetByID(ctx context.Context, id int) (*User, error) {
+	return s.getOne(ctx, `SELECT `+userColumns+` FROM user WHERE id = ?`, id)
+}
 
-	user := &User{}
-	err := s.db.QueryRowContext(ctx, query).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-	)
+func (s *UserStore) GetByUsername(ctx context.Context, username string) (*User, error) {
+	return s.getOne(ctx, `SELECT `+userColumns+` FROM user WHERE username = ?`, username)
+}
+
+// GetByOIDCSubject returns the account linked to an OIDC subject.
+func (s *UserStore) GetByOIDCSubject(ctx context.Context, subject string) (*User, error) {
+	return s.getOne(ctx, `SELECT `+userColumns+` FROM user WHERE oidc_subject = ?`, subject)
+}
 
-	if err == sql.ErrNoRows {
+func (s *UserStore) getOne(ctx context.Context, query string, arg any) (*User, error) {
+	user, err := scanUser(s.db.QueryRowContext(ctx, query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrUserNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	grants, err := s.listGrants(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.InstanceIDs = normalizeInstanceIDs(grants[user.ID])
 	return user, nil
 }
 
-func (s *UserStore) GetByUsername(ctx context.Context, username string) (*User, error) {
-	query := `
-		SELECT id, username, password_hash 
-		FROM user 
-		WHERE username = ?
-	`
+// List returns all users ordered by username.
+func (s *UserStore) List(ctx context.Context) ([]*User, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+userColumns+` FROM user ORDER BY username COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	user := &User{}
-	err := s.db.QueryRowContext(ctx, query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-	)
+	var users []*User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	if err == sql.ErrNoRows {
-		return nil, ErrUserNotFound
+	grants, err := s.listGrants(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		user.InstanceIDs = normalizeInstanceIDs(grants[user.ID])
 	}
+	return users, nil
+}
+
+// listGrants returns instance grants keyed by user ID, for one user or all when userID is 0.
+func (s *UserStore) listGrants(ctx context.Context, userID int) (map[int][]int, error) {
+	query := `SELECT user_id, instance_id FROM user_instance_permissions`
+	var args []any
+	if userID > 0 {
+		query += ` WHERE user_id = ?`
+		args = append(args, userID)
+	}
+	query += ` ORDER BY user_id, instance_id`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return user, nil
+	grants := make(map[int][]int)
+	for rows.Next() {
+		var uid, instanceID int
+		if err := rows.Scan(&uid, &instanceID); err != nil {
+			return nil, err
+		}
+		grants[uid] = append(grants[uid], instanceID)
+	}
+	return grants, rows.Err()
 }
 
-func (s *UserStore) UpdatePassword(ctx context.Context, passwordHash string) error {
+// Update changes a user's username, role and instance grants. The password
+// and OIDC link are left untouched.
+func (s *UserStore) Update(ctx context.Context, user *User) (*User, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 
 	query := `
-		UPDATE user 
-		SET password_hash = ? 
-		WHERE id = 1
-	`
+		UPDATE user
+		SET username = ?, role = ?, all_instances = ?
+		WHERE id = ?
+		RETURNING ` + userColumns
+
+	updated, err := scanUser(tx.QueryRowContext(ctx, query, user.Username, user.Role, user.AllInstances, user.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrUserAlreadyExists
+		}
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_instance_permissions WHERE user_id = ?`, user.ID); err != nil {
+		return nil, err
+	}
+	if err := setInstanceGrants(ctx, tx, user.ID, user.InstanceIDs); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	updated.InstanceIDs = normalizeInstanceIDs(user.InstanceIDs)
+	return updated, nil
+}
+
+// UpdateRole sets a user's role, e.g. from OIDC group claims.
+func (s *UserStore) UpdateRole(ctx context.Context, id int, role UserRole) error {
+	return s.execOne(ctx, `UPDATE user SET role = ? WHERE id = ?`, role, id)
+}
+
+// LinkOIDCSubject ties an existing account to an OIDC subject.
+func (s *UserStore) LinkOIDCSubject(ctx context.Context, id int, subject string) error {
+	return s.execOne(ctx, `UPDATE user SET oidc_subject = ? WHERE id = ?`, subject, id)
+}
 
-	result, err := tx.ExecContext(ctx, query, passwordHash)
+func (s *UserStore) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	return s.execOne(ctx, `UPDATE user SET password_hash = ? WHERE id = ?`, passwordHash, id)
+}
+
+// Delete removes a user and their instance grants.
+func (s *UserStore) Delete(ctx context.Context, id int) error {
+	return s.execOne(ctx, `DELETE FROM user WHERE id = ?`, id)
+}
+
+func (s *UserStore) execOne(ctx context.Context, query string, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -138,24 +316,50 @@ func (s *UserStore) UpdatePassword(ctx context.Context, passwordHash string) err
 	if err != nil {
 		return err
 	}
-
 	if rows == 0 {
 		return ErrUserNotFound
 	}
-
-	if err = tx.Commit(); err != nil {
-		return err
-	}
-
 	return nil
 }
 
+// CountAdmins returns the number of admin accounts.
+func (s *UserStore) CountAdmins(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user WHERE role = ?`, RoleAdmin).Scan(&count)
+	return count, err
+}
+
+// Exists reports whether any local (password) account exists, i.e. whether
+// initial setup has been completed.
 func (s *UserStore) Exists(ctx context.Context) (bool, error) {
 	var count int
-	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM user").Scan(&count)
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM user WHERE password_hash != ''").Scan(&count)
 	if err != nil {
 		return false, err
 	}
 
 	return count > 0, nil
 }
+
+func setInstanceGrants(ctx context.Context, tx dbinterface.TxQuerier, userID int, instanceIDs []int) error {
+	for _, instanceID := range normalizeInstanceIDs(instanceIDs) {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO user_instance_permissions (user_id, instance_id) VALUES (?, ?)`,
+			userID, instanceID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeInstanceIDs(ids []int) []int {
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id > 0 && !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestUserStore_MultipleUsersWithGrants(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewUserStore(db)
+	ctx := context.Background()
+
+	first := insertTestInstance(t, db, "first")
+	second := insertTestInstance(t, db, "second")
+
+	admin, err := store.Create(ctx, "admin", "hash")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, admin.Role)
+
+	viewer, err := store.CreateUser(ctx, &models.User{
+		Username:    "viewer",
+		Role:        models.RoleReadOnly,
+		InstanceIDs: []int{second, first, second},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{first, second}, viewer.InstanceIDs)
+
+	_, err = store.CreateUser(ctx, &models.User{Username: "viewer", Role: models.RoleOperator})
+	require.ErrorIs(t, err, models.ErrUserAlreadyExists)
+
+	exists, err := store.Exists(ctx)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	viewer.Role = models.RoleOperator
+	viewer.InstanceIDs = []int{second}
+	_, err = store.Update(ctx, viewer)
+	require.NoError(t, err)
+
+	loaded, err := store.GetByID(ctx, viewer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleOperator, loaded.Role)
+	assert.Equal(t, []int{second}, loaded.InstanceIDs)
+	assert.True(t, loaded.CanAccessInstance(second))
+	assert.False(t, loaded.CanAccessInstance(first))
+
+	users, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "admin", users[0].Username)
+	assert.Empty(t, users[0].InstanceIDs)
+	assert.True(t, users[0].CanAccessInstance(first), "admins see every instance")
+
+	admins, err := store.CountAdmins(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, admins)
+
+	require.NoError(t, store.Delete(ctx, viewer.ID))
+	_, err = store.GetByID(ctx, viewer.ID)
+	require.ErrorIs(t, err, models.ErrUserNotFound)
+	require.ErrorIs(t, store.Delete(ctx, viewer.ID), models.ErrUserNotFound)
+
+	var grants int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_instance_permissions`).Scan(&grants))
+	assert.Zero(t, grants)
+}
+
+func TestUserStore_OIDCAccountsDontCompleteSetup(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewUserStore(db)
+	ctx := context.Background()
+
+	created, err := store.CreateUser(ctx, &models.User{Username: "sso", Role: models.RoleAdmin, AllInstances: true, OIDCSubject: "sub-1"})
+	require.NoError(t, err)
+	assert.True(t, created.OIDC)
+
+	exists, err := store.Exists(ctx)
+	require.NoError(t, err)
+	assert.False(t, exists, "setup still requires a local account")
+
+	loaded, err := store.GetByOIDCSubject(ctx, "sub-1")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, loaded.ID)
+
+	require.NoError(t, store.UpdateRole(ctx, created.ID, models.RoleReadOnly))
+	loaded, err = store.GetByUsername(ctx, "sso")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleReadOnly, loaded.Role)
+}
+
+func TestUserRole_AtLeast(t *testing.T) {
+	assert.True(t, models.RoleAdmin.AtLeast(models.RoleOperator))
+	assert.True(t, models.RoleOperator.AtLeast(models.RoleOperator))
+	assert.False(t, models.RoleReadOnly.AtLeast(models.RoleOperator))
+	assert.False(t, models.UserRole("guest").AtLeast(models.RoleReadOnly))
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// HashRecoveryCode returns the stored form of a recovery code. Codes are
+// random, so a plain SHA-256 is enough; formatting differences are ignored.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// SetTOTPSecret stores a pending TOTP secret. Two-factor stays disabled until EnableTOTP.
+func (s *UserStore) SetTOTPSecret(ctx context.Context, id int, secret string) error {
+	return s.execOne(ctx, `UPDATE user SET totp_secret = ?, totp_enabled = 0, totp_last_step = 0 WHERE id = ?`, secret, id)
+}
+
+// EnableTOTP turns on two-factor for a user and replaces their recovery codes.
+func (s *UserStore) EnableTOTP(ctx context.Context, id int, codeHashes []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE user SET totp_enabled = 1 WHERE id = ? AND totp_secret != ''`, id)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrUserNotFound
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, id, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DisableTOTP turns off two-factor, clearing the secret and recovery codes.
+func (s *UserStore) DisableTOTP(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE user SET totp_secret = '', totp_enabled = 0, totp_last_step = 0 WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes discards a user's recovery codes and stores new ones.
+func (s *UserStore) ReplaceRecoveryCodes(ctx context.Context, id int, codeHashes []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, id, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx dbinterface.TxQuerier, id int, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, id); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO user_recovery_codes (user_id, code_hash) VALUES (?, ?)`,
+			id, hash,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It reports false when
+// the code doesn't exist or was already used.
+func (s *UserStore) UseRecoveryCode(ctx context.Context, id int, codeHash string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE user_recovery_codes
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`, id, codeHash)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left.
+func (s *UserStore) CountRecoveryCodes(ctx context.Context, id int) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = ? AND used_at IS NULL`, id,
+	).Scan(&count)
+	return count, err
+}
+
+// UseTOTPStep records an accepted TOTP time step. It reports false when the
+// step, or a later one, was already used, so each code works only once.
+func (s *UserStore) UseTOTPStep(ctx context.Context, id int, step int64) (bool, error) {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE user SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?`, step, id, step)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
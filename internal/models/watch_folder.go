@@ -0,0 +1,210 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// WatchFolderMode controls what happens to .torrent files dropped into a watch folder.
+type WatchFolderMode string
+
+const (
+	// WatchFolderModeAdd adds the torrent to the instance as-is.
+	WatchFolderModeAdd WatchFolderMode = "add"
+	// WatchFolderModeCrossSeed only adds the torrent when it cross-seeds existing data.
+	WatchFolderModeCrossSeed WatchFolderMode = "cross_seed"
+)
+
+// IsValid reports whether the mode is supported.
+func (m WatchFolderMode) IsValid() bool {
+	return m == WatchFolderModeAdd || m == WatchFolderModeCrossSeed
+}
+
+// WatchFolder is a directory watched for .torrent files on behalf of an instance.
+type WatchFolder struct {
+	ID          int64           `json:"id"`
+	InstanceID  int             `json:"instanceId"`
+	Path        string          `json:"path"`
+	Mode        WatchFolderMode `json:"mode"`
+	Category    string          `json:"category"`
+	Tags        []string        `json:"tags"`
+	StartPaused bool            `json:"startPaused"`
+	Enabled     bool            `json:"enabled"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+// WatchFolderStore handles database operations for watch folders.
+type WatchFolderStore struct {
+	db dbinterface.Querier
+}
+
+// NewWatchFolderStore creates a new WatchFolderStore.
+func NewWatchFolderStore(db dbinterface.Querier) *WatchFolderStore {
+	return &WatchFolderStore{db: db}
+}
+
+const watchFolderSelect = `
+	SELECT id, instance_id, path, mode, category, tags, start_paused, enabled, created_at, updated_at
+	FROM watch_folders
+`
+
+// List returns all watch folders across instances.
+func (s *WatchFolderStore) List(ctx context.Context) ([]*WatchFolder, error) {
+	return s.query(ctx, watchFolderSelect+` ORDER BY instance_id, path`)
+}
+
+// ListByInstance returns the watch folders configured for an instance.
+func (s *WatchFolderStore) ListByInstance(ctx context.Context, instanceID int) ([]*WatchFolder, error) {
+	return s.query(ctx, watchFolderSelect+` WHERE instance_id = ? ORDER BY path`, instanceID)
+}
+
+// Get returns a single watch folder or sql.ErrNoRows.
+func (s *WatchFolderStore) Get(ctx context.Context, id int64) (*WatchFolder, error) {
+	row := s.db.QueryRowContext(ctx, watchFolderSelect+` WHERE id = ?`, id)
+	return scanWatchFolder(row)
+}
+
+// Create inserts a new watch folder.
+func (s *WatchFolderStore) Create(ctx context.Context, folder *WatchFolder) (*WatchFolder, error) {
+	if folder == nil {
+		return nil, errors.New("watch folder is nil")
+	}
+
+	tagsJSON, err := json.Marshal(nonNilStrings(folder.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("marshal tags: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO watch_folders (instance_id, path, mode, category, tags, start_paused, enabled)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, folder.InstanceID, folder.Path, string(folder.Mode), folder.Category, string(tagsJSON), folder.StartPaused, folder.Enabled)
+	if err != nil {
+		return nil, fmt.Errorf("insert watch folder: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("fetch watch folder id: %w", err)
+	}
+
+	return s.Get(ctx, id)
+}
+
+// Update replaces the configuration of an existing watch folder.
+func (s *WatchFolderStore) Update(ctx context.Context, folder *WatchFolder) (*WatchFolder, error) {
+	if folder == nil {
+		return nil, errors.New("watch folder is nil")
+	}
+
+	tagsJSON, err := json.Marshal(nonNilStrings(folder.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("marshal tags: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE watch_folders
+		SET path = ?, mode = ?, category = ?, tags = ?, start_paused = ?, enabled = ?
+		WHERE id = ? AND instance_id = ?
+	`, folder.Path, string(folder.Mode), folder.Category, string(tagsJSON), folder.StartPaused, folder.Enabled, folder.ID, folder.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("update watch folder: %w", err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.Get(ctx, folder.ID)
+}
+
+// Delete removes a watch folder belonging to an instance.
+func (s *WatchFolderStore) Delete(ctx context.Context, instanceID int, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM watch_folders WHERE id = ? AND instance_id = ?`, id, instanceID)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (s *WatchFolderStore) query(ctx context.Context, query string, args ...any) ([]*WatchFolder, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list watch folders: %w", err)
+	}
+	defer rows.Close()
+
+	folders := []*WatchFolder{}
+	for rows.Next() {
+		folder, err := scanWatchFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+func scanWatchFolder(scanner interface {
+	Scan(dest ...any) error
+}) (*WatchFolder, error) {
+	var (
+		folder   WatchFolder
+		mode     string
+		tagsJSON string
+	)
+
+	if err := scanner.Scan(
+		&folder.ID,
+		&folder.InstanceID,
+		&folder.Path,
+		&mode,
+		&folder.Category,
+		&tagsJSON,
+		&folder.StartPaused,
+		&folder.Enabled,
+		&folder.CreatedAt,
+		&folder.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	folder.Mode = WatchFolderMode(mode)
+	if tagsJSON != "" {
+		if err := json.Unmarshal([]byte(tagsJSON), &folder.Tags); err != nil {
+			return nil, fmt.Errorf("unmarshal watch folder tags: %w", err)
+		}
+	}
+	if folder.Tags == nil {
+		folder.Tags = []string{}
+	}
+
+	return &folder, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestWatchFolderStore_CRUD(t *testing.T) {
+	db := setupCrossSeedTestDB(t)
+	store := models.NewWatchFolderStore(db)
+	ctx := context.Background()
+
+	instanceA := insertTestInstance(t, db, "instance-a")
+	instanceB := insertTestInstance(t, db, "instance-b")
+
+	created, err := store.Create(ctx, &models.WatchFolder{
+		InstanceID: instanceA,
+		Path:       "/watch/a",
+		Mode:       models.WatchFolderModeCrossSeed,
+		Category:   "cross-seed",
+		Tags:       []string{"watch", "xseed"},
+		Enabled:    true,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, models.WatchFolderModeCrossSeed, created.Mode)
+	assert.Equal(t, []string{"watch", "xseed"}, created.Tags)
+	assert.True(t, created.Enabled)
+
+	_, err = store.Create(ctx, &models.WatchFolder{
+		InstanceID: instanceB,
+		Path:       "/watch/b",
+		Mode:       models.WatchFolderModeAdd,
+	})
+	require.NoError(t, err)
+
+	// The same path can only be watched once.
+	_, err = store.Create(ctx, &models.WatchFolder{InstanceID: instanceB, Path: "/watch/a", Mode: models.WatchFolderModeAdd})
+	require.Error(t, err)
+
+	all, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	byInstance, err := store.ListByInstance(ctx, instanceB)
+	require.NoError(t, err)
+	require.Len(t, byInstance, 1)
+	assert.Equal(t, "/watch/b", byInstance[0].Path)
+	assert.Empty(t, byInstance[0].Tags)
+	assert.NotNil(t, byInstance[0].Tags)
+
+	created.StartPaused = true
+	created.Enabled = false
+	created.Tags = nil
+	updated, err := store.Update(ctx, created)
+	require.NoError(t, err)
+	assert.True(t, updated.StartPaused)
+	assert.False(t, updated.Enabled)
+	assert.Empty(t, updated.Tags)
+
+	// Updates and deletes are scoped to the owning instance.
+	created.InstanceID = instanceB
+	_, err = store.Update(ctx, created)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.ErrorIs(t, store.Delete(ctx, instanceB, created.ID), sql.ErrNoRows)
+
+	require.NoError(t, store.Delete(ctx, instanceA, created.ID))
+	_, err = store.Get(ctx, created.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -15,6 +15,8 @@ import (
 	qbt "github.com/autobrr/go-qbittorrent"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/events"
 )
 
 var (
@@ -61,6 +63,7 @@ type Client struct {
 	completionState   map[string]bool
 	completionHandler TorrentCompletionHandler
 	completionInit    bool
+	eventBus          *events.Bus
 }
 
 func NewClient(instanceID int, instanceHost, username, password string, basicUsername, basicPassword *string, tlsSkipVerify bool) (*Client, error) {
@@ -471,6 +474,13 @@ func (c *Client) SetTorrentCompletionHandler(handler TorrentCompletionHandler) {
 	c.completionMu.Unlock()
 }
 
+// SetEventBus sets the bus torrent added/completed/deleted events are published to.
+func (c *Client) SetEventBus(bus *events.Bus) {
+	c.completionMu.Lock()
+	c.eventBus = bus
+	c.completionMu.Unlock()
+}
+
 func (c *Client) StartSyncManager(ctx context.Context) error {
 	c.mu.RLock()
 	syncManager := c.syncManager
@@ -496,9 +506,19 @@ func (c *Client) handleCompletionUpdates(data *qbt.MainData) {
 	}
 
 	handler := c.completionHandler
+	bus := c.eventBus
 
+	var lifecycle []events.Event
 	for _, removed := range data.TorrentsRemoved {
-		delete(c.completionState, normalizeHashForCompletion(removed))
+		normalized := normalizeHashForCompletion(removed)
+		if _, tracked := c.completionState[normalized]; tracked {
+			delete(c.completionState, normalized)
+			lifecycle = append(lifecycle, events.Event{
+				Type:       events.TorrentDeleted,
+				InstanceID: c.instanceID,
+				Payload:    events.Torrent{Hash: normalized},
+			})
+		}
 	}
 
 	if !c.completionInit {
@@ -515,12 +535,37 @@ func (c *Client) handleCompletionUpdates(data *qbt.MainData) {
 		return
 	}
 
+	// data is the full merged snapshot, so anything we track that is no longer
+	// present has been removed.
+	present := make(map[string]struct{}, len(data.Torrents))
+	for hash := range data.Torrents {
+		present[normalizeHashForCompletion(hash)] = struct{}{}
+	}
+	for hash := range c.completionState {
+		if _, ok := present[hash]; !ok {
+			delete(c.completionState, hash)
+			lifecycle = append(lifecycle, events.Event{
+				Type:       events.TorrentDeleted,
+				InstanceID: c.instanceID,
+				Payload:    events.Torrent{Hash: hash},
+			})
+		}
+	}
+
 	ready := make([]qbt.Torrent, 0)
 	for hash, torrent := range data.Torrents {
 		normalized := normalizeHashForCompletion(hash)
-		alreadyHandled := c.completionState[normalized]
+		alreadyHandled, known := c.completionState[normalized]
 		isComplete := isTorrentComplete(&torrent)
 
+		if !known {
+			lifecycle = append(lifecycle, events.Event{
+				Type:       events.TorrentAdded,
+				InstanceID: c.instanceID,
+				Payload:    events.Torrent{Hash: normalized, Name: torrent.Name},
+			})
+		}
+
 		if !alreadyHandled && isComplete {
 			c.completionState[normalized] = true
 			ready = append(ready, torrent)
@@ -533,6 +578,17 @@ func (c *Client) handleCompletionUpdates(data *qbt.MainData) {
 	}
 	c.completionMu.Unlock()
 
+	for _, event := range lifecycle {
+		bus.Publish(event)
+	}
+	for _, torrent := range ready {
+		bus.Publish(events.Event{
+			Type:       events.TorrentCompleted,
+			InstanceID: c.instanceID,
+			Payload:    events.Torrent{Hash: normalizeHashForCompletion(torrent.Hash), Name: torrent.Name},
+		})
+	}
+
 	if handler == nil || len(ready) == 0 {
 		return
 	}
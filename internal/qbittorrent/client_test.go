@@ -4,10 +4,16 @@
 package qbittorrent
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
 	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/events"
 )
 
 func TestClientUpdateServerStateDoesNotBlockOnClientMutex(t *testing.T) {
@@ -33,3 +39,47 @@ func TestClientUpdateServerStateDoesNotBlockOnClientMutex(t *testing.T) {
 		t.Fatal("updateServerState blocked waiting for Client.mu write lock")
 	}
 }
+
+func TestClientHandleCompletionUpdatesPublishesLifecycleEvents(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	var mu sync.Mutex
+	got := make(map[events.Type][]string)
+	bus.Subscribe("test", func(_ context.Context, event events.Event) {
+		torrent, ok := events.PayloadAs[events.Torrent](event)
+		if !ok {
+			return
+		}
+		mu.Lock()
+		got[event.Type] = append(got[event.Type], torrent.Hash)
+		mu.Unlock()
+	})
+
+	client := &Client{instanceID: 1}
+	client.SetEventBus(bus)
+
+	downloading := qbt.Torrent{Hash: "aaa", Name: "A", Progress: 0.5, State: qbt.TorrentStateDownloading}
+	seeding := qbt.Torrent{Hash: "bbb", Name: "B", Progress: 1, State: qbt.TorrentStateUploading}
+
+	// The initial snapshot only seeds state.
+	client.handleCompletionUpdates(&qbt.MainData{Torrents: map[string]qbt.Torrent{"aaa": downloading, "bbb": seeding}})
+
+	finished := downloading
+	finished.Progress = 1
+	finished.State = qbt.TorrentStateUploading
+	added := qbt.Torrent{Hash: "ccc", Name: "C", Progress: 0, State: qbt.TorrentStateMetaDl}
+	client.handleCompletionUpdates(&qbt.MainData{Torrents: map[string]qbt.Torrent{"aaa": finished, "ccc": added}})
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got[events.TorrentAdded]) == 1 && len(got[events.TorrentCompleted]) == 1 && len(got[events.TorrentDeleted]) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"CCC"}, got[events.TorrentAdded])
+	assert.Equal(t, []string{"AAA"}, got[events.TorrentCompleted])
+	assert.Equal(t, []string{"BBB"}, got[events.TorrentDeleted])
+}
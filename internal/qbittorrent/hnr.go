@@ -0,0 +1,74 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+)
+
+// HnRStatus is a torrent's standing against its tracker's hit-and-run rules.
+type HnRStatus string
+
+const (
+	// HnRStatusExempt torrents downloaded nothing through this client, e.g. cross-seeds.
+	HnRStatusExempt    HnRStatus = "exempt"
+	HnRStatusCompliant HnRStatus = "compliant"
+	// HnRStatusDownloading torrents haven't completed yet.
+	HnRStatusDownloading HnRStatus = "downloading"
+	// HnRStatusPending torrents are seeding towards the requirement in time.
+	HnRStatusPending HnRStatus = "pending"
+	// HnRStatusAtRisk torrents are not seeding, or can no longer reach the
+	// seed time before the deadline.
+	HnRStatusAtRisk HnRStatus = "at_risk"
+	// HnRStatusViolated torrents missed the deadline.
+	HnRStatusViolated HnRStatus = "violated"
+)
+
+// IsCompliant reports whether removing the torrent is safe as far as
+// hit-and-run rules are concerned.
+func (s HnRStatus) IsCompliant() bool {
+	return s == HnRStatusExempt || s == HnRStatusCompliant
+}
+
+// HnRCompliance is a torrent's computed hit-and-run compliance.
+type HnRCompliance struct {
+	Status      HnRStatus `json:"status"`
+	ProfileID   int       `json:"profile_id"`
+	ProfileName string    `json:"profile_name"`
+	// SeedTimeRemaining and RatioRemaining are what is left of each configured
+	// requirement; zero when met or unused.
+	SeedTimeRemaining int64   `json:"seed_time_remaining,omitempty"`
+	RatioRemaining    float64 `json:"ratio_remaining,omitempty"`
+	// Deadline is when the grace period ends (Unix seconds), if the profile has one.
+	Deadline int64 `json:"deadline,omitempty"`
+}
+
+// HnRProvider computes hit-and-run compliance for torrents.
+type HnRProvider interface {
+	HnRCompliance(torrent qbt.Torrent, now time.Time) (HnRCompliance, bool)
+}
+
+// SetHnRProvider sets the source of hit-and-run compliance.
+func (sm *SyncManager) SetHnRProvider(provider HnRProvider) {
+	sm.hnr.Store(&provider)
+}
+
+// GetHnRCompliance returns a torrent's hit-and-run compliance, if a profile covers it.
+func (sm *SyncManager) GetHnRCompliance(torrent qbt.Torrent, now time.Time) (HnRCompliance, bool) {
+	v := sm.hnr.Load()
+	if v == nil || *v == nil {
+		return HnRCompliance{}, false
+	}
+	return (*v).HnRCompliance(torrent, now)
+}
+
+func (sm *SyncManager) hnrView(torrent qbt.Torrent, now time.Time) *HnRCompliance {
+	compliance, ok := sm.GetHnRCompliance(torrent, now)
+	if !ok {
+		return nil
+	}
+	return &compliance
+}
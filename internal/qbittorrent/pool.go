@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/models"
 )
 
@@ -65,6 +66,7 @@ type ClientPool struct {
 	decryptionTracker map[int]*decryptionErrorInfo
 	completionHandler TorrentCompletionHandler
 	syncManager       *SyncManager // Reference for starting background tasks
+	eventBus          *events.Bus
 }
 
 // NewClientPool creates a new client pool
@@ -115,6 +117,22 @@ func (cp *ClientPool) SetSyncManager(sm *SyncManager) {
 	cp.syncManager = sm
 }
 
+// SetEventBus sets the bus instance up/down and torrent lifecycle events are published to.
+func (cp *ClientPool) SetEventBus(bus *events.Bus) {
+	cp.mu.Lock()
+	cp.eventBus = bus
+
+	clients := make([]*Client, 0, len(cp.clients))
+	for _, client := range cp.clients {
+		clients = append(clients, client)
+	}
+	cp.mu.Unlock()
+
+	for _, client := range clients {
+		client.SetEventBus(bus)
+	}
+}
+
 // getInstanceLock gets or creates a per-instance creation lock
 func (cp *ClientPool) getInstanceLock(instanceID int) *sync.Mutex {
 	cp.creationMu.Lock()
@@ -248,11 +266,13 @@ func (cp *ClientPool) createClientWithTimeout(ctx context.Context, instanceID in
 	// Reset failure tracking on successful connection
 	cp.resetFailureTrackingLocked(instanceID)
 	handler := cp.completionHandler
+	bus := cp.eventBus
 	cp.mu.Unlock()
 
 	if handler != nil {
 		client.SetTorrentCompletionHandler(handler)
 	}
+	client.SetEventBus(bus)
 
 	// Start the sync manager
 	if err := client.StartSyncManager(ctx); err != nil {
@@ -441,6 +461,15 @@ func (cp *ClientPool) trackFailure(instanceID int, err error) {
 		log.Error().Err(recordErr).Int("instanceID", instanceID).Msg("Failed to record error to database")
 	}
 
+	// Only publish on the first failure of an outage; the tracker resets on recovery.
+	if info.attempts == 1 && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		cp.eventBus.Publish(events.Event{
+			Type:       events.InstanceDown,
+			InstanceID: instanceID,
+			Payload:    events.InstanceStatus{Error: err.Error()},
+		})
+	}
+
 	// Calculate backoff duration
 	var backoffDuration time.Duration
 	if cp.isBanError(err) {
@@ -474,6 +503,7 @@ func (cp *ClientPool) resetFailureTrackingLocked(instanceID int) {
 		delete(cp.failureTracker, instanceID)
 		hadFailures = true
 		log.Debug().Int("instanceID", instanceID).Msg("Reset failure tracking after successful connection")
+		cp.eventBus.Publish(events.Event{Type: events.InstanceUp, InstanceID: instanceID})
 	}
 
 	// Also reset decryption error tracking on successful connection
@@ -28,6 +28,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/autobrr/qui/internal/auth"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/services/trackericons"
 )
@@ -92,7 +93,9 @@ const (
 // TorrentView extends qBittorrent's torrent with UI-specific metadata.
 type TorrentView struct {
 	qbt.Torrent
-	TrackerHealth TrackerHealth `json:"tracker_health,omitempty"`
+	TrackerHealth TrackerHealth  `json:"tracker_health,omitempty"`
+	UploadHistory *UploadHistory `json:"upload_history,omitempty"`
+	HnR           *HnRCompliance `json:"hnr,omitempty"`
 }
 
 // CrossInstanceTorrentView extends TorrentView with cross-instance metadata.
@@ -168,9 +171,11 @@ type ValidatedTrackerMapping struct {
 }
 
 type SyncManager struct {
-	clientPool   *ClientPool
-	exprCache    *ttlcache.Cache[string, *vm.Program]
-	filesManager atomic.Value // stores FilesManager interface value
+	clientPool    *ClientPool
+	exprCache     *ttlcache.Cache[string, *vm.Program]
+	filesManager  atomic.Value // stores FilesManager interface value
+	uploadHistory atomic.Pointer[UploadHistoryProvider]
+	hnr           atomic.Pointer[HnRProvider]
 
 	// Providers used for testing and specialized flows; nil defaults to live clients.
 	torrentFilesClientProvider func(ctx context.Context, instanceID int) (torrentFilesClient, error)
@@ -194,6 +199,11 @@ type SyncManager struct {
 	// Validated tracker mapping cache - avoids stale MainData.Trackers entries
 	validatedTrackerMu      sync.RWMutex
 	validatedTrackerMapping map[int]*ValidatedTrackerMapping
+
+	// Torrent list delta streams - one shared feed per instance while subscribed
+	torrentStreamMu       sync.Mutex
+	torrentFeeds          map[int]*torrentFeed
+	torrentStreamInterval time.Duration
 }
 
 // ResumeWhenCompleteOptions configure resume monitoring behavior.
@@ -226,6 +236,8 @@ func NewSyncManager(clientPool *ClientPool) *SyncManager {
 		trackerHealthCancel:     make(map[int]context.CancelFunc),
 		trackerHealthRefresh:    60 * time.Second,
 		validatedTrackerMapping: make(map[int]*ValidatedTrackerMapping),
+		torrentFeeds:            make(map[int]*torrentFeed),
+		torrentStreamInterval:   defaultTorrentStreamInterval,
 	}
 
 	// Set up bidirectional reference for background task notifications
@@ -1070,9 +1082,10 @@ func (sm *SyncManager) GetTorrentsWithFilters(ctx context.Context, instanceID in
 
 	var paginatedViews []TorrentView
 	if len(paginatedTorrents) > 0 {
+		now := time.Now()
 		paginatedViews = make([]TorrentView, len(paginatedTorrents))
 		for i, torrent := range paginatedTorrents {
-			view := TorrentView{Torrent: torrent}
+			view := TorrentView{Torrent: torrent, UploadHistory: sm.uploadHistoryView(instanceID, torrent, now), HnR: sm.hnrView(torrent, now)}
 			// First try to determine health from enriched tracker data
 			if health := sm.determineTrackerHealth(torrent); health != "" {
 				view.TrackerHealth = health
@@ -1172,10 +1185,11 @@ func (sm *SyncManager) GetCachedInstanceTorrents(ctx context.Context, instanceID
 
 	// Get cached tracker health counts for this instance
 	cachedHealth := sm.GetTrackerHealthCounts(instanceID)
+	now := time.Now()
 
 	views := make([]CrossInstanceTorrentView, len(torrents))
 	for i, torrent := range torrents {
-		view := TorrentView{Torrent: torrent}
+		view := TorrentView{Torrent: torrent, UploadHistory: sm.uploadHistoryView(instanceID, torrent, now), HnR: sm.hnrView(torrent, now)}
 		// First try to determine health from enriched tracker data
 		if health := sm.determineTrackerHealth(torrent); health != "" {
 			view.TrackerHealth = health
@@ -1228,6 +1242,11 @@ func (sm *SyncManager) GetCrossInstanceTorrentsWithFilters(ctx context.Context,
 			return nil, ctx.Err()
 		}
 
+		// Requests made on behalf of a user only cover the instances they were granted
+		if user, ok := auth.UserFromContext(ctx); ok && !user.CanAccessInstance(instance.ID) {
+			continue
+		}
+
 		instanceResponse, err := sm.GetTorrentsWithFilters(ctx, instance.ID, 0, 0, "", "", search, filters)
 		if err != nil {
 			log.Warn().
@@ -1662,6 +1681,21 @@ func (sm *SyncManager) GetTorrentWebSeeds(ctx context.Context, instanceID int, h
 	return webseeds, nil
 }
 
+// GetTorrentPieceStates returns the download state of every piece of a torrent
+func (sm *SyncManager) GetTorrentPieceStates(ctx context.Context, instanceID int, hash string) ([]qbt.PieceState, error) {
+	client, err := sm.clientPool.GetClient(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	states, err := client.GetTorrentPieceStatesCtx(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get torrent piece states: %w", err)
+	}
+
+	return states, nil
+}
+
 // GetTorrentPeers gets peers for a specific torrent with incremental updates
 func (sm *SyncManager) GetTorrentPeers(ctx context.Context, instanceID int, hash string) (*qbt.TorrentPeersResponse, error) {
 	// Get client
@@ -3486,12 +3520,17 @@ func (sm *SyncManager) applyManualFilters(
 
 	var program *vm.Program
 	var compileErr error
+	var instanceID int
+	if client != nil {
+		instanceID = client.GetInstanceID()
+	}
+	now := time.Now()
 	if len(filters.Expr) > 0 {
 		if p, ok := sm.exprCache.Get(filters.Expr); ok {
 			log.Debug().Str("expr", filters.Expr).Msg("Using cached expression")
 			program = p
 		} else {
-			program, compileErr = expr.Compile(filters.Expr, expr.Env(qbt.Torrent{}), expr.AsBool())
+			program, compileErr = expr.Compile(filters.Expr, expr.Env(TorrentExprEnv{}), expr.AsBool())
 			if compileErr != nil {
 				log.Error().Err(compileErr).Msg("Failed to compile expression")
 			} else if ok := sm.exprCache.Set(filters.Expr, program, 5*time.Minute); !ok {
@@ -3681,7 +3720,7 @@ torrentsLoop:
 		}
 
 		if len(filters.Expr) > 0 && compileErr == nil {
-			result, err := expr.Run(program, torrent)
+			result, err := expr.Run(program, sm.torrentExprEnv(instanceID, torrent, now))
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to evaluate expression")
 				continue
@@ -4703,6 +4742,27 @@ func (sm *SyncManager) ToggleAlternativeSpeedLimits(ctx context.Context, instanc
 	return nil
 }
 
+// GetTrackerHashes returns the full tracker URLs known to an instance mapped to the
+// hashes of the torrents announcing to them.
+func (sm *SyncManager) GetTrackerHashes(ctx context.Context, instanceID int) (map[string][]string, error) {
+	_, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string)
+	mainData := syncManager.GetData()
+	if mainData == nil {
+		return result, nil
+	}
+
+	for trackerURL, hashes := range mainData.Trackers {
+		result[trackerURL] = append([]string(nil), hashes...)
+	}
+
+	return result, nil
+}
+
 // GetActiveTrackers returns all active tracker domains with their URLs and counts
 func (sm *SyncManager) GetActiveTrackers(ctx context.Context, instanceID int) (map[string]string, error) {
 	client, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
@@ -5378,3 +5438,13 @@ func (sm *SyncManager) GetFreeSpace(ctx context.Context, instanceID int) (int64,
 	state := client.syncManager.GetServerState()
 	return state.FreeSpaceOnDisk, nil
 }
+
+// GetServerState returns the instance's most recently synced server state.
+func (sm *SyncManager) GetServerState(ctx context.Context, instanceID int) (qbt.ServerState, error) {
+	_, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
+	if err != nil {
+		return qbt.ServerState{}, err
+	}
+
+	return syncManager.GetServerState(), nil
+}
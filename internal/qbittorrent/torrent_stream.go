@@ -0,0 +1,446 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultTorrentStreamInterval = 2 * time.Second
+	torrentStreamBuffer          = 64
+)
+
+// ErrTorrentStreamOverflow is reported by a stream that was dropped because its
+// consumer fell too far behind. Clients should reconnect and take a new snapshot.
+var ErrTorrentStreamOverflow = errors.New("torrent stream fell behind")
+
+// TorrentSnapshot is the filtered torrent list a stream starts from.
+type TorrentSnapshot struct {
+	InstanceID  int             `json:"instanceId"`
+	Torrents    []qbt.Torrent   `json:"torrents"`
+	ServerState qbt.ServerState `json:"serverState"`
+	Time        time.Time       `json:"time"`
+}
+
+// TorrentDelta describes how a stream's view of the torrent list changed since
+// the previous delta. Changed holds only the fields that differ, keyed by hash
+// and then by the field's JSON name. Torrents that start or stop matching the
+// stream's filters are reported as added or removed.
+type TorrentDelta struct {
+	InstanceID  int                       `json:"instanceId"`
+	Added       []qbt.Torrent             `json:"added,omitempty"`
+	Changed     map[string]map[string]any `json:"changed,omitempty"`
+	Removed     []string                  `json:"removed,omitempty"`
+	ServerState map[string]any            `json:"serverState,omitempty"`
+	Time        time.Time                 `json:"time"`
+}
+
+func (d *TorrentDelta) empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0 && len(d.ServerState) == 0
+}
+
+// TorrentStream receives deltas for one instance, narrowed to the torrents
+// matching its filters and search.
+type TorrentStream struct {
+	instanceID int
+	filters    FilterOptions
+	search     string
+	visible    map[string]struct{}
+	updates    chan TorrentDelta
+	err        error
+	closed     bool
+	sm         *SyncManager
+}
+
+// Updates returns the channel deltas are delivered on. It is closed when the
+// stream ends; check Err afterwards.
+func (s *TorrentStream) Updates() <-chan TorrentDelta {
+	return s.updates
+}
+
+// Err reports why the stream ended, or nil if it was closed by its consumer.
+func (s *TorrentStream) Err() error {
+	s.sm.torrentStreamMu.Lock()
+	defer s.sm.torrentStreamMu.Unlock()
+	return s.err
+}
+
+// Close unsubscribes the stream. It is safe to call more than once.
+func (s *TorrentStream) Close() {
+	s.sm.torrentStreamMu.Lock()
+	defer s.sm.torrentStreamMu.Unlock()
+	s.sm.removeTorrentStreamLocked(s, nil)
+}
+
+// torrentFeed is the shared per-instance state behind every stream for that
+// instance. One pump goroutine syncs and diffs while it has subscribers.
+type torrentFeed struct {
+	instanceID  int
+	torrents    map[string]qbt.Torrent
+	serverState qbt.ServerState
+	subscribers map[*TorrentStream]struct{}
+	cancel      context.CancelFunc
+}
+
+// torrentStreamView carries what filtering needs from a single sync.
+type torrentStreamView struct {
+	client           *Client
+	mainData         *qbt.MainData
+	categories       map[string]qbt.Category
+	useSubcategories bool
+	trackers         map[string][]qbt.TorrentTracker
+}
+
+// rawTorrentDelta is the unfiltered difference between two syncs.
+type rawTorrentDelta struct {
+	added       []qbt.Torrent
+	changed     map[string]map[string]any
+	updated     []qbt.Torrent
+	removed     []string
+	serverState map[string]any
+}
+
+// SubscribeTorrentDeltas starts streaming torrent list changes for an instance.
+// The returned snapshot is the stream's starting view; every later delta is
+// relative to it. Callers must Close the stream when done.
+func (sm *SyncManager) SubscribeTorrentDeltas(ctx context.Context, instanceID int, filters FilterOptions, search string) (*TorrentStream, *TorrentSnapshot, error) {
+	client, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mainData := syncManager.GetData()
+	if mainData == nil {
+		return nil, nil, errors.New("sync data not available")
+	}
+	view := sm.torrentStreamView(ctx, client, instanceID, mainData, filtersRequireTrackerData(filters))
+
+	stream := sm.newTorrentStream(instanceID, filters, search)
+
+	sm.torrentStreamMu.Lock()
+	defer sm.torrentStreamMu.Unlock()
+
+	feed := sm.torrentFeeds[instanceID]
+	if feed == nil {
+		feed = &torrentFeed{
+			instanceID:  instanceID,
+			torrents:    mainData.Torrents,
+			serverState: mainData.ServerState,
+			subscribers: make(map[*TorrentStream]struct{}),
+		}
+		pumpCtx, cancel := context.WithCancel(context.Background())
+		feed.cancel = cancel
+		sm.torrentFeeds[instanceID] = feed
+		go sm.runTorrentFeed(pumpCtx, feed)
+	}
+
+	return stream, sm.attachTorrentStreamLocked(feed, stream, view), nil
+}
+
+func (sm *SyncManager) newTorrentStream(instanceID int, filters FilterOptions, search string) *TorrentStream {
+	return &TorrentStream{
+		instanceID: instanceID,
+		filters:    filters,
+		search:     search,
+		visible:    make(map[string]struct{}),
+		updates:    make(chan TorrentDelta, torrentStreamBuffer),
+		sm:         sm,
+	}
+}
+
+// attachTorrentStreamLocked seeds a stream's visible set from the feed's
+// current state and subscribes it. Callers hold torrentStreamMu.
+func (sm *SyncManager) attachTorrentStreamLocked(feed *torrentFeed, stream *TorrentStream, view torrentStreamView) *TorrentSnapshot {
+	candidates := make([]qbt.Torrent, 0, len(feed.torrents))
+	for _, torrent := range feed.torrents {
+		candidates = append(candidates, torrent)
+	}
+	matched := sm.filterStreamTorrents(stream, view, candidates)
+	for _, torrent := range matched {
+		stream.visible[torrent.Hash] = struct{}{}
+	}
+	slices.SortFunc(matched, func(a, b qbt.Torrent) int { return strings.Compare(a.Hash, b.Hash) })
+
+	feed.subscribers[stream] = struct{}{}
+
+	return &TorrentSnapshot{
+		InstanceID:  feed.instanceID,
+		Torrents:    matched,
+		ServerState: feed.serverState,
+		Time:        time.Now().UTC(),
+	}
+}
+
+func (sm *SyncManager) runTorrentFeed(ctx context.Context, feed *torrentFeed) {
+	ticker := time.NewTicker(sm.torrentStreamInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		client, syncManager, err := sm.getClientAndSyncManager(ctx, feed.instanceID)
+		if err != nil {
+			log.Debug().Err(err).Int("instanceID", feed.instanceID).Msg("torrent stream: client unavailable")
+			continue
+		}
+		if err := syncManager.Sync(ctx); err != nil {
+			if ctx.Err() == nil {
+				log.Debug().Err(err).Int("instanceID", feed.instanceID).Msg("torrent stream: sync failed")
+			}
+			continue
+		}
+		mainData := syncManager.GetDataUnchecked()
+		if mainData == nil {
+			continue
+		}
+
+		sm.torrentStreamMu.Lock()
+		needsTrackers := false
+		for stream := range feed.subscribers {
+			if filtersRequireTrackerData(stream.filters) {
+				needsTrackers = true
+				break
+			}
+		}
+		sm.torrentStreamMu.Unlock()
+
+		view := sm.torrentStreamView(ctx, client, feed.instanceID, mainData, needsTrackers)
+
+		sm.torrentStreamMu.Lock()
+		if ctx.Err() == nil {
+			sm.applyTorrentFeedLocked(feed, view)
+		}
+		sm.torrentStreamMu.Unlock()
+	}
+}
+
+func (sm *SyncManager) torrentStreamView(ctx context.Context, client *Client, instanceID int, mainData *qbt.MainData, needsTrackers bool) torrentStreamView {
+	categories, err := sm.GetCategories(ctx, instanceID)
+	if err != nil {
+		categories = mainData.Categories
+	}
+
+	view := torrentStreamView{
+		client:           client,
+		mainData:         mainData,
+		categories:       categories,
+		useSubcategories: resolveUseSubcategories(client.SupportsSubcategories(), mainData, categories),
+	}
+
+	if needsTrackers && client.supportsTrackerInclude() {
+		torrents := make([]qbt.Torrent, 0, len(mainData.Torrents))
+		for _, torrent := range mainData.Torrents {
+			torrents = append(torrents, torrent)
+		}
+		_, view.trackers, _ = sm.enrichTorrentsWithTrackerData(ctx, client, torrents, nil)
+	}
+
+	return view
+}
+
+// applyTorrentFeedLocked diffs the view against the feed's last state and
+// hands each subscriber its filtered delta. Callers hold torrentStreamMu.
+func (sm *SyncManager) applyTorrentFeedLocked(feed *torrentFeed, view torrentStreamView) {
+	raw := diffTorrentState(feed.torrents, view.mainData.Torrents, feed.serverState, view.mainData.ServerState)
+	feed.torrents = view.mainData.Torrents
+	feed.serverState = view.mainData.ServerState
+
+	if len(raw.added) == 0 && len(raw.updated) == 0 && len(raw.removed) == 0 && len(raw.serverState) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	for stream := range feed.subscribers {
+		delta := sm.streamDelta(stream, view, raw)
+		if delta.empty() {
+			continue
+		}
+		delta.InstanceID = feed.instanceID
+		delta.Time = now
+
+		select {
+		case stream.updates <- delta:
+		default:
+			log.Debug().Int("instanceID", feed.instanceID).Msg("torrent stream: subscriber fell behind, dropping")
+			sm.removeTorrentStreamLocked(stream, ErrTorrentStreamOverflow)
+		}
+	}
+}
+
+// streamDelta narrows a raw delta to one stream's view and updates the set of
+// hashes that stream can see.
+func (sm *SyncManager) streamDelta(stream *TorrentStream, view torrentStreamView, raw rawTorrentDelta) TorrentDelta {
+	delta := TorrentDelta{ServerState: raw.serverState}
+
+	for _, hash := range raw.removed {
+		if _, ok := stream.visible[hash]; ok {
+			delete(stream.visible, hash)
+			delta.Removed = append(delta.Removed, hash)
+		}
+	}
+
+	candidates := make([]qbt.Torrent, 0, len(raw.added)+len(raw.updated))
+	candidates = append(candidates, raw.added...)
+	candidates = append(candidates, raw.updated...)
+	if len(candidates) == 0 {
+		return delta
+	}
+
+	matched := make(map[string]struct{})
+	for _, torrent := range sm.filterStreamTorrents(stream, view, candidates) {
+		matched[torrent.Hash] = struct{}{}
+	}
+
+	for _, torrent := range candidates {
+		_, inView := matched[torrent.Hash]
+		_, wasVisible := stream.visible[torrent.Hash]
+		switch {
+		case inView && !wasVisible:
+			stream.visible[torrent.Hash] = struct{}{}
+			delta.Added = append(delta.Added, torrent)
+		case inView && wasVisible:
+			if delta.Changed == nil {
+				delta.Changed = make(map[string]map[string]any)
+			}
+			delta.Changed[torrent.Hash] = raw.changed[torrent.Hash]
+		case !inView && wasVisible:
+			delete(stream.visible, torrent.Hash)
+			delta.Removed = append(delta.Removed, torrent.Hash)
+		}
+	}
+
+	return delta
+}
+
+func (sm *SyncManager) filterStreamTorrents(stream *TorrentStream, view torrentStreamView, torrents []qbt.Torrent) []qbt.Torrent {
+	if view.trackers != nil {
+		hydrated := make([]qbt.Torrent, len(torrents))
+		for i, torrent := range torrents {
+			if trackers, ok := view.trackers[torrent.Hash]; ok {
+				torrent.Trackers = trackers
+			}
+			hydrated[i] = torrent
+		}
+		torrents = hydrated
+	}
+
+	filtered := sm.applyManualFilters(view.client, torrents, stream.filters, view.mainData, view.categories, view.useSubcategories)
+	return sm.filterTorrentsBySearch(filtered, stream.search)
+}
+
+// removeTorrentStreamLocked detaches a stream, closing its channel, and stops
+// the instance's pump once nobody is listening. Callers hold torrentStreamMu.
+func (sm *SyncManager) removeTorrentStreamLocked(stream *TorrentStream, reason error) {
+	if stream.closed {
+		return
+	}
+	stream.closed = true
+	stream.err = reason
+	close(stream.updates)
+
+	feed := sm.torrentFeeds[stream.instanceID]
+	if feed == nil {
+		return
+	}
+	delete(feed.subscribers, stream)
+	if len(feed.subscribers) == 0 {
+		feed.cancel()
+		delete(sm.torrentFeeds, stream.instanceID)
+	}
+}
+
+// diffTorrentState compares two torrent maps and server states.
+func diffTorrentState(prev, next map[string]qbt.Torrent, prevState, nextState qbt.ServerState) rawTorrentDelta {
+	raw := rawTorrentDelta{changed: make(map[string]map[string]any)}
+
+	for hash, torrent := range next {
+		old, ok := prev[hash]
+		if !ok {
+			raw.added = append(raw.added, torrent)
+			continue
+		}
+		if fields := diffFields(torrentDiffFields, reflect.ValueOf(old), reflect.ValueOf(torrent)); len(fields) > 0 {
+			raw.changed[hash] = fields
+			raw.updated = append(raw.updated, torrent)
+		}
+	}
+	for hash := range prev {
+		if _, ok := next[hash]; !ok {
+			raw.removed = append(raw.removed, hash)
+		}
+	}
+
+	raw.serverState = diffFields(serverStateDiffFields, reflect.ValueOf(prevState), reflect.ValueOf(nextState))
+
+	slices.SortFunc(raw.added, func(a, b qbt.Torrent) int { return strings.Compare(a.Hash, b.Hash) })
+	slices.SortFunc(raw.updated, func(a, b qbt.Torrent) int { return strings.Compare(a.Hash, b.Hash) })
+	slices.Sort(raw.removed)
+
+	return raw
+}
+
+type diffField struct {
+	index int
+	name  string
+}
+
+// Tracker lists are only hydrated for filtering and never part of maindata, so
+// they are left out of torrent diffs.
+var (
+	torrentDiffFields     = jsonDiffFields(reflect.TypeFor[qbt.Torrent](), "trackers")
+	serverStateDiffFields = jsonDiffFields(reflect.TypeFor[qbt.ServerState]())
+)
+
+func jsonDiffFields(t reflect.Type, skip ...string) []diffField {
+	fields := make([]diffField, 0, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || slices.Contains(skip, name) {
+			continue
+		}
+		fields = append(fields, diffField{index: i, name: name})
+	}
+	return fields
+}
+
+func diffFields(fields []diffField, prev, next reflect.Value) map[string]any {
+	var changed map[string]any
+	for _, field := range fields {
+		a := prev.Field(field.index)
+		b := next.Field(field.index)
+
+		var equal bool
+		if a.Comparable() {
+			equal = a.Equal(b)
+		} else {
+			equal = reflect.DeepEqual(a.Interface(), b.Interface())
+		}
+		if equal {
+			continue
+		}
+
+		if changed == nil {
+			changed = make(map[string]any)
+		}
+		changed[field.name] = b.Interface()
+	}
+	return changed
+}
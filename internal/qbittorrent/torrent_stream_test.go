@@ -0,0 +1,182 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"context"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamView(torrents map[string]qbt.Torrent, state qbt.ServerState) torrentStreamView {
+	return torrentStreamView{mainData: &qbt.MainData{Torrents: torrents, ServerState: state}}
+}
+
+func newTestTorrentFeed(torrents map[string]qbt.Torrent) (*SyncManager, *torrentFeed) {
+	sm := NewSyncManager(nil)
+	_, cancel := context.WithCancel(context.Background())
+	feed := &torrentFeed{
+		instanceID:  1,
+		torrents:    torrents,
+		subscribers: make(map[*TorrentStream]struct{}),
+		cancel:      cancel,
+	}
+	sm.torrentFeeds[1] = feed
+	return sm, feed
+}
+
+func TestDiffTorrentState(t *testing.T) {
+	t.Parallel()
+
+	prev := map[string]qbt.Torrent{
+		"a": {Hash: "a", Name: "Alpha", Progress: 0.5, Tags: "x"},
+		"b": {Hash: "b", Name: "Beta"},
+	}
+	next := map[string]qbt.Torrent{
+		"a": {Hash: "a", Name: "Alpha", Progress: 1, Tags: "x, y"},
+		"c": {Hash: "c", Name: "Gamma"},
+	}
+
+	raw := diffTorrentState(prev, next, qbt.ServerState{DlInfoSpeed: 1}, qbt.ServerState{DlInfoSpeed: 5})
+
+	require.Len(t, raw.added, 1)
+	assert.Equal(t, "c", raw.added[0].Hash)
+	assert.Equal(t, []string{"b"}, raw.removed)
+	assert.Equal(t, map[string]any{"progress": float64(1), "tags": "x, y"}, raw.changed["a"])
+	assert.Equal(t, map[string]any{"dl_info_speed": int64(5)}, raw.serverState)
+}
+
+func TestDiffTorrentStateIgnoresTrackers(t *testing.T) {
+	t.Parallel()
+
+	prev := map[string]qbt.Torrent{"a": {Hash: "a"}}
+	next := map[string]qbt.Torrent{"a": {Hash: "a", Trackers: []qbt.TorrentTracker{{Url: "https://tracker.example"}}}}
+
+	raw := diffTorrentState(prev, next, qbt.ServerState{}, qbt.ServerState{})
+	assert.Empty(t, raw.changed)
+	assert.Empty(t, raw.updated)
+	assert.Empty(t, raw.serverState)
+}
+
+func TestTorrentStreamFiltersDeltas(t *testing.T) {
+	t.Parallel()
+
+	initial := map[string]qbt.Torrent{
+		"a": {Hash: "a", Name: "Alpha", Category: "movies"},
+		"b": {Hash: "b", Name: "Beta", Category: "tv"},
+	}
+	sm, feed := newTestTorrentFeed(initial)
+
+	movies := sm.newTorrentStream(1, FilterOptions{Categories: []string{"movies"}}, "")
+	everything := sm.newTorrentStream(1, FilterOptions{}, "")
+
+	sm.torrentStreamMu.Lock()
+	snapshot := sm.attachTorrentStreamLocked(feed, movies, streamView(initial, qbt.ServerState{}))
+	all := sm.attachTorrentStreamLocked(feed, everything, streamView(initial, qbt.ServerState{}))
+	sm.torrentStreamMu.Unlock()
+
+	require.Len(t, snapshot.Torrents, 1)
+	assert.Equal(t, "a", snapshot.Torrents[0].Hash)
+	assert.Len(t, all.Torrents, 2)
+
+	// a leaves the movies view, b enters it, c is added outside it.
+	next := map[string]qbt.Torrent{
+		"a": {Hash: "a", Name: "Alpha", Category: "tv"},
+		"b": {Hash: "b", Name: "Beta", Category: "movies"},
+		"c": {Hash: "c", Name: "Gamma", Category: "tv"},
+	}
+	sm.torrentStreamMu.Lock()
+	sm.applyTorrentFeedLocked(feed, streamView(next, qbt.ServerState{UpInfoSpeed: 10}))
+	sm.torrentStreamMu.Unlock()
+
+	delta := <-movies.Updates()
+	require.Len(t, delta.Added, 1)
+	assert.Equal(t, "b", delta.Added[0].Hash)
+	assert.Equal(t, []string{"a"}, delta.Removed)
+	assert.Empty(t, delta.Changed)
+	assert.Equal(t, map[string]any{"up_info_speed": int64(10)}, delta.ServerState)
+
+	delta = <-everything.Updates()
+	require.Len(t, delta.Added, 1)
+	assert.Equal(t, "c", delta.Added[0].Hash)
+	assert.Empty(t, delta.Removed)
+	assert.Equal(t, map[string]any{"category": "tv"}, delta.Changed["a"])
+	assert.Equal(t, map[string]any{"category": "movies"}, delta.Changed["b"])
+
+	// Deleting a torrent only reaches streams that could see it.
+	final := map[string]qbt.Torrent{
+		"a": next["a"],
+		"b": next["b"],
+	}
+	sm.torrentStreamMu.Lock()
+	sm.applyTorrentFeedLocked(feed, streamView(final, qbt.ServerState{UpInfoSpeed: 10}))
+	sm.torrentStreamMu.Unlock()
+
+	delta = <-everything.Updates()
+	assert.Equal(t, []string{"c"}, delta.Removed)
+	assert.Empty(t, movies.Updates())
+}
+
+func TestTorrentStreamSearch(t *testing.T) {
+	t.Parallel()
+
+	initial := map[string]qbt.Torrent{
+		"a": {Hash: "a", Name: "Ubuntu 24.04"},
+		"b": {Hash: "b", Name: "Debian 12"},
+	}
+	sm, feed := newTestTorrentFeed(initial)
+	stream := sm.newTorrentStream(1, FilterOptions{}, "ubuntu")
+
+	sm.torrentStreamMu.Lock()
+	snapshot := sm.attachTorrentStreamLocked(feed, stream, streamView(initial, qbt.ServerState{}))
+	sm.torrentStreamMu.Unlock()
+
+	require.Len(t, snapshot.Torrents, 1)
+	assert.Equal(t, "a", snapshot.Torrents[0].Hash)
+}
+
+func TestTorrentStreamCloseStopsFeed(t *testing.T) {
+	t.Parallel()
+
+	sm, feed := newTestTorrentFeed(map[string]qbt.Torrent{})
+	first := sm.newTorrentStream(1, FilterOptions{}, "")
+	second := sm.newTorrentStream(1, FilterOptions{}, "")
+
+	sm.torrentStreamMu.Lock()
+	sm.attachTorrentStreamLocked(feed, first, streamView(nil, qbt.ServerState{}))
+	sm.attachTorrentStreamLocked(feed, second, streamView(nil, qbt.ServerState{}))
+	sm.torrentStreamMu.Unlock()
+
+	first.Close()
+	first.Close()
+	_, open := <-first.Updates()
+	assert.False(t, open)
+	assert.NoError(t, first.Err())
+	assert.Contains(t, sm.torrentFeeds, 1)
+
+	second.Close()
+	assert.NotContains(t, sm.torrentFeeds, 1)
+}
+
+func TestTorrentStreamOverflowDropsSubscriber(t *testing.T) {
+	t.Parallel()
+
+	sm, feed := newTestTorrentFeed(map[string]qbt.Torrent{})
+	stream := sm.newTorrentStream(1, FilterOptions{}, "")
+
+	sm.torrentStreamMu.Lock()
+	sm.attachTorrentStreamLocked(feed, stream, streamView(nil, qbt.ServerState{}))
+	for i := range torrentStreamBuffer + 1 {
+		sm.applyTorrentFeedLocked(feed, streamView(nil, qbt.ServerState{DlInfoSpeed: int64(i + 1)}))
+	}
+	sm.torrentStreamMu.Unlock()
+
+	for range stream.Updates() {
+	}
+	assert.ErrorIs(t, stream.Err(), ErrTorrentStreamOverflow)
+	assert.NotContains(t, sm.torrentFeeds, 1)
+}
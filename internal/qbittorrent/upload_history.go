@@ -0,0 +1,82 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+)
+
+// UploadHistory summarises a torrent's recorded upload activity.
+type UploadHistory struct {
+	// UploadedLast7d and UploadedLast30d are nil until the recorded history
+	// covers the whole window (or the torrent is younger than it).
+	UploadedLast7d  *int64 `json:"uploaded_last_7d,omitempty"`
+	UploadedLast30d *int64 `json:"uploaded_last_30d,omitempty"`
+	// LastUpload is when the uploaded counter was last seen growing (Unix
+	// seconds). It is zero when no upload has been seen since TrackedSince.
+	LastUpload int64 `json:"last_upload,omitempty"`
+	// TrackedSince is the oldest snapshot still held for the torrent (Unix seconds).
+	TrackedSince int64 `json:"tracked_since"`
+}
+
+// UploadHistoryProvider supplies recorded upload history for torrents.
+type UploadHistoryProvider interface {
+	UploadHistory(instanceID int, torrent qbt.Torrent, now time.Time) (UploadHistory, bool)
+}
+
+// TorrentExprEnv is the environment filter expressions are evaluated against:
+// every qBittorrent torrent field plus derived upload history. The history
+// fields are -1 while unknown, so guard with e.g. `UploadedLast7d >= 0`.
+type TorrentExprEnv struct {
+	qbt.Torrent
+	// UploadedLast7d and UploadedLast30d are bytes uploaded in the window.
+	UploadedLast7d  int64
+	UploadedLast30d int64
+	// LastUploadAge is the number of seconds since the torrent last uploaded,
+	// or -1 when no upload has been seen since tracking began.
+	LastUploadAge int64
+}
+
+// SetUploadHistoryProvider sets the source of per-torrent upload history.
+func (sm *SyncManager) SetUploadHistoryProvider(provider UploadHistoryProvider) {
+	sm.uploadHistory.Store(&provider)
+}
+
+// GetUploadHistory returns a torrent's recorded upload history, if any.
+func (sm *SyncManager) GetUploadHistory(instanceID int, torrent qbt.Torrent, now time.Time) (UploadHistory, bool) {
+	v := sm.uploadHistory.Load()
+	if v == nil || *v == nil {
+		return UploadHistory{}, false
+	}
+	return (*v).UploadHistory(instanceID, torrent, now)
+}
+
+func (sm *SyncManager) uploadHistoryView(instanceID int, torrent qbt.Torrent, now time.Time) *UploadHistory {
+	history, ok := sm.GetUploadHistory(instanceID, torrent, now)
+	if !ok {
+		return nil
+	}
+	return &history
+}
+
+func (sm *SyncManager) torrentExprEnv(instanceID int, torrent qbt.Torrent, now time.Time) TorrentExprEnv {
+	env := TorrentExprEnv{Torrent: torrent, UploadedLast7d: -1, UploadedLast30d: -1, LastUploadAge: -1}
+
+	history, ok := sm.GetUploadHistory(instanceID, torrent, now)
+	if !ok {
+		return env
+	}
+	if history.UploadedLast7d != nil {
+		env.UploadedLast7d = *history.UploadedLast7d
+	}
+	if history.UploadedLast30d != nil {
+		env.UploadedLast30d = *history.UploadedLast30d
+	}
+	if history.LastUpload > 0 {
+		env.LastUploadAge = max(now.Unix()-history.LastUpload, 0)
+	}
+	return env
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUploadHistory map[string]UploadHistory
+
+func (f fakeUploadHistory) UploadHistory(_ int, torrent qbt.Torrent, _ time.Time) (UploadHistory, bool) {
+	history, ok := f[torrent.Hash]
+	return history, ok
+}
+
+func TestApplyManualFilters_UploadHistoryExpr(t *testing.T) {
+	sm := NewSyncManager(nil)
+	idle, busy := int64(0), int64(4096)
+	sm.SetUploadHistoryProvider(fakeUploadHistory{
+		"idle":    {UploadedLast7d: &idle, LastUpload: time.Now().Add(-10 * 24 * time.Hour).Unix()},
+		"busy":    {UploadedLast7d: &busy, LastUpload: time.Now().Unix()},
+		"partial": {LastUpload: time.Now().Unix()},
+	})
+
+	torrents := []qbt.Torrent{
+		{Hash: "idle", Ratio: 2},
+		{Hash: "busy", Ratio: 2},
+		{Hash: "partial", Ratio: 2},
+		{Hash: "untracked", Ratio: 0.5},
+	}
+
+	testCases := []struct {
+		expr     string
+		expected []string
+	}{
+		{expr: "UploadedLast7d >= 0 && UploadedLast7d < 1024", expected: []string{"idle"}},
+		{expr: "UploadedLast7d < 0", expected: []string{"partial", "untracked"}},
+		{expr: "LastUploadAge > 7 * 86400", expected: []string{"idle"}},
+		{expr: "Ratio > 1", expected: []string{"idle", "busy", "partial"}},
+	}
+
+	for _, tc := range testCases {
+		result := sm.applyManualFilters(nil, torrents, FilterOptions{Expr: tc.expr}, nil, nil, false)
+		var hashes []string
+		for _, torrent := range result {
+			hashes = append(hashes, torrent.Hash)
+		}
+		assert.ElementsMatch(t, tc.expected, hashes, tc.expr)
+	}
+}
@@ -53,6 +53,11 @@ const (
 	FieldCompletionOnAge = models.FieldCompletionOnAge
 	FieldLastActivityAge = models.FieldLastActivityAge
 
+	// Upload history fields
+	FieldUploadedLast7d  = models.FieldUploadedLast7d
+	FieldUploadedLast30d = models.FieldUploadedLast30d
+	FieldLastUploadAge   = models.FieldLastUploadAge
+
 	// Numeric fields (float64)
 	FieldRatio        = models.FieldRatio
 	FieldProgress     = models.FieldProgress
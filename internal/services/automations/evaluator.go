@@ -12,6 +12,8 @@ import (
 
 	qbt "github.com/autobrr/go-qbittorrent"
 	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/qbittorrent"
 )
 
 const maxConditionDepth = 20
@@ -39,6 +41,11 @@ type EvalContext struct {
 	InstanceHasLocalAccess bool
 	// FreeSpace is the free space on the instance's filesystem
 	FreeSpace int64
+	// UploadHistoryByHash maps torrent hash to its recorded upload history
+	UploadHistoryByHash map[string]qbittorrent.UploadHistory
+	// HnRByHash maps torrent hash to its hit-and-run compliance; torrents
+	// without a matching profile are absent
+	HnRByHash map[string]qbittorrent.HnRCompliance
 
 	// CategoryIndex maps lowercased category → lowercased name → set of hashes.
 	// Enables O(1) EXISTS_IN lookups while supporting self-exclusion.
@@ -340,6 +347,34 @@ func evaluateLeaf(cond *RuleCondition, torrent qbt.Torrent, ctx *EvalContext) bo
 		}
 		return compareAge(torrent.LastActivity, cond, ctx)
 
+	// Upload history fields. Torrents without enough recorded history don't
+	// match, so rules never act on a window that hasn't been observed yet.
+	case FieldUploadedLast7d, FieldUploadedLast30d, FieldLastUploadAge:
+		if ctx == nil || ctx.UploadHistoryByHash == nil {
+			return false
+		}
+		history, ok := ctx.UploadHistoryByHash[torrent.Hash]
+		if !ok {
+			return false
+		}
+		switch cond.Field {
+		case FieldUploadedLast7d:
+			if history.UploadedLast7d == nil {
+				return false
+			}
+			return compareInt64(*history.UploadedLast7d, cond)
+		case FieldUploadedLast30d:
+			if history.UploadedLast30d == nil {
+				return false
+			}
+			return compareInt64(*history.UploadedLast30d, cond)
+		default:
+			if history.LastUpload == 0 {
+				return compareUnknownUploadAge(history.TrackedSince, cond, ctx)
+			}
+			return compareAge(history.LastUpload, cond, ctx)
+		}
+
 	// Float64 fields
 	case FieldRatio:
 		return compareFloat64(torrent.Ratio, cond)
@@ -702,6 +737,21 @@ func compareAge(timestamp int64, cond *RuleCondition, ctx *EvalContext) bool {
 	return compareInt64(ageSeconds, cond)
 }
 
+// compareUnknownUploadAge handles torrents with no upload seen since tracking
+// began. Their real age is unknown but at least the time tracked, so only
+// greater-than comparisons that this lower bound already satisfies can match.
+func compareUnknownUploadAge(trackedSince int64, cond *RuleCondition, ctx *EvalContext) bool {
+	if trackedSince == 0 {
+		return false
+	}
+	switch cond.Operator {
+	case OperatorGreaterThan, OperatorGreaterThanOrEqual:
+		return compareAge(trackedSince, cond, ctx)
+	default:
+		return false
+	}
+}
+
 // splitTags splits a comma-separated tag string into individual tags.
 // Returns nil for empty input.
 func splitTags(raw string) []string {
@@ -5,8 +5,11 @@ package automations
 
 import (
 	"testing"
+	"time"
 
 	qbt "github.com/autobrr/go-qbittorrent"
+
+	"github.com/autobrr/qui/internal/qbittorrent"
 )
 
 func TestEvaluateCondition_StringFields(t *testing.T) {
@@ -1387,6 +1390,83 @@ func TestEvaluateCondition_AgeFields(t *testing.T) {
 	}
 }
 
+func TestEvaluateCondition_UploadHistory(t *testing.T) {
+	nowUnix := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC).Unix()
+	uploaded7d := int64(512)
+	torrent := qbt.Torrent{Hash: "abc123"}
+	history := map[string]qbittorrent.UploadHistory{
+		"abc123": {UploadedLast7d: &uploaded7d, LastUpload: nowUnix - 3*86400, TrackedSince: nowUnix - 10*86400},
+	}
+	idle := map[string]qbittorrent.UploadHistory{
+		"abc123": {TrackedSince: nowUnix - 10*86400},
+	}
+
+	tests := []struct {
+		name     string
+		cond     *RuleCondition
+		evalCtx  *EvalContext
+		expected bool
+	}{
+		{
+			name:     "uploaded last 7d below threshold - matches",
+			cond:     &RuleCondition{Field: FieldUploadedLast7d, Operator: OperatorLessThan, Value: "1024"},
+			evalCtx:  &EvalContext{UploadHistoryByHash: history, NowUnix: nowUnix},
+			expected: true,
+		},
+		{
+			name:     "uploaded last 30d not yet covered - does not match",
+			cond:     &RuleCondition{Field: FieldUploadedLast30d, Operator: OperatorLessThan, Value: "1024"},
+			evalCtx:  &EvalContext{UploadHistoryByHash: history, NowUnix: nowUnix},
+			expected: false,
+		},
+		{
+			name:     "last upload age greater than 2 days - matches",
+			cond:     &RuleCondition{Field: FieldLastUploadAge, Operator: OperatorGreaterThan, Value: "172800"},
+			evalCtx:  &EvalContext{UploadHistoryByHash: history, NowUnix: nowUnix},
+			expected: true,
+		},
+		{
+			name:     "no upload since tracking, age above tracked time - matches lower bound",
+			cond:     &RuleCondition{Field: FieldLastUploadAge, Operator: OperatorGreaterThan, Value: "432000"},
+			evalCtx:  &EvalContext{UploadHistoryByHash: idle, NowUnix: nowUnix},
+			expected: true,
+		},
+		{
+			name:     "no upload since tracking, age beyond tracked time - unknown",
+			cond:     &RuleCondition{Field: FieldLastUploadAge, Operator: OperatorGreaterThan, Value: "1728000"},
+			evalCtx:  &EvalContext{UploadHistoryByHash: idle, NowUnix: nowUnix},
+			expected: false,
+		},
+		{
+			name:     "no upload since tracking, less than - unknown",
+			cond:     &RuleCondition{Field: FieldLastUploadAge, Operator: OperatorLessThan, Value: "2592000"},
+			evalCtx:  &EvalContext{UploadHistoryByHash: idle, NowUnix: nowUnix},
+			expected: false,
+		},
+		{
+			name:     "untracked torrent - does not match",
+			cond:     &RuleCondition{Field: FieldLastUploadAge, Operator: OperatorGreaterThan, Value: "0"},
+			evalCtx:  &EvalContext{UploadHistoryByHash: map[string]qbittorrent.UploadHistory{}, NowUnix: nowUnix},
+			expected: false,
+		},
+		{
+			name:     "history not loaded - does not match",
+			cond:     &RuleCondition{Field: FieldUploadedLast7d, Operator: OperatorLessThan, Value: "1024"},
+			evalCtx:  &EvalContext{NowUnix: nowUnix},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvaluateConditionWithContext(tt.cond, torrent, tt.evalCtx, 0)
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestEvaluateCondition_HardlinkScope(t *testing.T) {
 	torrent := qbt.Torrent{
 		Hash: "abc123",
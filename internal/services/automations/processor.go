@@ -60,6 +60,7 @@ type ruleRunStats struct {
 	CategoryConditionNotMetOrBlocked int
 	DeleteApplied                    int
 	DeleteConditionNotMet            int
+	DeleteBlockedHnR                 int
 }
 
 func (s *ruleRunStats) totalApplied() int {
@@ -269,7 +270,11 @@ func processRuleForTorrent(rule *models.Automation, torrent qbt.Torrent, state *
 			}
 		} else {
 			shouldApply := EvaluateConditionWithContext(conditions.Delete.Condition, torrent, evalCtx, 0)
-			if shouldApply {
+			if shouldApply && hnrBlocksDelete(torrent, conditions.Delete, evalCtx) {
+				if stats != nil {
+					stats.DeleteBlockedHnR++
+				}
+			} else if shouldApply {
 				if stats != nil {
 					stats.DeleteApplied++
 				}
@@ -387,6 +392,16 @@ func processTagAction(tagAction *models.TagAction, torrent qbt.Torrent, state *t
 	return matchesCondition
 }
 
+// hnrBlocksDelete reports whether a delete must be skipped because the torrent
+// hasn't met its tracker's hit-and-run requirements yet.
+func hnrBlocksDelete(torrent qbt.Torrent, action *models.DeleteAction, evalCtx *EvalContext) bool {
+	if action.IgnoreHnR || evalCtx == nil {
+		return false
+	}
+	compliance, ok := evalCtx.HnRByHash[torrent.Hash]
+	return ok && !compliance.Status.IsCompliant()
+}
+
 // hasActions returns true if the state has any actions to execute.
 func hasActions(state *torrentDesiredState) bool {
 	return state.uploadLimitKiB != nil ||
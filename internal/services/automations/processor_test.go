@@ -136,3 +136,44 @@ func TestProcessTorrents_CategoryAllowedWhenProtectedCrossSeedDifferentSavePath(
 	_, ok := states["a"]
 	require.True(t, ok, "expected category action to apply when protected torrent is not in the same cross-seed group")
 }
+
+func TestProcessTorrents_DeleteBlockedUntilHnRCompliant(t *testing.T) {
+	sm := qbittorrent.NewSyncManager(nil)
+
+	torrents := []qbt.Torrent{
+		{Hash: "pending", Name: "pending", Ratio: 0.2},
+		{Hash: "compliant", Name: "compliant", Ratio: 0.2},
+		{Hash: "untracked", Name: "untracked", Ratio: 0.2},
+	}
+	evalCtx := &EvalContext{
+		HnRByHash: map[string]qbittorrent.HnRCompliance{
+			"pending":   {Status: qbittorrent.HnRStatusPending},
+			"compliant": {Status: qbittorrent.HnRStatusCompliant},
+		},
+	}
+
+	newRule := func(ignoreHnR bool) *models.Automation {
+		return &models.Automation{
+			ID:             1,
+			Enabled:        true,
+			TrackerPattern: "*",
+			Conditions: &models.ActionConditions{
+				SchemaVersion: "1",
+				Delete: &models.DeleteAction{
+					Enabled:   true,
+					IgnoreHnR: ignoreHnR,
+					Condition: &models.RuleCondition{Field: models.FieldRatio, Operator: models.OperatorLessThan, Value: "1"},
+				},
+			},
+		}
+	}
+
+	states := processTorrents(torrents, []*models.Automation{newRule(false)}, evalCtx, sm, nil, nil)
+	_, ok := states["pending"]
+	require.False(t, ok, "expected non-compliant torrent to be protected from deletion")
+	require.True(t, states["compliant"].shouldDelete)
+	require.True(t, states["untracked"].shouldDelete)
+
+	states = processTorrents(torrents, []*models.Automation{newRule(true)}, evalCtx, sm, nil, nil)
+	require.True(t, states["pending"].shouldDelete, "expected override to allow deletion")
+}
@@ -21,6 +21,7 @@ import (
 	qbt "github.com/autobrr/go-qbittorrent"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/pkg/hardlink"
@@ -62,6 +63,7 @@ type Service struct {
 	activityStore             *models.AutomationActivityStore
 	trackerCustomizationStore *models.TrackerCustomizationStore
 	syncManager               *qbittorrent.SyncManager
+	eventBus                  *events.Bus
 
 	// keep lightweight memory of recent applications to avoid hammering qBittorrent
 	lastApplied map[int]map[string]time.Time // instanceID -> hash -> timestamp
@@ -94,6 +96,11 @@ func NewService(cfg Config, instanceStore *models.InstanceStore, ruleStore *mode
 	}
 }
 
+// SetEventBus sets the bus applied rule actions are published to.
+func (s *Service) SetEventBus(bus *events.Bus) {
+	s.eventBus = bus
+}
+
 // cleanupStaleEntries removes entries from lastApplied and lastRuleRun maps
 // that are older than the cutoff to prevent unbounded memory growth.
 func (s *Service) cleanupStaleEntries() {
@@ -323,6 +330,9 @@ func (s *Service) PreviewDeleteRule(ctx context.Context, instanceID int, rule *m
 		}
 	}
 
+	evalCtx.UploadHistoryByHash = s.uploadHistoryByHash(instanceID, torrents)
+	evalCtx.HnRByHash = s.hnrByHash(torrents)
+
 	// Check if rule uses hardlink conditions and populate context
 	if instance != nil && instance.HasLocalFilesystemAccess && rule.Conditions != nil && rule.Conditions.Delete != nil {
 		cond := rule.Conditions.Delete.Condition
@@ -351,6 +361,10 @@ func (s *Service) PreviewDeleteRule(ctx context.Context, instanceID int, rule *m
 			}
 		}
 
+		if wouldDelete && hnrBlocksDelete(torrent, rule.Conditions.Delete, evalCtx) {
+			wouldDelete = false
+		}
+
 		if wouldDelete {
 			matchIndex++
 			if matchIndex <= offset {
@@ -424,6 +438,8 @@ func (s *Service) PreviewCategoryRule(ctx context.Context, instanceID int, rule
 		evalCtx.TrackerDownSet = healthCounts.TrackerDownSet
 	}
 
+	evalCtx.UploadHistoryByHash = s.uploadHistoryByHash(instanceID, torrents)
+
 	// Check if rule uses hardlink conditions
 	if instance != nil && instance.HasLocalFilesystemAccess && rule.Conditions != nil && rule.Conditions.Category != nil {
 		cond := rule.Conditions.Category.Condition
@@ -602,6 +618,18 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 		evalCtx.FreeSpace = freeSpace
 	}
 
+	// Look up hit-and-run compliance (only if a delete rule respects it)
+	if rulesRespectHnR(eligibleRules) {
+		evalCtx.HnRByHash = s.hnrByHash(torrents)
+	}
+
+	// Look up recorded upload history (only if rules use upload history fields)
+	if rulesUseCondition(eligibleRules, FieldUploadedLast7d) ||
+		rulesUseCondition(eligibleRules, FieldUploadedLast30d) ||
+		rulesUseCondition(eligibleRules, FieldLastUploadAge) {
+		evalCtx.UploadHistoryByHash = s.uploadHistoryByHash(instanceID, torrents)
+	}
+
 	// Load tracker display names if any rule uses UseTrackerAsTag with UseDisplayName
 	if rulesUseTrackerDisplayName(eligibleRules) && s.trackerCustomizationStore != nil {
 		customizations, err := s.trackerCustomizationStore.List(ctx)
@@ -679,6 +707,7 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 				Int("tagMissingUnregisteredSet", stats.TagSkippedMissingUnregisteredSet).
 				Int("categoryNoMatchOrBlocked", stats.CategoryConditionNotMetOrBlocked).
 				Int("deleteNoMatch", stats.DeleteConditionNotMet).
+				Int("deleteBlockedHnR", stats.DeleteBlockedHnR).
 				Msg("automations: rule matched trackers but applied no actions")
 		}
 	}
@@ -950,6 +979,22 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 			} else {
 				log.Info().Int("instanceID", instanceID).Int("count", len(batch)).Msg("automations: paused torrents")
 				pausedCount += len(batch)
+
+				names := make([]string, len(batch))
+				for i, hash := range batch {
+					if torrent, ok := torrentByHash[hash]; ok {
+						names[i] = torrent.Name
+					}
+				}
+				s.eventBus.Publish(events.Event{
+					Type:       events.AutomationRuleApplied,
+					InstanceID: instanceID,
+					Payload: events.RuleApplied{
+						Action: events.RuleActionPaused,
+						Hashes: slices.Clone(batch),
+						Names:  names,
+					},
+				})
 			}
 		}
 	}
@@ -1171,6 +1216,24 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 		}
 	}
 
+	if len(successfulMoves) > 0 {
+		hashes := make([]string, len(successfulMoves))
+		names := make([]string, len(successfulMoves))
+		for i, move := range successfulMoves {
+			hashes[i] = move.hash
+			names[i] = move.name
+		}
+		s.eventBus.Publish(events.Event{
+			Type:       events.AutomationRuleApplied,
+			InstanceID: instanceID,
+			Payload: events.RuleApplied{
+				Action: events.RuleActionCategoryChanged,
+				Hashes: hashes,
+				Names:  names,
+			},
+		})
+	}
+
 	// Record aggregated category activity (like tags)
 	if s.activityStore != nil && len(successfulMoves) > 0 {
 		categoryCounts := make(map[string]int) // category -> count of torrents moved
@@ -1243,6 +1306,28 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 					log.Info().Int("instanceID", instanceID).Int("count", len(batch)).Msg("automations: removed torrents with files")
 				}
 
+				names := make([]string, len(batch))
+				rules := make([]string, 0, 1)
+				for i, hash := range batch {
+					if pending, ok := pendingByHash[hash]; ok {
+						names[i] = pending.torrentName
+						if !slices.Contains(rules, pending.ruleName) {
+							rules = append(rules, pending.ruleName)
+						}
+					}
+				}
+				s.eventBus.Publish(events.Event{
+					Type:       events.AutomationRuleApplied,
+					InstanceID: instanceID,
+					Payload: events.RuleApplied{
+						Action:     events.RuleActionDeleted,
+						DeleteMode: mode,
+						RuleNames:  rules,
+						Hashes:     slices.Clone(batch),
+						Names:      names,
+					},
+				})
+
 				// Record successful deletion activity
 				if s.activityStore != nil {
 					for _, hash := range batch {
@@ -1440,6 +1525,42 @@ func detectCrossSeeds(target qbt.Torrent, allTorrents []qbt.Torrent) bool {
 }
 
 // rulesUseCondition checks if any enabled rule uses the given field.
+// uploadHistoryByHash looks up the recorded upload history of each torrent.
+func (s *Service) uploadHistoryByHash(instanceID int, torrents []qbt.Torrent) map[string]qbittorrent.UploadHistory {
+	now := time.Now()
+	byHash := make(map[string]qbittorrent.UploadHistory)
+	for _, torrent := range torrents {
+		if history, ok := s.syncManager.GetUploadHistory(instanceID, torrent, now); ok {
+			byHash[torrent.Hash] = history
+		}
+	}
+	return byHash
+}
+
+// hnrByHash computes hit-and-run compliance for torrents covered by a profile.
+func (s *Service) hnrByHash(torrents []qbt.Torrent) map[string]qbittorrent.HnRCompliance {
+	now := time.Now()
+	byHash := make(map[string]qbittorrent.HnRCompliance)
+	for _, torrent := range torrents {
+		if compliance, ok := s.syncManager.GetHnRCompliance(torrent, now); ok {
+			byHash[torrent.Hash] = compliance
+		}
+	}
+	return byHash
+}
+
+// rulesRespectHnR reports whether any enabled rule deletes without overriding
+// hit-and-run protection.
+func rulesRespectHnR(rules []*models.Automation) bool {
+	for _, rule := range rules {
+		if rule.Enabled && rule.Conditions != nil && rule.Conditions.Delete != nil &&
+			rule.Conditions.Delete.Enabled && !rule.Conditions.Delete.IgnoreHnR {
+			return true
+		}
+	}
+	return false
+}
+
 func rulesUseCondition(rules []*models.Automation, field ConditionField) bool {
 	for _, rule := range rules {
 		if rule.Conditions == nil || !rule.Enabled {
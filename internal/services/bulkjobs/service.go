@@ -0,0 +1,635 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package bulkjobs runs bulk torrent operations in the background, in chunks, with
+// per-torrent results and undo for operations that only change torrent settings.
+package bulkjobs
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+// chunkSize bounds how many torrents are sent to qBittorrent per call so progress
+// advances steadily and a failing call only affects a small slice of the selection.
+const chunkSize = 100
+
+// maxJobs bounds how many finished jobs are kept in memory.
+const maxJobs = 20
+
+var (
+	// ErrJobRunning is returned when a job is submitted while another runs on the same instance.
+	ErrJobRunning = errors.New("a bulk job is already running for this instance")
+	// ErrJobNotFound is returned for unknown job IDs.
+	ErrJobNotFound = errors.New("bulk job not found")
+	// ErrJobNotRunning is returned when canceling a job that already finished.
+	ErrJobNotRunning = errors.New("bulk job is not running")
+	// ErrNotUndoable is returned when undoing a job that has no recorded previous state.
+	ErrNotUndoable = errors.New("bulk job cannot be undone")
+)
+
+// Actions accepted by Spec.Action. They match the synchronous bulk action endpoint.
+var validActions = []string{
+	"pause", "resume", "delete", "deleteWithFiles",
+	"recheck", "reannounce", "increasePriority", "decreasePriority",
+	"topPriority", "bottomPriority", "addTags", "removeTags", "setTags", "setCategory",
+	"toggleAutoTMM", "forceStart", "setShareLimit", "setUploadLimit", "setDownloadLimit", "setLocation",
+	"editTrackers", "addTrackers", "removeTrackers", "toggleSequentialDownload",
+}
+
+// reversibleActions record the previous torrent state so the job can be undone.
+var reversibleActions = []string{
+	"addTags", "removeTags", "setTags", "setCategory",
+	"setShareLimit", "setUploadLimit", "setDownloadLimit", "setLocation",
+}
+
+type torrentClient interface {
+	GetTorrents(ctx context.Context, instanceID int, filter qbt.TorrentFilterOptions) ([]qbt.Torrent, error)
+	BulkAction(ctx context.Context, instanceID int, hashes []string, action string) error
+	AddTags(ctx context.Context, instanceID int, hashes []string, tags string) error
+	RemoveTags(ctx context.Context, instanceID int, hashes []string, tags string) error
+	SetTags(ctx context.Context, instanceID int, hashes []string, tags string) error
+	SetCategory(ctx context.Context, instanceID int, hashes []string, category string) error
+	SetAutoTMM(ctx context.Context, instanceID int, hashes []string, enable bool) error
+	SetForceStart(ctx context.Context, instanceID int, hashes []string, enable bool) error
+	SetTorrentShareLimit(ctx context.Context, instanceID int, hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int64) error
+	SetTorrentUploadLimit(ctx context.Context, instanceID int, hashes []string, limitKBs int64) error
+	SetTorrentDownloadLimit(ctx context.Context, instanceID int, hashes []string, limitKBs int64) error
+	SetLocation(ctx context.Context, instanceID int, hashes []string, location string) error
+	BulkEditTrackers(ctx context.Context, instanceID int, hashes []string, oldURL, newURL string) error
+	BulkAddTrackers(ctx context.Context, instanceID int, hashes []string, urls string) error
+	BulkRemoveTrackers(ctx context.Context, instanceID int, hashes []string, urls string) error
+}
+
+// Spec describes the operation a job applies to every selected torrent. Fields other
+// than Action are only read by the actions that use them.
+type Spec struct {
+	Action                   string  `json:"action"`
+	DeleteFiles              bool    `json:"deleteFiles,omitempty"`
+	Tags                     string  `json:"tags,omitempty"`
+	Category                 string  `json:"category,omitempty"`
+	Enable                   bool    `json:"enable,omitempty"`
+	RatioLimit               float64 `json:"ratioLimit,omitempty"`
+	SeedingTimeLimit         int64   `json:"seedingTimeLimit,omitempty"`
+	InactiveSeedingTimeLimit int64   `json:"inactiveSeedingTimeLimit,omitempty"`
+	UploadLimit              int64   `json:"uploadLimit,omitempty"`
+	DownloadLimit            int64   `json:"downloadLimit,omitempty"`
+	Location                 string  `json:"location,omitempty"`
+	TrackerOldURL            string  `json:"trackerOldURL,omitempty"`
+	TrackerNewURL            string  `json:"trackerNewURL,omitempty"`
+	TrackerURLs              string  `json:"trackerURLs,omitempty"`
+}
+
+// Validate returns a user-facing error when the spec cannot be applied.
+func (s Spec) Validate() error {
+	switch {
+	case !slices.Contains(validActions, s.Action):
+		return errors.New("invalid action")
+	case (s.Action == "addTags" || s.Action == "removeTags") && s.Tags == "":
+		return fmt.Errorf("tags parameter is required for %s action", s.Action)
+	case s.Action == "setLocation" && strings.TrimSpace(s.Location) == "":
+		return errors.New("location parameter is required for setLocation action")
+	case s.Action == "editTrackers" && (s.TrackerOldURL == "" || s.TrackerNewURL == ""):
+		return errors.New("both trackerOldURL and trackerNewURL are required for editTrackers action")
+	case (s.Action == "addTrackers" || s.Action == "removeTrackers") && s.TrackerURLs == "":
+		return fmt.Errorf("trackerURLs parameter is required for %s action", s.Action)
+	default:
+		return nil
+	}
+}
+
+// Reversible reports whether jobs running this spec record state for undo.
+func (s Spec) Reversible() bool {
+	return slices.Contains(reversibleActions, s.Action)
+}
+
+// JobStatus is the lifecycle state of a bulk job.
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// Progress reports how many of the selected torrents have been processed.
+type Progress struct {
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+}
+
+// HashError is the failure for one torrent.
+type HashError struct {
+	Hash  string `json:"hash"`
+	Error string `json:"error"`
+}
+
+// Job is a bulk operation applied in the background. Jobs are kept in memory only.
+type Job struct {
+	ID          int64       `json:"id"`
+	InstanceID  int         `json:"instanceId"`
+	Spec        Spec        `json:"spec"`
+	Status      JobStatus   `json:"status"`
+	Progress    Progress    `json:"progress"`
+	Succeeded   int         `json:"succeeded"`
+	Failed      int         `json:"failed"`
+	Errors      []HashError `json:"errors,omitempty"`
+	Undoable    bool        `json:"undoable"`
+	UndoOf      *int64      `json:"undoOf,omitempty"`
+	UndoneBy    *int64      `json:"undoneBy,omitempty"`
+	StartedAt   time.Time   `json:"startedAt"`
+	CompletedAt *time.Time  `json:"completedAt,omitempty"`
+
+	snapshots map[string]snapshot
+	cancel    context.CancelFunc
+}
+
+// snapshot is the state of a torrent before a reversible job changed it.
+type snapshot struct {
+	tags                     string
+	category                 string
+	ratioLimit               float64
+	seedingTimeLimit         int64
+	inactiveSeedingTimeLimit int64
+	upLimit                  int64
+	dlLimit                  int64
+	savePath                 string
+	autoTMM                  bool
+}
+
+func snapshotOf(torrent qbt.Torrent) snapshot {
+	return snapshot{
+		tags:                     normalizeTags(torrent.Tags),
+		category:                 torrent.Category,
+		ratioLimit:               torrent.RatioLimit,
+		seedingTimeLimit:         torrent.SeedingTimeLimit,
+		inactiveSeedingTimeLimit: torrent.InactiveSeedingTimeLimit,
+		upLimit:                  torrent.UpLimit,
+		dlLimit:                  torrent.DlLimit,
+		savePath:                 torrent.SavePath,
+		autoTMM:                  torrent.AutoManaged,
+	}
+}
+
+// operation applies one change to a set of torrents. Forward jobs have a single
+// operation; undo jobs have one per distinct previous value.
+type operation struct {
+	hashes []string
+	apply  func(ctx context.Context, hashes []string) error
+}
+
+// Service runs bulk jobs.
+type Service struct {
+	client torrentClient
+
+	mu     sync.Mutex
+	jobs   []*Job
+	nextID int64
+}
+
+// NewService creates a bulk job service.
+func NewService(syncManager *qbittorrent.SyncManager) *Service {
+	return &Service{client: syncManager}
+}
+
+// Submit validates spec and applies it to hashes in the background, returning a
+// snapshot of the new job.
+func (s *Service) Submit(instanceID int, spec Spec, hashes []string) (*Job, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	ops := []operation{{
+		hashes: uniqueHashes(hashes),
+		apply: func(ctx context.Context, chunk []string) error {
+			return s.applySpec(ctx, instanceID, spec, chunk)
+		},
+	}}
+	return s.start(instanceID, spec, ops, nil)
+}
+
+// Undo restores the state recorded by a finished reversible job in a new job.
+func (s *Service) Undo(instanceID int, id int64) (*Job, error) {
+	s.mu.Lock()
+	original := s.findLocked(instanceID, id)
+	if original == nil {
+		s.mu.Unlock()
+		return nil, ErrJobNotFound
+	}
+	if !undoable(original) {
+		s.mu.Unlock()
+		return nil, ErrNotUndoable
+	}
+	spec := original.Spec
+	ops := s.undoOperations(instanceID, spec, original.snapshots)
+	s.mu.Unlock()
+
+	return s.start(instanceID, Spec{Action: spec.Action}, ops, &id)
+}
+
+// GetJob returns a snapshot of a job including its per-torrent errors.
+func (s *Service) GetJob(instanceID int, id int64) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.findLocked(instanceID, id)
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	return cloneJob(job, true), nil
+}
+
+// ListJobs returns snapshots of recent jobs for an instance, newest first. Per-torrent
+// errors are left out to keep polling cheap; fetch a single job for them.
+func (s *Service) ListJobs(instanceID int) []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]*Job, 0)
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		if s.jobs[i].InstanceID == instanceID {
+			jobs = append(jobs, cloneJob(s.jobs[i], false))
+		}
+	}
+	return jobs
+}
+
+// Cancel stops a running job after its current chunk. Torrents already processed keep
+// their changes and can still be undone.
+func (s *Service) Cancel(instanceID int, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.findLocked(instanceID, id)
+	if job == nil {
+		return ErrJobNotFound
+	}
+	if job.Status != JobStatusRunning || job.cancel == nil {
+		return ErrJobNotRunning
+	}
+	job.cancel()
+	return nil
+}
+
+func (s *Service) start(instanceID int, spec Spec, ops []operation, undoOf *int64) (*Job, error) {
+	total := 0
+	for _, op := range ops {
+		total += len(op.hashes)
+	}
+
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		if job.InstanceID == instanceID && job.Status == JobStatusRunning {
+			s.mu.Unlock()
+			return nil, ErrJobRunning
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.nextID++
+	job := &Job{
+		ID:         s.nextID,
+		InstanceID: instanceID,
+		Spec:       spec,
+		Status:     JobStatusRunning,
+		Progress:   Progress{Total: total},
+		UndoOf:     undoOf,
+		StartedAt:  time.Now().UTC(),
+		cancel:     cancel,
+	}
+	if undoOf == nil && spec.Reversible() {
+		job.snapshots = make(map[string]snapshot, total)
+	}
+	if undoOf != nil {
+		if original := s.findLocked(instanceID, *undoOf); original != nil {
+			original.UndoneBy = &job.ID
+		}
+	}
+	s.jobs = append(s.jobs, job)
+	s.pruneLocked()
+	snapshot := cloneJob(job, true)
+	s.mu.Unlock()
+
+	go s.run(ctx, job, ops)
+
+	return snapshot, nil
+}
+
+func (s *Service) run(ctx context.Context, job *Job, ops []operation) {
+	defer job.cancel()
+
+	for _, op := range ops {
+		for chunk := range slices.Chunk(op.hashes, chunkSize) {
+			if ctx.Err() != nil {
+				s.finish(job, JobStatusCanceled)
+				return
+			}
+			s.runChunk(ctx, job, op, chunk)
+		}
+	}
+
+	s.mu.Lock()
+	status := JobStatusCompleted
+	if job.Succeeded == 0 && job.Failed > 0 {
+		status = JobStatusFailed
+	}
+	s.mu.Unlock()
+	s.finish(job, status)
+}
+
+// runChunk applies op to the torrents in chunk that still exist, recording the
+// previous state of each one first when the job is reversible.
+func (s *Service) runChunk(ctx context.Context, job *Job, op operation, chunk []string) {
+	torrents, err := s.client.GetTorrents(ctx, job.InstanceID, qbt.TorrentFilterOptions{Hashes: chunk})
+	if err != nil {
+		s.recordChunk(job, nil, failAll(chunk, err), nil)
+		return
+	}
+
+	found := make(map[string]qbt.Torrent, len(torrents))
+	for _, torrent := range torrents {
+		found[torrent.Hash] = torrent
+	}
+
+	present := make([]string, 0, len(chunk))
+	var failures []HashError
+	for _, hash := range chunk {
+		if _, ok := found[hash]; ok {
+			present = append(present, hash)
+		} else {
+			failures = append(failures, HashError{Hash: hash, Error: "torrent not found"})
+		}
+	}
+	if len(present) == 0 {
+		s.recordChunk(job, nil, failures, nil)
+		return
+	}
+
+	if err := op.apply(ctx, present); err != nil {
+		log.Warn().Err(err).Int("instanceID", job.InstanceID).Int64("jobID", job.ID).Str("action", job.Spec.Action).Int("torrents", len(present)).Msg("bulkjobs: chunk failed")
+		s.recordChunk(job, nil, append(failures, failAll(present, err)...), nil)
+		return
+	}
+	s.recordChunk(job, present, failures, found)
+}
+
+func (s *Service) recordChunk(job *Job, succeeded []string, failures []HashError, found map[string]qbt.Torrent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job.snapshots != nil {
+		for _, hash := range succeeded {
+			job.snapshots[hash] = snapshotOf(found[hash])
+		}
+	}
+	job.Succeeded += len(succeeded)
+	job.Failed += len(failures)
+	job.Errors = append(job.Errors, failures...)
+	job.Progress.Processed += len(succeeded) + len(failures)
+}
+
+func (s *Service) finish(job *Job, status JobStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	job.Status = status
+	job.CompletedAt = &now
+	// An undo that changed nothing leaves the original job undoable.
+	if status == JobStatusFailed && job.UndoOf != nil {
+		if original := s.findLocked(job.InstanceID, *job.UndoOf); original != nil && original.UndoneBy != nil && *original.UndoneBy == job.ID {
+			original.UndoneBy = nil
+		}
+	}
+	log.Info().Int("instanceID", job.InstanceID).Int64("jobID", job.ID).Str("action", job.Spec.Action).Str("status", string(status)).
+		Int("succeeded", job.Succeeded).Int("failed", job.Failed).Msg("bulkjobs: job finished")
+}
+
+func (s *Service) applySpec(ctx context.Context, instanceID int, spec Spec, hashes []string) error {
+	switch spec.Action {
+	case "addTags":
+		return s.client.AddTags(ctx, instanceID, hashes, spec.Tags)
+	case "removeTags":
+		return s.client.RemoveTags(ctx, instanceID, hashes, spec.Tags)
+	case "setTags":
+		return s.client.SetTags(ctx, instanceID, hashes, spec.Tags)
+	case "setCategory":
+		return s.client.SetCategory(ctx, instanceID, hashes, spec.Category)
+	case "toggleAutoTMM":
+		return s.client.SetAutoTMM(ctx, instanceID, hashes, spec.Enable)
+	case "forceStart":
+		return s.client.SetForceStart(ctx, instanceID, hashes, spec.Enable)
+	case "setShareLimit":
+		return s.client.SetTorrentShareLimit(ctx, instanceID, hashes, spec.RatioLimit, spec.SeedingTimeLimit, spec.InactiveSeedingTimeLimit)
+	case "setUploadLimit":
+		return s.client.SetTorrentUploadLimit(ctx, instanceID, hashes, spec.UploadLimit)
+	case "setDownloadLimit":
+		return s.client.SetTorrentDownloadLimit(ctx, instanceID, hashes, spec.DownloadLimit)
+	case "setLocation":
+		return s.client.SetLocation(ctx, instanceID, hashes, spec.Location)
+	case "editTrackers":
+		return s.client.BulkEditTrackers(ctx, instanceID, hashes, spec.TrackerOldURL, spec.TrackerNewURL)
+	case "addTrackers":
+		return s.client.BulkAddTrackers(ctx, instanceID, hashes, spec.TrackerURLs)
+	case "removeTrackers":
+		return s.client.BulkRemoveTrackers(ctx, instanceID, hashes, spec.TrackerURLs)
+	case "delete":
+		if spec.DeleteFiles {
+			return s.client.BulkAction(ctx, instanceID, hashes, "deleteWithFiles")
+		}
+		return s.client.BulkAction(ctx, instanceID, hashes, "delete")
+	default:
+		return s.client.BulkAction(ctx, instanceID, hashes, spec.Action)
+	}
+}
+
+// undoOperations groups torrents by the value they had before the job ran, so each
+// distinct value is restored with one call per chunk.
+func (s *Service) undoOperations(instanceID int, spec Spec, snapshots map[string]snapshot) []operation {
+	switch spec.Action {
+	case "addTags", "removeTags", "setTags":
+		return groupOperations(snapshots, func(snap snapshot) string { return snap.tags },
+			func(ctx context.Context, hashes []string, tags string) error {
+				return s.client.SetTags(ctx, instanceID, hashes, tags)
+			})
+	case "setCategory":
+		return groupOperations(snapshots, func(snap snapshot) string { return snap.category },
+			func(ctx context.Context, hashes []string, category string) error {
+				return s.client.SetCategory(ctx, instanceID, hashes, category)
+			})
+	case "setShareLimit":
+		type shareLimits struct {
+			ratio    float64
+			seeding  int64
+			inactive int64
+		}
+		return groupOperations(snapshots, func(snap snapshot) shareLimits {
+			return shareLimits{ratio: snap.ratioLimit, seeding: snap.seedingTimeLimit, inactive: snap.inactiveSeedingTimeLimit}
+		}, func(ctx context.Context, hashes []string, limits shareLimits) error {
+			return s.client.SetTorrentShareLimit(ctx, instanceID, hashes, limits.ratio, limits.seeding, limits.inactive)
+		})
+	case "setUploadLimit":
+		return groupOperations(snapshots, func(snap snapshot) int64 { return speedLimitKiB(snap.upLimit) },
+			func(ctx context.Context, hashes []string, limit int64) error {
+				return s.client.SetTorrentUploadLimit(ctx, instanceID, hashes, limit)
+			})
+	case "setDownloadLimit":
+		return groupOperations(snapshots, func(snap snapshot) int64 { return speedLimitKiB(snap.dlLimit) },
+			func(ctx context.Context, hashes []string, limit int64) error {
+				return s.client.SetTorrentDownloadLimit(ctx, instanceID, hashes, limit)
+			})
+	case "setLocation":
+		// Setting a location turns automatic management off; torrents that had it on are
+		// moved back by turning it on again rather than by pinning their old path.
+		type location struct {
+			autoTMM  bool
+			savePath string
+		}
+		return groupOperations(snapshots, func(snap snapshot) location {
+			if snap.autoTMM {
+				return location{autoTMM: true}
+			}
+			return location{savePath: snap.savePath}
+		}, func(ctx context.Context, hashes []string, loc location) error {
+			if loc.autoTMM {
+				return s.client.SetAutoTMM(ctx, instanceID, hashes, true)
+			}
+			return s.client.SetLocation(ctx, instanceID, hashes, loc.savePath)
+		})
+	default:
+		return nil
+	}
+}
+
+// groupOperations builds one operation per distinct key, with hashes sorted so undo
+// runs in a stable order.
+func groupOperations[K comparable](snapshots map[string]snapshot, key func(snapshot) K, apply func(context.Context, []string, K) error) []operation {
+	grouped := make(map[K][]string)
+	var order []K
+	for hash, snap := range snapshots {
+		k := key(snap)
+		if _, ok := grouped[k]; !ok {
+			order = append(order, k)
+		}
+		grouped[k] = append(grouped[k], hash)
+	}
+	for _, hashes := range grouped {
+		slices.Sort(hashes)
+	}
+	slices.SortFunc(order, func(a, b K) int {
+		return cmp.Compare(grouped[a][0], grouped[b][0])
+	})
+
+	ops := make([]operation, 0, len(order))
+	for _, k := range order {
+		value := k
+		ops = append(ops, operation{
+			hashes: grouped[k],
+			apply: func(ctx context.Context, hashes []string) error {
+				return apply(ctx, hashes, value)
+			},
+		})
+	}
+	return ops
+}
+
+// speedLimitKiB converts a qBittorrent speed limit in bytes/s to the KiB/s the sync
+// manager expects, mapping unlimited (0 or -1) to 0.
+func speedLimitKiB(limit int64) int64 {
+	if limit <= 0 {
+		return 0
+	}
+	return limit / 1024
+}
+
+// normalizeTags converts qBittorrent's ", " separated tag list to the comma separated
+// form the tag endpoints accept.
+func normalizeTags(tags string) string {
+	parts := strings.Split(tags, ",")
+	cleaned := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if tag := strings.TrimSpace(part); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+func uniqueHashes(hashes []string) []string {
+	seen := make(map[string]struct{}, len(hashes))
+	unique := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		hash = strings.TrimSpace(hash)
+		if hash == "" {
+			continue
+		}
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+		unique = append(unique, hash)
+	}
+	return unique
+}
+
+func failAll(hashes []string, err error) []HashError {
+	failures := make([]HashError, 0, len(hashes))
+	for _, hash := range hashes {
+		failures = append(failures, HashError{Hash: hash, Error: err.Error()})
+	}
+	return failures
+}
+
+func undoable(job *Job) bool {
+	return len(job.snapshots) > 0 && job.Status != JobStatusRunning && job.UndoneBy == nil
+}
+
+func (s *Service) findLocked(instanceID int, id int64) *Job {
+	for _, job := range s.jobs {
+		if job.ID == id && job.InstanceID == instanceID {
+			return job
+		}
+	}
+	return nil
+}
+
+// pruneLocked drops the oldest finished jobs beyond maxJobs. Running jobs are kept.
+func (s *Service) pruneLocked() {
+	for len(s.jobs) > maxJobs {
+		idx := slices.IndexFunc(s.jobs, func(job *Job) bool { return job.Status != JobStatusRunning })
+		if idx < 0 {
+			return
+		}
+		s.jobs = slices.Delete(s.jobs, idx, idx+1)
+	}
+}
+
+func cloneJob(job *Job, withErrors bool) *Job {
+	clone := *job
+	clone.snapshots = nil
+	clone.cancel = nil
+	clone.Undoable = undoable(job)
+	clone.Errors = nil
+	if withErrors {
+		clone.Errors = slices.Clone(job.Errors)
+	}
+	if job.UndoOf != nil {
+		undoOf := *job.UndoOf
+		clone.UndoOf = &undoOf
+	}
+	if job.UndoneBy != nil {
+		undoneBy := *job.UndoneBy
+		clone.UndoneBy = &undoneBy
+	}
+	if job.CompletedAt != nil {
+		completedAt := *job.CompletedAt
+		clone.CompletedAt = &completedAt
+	}
+	return &clone
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package bulkjobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	method string
+	hashes []string
+	value  string
+}
+
+// fakeClient keeps torrents in memory and applies tag, category and location changes
+// to them so undo can be checked against the original state.
+type fakeClient struct {
+	mu       sync.Mutex
+	torrents map[string]qbt.Torrent
+	calls    []call
+	failOn   map[string]error // fails any chunk containing the hash
+	block    chan struct{}    // when set, every apply waits on it
+	entered  chan struct{}    // when set, receives a value as each apply starts
+}
+
+func newFakeClient(torrents ...qbt.Torrent) *fakeClient {
+	f := &fakeClient{torrents: make(map[string]qbt.Torrent), failOn: make(map[string]error)}
+	for _, torrent := range torrents {
+		f.torrents[torrent.Hash] = torrent
+	}
+	return f
+}
+
+func (f *fakeClient) record(method string, hashes []string, value string, mutate func(*qbt.Torrent)) error {
+	if f.entered != nil {
+		f.entered <- struct{}{}
+	}
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call{method: method, hashes: slices.Clone(hashes), value: value})
+	for _, hash := range hashes {
+		if err := f.failOn[hash]; err != nil {
+			return err
+		}
+	}
+	if mutate != nil {
+		for _, hash := range hashes {
+			torrent := f.torrents[hash]
+			mutate(&torrent)
+			f.torrents[hash] = torrent
+		}
+	}
+	return nil
+}
+
+func (f *fakeClient) GetTorrents(_ context.Context, _ int, filter qbt.TorrentFilterOptions) ([]qbt.Torrent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var torrents []qbt.Torrent
+	for _, hash := range filter.Hashes {
+		if torrent, ok := f.torrents[hash]; ok {
+			torrents = append(torrents, torrent)
+		}
+	}
+	return torrents, nil
+}
+
+func (f *fakeClient) BulkAction(_ context.Context, _ int, hashes []string, action string) error {
+	return f.record("BulkAction", hashes, action, nil)
+}
+
+func (f *fakeClient) AddTags(_ context.Context, _ int, hashes []string, tags string) error {
+	return f.record("AddTags", hashes, tags, func(t *qbt.Torrent) {
+		if t.Tags == "" {
+			t.Tags = tags
+		} else {
+			t.Tags += ", " + tags
+		}
+	})
+}
+
+func (f *fakeClient) RemoveTags(_ context.Context, _ int, hashes []string, tags string) error {
+	return f.record("RemoveTags", hashes, tags, nil)
+}
+
+func (f *fakeClient) SetTags(_ context.Context, _ int, hashes []string, tags string) error {
+	return f.record("SetTags", hashes, tags, func(t *qbt.Torrent) { t.Tags = tags })
+}
+
+func (f *fakeClient) SetCategory(_ context.Context, _ int, hashes []string, category string) error {
+	return f.record("SetCategory", hashes, category, func(t *qbt.Torrent) { t.Category = category })
+}
+
+func (f *fakeClient) SetAutoTMM(_ context.Context, _ int, hashes []string, enable bool) error {
+	return f.record("SetAutoTMM", hashes, fmt.Sprint(enable), func(t *qbt.Torrent) { t.AutoManaged = enable })
+}
+
+func (f *fakeClient) SetForceStart(_ context.Context, _ int, hashes []string, enable bool) error {
+	return f.record("SetForceStart", hashes, fmt.Sprint(enable), nil)
+}
+
+func (f *fakeClient) SetTorrentShareLimit(_ context.Context, _ int, hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int64) error {
+	return f.record("SetTorrentShareLimit", hashes, fmt.Sprint(ratioLimit, seedingTimeLimit, inactiveSeedingTimeLimit), nil)
+}
+
+func (f *fakeClient) SetTorrentUploadLimit(_ context.Context, _ int, hashes []string, limitKBs int64) error {
+	return f.record("SetTorrentUploadLimit", hashes, fmt.Sprint(limitKBs), nil)
+}
+
+func (f *fakeClient) SetTorrentDownloadLimit(_ context.Context, _ int, hashes []string, limitKBs int64) error {
+	return f.record("SetTorrentDownloadLimit", hashes, fmt.Sprint(limitKBs), nil)
+}
+
+func (f *fakeClient) SetLocation(_ context.Context, _ int, hashes []string, location string) error {
+	return f.record("SetLocation", hashes, location, func(t *qbt.Torrent) {
+		t.SavePath = location
+		t.AutoManaged = false
+	})
+}
+
+func (f *fakeClient) BulkEditTrackers(_ context.Context, _ int, hashes []string, oldURL, newURL string) error {
+	return f.record("BulkEditTrackers", hashes, oldURL+"->"+newURL, nil)
+}
+
+func (f *fakeClient) BulkAddTrackers(_ context.Context, _ int, hashes []string, urls string) error {
+	return f.record("BulkAddTrackers", hashes, urls, nil)
+}
+
+func (f *fakeClient) BulkRemoveTrackers(_ context.Context, _ int, hashes []string, urls string) error {
+	return f.record("BulkRemoveTrackers", hashes, urls, nil)
+}
+
+func (f *fakeClient) callsFor(method string) []call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []call
+	for _, c := range f.calls {
+		if c.method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+func waitForJob(t *testing.T, s *Service, instanceID int, id int64) *Job {
+	t.Helper()
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = s.GetJob(instanceID, id)
+		require.NoError(t, err)
+		return job.Status != JobStatusRunning
+	}, 2*time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestSpecValidate(t *testing.T) {
+	assert.NoError(t, Spec{Action: "pause"}.Validate())
+	assert.NoError(t, Spec{Action: "setTags"}.Validate())
+	assert.Error(t, Spec{Action: "explode"}.Validate())
+	assert.Error(t, Spec{Action: "addTags"}.Validate())
+	assert.Error(t, Spec{Action: "setLocation", Location: "  "}.Validate())
+	assert.Error(t, Spec{Action: "editTrackers", TrackerOldURL: "a"}.Validate())
+	assert.Error(t, Spec{Action: "removeTrackers"}.Validate())
+}
+
+func TestSubmitChunksAndReportsPerHashErrors(t *testing.T) {
+	var torrents []qbt.Torrent
+	var hashes []string
+	for i := range 250 {
+		hash := fmt.Sprintf("h%03d", i)
+		torrents = append(torrents, qbt.Torrent{Hash: hash})
+		hashes = append(hashes, hash)
+	}
+	client := newFakeClient(torrents...)
+	client.failOn["h150"] = errors.New("qbittorrent unavailable")
+	s := &Service{client: client}
+
+	job, err := s.Submit(1, Spec{Action: "pause"}, append(hashes, "missing", "h000"))
+	require.NoError(t, err)
+	assert.Equal(t, 251, job.Progress.Total)
+
+	job = waitForJob(t, s, 1, job.ID)
+	assert.Equal(t, JobStatusCompleted, job.Status)
+	assert.Equal(t, 251, job.Progress.Processed)
+	assert.Equal(t, 150, job.Succeeded)
+	assert.Equal(t, 101, job.Failed)
+	assert.False(t, job.Undoable)
+
+	calls := client.callsFor("BulkAction")
+	require.Len(t, calls, 3)
+	assert.Len(t, calls[0].hashes, chunkSize)
+	assert.Len(t, calls[2].hashes, 50)
+
+	var missing *HashError
+	for i := range job.Errors {
+		if job.Errors[i].Hash == "missing" {
+			missing = &job.Errors[i]
+		}
+	}
+	require.NotNil(t, missing)
+	assert.Equal(t, "torrent not found", missing.Error)
+
+	listed := s.ListJobs(1)
+	require.Len(t, listed, 1)
+	assert.Nil(t, listed[0].Errors)
+	assert.Empty(t, s.ListJobs(2))
+}
+
+func TestSubmitRejectsConcurrentJobPerInstance(t *testing.T) {
+	client := newFakeClient(qbt.Torrent{Hash: "a"})
+	client.block = make(chan struct{})
+	s := &Service{client: client}
+
+	job, err := s.Submit(1, Spec{Action: "pause"}, []string{"a"})
+	require.NoError(t, err)
+
+	_, err = s.Submit(1, Spec{Action: "resume"}, []string{"a"})
+	require.ErrorIs(t, err, ErrJobRunning)
+
+	other, err := s.Submit(2, Spec{Action: "resume"}, []string{"a"})
+	require.NoError(t, err)
+
+	close(client.block)
+	waitForJob(t, s, 1, job.ID)
+	waitForJob(t, s, 2, other.ID)
+}
+
+func TestCancelStopsAfterCurrentChunk(t *testing.T) {
+	var torrents []qbt.Torrent
+	var hashes []string
+	for i := range 3 * chunkSize {
+		hash := fmt.Sprintf("h%03d", i)
+		torrents = append(torrents, qbt.Torrent{Hash: hash})
+		hashes = append(hashes, hash)
+	}
+	client := newFakeClient(torrents...)
+	client.block = make(chan struct{})
+	client.entered = make(chan struct{}, 3)
+	s := &Service{client: client}
+
+	job, err := s.Submit(1, Spec{Action: "recheck"}, hashes)
+	require.NoError(t, err)
+	<-client.entered
+	require.NoError(t, s.Cancel(1, job.ID))
+	close(client.block)
+
+	job = waitForJob(t, s, 1, job.ID)
+	assert.Equal(t, JobStatusCanceled, job.Status)
+	assert.Equal(t, chunkSize, job.Progress.Processed)
+	assert.ErrorIs(t, s.Cancel(1, job.ID), ErrJobNotRunning)
+	assert.ErrorIs(t, s.Cancel(2, job.ID), ErrJobNotFound)
+}
+
+func TestUndoRestoresPreviousTags(t *testing.T) {
+	client := newFakeClient(
+		qbt.Torrent{Hash: "a", Tags: "keep, old"},
+		qbt.Torrent{Hash: "b", Tags: ""},
+		qbt.Torrent{Hash: "c", Tags: "keep, old"},
+	)
+	s := &Service{client: client}
+
+	job, err := s.Submit(1, Spec{Action: "addTags", Tags: "new"}, []string{"a", "b", "c", "gone"})
+	require.NoError(t, err)
+	job = waitForJob(t, s, 1, job.ID)
+	assert.True(t, job.Undoable)
+	assert.Equal(t, 1, job.Failed)
+
+	undo, err := s.Undo(1, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, undo.UndoOf)
+	assert.Equal(t, job.ID, *undo.UndoOf)
+	assert.Equal(t, 3, undo.Progress.Total)
+
+	undo = waitForJob(t, s, 1, undo.ID)
+	assert.Equal(t, JobStatusCompleted, undo.Status)
+	assert.False(t, undo.Undoable)
+
+	calls := client.callsFor("SetTags")
+	require.Len(t, calls, 2)
+	assert.Equal(t, call{method: "SetTags", hashes: []string{"a", "c"}, value: "keep,old"}, calls[0])
+	assert.Equal(t, call{method: "SetTags", hashes: []string{"b"}, value: ""}, calls[1])
+
+	original, err := s.GetJob(1, job.ID)
+	require.NoError(t, err)
+	assert.False(t, original.Undoable)
+	require.NotNil(t, original.UndoneBy)
+	assert.Equal(t, undo.ID, *original.UndoneBy)
+
+	_, err = s.Undo(1, job.ID)
+	assert.ErrorIs(t, err, ErrNotUndoable)
+}
+
+func TestUndoLocationReenablesAutoTMM(t *testing.T) {
+	client := newFakeClient(
+		qbt.Torrent{Hash: "a", SavePath: "/data/movies", AutoManaged: true},
+		qbt.Torrent{Hash: "b", SavePath: "/data/tv"},
+	)
+	s := &Service{client: client}
+
+	job, err := s.Submit(1, Spec{Action: "setLocation", Location: "/archive"}, []string{"a", "b"})
+	require.NoError(t, err)
+	waitForJob(t, s, 1, job.ID)
+
+	undo, err := s.Undo(1, job.ID)
+	require.NoError(t, err)
+	waitForJob(t, s, 1, undo.ID)
+
+	autoTMM := client.callsFor("SetAutoTMM")
+	require.Len(t, autoTMM, 1)
+	assert.Equal(t, []string{"a"}, autoTMM[0].hashes)
+
+	locations := client.callsFor("SetLocation")
+	require.Len(t, locations, 2)
+	assert.Equal(t, call{method: "SetLocation", hashes: []string{"b"}, value: "/data/tv"}, locations[1])
+}
+
+func TestUndoRequiresReversibleAction(t *testing.T) {
+	client := newFakeClient(qbt.Torrent{Hash: "a"})
+	s := &Service{client: client}
+
+	job, err := s.Submit(1, Spec{Action: "delete"}, []string{"a"})
+	require.NoError(t, err)
+	waitForJob(t, s, 1, job.ID)
+
+	_, err = s.Undo(1, job.ID)
+	assert.ErrorIs(t, err, ErrNotUndoable)
+	_, err = s.Undo(1, 999)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestSpeedLimitKiB(t *testing.T) {
+	assert.Equal(t, int64(0), speedLimitKiB(-1))
+	assert.Equal(t, int64(0), speedLimitKiB(0))
+	assert.Equal(t, int64(512), speedLimitKiB(512*1024))
+}
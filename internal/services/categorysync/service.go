@@ -0,0 +1,318 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package categorysync compares instances against the category and tag templates
+// defined in qui and reconciles the differences.
+package categorysync
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+// ErrNotTarget is returned when reconciling an instance that is not a sync target.
+var ErrNotTarget = errors.New("instance is not a category sync target")
+
+// Category drift states.
+const (
+	DriftMissing   = "missing"
+	DriftDifferent = "different"
+	DriftExtra     = "extra"
+)
+
+type templateStore interface {
+	GetTemplates(ctx context.Context) (*models.CategoryTemplates, error)
+	ListTargets(ctx context.Context) ([]models.CategorySyncTarget, error)
+}
+
+type instanceGetter interface {
+	Get(ctx context.Context, id int) (*models.Instance, error)
+}
+
+type instanceClient interface {
+	GetCategories(ctx context.Context, instanceID int) (map[string]qbt.Category, error)
+	GetTags(ctx context.Context, instanceID int) ([]string, error)
+	CreateCategory(ctx context.Context, instanceID int, name string, path string) error
+	EditCategory(ctx context.Context, instanceID int, name string, path string) error
+	RemoveCategories(ctx context.Context, instanceID int, categories []string) error
+	CreateTags(ctx context.Context, instanceID int, tags []string) error
+	DeleteTags(ctx context.Context, instanceID int, tags []string) error
+}
+
+// CategoryDrift is one category that differs between the templates and an instance.
+type CategoryDrift struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// ExpectedSavePath is empty for extra categories.
+	ExpectedSavePath string `json:"expectedSavePath"`
+	// ActualSavePath is empty for missing categories.
+	ActualSavePath string `json:"actualSavePath"`
+}
+
+// InstanceDrift is the drift report for one sync target.
+type InstanceDrift struct {
+	InstanceID     int             `json:"instanceId"`
+	InstanceName   string          `json:"instanceName"`
+	SavePathPrefix string          `json:"savePathPrefix"`
+	Categories     []CategoryDrift `json:"categories"`
+	MissingTags    []string        `json:"missingTags"`
+	ExtraTags      []string        `json:"extraTags"`
+	InSync         bool            `json:"inSync"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// ReconcileResult is the outcome of reconciling one instance.
+type ReconcileResult struct {
+	InstanceID        int            `json:"instanceId"`
+	CategoriesCreated int            `json:"categoriesCreated"`
+	CategoriesUpdated int            `json:"categoriesUpdated"`
+	CategoriesRemoved int            `json:"categoriesRemoved"`
+	TagsCreated       int            `json:"tagsCreated"`
+	TagsRemoved       int            `json:"tagsRemoved"`
+	Errors            []string       `json:"errors,omitempty"`
+	Drift             *InstanceDrift `json:"drift,omitempty"`
+}
+
+// Service computes drift and reconciles sync targets.
+type Service struct {
+	store     templateStore
+	instances instanceGetter
+	client    instanceClient
+}
+
+// NewService creates a category sync service.
+func NewService(store *models.CategoryTemplateStore, instanceStore *models.InstanceStore, syncManager *qbittorrent.SyncManager) *Service {
+	return &Service{
+		store:     store,
+		instances: instanceStore,
+		client:    syncManager,
+	}
+}
+
+// EffectiveSavePath returns the save path a template category should have on a target.
+// Absolute template paths are used as-is; relative ones are joined onto the target's
+// prefix when it has one.
+func EffectiveSavePath(templatePath, prefix string) string {
+	templatePath = strings.TrimSpace(templatePath)
+	prefix = strings.TrimSpace(prefix)
+	if templatePath == "" || prefix == "" || isAbsolute(templatePath) {
+		return templatePath
+	}
+	return path.Join(strings.ReplaceAll(prefix, `\`, "/"), templatePath)
+}
+
+// isAbsolute reports whether p is a POSIX or Windows absolute path. Paths belong to the
+// qBittorrent host, which may run a different OS than qui.
+func isAbsolute(p string) bool {
+	if strings.HasPrefix(p, "/") || strings.HasPrefix(p, `\`) {
+		return true
+	}
+	return len(p) >= 3 && p[1] == ':' && (p[2] == '/' || p[2] == '\\')
+}
+
+// sameSavePath compares save paths ignoring trailing separators and separator style.
+func sameSavePath(a, b string) bool {
+	normalize := func(p string) string {
+		p = strings.ReplaceAll(strings.TrimSpace(p), `\`, "/")
+		if len(p) > 1 {
+			p = strings.TrimRight(p, "/")
+		}
+		return p
+	}
+	return normalize(a) == normalize(b)
+}
+
+// Drift returns a drift report for every sync target.
+func (s *Service) Drift(ctx context.Context) ([]InstanceDrift, error) {
+	templates, err := s.store.GetTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load templates: %w", err)
+	}
+	targets, err := s.store.ListTargets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load targets: %w", err)
+	}
+
+	reports := make([]InstanceDrift, 0, len(targets))
+	for _, target := range targets {
+		reports = append(reports, s.instanceDrift(ctx, templates, target))
+	}
+	return reports, nil
+}
+
+// Reconcile creates missing categories and tags on a target and fixes differing save
+// paths. Extra categories and tags are only removed when removeExtra is set, since
+// removing a category clears it from every torrent that uses it.
+func (s *Service) Reconcile(ctx context.Context, instanceID int, removeExtra bool) (*ReconcileResult, error) {
+	templates, err := s.store.GetTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load templates: %w", err)
+	}
+	target, err := s.target(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := s.instanceDrift(ctx, templates, *target)
+	result := &ReconcileResult{InstanceID: instanceID}
+	if drift.Error != "" {
+		result.Errors = append(result.Errors, drift.Error)
+		result.Drift = &drift
+		return result, nil
+	}
+
+	var extraCategories []string
+	for _, category := range drift.Categories {
+		switch category.Status {
+		case DriftMissing:
+			if err := s.client.CreateCategory(ctx, instanceID, category.Name, category.ExpectedSavePath); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("create category %q: %v", category.Name, err))
+				continue
+			}
+			result.CategoriesCreated++
+		case DriftDifferent:
+			if err := s.client.EditCategory(ctx, instanceID, category.Name, category.ExpectedSavePath); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("update category %q: %v", category.Name, err))
+				continue
+			}
+			result.CategoriesUpdated++
+		case DriftExtra:
+			extraCategories = append(extraCategories, category.Name)
+		}
+	}
+
+	if len(drift.MissingTags) > 0 {
+		if err := s.client.CreateTags(ctx, instanceID, drift.MissingTags); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("create tags: %v", err))
+		} else {
+			result.TagsCreated = len(drift.MissingTags)
+		}
+	}
+
+	if removeExtra {
+		if len(extraCategories) > 0 {
+			if err := s.client.RemoveCategories(ctx, instanceID, extraCategories); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("remove categories: %v", err))
+			} else {
+				result.CategoriesRemoved = len(extraCategories)
+			}
+		}
+		if len(drift.ExtraTags) > 0 {
+			if err := s.client.DeleteTags(ctx, instanceID, drift.ExtraTags); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("remove tags: %v", err))
+			} else {
+				result.TagsRemoved = len(drift.ExtraTags)
+			}
+		}
+	}
+
+	log.Info().Int("instanceID", instanceID).
+		Int("categoriesCreated", result.CategoriesCreated).Int("categoriesUpdated", result.CategoriesUpdated).
+		Int("categoriesRemoved", result.CategoriesRemoved).Int("tagsCreated", result.TagsCreated).
+		Int("tagsRemoved", result.TagsRemoved).Int("errors", len(result.Errors)).
+		Msg("categorysync: reconciled instance")
+
+	after := s.instanceDrift(ctx, templates, *target)
+	result.Drift = &after
+	return result, nil
+}
+
+func (s *Service) target(ctx context.Context, instanceID int) (*models.CategorySyncTarget, error) {
+	targets, err := s.store.ListTargets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load targets: %w", err)
+	}
+	for i := range targets {
+		if targets[i].InstanceID == instanceID {
+			return &targets[i], nil
+		}
+	}
+	return nil, ErrNotTarget
+}
+
+func (s *Service) instanceDrift(ctx context.Context, templates *models.CategoryTemplates, target models.CategorySyncTarget) InstanceDrift {
+	report := InstanceDrift{
+		InstanceID:     target.InstanceID,
+		SavePathPrefix: target.SavePathPrefix,
+		Categories:     []CategoryDrift{},
+		MissingTags:    []string{},
+		ExtraTags:      []string{},
+	}
+
+	instance, err := s.instances.Get(ctx, target.InstanceID)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.InstanceName = instance.Name
+
+	categories, err := s.client.GetCategories(ctx, target.InstanceID)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	tags, err := s.client.GetTags(ctx, target.InstanceID)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	report.Categories = compareCategories(templates.Categories, categories, target.SavePathPrefix)
+	report.MissingTags, report.ExtraTags = compareTags(templates.Tags, tags)
+	report.InSync = len(report.Categories) == 0 && len(report.MissingTags) == 0 && len(report.ExtraTags) == 0
+	return report
+}
+
+// compareCategories lists template categories that are missing or have another save
+// path on the instance, followed by instance categories with no template.
+func compareCategories(templates []models.CategoryTemplate, actual map[string]qbt.Category, prefix string) []CategoryDrift {
+	drift := []CategoryDrift{}
+	known := make(map[string]struct{}, len(templates))
+	for _, template := range templates {
+		known[template.Name] = struct{}{}
+		expected := EffectiveSavePath(template.SavePath, prefix)
+		category, ok := actual[template.Name]
+		switch {
+		case !ok:
+			drift = append(drift, CategoryDrift{Name: template.Name, Status: DriftMissing, ExpectedSavePath: expected})
+		case !sameSavePath(category.SavePath, expected):
+			drift = append(drift, CategoryDrift{Name: template.Name, Status: DriftDifferent, ExpectedSavePath: expected, ActualSavePath: category.SavePath})
+		}
+	}
+
+	var extras []CategoryDrift
+	for name, category := range actual {
+		if _, ok := known[name]; !ok {
+			extras = append(extras, CategoryDrift{Name: name, Status: DriftExtra, ActualSavePath: category.SavePath})
+		}
+	}
+	slices.SortFunc(extras, func(a, b CategoryDrift) int { return cmp.Compare(a.Name, b.Name) })
+	return append(drift, extras...)
+}
+
+func compareTags(templates, actual []string) (missing, extra []string) {
+	missing, extra = []string{}, []string{}
+	for _, tag := range templates {
+		if !slices.Contains(actual, tag) {
+			missing = append(missing, tag)
+		}
+	}
+	for _, tag := range actual {
+		if !slices.Contains(templates, tag) {
+			extra = append(extra, tag)
+		}
+	}
+	slices.Sort(extra)
+	return missing, extra
+}
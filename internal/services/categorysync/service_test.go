@@ -0,0 +1,212 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package categorysync
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type fakeStore struct {
+	templates *models.CategoryTemplates
+	targets   []models.CategorySyncTarget
+}
+
+func (f *fakeStore) GetTemplates(context.Context) (*models.CategoryTemplates, error) {
+	return f.templates, nil
+}
+
+func (f *fakeStore) ListTargets(context.Context) ([]models.CategorySyncTarget, error) {
+	return f.targets, nil
+}
+
+type fakeInstances map[int]*models.Instance
+
+func (f fakeInstances) Get(_ context.Context, id int) (*models.Instance, error) {
+	if instance, ok := f[id]; ok {
+		return instance, nil
+	}
+	return nil, models.ErrInstanceNotFound
+}
+
+// fakeClient holds the categories and tags of each instance and applies changes to them.
+type fakeClient struct {
+	categories map[int]map[string]qbt.Category
+	tags       map[int][]string
+	failCreate error
+}
+
+func (f *fakeClient) GetCategories(_ context.Context, instanceID int) (map[string]qbt.Category, error) {
+	categories, ok := f.categories[instanceID]
+	if !ok {
+		return nil, errors.New("instance offline")
+	}
+	return categories, nil
+}
+
+func (f *fakeClient) GetTags(_ context.Context, instanceID int) ([]string, error) {
+	return f.tags[instanceID], nil
+}
+
+func (f *fakeClient) CreateCategory(_ context.Context, instanceID int, name, savePath string) error {
+	if f.failCreate != nil {
+		return f.failCreate
+	}
+	f.categories[instanceID][name] = qbt.Category{Name: name, SavePath: savePath}
+	return nil
+}
+
+func (f *fakeClient) EditCategory(_ context.Context, instanceID int, name, savePath string) error {
+	f.categories[instanceID][name] = qbt.Category{Name: name, SavePath: savePath}
+	return nil
+}
+
+func (f *fakeClient) RemoveCategories(_ context.Context, instanceID int, categories []string) error {
+	for _, name := range categories {
+		delete(f.categories[instanceID], name)
+	}
+	return nil
+}
+
+func (f *fakeClient) CreateTags(_ context.Context, instanceID int, tags []string) error {
+	f.tags[instanceID] = append(f.tags[instanceID], tags...)
+	return nil
+}
+
+func (f *fakeClient) DeleteTags(_ context.Context, instanceID int, tags []string) error {
+	kept := f.tags[instanceID][:0]
+	for _, tag := range f.tags[instanceID] {
+		if !slices.Contains(tags, tag) {
+			kept = append(kept, tag)
+		}
+	}
+	f.tags[instanceID] = kept
+	return nil
+}
+
+func newTestService() (*Service, *fakeClient) {
+	client := &fakeClient{
+		categories: map[int]map[string]qbt.Category{
+			1: {
+				"movies": {Name: "movies", SavePath: "/data/movies/"},
+				"tv":     {Name: "tv", SavePath: "/old/tv"},
+				"manual": {Name: "manual", SavePath: "/data/manual"},
+			},
+		},
+		tags: map[int][]string{1: {"sonarr", "cleanup"}},
+	}
+	store := &fakeStore{
+		templates: &models.CategoryTemplates{
+			Categories: []models.CategoryTemplate{
+				{Name: "movies", SavePath: "movies"},
+				{Name: "tv", SavePath: "tv"},
+				{Name: "music", SavePath: "/srv/music"},
+			},
+			Tags: []string{"radarr", "sonarr"},
+		},
+		targets: []models.CategorySyncTarget{
+			{InstanceID: 1, SavePathPrefix: "/data"},
+			{InstanceID: 2},
+		},
+	}
+	instances := fakeInstances{
+		1: {ID: 1, Name: "seedbox"},
+		2: {ID: 2, Name: "offline"},
+	}
+	return &Service{store: store, instances: instances, client: client}, client
+}
+
+func TestEffectiveSavePath(t *testing.T) {
+	tests := []struct {
+		template, prefix, want string
+	}{
+		{"movies", "", "movies"},
+		{"movies", "/data", "/data/movies"},
+		{"movies", "/data/", "/data/movies"},
+		{"/srv/movies", "/data", "/srv/movies"},
+		{`D:\Movies`, "/data", `D:\Movies`},
+		{"movies", `D:\Torrents`, "D:/Torrents/movies"},
+		{"", "/data", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, EffectiveSavePath(tt.template, tt.prefix), "template %q prefix %q", tt.template, tt.prefix)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	s, _ := newTestService()
+
+	reports, err := s.Drift(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	seedbox := reports[0]
+	assert.Equal(t, "seedbox", seedbox.InstanceName)
+	assert.False(t, seedbox.InSync)
+	assert.Equal(t, []CategoryDrift{
+		{Name: "tv", Status: DriftDifferent, ExpectedSavePath: "/data/tv", ActualSavePath: "/old/tv"},
+		{Name: "music", Status: DriftMissing, ExpectedSavePath: "/srv/music"},
+		{Name: "manual", Status: DriftExtra, ActualSavePath: "/data/manual"},
+	}, seedbox.Categories)
+	assert.Equal(t, []string{"radarr"}, seedbox.MissingTags)
+	assert.Equal(t, []string{"cleanup"}, seedbox.ExtraTags)
+
+	offline := reports[1]
+	assert.Equal(t, "offline", offline.InstanceName)
+	assert.Equal(t, "instance offline", offline.Error)
+	assert.False(t, offline.InSync)
+}
+
+func TestReconcileKeepsExtrasByDefault(t *testing.T) {
+	s, client := newTestService()
+
+	result, err := s.Reconcile(context.Background(), 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.CategoriesCreated)
+	assert.Equal(t, 1, result.CategoriesUpdated)
+	assert.Equal(t, 0, result.CategoriesRemoved)
+	assert.Equal(t, 1, result.TagsCreated)
+	assert.Empty(t, result.Errors)
+
+	assert.Equal(t, "/data/tv", client.categories[1]["tv"].SavePath)
+	assert.Contains(t, client.categories[1], "manual")
+
+	require.NotNil(t, result.Drift)
+	assert.Equal(t, []CategoryDrift{{Name: "manual", Status: DriftExtra, ActualSavePath: "/data/manual"}}, result.Drift.Categories)
+	assert.Empty(t, result.Drift.MissingTags)
+}
+
+func TestReconcileRemovesExtras(t *testing.T) {
+	s, client := newTestService()
+
+	result, err := s.Reconcile(context.Background(), 1, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.CategoriesRemoved)
+	assert.Equal(t, 1, result.TagsRemoved)
+	assert.NotContains(t, client.categories[1], "manual")
+	assert.True(t, result.Drift.InSync)
+}
+
+func TestReconcileReportsErrors(t *testing.T) {
+	s, client := newTestService()
+	client.failCreate = errors.New("conflict")
+
+	result, err := s.Reconcile(context.Background(), 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.CategoriesCreated)
+	assert.Equal(t, 1, result.CategoriesUpdated)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], `create category "music"`)
+
+	_, err = s.Reconcile(context.Background(), 3, false)
+	assert.ErrorIs(t, err, ErrNotTarget)
+}
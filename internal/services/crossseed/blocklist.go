@@ -0,0 +1,344 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// blocklistSkipStatus is the run result status reported when a blocklist entry rejects a release.
+const blocklistSkipStatus = "blocklisted"
+
+type blocklistProvider interface {
+	List(ctx context.Context) ([]*models.CrossSeedBlocklistEntry, error)
+	Create(ctx context.Context, entry *models.CrossSeedBlocklistEntry) (*models.CrossSeedBlocklistEntry, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// BlocklistFromCrossSeedRequest blocklists a cross-seed that failed or was removed.
+// When TorrentName is empty and the torrent still exists on InstanceID, the name is
+// looked up from qBittorrent.
+type BlocklistFromCrossSeedRequest struct {
+	InstanceID     int                             `json:"instanceId"`
+	InfoHash       string                          `json:"infoHash"`
+	TorrentName    string                          `json:"torrentName"`
+	IndexerName    string                          `json:"indexerName"`
+	Kinds          []models.CrossSeedBlocklistKind `json:"kinds"`
+	ScopeToIndexer bool                            `json:"scopeToIndexer"`
+	Reason         string                          `json:"reason"`
+}
+
+// compiledBlocklistEntry holds an entry with its pre-processed match value.
+type compiledBlocklistEntry struct {
+	entry *models.CrossSeedBlocklistEntry
+	value string
+	re    *regexp.Regexp
+}
+
+// blocklistMatcher evaluates releases against the blocklist.
+type blocklistMatcher struct {
+	entries []compiledBlocklistEntry
+}
+
+func newBlocklistMatcher(entries []*models.CrossSeedBlocklistEntry) *blocklistMatcher {
+	m := &blocklistMatcher{entries: make([]compiledBlocklistEntry, 0, len(entries))}
+	for _, entry := range entries {
+		compiled := compiledBlocklistEntry{entry: entry, value: strings.ToLower(strings.TrimSpace(entry.Value))}
+		if entry.Kind == models.CrossSeedBlocklistRegex {
+			re, err := regexp.Compile(entry.Value)
+			if err != nil {
+				log.Warn().Err(err).Int64("entryID", entry.ID).Str("pattern", entry.Value).Msg("[CROSSSEED-BLOCKLIST] Skipping invalid regex entry")
+				continue
+			}
+			compiled.re = re
+		}
+		m.entries = append(m.entries, compiled)
+	}
+	return m
+}
+
+// match returns the first entry that blocks the release, or nil. An empty
+// infoHash or releaseGroup never matches entries of that kind.
+func (m *blocklistMatcher) match(name, releaseGroup, infoHash string, indexerID int) *models.CrossSeedBlocklistEntry {
+	if m == nil {
+		return nil
+	}
+
+	name = strings.TrimSpace(name)
+	lowerName := strings.ToLower(name)
+	releaseGroup = strings.ToLower(strings.TrimSpace(releaseGroup))
+	infoHash = strings.ToLower(strings.TrimSpace(infoHash))
+
+	for _, compiled := range m.entries {
+		if compiled.entry.IndexerID != nil && *compiled.entry.IndexerID != indexerID {
+			continue
+		}
+
+		switch compiled.entry.Kind {
+		case models.CrossSeedBlocklistInfoHash:
+			if infoHash != "" && compiled.value == infoHash {
+				return compiled.entry
+			}
+		case models.CrossSeedBlocklistName:
+			if lowerName != "" && compiled.value == lowerName {
+				return compiled.entry
+			}
+		case models.CrossSeedBlocklistReleaseGroup:
+			if releaseGroup != "" && compiled.value == releaseGroup {
+				return compiled.entry
+			}
+		case models.CrossSeedBlocklistRegex:
+			if name != "" && compiled.re.MatchString(name) {
+				return compiled.entry
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListBlocklist returns all cross-seed blocklist entries.
+func (s *Service) ListBlocklist(ctx context.Context) ([]*models.CrossSeedBlocklistEntry, error) {
+	if s.blocklistStore == nil {
+		return []*models.CrossSeedBlocklistEntry{}, nil
+	}
+	entries, err := s.blocklistStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list blocklist: %w", err)
+	}
+	if entries == nil {
+		entries = []*models.CrossSeedBlocklistEntry{}
+	}
+	return entries, nil
+}
+
+// AddBlocklistEntry validates and persists a blocklist entry.
+func (s *Service) AddBlocklistEntry(ctx context.Context, entry *models.CrossSeedBlocklistEntry) (*models.CrossSeedBlocklistEntry, error) {
+	if s.blocklistStore == nil {
+		return nil, errors.New("blocklist not configured")
+	}
+	if err := normalizeBlocklistEntry(entry); err != nil {
+		return nil, err
+	}
+
+	created, err := s.blocklistStore.Create(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateBlocklist()
+	return created, nil
+}
+
+// DeleteBlocklistEntry removes a blocklist entry.
+func (s *Service) DeleteBlocklistEntry(ctx context.Context, id int64) error {
+	if s.blocklistStore == nil {
+		return errors.New("blocklist not configured")
+	}
+	if err := s.blocklistStore.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateBlocklist()
+	return nil
+}
+
+// BlocklistFromCrossSeed creates one blocklist entry per requested kind from a
+// failed or removed cross-seed. Kinds default to infohash only.
+func (s *Service) BlocklistFromCrossSeed(ctx context.Context, req *BlocklistFromCrossSeedRequest) ([]*models.CrossSeedBlocklistEntry, error) {
+	if s.blocklistStore == nil {
+		return nil, errors.New("blocklist not configured")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("%w: request is required", ErrInvalidRequest)
+	}
+
+	infoHash := strings.ToLower(strings.TrimSpace(req.InfoHash))
+	torrentName := strings.TrimSpace(req.TorrentName)
+
+	if torrentName == "" && infoHash != "" && req.InstanceID > 0 && s.syncManager != nil {
+		torrent, exists, err := s.syncManager.HasTorrentByAnyHash(ctx, req.InstanceID, []string{infoHash})
+		if err != nil {
+			log.Debug().Err(err).Int("instanceID", req.InstanceID).Str("hash", infoHash).Msg("[CROSSSEED-BLOCKLIST] Failed to look up torrent name")
+		} else if exists && torrent != nil {
+			torrentName = torrent.Name
+		}
+	}
+
+	var indexerID *int
+	if req.ScopeToIndexer {
+		id, err := s.resolveIndexerIDByName(ctx, req.IndexerName)
+		if err != nil {
+			return nil, err
+		}
+		indexerID = &id
+	}
+
+	kinds := req.Kinds
+	if len(kinds) == 0 {
+		kinds = []models.CrossSeedBlocklistKind{models.CrossSeedBlocklistInfoHash}
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" && torrentName != "" {
+		reason = "Blocked from cross-seed " + torrentName
+	}
+
+	entries := make([]*models.CrossSeedBlocklistEntry, 0, len(kinds))
+	for _, kind := range kinds {
+		entry := &models.CrossSeedBlocklistEntry{Kind: kind, IndexerID: indexerID, Reason: reason}
+		switch kind {
+		case models.CrossSeedBlocklistInfoHash:
+			entry.Value = infoHash
+		case models.CrossSeedBlocklistName:
+			entry.Value = torrentName
+		case models.CrossSeedBlocklistReleaseGroup:
+			if torrentName != "" && s.releaseCache != nil {
+				entry.Value = s.releaseCache.Parse(torrentName).Group
+			}
+			if entry.Value == "" {
+				return nil, fmt.Errorf("%w: could not determine release group for %q", ErrInvalidRequest, torrentName)
+			}
+		default:
+			return nil, fmt.Errorf("%w: kind %q cannot be derived from a cross-seed", ErrInvalidRequest, kind)
+		}
+		if err := normalizeBlocklistEntry(entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	created := make([]*models.CrossSeedBlocklistEntry, 0, len(entries))
+	for _, entry := range entries {
+		stored, err := s.blocklistStore.Create(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, stored)
+	}
+	s.invalidateBlocklist()
+
+	return created, nil
+}
+
+// resolveIndexerIDByName maps an enabled indexer name to its ID.
+func (s *Service) resolveIndexerIDByName(ctx context.Context, indexerName string) (int, error) {
+	indexerName = strings.TrimSpace(indexerName)
+	if indexerName == "" {
+		return 0, fmt.Errorf("%w: indexerName is required to scope an entry", ErrInvalidRequest)
+	}
+	if s.jackettService == nil {
+		return 0, errors.New("torznab indexers not configured")
+	}
+
+	indexers, err := s.jackettService.GetEnabledIndexersInfo(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("load indexers: %w", err)
+	}
+
+	normalized := s.normalizeIndexerName(indexerName)
+	for id, info := range indexers {
+		if strings.EqualFold(info.Name, indexerName) || s.normalizeIndexerName(info.Name) == normalized {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: unknown indexer %q", ErrInvalidRequest, indexerName)
+}
+
+func normalizeBlocklistEntry(entry *models.CrossSeedBlocklistEntry) error {
+	if entry == nil {
+		return fmt.Errorf("%w: blocklist entry is required", ErrInvalidRequest)
+	}
+	if !entry.Kind.IsValid() {
+		return fmt.Errorf("%w: unsupported blocklist kind %q", ErrInvalidRequest, entry.Kind)
+	}
+	if entry.IndexerID != nil && *entry.IndexerID <= 0 {
+		entry.IndexerID = nil
+	}
+
+	entry.Value = strings.TrimSpace(entry.Value)
+	entry.Reason = strings.TrimSpace(entry.Reason)
+	if entry.Value == "" {
+		return fmt.Errorf("%w: blocklist value is required", ErrInvalidRequest)
+	}
+
+	switch entry.Kind {
+	case models.CrossSeedBlocklistInfoHash:
+		entry.Value = strings.ToLower(entry.Value)
+		if len(entry.Value) != 40 && len(entry.Value) != 64 {
+			return fmt.Errorf("%w: infohash must be 40 or 64 hex characters", ErrInvalidRequest)
+		}
+		if _, err := hex.DecodeString(entry.Value); err != nil {
+			return fmt.Errorf("%w: infohash must be hexadecimal", ErrInvalidRequest)
+		}
+	case models.CrossSeedBlocklistRegex:
+		if _, err := regexp.Compile(entry.Value); err != nil {
+			return fmt.Errorf("%w: invalid regex: %v", ErrInvalidRequest, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) invalidateBlocklist() {
+	s.blocklistMu.Lock()
+	s.blocklistMatcher = nil
+	s.blocklistMu.Unlock()
+}
+
+// loadBlocklist returns the cached matcher, loading it from the store when needed.
+func (s *Service) loadBlocklist(ctx context.Context) *blocklistMatcher {
+	if s.blocklistStore == nil {
+		return nil
+	}
+
+	s.blocklistMu.Lock()
+	defer s.blocklistMu.Unlock()
+
+	if s.blocklistMatcher != nil {
+		return s.blocklistMatcher
+	}
+
+	entries, err := s.blocklistStore.List(ctx)
+	if err != nil {
+		// Do not cache failures so the next candidate retries the load.
+		log.Warn().Err(err).Msg("[CROSSSEED-BLOCKLIST] Failed to load blocklist, allowing releases")
+		return nil
+	}
+
+	s.blocklistMatcher = newBlocklistMatcher(entries)
+	return s.blocklistMatcher
+}
+
+// checkBlocklist returns the entry blocking a release, or nil when it is allowed.
+func (s *Service) checkBlocklist(ctx context.Context, name, infoHash string, indexerID int) *models.CrossSeedBlocklistEntry {
+	matcher := s.loadBlocklist(ctx)
+	if matcher == nil || len(matcher.entries) == 0 {
+		return nil
+	}
+
+	var group string
+	if name != "" && s.releaseCache != nil {
+		group = s.releaseCache.Parse(name).Group
+	}
+
+	return matcher.match(name, group, infoHash, indexerID)
+}
+
+// blocklistMessage describes why a release was rejected.
+func blocklistMessage(entry *models.CrossSeedBlocklistEntry, title string) string {
+	msg := fmt.Sprintf("Blocklisted (%s %q): %s", entry.Kind, entry.Value, title)
+	if entry.Reason != "" {
+		msg += " - " + entry.Reason
+	}
+	return msg
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type fakeBlocklistStore struct {
+	entries []*models.CrossSeedBlocklistEntry
+	lists   int
+}
+
+func (f *fakeBlocklistStore) List(context.Context) ([]*models.CrossSeedBlocklistEntry, error) {
+	f.lists++
+	return f.entries, nil
+}
+
+func (f *fakeBlocklistStore) Create(_ context.Context, entry *models.CrossSeedBlocklistEntry) (*models.CrossSeedBlocklistEntry, error) {
+	entry.ID = int64(len(f.entries) + 1)
+	f.entries = append(f.entries, entry)
+	return entry, nil
+}
+
+func (f *fakeBlocklistStore) Delete(context.Context, int64) error {
+	return nil
+}
+
+func TestCheckBlocklist(t *testing.T) {
+	scoped := 7
+	store := &fakeBlocklistStore{entries: []*models.CrossSeedBlocklistEntry{
+		{ID: 1, Kind: models.CrossSeedBlocklistInfoHash, Value: "0123456789abcdef0123456789abcdef01234567"},
+		{ID: 2, Kind: models.CrossSeedBlocklistName, Value: "Some.Show.S01E01.1080p.WEB-DL.x264-GRP"},
+		{ID: 3, Kind: models.CrossSeedBlocklistReleaseGroup, Value: "badgrp", IndexerID: &scoped},
+		{ID: 4, Kind: models.CrossSeedBlocklistRegex, Value: `(?i)\bCAM\b`},
+	}}
+	s := &Service{blocklistStore: store, releaseCache: NewReleaseCache()}
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		title     string
+		infoHash  string
+		indexerID int
+		wantID    int64
+	}{
+		{"infohash is case-insensitive", "Anything", "0123456789ABCDEF0123456789ABCDEF01234567", 1, 1},
+		{"exact name", "some.show.s01e01.1080p.web-dl.x264-grp", "", 1, 2},
+		{"release group on scoped indexer", "Movie.2024.1080p.BluRay.x264-BADGRP", "", 7, 3},
+		{"release group on other indexer", "Movie.2024.1080p.BluRay.x264-BADGRP", "", 8, 0},
+		{"regex", "Movie.2024.CAM.x264-GRP", "", 1, 4},
+		{"allowed", "Movie.2024.1080p.BluRay.x264-GOOD", "", 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := s.checkBlocklist(ctx, tt.title, tt.infoHash, tt.indexerID)
+			if tt.wantID == 0 {
+				assert.Nil(t, entry)
+				return
+			}
+			require.NotNil(t, entry)
+			assert.Equal(t, tt.wantID, entry.ID)
+		})
+	}
+
+	assert.Equal(t, 1, store.lists, "matcher should be cached between checks")
+}
+
+func TestAddBlocklistEntry_ValidatesAndInvalidatesCache(t *testing.T) {
+	store := &fakeBlocklistStore{}
+	s := &Service{blocklistStore: store, releaseCache: NewReleaseCache()}
+	ctx := context.Background()
+
+	assert.Nil(t, s.checkBlocklist(ctx, "Movie.2024.1080p.BluRay.x264-GRP", "", 1))
+
+	_, err := s.AddBlocklistEntry(ctx, &models.CrossSeedBlocklistEntry{Kind: models.CrossSeedBlocklistRegex, Value: "("})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = s.AddBlocklistEntry(ctx, &models.CrossSeedBlocklistEntry{Kind: models.CrossSeedBlocklistInfoHash, Value: "nothex"})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = s.AddBlocklistEntry(ctx, &models.CrossSeedBlocklistEntry{Kind: models.CrossSeedBlocklistReleaseGroup, Value: " GRP "})
+	require.NoError(t, err)
+
+	entry := s.checkBlocklist(ctx, "Movie.2024.1080p.BluRay.x264-GRP", "", 1)
+	require.NotNil(t, entry)
+	assert.Equal(t, "GRP", entry.Value)
+}
+
+func TestBlocklistFromCrossSeed(t *testing.T) {
+	store := &fakeBlocklistStore{}
+	s := &Service{blocklistStore: store, releaseCache: NewReleaseCache()}
+
+	entries, err := s.BlocklistFromCrossSeed(context.Background(), &BlocklistFromCrossSeedRequest{
+		InfoHash:    "0123456789ABCDEF0123456789ABCDEF01234567",
+		TorrentName: "Movie.2024.1080p.BluRay.x264-GRP",
+		Kinds:       []models.CrossSeedBlocklistKind{models.CrossSeedBlocklistInfoHash, models.CrossSeedBlocklistReleaseGroup},
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", entries[0].Value)
+	assert.Equal(t, "GRP", entries[1].Value)
+	assert.Contains(t, entries[0].Reason, "Movie.2024")
+
+	_, err = s.BlocklistFromCrossSeed(context.Background(), &BlocklistFromCrossSeedRequest{
+		InfoHash:       "0123456789abcdef0123456789abcdef01234567",
+		ScopeToIndexer: true,
+	})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = s.BlocklistFromCrossSeed(context.Background(), &BlocklistFromCrossSeedRequest{
+		Kinds: []models.CrossSeedBlocklistKind{models.CrossSeedBlocklistRegex},
+	})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+}
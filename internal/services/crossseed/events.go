@@ -0,0 +1,35 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"github.com/autobrr/qui/internal/events"
+)
+
+// SetEventBus sets the bus successful injections are published to.
+func (s *Service) SetEventBus(bus *events.Bus) {
+	s.eventBus = bus
+}
+
+func (s *Service) publishInjected(result InstanceCrossSeedResult, torrentName, indexerName string) {
+	if result.Status != "added" {
+		return
+	}
+
+	payload := events.CrossSeedInjection{
+		InstanceName: result.InstanceName,
+		TorrentName:  torrentName,
+		IndexerName:  indexerName,
+	}
+	if result.MatchedTorrent != nil {
+		payload.MatchedHash = result.MatchedTorrent.Hash
+		payload.MatchedName = result.MatchedTorrent.Name
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:       events.CrossSeedInjected,
+		InstanceID: result.InstanceID,
+		Payload:    payload,
+	})
+}
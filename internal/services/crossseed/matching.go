@@ -122,6 +122,11 @@ func (s *Service) releasesMatch(source, candidate *rls.Release, findIndividualEp
 		return true
 	}
 
+	// Music, books and audiobooks have their own matchers; the checks below are video-centric.
+	if matched, handled := s.mediaReleasesMatch(source, candidate); handled {
+		return matched
+	}
+
 	// Title should match closely but not necessarily exactly.
 	// Use punctuation-stripping normalization to handle differences like
 	// "Bob's Burgers" vs "Bobs.Burgers" (apostrophes lost in dot notation).
@@ -693,6 +698,20 @@ func (s *Service) getMatchTypeWithReason(sourceRelease, candidateRelease *rls.Re
 		return MatchResult{MatchType: "exact", Reason: ""}
 	}
 
+	// Music and book torrents: track/chapter names differ between releases, so compare
+	// media file sizes instead. Release keys carry no per-file identity for these.
+	mediaFamily := releaseMediaFamily(sourceRelease)
+	if mediaFamily != mediaFamilyNone && releaseMediaFamily(candidateRelease) == mediaFamily {
+		if mediaFileSizesMatch(mediaFamily, filteredSourceFiles, filteredCandidateFiles) {
+			if s.metrics != nil {
+				s.metrics.GetMatchTypeSizeMatch.Inc()
+			}
+			return MatchResult{MatchType: "size", Reason: ""}
+		}
+		clear(sourceReleaseKeys)
+		clear(candidateReleaseKeys)
+	}
+
 	// Check for partial match
 	if len(sourceReleaseKeys) > 0 && len(candidateReleaseKeys) > 0 {
 		if s.checkPartialMatch(sourceReleaseKeys, candidateReleaseKeys) {
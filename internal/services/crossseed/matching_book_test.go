@@ -0,0 +1,109 @@
+package crossseed
+
+import (
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/pkg/stringutils"
+)
+
+func TestReleasesMatch_Books(t *testing.T) {
+	s := &Service{
+		releaseCache:     NewReleaseCache(),
+		stringNormalizer: stringutils.NewDefaultNormalizer(),
+	}
+
+	tests := []struct {
+		name      string
+		source    string
+		candidate string
+		want      bool
+	}{
+		{
+			name:      "same ebook",
+			source:    "Stephen King - The Stand (2012) [EPUB]",
+			candidate: "Stephen King - The Stand (2012) [EPUB]",
+			want:      true,
+		},
+		{
+			name:      "author parsed on one side only",
+			source:    "Stephen King - The Stand (2012) [EPUB]",
+			candidate: "Stephen.King.The.Stand.2012.RETAIL.EPUB.eBook",
+			want:      true,
+		},
+		{
+			name:      "EPUB vs PDF",
+			source:    "Stephen King - The Stand (2012) [EPUB]",
+			candidate: "Stephen King - The Stand (2012) [PDF]",
+			want:      false,
+		},
+		{
+			name:      "different author",
+			source:    "Stephen King - The Stand (2012) [EPUB]",
+			candidate: "Someone Else - The Stand (2012) [EPUB]",
+			want:      false,
+		},
+		{
+			name:      "different edition year",
+			source:    "Stephen King - The Stand (2012) [EPUB]",
+			candidate: "Stephen King - The Stand (1990) [EPUB]",
+			want:      false,
+		},
+		{
+			name:      "same audiobook",
+			source:    "Stephen_King-The_Stand-AUDIOBOOK-WEB-2012-GRP",
+			candidate: "Stephen_King-The_Stand-AUDIOBOOK-WEB-2012-GRP",
+			want:      true,
+		},
+		{
+			name:      "audiobook bitrate differs",
+			source:    "Brandon Sanderson - Mistborn (2006) [MP3 64kbps] Audiobook",
+			candidate: "Brandon Sanderson - Mistborn (2006) [MP3 128kbps] Audiobook",
+			want:      false,
+		},
+		{
+			name:      "audiobook bitrate matches",
+			source:    "Brandon Sanderson - Mistborn (2006) [MP3 64kbps] Audiobook",
+			candidate: "Brandon Sanderson - Mistborn (2006) [MP3 64kbps] Audiobook",
+			want:      true,
+		},
+		{
+			name:      "ebook never matches audiobook",
+			source:    "Stephen King - The Stand (2012) [EPUB]",
+			candidate: "Stephen_King-The_Stand-AUDIOBOOK-WEB-2012-GRP",
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := s.releaseCache.Parse(tt.source)
+			candidate := s.releaseCache.Parse(tt.candidate)
+			require.Equal(t, tt.want, s.releasesMatch(source, candidate, false))
+		})
+	}
+}
+
+func TestGetMatchType_AudiobookToleratesChapterNameDifferences(t *testing.T) {
+	s := &Service{
+		releaseCache:     NewReleaseCache(),
+		stringNormalizer: stringutils.NewDefaultNormalizer(),
+	}
+
+	source := s.releaseCache.Parse("Stephen_King-The_Stand-AUDIOBOOK-WEB-2012-GRP")
+	candidate := s.releaseCache.Parse("Stephen_King-The_Stand-AUDIOBOOK-WEB-2012-GRP")
+
+	sourceFiles := qbt.TorrentFiles{
+		{Name: "The Stand/Part 1.m4b", Size: 400_000_000},
+		{Name: "The Stand/Part 2.m4b", Size: 380_000_000},
+	}
+	candidateFiles := qbt.TorrentFiles{
+		{Name: "Stephen King - The Stand/The Stand - 02.m4b", Size: 380_000_000},
+		{Name: "Stephen King - The Stand/The Stand - 01.m4b", Size: 400_000_000},
+	}
+
+	result := s.getMatchTypeWithReason(source, candidate, sourceFiles, candidateFiles, 0)
+	require.Equal(t, "size", result.MatchType)
+}
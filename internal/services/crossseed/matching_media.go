@@ -0,0 +1,312 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/moistari/rls"
+
+	"github.com/autobrr/qui/pkg/stringutils"
+)
+
+// matching_media.go holds the content-type specific matchers for music, books and
+// audiobooks. The generic matcher in matching.go is built around video attributes
+// (resolution, HDR, codec, source) that these releases never carry.
+
+// mediaFamily groups release types that share a matching strategy.
+type mediaFamily int
+
+const (
+	mediaFamilyNone mediaFamily = iota
+	mediaFamilyMusic
+	mediaFamilyBook
+)
+
+var (
+	// reMediaQualityNoise matches bitrate, bit depth and sample rate tokens that rls
+	// sometimes leaves in the title (e.g. "Mistborn 64kbps").
+	reMediaQualityNoise = regexp.MustCompile(`(?i)\b(\d{2,4})\s?kbps\b|\b(\d{2,3}(?:\.\d)?)\s?khz\b|\b(16|24|32)\s?-?bit\b`)
+	// reBookEditionNoise matches book tags that do not change the work itself.
+	reBookEditionNoise = regexp.MustCompile(`(?i)\b(un)?abridged\b|\bretail\b|\bebook\b|\baudiobook\b`)
+)
+
+// musicFormats maps audio tokens to a canonical file format.
+var musicFormats = map[string]string{
+	"FLAC":   "FLAC",
+	"MP3":    "MP3",
+	"AAC":    "AAC",
+	"ALAC":   "ALAC",
+	"OGG":    "OGG",
+	"VORBIS": "OGG",
+	"OPUS":   "OPUS",
+	"WAV":    "WAV",
+	"DSD":    "DSD",
+	"APE":    "APE",
+	"M4A":    "AAC",
+	"M4B":    "M4B",
+}
+
+// bookFormats maps container tokens to a canonical ebook format.
+var bookFormats = map[string]string{
+	"EPUB": "EPUB",
+	"MOBI": "MOBI",
+	"AZW":  "AZW",
+	"AZW3": "AZW3",
+	"PDF":  "PDF",
+	"DJVU": "DJVU",
+	"FB2":  "FB2",
+	"CBZ":  "CBZ",
+	"CBR":  "CBR",
+}
+
+var musicFileExtensions = []string{".flac", ".mp3", ".m4a", ".aac", ".ogg", ".opus", ".wav", ".ape", ".wv", ".dsf", ".dff", ".aiff", ".alac"}
+
+var bookFileExtensions = []string{".epub", ".mobi", ".azw", ".azw3", ".pdf", ".djvu", ".fb2", ".cbz", ".cbr", ".m4b", ".m4a", ".mp3", ".aac", ".ogg", ".opus", ".flac"}
+
+// mediaReleaseInfo is the normalized identity of a music, book or audiobook release.
+type mediaReleaseInfo struct {
+	creator string // artist or author
+	title   string // album or book title
+	year    int
+	month   int
+	day     int
+	format  string
+	quality string
+	source  string
+	group   string
+}
+
+// releaseMediaFamily classifies a release for media-specific matching.
+// Music releases that look like video (resolution, HDR, video codecs) are excluded,
+// mirroring DetermineContentType.
+func releaseMediaFamily(r *rls.Release) mediaFamily {
+	if r == nil {
+		return mediaFamilyNone
+	}
+	switch r.Type {
+	case rls.Music:
+		if looksLikeVideoRelease(r) {
+			return mediaFamilyNone
+		}
+		return mediaFamilyMusic
+	case rls.Audiobook, rls.Book:
+		return mediaFamilyBook
+	default:
+		return mediaFamilyNone
+	}
+}
+
+// mediaReleasesMatch dispatches to the music or book matcher when both releases
+// belong to a media family. handled is false when the generic matcher should decide.
+func (s *Service) mediaReleasesMatch(source, candidate *rls.Release) (matched, handled bool) {
+	sourceFamily := releaseMediaFamily(source)
+	candidateFamily := releaseMediaFamily(candidate)
+	if sourceFamily == mediaFamilyNone || candidateFamily == mediaFamilyNone {
+		return false, false
+	}
+	if sourceFamily != candidateFamily {
+		return false, true
+	}
+
+	switch sourceFamily {
+	case mediaFamilyMusic:
+		return s.musicReleasesMatch(source, candidate), true
+	case mediaFamilyBook:
+		return s.bookReleasesMatch(source, candidate), true
+	default:
+		return false, false
+	}
+}
+
+// musicReleasesMatch compares artist, album, year, format and quality.
+// Release group and source (CD/WEB/Vinyl) must match when present since they are
+// different rips with different files.
+func (s *Service) musicReleasesMatch(source, candidate *rls.Release) bool {
+	src := extractMediaReleaseInfo(source, mediaFamilyMusic)
+	cand := extractMediaReleaseInfo(candidate, mediaFamilyMusic)
+
+	if !mediaIdentityMatches(src, cand) {
+		return false
+	}
+	if src.source != "" && cand.source != "" && src.source != cand.source {
+		return false
+	}
+	if !mediaAttributesMatch(src, cand) {
+		return false
+	}
+
+	compatible, _ := checkVariantsCompatible(source, candidate)
+	return compatible
+}
+
+// bookReleasesMatch compares author, title, year and format for ebooks and audiobooks.
+// Ebooks never match audiobooks even when the work is the same.
+func (s *Service) bookReleasesMatch(source, candidate *rls.Release) bool {
+	if source.Type != candidate.Type {
+		return false
+	}
+
+	src := extractMediaReleaseInfo(source, mediaFamilyBook)
+	cand := extractMediaReleaseInfo(candidate, mediaFamilyBook)
+
+	if !mediaIdentityMatches(src, cand) {
+		return false
+	}
+
+	return mediaAttributesMatch(src, cand)
+}
+
+// mediaIdentityMatches checks creator, title and release date. When only one side has a
+// creator (scene names like "Author.Title.2012.EPUB-GRP" parse without one), the
+// combined "creator title" strings are compared instead.
+func mediaIdentityMatches(src, cand mediaReleaseInfo) bool {
+	if src.title == "" || cand.title == "" {
+		return false
+	}
+
+	if src.creator != "" && cand.creator != "" {
+		if src.creator != cand.creator || src.title != cand.title {
+			return false
+		}
+	} else if joinCreatorTitle(src) != joinCreatorTitle(cand) {
+		return false
+	}
+
+	if src.year > 0 && cand.year > 0 && src.year != cand.year {
+		return false
+	}
+
+	// Date-based releases (0day radio shows, live sets) must be from the same day.
+	if src.month > 0 && src.day > 0 && cand.month > 0 && cand.day > 0 &&
+		(src.month != cand.month || src.day != cand.day) {
+		return false
+	}
+
+	return true
+}
+
+// mediaAttributesMatch checks format, quality and group.
+func mediaAttributesMatch(src, cand mediaReleaseInfo) bool {
+	// FLAC vs MP3 or EPUB vs PDF are different files.
+	if src.format != "" && cand.format != "" && src.format != cand.format {
+		return false
+	}
+	// 24bit vs 16bit or 320 vs 64kbps are different encodes.
+	if src.quality != "" && cand.quality != "" && src.quality != cand.quality {
+		return false
+	}
+	// Only enforce the group when the source has one, matching the video matcher.
+	if src.group != "" && src.group != cand.group {
+		return false
+	}
+	return true
+}
+
+func joinCreatorTitle(info mediaReleaseInfo) string {
+	if info.creator == "" {
+		return info.title
+	}
+	return info.creator + " " + info.title
+}
+
+// extractMediaReleaseInfo normalizes the parsed fields relevant to media matching.
+func extractMediaReleaseInfo(r *rls.Release, family mediaFamily) mediaReleaseInfo {
+	info := mediaReleaseInfo{
+		creator: stringutils.NormalizeForMatching(r.Artist),
+		year:    r.Year,
+		month:   r.Month,
+		day:     r.Day,
+	}
+
+	var quality []string
+	title := r.Title
+	for _, m := range reMediaQualityNoise.FindAllStringSubmatch(title, -1) {
+		quality = append(quality, mediaQualityToken(m))
+	}
+	title = reMediaQualityNoise.ReplaceAllString(title, " ")
+	if family == mediaFamilyBook {
+		title = reBookEditionNoise.ReplaceAllString(title, " ")
+	}
+	info.title = stringutils.NormalizeForMatching(title)
+
+	for _, token := range r.Audio {
+		upper := strings.ToUpper(strings.ReplaceAll(token, " ", ""))
+		if format, ok := musicFormats[upper]; ok {
+			info.format = format
+			continue
+		}
+		if m := reMediaQualityNoise.FindStringSubmatch(token); m != nil {
+			quality = append(quality, mediaQualityToken(m))
+		}
+	}
+
+	if family == mediaFamilyBook {
+		if format, ok := bookFormats[strings.ToUpper(r.Container)]; ok {
+			info.format = format
+		}
+	} else {
+		switch source := strings.ToUpper(strings.TrimSpace(r.Source)); source {
+		case "", "AUDIOBOOK":
+		default:
+			info.source = source
+		}
+	}
+
+	if len(quality) > 0 {
+		slices.Sort(quality)
+		info.quality = strings.Join(slices.Compact(quality), " ")
+	}
+
+	// rls occasionally reads a trailing quality tag ("[FLAC 24bit]") as the group.
+	if group := strings.TrimSpace(r.Group); group != "" && !reMediaQualityNoise.MatchString(group) {
+		info.group = strings.ToUpper(group)
+	}
+
+	return info
+}
+
+// mediaQualityToken canonicalizes a reMediaQualityNoise submatch.
+func mediaQualityToken(m []string) string {
+	switch {
+	case m[1] != "":
+		return m[1] + "KBPS"
+	case m[2] != "":
+		return m[2] + "KHZ"
+	default:
+		return m[3] + "BIT"
+	}
+}
+
+// mediaFileSizesMatch reports whether both torrents contain the same set of media
+// file sizes. Track and chapter file names vary between releases of the same album
+// or audiobook, so only sizes are compared; renames are handled during alignment.
+func mediaFileSizesMatch(family mediaFamily, sourceFiles, candidateFiles []TorrentFile) bool {
+	extensions := musicFileExtensions
+	if family == mediaFamilyBook {
+		extensions = bookFileExtensions
+	}
+
+	sourceSizes := mediaFileSizes(sourceFiles, extensions)
+	candidateSizes := mediaFileSizes(candidateFiles, extensions)
+	if len(sourceSizes) == 0 {
+		return false
+	}
+
+	return slices.Equal(sourceSizes, candidateSizes)
+}
+
+func mediaFileSizes(files []TorrentFile, extensions []string) []int64 {
+	sizes := make([]int64, 0, len(files))
+	for _, f := range files {
+		if slices.Contains(extensions, strings.ToLower(filepath.Ext(f.Name))) {
+			sizes = append(sizes, f.Size)
+		}
+	}
+	slices.Sort(sizes)
+	return sizes
+}
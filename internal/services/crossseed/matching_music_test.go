@@ -0,0 +1,132 @@
+package crossseed
+
+import (
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/moistari/rls"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/pkg/stringutils"
+)
+
+func TestReleasesMatch_Music(t *testing.T) {
+	s := &Service{
+		releaseCache:     NewReleaseCache(),
+		stringNormalizer: stringutils.NewDefaultNormalizer(),
+	}
+
+	tests := []struct {
+		name      string
+		source    string
+		candidate string
+		want      bool
+	}{
+		{
+			name:      "same scene release",
+			source:    "Artist-Album_Title-WEB-FLAC-2024-GRP",
+			candidate: "Artist-Album_Title-WEB-FLAC-2024-GRP",
+			want:      true,
+		},
+		{
+			name:      "p2p naming with same format",
+			source:    "Artist Name - Album Title (2024) [FLAC]",
+			candidate: "Artist Name - Album Title (2024) [FLAC]",
+			want:      true,
+		},
+		{
+			name:      "different artist same album",
+			source:    "Artist Name - Album Title (2024) [FLAC]",
+			candidate: "Other Artist - Album Title (2024) [FLAC]",
+			want:      false,
+		},
+		{
+			name:      "different album",
+			source:    "Artist Name - Album Title (2024) [FLAC]",
+			candidate: "Artist Name - Another Album (2024) [FLAC]",
+			want:      false,
+		},
+		{
+			name:      "different year",
+			source:    "Artist Name - Album Title (2024) [FLAC]",
+			candidate: "Artist Name - Album Title (2019) [FLAC]",
+			want:      false,
+		},
+		{
+			name:      "FLAC vs MP3",
+			source:    "Artist Name - Album Title (2024) [FLAC]",
+			candidate: "Artist Name - Album Title (2024) [MP3]",
+			want:      false,
+		},
+		{
+			name:      "24bit vs 16bit FLAC",
+			source:    "Artist-Album_Title-24BIT-WEB-FLAC-2024-GRP",
+			candidate: "Artist-Album_Title-16BIT-WEB-FLAC-2024-GRP",
+			want:      false,
+		},
+		{
+			name:      "CD vs WEB rip",
+			source:    "Artist-Album_Title-CD-FLAC-2024-GRP",
+			candidate: "Artist-Album_Title-WEB-FLAC-2024-GRP",
+			want:      false,
+		},
+		{
+			name:      "different group",
+			source:    "Artist-Album_Title-WEB-FLAC-2024-GRP",
+			candidate: "Artist-Album_Title-WEB-FLAC-2024-OTHER",
+			want:      false,
+		},
+		{
+			name:      "quality tag misparsed as group is ignored",
+			source:    "Artist Name - Album Title (2024) [FLAC 24bit 96kHz]",
+			candidate: "Artist Name - Album Title (2024) [FLAC 24bit 96kHz]",
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := s.releaseCache.Parse(tt.source)
+			candidate := s.releaseCache.Parse(tt.candidate)
+			require.Equal(t, tt.want, s.releasesMatch(source, candidate, false))
+		})
+	}
+}
+
+func TestReleasesMatch_MusicDoesNotMatchAudiobook(t *testing.T) {
+	s := &Service{stringNormalizer: stringutils.NewDefaultNormalizer()}
+
+	album := &rls.Release{Type: rls.Music, Artist: "Artist", Title: "Live", Year: 2024, Audio: []string{"FLAC"}}
+	audiobook := &rls.Release{Type: rls.Audiobook, Artist: "Artist", Title: "Live", Year: 2024}
+
+	require.False(t, s.releasesMatch(album, audiobook, false))
+}
+
+func TestGetMatchType_MusicToleratesTrackNameDifferences(t *testing.T) {
+	s := &Service{
+		releaseCache:     NewReleaseCache(),
+		stringNormalizer: stringutils.NewDefaultNormalizer(),
+	}
+
+	source := s.releaseCache.Parse("Artist Name - Album Title (2024) [FLAC]")
+	candidate := s.releaseCache.Parse("Artist Name - Album Title (2024) [FLAC]")
+
+	sourceFiles := qbt.TorrentFiles{
+		{Name: "Artist Name - Album Title (2024) [FLAC]/01 - Intro.flac", Size: 21_000_000},
+		{Name: "Artist Name - Album Title (2024) [FLAC]/02 - Song.flac", Size: 34_000_000},
+		{Name: "Artist Name - Album Title (2024) [FLAC]/cover.jpg", Size: 500_000},
+	}
+	candidateFiles := qbt.TorrentFiles{
+		{Name: "Artist-Album_Title/01. Artist Name - Intro.flac", Size: 21_000_000},
+		{Name: "Artist-Album_Title/02. Artist Name - Song.flac", Size: 34_000_000},
+		{Name: "Artist-Album_Title/folder.jpg", Size: 480_000},
+	}
+
+	result := s.getMatchTypeWithReason(source, candidate, sourceFiles, candidateFiles, 0)
+	require.Equal(t, "size", result.MatchType)
+
+	// A different track size means a different rip.
+	candidateFiles[1].Size = 34_000_001
+	result = s.getMatchTypeWithReason(source, candidate, sourceFiles, candidateFiles, 0)
+	require.Empty(t, result.MatchType)
+}
@@ -38,6 +38,9 @@ type CrossSeedRequest struct {
 	InheritSourceTags bool `json:"inherit_source_tags,omitempty"`
 	// IndexerName specifies the name of the indexer for this torrent (used with useCategoryFromIndexer setting)
 	IndexerName string `json:"indexer_name,omitempty"`
+	// IndexerID identifies the Torznab indexer the torrent came from, used to enforce snatch budgets.
+	// Internal-only; webhook requests resolve the indexer from IndexerName instead.
+	IndexerID int `json:"-"`
 	// FindIndividualEpisodes enables episode-aware matching for season packs. When true,
 	// a season pack source can match individual episode candidates (useful for finding
 	// episodes to seed within a pack). However, applying a season pack cross-seed is
@@ -378,6 +381,9 @@ type WebhookCheckRequest struct {
 	// FindIndividualEpisodes overrides the default behavior when matching season packs vs episodes.
 	// When omitted, qui uses the automation setting; when set, this explicitly forces the behavior.
 	FindIndividualEpisodes *bool `json:"findIndividualEpisodes,omitempty"`
+	// IndexerName is the display name of the announcing indexer. When it matches an indexer with a
+	// snatch budget, a positive check counts against that budget and is refused once it is exhausted.
+	IndexerName string `json:"indexerName,omitempty"`
 }
 
 // WebhookCheckMatch represents a matched torrent in an instance
@@ -395,7 +401,8 @@ type WebhookCheckMatch struct {
 type WebhookCheckResponse struct {
 	CanCrossSeed   bool                `json:"canCrossSeed"`
 	Matches        []WebhookCheckMatch `json:"matches"`
-	Recommendation string              `json:"recommendation"` // "download" or "skip"
+	Recommendation string              `json:"recommendation"`       // "download" or "skip"
+	SkipReason     string              `json:"skipReason,omitempty"` // Set when a policy such as an indexer budget forced "skip"
 }
 
 // AutobrrApplyRequest represents autobrr pushing a torrent directly to qui for application.
@@ -0,0 +1,382 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// RunReportKind identifies the type of run a report describes.
+type RunReportKind string
+
+const (
+	RunReportKindAutomation RunReportKind = "automation"
+	RunReportKindSearch     RunReportKind = "search"
+)
+
+// Decisions recorded for each report item.
+const (
+	RunReportDecisionAdded   = "added"
+	RunReportDecisionExists  = "exists"
+	RunReportDecisionSkipped = "skipped"
+	RunReportDecisionFailed  = "failed"
+)
+
+// runReportWebhookTimeout bounds how long a finished run waits on the summary webhook.
+const runReportWebhookTimeout = 15 * time.Second
+
+// RunReport is an exportable view of an RSS automation or seeded search run.
+type RunReport struct {
+	Kind        RunReportKind    `json:"kind"`
+	RunID       int64            `json:"runId"`
+	InstanceID  int              `json:"instanceId,omitempty"`
+	TriggeredBy string           `json:"triggeredBy,omitempty"`
+	Status      string           `json:"status"`
+	StartedAt   time.Time        `json:"startedAt"`
+	CompletedAt *time.Time       `json:"completedAt,omitempty"`
+	DurationMs  int64            `json:"durationMs"`
+	Summary     RunReportSummary `json:"summary"`
+	Items       []RunReportItem  `json:"items"`
+}
+
+// RunReportSummary holds the run totals.
+type RunReportSummary struct {
+	Considered int    `json:"considered"`
+	Added      int    `json:"added"`
+	Skipped    int    `json:"skipped"`
+	Failed     int    `json:"failed"`
+	BytesSaved int64  `json:"bytesSaved"`
+	Message    string `json:"message,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RunReportItem is a single candidate considered during a run.
+type RunReportItem struct {
+	Title        string     `json:"title"`
+	Decision     string     `json:"decision"`
+	Status       string     `json:"status,omitempty"`
+	Message      string     `json:"message,omitempty"`
+	IndexerName  string     `json:"indexerName,omitempty"`
+	InstanceID   int        `json:"instanceId,omitempty"`
+	InstanceName string     `json:"instanceName,omitempty"`
+	MatchedHash  string     `json:"matchedHash,omitempty"`
+	MatchedName  string     `json:"matchedName,omitempty"`
+	BytesSaved   int64      `json:"bytesSaved"`
+	DurationMs   int64      `json:"durationMs"`
+	ProcessedAt  *time.Time `json:"processedAt,omitempty"`
+}
+
+// runReportWebhookPayload is the summary POSTed when a run finishes. Items are
+// omitted to keep the payload small; the full report is available via the API.
+type runReportWebhookPayload struct {
+	Event       string           `json:"event"`
+	Kind        RunReportKind    `json:"kind"`
+	RunID       int64            `json:"runId"`
+	InstanceID  int              `json:"instanceId,omitempty"`
+	Status      string           `json:"status"`
+	StartedAt   time.Time        `json:"startedAt"`
+	CompletedAt *time.Time       `json:"completedAt,omitempty"`
+	DurationMs  int64            `json:"durationMs"`
+	Summary     RunReportSummary `json:"summary"`
+}
+
+// BuildAutomationRunReport converts a stored RSS automation run into a report.
+func BuildAutomationRunReport(run *models.CrossSeedRun) *RunReport {
+	if run == nil {
+		return nil
+	}
+
+	report := &RunReport{
+		Kind:        RunReportKindAutomation,
+		RunID:       run.ID,
+		TriggeredBy: run.TriggeredBy,
+		Status:      string(run.Status),
+		StartedAt:   run.StartedAt,
+		CompletedAt: run.CompletedAt,
+		DurationMs:  runDurationMs(run.StartedAt, run.CompletedAt),
+		Summary: RunReportSummary{
+			Considered: run.TotalFeedItems,
+			Added:      run.TorrentsAdded,
+			Skipped:    run.TorrentsSkipped,
+			Failed:     run.TorrentsFailed,
+			Message:    derefString(run.Message),
+			Error:      derefString(run.ErrorMessage),
+		},
+		Items: make([]RunReportItem, 0, len(run.Results)),
+	}
+
+	for _, result := range run.Results {
+		item := RunReportItem{
+			Title:        result.ReleaseTitle,
+			Decision:     automationResultDecision(result),
+			Status:       result.Status,
+			Message:      result.Message,
+			IndexerName:  result.IndexerName,
+			InstanceID:   result.InstanceID,
+			InstanceName: result.InstanceName,
+			MatchedHash:  derefString(result.MatchedTorrentHash),
+			MatchedName:  derefString(result.MatchedTorrentName),
+			BytesSaved:   result.BytesSaved,
+			DurationMs:   result.DurationMs,
+		}
+		report.Summary.BytesSaved += result.BytesSaved
+		report.Items = append(report.Items, item)
+	}
+
+	return report
+}
+
+// BuildSearchRunReport converts a stored seeded search run into a report.
+func BuildSearchRunReport(run *models.CrossSeedSearchRun) *RunReport {
+	if run == nil {
+		return nil
+	}
+
+	report := &RunReport{
+		Kind:        RunReportKindSearch,
+		RunID:       run.ID,
+		InstanceID:  run.InstanceID,
+		Status:      string(run.Status),
+		StartedAt:   run.StartedAt,
+		CompletedAt: run.CompletedAt,
+		DurationMs:  runDurationMs(run.StartedAt, run.CompletedAt),
+		Summary: RunReportSummary{
+			Considered: run.Processed,
+			Added:      run.TorrentsAdded,
+			Skipped:    run.TorrentsSkipped,
+			Failed:     run.TorrentsFailed,
+			Message:    derefString(run.Message),
+			Error:      derefString(run.ErrorMessage),
+		},
+		Items: make([]RunReportItem, 0, len(run.Results)),
+	}
+
+	for _, result := range run.Results {
+		title := result.ReleaseTitle
+		if title == "" {
+			title = result.TorrentName
+		}
+		item := RunReportItem{
+			Title:       title,
+			Decision:    searchResultDecision(result),
+			Message:     result.Message,
+			IndexerName: result.IndexerName,
+			InstanceID:  run.InstanceID,
+			MatchedHash: result.TorrentHash,
+			MatchedName: result.TorrentName,
+			BytesSaved:  result.BytesSaved,
+			DurationMs:  result.DurationMs,
+		}
+		if !result.ProcessedAt.IsZero() {
+			processedAt := result.ProcessedAt
+			item.ProcessedAt = &processedAt
+		}
+		report.Summary.BytesSaved += result.BytesSaved
+		report.Items = append(report.Items, item)
+	}
+
+	return report
+}
+
+var runReportCSVHeader = []string{
+	"title", "decision", "status", "message", "indexer",
+	"instance_id", "instance_name", "matched_hash", "matched_name",
+	"bytes_saved", "duration_ms", "processed_at",
+}
+
+// WriteCSV writes one row per report item.
+func (r *RunReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(runReportCSVHeader); err != nil {
+		return err
+	}
+
+	for _, item := range r.Items {
+		instanceID := ""
+		if item.InstanceID > 0 {
+			instanceID = strconv.Itoa(item.InstanceID)
+		}
+		processedAt := ""
+		if item.ProcessedAt != nil {
+			processedAt = item.ProcessedAt.UTC().Format(time.RFC3339)
+		}
+		if err := cw.Write([]string{
+			item.Title,
+			item.Decision,
+			item.Status,
+			item.Message,
+			item.IndexerName,
+			instanceID,
+			item.InstanceName,
+			item.MatchedHash,
+			item.MatchedName,
+			strconv.FormatInt(item.BytesSaved, 10),
+			strconv.FormatInt(item.DurationMs, 10),
+			processedAt,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// GetAutomationRunReport builds the report for a stored RSS automation run.
+func (s *Service) GetAutomationRunReport(ctx context.Context, runID int64) (*RunReport, error) {
+	if s.automationStore == nil {
+		return nil, nil
+	}
+	run, err := s.automationStore.GetRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("load automation run: %w", err)
+	}
+	return BuildAutomationRunReport(run), nil
+}
+
+// GetSearchRunReport builds the report for a stored seeded search run.
+func (s *Service) GetSearchRunReport(ctx context.Context, runID int64) (*RunReport, error) {
+	if s.automationStore == nil {
+		return nil, nil
+	}
+	run, err := s.automationStore.GetSearchRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("load search run: %w", err)
+	}
+	return BuildSearchRunReport(run), nil
+}
+
+// notifyRunReport posts the run summary to the configured webhook in the background.
+func (s *Service) notifyRunReport(report *RunReport) {
+	if s.automationStore == nil || report == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), runReportWebhookTimeout)
+		defer cancel()
+
+		settings, err := s.automationStore.GetSettings(ctx)
+		if err != nil {
+			log.Debug().Err(err).Msg("[CROSSSEED-REPORT] Failed to load settings for run report webhook")
+			return
+		}
+		if settings == nil || settings.RunReportWebhookURL == "" {
+			return
+		}
+
+		if err := sendRunReportWebhook(ctx, http.DefaultClient, settings.RunReportWebhookURL, report); err != nil {
+			log.Warn().
+				Err(err).
+				Str("kind", string(report.Kind)).
+				Int64("runID", report.RunID).
+				Msg("[CROSSSEED-REPORT] Failed to send run report webhook")
+		}
+	}()
+}
+
+func sendRunReportWebhook(ctx context.Context, client *http.Client, webhookURL string, report *RunReport) error {
+	body, err := json.Marshal(runReportWebhookPayload{
+		Event:       "cross_seed.run_completed",
+		Kind:        report.Kind,
+		RunID:       report.RunID,
+		InstanceID:  report.InstanceID,
+		Status:      report.Status,
+		StartedAt:   report.StartedAt,
+		CompletedAt: report.CompletedAt,
+		DurationMs:  report.DurationMs,
+		Summary:     report.Summary,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal run report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// normalizeRunReportWebhookURL trims the configured URL and rejects non-HTTP schemes.
+func normalizeRunReportWebhookURL(settings *models.CrossSeedAutomationSettings) error {
+	settings.RunReportWebhookURL = strings.TrimSpace(settings.RunReportWebhookURL)
+	if settings.RunReportWebhookURL == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(settings.RunReportWebhookURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("%w: runReportWebhookUrl must be an http(s) URL", ErrInvalidRequest)
+	}
+	return nil
+}
+
+func automationResultDecision(result models.CrossSeedRunResult) string {
+	switch {
+	case result.Success && result.Status != "dry-run":
+		return RunReportDecisionAdded
+	case result.Status == "exists":
+		return RunReportDecisionExists
+	case result.Status == "error" || result.Status == "failed":
+		return RunReportDecisionFailed
+	default:
+		return RunReportDecisionSkipped
+	}
+}
+
+// searchFailurePrefixes are the message prefixes processSearchCandidate uses for failures.
+var searchFailurePrefixes = []string{"analyze torrent:", "search failed:", "download failed:", "cross-seed failed:"}
+
+func searchResultDecision(result models.CrossSeedSearchResult) string {
+	if result.Added {
+		return RunReportDecisionAdded
+	}
+	for _, prefix := range searchFailurePrefixes {
+		if strings.HasPrefix(result.Message, prefix) {
+			return RunReportDecisionFailed
+		}
+	}
+	return RunReportDecisionSkipped
+}
+
+func runDurationMs(startedAt time.Time, completedAt *time.Time) int64 {
+	if completedAt == nil || startedAt.IsZero() {
+		return 0
+	}
+	return completedAt.Sub(startedAt).Milliseconds()
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestBuildAutomationRunReport(t *testing.T) {
+	started := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	completed := started.Add(90 * time.Second)
+	hash := "abc123"
+	name := "Movie.2024.1080p.BluRay.x264-GRP"
+
+	report := BuildAutomationRunReport(&models.CrossSeedRun{
+		ID:             7,
+		TriggeredBy:    "scheduler",
+		Status:         models.CrossSeedRunStatusPartial,
+		StartedAt:      started,
+		CompletedAt:    &completed,
+		TotalFeedItems: 4,
+		TorrentsAdded:  1,
+		Results: []models.CrossSeedRunResult{
+			{InstanceID: 1, InstanceName: "main", IndexerName: "TrackerA", Success: true, Status: "added", ReleaseTitle: name, MatchedTorrentHash: &hash, MatchedTorrentName: &name, BytesSaved: 4096, DurationMs: 250},
+			{InstanceID: 1, InstanceName: "main", IndexerName: "TrackerA", Status: "exists", ReleaseTitle: "Other.Release-GRP"},
+			{IndexerName: "TrackerB", Status: "no_match", ReleaseTitle: "Unknown.Release-GRP"},
+			{IndexerName: "TrackerB", Success: true, Status: "dry-run", ReleaseTitle: "Dry.Release-GRP"},
+			{InstanceID: 2, IndexerName: "TrackerB", Status: "error", Message: "boom"},
+		},
+	})
+
+	require.NotNil(t, report)
+	assert.Equal(t, RunReportKindAutomation, report.Kind)
+	assert.Equal(t, int64(7), report.RunID)
+	assert.Equal(t, int64(90000), report.DurationMs)
+	assert.Equal(t, int64(4096), report.Summary.BytesSaved)
+	assert.Equal(t, 4, report.Summary.Considered)
+	require.Len(t, report.Items, 5)
+
+	decisions := make([]string, 0, len(report.Items))
+	for _, item := range report.Items {
+		decisions = append(decisions, item.Decision)
+	}
+	assert.Equal(t, []string{
+		RunReportDecisionAdded,
+		RunReportDecisionExists,
+		RunReportDecisionSkipped,
+		RunReportDecisionSkipped,
+		RunReportDecisionFailed,
+	}, decisions)
+	assert.Equal(t, hash, report.Items[0].MatchedHash)
+	assert.Equal(t, int64(250), report.Items[0].DurationMs)
+}
+
+func TestBuildSearchRunReport(t *testing.T) {
+	processed := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	report := BuildSearchRunReport(&models.CrossSeedSearchRun{
+		ID:         3,
+		InstanceID: 2,
+		Status:     models.CrossSeedSearchRunStatusSuccess,
+		StartedAt:  processed,
+		Results: []models.CrossSeedSearchResult{
+			{TorrentHash: "local1", TorrentName: "Local.One", IndexerName: "TrackerA", ReleaseTitle: "Remote.One", Added: true, BytesSaved: 100, ProcessedAt: processed},
+			{TorrentHash: "local2", TorrentName: "Local.Two", Message: "search failed: timeout", ProcessedAt: processed},
+			{TorrentHash: "local3", TorrentName: "Local.Three", Message: "no matches returned"},
+		},
+	})
+
+	require.NotNil(t, report)
+	assert.Equal(t, RunReportKindSearch, report.Kind)
+	assert.Zero(t, report.DurationMs, "running runs have no duration")
+	require.Len(t, report.Items, 3)
+
+	assert.Equal(t, "Remote.One", report.Items[0].Title)
+	assert.Equal(t, "local1", report.Items[0].MatchedHash)
+	assert.Equal(t, 2, report.Items[0].InstanceID)
+	assert.Equal(t, RunReportDecisionAdded, report.Items[0].Decision)
+	assert.Equal(t, "Local.Two", report.Items[1].Title)
+	assert.Equal(t, RunReportDecisionFailed, report.Items[1].Decision)
+	assert.Equal(t, RunReportDecisionSkipped, report.Items[2].Decision)
+	assert.Nil(t, report.Items[2].ProcessedAt)
+	assert.Equal(t, int64(100), report.Summary.BytesSaved)
+}
+
+func TestRunReport_WriteCSV(t *testing.T) {
+	report := &RunReport{
+		Items: []RunReportItem{
+			{Title: "Release, with comma", Decision: RunReportDecisionAdded, IndexerName: "TrackerA", InstanceID: 1, MatchedHash: "abc", BytesSaved: 42, DurationMs: 7},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, runReportCSVHeader, records[0])
+	assert.Equal(t, "Release, with comma", records[1][0])
+	assert.Equal(t, "1", records[1][5])
+	assert.Equal(t, "42", records[1][9])
+	assert.Equal(t, "7", records[1][10])
+}
+
+func TestSendRunReportWebhook(t *testing.T) {
+	var received runReportWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	report := &RunReport{
+		Kind:    RunReportKindAutomation,
+		RunID:   9,
+		Status:  "success",
+		Summary: RunReportSummary{Added: 2, BytesSaved: 1024},
+		Items:   []RunReportItem{{Title: "x"}},
+	}
+	require.NoError(t, sendRunReportWebhook(context.Background(), server.Client(), server.URL, report))
+
+	assert.Equal(t, "cross_seed.run_completed", received.Event)
+	assert.Equal(t, int64(9), received.RunID)
+	assert.Equal(t, 2, received.Summary.Added)
+	assert.Equal(t, int64(1024), received.Summary.BytesSaved)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	require.Error(t, sendRunReportWebhook(context.Background(), failing.Client(), failing.URL, report))
+}
+
+func TestNormalizeRunReportWebhookURL(t *testing.T) {
+	settings := &models.CrossSeedAutomationSettings{RunReportWebhookURL: "  https://hooks.example/x  "}
+	require.NoError(t, normalizeRunReportWebhookURL(settings))
+	assert.Equal(t, "https://hooks.example/x", settings.RunReportWebhookURL)
+
+	settings.RunReportWebhookURL = ""
+	require.NoError(t, normalizeRunReportWebhookURL(settings))
+
+	for _, invalid := range []string{"ftp://hooks.example/x", "not a url", "https://"} {
+		settings.RunReportWebhookURL = invalid
+		err := normalizeRunReportWebhookURL(settings)
+		require.Error(t, err, invalid)
+		assert.True(t, errors.Is(err, ErrInvalidRequest))
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/singleflight"
 
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/externalprograms"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/pkg/timeouts"
@@ -274,6 +275,21 @@ type Service struct {
 	// Per-instance completion settings
 	completionStore *models.InstanceCrossSeedCompletionStore
 
+	// eventBus receives successful injections; nil discards them.
+	eventBus *events.Bus
+
+	// Per-indexer snatch budgets. budgetPending holds in-flight reservations
+	// so concurrent adds cannot overshoot a cap before usage is persisted.
+	budgetStore   snatchBudgetProvider
+	budgetMu      sync.Mutex
+	budgetPending map[int]*models.CrossSeedIndexerUsage
+
+	// Blocklist of releases that must never be cross-seeded. blocklistMatcher caches
+	// compiled entries and is reset whenever the blocklist changes.
+	blocklistStore   blocklistProvider
+	blocklistMu      sync.Mutex
+	blocklistMatcher *blocklistMatcher
+
 	// recoverErroredTorrentsEnabled controls whether to attempt recovery of errored/missingFiles
 	// torrents before candidate selection. When false (default), errored torrents are simply
 	// excluded from matching. Set at startup via config.
@@ -339,6 +355,8 @@ func NewService(
 	externalProgramStore *models.ExternalProgramStore,
 	completionStore *models.InstanceCrossSeedCompletionStore,
 	trackerCustomizationStore *models.TrackerCustomizationStore,
+	budgetStore *models.CrossSeedBudgetStore,
+	blocklistStore *models.CrossSeedBlocklistStore,
 	recoverErroredTorrents bool,
 ) *Service {
 	searchCache := ttlcache.New(ttlcache.Options[string, []TorrentSearchResult]{}.
@@ -380,6 +398,12 @@ func NewService(
 		recheckResumeCtx:              recheckCtx,
 		recheckResumeCancel:           recheckCancel,
 	}
+	if budgetStore != nil {
+		svc.budgetStore = budgetStore
+	}
+	if blocklistStore != nil {
+		svc.blocklistStore = blocklistStore
+	}
 
 	// Start the single worker goroutine for processing recheck resumes
 	go svc.recheckResumeWorker()
@@ -659,6 +683,8 @@ type AutomationStatus struct {
 	LastRun   *models.CrossSeedRun                `json:"lastRun,omitempty"`
 	NextRunAt *time.Time                          `json:"nextRunAt,omitempty"`
 	Running   bool                                `json:"running"`
+	// IndexerBudgets reports per-indexer snatch budget consumption over the last 24h.
+	IndexerBudgets []IndexerBudgetStatus `json:"indexerBudgets,omitempty"`
 }
 
 // GetAutomationSettings returns the persisted automation configuration.
@@ -699,6 +725,9 @@ func (s *Service) UpdateAutomationSettings(ctx context.Context, settings *models
 
 	// Validate and normalize settings before checking store
 	s.validateAndNormalizeSettings(settings)
+	if err := normalizeRunReportWebhookURL(settings); err != nil {
+		return nil, err
+	}
 
 	if s.automationStore == nil {
 		return nil, errors.New("automation storage not configured")
@@ -1035,6 +1064,7 @@ func (s *Service) RunAutomation(ctx context.Context, opts AutomationRunOptions)
 	}
 
 	finalRun, execErr := s.executeAutomationRun(runCtx, storedRun, settings, opts)
+	s.notifyRunReport(BuildAutomationRunReport(finalRun))
 	if execErr != nil {
 		return finalRun, execErr
 	}
@@ -1238,6 +1268,13 @@ func (s *Service) GetAutomationStatus(ctx context.Context) (*AutomationStatus, e
 		}
 	}
 
+	budgets, err := s.indexerBudgetStatuses(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load cross-seed indexer budgets for status")
+	} else {
+		status.IndexerBudgets = budgets
+	}
+
 	return status, nil
 }
 
@@ -1795,7 +1832,14 @@ func (s *Service) executeAutomationRun(ctx context.Context, run *models.CrossSee
 			continue
 		}
 
+		itemStart := time.Now()
+		firstResult := len(run.Results)
 		status, infoHash, procErr := s.processAutomationCandidate(ctx, run, settings, autoCtx, result, opts, indexerInfo)
+		itemDuration := time.Since(itemStart).Milliseconds()
+		for i := firstResult; i < len(run.Results); i++ {
+			run.Results[i].ReleaseTitle = result.Title
+			run.Results[i].DurationMs = itemDuration
+		}
 		if procErr != nil {
 			if runErr == nil {
 				runErr = procErr
@@ -1844,6 +1888,7 @@ func (s *Service) executeAutomationRun(ctx context.Context, run *models.CrossSee
 			log.Debug().Err(pruneErr).Msg("Failed to prune cross-seed feed cache")
 		}
 	}
+	s.pruneIndexerUsage(ctx)
 
 	return run, runErr
 }
@@ -1854,6 +1899,18 @@ func (s *Service) processAutomationCandidate(ctx context.Context, run *models.Cr
 		sourceIndexer = resolved
 	}
 
+	if entry := s.checkBlocklist(ctx, result.Title, result.InfoHashV1, result.IndexerID); entry != nil {
+		run.TorrentsSkipped++
+		run.Results = append(run.Results, models.CrossSeedRunResult{
+			InstanceName: result.Indexer,
+			IndexerName:  result.Indexer,
+			Success:      false,
+			Status:       blocklistSkipStatus,
+			Message:      blocklistMessage(entry, result.Title),
+		})
+		return models.CrossSeedFeedItemStatusProcessed, nil, nil
+	}
+
 	findReq := &FindCandidatesRequest{
 		TorrentName:            result.Title,
 		SourceIndexer:          sourceIndexer,
@@ -2011,18 +2068,47 @@ func (s *Service) processAutomationCandidate(ctx context.Context, run *models.Cr
 		}
 	}
 
-	torrentBytes, err := s.downloadTorrent(ctx, jackett.TorrentDownloadRequest{
+	torrentBytes, err := s.snatchTorrent(ctx, jackett.TorrentDownloadRequest{
 		IndexerID:   result.IndexerID,
 		DownloadURL: result.DownloadURL,
 		GUID:        result.GUID,
 		Title:       result.Title,
 		Size:        result.Size,
-	})
+	}, result.PublishDate)
 	if err != nil {
+		if isBudgetError(err) {
+			// Leave the feed item unprocessed so it is retried once the budget allows it.
+			run.TorrentsSkipped++
+			run.Results = append(run.Results, models.CrossSeedRunResult{
+				InstanceName: result.Indexer,
+				IndexerName:  result.Indexer,
+				Success:      false,
+				Status:       budgetSkipStatus,
+				Message:      err.Error(),
+			})
+			return models.CrossSeedFeedItemStatusSkipped, nil, nil
+		}
 		run.TorrentsFailed++
 		return models.CrossSeedFeedItemStatusFailed, nil, fmt.Errorf("download torrent: %w", err)
 	}
 
+	// Feeds without infohashes can only be checked against infohash entries once the torrent is downloaded.
+	if result.InfoHashV1 == "" {
+		if _, hash, parseErr := ParseTorrentName(torrentBytes); parseErr == nil {
+			if entry := s.checkBlocklist(ctx, result.Title, hash, result.IndexerID); entry != nil {
+				run.TorrentsSkipped++
+				run.Results = append(run.Results, models.CrossSeedRunResult{
+					InstanceName: result.Indexer,
+					IndexerName:  result.Indexer,
+					Success:      false,
+					Status:       blocklistSkipStatus,
+					Message:      blocklistMessage(entry, result.Title),
+				})
+				return models.CrossSeedFeedItemStatusProcessed, &hash, nil
+			}
+		}
+	}
+
 	encodedTorrent := base64.StdEncoding.EncodeToString(torrentBytes)
 	startPaused := settings.StartPaused
 
@@ -2034,6 +2120,7 @@ func (s *Service) processAutomationCandidate(ctx context.Context, run *models.Cr
 		InheritSourceTags:            settings.InheritSourceTags,
 		SkipIfExists:                 &skipIfExists,
 		IndexerName:                  sourceIndexer,
+		IndexerID:                    result.IndexerID,
 		FindIndividualEpisodes:       settings.FindIndividualEpisodes,
 		SizeMismatchTolerancePercent: settings.SizeMismatchTolerancePercent,
 		SkipAutoResume:               settings.SkipAutoResumeRSS,
@@ -2094,6 +2181,9 @@ func (s *Service) processAutomationCandidate(ctx context.Context, run *models.Cr
 			mapped.MatchedTorrentHash = &hash
 			mapped.MatchedTorrentName = &name
 		}
+		if instanceResult.Success && resp.TorrentInfo != nil {
+			mapped.BytesSaved = resp.TorrentInfo.Size
+		}
 
 		run.Results = append(run.Results, mapped)
 
@@ -2612,12 +2702,31 @@ func (s *Service) CrossSeed(ctx context.Context, req *CrossSeedRequest) (*CrossS
 		}
 	}
 
+	budgetIndexerID := req.IndexerID
+	if budgetIndexerID <= 0 {
+		budgetIndexerID = s.resolveBudgetIndexerID(ctx, req.IndexerName)
+	}
+
 	// Process each instance with matching candidates
 	for _, candidate := range candidatesResp.Candidates {
+		release, budgetErr := s.reserveIndexerBudget(ctx, budgetIndexerID, models.CrossSeedUsageInjection, time.Time{})
+		if budgetErr != nil {
+			response.Results = append(response.Results, InstanceCrossSeedResult{
+				InstanceID:   candidate.InstanceID,
+				InstanceName: candidate.InstanceName,
+				Success:      false,
+				Status:       budgetSkipStatus,
+				Message:      budgetErr.Error(),
+			})
+			continue
+		}
+
 		result := s.processCrossSeedCandidate(ctx, candidate, torrentBytes, torrentHash, torrentName, req, sourceRelease, sourceFiles, torrentInfo)
+		release(result.Success)
 		response.Results = append(response.Results, result)
 		if result.Success {
 			response.Success = true
+			s.publishInjected(result, torrentName, req.IndexerName)
 		}
 	}
 
@@ -5200,13 +5309,13 @@ func (s *Service) ApplyTorrentSearchResults(ctx context.Context, instanceID int,
 				title = cachedResult.Title
 			}
 
-			torrentBytes, err := s.downloadTorrent(ctx, jackett.TorrentDownloadRequest{
+			torrentBytes, err := s.snatchTorrent(ctx, jackett.TorrentDownloadRequest{
 				IndexerID:   cachedResult.IndexerID,
 				DownloadURL: cachedResult.DownloadURL,
 				GUID:        cachedResult.GUID,
 				Title:       cachedResult.Title,
 				Size:        cachedResult.Size,
-			})
+			}, parsePublishDate(cachedResult.PublishDate))
 			if err != nil {
 				resultChan <- selectionResult{idx, TorrentSearchAddResult{
 					Title:   title,
@@ -5263,6 +5372,7 @@ func (s *Service) ApplyTorrentSearchResults(ctx context.Context, instanceID int,
 				Tags:                         applyTags,
 				InheritSourceTags:            inheritSourceTags,
 				IndexerName:                  indexerName,
+				IndexerID:                    cachedResult.IndexerID,
 				FindIndividualEpisodes:       req.FindIndividualEpisodes,
 				SizeMismatchTolerancePercent: sizeTolerance,
 				SkipAutoResume:               skipAutoResume,
@@ -5466,6 +5576,11 @@ func (s *Service) finalizeSearchRun(state *searchRunState, canceled bool) {
 		log.Warn().Err(err).Msg("failed to persist search run state")
 	}
 
+	s.searchMu.Lock()
+	report := BuildSearchRunReport(state.run)
+	s.searchMu.Unlock()
+	s.notifyRunReport(report)
+
 	s.searchMu.Lock()
 	if s.searchState == state {
 		s.searchState = nil
@@ -6093,8 +6208,26 @@ func (s *Service) processSearchCandidate(ctx context.Context, state *searchRunSt
 	indexerFails := make(map[int]int) // indexerID -> count of fails
 
 	for _, match := range searchResp.Results {
+		if entry := s.checkBlocklist(ctx, match.Title, "", match.IndexerID); entry != nil {
+			s.searchMu.Lock()
+			state.run.TorrentsSkipped++
+			s.searchMu.Unlock()
+			s.appendSearchResult(state, models.CrossSeedSearchResult{
+				TorrentHash:  torrent.Hash,
+				TorrentName:  torrent.Name,
+				IndexerName:  match.Indexer,
+				ReleaseTitle: match.Title,
+				Added:        false,
+				Message:      blocklistMessage(entry, match.Title),
+				ProcessedAt:  processedAt,
+			})
+			continue
+		}
+
+		attemptStart := time.Now()
 		attemptResult, err := s.executeCrossSeedSearchAttempt(ctx, state, torrent, match, processedAt)
 		if attemptResult != nil {
+			attemptResult.DurationMs = time.Since(attemptStart).Milliseconds()
 			if attemptResult.Added {
 				s.searchMu.Lock()
 				state.run.TorrentsAdded++
@@ -6327,18 +6460,30 @@ func (s *Service) executeCrossSeedSearchAttempt(ctx context.Context, state *sear
 		ProcessedAt:  processedAt,
 	}
 
-	data, err := s.downloadTorrent(ctx, jackett.TorrentDownloadRequest{
+	data, err := s.snatchTorrent(ctx, jackett.TorrentDownloadRequest{
 		IndexerID:   match.IndexerID,
 		DownloadURL: match.DownloadURL,
 		GUID:        match.GUID,
 		Title:       match.Title,
 		Size:        match.Size,
-	})
+	}, parsePublishDate(match.PublishDate))
 	if err != nil {
+		if isBudgetError(err) {
+			result.Message = "skipped: " + err.Error()
+			return result, nil
+		}
 		result.Message = fmt.Sprintf("download failed: %v", err)
 		return result, fmt.Errorf("download failed: %w", err)
 	}
 
+	// Search results rarely carry infohashes, so infohash entries are checked after download.
+	if _, hash, parseErr := ParseTorrentName(data); parseErr == nil {
+		if entry := s.checkBlocklist(ctx, match.Title, hash, match.IndexerID); entry != nil {
+			result.Message = blocklistMessage(entry, match.Title)
+			return result, nil
+		}
+	}
+
 	encoded := base64.StdEncoding.EncodeToString(data)
 	startPaused := state.opts.StartPaused
 	skipIfExists := true
@@ -6354,6 +6499,7 @@ func (s *Service) executeCrossSeedSearchAttempt(ctx context.Context, state *sear
 		InheritSourceTags:            state.opts.InheritSourceTags,
 		Category:                     "",
 		IndexerName:                  match.Indexer,
+		IndexerID:                    match.IndexerID,
 		FindIndividualEpisodes:       state.opts.FindIndividualEpisodes,
 		SkipIfExists:                 &skipIfExists,
 		SizeMismatchTolerancePercent: sizeTolerance,
@@ -6379,6 +6525,7 @@ func (s *Service) executeCrossSeedSearchAttempt(ctx context.Context, state *sear
 	if resp.Success {
 		result.Added = true
 		result.Message = "added via " + match.Indexer
+		result.BytesSaved = match.Size
 		return result, nil
 	}
 
@@ -6998,6 +7145,9 @@ func (s *Service) extractTrackerDomainsFromTorrent(torrent qbt.Torrent) []string
 }
 
 func (s *Service) appendSearchResult(state *searchRunState, result models.CrossSeedSearchResult) {
+	if result.DurationMs == 0 && !result.ProcessedAt.IsZero() {
+		result.DurationMs = time.Since(result.ProcessedAt).Milliseconds()
+	}
 	s.searchMu.Lock()
 	state.run.Results = append(state.run.Results, result)
 	// Only track successfully added torrents in recentResults so the UI
@@ -7933,6 +8083,28 @@ func (s *Service) CheckWebhook(ctx context.Context, req *WebhookCheckRequest) (*
 		recommendation = "download"
 	}
 
+	// A 200 tells autobrr to snatch immediately, so it consumes the indexer's download budget.
+	if canCrossSeed {
+		if indexerID := s.resolveBudgetIndexerID(ctx, req.IndexerName); indexerID > 0 {
+			release, budgetErr := s.reserveIndexerBudget(ctx, indexerID, models.CrossSeedUsageDownload, time.Now())
+			if budgetErr != nil {
+				log.Info().
+					Str("source", "cross-seed.webhook").
+					Str("torrentName", req.TorrentName).
+					Str("indexer", req.IndexerName).
+					Err(budgetErr).
+					Msg("Webhook check skipped by indexer budget")
+				return &WebhookCheckResponse{
+					CanCrossSeed:   false,
+					Matches:        nil,
+					Recommendation: "skip",
+					SkipReason:     budgetErr.Error(),
+				}, nil
+			}
+			release(true)
+		}
+	}
+
 	log.Debug().
 		Str("source", "cross-seed.webhook").
 		Ints("requestedInstanceIds", requestedInstanceIDs).
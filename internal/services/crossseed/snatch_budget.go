@@ -0,0 +1,299 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/jackett"
+)
+
+const (
+	// snatchBudgetWindow is the rolling window that daily budgets are evaluated against.
+	snatchBudgetWindow = 24 * time.Hour
+	// snatchUsageRetention controls how long usage events are kept before pruning.
+	snatchUsageRetention = 2 * snatchBudgetWindow
+	// budgetSkipStatus is the per-instance result status reported when a budget blocks an add.
+	budgetSkipStatus = "skipped"
+)
+
+// ErrSnatchBudgetExceeded indicates an indexer's daily download or injection cap has been reached.
+var ErrSnatchBudgetExceeded = errors.New("cross-seed indexer budget exceeded")
+
+// ErrReleaseTooNew indicates a release is younger than the indexer's minimum snatch age.
+var ErrReleaseTooNew = errors.New("release younger than indexer minimum age")
+
+type snatchBudgetProvider interface {
+	List(ctx context.Context) ([]*models.CrossSeedIndexerBudget, error)
+	Upsert(ctx context.Context, budget *models.CrossSeedIndexerBudget) (*models.CrossSeedIndexerBudget, error)
+	Delete(ctx context.Context, indexerID int) error
+	RecordUsage(ctx context.Context, indexerID int, kind models.CrossSeedUsageKind, at time.Time) error
+	UsageSince(ctx context.Context, since time.Time) (map[int]models.CrossSeedIndexerUsage, error)
+	PruneUsage(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+// IndexerBudgetStatus reports how much of an indexer's snatch budget has been consumed
+// within the rolling 24h window.
+type IndexerBudgetStatus struct {
+	IndexerID            int    `json:"indexerId"`
+	IndexerName          string `json:"indexerName"`
+	MaxDownloadsPerDay   int    `json:"maxDownloadsPerDay"`
+	DownloadsUsed        int    `json:"downloadsUsed"`
+	MaxInjectionsPerDay  int    `json:"maxInjectionsPerDay"`
+	InjectionsUsed       int    `json:"injectionsUsed"`
+	MinReleaseAgeMinutes int    `json:"minReleaseAgeMinutes"`
+	Exhausted            bool   `json:"exhausted"`
+}
+
+// ListIndexerBudgets returns all configured per-indexer snatch budgets.
+func (s *Service) ListIndexerBudgets(ctx context.Context) ([]*models.CrossSeedIndexerBudget, error) {
+	if s.budgetStore == nil {
+		return []*models.CrossSeedIndexerBudget{}, nil
+	}
+	budgets, err := s.budgetStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list indexer budgets: %w", err)
+	}
+	if budgets == nil {
+		budgets = []*models.CrossSeedIndexerBudget{}
+	}
+	return budgets, nil
+}
+
+// UpsertIndexerBudget creates or replaces the snatch budget for an indexer.
+func (s *Service) UpsertIndexerBudget(ctx context.Context, budget *models.CrossSeedIndexerBudget) (*models.CrossSeedIndexerBudget, error) {
+	if s.budgetStore == nil {
+		return nil, errors.New("indexer budgets not configured")
+	}
+	if budget == nil || budget.IndexerID <= 0 {
+		return nil, fmt.Errorf("%w: indexerId must be a positive integer", ErrInvalidRequest)
+	}
+	if budget.MaxDownloadsPerDay < 0 || budget.MaxInjectionsPerDay < 0 || budget.MinReleaseAgeMinutes < 0 {
+		return nil, fmt.Errorf("%w: budget limits cannot be negative", ErrInvalidRequest)
+	}
+	return s.budgetStore.Upsert(ctx, budget)
+}
+
+// DeleteIndexerBudget removes the snatch budget for an indexer.
+func (s *Service) DeleteIndexerBudget(ctx context.Context, indexerID int) error {
+	if s.budgetStore == nil {
+		return errors.New("indexer budgets not configured")
+	}
+	return s.budgetStore.Delete(ctx, indexerID)
+}
+
+// indexerBudgetStatuses summarises budget consumption for GetAutomationStatus.
+func (s *Service) indexerBudgetStatuses(ctx context.Context) ([]IndexerBudgetStatus, error) {
+	if s.budgetStore == nil {
+		return nil, nil
+	}
+
+	budgets, err := s.budgetStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list indexer budgets: %w", err)
+	}
+	if len(budgets) == 0 {
+		return nil, nil
+	}
+
+	usage, err := s.budgetStore.UsageSince(ctx, time.Now().Add(-snatchBudgetWindow))
+	if err != nil {
+		return nil, fmt.Errorf("load indexer usage: %w", err)
+	}
+
+	statuses := make([]IndexerBudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		used := usage[budget.IndexerID]
+		statuses = append(statuses, IndexerBudgetStatus{
+			IndexerID:            budget.IndexerID,
+			IndexerName:          budget.IndexerName,
+			MaxDownloadsPerDay:   budget.MaxDownloadsPerDay,
+			DownloadsUsed:        used.Downloads,
+			MaxInjectionsPerDay:  budget.MaxInjectionsPerDay,
+			InjectionsUsed:       used.Injections,
+			MinReleaseAgeMinutes: budget.MinReleaseAgeMinutes,
+			Exhausted:            budgetExhausted(budget.MaxDownloadsPerDay, used.Downloads) || budgetExhausted(budget.MaxInjectionsPerDay, used.Injections),
+		})
+	}
+
+	return statuses, nil
+}
+
+// resolveBudgetIndexerID maps an indexer name to its ID using configured budgets.
+// Only indexers with a budget are resolvable, which is all enforcement needs.
+func (s *Service) resolveBudgetIndexerID(ctx context.Context, indexerName string) int {
+	indexerName = strings.TrimSpace(indexerName)
+	if s.budgetStore == nil || indexerName == "" {
+		return 0
+	}
+
+	budgets, err := s.budgetStore.List(ctx)
+	if err != nil {
+		log.Debug().Err(err).Str("indexer", indexerName).Msg("[CROSSSEED-BUDGET] Failed to load budgets for indexer lookup")
+		return 0
+	}
+
+	normalized := s.normalizeIndexerName(indexerName)
+	for _, budget := range budgets {
+		if strings.EqualFold(budget.IndexerName, indexerName) || s.normalizeIndexerName(budget.IndexerName) == normalized {
+			return budget.IndexerID
+		}
+	}
+	return 0
+}
+
+// loadIndexerBudget returns the budget and current usage for an indexer.
+// A nil budget means the indexer is unrestricted.
+func (s *Service) loadIndexerBudget(ctx context.Context, indexerID int) (*models.CrossSeedIndexerBudget, models.CrossSeedIndexerUsage, error) {
+	if s.budgetStore == nil || indexerID <= 0 {
+		return nil, models.CrossSeedIndexerUsage{}, nil
+	}
+
+	budgets, err := s.budgetStore.List(ctx)
+	if err != nil {
+		return nil, models.CrossSeedIndexerUsage{}, fmt.Errorf("list indexer budgets: %w", err)
+	}
+
+	var budget *models.CrossSeedIndexerBudget
+	for _, b := range budgets {
+		if b.IndexerID == indexerID {
+			budget = b
+			break
+		}
+	}
+	if budget.IsUnlimited() {
+		return nil, models.CrossSeedIndexerUsage{}, nil
+	}
+
+	usage, err := s.budgetStore.UsageSince(ctx, time.Now().Add(-snatchBudgetWindow))
+	if err != nil {
+		return nil, models.CrossSeedIndexerUsage{}, fmt.Errorf("load indexer usage: %w", err)
+	}
+
+	return budget, usage[indexerID], nil
+}
+
+// reserveIndexerBudget checks the budget for an indexer and reserves one slot of the given kind.
+// Reservations are held in memory until release is called so concurrent adds cannot overshoot the cap.
+// Download reservations also require the injection budget to have room and the release to be old enough.
+// release must be called exactly once; only successful operations are persisted to the usage ledger.
+func (s *Service) reserveIndexerBudget(ctx context.Context, indexerID int, kind models.CrossSeedUsageKind, publishedAt time.Time) (func(success bool), error) {
+	noop := func(bool) {}
+	if s.budgetStore == nil || indexerID <= 0 {
+		return noop, nil
+	}
+
+	s.budgetMu.Lock()
+	defer s.budgetMu.Unlock()
+
+	budget, usage, err := s.loadIndexerBudget(ctx, indexerID)
+	if err != nil {
+		// Budget lookups should never block cross-seeding outright.
+		log.Warn().Err(err).Int("indexerID", indexerID).Msg("[CROSSSEED-BUDGET] Failed to load indexer budget, allowing request")
+		return noop, nil
+	}
+	if budget == nil {
+		return noop, nil
+	}
+
+	if s.budgetPending == nil {
+		s.budgetPending = make(map[int]*models.CrossSeedIndexerUsage)
+	}
+	pending := s.budgetPending[indexerID]
+	if pending == nil {
+		pending = &models.CrossSeedIndexerUsage{}
+		s.budgetPending[indexerID] = pending
+	}
+
+	if budgetExhausted(budget.MaxInjectionsPerDay, usage.Injections+pending.Injections) {
+		return nil, fmt.Errorf("%w: %s reached %d injections in the last 24h", ErrSnatchBudgetExceeded, budget.IndexerName, budget.MaxInjectionsPerDay)
+	}
+
+	if kind == models.CrossSeedUsageDownload {
+		if budget.MinReleaseAgeMinutes > 0 && !publishedAt.IsZero() {
+			minAge := time.Duration(budget.MinReleaseAgeMinutes) * time.Minute
+			if age := time.Since(publishedAt); age < minAge {
+				return nil, fmt.Errorf("%w: %s requires releases to be %s old (current age %s)", ErrReleaseTooNew, budget.IndexerName, minAge, age.Truncate(time.Minute))
+			}
+		}
+		if budgetExhausted(budget.MaxDownloadsPerDay, usage.Downloads+pending.Downloads) {
+			return nil, fmt.Errorf("%w: %s reached %d downloads in the last 24h", ErrSnatchBudgetExceeded, budget.IndexerName, budget.MaxDownloadsPerDay)
+		}
+		pending.Downloads++
+	} else {
+		pending.Injections++
+	}
+
+	released := false
+	return func(success bool) {
+		s.budgetMu.Lock()
+		defer s.budgetMu.Unlock()
+		if released {
+			return
+		}
+		released = true
+
+		if kind == models.CrossSeedUsageDownload {
+			pending.Downloads--
+		} else {
+			pending.Injections--
+		}
+
+		if !success {
+			return
+		}
+		if err := s.budgetStore.RecordUsage(context.WithoutCancel(ctx), indexerID, kind, time.Now()); err != nil {
+			log.Warn().Err(err).Int("indexerID", indexerID).Str("kind", string(kind)).Msg("[CROSSSEED-BUDGET] Failed to record indexer usage")
+		}
+	}, nil
+}
+
+// snatchTorrent downloads a .torrent from an indexer while enforcing the indexer's snatch budget.
+func (s *Service) snatchTorrent(ctx context.Context, req jackett.TorrentDownloadRequest, publishedAt time.Time) ([]byte, error) {
+	release, err := s.reserveIndexerBudget(ctx, req.IndexerID, models.CrossSeedUsageDownload, publishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.downloadTorrent(ctx, req)
+	release(err == nil)
+	return data, err
+}
+
+// pruneIndexerUsage removes usage events that no longer affect any budget window.
+func (s *Service) pruneIndexerUsage(ctx context.Context) {
+	if s.budgetStore == nil {
+		return
+	}
+	if _, err := s.budgetStore.PruneUsage(ctx, time.Now().Add(-snatchUsageRetention)); err != nil {
+		log.Debug().Err(err).Msg("[CROSSSEED-BUDGET] Failed to prune indexer usage")
+	}
+}
+
+func isBudgetError(err error) bool {
+	return errors.Is(err, ErrSnatchBudgetExceeded) || errors.Is(err, ErrReleaseTooNew)
+}
+
+func budgetExhausted(limit, used int) bool {
+	return limit > 0 && used >= limit
+}
+
+// parsePublishDate parses the RFC3339 publish date carried on search results.
+func parsePublishDate(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
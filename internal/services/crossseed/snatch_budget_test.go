@@ -0,0 +1,169 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package crossseed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type fakeBudgetStore struct {
+	mu      sync.Mutex
+	budgets []*models.CrossSeedIndexerBudget
+	usage   map[int]models.CrossSeedIndexerUsage
+	listErr error
+}
+
+func (f *fakeBudgetStore) List(context.Context) ([]*models.CrossSeedIndexerBudget, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	return f.budgets, nil
+}
+
+func (f *fakeBudgetStore) Upsert(_ context.Context, budget *models.CrossSeedIndexerBudget) (*models.CrossSeedIndexerBudget, error) {
+	f.budgets = append(f.budgets, budget)
+	return budget, nil
+}
+
+func (f *fakeBudgetStore) Delete(context.Context, int) error {
+	return nil
+}
+
+func (f *fakeBudgetStore) RecordUsage(_ context.Context, indexerID int, kind models.CrossSeedUsageKind, _ time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.usage == nil {
+		f.usage = make(map[int]models.CrossSeedIndexerUsage)
+	}
+	entry := f.usage[indexerID]
+	if kind == models.CrossSeedUsageDownload {
+		entry.Downloads++
+	} else {
+		entry.Injections++
+	}
+	f.usage[indexerID] = entry
+	return nil
+}
+
+func (f *fakeBudgetStore) UsageSince(context.Context, time.Time) (map[int]models.CrossSeedIndexerUsage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[int]models.CrossSeedIndexerUsage, len(f.usage))
+	for id, u := range f.usage {
+		out[id] = u
+	}
+	return out, nil
+}
+
+func (f *fakeBudgetStore) PruneUsage(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestReserveIndexerBudget_DownloadCap(t *testing.T) {
+	store := &fakeBudgetStore{budgets: []*models.CrossSeedIndexerBudget{{IndexerID: 1, IndexerName: "TrackerA", MaxDownloadsPerDay: 2}}}
+	s := &Service{budgetStore: store}
+	ctx := context.Background()
+
+	first, err := s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Time{})
+	require.NoError(t, err)
+	second, err := s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Time{})
+	require.NoError(t, err)
+
+	// Two reservations are pending, so a third must be refused even before anything is recorded.
+	_, err = s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Time{})
+	require.ErrorIs(t, err, ErrSnatchBudgetExceeded)
+
+	// A failed download frees its slot without consuming budget.
+	first(false)
+	third, err := s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Time{})
+	require.NoError(t, err)
+
+	second(true)
+	third(true)
+	third(true) // releasing twice is a no-op
+	assert.Equal(t, 2, store.usage[1].Downloads)
+
+	_, err = s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Time{})
+	require.ErrorIs(t, err, ErrSnatchBudgetExceeded)
+
+	// Other indexers are unaffected.
+	release, err := s.reserveIndexerBudget(ctx, 2, models.CrossSeedUsageDownload, time.Time{})
+	require.NoError(t, err)
+	release(true)
+}
+
+func TestReserveIndexerBudget_InjectionCapBlocksDownloads(t *testing.T) {
+	store := &fakeBudgetStore{
+		budgets: []*models.CrossSeedIndexerBudget{{IndexerID: 1, IndexerName: "TrackerA", MaxInjectionsPerDay: 1}},
+		usage:   map[int]models.CrossSeedIndexerUsage{1: {Injections: 1}},
+	}
+	s := &Service{budgetStore: store}
+
+	_, err := s.reserveIndexerBudget(context.Background(), 1, models.CrossSeedUsageInjection, time.Time{})
+	require.ErrorIs(t, err, ErrSnatchBudgetExceeded)
+
+	_, err = s.reserveIndexerBudget(context.Background(), 1, models.CrossSeedUsageDownload, time.Time{})
+	require.ErrorIs(t, err, ErrSnatchBudgetExceeded, "downloads are pointless once injections are exhausted")
+}
+
+func TestReserveIndexerBudget_MinReleaseAge(t *testing.T) {
+	store := &fakeBudgetStore{budgets: []*models.CrossSeedIndexerBudget{{IndexerID: 1, IndexerName: "TrackerA", MinReleaseAgeMinutes: 30}}}
+	s := &Service{budgetStore: store}
+	ctx := context.Background()
+
+	_, err := s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Now().Add(-5*time.Minute))
+	require.ErrorIs(t, err, ErrReleaseTooNew)
+	assert.True(t, isBudgetError(err))
+
+	release, err := s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	release(true)
+
+	// Unknown publish dates are not held back.
+	release, err = s.reserveIndexerBudget(ctx, 1, models.CrossSeedUsageDownload, time.Time{})
+	require.NoError(t, err)
+	release(false)
+}
+
+func TestReserveIndexerBudget_FailsOpenOnStoreError(t *testing.T) {
+	store := &fakeBudgetStore{listErr: errors.New("database locked")}
+	s := &Service{budgetStore: store}
+
+	release, err := s.reserveIndexerBudget(context.Background(), 1, models.CrossSeedUsageDownload, time.Time{})
+	require.NoError(t, err)
+	release(true)
+	assert.Empty(t, store.usage)
+}
+
+func TestResolveBudgetIndexerID(t *testing.T) {
+	store := &fakeBudgetStore{budgets: []*models.CrossSeedIndexerBudget{
+		{IndexerID: 7, IndexerName: "TrackerA (Prowlarr)", MaxDownloadsPerDay: 1},
+	}}
+	s := &Service{budgetStore: store}
+	ctx := context.Background()
+
+	assert.Equal(t, 7, s.resolveBudgetIndexerID(ctx, "TrackerA (Prowlarr)"))
+	assert.Equal(t, 7, s.resolveBudgetIndexerID(ctx, "trackera"))
+	assert.Equal(t, 0, s.resolveBudgetIndexerID(ctx, "TrackerB"))
+	assert.Equal(t, 0, s.resolveBudgetIndexerID(ctx, ""))
+}
+
+func TestUpsertIndexerBudget_RejectsNegativeLimits(t *testing.T) {
+	s := &Service{budgetStore: &fakeBudgetStore{}}
+
+	_, err := s.UpsertIndexerBudget(context.Background(), &models.CrossSeedIndexerBudget{IndexerID: 1, MaxDownloadsPerDay: -1})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = s.UpsertIndexerBudget(context.Background(), &models.CrossSeedIndexerBudget{IndexerID: 0})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+}
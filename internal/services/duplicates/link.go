@@ -0,0 +1,179 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package duplicates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+)
+
+// linkSuffix marks the temporary hardlinks created before replacing target files.
+const linkSuffix = ".quilink"
+
+// compareChunkSize is the read size used when comparing source and target files.
+const compareChunkSize = 1 << 20
+
+type filePair struct {
+	source string
+	target string
+	size   int64
+}
+
+// pairFiles matches every target file to a source file of the same size, first by
+// relative path, then by file name. Files are never paired by size alone, since
+// unrelated files of the same size would be replaced.
+func pairFiles(sourceSavePath string, sourceFiles qbt.TorrentFiles, targetSavePath string, targetFiles qbt.TorrentFiles) ([]filePair, error) {
+	used := make([]bool, len(sourceFiles))
+	match := func(accept func(source, target string) bool, targetName string, targetSize int64) int {
+		found := -1
+		for i, source := range sourceFiles {
+			if used[i] || source.Size != targetSize || !accept(source.Name, targetName) {
+				continue
+			}
+			if found >= 0 {
+				return -1
+			}
+			found = i
+		}
+		return found
+	}
+
+	strategies := []func(source, target string) bool{
+		func(source, target string) bool { return source == target },
+		func(source, target string) bool { return path.Base(source) == path.Base(target) },
+	}
+
+	matched := make([]int, len(targetFiles))
+	for i := range matched {
+		matched[i] = -1
+	}
+	for _, accept := range strategies {
+		for ti, target := range targetFiles {
+			if matched[ti] >= 0 {
+				continue
+			}
+			if si := match(accept, target.Name, target.Size); si >= 0 {
+				matched[ti] = si
+				used[si] = true
+			}
+		}
+	}
+
+	pairs := make([]filePair, 0, len(targetFiles))
+	for ti, target := range targetFiles {
+		si := matched[ti]
+		if si < 0 {
+			return nil, fmt.Errorf("no matching source file for %s", target.Name)
+		}
+		pairs = append(pairs, filePair{
+			source: filepath.Join(filepath.FromSlash(sourceSavePath), filepath.FromSlash(sourceFiles[si].Name)),
+			target: filepath.Join(filepath.FromSlash(targetSavePath), filepath.FromSlash(target.Name)),
+			size:   target.Size,
+		})
+	}
+	return pairs, nil
+}
+
+// linkFiles replaces each target with a hardlink to its source. Every target is
+// compared byte for byte with its source and every link is created next to its
+// target before any target is replaced, so a mismatch or a failure while linking
+// leaves the target files untouched. Targets already linked to their source are
+// skipped.
+func linkFiles(pairs []filePair) (*HardlinkResult, error) {
+	result := &HardlinkResult{}
+
+	var pending []filePair
+	for _, pair := range pairs {
+		sourceInfo, err := os.Stat(pair.source)
+		if err != nil {
+			return result, fmt.Errorf("source file: %w", err)
+		}
+		if !sourceInfo.Mode().IsRegular() || sourceInfo.Size() != pair.size {
+			return result, fmt.Errorf("source file %s does not match the expected size", pair.source)
+		}
+		targetInfo, err := os.Stat(pair.target)
+		if err != nil {
+			return result, fmt.Errorf("target file: %w", err)
+		}
+		if os.SameFile(sourceInfo, targetInfo) {
+			continue
+		}
+		same, err := sameContent(pair.source, pair.target)
+		if err != nil {
+			return result, fmt.Errorf("compare %s: %w", pair.target, err)
+		}
+		if !same {
+			return result, fmt.Errorf("target file %s differs from source %s", pair.target, pair.source)
+		}
+		pending = append(pending, pair)
+	}
+
+	var created []string
+	cleanup := func() {
+		for _, tmp := range created {
+			_ = os.Remove(tmp)
+		}
+	}
+	for _, pair := range pending {
+		tmp := pair.target + linkSuffix
+		if err := os.Link(pair.source, tmp); err != nil {
+			cleanup()
+			return result, fmt.Errorf("link %s: %w", pair.target, err)
+		}
+		created = append(created, tmp)
+	}
+
+	var errs []error
+	for i, pair := range pending {
+		if err := os.Rename(created[i], pair.target); err != nil {
+			_ = os.Remove(created[i])
+			errs = append(errs, fmt.Errorf("replace %s: %w", pair.target, err))
+			continue
+		}
+		result.FilesLinked++
+		result.BytesLinked += pair.size
+	}
+	return result, errors.Join(errs...)
+}
+
+// sameContent reports whether two files hold the same bytes.
+func sameContent(a, b string) (bool, error) {
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	bufA := make([]byte, compareChunkSize)
+	bufB := make([]byte, compareChunkSize)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		doneA := errors.Is(errA, io.EOF) || errors.Is(errA, io.ErrUnexpectedEOF)
+		doneB := errors.Is(errB, io.EOF) || errors.Is(errB, io.ErrUnexpectedEOF)
+		switch {
+		case errA != nil && !doneA:
+			return false, errA
+		case errB != nil && !doneB:
+			return false, errB
+		case doneA || doneB:
+			return doneA == doneB, nil
+		}
+	}
+}
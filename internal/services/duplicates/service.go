@@ -0,0 +1,640 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package duplicates reports torrents that hold the same content across all
+// instances, separating cross-seeds that share data from duplicates that occupy
+// separate disk space.
+package duplicates
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/moistari/rls"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+	"github.com/autobrr/qui/pkg/hardlink"
+	"github.com/autobrr/qui/pkg/releases"
+)
+
+// ErrNotInGroup is returned when an action targets a torrent that is not part of a group.
+var ErrNotInGroup = errors.New("torrent is not part of a duplicate group")
+
+// ErrSharedData is returned when deleting files that other torrents still use.
+var ErrSharedData = errors.New("torrent data is shared with other torrents")
+
+// ErrLocalAccessRequired is returned when an instance cannot access its torrents' data.
+var ErrLocalAccessRequired = errors.New("instance does not have local filesystem access")
+
+// ErrUnresolvedFiles is returned when the files of a torrent cannot be found on disk.
+var ErrUnresolvedFiles = errors.New("torrent files could not be resolved on disk")
+
+// Ways torrents are matched to each other.
+const (
+	MatchInfohash = "infohash"
+	MatchContent  = "content"
+	MatchRelease  = "release"
+)
+
+// Group kinds.
+const (
+	KindCrossSeed = "cross-seed"
+	KindDuplicate = "duplicate"
+)
+
+type instanceLister interface {
+	List(ctx context.Context) ([]*models.Instance, error)
+}
+
+type torrentSource interface {
+	GetCachedInstanceTorrents(ctx context.Context, instanceID int) ([]qbittorrent.CrossInstanceTorrentView, error)
+	GetTorrentFilesBatch(ctx context.Context, instanceID int, hashes []string) (map[string]qbt.TorrentFiles, error)
+	BulkAction(ctx context.Context, instanceID int, hashes []string, action string) error
+}
+
+// Member is one torrent in a group.
+type Member struct {
+	InstanceID   int    `json:"instanceId"`
+	InstanceName string `json:"instanceName"`
+	Hash         string `json:"hash"`
+	Name         string `json:"name"`
+	Size         int64  `json:"size"`
+	SavePath     string `json:"savePath"`
+	ContentPath  string `json:"contentPath"`
+	Category     string `json:"category"`
+	Tags         string `json:"tags"`
+	Tracker      string `json:"tracker"`
+	State        string `json:"state"`
+	// Storage numbers the copies of the data within the group; members with the same
+	// value share data on disk.
+	Storage int `json:"storage"`
+	// LocalAccess reports whether qui can read the member's files, which is required to
+	// convert it to a hardlinked cross-seed.
+	LocalAccess bool `json:"localAccess"`
+}
+
+// Group is a set of torrents holding the same content.
+type Group struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	MatchedBy []string `json:"matchedBy"`
+	Size      int64    `json:"size"`
+	Copies    int      `json:"copies"`
+	// ReclaimableBytes is the space freed by keeping a single copy of the data.
+	ReclaimableBytes int64    `json:"reclaimableBytes"`
+	Members          []Member `json:"members"`
+}
+
+// InstanceError records an instance that could not be included in the report.
+type InstanceError struct {
+	InstanceID   int    `json:"instanceId"`
+	InstanceName string `json:"instanceName"`
+	Error        string `json:"error"`
+}
+
+// Report is the fleet-wide duplicate report.
+type Report struct {
+	Groups           []Group         `json:"groups"`
+	DuplicateGroups  int             `json:"duplicateGroups"`
+	CrossSeedGroups  int             `json:"crossSeedGroups"`
+	ReclaimableBytes int64           `json:"reclaimableBytes"`
+	Errors           []InstanceError `json:"errors,omitempty"`
+	GeneratedAt      time.Time       `json:"generatedAt"`
+}
+
+// HardlinkResult is the outcome of converting a duplicate to a hardlinked cross-seed.
+type HardlinkResult struct {
+	FilesLinked int   `json:"filesLinked"`
+	BytesLinked int64 `json:"bytesLinked"`
+}
+
+// Service builds duplicate reports and applies actions to their members.
+type Service struct {
+	instances instanceLister
+	torrents  torrentSource
+	parser    *releases.Parser
+}
+
+// NewService creates a duplicate finder.
+func NewService(instanceStore *models.InstanceStore, syncManager *qbittorrent.SyncManager) *Service {
+	return &Service{
+		instances: instanceStore,
+		torrents:  syncManager,
+		parser:    releases.NewDefaultParser(),
+	}
+}
+
+type entry struct {
+	instance *models.Instance
+	torrent  qbittorrent.CrossInstanceTorrentView
+}
+
+// Report groups the completed torrents of every active instance by content identity.
+func (s *Service) Report(ctx context.Context) (*Report, error) {
+	instances, err := s.instances.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list instances: %w", err)
+	}
+
+	report := &Report{Groups: []Group{}, GeneratedAt: time.Now()}
+	var entries []entry
+	for _, instance := range instances {
+		if !instance.IsActive {
+			continue
+		}
+		torrents, err := s.torrents.GetCachedInstanceTorrents(ctx, instance.ID)
+		if err != nil {
+			log.Warn().Err(err).Int("instanceID", instance.ID).Msg("duplicates: failed to get cached torrents")
+			report.Errors = append(report.Errors, InstanceError{InstanceID: instance.ID, InstanceName: instance.Name, Error: err.Error()})
+			continue
+		}
+		for _, torrent := range torrents {
+			// Incomplete torrents are not yet occupying their full size on disk
+			if torrent.Progress < 1 || torrent.Size <= 0 {
+				continue
+			}
+			entries = append(entries, entry{instance: instance, torrent: torrent})
+		}
+	}
+
+	sets := newUnionFind(len(entries))
+	keyMembers := make(map[string][]int)
+	for i := range entries {
+		for _, key := range s.identityKeys(&entries[i].torrent.Torrent) {
+			if members := keyMembers[key]; len(members) > 0 {
+				sets.union(members[0], i)
+			}
+			keyMembers[key] = append(keyMembers[key], i)
+		}
+	}
+
+	components := make(map[int][]int)
+	for i := range entries {
+		root := sets.find(i)
+		components[root] = append(components[root], i)
+	}
+
+	matchedBy := make(map[int]map[string]struct{})
+	for key, members := range keyMembers {
+		if len(members) < 2 {
+			continue
+		}
+		root := sets.find(members[0])
+		if matchedBy[root] == nil {
+			matchedBy[root] = make(map[string]struct{})
+		}
+		matchedBy[root][key[:strings.IndexByte(key, ':')]] = struct{}{}
+	}
+
+	var grouped [][]int
+	for _, members := range components {
+		if len(members) > 1 {
+			grouped = append(grouped, members)
+		}
+	}
+	fileIDs := s.resolveFileIDs(ctx, entries, grouped)
+
+	for _, members := range grouped {
+		group := buildGroup(entries, members, fileIDs)
+		for kind := range matchedBy[sets.find(members[0])] {
+			group.MatchedBy = append(group.MatchedBy, kind)
+		}
+		slices.Sort(group.MatchedBy)
+
+		if group.Kind == KindDuplicate {
+			report.DuplicateGroups++
+		} else {
+			report.CrossSeedGroups++
+		}
+		report.ReclaimableBytes += group.ReclaimableBytes
+		report.Groups = append(report.Groups, group)
+	}
+
+	slices.SortFunc(report.Groups, func(a, b Group) int {
+		if c := cmp.Compare(b.ReclaimableBytes, a.ReclaimableBytes); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Size, a.Size); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return report, nil
+}
+
+// identityKeys returns the keys under which a torrent is matched. Content and release
+// keys include the size so that different editions of a release stay apart.
+func (s *Service) identityKeys(torrent *qbt.Torrent) []string {
+	var keys []string
+	seen := make(map[string]struct{}, 3)
+	for _, hash := range []string{torrent.Hash, torrent.InfohashV1, torrent.InfohashV2} {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if hash == "" {
+			continue
+		}
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+		keys = append(keys, MatchInfohash+":"+hash)
+	}
+
+	size := strconv.FormatInt(torrent.Size, 10)
+	if contentPath := normalizePath(torrent.ContentPath); contentPath != "" {
+		keys = append(keys, MatchContent+":"+size+":"+contentPath)
+	}
+	if release := releaseKey(s.parser.Parse(torrent.Name)); release != "" {
+		keys = append(keys, MatchRelease+":"+size+":"+release)
+	}
+	return keys
+}
+
+// releaseKey identifies a release by its parsed metadata, or returns an empty string
+// when the name carries too little to tell releases apart.
+func releaseKey(release *rls.Release) string {
+	title := strings.ToLower(strings.TrimSpace(release.Title))
+	if title == "" || release.Group == "" {
+		return ""
+	}
+	return strings.Join([]string{
+		release.Type.String(),
+		strings.ToLower(release.Artist),
+		title,
+		strings.ToLower(release.Subtitle),
+		strconv.Itoa(release.Year),
+		strconv.Itoa(release.Month),
+		strconv.Itoa(release.Day),
+		strconv.Itoa(release.Series),
+		strconv.Itoa(release.Episode),
+		strings.ToLower(release.Version),
+		strings.ToLower(release.Resolution),
+		strings.ToLower(release.Source),
+		strings.ToLower(strings.Join(release.Codec, ",")),
+		strings.ToLower(strings.Join(release.Edition, ",")),
+		strings.ToLower(release.Group),
+	}, "|")
+}
+
+// resolveFileIDs returns the physical file identity of the largest file of each grouped
+// torrent on instances with local filesystem access, keyed by entry index. Torrents
+// whose largest files are the same file share their data through hardlinks.
+func (s *Service) resolveFileIDs(ctx context.Context, entries []entry, groups [][]int) map[int]string {
+	byInstance := make(map[int][]int)
+	for _, members := range groups {
+		for _, i := range members {
+			if entries[i].instance.HasLocalFilesystemAccess {
+				byInstance[entries[i].instance.ID] = append(byInstance[entries[i].instance.ID], i)
+			}
+		}
+	}
+
+	fileIDs := make(map[int]string)
+	for instanceID, indexes := range byInstance {
+		hashes := make([]string, 0, len(indexes))
+		for _, i := range indexes {
+			hashes = append(hashes, entries[i].torrent.Hash)
+		}
+		filesByHash, err := s.torrents.GetTorrentFilesBatch(ctx, instanceID, hashes)
+		if err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Msg("duplicates: failed to get torrent files")
+			continue
+		}
+		for _, i := range indexes {
+			files := filesByHash[entries[i].torrent.Hash]
+			if len(files) == 0 {
+				continue
+			}
+			largest := 0
+			for fi := range files {
+				if files[fi].Size > files[largest].Size {
+					largest = fi
+				}
+			}
+			if id, err := fileID(entries[i].torrent.SavePath, files[largest].Name); err == nil {
+				fileIDs[i] = id
+			}
+		}
+	}
+	return fileIDs
+}
+
+// memberFileIDs returns the physical identity of every file of each member, in member
+// order. Deleting files relies on it, so it fails unless every member is on an instance
+// with local filesystem access and every one of their files is found on disk.
+func (s *Service) memberFileIDs(ctx context.Context, members []Member) ([][]string, error) {
+	byInstance := make(map[int][]int)
+	for i, member := range members {
+		if !member.LocalAccess {
+			return nil, fmt.Errorf("%w: %s", ErrLocalAccessRequired, member.InstanceName)
+		}
+		byInstance[member.InstanceID] = append(byInstance[member.InstanceID], i)
+	}
+
+	ids := make([][]string, len(members))
+	for instanceID, indexes := range byInstance {
+		hashes := make([]string, 0, len(indexes))
+		for _, i := range indexes {
+			hashes = append(hashes, members[i].Hash)
+		}
+		filesByHash, err := s.torrents.GetTorrentFilesBatch(ctx, instanceID, hashes)
+		if err != nil {
+			return nil, fmt.Errorf("get torrent files: %w", err)
+		}
+		for _, i := range indexes {
+			member := &members[i]
+			files := filesByHash[member.Hash]
+			if len(files) == 0 {
+				return nil, fmt.Errorf("%w: no files reported for %s on %s", ErrUnresolvedFiles, member.Name, member.InstanceName)
+			}
+			for _, file := range files {
+				id, err := fileID(member.SavePath, file.Name)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s on %s: %v", ErrUnresolvedFiles, member.Name, member.InstanceName, err)
+				}
+				ids[i] = append(ids[i], id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// fileID returns the physical identity of a torrent file, shared by its hardlinks.
+func fileID(savePath, name string) (string, error) {
+	fullPath := filepath.Join(filepath.FromSlash(savePath), filepath.FromSlash(name))
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", fullPath)
+	}
+	id, _, err := hardlink.LinkInfo(info, fullPath)
+	return id, err
+}
+
+// buildGroup numbers the distinct copies of the data among members and computes how
+// much space keeping a single copy would free.
+func buildGroup(entries []entry, members []int, fileIDs map[int]string) Group {
+	storage := newUnionFind(len(members))
+	byKey := make(map[string]int)
+	for pos, i := range members {
+		keys := []string{"path:" + normalizePath(entries[i].torrent.ContentPath)}
+		if fileID, ok := fileIDs[i]; ok {
+			keys = append(keys, "file:"+fileID)
+		}
+		for _, key := range keys {
+			if other, ok := byKey[key]; ok {
+				storage.union(other, pos)
+			} else {
+				byKey[key] = pos
+			}
+		}
+	}
+
+	group := Group{MatchedBy: []string{}, Members: make([]Member, 0, len(members))}
+	copyNumbers := make(map[int]int)
+	copySizes := []int64{}
+	for pos, i := range members {
+		torrent := &entries[i].torrent
+		root := storage.find(pos)
+		number, ok := copyNumbers[root]
+		if !ok {
+			number = len(copyNumbers) + 1
+			copyNumbers[root] = number
+			copySizes = append(copySizes, 0)
+		}
+		copySizes[number-1] = max(copySizes[number-1], torrent.Size)
+		group.Size = max(group.Size, torrent.Size)
+
+		group.Members = append(group.Members, Member{
+			InstanceID:   entries[i].instance.ID,
+			InstanceName: entries[i].instance.Name,
+			Hash:         torrent.Hash,
+			Name:         torrent.Name,
+			Size:         torrent.Size,
+			SavePath:     torrent.SavePath,
+			ContentPath:  torrent.ContentPath,
+			Category:     torrent.Category,
+			Tags:         torrent.Tags,
+			Tracker:      torrent.Tracker,
+			State:        string(torrent.State),
+			Storage:      number,
+			LocalAccess:  entries[i].instance.HasLocalFilesystemAccess,
+		})
+	}
+
+	slices.SortFunc(group.Members, func(a, b Member) int {
+		if c := cmp.Compare(a.Storage, b.Storage); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.InstanceID, b.InstanceID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Hash, b.Hash)
+	})
+
+	group.ID = fmt.Sprintf("%d:%s", group.Members[0].InstanceID, group.Members[0].Hash)
+	group.Name = group.Members[0].Name
+	group.Copies = len(copySizes)
+	group.Kind = KindCrossSeed
+	if group.Copies > 1 {
+		group.Kind = KindDuplicate
+		var total int64
+		for _, size := range copySizes {
+			total += size
+		}
+		group.ReclaimableBytes = total - slices.Max(copySizes)
+	}
+	return group
+}
+
+// findMember returns the group holding the torrent and the torrent's member entry.
+func findMember(report *Report, instanceID int, hash string) (*Group, *Member) {
+	for gi := range report.Groups {
+		group := &report.Groups[gi]
+		for mi := range group.Members {
+			member := &group.Members[mi]
+			if member.InstanceID == instanceID && strings.EqualFold(member.Hash, hash) {
+				return group, member
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Delete removes a grouped torrent. Its files are only deleted when every member's
+// files are found on disk and none of the torrent's files are used by another member,
+// so another copy of the data remains.
+func (s *Service) Delete(ctx context.Context, instanceID int, hash string, deleteFiles bool) error {
+	report, err := s.Report(ctx)
+	if err != nil {
+		return err
+	}
+	group, member := findMember(report, instanceID, hash)
+	if group == nil {
+		return ErrNotInGroup
+	}
+
+	action := "delete"
+	if deleteFiles {
+		fileIDs, err := s.memberFileIDs(ctx, group.Members)
+		if err != nil {
+			return err
+		}
+		own := make(map[string]struct{})
+		for i, other := range group.Members {
+			if other.InstanceID == member.InstanceID && other.Hash == member.Hash {
+				for _, id := range fileIDs[i] {
+					own[id] = struct{}{}
+				}
+			}
+		}
+		for i, other := range group.Members {
+			if other.InstanceID == member.InstanceID && other.Hash == member.Hash {
+				continue
+			}
+			for _, id := range fileIDs[i] {
+				if _, ok := own[id]; ok {
+					return ErrSharedData
+				}
+			}
+		}
+		action = "deleteWithFiles"
+	}
+
+	if err := s.torrents.BulkAction(ctx, instanceID, []string{member.Hash}, action); err != nil {
+		return fmt.Errorf("delete torrent: %w", err)
+	}
+	log.Info().Int("instanceID", instanceID).Str("hash", member.Hash).Bool("deleteFiles", deleteFiles).
+		Msg("duplicates: deleted torrent")
+	return nil
+}
+
+// Hardlink replaces the files of a duplicate with hardlinks to the files of another
+// member holding a separate copy, then rechecks it. The torrent keeps its save path,
+// so it becomes a cross-seed of the source and the duplicate copy is freed.
+func (s *Service) Hardlink(ctx context.Context, instanceID int, hash string, sourceInstanceID int, sourceHash string) (*HardlinkResult, error) {
+	report, err := s.Report(ctx)
+	if err != nil {
+		return nil, err
+	}
+	group, target := findMember(report, instanceID, hash)
+	if group == nil {
+		return nil, ErrNotInGroup
+	}
+	sourceGroup, source := findMember(report, sourceInstanceID, sourceHash)
+	if sourceGroup != group {
+		return nil, fmt.Errorf("%w: source and target are in different groups", ErrNotInGroup)
+	}
+	if source.Storage == target.Storage {
+		return nil, fmt.Errorf("%w: target already uses the source's data", ErrSharedData)
+	}
+	if !target.LocalAccess || !source.LocalAccess {
+		return nil, ErrLocalAccessRequired
+	}
+
+	targetFiles, err := s.files(ctx, instanceID, target.Hash)
+	if err != nil {
+		return nil, err
+	}
+	sourceFiles, err := s.files(ctx, sourceInstanceID, source.Hash)
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := pairFiles(source.SavePath, sourceFiles, target.SavePath, targetFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	wasStopped := isStopped(target.State)
+	if !wasStopped {
+		if err := s.torrents.BulkAction(ctx, instanceID, []string{target.Hash}, "pause"); err != nil {
+			return nil, fmt.Errorf("pause torrent: %w", err)
+		}
+	}
+
+	result, linkErr := linkFiles(pairs)
+	if result.FilesLinked > 0 {
+		if err := s.torrents.BulkAction(ctx, instanceID, []string{target.Hash}, "recheck"); err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Str("hash", target.Hash).Msg("duplicates: failed to recheck torrent")
+		}
+	}
+	if !wasStopped {
+		if err := s.torrents.BulkAction(ctx, instanceID, []string{target.Hash}, "resume"); err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Str("hash", target.Hash).Msg("duplicates: failed to resume torrent")
+		}
+	}
+	if linkErr != nil {
+		return result, linkErr
+	}
+
+	log.Info().Int("instanceID", instanceID).Str("hash", target.Hash).
+		Int("sourceInstanceID", sourceInstanceID).Str("sourceHash", source.Hash).
+		Int("files", result.FilesLinked).Int64("bytes", result.BytesLinked).
+		Msg("duplicates: converted torrent to hardlinked cross-seed")
+	return result, nil
+}
+
+func (s *Service) files(ctx context.Context, instanceID int, hash string) (qbt.TorrentFiles, error) {
+	filesByHash, err := s.torrents.GetTorrentFilesBatch(ctx, instanceID, []string{hash})
+	if err != nil {
+		return nil, fmt.Errorf("get torrent files: %w", err)
+	}
+	files := filesByHash[hash]
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files reported for torrent %s", hash)
+	}
+	return files, nil
+}
+
+func isStopped(state string) bool {
+	return strings.HasPrefix(state, "paused") || strings.HasPrefix(state, "stopped")
+}
+
+// normalizePath unifies separators and trailing slashes. Case is kept, since paths
+// differing only in case are distinct files on case-sensitive filesystems.
+func normalizePath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(strings.ReplaceAll(p, `\`, "/"))
+	return strings.TrimSuffix(p, "/")
+}
+
+type unionFind []int
+
+func newUnionFind(n int) unionFind {
+	parents := make(unionFind, n)
+	for i := range parents {
+		parents[i] = i
+	}
+	return parents
+}
+
+func (u unionFind) find(i int) int {
+	for u[i] != i {
+		u[i] = u[u[i]]
+		i = u[i]
+	}
+	return i
+}
+
+func (u unionFind) union(a, b int) {
+	if ra, rb := u.find(a), u.find(b); ra != rb {
+		u[rb] = ra
+	}
+}
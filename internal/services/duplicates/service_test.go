@@ -0,0 +1,315 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package duplicates
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+	"github.com/autobrr/qui/pkg/releases"
+)
+
+type fakeInstances []*models.Instance
+
+func (f fakeInstances) List(context.Context) ([]*models.Instance, error) {
+	return f, nil
+}
+
+type fakeTorrents struct {
+	torrents map[int][]qbt.Torrent
+	files    map[string]qbt.TorrentFiles
+	failing  map[int]error
+	actions  []string
+}
+
+func (f *fakeTorrents) GetCachedInstanceTorrents(_ context.Context, instanceID int) ([]qbittorrent.CrossInstanceTorrentView, error) {
+	if err := f.failing[instanceID]; err != nil {
+		return nil, err
+	}
+	views := make([]qbittorrent.CrossInstanceTorrentView, 0, len(f.torrents[instanceID]))
+	for _, torrent := range f.torrents[instanceID] {
+		views = append(views, qbittorrent.CrossInstanceTorrentView{
+			TorrentView: qbittorrent.TorrentView{Torrent: torrent},
+			InstanceID:  instanceID,
+		})
+	}
+	return views, nil
+}
+
+func (f *fakeTorrents) GetTorrentFilesBatch(_ context.Context, _ int, hashes []string) (map[string]qbt.TorrentFiles, error) {
+	result := make(map[string]qbt.TorrentFiles)
+	for _, hash := range hashes {
+		if files, ok := f.files[hash]; ok {
+			result[hash] = files
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeTorrents) BulkAction(_ context.Context, _ int, hashes []string, action string) error {
+	for _, hash := range hashes {
+		f.actions = append(f.actions, action+":"+hash)
+	}
+	return nil
+}
+
+func complete(hash, name, contentPath string, size int64) qbt.Torrent {
+	return qbt.Torrent{
+		Hash:        hash,
+		Name:        name,
+		Size:        size,
+		Progress:    1,
+		SavePath:    filepath.Dir(contentPath),
+		ContentPath: contentPath,
+		State:       qbt.TorrentStateUploading,
+	}
+}
+
+func newTestService(torrents *fakeTorrents, instances ...*models.Instance) *Service {
+	return &Service{instances: fakeInstances(instances), torrents: torrents, parser: releases.NewDefaultParser()}
+}
+
+func TestReport(t *testing.T) {
+	const movie = "Movie.Name.2020.1080p.BluRay.x264-GRP"
+	torrents := &fakeTorrents{torrents: map[int][]qbt.Torrent{
+		1: {
+			complete("aaa", movie, "/data/movies/"+movie, 1000),
+			complete("bbb", "Show.S01E01.720p.WEB.h264-OTHER", "/data/tv/Show.S01E01", 300),
+			complete("ccc", "Other.Film.2019.2160p.WEB-DL.DDP5.1.H.265-GRP", "/data/movies/Other.Film", 700),
+		},
+		2: {
+			// Cross-seed of aaa on the same data
+			complete("ddd", movie, "/data/movies/"+movie, 1000),
+			// Same release from another tracker, downloaded again
+			complete("eee", movie, "/mnt/seedbox/"+movie, 1000),
+			// Same infohash as ccc in a separate location
+			complete("ccc", "Other.Film.2019.2160p.WEB-DL.DDP5.1.H.265-GRP", "/mnt/seedbox/Other.Film", 700),
+			// Still downloading
+			{Hash: "fff", Name: movie, Size: 1000, Progress: 0.5, ContentPath: "/tmp/" + movie},
+		},
+		3: {complete("ggg", movie, "/x/"+movie, 1000)},
+	}, failing: map[int]error{4: errors.New("connection refused")}}
+
+	s := newTestService(torrents,
+		&models.Instance{ID: 1, Name: "main", IsActive: true},
+		&models.Instance{ID: 2, Name: "seedbox", IsActive: true},
+		&models.Instance{ID: 3, Name: "disabled"},
+		&models.Instance{ID: 4, Name: "offline", IsActive: true},
+	)
+
+	report, err := s.Report(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Groups, 2)
+	assert.Equal(t, 2, report.DuplicateGroups)
+	assert.Equal(t, int64(1700), report.ReclaimableBytes)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "offline", report.Errors[0].InstanceName)
+
+	movies := report.Groups[0]
+	assert.Equal(t, KindDuplicate, movies.Kind)
+	assert.Equal(t, []string{MatchContent, MatchRelease}, movies.MatchedBy)
+	assert.Equal(t, 2, movies.Copies)
+	assert.Equal(t, int64(1000), movies.ReclaimableBytes)
+	require.Len(t, movies.Members, 3)
+	assert.Equal(t, "aaa", movies.Members[0].Hash)
+	assert.Equal(t, "ddd", movies.Members[1].Hash)
+	assert.Equal(t, movies.Members[0].Storage, movies.Members[1].Storage)
+	assert.Equal(t, "eee", movies.Members[2].Hash)
+	assert.NotEqual(t, movies.Members[0].Storage, movies.Members[2].Storage)
+
+	film := report.Groups[1]
+	assert.Equal(t, []string{MatchInfohash, MatchRelease}, film.MatchedBy)
+	assert.Equal(t, int64(700), film.ReclaimableBytes)
+}
+
+func TestReportCrossSeedOnly(t *testing.T) {
+	torrents := &fakeTorrents{torrents: map[int][]qbt.Torrent{
+		1: {complete("aaa", "Some.Album-GRP", "/data/music/Some.Album", 50)},
+		2: {complete("bbb", "Some.Album-GRP", `\data\music\Some.Album\`, 50)},
+	}}
+	s := newTestService(torrents,
+		&models.Instance{ID: 1, Name: "a", IsActive: true},
+		&models.Instance{ID: 2, Name: "b", IsActive: true},
+	)
+
+	report, err := s.Report(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, KindCrossSeed, report.Groups[0].Kind)
+	assert.Equal(t, 1, report.CrossSeedGroups)
+	assert.Zero(t, report.ReclaimableBytes)
+}
+
+func TestDelete(t *testing.T) {
+	const movie = "Movie.Name.2020.1080p.BluRay.x264-GRP"
+	dir := t.TempDir()
+	for _, base := range []string{"data", "other"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, base, movie), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, base, movie, "movie.mkv"), []byte("content"), 0o644))
+	}
+	movieFiles := qbt.TorrentFiles{{Name: movie + "/movie.mkv", Size: 7}}
+
+	torrents := &fakeTorrents{
+		torrents: map[int][]qbt.Torrent{
+			1: {
+				complete("aaa", movie, filepath.Join(dir, "data", movie), 7),
+				complete("bbb", movie, filepath.Join(dir, "data", movie), 7),
+				complete("ccc", movie, filepath.Join(dir, "other", movie), 7),
+				complete("zzz", "Unrelated.2001.720p.WEB.x264-X", filepath.Join(dir, "Unrelated"), 10),
+			},
+		},
+		files: map[string]qbt.TorrentFiles{"aaa": movieFiles, "bbb": movieFiles},
+	}
+	s := newTestService(torrents, &models.Instance{ID: 1, Name: "main", IsActive: true})
+	ctx := context.Background()
+
+	assert.ErrorIs(t, s.Delete(ctx, 1, "zzz", false), ErrNotInGroup)
+	assert.ErrorIs(t, s.Delete(ctx, 1, "ccc", true), ErrLocalAccessRequired)
+
+	s.instances = fakeInstances{{ID: 1, Name: "main", IsActive: true, HasLocalFilesystemAccess: true}}
+	assert.ErrorIs(t, s.Delete(ctx, 1, "ccc", true), ErrUnresolvedFiles)
+	torrents.files["ccc"] = movieFiles
+	assert.ErrorIs(t, s.Delete(ctx, 1, "aaa", true), ErrSharedData)
+
+	// A hardlink under another path still shares the files
+	torrents.files["ddd"] = movieFiles
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "linked", movie), 0o755))
+	require.NoError(t, os.Link(filepath.Join(dir, "other", movie, "movie.mkv"), filepath.Join(dir, "linked", movie, "movie.mkv")))
+	torrents.torrents[1] = append(torrents.torrents[1], complete("ddd", movie, filepath.Join(dir, "linked", movie), 7))
+	assert.ErrorIs(t, s.Delete(ctx, 1, "ccc", true), ErrSharedData)
+	assert.Empty(t, torrents.actions)
+
+	torrents.torrents[1] = torrents.torrents[1][:4]
+	require.NoError(t, s.Delete(ctx, 1, "aaa", false))
+	require.NoError(t, s.Delete(ctx, 1, "ccc", true))
+	assert.Equal(t, []string{"delete:aaa", "deleteWithFiles:ccc"}, torrents.actions)
+}
+
+func TestNormalizePath(t *testing.T) {
+	assert.Equal(t, "/data/Movies/Film", normalizePath(`\data\Movies\Film\`))
+	assert.NotEqual(t, normalizePath("/data/Film"), normalizePath("/data/film"), "case-sensitive paths stay distinct")
+}
+
+func TestPairFiles(t *testing.T) {
+	source := qbt.TorrentFiles{
+		{Name: "Movie/movie.mkv", Size: 1000},
+		{Name: "Movie/sample.mkv", Size: 10},
+		{Name: "Movie/movie.nfo", Size: 5},
+	}
+	target := qbt.TorrentFiles{
+		{Name: "Movie.Other/sample.mkv", Size: 10},
+		{Name: "Movie.Other/movie.mkv", Size: 1000},
+	}
+
+	pairs, err := pairFiles("/src", source, "/dst", target)
+	require.NoError(t, err)
+	require.Len(t, pairs, 2)
+	assert.Equal(t, filepath.FromSlash("/src/Movie/sample.mkv"), pairs[0].source)
+	assert.Equal(t, filepath.FromSlash("/dst/Movie.Other/sample.mkv"), pairs[0].target)
+	assert.Equal(t, filepath.FromSlash("/src/Movie/movie.mkv"), pairs[1].source)
+
+	_, err = pairFiles("/src", source, "/dst", qbt.TorrentFiles{{Name: "x.mkv", Size: 999}})
+	assert.Error(t, err)
+
+	// Files are never paired by size alone
+	_, err = pairFiles("/src", source, "/dst", qbt.TorrentFiles{{Name: "Movie.Other/renamed.mkv", Size: 1000}})
+	assert.Error(t, err)
+}
+
+func TestLinkFiles(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source.mkv")
+	target := filepath.Join(dir, "target.mkv")
+	require.NoError(t, os.WriteFile(source, []byte("content"), 0o644))
+	require.NoError(t, os.WriteFile(target, []byte("content"), 0o644))
+
+	pairs := []filePair{{source: source, target: target, size: 7}}
+	result, err := linkFiles(pairs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.FilesLinked)
+	assert.Equal(t, int64(7), result.BytesLinked)
+
+	sourceInfo, err := os.Stat(source)
+	require.NoError(t, err)
+	targetInfo, err := os.Stat(target)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(sourceInfo, targetInfo))
+	assert.NoFileExists(t, target+linkSuffix)
+
+	// Already linked files are skipped
+	result, err = linkFiles(pairs)
+	require.NoError(t, err)
+	assert.Zero(t, result.FilesLinked)
+
+	// A size mismatch fails before anything is replaced
+	other := filepath.Join(dir, "other.mkv")
+	require.NoError(t, os.WriteFile(other, []byte("different size"), 0o644))
+	_, err = linkFiles([]filePair{{source: other, target: target, size: 7}})
+	assert.Error(t, err)
+
+	// So does a file of the same size with different content
+	different := filepath.Join(dir, "different.mkv")
+	require.NoError(t, os.WriteFile(different, []byte("CONTENT"), 0o644))
+	_, err = linkFiles([]filePair{{source: source, target: different, size: 7}})
+	assert.Error(t, err)
+	data, err := os.ReadFile(different)
+	require.NoError(t, err)
+	assert.Equal(t, "CONTENT", string(data))
+	assert.NoFileExists(t, different+linkSuffix)
+}
+
+func TestHardlink(t *testing.T) {
+	const movie = "Movie.Name.2020.1080p.BluRay.x264-GRP"
+	dir := t.TempDir()
+	for _, base := range []string{"keep", "dupe"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, base, movie), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, base, movie, "movie.mkv"), []byte("content"), 0o644))
+	}
+
+	torrents := &fakeTorrents{
+		torrents: map[int][]qbt.Torrent{
+			1: {complete("aaa", movie, filepath.Join(dir, "keep", movie), 7)},
+			2: {complete("bbb", movie, filepath.Join(dir, "dupe", movie), 7)},
+		},
+		files: map[string]qbt.TorrentFiles{
+			"aaa": {{Name: movie + "/movie.mkv", Size: 7}},
+			"bbb": {{Name: movie + "/movie.mkv", Size: 7}},
+		},
+	}
+	s := newTestService(torrents,
+		&models.Instance{ID: 1, Name: "main", IsActive: true, HasLocalFilesystemAccess: true},
+		&models.Instance{ID: 2, Name: "other", IsActive: true},
+	)
+	ctx := context.Background()
+
+	_, err := s.Hardlink(ctx, 2, "bbb", 1, "aaa")
+	require.ErrorIs(t, err, ErrLocalAccessRequired)
+
+	s.instances = fakeInstances{
+		{ID: 1, Name: "main", IsActive: true, HasLocalFilesystemAccess: true},
+		{ID: 2, Name: "other", IsActive: true, HasLocalFilesystemAccess: true},
+	}
+	result, err := s.Hardlink(ctx, 2, "bbb", 1, "aaa")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.FilesLinked)
+	assert.Equal(t, []string{"pause:bbb", "recheck:bbb", "resume:bbb"}, torrents.actions)
+
+	// The members now share data and form a cross-seed group
+	report, err := s.Report(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, KindCrossSeed, report.Groups[0].Kind)
+
+	_, err = s.Hardlink(ctx, 2, "bbb", 1, "aaa")
+	assert.ErrorIs(t, err, ErrSharedData)
+}
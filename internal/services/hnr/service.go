@@ -0,0 +1,234 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package hnr tracks torrents against per-tracker hit-and-run requirements.
+package hnr
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+type profileLister interface {
+	List(ctx context.Context) ([]*models.HnRProfile, error)
+}
+
+type instanceLister interface {
+	List(ctx context.Context) ([]*models.Instance, error)
+}
+
+type torrentSource interface {
+	GetAllTorrents(ctx context.Context, instanceID int) ([]qbt.Torrent, error)
+	ExtractDomainFromURL(urlStr string) string
+}
+
+// AtRiskTorrent is a torrent whose hit-and-run compliance needs attention.
+type AtRiskTorrent struct {
+	InstanceID   int                       `json:"instanceId"`
+	InstanceName string                    `json:"instanceName"`
+	Hash         string                    `json:"hash"`
+	Name         string                    `json:"name"`
+	State        qbt.TorrentState          `json:"state"`
+	SeedingTime  int64                     `json:"seedingTime"`
+	Ratio        float64                   `json:"ratio"`
+	Compliance   qbittorrent.HnRCompliance `json:"compliance"`
+}
+
+// Service matches torrents to hit-and-run profiles. Enabled profiles are cached
+// in memory; call Reload after changing them.
+type Service struct {
+	profileStore profileLister
+	instances    instanceLister
+	source       torrentSource
+
+	mu       sync.RWMutex
+	profiles []*models.HnRProfile
+}
+
+// NewService creates a hit-and-run compliance service.
+func NewService(profileStore *models.HnRProfileStore, instanceStore *models.InstanceStore, syncManager *qbittorrent.SyncManager) *Service {
+	return &Service{
+		profileStore: profileStore,
+		instances:    instanceStore,
+		source:       syncManager,
+	}
+}
+
+// Reload refreshes the cached profiles from the store.
+func (s *Service) Reload(ctx context.Context) error {
+	profiles, err := s.profileStore.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	enabled := make([]*models.HnRProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.Enabled {
+			enabled = append(enabled, profile)
+		}
+	}
+
+	s.mu.Lock()
+	s.profiles = enabled
+	s.mu.Unlock()
+	return nil
+}
+
+// HnRCompliance implements qbittorrent.HnRProvider.
+func (s *Service) HnRCompliance(torrent qbt.Torrent, now time.Time) (qbittorrent.HnRCompliance, bool) {
+	profile := s.profileFor(torrent)
+	if profile == nil {
+		return qbittorrent.HnRCompliance{}, false
+	}
+	return Evaluate(profile, torrent, now), true
+}
+
+// profileFor returns the first enabled profile covering one of the torrent's trackers.
+func (s *Service) profileFor(torrent qbt.Torrent) *models.HnRProfile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.profiles) == 0 {
+		return nil
+	}
+
+	urls := make([]string, 0, len(torrent.Trackers)+1)
+	if torrent.Tracker != "" {
+		urls = append(urls, torrent.Tracker)
+	}
+	for _, tracker := range torrent.Trackers {
+		if tracker.Url != "" {
+			urls = append(urls, tracker.Url)
+		}
+	}
+
+	for _, profile := range s.profiles {
+		for _, url := range urls {
+			if profile.MatchesDomain(s.source.ExtractDomainFromURL(url)) {
+				return profile
+			}
+		}
+	}
+	return nil
+}
+
+// AtRisk lists torrents that are at risk of, or already counted as, a
+// hit-and-run, most urgent first. A zero instanceID covers every active instance.
+func (s *Service) AtRisk(ctx context.Context, instanceID int) ([]AtRiskTorrent, error) {
+	instances, err := s.instances.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var results []AtRiskTorrent
+	for _, instance := range instances {
+		if !instance.IsActive || (instanceID > 0 && instance.ID != instanceID) {
+			continue
+		}
+
+		torrents, err := s.source.GetAllTorrents(ctx, instance.ID)
+		if err != nil {
+			log.Debug().Err(err).Int("instanceID", instance.ID).Msg("hnr: failed to load torrents")
+			continue
+		}
+
+		for _, torrent := range torrents {
+			compliance, ok := s.HnRCompliance(torrent, now)
+			if !ok || (compliance.Status != qbittorrent.HnRStatusAtRisk && compliance.Status != qbittorrent.HnRStatusViolated) {
+				continue
+			}
+			results = append(results, AtRiskTorrent{
+				InstanceID:   instance.ID,
+				InstanceName: instance.Name,
+				Hash:         torrent.Hash,
+				Name:         torrent.Name,
+				State:        torrent.State,
+				SeedingTime:  torrent.SeedingTime,
+				Ratio:        torrent.Ratio,
+				Compliance:   compliance,
+			})
+		}
+	}
+
+	slices.SortFunc(results, func(a, b AtRiskTorrent) int {
+		if a.Compliance.Status != b.Compliance.Status {
+			if a.Compliance.Status == qbittorrent.HnRStatusViolated {
+				return -1
+			}
+			return 1
+		}
+		if a.Compliance.Deadline != b.Compliance.Deadline {
+			switch {
+			case a.Compliance.Deadline == 0:
+				return 1
+			case b.Compliance.Deadline == 0:
+				return -1
+			}
+			return cmp.Compare(a.Compliance.Deadline, b.Compliance.Deadline)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return results, nil
+}
+
+// Evaluate computes a torrent's compliance with a profile.
+func Evaluate(profile *models.HnRProfile, torrent qbt.Torrent, now time.Time) qbittorrent.HnRCompliance {
+	compliance := qbittorrent.HnRCompliance{ProfileID: profile.ID, ProfileName: profile.Name}
+
+	if profile.MinSeedTime > 0 {
+		compliance.SeedTimeRemaining = max(profile.MinSeedTime-torrent.SeedingTime, 0)
+	}
+	if profile.MinRatio > 0 {
+		compliance.RatioRemaining = max(profile.MinRatio-torrent.Ratio, 0)
+	}
+	if profile.GracePeriod > 0 && torrent.CompletionOn > 0 {
+		compliance.Deadline = torrent.CompletionOn + profile.GracePeriod
+	}
+
+	seedTimeMet := profile.MinSeedTime > 0 && compliance.SeedTimeRemaining == 0
+	ratioMet := profile.MinRatio > 0 && compliance.RatioRemaining == 0
+
+	switch {
+	case torrent.Progress < 1:
+		compliance.Status = qbittorrent.HnRStatusDownloading
+	case torrent.Downloaded == 0:
+		compliance.Status = qbittorrent.HnRStatusExempt
+	case seedTimeMet || ratioMet || (profile.MinSeedTime == 0 && profile.MinRatio == 0):
+		compliance.Status = qbittorrent.HnRStatusCompliant
+	case compliance.Deadline > 0 && now.Unix() > compliance.Deadline:
+		compliance.Status = qbittorrent.HnRStatusViolated
+	case !isSeeding(torrent.State):
+		compliance.Status = qbittorrent.HnRStatusAtRisk
+	case compliance.Deadline > 0 && profile.MinRatio == 0 && now.Unix()+compliance.SeedTimeRemaining > compliance.Deadline:
+		// Seed time alone can no longer be met in time.
+		compliance.Status = qbittorrent.HnRStatusAtRisk
+	default:
+		compliance.Status = qbittorrent.HnRStatusPending
+	}
+
+	if compliance.Status.IsCompliant() {
+		compliance.SeedTimeRemaining = 0
+		compliance.RatioRemaining = 0
+	}
+	return compliance
+}
+
+// isSeeding reports whether the torrent accrues seeding time in its state.
+func isSeeding(state qbt.TorrentState) bool {
+	switch state {
+	case qbt.TorrentStatePausedUp, qbt.TorrentStateStoppedUp, qbt.TorrentStateQueuedUp,
+		qbt.TorrentStateError, qbt.TorrentStateMissingFiles:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package hnr
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+type fakeProfiles []*models.HnRProfile
+
+func (f fakeProfiles) List(context.Context) ([]*models.HnRProfile, error) {
+	return f, nil
+}
+
+type fakeInstances []*models.Instance
+
+func (f fakeInstances) List(context.Context) ([]*models.Instance, error) {
+	return f, nil
+}
+
+type fakeSource map[int][]qbt.Torrent
+
+func (f fakeSource) GetAllTorrents(_ context.Context, instanceID int) ([]qbt.Torrent, error) {
+	return f[instanceID], nil
+}
+
+func (f fakeSource) ExtractDomainFromURL(urlStr string) string {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	completed := now.Add(-3 * 24 * time.Hour).Unix()
+	profile := &models.HnRProfile{ID: 1, Name: "tracker", MinSeedTime: 72 * 3600, MinRatio: 1, GracePeriod: 14 * 24 * 3600}
+
+	tests := []struct {
+		name     string
+		profile  *models.HnRProfile
+		torrent  qbt.Torrent
+		expected qbittorrent.HnRStatus
+	}{
+		{
+			name:     "still downloading",
+			profile:  profile,
+			torrent:  qbt.Torrent{Progress: 0.5, Downloaded: 100},
+			expected: qbittorrent.HnRStatusDownloading,
+		},
+		{
+			name:     "nothing downloaded",
+			profile:  profile,
+			torrent:  qbt.Torrent{Progress: 1, CompletionOn: completed, State: qbt.TorrentStateStoppedUp},
+			expected: qbittorrent.HnRStatusExempt,
+		},
+		{
+			name:     "ratio met first",
+			profile:  profile,
+			torrent:  qbt.Torrent{Progress: 1, Downloaded: 100, Ratio: 1.2, SeedingTime: 3600, CompletionOn: completed},
+			expected: qbittorrent.HnRStatusCompliant,
+		},
+		{
+			name:     "seed time met first",
+			profile:  profile,
+			torrent:  qbt.Torrent{Progress: 1, Downloaded: 100, Ratio: 0.1, SeedingTime: 72 * 3600, CompletionOn: completed},
+			expected: qbittorrent.HnRStatusCompliant,
+		},
+		{
+			name:     "seeding towards requirement",
+			profile:  profile,
+			torrent:  qbt.Torrent{Progress: 1, Downloaded: 100, SeedingTime: 3600, CompletionOn: completed, State: qbt.TorrentStateUploading},
+			expected: qbittorrent.HnRStatusPending,
+		},
+		{
+			name:     "stopped before requirement",
+			profile:  profile,
+			torrent:  qbt.Torrent{Progress: 1, Downloaded: 100, SeedingTime: 3600, CompletionOn: completed, State: qbt.TorrentStateStoppedUp},
+			expected: qbittorrent.HnRStatusAtRisk,
+		},
+		{
+			name:     "deadline passed",
+			profile:  profile,
+			torrent:  qbt.Torrent{Progress: 1, Downloaded: 100, SeedingTime: 3600, CompletionOn: now.Add(-15 * 24 * time.Hour).Unix(), State: qbt.TorrentStateUploading},
+			expected: qbittorrent.HnRStatusViolated,
+		},
+		{
+			name:     "seed time can no longer be met in time",
+			profile:  &models.HnRProfile{MinSeedTime: 72 * 3600, GracePeriod: 4 * 24 * 3600},
+			torrent:  qbt.Torrent{Progress: 1, Downloaded: 100, SeedingTime: 3600, CompletionOn: completed, State: qbt.TorrentStateUploading},
+			expected: qbittorrent.HnRStatusAtRisk,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Evaluate(tt.profile, tt.torrent, now).Status)
+		})
+	}
+
+	pending := Evaluate(profile, qbt.Torrent{Progress: 1, Downloaded: 100, Ratio: 0.25, SeedingTime: 3600, CompletionOn: completed, State: qbt.TorrentStateUploading}, now)
+	assert.Equal(t, int64(71*3600), pending.SeedTimeRemaining)
+	assert.InDelta(t, 0.75, pending.RatioRemaining, 0.0001)
+	assert.Equal(t, completed+14*24*3600, pending.Deadline)
+}
+
+func TestService_MatchesProfilesAndListsRisks(t *testing.T) {
+	now := time.Now()
+	source := fakeSource{
+		1: {
+			{Hash: "a", Name: "stopped", Tracker: "https://tracker.example/announce", Progress: 1, Downloaded: 1, CompletionOn: now.Add(-time.Hour).Unix(), State: qbt.TorrentStateStoppedUp},
+			{Hash: "b", Name: "late", Trackers: []qbt.TorrentTracker{{Url: "https://tracker.example/announce"}}, Progress: 1, Downloaded: 1, CompletionOn: now.Add(-30 * 24 * time.Hour).Unix(), State: qbt.TorrentStateUploading},
+			{Hash: "c", Name: "other tracker", Tracker: "https://other.example/announce", Progress: 1, Downloaded: 1, State: qbt.TorrentStateStoppedUp},
+			{Hash: "d", Name: "seeding", Tracker: "https://tracker.example/announce", Progress: 1, Downloaded: 1, CompletionOn: now.Unix(), State: qbt.TorrentStateUploading},
+		},
+	}
+	svc := &Service{
+		profileStore: fakeProfiles{
+			{ID: 1, Name: "tracker", Domains: []string{"tracker.example"}, MinSeedTime: 3600, GracePeriod: 7 * 24 * 3600, Enabled: true},
+			{ID: 2, Name: "disabled", Domains: []string{"other.example"}, MinSeedTime: 3600},
+		},
+		instances: fakeInstances{{ID: 1, Name: "main", IsActive: true}},
+		source:    source,
+	}
+	require.NoError(t, svc.Reload(context.Background()))
+
+	_, ok := svc.HnRCompliance(source[1][2], now)
+	assert.False(t, ok, "disabled profiles don't apply")
+
+	risks, err := svc.AtRisk(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, risks, 2)
+	assert.Equal(t, "b", risks[0].Hash, "violations come first")
+	assert.Equal(t, qbittorrent.HnRStatusViolated, risks[0].Compliance.Status)
+	assert.Equal(t, "a", risks[1].Hash)
+	assert.Equal(t, "main", risks[1].InstanceName)
+}
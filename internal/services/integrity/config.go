@@ -0,0 +1,40 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package integrity
+
+import (
+	"time"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// Config holds the service configuration.
+type Config struct {
+	// SchedulerInterval is how often to check for instances due a scheduled pass.
+	SchedulerInterval time.Duration
+
+	// HistoryPerInstance is how many checks are kept per instance.
+	HistoryPerInstance int
+}
+
+// DefaultConfig returns the default service configuration.
+func DefaultConfig() Config {
+	return Config{
+		SchedulerInterval:  time.Minute,
+		HistoryPerInstance: 500,
+	}
+}
+
+// DefaultSettings returns default settings for an instance without saved settings.
+func DefaultSettings(instanceID int) *models.IntegritySettings {
+	return &models.IntegritySettings{
+		InstanceID:          instanceID,
+		Enabled:             false,
+		IntervalMinutes:     60,
+		TorrentsPerRun:      1,
+		RecheckAfterDays:    30,
+		MaxReadMiBPerSecond: 20,
+		FailureTag:          "integrity-failed",
+	}
+}
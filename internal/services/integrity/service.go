@@ -0,0 +1,449 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package integrity verifies torrent data on disk against its piece hashes
+// without involving qBittorrent's own recheck.
+package integrity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+// ErrCheckInProgress is returned when a check is already running for an instance.
+var ErrCheckInProgress = errors.New("integrity check already in progress for this instance")
+
+// ErrCheckNotFound is returned when a check cannot be found.
+var ErrCheckNotFound = errors.New("integrity check not found")
+
+// ErrCheckNotRunning is returned when canceling a check that already finished.
+var ErrCheckNotRunning = errors.New("integrity check is not running")
+
+// ErrTorrentNotFound is returned when the torrent to verify is not on the instance.
+var ErrTorrentNotFound = errors.New("torrent not found")
+
+// ErrLocalAccessRequired is returned when the instance cannot read its torrents' data.
+var ErrLocalAccessRequired = errors.New("instance does not have local filesystem access")
+
+// Trigger types recorded on checks.
+const (
+	TriggerManual    = "manual"
+	TriggerScheduled = "scheduled"
+)
+
+// Service runs integrity checks and the scheduled sampler.
+type Service struct {
+	cfg           Config
+	instanceStore *models.InstanceStore
+	store         *models.IntegrityStore
+	syncManager   *qbittorrent.SyncManager
+
+	// In-memory cancel handles keyed by check ID
+	cancelFuncs map[int64]context.CancelFunc
+	cancelMu    sync.Mutex
+
+	// Instances with a scheduled pass still working through its torrents, and
+	// when each instance last started one
+	sampling    map[int]bool
+	lastSampled map[int]time.Time
+	samplingMu  sync.Mutex
+}
+
+// NewService creates a new integrity service.
+func NewService(cfg Config, instanceStore *models.InstanceStore, store *models.IntegrityStore, syncManager *qbittorrent.SyncManager) *Service {
+	if cfg.SchedulerInterval <= 0 {
+		cfg.SchedulerInterval = DefaultConfig().SchedulerInterval
+	}
+	if cfg.HistoryPerInstance <= 0 {
+		cfg.HistoryPerInstance = DefaultConfig().HistoryPerInstance
+	}
+	return &Service{
+		cfg:           cfg,
+		instanceStore: instanceStore,
+		store:         store,
+		syncManager:   syncManager,
+		cancelFuncs:   make(map[int64]context.CancelFunc),
+		sampling:      make(map[int]bool),
+		lastSampled:   make(map[int]time.Time),
+	}
+}
+
+// Start starts the background scheduler.
+func (s *Service) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	go s.loop(ctx)
+}
+
+func (s *Service) loop(ctx context.Context) {
+	// Checks never resume after a restart
+	if err := s.store.MarkRunningChecksInterrupted(ctx); err != nil {
+		log.Error().Err(err).Msg("integrity: failed to mark interrupted checks")
+	}
+
+	ticker := time.NewTicker(s.cfg.SchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkScheduled(ctx)
+		}
+	}
+}
+
+// GetSettings returns the saved settings for an instance, or the defaults.
+func (s *Service) GetSettings(ctx context.Context, instanceID int) (*models.IntegritySettings, error) {
+	settings, err := s.store.GetSettings(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = DefaultSettings(instanceID)
+	}
+	return settings, nil
+}
+
+func (s *Service) checkScheduled(ctx context.Context) {
+	instances, err := s.instanceStore.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("integrity: failed to list instances")
+		return
+	}
+
+	now := time.Now()
+	for _, inst := range instances {
+		if !inst.IsActive || !inst.HasLocalFilesystemAccess {
+			continue
+		}
+
+		settings, err := s.store.GetSettings(ctx, inst.ID)
+		if err != nil || settings == nil || !settings.Enabled {
+			continue
+		}
+
+		// Passes that found nothing to verify leave no check behind, so the
+		// in-memory time covers them until the next restart.
+		last, err := s.store.GetLastStartedAt(ctx, inst.ID, TriggerScheduled)
+		if err != nil {
+			log.Error().Err(err).Int("instance", inst.ID).Msg("integrity: failed to load last scheduled check")
+			continue
+		}
+		interval := time.Duration(settings.IntervalMinutes) * time.Minute
+		if last != nil && now.Before(last.Add(interval)) {
+			continue
+		}
+
+		if !s.beginSampling(inst.ID, now, interval) {
+			continue
+		}
+		go func(instanceID int, settings *models.IntegritySettings) {
+			defer s.endSampling(instanceID)
+			s.sample(ctx, instanceID, settings)
+		}(inst.ID, settings)
+	}
+}
+
+func (s *Service) beginSampling(instanceID int, now time.Time, interval time.Duration) bool {
+	s.samplingMu.Lock()
+	defer s.samplingMu.Unlock()
+	if s.sampling[instanceID] {
+		return false
+	}
+	if last, ok := s.lastSampled[instanceID]; ok && now.Before(last.Add(interval)) {
+		return false
+	}
+	s.sampling[instanceID] = true
+	s.lastSampled[instanceID] = now
+	return true
+}
+
+func (s *Service) endSampling(instanceID int) {
+	s.samplingMu.Lock()
+	defer s.samplingMu.Unlock()
+	delete(s.sampling, instanceID)
+}
+
+// sample verifies the instance's most overdue torrents one after another.
+func (s *Service) sample(ctx context.Context, instanceID int, settings *models.IntegritySettings) {
+	torrents, err := s.syncManager.GetAllTorrents(ctx, instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instance", instanceID).Msg("integrity: failed to list torrents")
+		return
+	}
+	verified, err := s.store.GetLastVerifiedTimes(ctx, instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instance", instanceID).Msg("integrity: failed to load verification history")
+		return
+	}
+
+	recheckBefore := time.Now().AddDate(0, 0, -settings.RecheckAfterDays)
+	for _, torrent := range selectCandidates(torrents, verified, recheckBefore, settings.TorrentsPerRun) {
+		if ctx.Err() != nil {
+			return
+		}
+		_, done, err := s.start(instanceID, torrent, TriggerScheduled)
+		if err != nil {
+			if !errors.Is(err, ErrCheckInProgress) {
+				log.Error().Err(err).Int("instance", instanceID).Str("hash", torrent.Hash).Msg("integrity: scheduled check failed to start")
+			}
+			return
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// selectCandidates picks up to limit complete torrents that were never verified or were
+// last verified before recheckBefore, never-verified and least recently verified first.
+func selectCandidates(torrents []qbt.Torrent, verified map[string]time.Time, recheckBefore time.Time, limit int) []qbt.Torrent {
+	var candidates []qbt.Torrent
+	for _, torrent := range torrents {
+		if torrent.Progress < 1 {
+			continue
+		}
+		if last, ok := verified[torrent.Hash]; ok && !last.Before(recheckBefore) {
+			continue
+		}
+		candidates = append(candidates, torrent)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ti, iok := verified[candidates[i].Hash]
+		tj, jok := verified[candidates[j].Hash]
+		if iok != jok {
+			return !iok
+		}
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return candidates[i].CompletionOn < candidates[j].CompletionOn
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// TriggerCheck starts verifying a torrent and returns the check ID.
+func (s *Service) TriggerCheck(ctx context.Context, instanceID int, hash, triggeredBy string) (int64, error) {
+	instance, err := s.instanceStore.Get(ctx, instanceID)
+	if err != nil {
+		return 0, err
+	}
+	if !instance.HasLocalFilesystemAccess {
+		return 0, ErrLocalAccessRequired
+	}
+
+	torrents, err := s.syncManager.GetTorrents(ctx, instanceID, qbt.TorrentFilterOptions{Hashes: []string{hash}})
+	if err != nil {
+		return 0, err
+	}
+	if len(torrents) == 0 {
+		return 0, ErrTorrentNotFound
+	}
+
+	checkID, _, err := s.start(instanceID, torrents[0], triggeredBy)
+	return checkID, err
+}
+
+// start records a running check and verifies the torrent in the background.
+// The returned channel is closed once the check has finished.
+func (s *Service) start(instanceID int, torrent qbt.Torrent, triggeredBy string) (int64, <-chan struct{}, error) {
+	checkID, err := s.store.CreateCheckIfNoActive(context.Background(), instanceID, torrent.Hash, torrent.Name, triggeredBy)
+	if errors.Is(err, models.ErrIntegrityCheckActive) {
+		return 0, nil, ErrCheckInProgress
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	checkCtx, cancel := context.WithCancel(context.Background())
+	s.cancelMu.Lock()
+	s.cancelFuncs[checkID] = cancel
+	s.cancelMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			s.cancelMu.Lock()
+			delete(s.cancelFuncs, checkID)
+			s.cancelMu.Unlock()
+			cancel()
+		}()
+		s.execute(checkCtx, instanceID, checkID, torrent)
+	}()
+
+	return checkID, done, nil
+}
+
+// CancelCheck stops a running check.
+func (s *Service) CancelCheck(ctx context.Context, instanceID int, checkID int64) error {
+	check, err := s.store.GetCheckByInstance(ctx, instanceID, checkID)
+	if err != nil {
+		return err
+	}
+	if check == nil {
+		return ErrCheckNotFound
+	}
+
+	s.cancelMu.Lock()
+	cancel, ok := s.cancelFuncs[checkID]
+	s.cancelMu.Unlock()
+	if check.Status != models.IntegrityCheckStatusRunning || !ok {
+		return ErrCheckNotRunning
+	}
+
+	cancel()
+	return nil
+}
+
+func (s *Service) execute(ctx context.Context, instanceID int, checkID int64, torrent qbt.Torrent) {
+	l := log.With().Int("instance", instanceID).Int64("check", checkID).Str("hash", torrent.Hash).Logger()
+
+	check := &models.IntegrityCheck{ID: checkID, Status: models.IntegrityCheckStatusError}
+	settings, err := s.GetSettings(ctx, instanceID)
+	if err == nil {
+		var result *verifyResult
+		result, err = s.verify(ctx, instanceID, checkID, torrent, settings)
+		if result != nil {
+			check.PiecesTotal = result.PiecesTotal
+			check.PiecesChecked = result.PiecesChecked
+			check.CorruptPieces = result.CorruptPieces
+			check.MissingPieces = result.MissingPieces
+			check.BytesChecked = result.BytesChecked
+			check.BadFiles = result.BadFiles
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		check.Status = models.IntegrityCheckStatusCanceled
+	case err != nil:
+		check.ErrorMessage = err.Error()
+		l.Warn().Err(err).Msg("integrity: check failed")
+	case check.CorruptPieces > 0 || check.MissingPieces > 0:
+		check.Status = models.IntegrityCheckStatusFailed
+		l.Warn().Int("corrupt", check.CorruptPieces).Int("missing", check.MissingPieces).Msg("integrity: torrent data failed verification")
+	default:
+		check.Status = models.IntegrityCheckStatusPassed
+	}
+
+	// Use a fresh context: the check's own may be canceled
+	storeCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.store.CompleteCheck(storeCtx, check); err != nil {
+		l.Error().Err(err).Msg("integrity: failed to save check result")
+	}
+	if settings != nil {
+		s.applyFailureTag(storeCtx, instanceID, torrent, check.Status, settings.FailureTag)
+	}
+	if err := s.store.PruneChecks(storeCtx, instanceID, s.cfg.HistoryPerInstance); err != nil {
+		l.Error().Err(err).Msg("integrity: failed to prune check history")
+	}
+}
+
+func (s *Service) verify(ctx context.Context, instanceID int, checkID int64, torrent qbt.Torrent, settings *models.IntegritySettings) (*verifyResult, error) {
+	data, _, _, err := s.syncManager.ExportTorrent(ctx, instanceID, torrent.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("export torrent: %w", err)
+	}
+	mi, err := metainfo.Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse torrent: %w", err)
+	}
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		return nil, fmt.Errorf("parse torrent info: %w", err)
+	}
+
+	qbtFiles, err := s.syncManager.GetTorrentFiles(ctx, instanceID, torrent.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("get torrent files: %w", err)
+	}
+	if qbtFiles == nil {
+		return nil, errors.New("torrent has no files")
+	}
+
+	basePath := torrent.SavePath
+	if torrent.Progress < 1 && torrent.DownloadPath != "" {
+		basePath = torrent.DownloadPath
+	}
+	files, err := mapFiles(&info, *qbtFiles, basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := s.syncManager.GetTorrentPieceStates(ctx, instanceID, torrent.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("get piece states: %w", err)
+	}
+	if numPieces := len(info.Pieces) / sha1.Size; len(states) != numPieces {
+		return nil, fmt.Errorf("qBittorrent reports %d pieces, torrent has %d", len(states), numPieces)
+	}
+
+	v := &verifier{
+		info:     &info,
+		files:    files,
+		have:     func(piece int) bool { return states[piece] == qbt.PieceStateAlreadyDownloaded },
+		throttle: newThrottle(settings.MaxReadMiBPerSecond),
+		progress: func(piecesChecked int, bytesChecked int64) {
+			if err := s.store.UpdateCheckProgress(ctx, checkID, len(states), piecesChecked, bytesChecked); err != nil && ctx.Err() == nil {
+				log.Debug().Err(err).Int64("check", checkID).Msg("integrity: failed to update progress")
+			}
+		},
+	}
+	return v.run(ctx)
+}
+
+// applyFailureTag tags torrents that failed and untags them once they pass again.
+func (s *Service) applyFailureTag(ctx context.Context, instanceID int, torrent qbt.Torrent, status, tag string) {
+	if tag == "" {
+		return
+	}
+
+	var err error
+	switch status {
+	case models.IntegrityCheckStatusFailed:
+		err = s.syncManager.AddTags(ctx, instanceID, []string{torrent.Hash}, tag)
+	case models.IntegrityCheckStatusPassed:
+		if hasTag(torrent.Tags, tag) {
+			err = s.syncManager.RemoveTags(ctx, instanceID, []string{torrent.Hash}, tag)
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Int("instance", instanceID).Str("hash", torrent.Hash).Msg("integrity: failed to update failure tag")
+	}
+}
+
+func hasTag(tags, tag string) bool {
+	for t := range strings.SplitSeq(tags, ",") {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package integrity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	qbt "github.com/autobrr/go-qbittorrent"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// incompleteSuffix is appended by qBittorrent to files still being downloaded
+// when "append .!qB extension" is enabled.
+const incompleteSuffix = ".!qB"
+
+// progressEvery is how many pieces are verified between progress callbacks.
+const progressEvery = 64
+
+// dataFile is a contiguous span of the torrent's data stream.
+type dataFile struct {
+	// name is the path reported to users, relative to the save path.
+	name string
+	// path is where the file is read from; empty for BEP 47 padding files.
+	path   string
+	offset int64
+	length int64
+}
+
+func (f dataFile) pad() bool {
+	return f.path == ""
+}
+
+// verifyResult summarises a verification pass.
+type verifyResult struct {
+	PiecesTotal   int
+	PiecesChecked int
+	CorruptPieces int
+	MissingPieces int
+	BytesChecked  int64
+	BadFiles      []models.IntegrityBadFile
+}
+
+// verifier hashes torrent pieces straight from disk.
+type verifier struct {
+	info  *metainfo.Info
+	files []dataFile
+	// have reports whether qBittorrent considers a piece downloaded; nil verifies every piece.
+	have     func(piece int) bool
+	throttle *throttle
+	progress func(piecesChecked int, bytesChecked int64)
+
+	open map[string]*os.File
+}
+
+// mapFiles pairs the non-padding files of a v1 info dict with qBittorrent's file list,
+// which omits padding files and reflects renames, and resolves them under basePath.
+func mapFiles(info *metainfo.Info, qbtFiles qbt.TorrentFiles, basePath string) ([]dataFile, error) {
+	if !info.HasV1() {
+		return nil, errors.New("v2-only torrents are not supported")
+	}
+
+	sorted := make(qbt.TorrentFiles, len(qbtFiles))
+	copy(sorted, qbtFiles)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+
+	var files []dataFile
+	next := 0
+	for fi := range info.UpvertedV1Files() {
+		if strings.Contains(fi.ExtendedFileAttrs.Attr, "p") {
+			files = append(files, dataFile{offset: fi.TorrentOffset, length: fi.Length})
+			continue
+		}
+		if next >= len(sorted) {
+			return nil, fmt.Errorf("torrent has more files than qBittorrent reports (%d)", len(sorted))
+		}
+		qf := sorted[next]
+		next++
+		if qf.Size != fi.Length {
+			return nil, fmt.Errorf("size mismatch for %s: torrent %d, qBittorrent %d", qf.Name, fi.Length, qf.Size)
+		}
+		files = append(files, dataFile{
+			name:   qf.Name,
+			path:   filepath.Join(basePath, filepath.FromSlash(qf.Name)),
+			offset: fi.TorrentOffset,
+			length: fi.Length,
+		})
+	}
+	if next != len(sorted) {
+		return nil, fmt.Errorf("qBittorrent reports %d files, torrent has %d", len(sorted), next)
+	}
+
+	return files, nil
+}
+
+// run verifies every downloaded piece. It stops early only when ctx is canceled.
+func (v *verifier) run(ctx context.Context) (*verifyResult, error) {
+	v.open = make(map[string]*os.File)
+	defer v.closeAll()
+
+	// Only the v1 piece layout is verified, so hybrid torrents are hashed with SHA-1 like v1 ones.
+	numPieces := len(v.info.Pieces) / sha1.Size
+	pieceLength := v.info.PieceLength
+	var totalLength int64
+	for _, f := range v.files {
+		totalLength = max(totalLength, f.offset+f.length)
+	}
+	result := &verifyResult{PiecesTotal: numPieces}
+	bad := make(map[int]*models.IntegrityBadFile)
+
+	buf := make([]byte, pieceLength)
+	hasher := sha1.New()
+	first := 0 // index of the first file that can overlap the current piece
+
+	for piece := 0; piece < numPieces; piece++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		start := int64(piece) * pieceLength
+		for first < len(v.files) && v.files[first].offset+v.files[first].length <= start {
+			first++
+		}
+
+		if v.have != nil && !v.have(piece) {
+			continue
+		}
+
+		length := min(pieceLength, totalLength-start)
+		data := buf[:length]
+		touched, unreadable := v.readPiece(data, start, first)
+		missing := len(unreadable) > 0
+
+		if err := v.throttle.wait(ctx, length); err != nil {
+			return result, err
+		}
+
+		result.PiecesChecked++
+		result.BytesChecked += length
+
+		var corrupt bool
+		if !missing {
+			hasher.Reset()
+			hasher.Write(data)
+			want := v.info.Pieces[piece*sha1.Size : (piece+1)*sha1.Size]
+			corrupt = !bytes.Equal(hasher.Sum(nil), want)
+		}
+
+		switch {
+		case missing:
+			result.MissingPieces++
+		case corrupt:
+			result.CorruptPieces++
+		}
+
+		// A hash mismatch cannot be pinned on one file, but unreadable data can
+		blamed := unreadable
+		if corrupt {
+			blamed = touched
+		}
+		if missing || corrupt {
+			for _, idx := range blamed {
+				entry, ok := bad[idx]
+				if !ok {
+					entry = &models.IntegrityBadFile{Path: v.files[idx].name}
+					bad[idx] = entry
+				}
+				if missing {
+					entry.MissingPieces++
+				} else {
+					entry.CorruptPieces++
+				}
+			}
+		}
+
+		if v.progress != nil && result.PiecesChecked%progressEvery == 0 {
+			v.progress(result.PiecesChecked, result.BytesChecked)
+		}
+	}
+
+	indexes := make([]int, 0, len(bad))
+	for idx := range bad {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	result.BadFiles = make([]models.IntegrityBadFile, 0, len(indexes))
+	for _, idx := range indexes {
+		result.BadFiles = append(result.BadFiles, *bad[idx])
+	}
+
+	return result, nil
+}
+
+// readPiece fills data with the piece starting at torrent offset start. It returns
+// the real files the piece spans and those whose part of it could not be read.
+func (v *verifier) readPiece(data []byte, start int64, first int) (touched, unreadable []int) {
+	end := start + int64(len(data))
+	for i := first; i < len(v.files); i++ {
+		f := v.files[i]
+		if f.offset >= end {
+			break
+		}
+		if f.length == 0 {
+			continue
+		}
+
+		from := max(start, f.offset)
+		to := min(end, f.offset+f.length)
+		chunk := data[from-start : to-start]
+
+		if f.pad() {
+			clear(chunk)
+			continue
+		}
+		touched = append(touched, i)
+
+		file := v.openFile(f.path)
+		if file == nil {
+			unreadable = append(unreadable, i)
+			continue
+		}
+		if _, err := file.ReadAt(chunk, from-f.offset); err != nil {
+			unreadable = append(unreadable, i)
+		}
+	}
+	return touched, unreadable
+}
+
+// openFile returns a cached handle for path, falling back to qBittorrent's
+// incomplete-file name. It returns nil when neither can be opened.
+func (v *verifier) openFile(path string) *os.File {
+	if file, ok := v.open[path]; ok {
+		return file
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		file, err = os.Open(path + incompleteSuffix)
+	}
+	if err != nil {
+		file = nil
+	}
+	v.open[path] = file
+	return file
+}
+
+func (v *verifier) closeAll() {
+	for _, file := range v.open {
+		if file != nil {
+			file.Close()
+		}
+	}
+}
+
+// throttle limits the average read rate of a verification pass.
+type throttle struct {
+	bytesPerSecond int64
+	started        time.Time
+	consumed       int64
+}
+
+func newThrottle(mibPerSecond int) *throttle {
+	if mibPerSecond <= 0 {
+		return nil
+	}
+	return &throttle{bytesPerSecond: int64(mibPerSecond) << 20, started: time.Now()}
+}
+
+// wait accounts for n bytes read and sleeps until the average rate is back under the limit.
+func (t *throttle) wait(ctx context.Context, n int64) error {
+	if t == nil {
+		return nil
+	}
+	t.consumed += n
+	due := time.Duration(float64(t.consumed) / float64(t.bytesPerSecond) * float64(time.Second))
+	delay := due - time.Since(t.started)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package integrity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const testPieceLength = 16
+
+type testFile struct {
+	path string // empty for a padding file
+	data []byte
+}
+
+// buildTorrent writes the files under dir/name and returns a v1 info dict for them
+// plus the file list qBittorrent would report, which leaves out padding files.
+func buildTorrent(t *testing.T, dir, name string, files []testFile) (*metainfo.Info, qbt.TorrentFiles) {
+	t.Helper()
+
+	info := &metainfo.Info{Name: name, PieceLength: testPieceLength}
+	var stream bytes.Buffer
+	var qbtFiles qbt.TorrentFiles
+	for _, f := range files {
+		stream.Write(f.data)
+		if f.path == "" {
+			info.Files = append(info.Files, metainfo.FileInfo{
+				Length:            int64(len(f.data)),
+				Path:              []string{".pad", "0"},
+				ExtendedFileAttrs: metainfo.ExtendedFileAttrs{Attr: "p"},
+			})
+			continue
+		}
+
+		info.Files = append(info.Files, metainfo.FileInfo{Length: int64(len(f.data)), Path: []string{f.path}})
+		full := filepath.Join(dir, name, f.path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, f.data, 0o644))
+
+		qbtFiles = append(qbtFiles, struct {
+			Availability float32 `json:"availability"`
+			Index        int     `json:"index"`
+			IsSeed       bool    `json:"is_seed,omitempty"`
+			Name         string  `json:"name"`
+			PieceRange   []int   `json:"piece_range"`
+			Priority     int     `json:"priority"`
+			Progress     float32 `json:"progress"`
+			Size         int64   `json:"size"`
+		}{Index: len(qbtFiles), Name: name + "/" + f.path, Size: int64(len(f.data))})
+	}
+
+	data := stream.Bytes()
+	for start := 0; start < len(data); start += testPieceLength {
+		sum := sha1.Sum(data[start:min(start+testPieceLength, len(data))])
+		info.Pieces = append(info.Pieces, sum[:]...)
+	}
+	return info, qbtFiles
+}
+
+func verifyTorrent(t *testing.T, info *metainfo.Info, qbtFiles qbt.TorrentFiles, dir string, have func(int) bool) *verifyResult {
+	t.Helper()
+
+	files, err := mapFiles(info, qbtFiles, dir)
+	require.NoError(t, err)
+	result, err := (&verifier{info: info, files: files, have: have}).run(context.Background())
+	require.NoError(t, err)
+	return result
+}
+
+func standardFiles() []testFile {
+	return []testFile{
+		{path: "a.bin", data: bytes.Repeat([]byte("a"), 20)},
+		{path: "b.bin", data: bytes.Repeat([]byte("b"), 12)},
+		{path: "c.bin", data: bytes.Repeat([]byte("c"), 10)},
+	}
+}
+
+func TestVerifierPassesIntactData(t *testing.T) {
+	dir := t.TempDir()
+	info, qbtFiles := buildTorrent(t, dir, "release", standardFiles())
+
+	result := verifyTorrent(t, info, qbtFiles, dir, nil)
+	assert.Equal(t, 3, result.PiecesTotal)
+	assert.Equal(t, 3, result.PiecesChecked)
+	assert.Equal(t, int64(42), result.BytesChecked)
+	assert.Zero(t, result.CorruptPieces)
+	assert.Zero(t, result.MissingPieces)
+	assert.Empty(t, result.BadFiles)
+}
+
+func TestVerifierReportsCorruptPieces(t *testing.T) {
+	dir := t.TempDir()
+	info, qbtFiles := buildTorrent(t, dir, "release", standardFiles())
+
+	// Byte 18 of a.bin lives in piece 1, which also spans b.bin
+	path := filepath.Join(dir, "release", "a.bin")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[18] = 'x'
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	result := verifyTorrent(t, info, qbtFiles, dir, nil)
+	assert.Equal(t, 1, result.CorruptPieces)
+	assert.Zero(t, result.MissingPieces)
+	assert.Equal(t, []models.IntegrityBadFile{
+		{Path: "release/a.bin", CorruptPieces: 1},
+		{Path: "release/b.bin", CorruptPieces: 1},
+	}, result.BadFiles)
+}
+
+func TestVerifierReportsMissingAndTruncatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	info, qbtFiles := buildTorrent(t, dir, "release", standardFiles())
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "release", "c.bin")))
+	require.NoError(t, os.Truncate(filepath.Join(dir, "release", "a.bin"), 8))
+
+	result := verifyTorrent(t, info, qbtFiles, dir, nil)
+	assert.Equal(t, 3, result.MissingPieces, "every piece touches a truncated or deleted file")
+	assert.Zero(t, result.CorruptPieces)
+	// b.bin shares piece 1 with a.bin but is intact, so it is not blamed
+	assert.Equal(t, []models.IntegrityBadFile{
+		{Path: "release/a.bin", MissingPieces: 2},
+		{Path: "release/c.bin", MissingPieces: 1},
+	}, result.BadFiles)
+}
+
+func TestVerifierSkipsPiecesNotDownloaded(t *testing.T) {
+	dir := t.TempDir()
+	info, qbtFiles := buildTorrent(t, dir, "release", standardFiles())
+	require.NoError(t, os.Remove(filepath.Join(dir, "release", "c.bin")))
+
+	result := verifyTorrent(t, info, qbtFiles, dir, func(piece int) bool { return piece < 2 })
+	assert.Equal(t, 2, result.PiecesChecked)
+	assert.Zero(t, result.MissingPieces)
+	assert.Empty(t, result.BadFiles)
+}
+
+func TestVerifierReadsIncompleteFileSuffix(t *testing.T) {
+	dir := t.TempDir()
+	info, qbtFiles := buildTorrent(t, dir, "release", standardFiles())
+
+	path := filepath.Join(dir, "release", "c.bin")
+	require.NoError(t, os.Rename(path, path+incompleteSuffix))
+
+	result := verifyTorrent(t, info, qbtFiles, dir, nil)
+	assert.Zero(t, result.MissingPieces)
+	assert.Zero(t, result.CorruptPieces)
+}
+
+func TestVerifierHandlesPaddingFiles(t *testing.T) {
+	dir := t.TempDir()
+	info, qbtFiles := buildTorrent(t, dir, "release", []testFile{
+		{path: "a.bin", data: bytes.Repeat([]byte("a"), 10)},
+		{data: make([]byte, 6)},
+		{path: "b.bin", data: bytes.Repeat([]byte("b"), 16)},
+	})
+	require.Len(t, qbtFiles, 2)
+
+	result := verifyTorrent(t, info, qbtFiles, dir, nil)
+	assert.Equal(t, 2, result.PiecesChecked)
+	assert.Zero(t, result.CorruptPieces)
+	assert.Zero(t, result.MissingPieces)
+}
+
+func TestMapFilesRejectsMismatchedFileLists(t *testing.T) {
+	dir := t.TempDir()
+	info, qbtFiles := buildTorrent(t, dir, "release", standardFiles())
+
+	_, err := mapFiles(info, qbtFiles[:2], dir)
+	require.Error(t, err)
+
+	qbtFiles[1].Size++
+	_, err = mapFiles(info, qbtFiles, dir)
+	require.Error(t, err)
+}
+
+func TestThrottleLimitsReadRate(t *testing.T) {
+	th := newThrottle(1)
+	require.Nil(t, newThrottle(0))
+
+	start := time.Now()
+	require.NoError(t, th.wait(context.Background(), 1<<17)) // 1/8 MiB
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, th.wait(ctx, 1<<20), context.Canceled)
+}
+
+func TestSelectCandidates(t *testing.T) {
+	now := time.Now()
+	torrents := []qbt.Torrent{
+		{Hash: "recent", Progress: 1},
+		{Hash: "stale", Progress: 1},
+		{Hash: "never-new", Progress: 1, CompletionOn: 200},
+		{Hash: "never-old", Progress: 1, CompletionOn: 100},
+		{Hash: "incomplete", Progress: 0.5},
+	}
+	verified := map[string]time.Time{
+		"recent": now.Add(-time.Hour),
+		"stale":  now.AddDate(0, 0, -60),
+	}
+
+	got := selectCandidates(torrents, verified, now.AddDate(0, 0, -30), 0)
+	hashes := make([]string, 0, len(got))
+	for _, torrent := range got {
+		hashes = append(hashes, torrent.Hash)
+	}
+	assert.Equal(t, []string{"never-old", "never-new", "stale"}, hashes)
+
+	assert.Len(t, selectCandidates(torrents, verified, now.AddDate(0, 0, -30), 1), 1)
+}
+
+func TestHasTag(t *testing.T) {
+	assert.True(t, hasTag("keep, integrity-failed", "integrity-failed"))
+	assert.False(t, hasTag("integrity-failed-old", "integrity-failed"))
+	assert.False(t, hasTag("", "integrity-failed"))
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/moistari/rls"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/pkg/timeouts"
 	"github.com/autobrr/qui/pkg/prowlarr"
@@ -88,6 +89,9 @@ type Service struct {
 
 	// indexerOutcomes tracks cross-seed outcomes per (jobID, indexerID)
 	indexerOutcomes *IndexerOutcomeStore
+
+	// eventBus receives indexer cooldowns; nil discards them.
+	eventBus *events.Bus
 }
 
 // ErrMissingIndexerIdentifier signals that the Torznab backend requires an indexer ID to fetch caps.
@@ -464,6 +468,13 @@ func WithIndexerOutcomes(capacity int) ServiceOption {
 	}
 }
 
+// WithEventBus publishes indexer cooldowns to the given bus.
+func WithEventBus(bus *events.Bus) ServiceOption {
+	return func(s *Service) {
+		s.eventBus = bus
+	}
+}
+
 // ReportIndexerOutcome records a cross-seed outcome for a specific indexer's search results.
 // Called by the cross-seed service after processing search results.
 func (s *Service) ReportIndexerOutcome(jobID uint64, indexerID int, outcome string, addedCount int, message string) {
@@ -1015,6 +1026,7 @@ func (s *Service) DownloadTorrent(ctx context.Context, req TorrentDownloadReques
 
 			// Persist cooldown if enabled
 			s.persistRateLimitCooldown(req.IndexerID, resumeAt, cooldown, "download_rate_limited")
+			s.publishCooldown(req.IndexerID, indexer.Name, resumeAt, "download_rate_limited")
 
 			return nil, &DownloadRateLimitError{
 				IndexerID:   req.IndexerID,
@@ -2717,6 +2729,19 @@ func (s *Service) handleRateLimit(ctx context.Context, idx *models.TorznabIndexe
 		reason = cause.Error()
 	}
 	s.persistRateLimitCooldown(idx.ID, resumeAt, cooldown, reason)
+	s.publishCooldown(idx.ID, idx.Name, resumeAt, reason)
+}
+
+func (s *Service) publishCooldown(indexerID int, indexerName string, until time.Time, reason string) {
+	s.eventBus.Publish(events.Event{
+		Type: events.IndexerCooledDown,
+		Payload: events.IndexerCooldown{
+			IndexerID:   indexerID,
+			IndexerName: indexerName,
+			Until:       until,
+			Reason:      reason,
+		},
+	})
 }
 
 func (s *Service) ensureRateLimiterState() {
@@ -0,0 +1,85 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// Event is a single notification, translated from a bus event.
+type Event struct {
+	Type         models.NotificationEventType `json:"type"`
+	InstanceID   int                          `json:"instanceId,omitempty"`
+	InstanceName string                       `json:"instanceName,omitempty"`
+	Title        string                       `json:"title"`
+	Message      string                       `json:"message"`
+	Fields       map[string]string            `json:"fields,omitempty"`
+	Time         time.Time                    `json:"time"`
+}
+
+// IsFailure reports whether the event describes something going wrong.
+func (e Event) IsFailure() bool {
+	switch e.Type {
+	case models.NotificationEventInstanceError, models.NotificationEventBackupFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// renderedMessage is the provider-agnostic title and body for an event.
+type renderedMessage struct {
+	Title string
+	Body  string
+}
+
+// parseTemplates validates the title and body templates.
+func parseTemplates(titleTemplate, bodyTemplate string) (*template.Template, *template.Template, error) {
+	var titleTmpl, bodyTmpl *template.Template
+	var err error
+	if titleTemplate != "" {
+		if titleTmpl, err = template.New("title").Option("missingkey=zero").Parse(titleTemplate); err != nil {
+			return nil, nil, fmt.Errorf("invalid title template: %w", err)
+		}
+	}
+	if bodyTemplate != "" {
+		if bodyTmpl, err = template.New("body").Option("missingkey=zero").Parse(bodyTemplate); err != nil {
+			return nil, nil, fmt.Errorf("invalid body template: %w", err)
+		}
+	}
+	return titleTmpl, bodyTmpl, nil
+}
+
+// render applies the provider's templates to the event, falling back to the
+// event's own title and message.
+func render(provider *models.NotificationProvider, event Event) (renderedMessage, error) {
+	msg := renderedMessage{Title: event.Title, Body: event.Message}
+
+	titleTmpl, bodyTmpl, err := parseTemplates(provider.TitleTemplate, provider.BodyTemplate)
+	if err != nil {
+		return msg, err
+	}
+
+	if titleTmpl != nil {
+		var buf bytes.Buffer
+		if err := titleTmpl.Execute(&buf, event); err != nil {
+			return msg, fmt.Errorf("render title: %w", err)
+		}
+		msg.Title = buf.String()
+	}
+	if bodyTmpl != nil {
+		var buf bytes.Buffer
+		if err := bodyTmpl.Execute(&buf, event); err != nil {
+			return msg, fmt.Errorf("render body: %w", err)
+		}
+		msg.Body = buf.String()
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	discordColorInfo    = 0x3b82f6
+	discordColorFailure = 0xef4444
+	defaultGotifyPrio   = 5
+)
+
+// webhookPayload is the body sent to generic webhook providers.
+type webhookPayload struct {
+	Event        models.NotificationEventType `json:"event"`
+	InstanceID   int                          `json:"instanceId,omitempty"`
+	InstanceName string                       `json:"instanceName,omitempty"`
+	Title        string                       `json:"title"`
+	Message      string                       `json:"message"`
+	Fields       map[string]string            `json:"fields,omitempty"`
+	Time         time.Time                    `json:"time"`
+}
+
+// send delivers a rendered message through the provider.
+func send(ctx context.Context, client *http.Client, provider *models.NotificationProvider, token string, event Event, msg renderedMessage) error {
+	switch provider.Type {
+	case models.NotificationProviderWebhook:
+		return sendWebhook(ctx, client, provider, token, event, msg)
+	case models.NotificationProviderDiscord:
+		return sendDiscord(ctx, client, provider, event, msg)
+	case models.NotificationProviderNtfy:
+		return sendNtfy(ctx, client, provider, token, event, msg)
+	case models.NotificationProviderGotify:
+		return sendGotify(ctx, client, provider, token, msg)
+	case models.NotificationProviderApprise:
+		return sendApprise(ctx, client, provider, token, event, msg)
+	default:
+		return fmt.Errorf("unsupported provider type %q", provider.Type)
+	}
+}
+
+func sendWebhook(ctx context.Context, client *http.Client, provider *models.NotificationProvider, token string, event Event, msg renderedMessage) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:        event.Type,
+		InstanceID:   event.InstanceID,
+		InstanceName: event.InstanceName,
+		Title:        msg.Title,
+		Message:      msg.Body,
+		Fields:       event.Fields,
+		Time:         event.Time,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return post(ctx, client, provider.URL, body, headers)
+}
+
+func sendDiscord(ctx context.Context, client *http.Client, provider *models.NotificationProvider, event Event, msg renderedMessage) error {
+	color := discordColorInfo
+	if event.IsFailure() {
+		color = discordColorFailure
+	}
+
+	embed := map[string]any{
+		"title":       truncate(msg.Title, 256),
+		"description": truncate(msg.Body, 4096),
+		"color":       color,
+		"timestamp":   event.Time.UTC().Format(time.RFC3339),
+	}
+	if event.InstanceName != "" {
+		embed["footer"] = map[string]string{"text": event.InstanceName}
+	}
+
+	payload := map[string]any{"embeds": []any{embed}}
+	if username := provider.Options["username"]; username != "" {
+		payload["username"] = username
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	return post(ctx, client, provider.URL, body, map[string]string{"Content-Type": "application/json"})
+}
+
+// sendNtfy publishes to an ntfy topic URL (e.g. https://ntfy.sh/my-topic).
+func sendNtfy(ctx context.Context, client *http.Client, provider *models.NotificationProvider, token string, event Event, msg renderedMessage) error {
+	headers := map[string]string{"Title": msg.Title}
+	if priority := provider.Options["priority"]; priority != "" {
+		headers["Priority"] = priority
+	} else if event.IsFailure() {
+		headers["Priority"] = "high"
+	}
+	if tags := provider.Options["tags"]; tags != "" {
+		headers["Tags"] = tags
+	}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return post(ctx, client, provider.URL, []byte(msg.Body), headers)
+}
+
+// sendGotify posts to a Gotify server's /message endpoint using an application token.
+func sendGotify(ctx context.Context, client *http.Client, provider *models.NotificationProvider, token string, msg renderedMessage) error {
+	priority := defaultGotifyPrio
+	if raw := provider.Options["priority"]; raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			priority = parsed
+		}
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"title":    msg.Title,
+		"message":  msg.Body,
+		"priority": priority,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if token != "" {
+		headers["X-Gotify-Key"] = token
+	}
+	return post(ctx, client, strings.TrimRight(provider.URL, "/")+"/message", body, headers)
+}
+
+// sendApprise posts to an Apprise API notify endpoint (e.g. http://apprise:8000/notify/qui).
+func sendApprise(ctx context.Context, client *http.Client, provider *models.NotificationProvider, token string, event Event, msg renderedMessage) error {
+	notifyType := "info"
+	if event.IsFailure() {
+		notifyType = "failure"
+	}
+
+	payload := map[string]any{
+		"title": msg.Title,
+		"body":  msg.Body,
+		"type":  notifyType,
+	}
+	if tags := provider.Options["tags"]; tags != "" {
+		payload["tag"] = tags
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return post(ctx, client, provider.URL, body, headers)
+}
+
+func post(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("provider responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit-3] + "..."
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package notifications delivers qui events to user-configured providers such
+// as webhooks, Discord, ntfy, Gotify and Apprise.
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	defaultSendTimeout = 15 * time.Second
+)
+
+// ErrInvalidTemplate is returned when a provider's title or body template cannot be parsed.
+var ErrInvalidTemplate = errors.New("invalid notification template")
+
+type providerStore interface {
+	ListEnabled(ctx context.Context) ([]*models.NotificationProvider, error)
+	Get(ctx context.Context, id int) (*models.NotificationProvider, error)
+	GetDecryptedToken(provider *models.NotificationProvider) (string, error)
+}
+
+type instanceLookup interface {
+	Get(ctx context.Context, id int) (*models.Instance, error)
+}
+
+// Service fans events out to every subscribed provider.
+type Service struct {
+	store     providerStore
+	instances instanceLookup
+	client    *http.Client
+	timeout   time.Duration
+}
+
+// NewService creates a notification service.
+func NewService(store *models.NotificationProviderStore, instanceStore *models.InstanceStore) *Service {
+	return &Service{
+		store:     store,
+		instances: instanceStore,
+		client:    &http.Client{Timeout: defaultSendTimeout},
+		timeout:   defaultSendTimeout,
+	}
+}
+
+func (s *Service) dispatch(ctx context.Context, event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	providers, err := s.store.ListEnabled(ctx)
+	if err != nil {
+		log.Warn().Err(err).Str("event", string(event.Type)).Msg("notifications: failed to load providers")
+		return
+	}
+
+	s.resolveInstanceName(ctx, &event)
+
+	for _, provider := range providers {
+		if !provider.Subscribed(event.Type, event.InstanceID) {
+			continue
+		}
+		if err := s.deliver(ctx, provider, event); err != nil {
+			log.Warn().
+				Err(err).
+				Int("providerID", provider.ID).
+				Str("provider", provider.Name).
+				Str("event", string(event.Type)).
+				Msg("notifications: delivery failed")
+		}
+	}
+}
+
+// SendTest synchronously sends a test notification through a single provider,
+// ignoring its event and instance filters.
+func (s *Service) SendTest(ctx context.Context, providerID int) error {
+	provider, err := s.store.Get(ctx, providerID)
+	if err != nil {
+		return err
+	}
+
+	event := Event{
+		Type:    models.NotificationEventTest,
+		Title:   "qui test notification",
+		Message: fmt.Sprintf("This is a test notification for provider %q.", provider.Name),
+		Time:    time.Now().UTC(),
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.deliver(sendCtx, provider, event)
+}
+
+func (s *Service) deliver(ctx context.Context, provider *models.NotificationProvider, event Event) error {
+	msg, err := render(provider, event)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.store.GetDecryptedToken(provider)
+	if err != nil {
+		return fmt.Errorf("decrypt token: %w", err)
+	}
+
+	return send(ctx, s.client, provider, token, event, msg)
+}
+
+func (s *Service) resolveInstanceName(ctx context.Context, event *Event) {
+	if event.InstanceID <= 0 || event.InstanceName != "" || s.instances == nil {
+		return
+	}
+	instance, err := s.instances.Get(ctx, event.InstanceID)
+	if err != nil || instance == nil {
+		return
+	}
+	event.InstanceName = instance.Name
+}
+
+// ValidateTemplates checks that the title and body templates parse.
+func ValidateTemplates(titleTemplate, bodyTemplate string) error {
+	if _, _, err := parseTemplates(titleTemplate, bodyTemplate); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/events"
+	"github.com/autobrr/qui/internal/models"
+)
+
+type fakeProviderStore struct {
+	providers []*models.NotificationProvider
+	tokens    map[int]string
+}
+
+func (f *fakeProviderStore) ListEnabled(context.Context) ([]*models.NotificationProvider, error) {
+	var enabled []*models.NotificationProvider
+	for _, p := range f.providers {
+		if p.Enabled {
+			enabled = append(enabled, p)
+		}
+	}
+	return enabled, nil
+}
+
+func (f *fakeProviderStore) Get(_ context.Context, id int) (*models.NotificationProvider, error) {
+	for _, p := range f.providers {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeProviderStore) GetDecryptedToken(provider *models.NotificationProvider) (string, error) {
+	return f.tokens[provider.ID], nil
+}
+
+type fakeInstances map[int]string
+
+func (f fakeInstances) Get(_ context.Context, id int) (*models.Instance, error) {
+	name, ok := f[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &models.Instance{ID: id, Name: name}, nil
+}
+
+type capturedRequest struct {
+	path    string
+	headers http.Header
+	body    []byte
+}
+
+func newCaptureServer(t *testing.T) (*httptest.Server, func() []capturedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var captured []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		captured = append(captured, capturedRequest{path: r.URL.Path, headers: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []capturedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]capturedRequest(nil), captured...)
+	}
+}
+
+func newTestService(store *fakeProviderStore) *Service {
+	return &Service{
+		store:     store,
+		instances: fakeInstances{1: "main", 2: "seedbox"},
+		client:    &http.Client{Timeout: 5 * time.Second},
+		timeout:   5 * time.Second,
+	}
+}
+
+func TestDispatch_FiltersByEventAndInstance(t *testing.T) {
+	server, requests := newCaptureServer(t)
+
+	store := &fakeProviderStore{providers: []*models.NotificationProvider{
+		{ID: 1, Name: "all", Type: models.NotificationProviderWebhook, URL: server.URL + "/all", Enabled: true},
+		{ID: 2, Name: "backups", Type: models.NotificationProviderWebhook, URL: server.URL + "/backups", Enabled: true,
+			Events: []models.NotificationEventType{models.NotificationEventBackupFailed}},
+		{ID: 3, Name: "seedbox", Type: models.NotificationProviderWebhook, URL: server.URL + "/seedbox", Enabled: true,
+			InstanceIDs: []int{2}},
+		{ID: 4, Name: "off", Type: models.NotificationProviderWebhook, URL: server.URL + "/off"},
+	}}
+
+	newTestService(store).dispatch(context.Background(), Event{
+		Type:       models.NotificationEventInstanceError,
+		InstanceID: 1,
+		Title:      "down",
+		Message:    "connection refused",
+	})
+
+	got := requests()
+	require.Len(t, got, 1)
+	assert.Equal(t, "/all", got[0].path)
+
+	var payload webhookPayload
+	require.NoError(t, json.Unmarshal(got[0].body, &payload))
+	assert.Equal(t, models.NotificationEventInstanceError, payload.Event)
+	assert.Equal(t, "main", payload.InstanceName, "instance name is resolved from the store")
+	assert.Equal(t, "connection refused", payload.Message)
+}
+
+func TestDispatch_RendersTemplates(t *testing.T) {
+	server, requests := newCaptureServer(t)
+
+	store := &fakeProviderStore{
+		providers: []*models.NotificationProvider{{
+			ID: 1, Name: "ntfy", Type: models.NotificationProviderNtfy, URL: server.URL + "/topic", Enabled: true,
+			TitleTemplate: "[{{ .InstanceName }}] {{ .Title }}",
+			BodyTemplate:  "{{ .Message }} ({{ index .Fields \"runId\" }})",
+			Options:       map[string]string{"tags": "warning"},
+		}},
+		tokens: map[int]string{1: "tk"},
+	}
+
+	newTestService(store).dispatch(context.Background(), Event{
+		Type:       models.NotificationEventBackupFailed,
+		InstanceID: 2,
+		Title:      "Backup failed",
+		Message:    "disk full",
+		Fields:     map[string]string{"runId": "42"},
+	})
+
+	got := requests()
+	require.Len(t, got, 1)
+	assert.Equal(t, "[seedbox] Backup failed", got[0].headers.Get("Title"))
+	assert.Equal(t, "high", got[0].headers.Get("Priority"))
+	assert.Equal(t, "warning", got[0].headers.Get("Tags"))
+	assert.Equal(t, "Bearer tk", got[0].headers.Get("Authorization"))
+	assert.Equal(t, "disk full (42)", string(got[0].body))
+}
+
+func TestSend_ProviderFormats(t *testing.T) {
+	server, requests := newCaptureServer(t)
+	event := Event{Type: models.NotificationEventCrossSeedInjected, Title: "Cross-seed added", Message: "Injected x", Time: time.Now()}
+	msg := renderedMessage{Title: event.Title, Body: event.Message}
+	ctx := context.Background()
+
+	discord := &models.NotificationProvider{Type: models.NotificationProviderDiscord, URL: server.URL + "/discord", Options: map[string]string{"username": "qui"}}
+	require.NoError(t, send(ctx, server.Client(), discord, "", event, msg))
+
+	gotify := &models.NotificationProvider{Type: models.NotificationProviderGotify, URL: server.URL + "/gotify/"}
+	require.NoError(t, send(ctx, server.Client(), gotify, "app-token", event, msg))
+
+	apprise := &models.NotificationProvider{Type: models.NotificationProviderApprise, URL: server.URL + "/notify/qui"}
+	require.NoError(t, send(ctx, server.Client(), apprise, "", event, msg))
+
+	got := requests()
+	require.Len(t, got, 3)
+
+	var discordBody struct {
+		Username string `json:"username"`
+		Embeds   []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"embeds"`
+	}
+	require.NoError(t, json.Unmarshal(got[0].body, &discordBody))
+	assert.Equal(t, "qui", discordBody.Username)
+	require.Len(t, discordBody.Embeds, 1)
+	assert.Equal(t, "Cross-seed added", discordBody.Embeds[0].Title)
+
+	assert.Equal(t, "/gotify/message", got[1].path)
+	assert.Equal(t, "app-token", got[1].headers.Get("X-Gotify-Key"))
+	var gotifyBody map[string]any
+	require.NoError(t, json.Unmarshal(got[1].body, &gotifyBody))
+	assert.Equal(t, "Injected x", gotifyBody["message"])
+	assert.EqualValues(t, defaultGotifyPrio, gotifyBody["priority"])
+
+	var appriseBody map[string]any
+	require.NoError(t, json.Unmarshal(got[2].body, &appriseBody))
+	assert.Equal(t, "info", appriseBody["type"])
+	assert.Equal(t, "Injected x", appriseBody["body"])
+}
+
+func TestSendTest(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "nope", http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	server, requests := newCaptureServer(t)
+
+	store := &fakeProviderStore{providers: []*models.NotificationProvider{
+		// Test sends ignore the enabled flag and subscriptions.
+		{ID: 1, Name: "hook", Type: models.NotificationProviderWebhook, URL: server.URL,
+			Events: []models.NotificationEventType{models.NotificationEventBackupFailed}},
+		{ID: 2, Name: "broken", Type: models.NotificationProviderWebhook, URL: failing.URL, Enabled: true},
+	}}
+	svc := newTestService(store)
+
+	require.NoError(t, svc.SendTest(context.Background(), 1))
+	got := requests()
+	require.Len(t, got, 1)
+	var payload webhookPayload
+	require.NoError(t, json.Unmarshal(got[0].body, &payload))
+	assert.Equal(t, models.NotificationEventTest, payload.Event)
+
+	err := svc.SendTest(context.Background(), 2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	require.ErrorIs(t, svc.SendTest(context.Background(), 99), sql.ErrNoRows)
+}
+
+func TestValidateTemplates(t *testing.T) {
+	require.NoError(t, ValidateTemplates("", ""))
+	require.NoError(t, ValidateTemplates("{{ .Title }}", "{{ .Message }}"))
+	require.ErrorIs(t, ValidateTemplates("{{ .Title", ""), ErrInvalidTemplate)
+	require.ErrorIs(t, ValidateTemplates("", "{{ end }}"), ErrInvalidTemplate)
+}
+
+func TestFromBusEvent(t *testing.T) {
+	_, ok := fromBusEvent(events.Event{Type: events.InstanceUp, Payload: events.InstanceStatus{}})
+	assert.False(t, ok, "recoveries do not notify")
+
+	_, ok = fromBusEvent(events.Event{Type: events.BackupFinished, Payload: events.BackupResult{Success: true}})
+	assert.False(t, ok, "successful backups do not notify")
+
+	_, ok = fromBusEvent(events.Event{Type: events.AutomationRuleApplied, Payload: events.RuleApplied{Action: events.RuleActionPaused}})
+	assert.False(t, ok, "only deletions notify")
+
+	notification, ok := fromBusEvent(events.Event{
+		Type:       events.BackupFinished,
+		InstanceID: 2,
+		Payload:    events.BackupResult{RunID: 7, Kind: "manual", Error: "disk full"},
+	})
+	require.True(t, ok)
+	assert.Equal(t, models.NotificationEventBackupFailed, notification.Type)
+	assert.Equal(t, 2, notification.InstanceID)
+	assert.Equal(t, "7", notification.Fields["runId"])
+	assert.Contains(t, notification.Message, "disk full")
+
+	names := make([]string, 12)
+	hashes := make([]string, 12)
+	for i := range names {
+		names[i] = "torrent"
+		hashes[i] = "hash"
+	}
+	notification, ok = fromBusEvent(events.Event{
+		Type: events.AutomationRuleApplied,
+		Payload: events.RuleApplied{
+			Action:     events.RuleActionDeleted,
+			DeleteMode: models.DeleteModeWithFiles,
+			RuleNames:  []string{"old seeds"},
+			Hashes:     hashes,
+			Names:      names,
+		},
+	})
+	require.True(t, ok)
+	assert.Equal(t, models.NotificationEventAutomationDeleted, notification.Type)
+	assert.Contains(t, notification.Message, "Removed 12 torrent(s) (with files)")
+	assert.Contains(t, notification.Message, "and 2 more")
+	assert.Equal(t, "old seeds", notification.Fields["rules"])
+}
+
+func TestSubscribe_DeliversBusEvents(t *testing.T) {
+	server, requests := newCaptureServer(t)
+	store := &fakeProviderStore{providers: []*models.NotificationProvider{
+		{ID: 1, Name: "hook", Type: models.NotificationProviderWebhook, URL: server.URL, Enabled: true},
+	}}
+
+	bus := events.NewBus()
+	defer bus.Close()
+	newTestService(store).Subscribe(bus)
+
+	bus.Publish(events.Event{Type: events.TorrentAdded, Payload: events.Torrent{Hash: "A"}})
+	bus.Publish(events.Event{
+		Type:       events.CrossSeedInjected,
+		InstanceID: 1,
+		Payload:    events.CrossSeedInjection{TorrentName: "Movie.2024", IndexerName: "TrackerA"},
+	})
+
+	require.Eventually(t, func() bool { return len(requests()) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	var payload webhookPayload
+	require.NoError(t, json.Unmarshal(requests()[0].body, &payload))
+	assert.Equal(t, models.NotificationEventCrossSeedInjected, payload.Event)
+	assert.Equal(t, "Injected Movie.2024 from TrackerA", payload.Message)
+	assert.Equal(t, "main", payload.InstanceName)
+}
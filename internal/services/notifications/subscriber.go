@@ -0,0 +1,119 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/qui/internal/events"
+	"github.com/autobrr/qui/internal/models"
+)
+
+const maxListedTorrents = 10
+
+// Subscribe delivers notifications for bus events that map to a notification
+// event type. The returned function unsubscribes.
+func (s *Service) Subscribe(bus *events.Bus) func() {
+	return bus.Subscribe("notifications", func(ctx context.Context, event events.Event) {
+		notification, ok := fromBusEvent(event)
+		if !ok {
+			return
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+		s.dispatch(sendCtx, notification)
+	},
+		events.InstanceDown,
+		events.CrossSeedInjected,
+		events.AutomationRuleApplied,
+		events.BackupFinished,
+		events.OrphanScanPreviewReady,
+	)
+}
+
+// fromBusEvent translates a bus event into a notification. It reports false for
+// events that should not notify.
+func fromBusEvent(event events.Event) (Event, bool) {
+	notification := Event{InstanceID: event.InstanceID, Time: event.Time}
+
+	switch payload := event.Payload.(type) {
+	case events.InstanceStatus:
+		if event.Type != events.InstanceDown {
+			return Event{}, false
+		}
+		notification.Type = models.NotificationEventInstanceError
+		notification.Title = "qBittorrent instance unreachable"
+		notification.Message = payload.Error
+
+	case events.CrossSeedInjection:
+		notification.Type = models.NotificationEventCrossSeedInjected
+		notification.InstanceName = payload.InstanceName
+		notification.Title = "Cross-seed added"
+		notification.Message = "Injected " + payload.TorrentName
+		notification.Fields = map[string]string{"torrent": payload.TorrentName}
+		if payload.IndexerName != "" {
+			notification.Message += " from " + payload.IndexerName
+			notification.Fields["indexer"] = payload.IndexerName
+		}
+		if payload.MatchedHash != "" {
+			notification.Fields["matchedHash"] = payload.MatchedHash
+			notification.Fields["matchedName"] = payload.MatchedName
+		}
+
+	case events.RuleApplied:
+		if payload.Action != events.RuleActionDeleted {
+			return Event{}, false
+		}
+		filesNote := "files kept"
+		if payload.DeleteMode != models.DeleteModeKeepFiles {
+			filesNote = "with files"
+		}
+		listed := payload.Names
+		if len(listed) > maxListedTorrents {
+			listed = listed[:maxListedTorrents]
+		}
+		notification.Type = models.NotificationEventAutomationDeleted
+		notification.Title = "Automation removed torrents"
+		notification.Message = fmt.Sprintf("Removed %d torrent(s) (%s): %s", len(payload.Hashes), filesNote, strings.Join(listed, ", "))
+		if len(payload.Names) > maxListedTorrents {
+			notification.Message += fmt.Sprintf(" and %d more", len(payload.Names)-maxListedTorrents)
+		}
+		notification.Fields = map[string]string{
+			"count": strconv.Itoa(len(payload.Hashes)),
+			"mode":  payload.DeleteMode,
+			"rules": strings.Join(payload.RuleNames, ", "),
+		}
+
+	case events.BackupResult:
+		if payload.Success {
+			return Event{}, false
+		}
+		notification.Type = models.NotificationEventBackupFailed
+		notification.Title = "Backup failed"
+		notification.Message = fmt.Sprintf("%s backup run %d failed: %s", payload.Kind, payload.RunID, payload.Error)
+		notification.Fields = map[string]string{
+			"runId": strconv.FormatInt(payload.RunID, 10),
+			"kind":  payload.Kind,
+		}
+
+	case events.OrphanScanPreview:
+		notification.Type = models.NotificationEventOrphanScanPreviewReady
+		notification.Title = "Orphan scan preview ready"
+		notification.Message = fmt.Sprintf("Found %d orphan file(s) totalling %d bytes awaiting review", payload.Files, payload.Bytes)
+		notification.Fields = map[string]string{
+			"runId":     strconv.FormatInt(payload.RunID, 10),
+			"files":     strconv.Itoa(payload.Files),
+			"bytes":     strconv.FormatInt(payload.Bytes, 10),
+			"truncated": strconv.FormatBool(payload.Truncated),
+		}
+
+	default:
+		return Event{}, false
+	}
+
+	return notification, true
+}
@@ -29,12 +29,17 @@ func DefaultConfig() Config {
 // DefaultSettings returns default settings for a new instance.
 func DefaultSettings() Settings {
 	return Settings{
-		Enabled:             false,
-		GracePeriodMinutes:  10,
-		IgnorePaths:         []string{},
-		ScanIntervalHours:   24,
-		MaxFilesPerRun:      10000,
-		AutoCleanupEnabled:  false,
-		AutoCleanupMaxFiles: 100,
+		Enabled:                        false,
+		GracePeriodMinutes:             10,
+		IgnorePaths:                    []string{},
+		ScanIntervalHours:              24,
+		MaxFilesPerRun:                 10000,
+		AutoCleanupEnabled:             false,
+		AutoCleanupMaxFiles:            100,
+		AutoCleanupUnique:              true,
+		AutoCleanupHardlinkedOrphans:   true,
+		AutoCleanupHardlinkedElsewhere: false,
+		QuarantineEnabled:              false,
+		QuarantineRetentionDays:        7,
 	}
 }
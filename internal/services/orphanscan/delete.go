@@ -17,26 +17,35 @@ const (
 	deleteDispositionDeleted deleteDisposition = iota
 	deleteDispositionSkippedInUse
 	deleteDispositionSkippedMissing
+	deleteDispositionQuarantined
 )
 
-// safeDeleteFile removes a single file with safety checks.
-// Re-checks TorrentFileMap before deletion to handle torrents added since scan.
-// Never removes directories.
-func safeDeleteFile(scanRoot, target string, tfm *TorrentFileMap) (deleteDisposition, error) {
+// checkWithinScanRoot verifies target is an absolute path strictly inside scanRoot.
+func checkWithinScanRoot(scanRoot, target string) error {
 	// Must be absolute
 	if !filepath.IsAbs(target) {
-		return 0, fmt.Errorf("refusing non-absolute path: %s", target)
+		return fmt.Errorf("refusing non-absolute path: %s", target)
 	}
 
 	// Must not be the scan root itself
 	if filepath.Clean(target) == filepath.Clean(scanRoot) {
-		return 0, fmt.Errorf("refusing to delete scan root: %s", scanRoot)
+		return fmt.Errorf("refusing to delete scan root: %s", scanRoot)
 	}
 
 	// Must be within scan root (no path traversal)
 	rel, err := filepath.Rel(scanRoot, target)
 	if err != nil || strings.HasPrefix(rel, "..") {
-		return 0, fmt.Errorf("path escapes scan root: %s", target)
+		return fmt.Errorf("path escapes scan root: %s", target)
+	}
+	return nil
+}
+
+// safeDeleteFile removes a single file with safety checks.
+// Re-checks TorrentFileMap before deletion to handle torrents added since scan.
+// Never removes directories.
+func safeDeleteFile(scanRoot, target string, tfm *TorrentFileMap) (deleteDisposition, error) {
+	if err := checkWithinScanRoot(scanRoot, target); err != nil {
+		return 0, err
 	}
 
 	// Re-check: torrent may have been added since scan (skip)
@@ -67,24 +76,12 @@ func safeDeleteFile(scanRoot, target string, tfm *TorrentFileMap) (deleteDisposi
 
 // safeDeleteEmptyDir removes a directory only if empty. Never recursive.
 func safeDeleteEmptyDir(scanRoot, target string) error {
-	// Must be absolute
-	if !filepath.IsAbs(target) {
-		return fmt.Errorf("refusing non-absolute path: %s", target)
-	}
-
-	// Must not be the scan root itself
-	if filepath.Clean(target) == filepath.Clean(scanRoot) {
-		return fmt.Errorf("refusing to delete scan root: %s", scanRoot)
-	}
-
-	// Must be within scan root (no path traversal)
-	rel, err := filepath.Rel(scanRoot, target)
-	if err != nil || strings.HasPrefix(rel, "..") {
-		return fmt.Errorf("path escapes scan root: %s", target)
+	if err := checkWithinScanRoot(scanRoot, target); err != nil {
+		return err
 	}
 
 	// os.Remove on a directory only succeeds if it's empty
-	err = os.Remove(target)
+	err := os.Remove(target)
 	if os.IsNotExist(err) {
 		return nil // Already gone
 	}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+
+	"github.com/autobrr/qui/internal/models"
 )
 
 // TorrentFileMap is a thread-safe set of file paths belonging to torrents.
@@ -50,6 +52,27 @@ func normalizePath(path string) string {
 	return filepath.Clean(path)
 }
 
+// mapPath rewrites path using the longest matching mapping prefix.
+// Prefixes only match at path boundaries, so /data does not match /database.
+func mapPath(path string, mappings []models.OrphanScanPathMapping) string {
+	best := -1
+	for i, m := range mappings {
+		from := filepath.Clean(m.From)
+		if path != from && !strings.HasPrefix(path, from+string(filepath.Separator)) {
+			continue
+		}
+		if best < 0 || len(from) > len(filepath.Clean(mappings[best].From)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return path
+	}
+
+	from := filepath.Clean(mappings[best].From)
+	return filepath.Join(filepath.Clean(mappings[best].To), strings.TrimPrefix(path, from))
+}
+
 // canonicalizeHash matches SyncManager's internal hash normalization.
 func canonicalizeHash(hash string) string {
 	return strings.ToLower(strings.TrimSpace(hash))
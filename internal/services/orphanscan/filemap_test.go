@@ -0,0 +1,40 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package orphanscan
+
+import (
+	"testing"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestMapPath(t *testing.T) {
+	t.Parallel()
+
+	mappings := []models.OrphanScanPathMapping{
+		{From: "/downloads", To: "/mnt/shared"},
+		{From: "/downloads/tv", To: "/mnt/tv"},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "prefix", path: "/downloads/movies/a.mkv", want: "/mnt/shared/movies/a.mkv"},
+		{name: "exact", path: "/downloads", want: "/mnt/shared"},
+		{name: "longest prefix wins", path: "/downloads/tv/show", want: "/mnt/tv/show"},
+		{name: "boundary", path: "/downloads-old/a.mkv", want: "/downloads-old/a.mkv"},
+		{name: "unmapped", path: "/data/a.mkv", want: "/data/a.mkv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := mapPath(tt.path, mappings); got != tt.want {
+				t.Fatalf("mapPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package orphanscan
+
+import (
+	"os"
+
+	"github.com/autobrr/qui/pkg/hardlink"
+)
+
+// classifyOrphans sets the LinkClass of each orphan and returns the bytes deleting all
+// of them would free. Data shared by several orphans is counted once, and data still
+// linked from outside the orphan set (media libraries, torrents) is not counted at all.
+// Orphans without link information are treated as unique.
+func classifyOrphans(orphans []OrphanFile) int64 {
+	// How many of the found orphans point at each physical file
+	orphanLinks := make(map[string]uint64)
+	for _, o := range orphans {
+		if o.fileID != "" && o.nlink > 1 {
+			orphanLinks[o.fileID]++
+		}
+	}
+
+	var reclaimable int64
+	counted := make(map[string]struct{})
+	for i := range orphans {
+		o := &orphans[i]
+		switch {
+		case o.fileID == "" || o.nlink <= 1:
+			o.LinkClass = LinkClassUnique
+			reclaimable += o.Size
+		case orphanLinks[o.fileID] >= o.nlink:
+			o.LinkClass = LinkClassHardlinkedOrphan
+			if _, ok := counted[o.fileID]; !ok {
+				counted[o.fileID] = struct{}{}
+				reclaimable += o.Size
+			}
+		default:
+			o.LinkClass = LinkClassHardlinkedElsewhere
+		}
+	}
+	return reclaimable
+}
+
+// fileLinkInfo returns the physical file identifier and link count for a regular file.
+// Returns an empty identifier when the information is unavailable.
+func fileLinkInfo(info os.FileInfo, path string) (string, uint64) {
+	if !info.Mode().IsRegular() {
+		return "", 0
+	}
+	fileID, nlink, err := hardlink.LinkInfo(info, path)
+	if err != nil {
+		return "", 0
+	}
+	return fileID, nlink
+}
+
+// bytesFreedByRemoving returns size if removing path drops the last link to its data,
+// and zero when other links keep the data alive.
+func bytesFreedByRemoving(path string, size int64) int64 {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0
+	}
+	if _, nlink := fileLinkInfo(info, path); nlink > 1 {
+		return 0
+	}
+	return size
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package orphanscan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func linkTestFile(t *testing.T, oldname, newname string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(newname), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Link(oldname, newname); err != nil {
+		t.Skipf("hardlinks not supported: %v", err)
+	}
+}
+
+func TestClassifyOrphans_Walk(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	root := filepath.Join(base, "downloads")
+	library := filepath.Join(base, "library")
+
+	unique := filepath.Join(root, "unique.mkv")
+	pairA := filepath.Join(root, "pair", "a.mkv")
+	pairB := filepath.Join(root, "pair", "b.mkv")
+	imported := filepath.Join(root, "imported.mkv")
+	writeTestFile(t, unique)
+	writeTestFile(t, pairA)
+	linkTestFile(t, pairA, pairB)
+	writeTestFile(t, imported)
+	linkTestFile(t, imported, filepath.Join(library, "imported.mkv"))
+
+	orphans, truncated, err := walkScanRoot(context.Background(), root, NewTorrentFileMap(), nil, 0, 100)
+	if err != nil {
+		t.Fatalf("walkScanRoot error: %v", err)
+	}
+	if truncated {
+		t.Fatalf("expected walk not to be truncated")
+	}
+
+	reclaimable := classifyOrphans(orphans)
+
+	want := map[string]LinkClass{
+		unique:   LinkClassUnique,
+		pairA:    LinkClassHardlinkedOrphan,
+		pairB:    LinkClassHardlinkedOrphan,
+		imported: LinkClassHardlinkedElsewhere,
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("expected %d orphans, got %d", len(want), len(orphans))
+	}
+	for _, o := range orphans {
+		if o.LinkClass != want[o.Path] {
+			t.Fatalf("%s: expected class %q, got %q", o.Path, want[o.Path], o.LinkClass)
+		}
+	}
+
+	// unique.mkv plus one copy of the pair; imported.mkv stays in the library
+	if reclaimable != 8 {
+		t.Fatalf("expected 8 reclaimable bytes, got %d", reclaimable)
+	}
+}
+
+func TestClassifyOrphans_NoLinkInfo(t *testing.T) {
+	t.Parallel()
+
+	orphans := []OrphanFile{{Path: "/data/a", Size: 10}, {Path: "/data/b", Size: 5}}
+	if got := classifyOrphans(orphans); got != 15 {
+		t.Fatalf("expected 15 reclaimable bytes, got %d", got)
+	}
+	for _, o := range orphans {
+		if o.LinkClass != LinkClassUnique {
+			t.Fatalf("%s: expected unique class, got %q", o.Path, o.LinkClass)
+		}
+	}
+}
+
+func TestBytesFreedByRemoving(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.mkv")
+	second := filepath.Join(dir, "second.mkv")
+	writeTestFile(t, first)
+	linkTestFile(t, first, second)
+
+	if got := bytesFreedByRemoving(first, 4); got != 0 {
+		t.Fatalf("expected no bytes freed while linked, got %d", got)
+	}
+	if err := os.Remove(second); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if got := bytesFreedByRemoving(first, 4); got != 4 {
+		t.Fatalf("expected 4 bytes freed for last link, got %d", got)
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package orphanscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// quarantineDirName is the directory created inside each scan root to hold
+// quarantined orphans. The walker never descends into it.
+const quarantineDirName = ".qui-quarantine"
+
+// quarantineRunDir returns the quarantine directory for a run within a scan root.
+func quarantineRunDir(scanRoot string, runID int64) string {
+	return filepath.Join(scanRoot, quarantineDirName, strconv.FormatInt(runID, 10))
+}
+
+// safeQuarantineFile moves a single orphan into the run's quarantine directory,
+// preserving its path relative to the scan root. It applies the same safety checks
+// as safeDeleteFile and only ever renames, so the quarantine must be on the same
+// filesystem as the file. Returns the quarantine path on success.
+func safeQuarantineFile(scanRoot string, runID int64, target string, tfm *TorrentFileMap) (deleteDisposition, string, error) {
+	if err := checkWithinScanRoot(scanRoot, target); err != nil {
+		return 0, "", err
+	}
+
+	// Re-check: torrent may have been added since scan (skip)
+	if tfm.Has(normalizePath(target)) {
+		return deleteDispositionSkippedInUse, "", nil
+	}
+
+	info, err := os.Lstat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return deleteDispositionSkippedMissing, "", nil
+		}
+		return 0, "", err
+	}
+	if info.IsDir() {
+		return 0, "", fmt.Errorf("refusing to quarantine directory as file: %s", target)
+	}
+
+	rel, err := filepath.Rel(scanRoot, target)
+	if err != nil {
+		return 0, "", err
+	}
+	dest := filepath.Join(quarantineRunDir(scanRoot, runID), rel)
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return 0, "", err
+	}
+	if err := os.Rename(target, dest); err != nil {
+		if os.IsNotExist(err) {
+			return deleteDispositionSkippedMissing, "", nil
+		}
+		if errors.Is(err, syscall.EXDEV) {
+			return 0, "", fmt.Errorf("quarantine directory is on a different filesystem: %w", err)
+		}
+		return 0, "", err
+	}
+	return deleteDispositionQuarantined, dest, nil
+}
+
+// checkInQuarantine verifies path lives inside the scan root's quarantine directory.
+func checkInQuarantine(scanRoot, path string) error {
+	quarantineRoot := filepath.Join(scanRoot, quarantineDirName)
+	if err := checkWithinScanRoot(quarantineRoot, path); err != nil {
+		return fmt.Errorf("path is not in quarantine: %s", path)
+	}
+	return nil
+}
+
+// restoreQuarantinedFile moves a quarantined file back to its original location,
+// recreating parent directories removed by the empty-directory cleanup. It never
+// overwrites an existing file.
+func restoreQuarantinedFile(scanRoot, quarantinePath, originalPath string) error {
+	if err := checkInQuarantine(scanRoot, quarantinePath); err != nil {
+		return err
+	}
+	if err := checkWithinScanRoot(scanRoot, originalPath); err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(originalPath); err == nil {
+		return ErrRestoreTargetExists
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(originalPath), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(quarantinePath, originalPath)
+}
+
+// purgeQuarantinedFile permanently removes a quarantined file.
+// A file that is already gone counts as purged.
+func purgeQuarantinedFile(scanRoot, quarantinePath string) error {
+	if err := checkInQuarantine(scanRoot, quarantinePath); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(quarantinePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("refusing to purge directory as file: %s", quarantinePath)
+	}
+
+	if err := os.Remove(quarantinePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// cleanupQuarantineDir removes empty directories left in a run's quarantine
+// directory, and the quarantine root itself once nothing remains in it.
+func cleanupQuarantineDir(scanRoot string, runID int64) {
+	runDir := quarantineRunDir(scanRoot, runID)
+
+	var dirs []string
+	_ = filepath.WalkDir(runDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+
+	// Deepest first so parents are empty by the time they're reached
+	sort.Slice(dirs, func(i, j int) bool {
+		return len(dirs[i]) > len(dirs[j])
+	})
+	for _, dir := range dirs {
+		_ = os.Remove(dir)
+	}
+	_ = os.Remove(filepath.Join(scanRoot, quarantineDirName))
+}
+
+// RestoreResult summarizes a quarantine restore.
+type RestoreResult struct {
+	Restored int `json:"restored"`
+	Failed   int `json:"failed"`
+}
+
+// RestoreRun moves every quarantined file of a run back to its original location.
+// Files whose original path is occupied are left in quarantine and counted as failed.
+func (s *Service) RestoreRun(ctx context.Context, instanceID int, runID int64) (RestoreResult, error) {
+	run, err := s.store.GetRunByInstance(ctx, instanceID, runID)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	if run == nil {
+		return RestoreResult{}, ErrRunNotFound
+	}
+
+	mu := s.getInstanceMutex(instanceID)
+	if !mu.TryLock() {
+		return RestoreResult{}, ErrScanInProgress
+	}
+	defer mu.Unlock()
+
+	files, err := s.store.GetFilesByStatus(ctx, runID, string(FileStatusQuarantined))
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	var result RestoreResult
+	var bytesRestored int64
+	for _, f := range files {
+		if err := s.restoreFile(ctx, run, f); err != nil {
+			log.Warn().Err(err).Str("path", f.FilePath).Msg("orphanscan: failed to restore quarantined file")
+			result.Failed++
+			continue
+		}
+		result.Restored++
+		bytesRestored += f.FileSize
+	}
+
+	if result.Restored > 0 {
+		if err := s.store.RecordQuarantineRestored(ctx, runID, result.Restored, bytesRestored); err != nil {
+			log.Error().Err(err).Int64("run", runID).Msg("orphanscan: failed to update quarantine stats")
+		}
+	}
+	for _, root := range run.ScanPaths {
+		cleanupQuarantineDir(root, runID)
+	}
+
+	log.Info().
+		Int64("run", runID).
+		Int("restored", result.Restored).
+		Int("failed", result.Failed).
+		Msg("orphanscan: restored quarantined files")
+
+	return result, nil
+}
+
+// RestoreFile moves a single quarantined file back to its original location.
+func (s *Service) RestoreFile(ctx context.Context, instanceID int, runID, fileID int64) error {
+	run, err := s.store.GetRunByInstance(ctx, instanceID, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return ErrRunNotFound
+	}
+
+	mu := s.getInstanceMutex(instanceID)
+	if !mu.TryLock() {
+		return ErrScanInProgress
+	}
+	defer mu.Unlock()
+
+	f, err := s.store.GetFile(ctx, runID, fileID)
+	if err != nil {
+		return err
+	}
+	if f == nil {
+		return ErrFileNotFound
+	}
+	if f.Status != string(FileStatusQuarantined) {
+		return ErrFileNotQuarantined
+	}
+
+	if err := s.restoreFile(ctx, run, f); err != nil {
+		return err
+	}
+	if err := s.store.RecordQuarantineRestored(ctx, runID, 1, f.FileSize); err != nil {
+		log.Error().Err(err).Int64("run", runID).Msg("orphanscan: failed to update quarantine stats")
+	}
+	if scanRoot := findScanRoot(f.FilePath, run.ScanPaths); scanRoot != "" {
+		cleanupQuarantineDir(scanRoot, runID)
+	}
+	return nil
+}
+
+func (s *Service) restoreFile(ctx context.Context, run *models.OrphanScanRun, f *models.OrphanScanFile) error {
+	scanRoot := findScanRoot(f.FilePath, run.ScanPaths)
+	if scanRoot == "" {
+		return errors.New("no matching scan root")
+	}
+	if err := restoreQuarantinedFile(scanRoot, f.QuarantinePath, f.FilePath); err != nil {
+		return err
+	}
+	s.updateFileStatus(ctx, f.ID, string(FileStatusRestored), "")
+	return nil
+}
+
+// purgeExpiredQuarantine permanently deletes quarantined files whose retention has elapsed.
+func (s *Service) purgeExpiredQuarantine(ctx context.Context) {
+	instances, err := s.instanceStore.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("orphanscan: failed to list instances for quarantine purge")
+		return
+	}
+
+	for _, inst := range instances {
+		if ctx.Err() != nil {
+			return
+		}
+
+		retentionDays := DefaultSettings().QuarantineRetentionDays
+		settings, err := s.store.GetSettings(ctx, inst.ID)
+		if err != nil {
+			continue
+		}
+		if settings != nil && settings.QuarantineRetentionDays > 0 {
+			retentionDays = settings.QuarantineRetentionDays
+		}
+
+		cutoff := time.Now().Add(-time.Duration(retentionDays) * 24 * time.Hour)
+		runs, err := s.store.ListQuarantinedRuns(ctx, inst.ID, cutoff)
+		if err != nil {
+			log.Error().Err(err).Int("instance", inst.ID).Msg("orphanscan: failed to list quarantined runs")
+			continue
+		}
+		if len(runs) == 0 {
+			continue
+		}
+
+		// Skip while a deletion or restore holds the instance; the next tick retries
+		mu := s.getInstanceMutex(inst.ID)
+		if !mu.TryLock() {
+			continue
+		}
+		for _, run := range runs {
+			s.purgeRun(ctx, run)
+		}
+		mu.Unlock()
+	}
+}
+
+func (s *Service) purgeRun(ctx context.Context, run *models.OrphanScanRun) {
+	files, err := s.store.GetFilesByStatus(ctx, run.ID, string(FileStatusQuarantined))
+	if err != nil {
+		log.Error().Err(err).Int64("run", run.ID).Msg("orphanscan: failed to list quarantined files")
+		return
+	}
+
+	var filesPurged int
+	var bytesPurged, bytesFreed int64
+	for _, f := range files {
+		scanRoot := findScanRoot(f.FilePath, run.ScanPaths)
+		if scanRoot == "" {
+			continue
+		}
+		freed := bytesFreedByRemoving(f.QuarantinePath, f.FileSize)
+		if err := purgeQuarantinedFile(scanRoot, f.QuarantinePath); err != nil {
+			log.Warn().Err(err).Str("path", f.QuarantinePath).Msg("orphanscan: failed to purge quarantined file")
+			continue
+		}
+		s.updateFileStatus(ctx, f.ID, string(FileStatusDeleted), "")
+		filesPurged++
+		bytesPurged += f.FileSize
+		bytesFreed += freed
+	}
+
+	if filesPurged > 0 {
+		if err := s.store.RecordQuarantinePurged(ctx, run.ID, filesPurged, bytesPurged, bytesFreed); err != nil {
+			log.Error().Err(err).Int64("run", run.ID).Msg("orphanscan: failed to update quarantine stats")
+		}
+	}
+	for _, root := range run.ScanPaths {
+		cleanupQuarantineDir(root, run.ID)
+	}
+
+	log.Info().
+		Int64("run", run.ID).
+		Int("filesPurged", filesPurged).
+		Int64("bytesReclaimed", bytesFreed).
+		Msg("orphanscan: purged expired quarantine")
+}
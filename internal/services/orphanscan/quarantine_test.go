@@ -0,0 +1,148 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package orphanscan
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
+
+func TestSafeQuarantineFile_RestoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	target := filepath.Join(root, "Show", "episode.mkv")
+	writeTestFile(t, target)
+
+	disp, dest, err := safeQuarantineFile(root, 42, target, NewTorrentFileMap())
+	if err != nil {
+		t.Fatalf("safeQuarantineFile error: %v", err)
+	}
+	if disp != deleteDispositionQuarantined {
+		t.Fatalf("expected quarantined disposition, got %v", disp)
+	}
+	wantDest := filepath.Join(root, quarantineDirName, "42", "Show", "episode.mkv")
+	if dest != wantDest {
+		t.Fatalf("expected quarantine path %s, got %s", wantDest, dest)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("expected original moved, stat err=%v", err)
+	}
+
+	// Simulate the empty-directory cleanup that follows deletion
+	if err := os.Remove(filepath.Dir(target)); err != nil {
+		t.Fatalf("remove parent: %v", err)
+	}
+
+	if err := restoreQuarantinedFile(root, dest, target); err != nil {
+		t.Fatalf("restoreQuarantinedFile error: %v", err)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Fatalf("expected file restored, stat err=%v", err)
+	}
+
+	cleanupQuarantineDir(root, 42)
+	if _, err := os.Stat(filepath.Join(root, quarantineDirName)); !os.IsNotExist(err) {
+		t.Fatalf("expected empty quarantine dir removed, stat err=%v", err)
+	}
+}
+
+func TestSafeQuarantineFile_SkipsWhenInUse(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	target := filepath.Join(root, "movie.mkv")
+	writeTestFile(t, target)
+
+	tfm := NewTorrentFileMap()
+	tfm.Add(normalizePath(target))
+
+	disp, _, err := safeQuarantineFile(root, 1, target, tfm)
+	if err != nil {
+		t.Fatalf("safeQuarantineFile error: %v", err)
+	}
+	if disp != deleteDispositionSkippedInUse {
+		t.Fatalf("expected skipped-in-use disposition, got %v", disp)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Fatalf("expected file to remain, stat err=%v", err)
+	}
+}
+
+func TestRestoreQuarantinedFile_RefusesOverwrite(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	target := filepath.Join(root, "movie.mkv")
+	writeTestFile(t, target)
+
+	_, dest, err := safeQuarantineFile(root, 1, target, NewTorrentFileMap())
+	if err != nil {
+		t.Fatalf("safeQuarantineFile error: %v", err)
+	}
+	writeTestFile(t, target)
+
+	if err := restoreQuarantinedFile(root, dest, target); !errors.Is(err, ErrRestoreTargetExists) {
+		t.Fatalf("expected ErrRestoreTargetExists, got %v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Fatalf("expected quarantined file kept, stat err=%v", err)
+	}
+}
+
+func TestPurgeQuarantinedFile_RefusesOutsideQuarantine(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	target := filepath.Join(root, "movie.mkv")
+	writeTestFile(t, target)
+
+	if err := purgeQuarantinedFile(root, target); err == nil {
+		t.Fatalf("expected error purging file outside quarantine")
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Fatalf("expected file to remain, stat err=%v", err)
+	}
+
+	_, dest, err := safeQuarantineFile(root, 1, target, NewTorrentFileMap())
+	if err != nil {
+		t.Fatalf("safeQuarantineFile error: %v", err)
+	}
+	if err := purgeQuarantinedFile(root, dest); err != nil {
+		t.Fatalf("purgeQuarantinedFile error: %v", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Fatalf("expected quarantined file purged, stat err=%v", err)
+	}
+}
+
+func TestWalkScanRoot_SkipsQuarantine(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, quarantineDirName, "1", "old.mkv"))
+	orphan := filepath.Join(root, "orphan.mkv")
+	writeTestFile(t, orphan)
+
+	orphans, _, err := walkScanRoot(context.Background(), root, NewTorrentFileMap(), nil, 0, 100)
+	if err != nil {
+		t.Fatalf("walkScanRoot error: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].Path != orphan {
+		t.Fatalf("expected only %s, got %+v", orphan, orphans)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	qbt "github.com/autobrr/go-qbittorrent"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
 )
@@ -29,6 +30,7 @@ type Service struct {
 	instanceStore *models.InstanceStore
 	store         *models.OrphanScanStore
 	syncManager   *qbittorrent.SyncManager
+	eventBus      *events.Bus
 
 	// Per-instance mutex to prevent overlapping scans
 	instanceMu map[int]*sync.Mutex
@@ -73,6 +75,7 @@ func (s *Service) loop(ctx context.Context) {
 	if err := s.recoverStuckRuns(ctx); err != nil {
 		log.Error().Err(err).Msg("orphanscan: failed to recover stuck runs")
 	}
+	s.purgeExpiredQuarantine(ctx)
 
 	ticker := time.NewTicker(s.cfg.SchedulerInterval)
 	defer ticker.Stop()
@@ -83,6 +86,7 @@ func (s *Service) loop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			s.checkScheduledScans(ctx)
+			s.purgeExpiredQuarantine(ctx)
 		}
 	}
 }
@@ -317,19 +321,24 @@ func (s *Service) executeScan(ctx context.Context, instanceID int, runID int64)
 	if settings == nil {
 		defaults := DefaultSettings()
 		settings = &models.OrphanScanSettings{
-			InstanceID:          instanceID,
-			Enabled:             defaults.Enabled,
-			GracePeriodMinutes:  defaults.GracePeriodMinutes,
-			IgnorePaths:         defaults.IgnorePaths,
-			ScanIntervalHours:   defaults.ScanIntervalHours,
-			MaxFilesPerRun:      defaults.MaxFilesPerRun,
-			AutoCleanupEnabled:  defaults.AutoCleanupEnabled,
-			AutoCleanupMaxFiles: defaults.AutoCleanupMaxFiles,
+			InstanceID:                     instanceID,
+			Enabled:                        defaults.Enabled,
+			GracePeriodMinutes:             defaults.GracePeriodMinutes,
+			IgnorePaths:                    defaults.IgnorePaths,
+			ScanIntervalHours:              defaults.ScanIntervalHours,
+			MaxFilesPerRun:                 defaults.MaxFilesPerRun,
+			AutoCleanupEnabled:             defaults.AutoCleanupEnabled,
+			AutoCleanupMaxFiles:            defaults.AutoCleanupMaxFiles,
+			AutoCleanupUnique:              defaults.AutoCleanupUnique,
+			AutoCleanupHardlinkedOrphans:   defaults.AutoCleanupHardlinkedOrphans,
+			AutoCleanupHardlinkedElsewhere: defaults.AutoCleanupHardlinkedElsewhere,
+			QuarantineEnabled:              defaults.QuarantineEnabled,
+			QuarantineRetentionDays:        defaults.QuarantineRetentionDays,
 		}
 	}
 
 	// Build file map
-	tfm, scanRoots, err := s.buildFileMap(ctx, instanceID)
+	tfm, scanRoots, err := s.buildFileMap(ctx, instanceID, settings.SharedInstances)
 	if err != nil {
 		// Check if this was a cancellation - preserve canceled status instead of marking failed
 		if ctx.Err() != nil {
@@ -416,6 +425,8 @@ func (s *Service) executeScan(ctx context.Context, instanceID int, runID int64)
 		s.warnRun(ctx, runID, warnMsg)
 	}
 
+	bytesReclaimable := classifyOrphans(allOrphans)
+
 	log.Info().Int("orphans", len(allOrphans)).Bool("truncated", truncated).Msg("orphanscan: scan complete")
 
 	// Convert to model files
@@ -427,6 +438,7 @@ func (s *Service) executeScan(ctx context.Context, instanceID int, runID int64)
 			FileSize:   o.Size,
 			ModifiedAt: &modTime,
 			Status:     "pending",
+			LinkClass:  string(o.LinkClass),
 		}
 	}
 
@@ -445,6 +457,9 @@ func (s *Service) executeScan(ctx context.Context, instanceID int, runID int64)
 	if err := s.store.UpdateRunFoundStats(ctx, runID, len(allOrphans), truncated, bytesFound); err != nil {
 		log.Error().Err(err).Msg("orphanscan: failed to update files found")
 	}
+	if err := s.store.UpdateRunReclaimable(ctx, runID, bytesReclaimable); err != nil {
+		log.Error().Err(err).Msg("orphanscan: failed to update reclaimable bytes")
+	}
 
 	// If no orphans found, mark as completed (clean) instead of preview_ready
 	if len(allOrphans) == 0 {
@@ -472,16 +487,34 @@ func (s *Service) executeScan(ctx context.Context, instanceID int, runID int64)
 
 	log.Info().Int64("run", runID).Int("files", len(allOrphans)).Msg("orphanscan: preview ready")
 
+	s.eventBus.Publish(events.Event{
+		Type:       events.OrphanScanPreviewReady,
+		InstanceID: instanceID,
+		Payload: events.OrphanScanPreview{
+			RunID:     runID,
+			Files:     len(allOrphans),
+			Bytes:     bytesFound,
+			Truncated: truncated,
+		},
+	})
+
 	// Check if auto-cleanup should be triggered for scheduled scans
-	s.maybeAutoCleanup(ctx, instanceID, runID, settings, len(allOrphans))
+	s.maybeAutoCleanup(ctx, instanceID, runID, settings)
+}
+
+// SetEventBus sets the bus scans awaiting review are published to.
+func (s *Service) SetEventBus(bus *events.Bus) {
+	s.eventBus = bus
 }
 
 // maybeAutoCleanup checks if auto-cleanup should be triggered for a scheduled scan.
 // Auto-cleanup is only performed when:
 // 1. The scan was triggered by the scheduler (not manual)
 // 2. AutoCleanupEnabled is true in settings
-// 3. The number of files found is <= AutoCleanupMaxFiles threshold
-func (s *Service) maybeAutoCleanup(ctx context.Context, instanceID int, runID int64, settings *models.OrphanScanSettings, filesFound int) {
+// 3. At least one file belongs to a link class allowed by the per-class policy
+// 4. The number of files to delete is <= AutoCleanupMaxFiles threshold
+// Files in disallowed classes are marked skipped so only eligible files are removed.
+func (s *Service) maybeAutoCleanup(ctx context.Context, instanceID int, runID int64, settings *models.OrphanScanSettings) {
 	// Get the run to check how it was triggered
 	run, err := s.store.GetRun(ctx, runID)
 	if err != nil || run == nil {
@@ -499,6 +532,31 @@ func (s *Service) maybeAutoCleanup(ctx context.Context, instanceID int, runID in
 		return
 	}
 
+	counts, err := s.store.CountPendingFilesByLinkClass(ctx, runID)
+	if err != nil {
+		log.Error().Err(err).Int64("run", runID).Msg("orphanscan: failed to count files for auto-cleanup")
+		return
+	}
+
+	allowed := map[LinkClass]bool{
+		LinkClassUnique:              settings.AutoCleanupUnique,
+		LinkClassHardlinkedOrphan:    settings.AutoCleanupHardlinkedOrphans,
+		LinkClassHardlinkedElsewhere: settings.AutoCleanupHardlinkedElsewhere,
+	}
+	filesFound := 0
+	var excluded []string
+	for class, count := range counts {
+		if allowed[LinkClass(class)] {
+			filesFound += count
+		} else {
+			excluded = append(excluded, class)
+		}
+	}
+	if filesFound == 0 {
+		log.Info().Int64("run", runID).Msg("orphanscan: skipping auto-cleanup (no files allowed by link class policy)")
+		return
+	}
+
 	// Check file count threshold (safety check for anomalies)
 	maxFiles := settings.AutoCleanupMaxFiles
 	if maxFiles <= 0 {
@@ -513,9 +571,15 @@ func (s *Service) maybeAutoCleanup(ctx context.Context, instanceID int, runID in
 		return
 	}
 
+	if err := s.store.SkipPendingFilesByLinkClass(ctx, runID, excluded, "kept by auto-cleanup hardlink policy"); err != nil {
+		log.Error().Err(err).Int64("run", runID).Msg("orphanscan: failed to exclude files from auto-cleanup")
+		return
+	}
+
 	log.Info().
 		Int64("run", runID).
 		Int("filesFound", filesFound).
+		Strs("excludedClasses", excluded).
 		Msg("orphanscan: triggering auto-cleanup for scheduled scan")
 
 	// Trigger deletion - ConfirmDeletion runs in a goroutine
@@ -540,8 +604,18 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 		return
 	}
 
+	settings, err := s.store.GetSettings(ctx, instanceID)
+	if err != nil {
+		log.Warn().Err(err).Int("instance", instanceID).Msg("orphanscan: failed to load settings for deletion")
+	}
+	quarantine := settings != nil && settings.QuarantineEnabled
+	var shared []models.OrphanScanSharedInstance
+	if settings != nil {
+		shared = settings.SharedInstances
+	}
+
 	// Build fresh file map for re-checking
-	tfm, _, err := s.buildFileMap(ctx, instanceID)
+	tfm, _, err := s.buildFileMap(ctx, instanceID, shared)
 	if err != nil {
 		log.Error().Err(err).Msg("orphanscan: failed to rebuild file map for deletion")
 		s.failRun(ctx, runID, fmt.Sprintf("failed to rebuild file map: %v", err))
@@ -557,12 +631,15 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 
 	var filesDeleted int
 	var bytesReclaimed int64
+	var filesQuarantined int
+	var bytesQuarantined int64
 	var deletedOrMissingPaths []string
 
 	// Track deletion failures for user-facing error reporting
 	var failedDeletes int
 	var sawReadOnly bool
 	var sawPermissionDenied bool
+	var sawCrossDevice bool
 
 	// Delete files
 	for _, f := range files {
@@ -580,7 +657,16 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 			continue
 		}
 
-		disp, err := safeDeleteFile(scanRoot, f.FilePath, tfm)
+		var disp deleteDisposition
+		var quarantinePath string
+		var freed int64
+		if quarantine {
+			disp, quarantinePath, err = safeQuarantineFile(scanRoot, runID, f.FilePath, tfm)
+		} else {
+			// Only count space actually released: other hardlinks keep the data alive
+			freed = bytesFreedByRemoving(f.FilePath, f.FileSize)
+			disp, err = safeDeleteFile(scanRoot, f.FilePath, tfm)
+		}
 		if err != nil {
 			s.updateFileStatus(ctx, f.ID, "failed", err.Error())
 			log.Warn().Err(err).Str("path", f.FilePath).Msg("orphanscan: failed to delete file")
@@ -589,6 +675,8 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 			// Detect error type for user-facing message
 			if errors.Is(err, syscall.EROFS) || strings.Contains(err.Error(), "read-only file system") {
 				sawReadOnly = true
+			} else if errors.Is(err, syscall.EXDEV) {
+				sawCrossDevice = true
 			} else if os.IsPermission(err) || strings.Contains(err.Error(), "permission denied") || strings.Contains(err.Error(), "operation not permitted") {
 				sawPermissionDenied = true
 			}
@@ -604,7 +692,14 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 		case deleteDispositionDeleted:
 			s.updateFileStatus(ctx, f.ID, "deleted", "")
 			filesDeleted++
-			bytesReclaimed += f.FileSize
+			bytesReclaimed += freed
+			deletedOrMissingPaths = append(deletedOrMissingPaths, f.FilePath)
+		case deleteDispositionQuarantined:
+			if err := s.store.MarkFileQuarantined(ctx, f.ID, quarantinePath); err != nil {
+				log.Error().Err(err).Int64("file", f.ID).Msg("orphanscan: failed to record quarantined file")
+			}
+			filesQuarantined++
+			bytesQuarantined += f.FileSize
 			deletedOrMissingPaths = append(deletedOrMissingPaths, f.FilePath)
 		default:
 			s.updateFileStatus(ctx, f.ID, "failed", "unknown delete result")
@@ -613,10 +708,6 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 	}
 
 	// Clean up empty directories
-	settings, err := s.store.GetSettings(ctx, instanceID)
-	if err != nil {
-		log.Warn().Err(err).Int("instance", instanceID).Msg("orphanscan: failed to load settings for directory cleanup")
-	}
 	var ignorePaths []string
 	if settings != nil {
 		ignorePaths, err = NormalizeIgnorePaths(settings.IgnorePaths)
@@ -646,7 +737,9 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 	// Build user-facing error message if deletion failures occurred
 	var failureMessage string
 	if failedDeletes > 0 {
-		if sawReadOnly {
+		if sawCrossDevice {
+			failureMessage = fmt.Sprintf("Quarantine failed for %d file(s): files must be on the same filesystem as their scan root. Disable quarantine or check for nested mounts.", failedDeletes)
+		} else if sawReadOnly {
 			failureMessage = fmt.Sprintf("Deletion failed for %d file(s): filesystem is read-only. If running via Docker, remove ':ro' from the volume mapping for your downloads path.", failedDeletes)
 		} else if sawPermissionDenied {
 			failureMessage = fmt.Sprintf("Deletion failed for %d file(s): permission denied. Check that the qui process has write access to the download directories.", failedDeletes)
@@ -656,7 +749,7 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 	}
 
 	// Determine final status based on deletion results
-	if failedDeletes > 0 && filesDeleted == 0 {
+	if failedDeletes > 0 && filesDeleted == 0 && filesQuarantined == 0 {
 		// All deletions failed - mark as failed
 		if err := s.store.UpdateRunFailed(ctx, runID, failureMessage); err != nil {
 			log.Error().Err(err).Msg("orphanscan: failed to mark run as failed")
@@ -669,8 +762,14 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 		return
 	}
 
-	// Mark as completed (possibly with partial failure warning)
-	if err := s.store.UpdateRunCompleted(ctx, runID, filesDeleted, foldersDeleted, bytesReclaimed); err != nil {
+	// Mark as completed (possibly with partial failure warning).
+	// Quarantined runs only count reclaimed bytes once the quarantine is purged.
+	if quarantine {
+		err = s.store.UpdateRunQuarantined(ctx, runID, filesQuarantined, foldersDeleted, bytesQuarantined)
+	} else {
+		err = s.store.UpdateRunCompleted(ctx, runID, filesDeleted, foldersDeleted, bytesReclaimed)
+	}
+	if err != nil {
 		log.Error().Err(err).Msg("orphanscan: failed to update run completed")
 		return
 	}
@@ -685,6 +784,7 @@ func (s *Service) executeDeletion(ctx context.Context, instanceID int, runID int
 	log.Info().
 		Int64("run", runID).
 		Int("filesDeleted", filesDeleted).
+		Int("filesQuarantined", filesQuarantined).
 		Int("foldersDeleted", foldersDeleted).
 		Int("failedDeletes", failedDeletes).
 		Int64("bytesReclaimed", bytesReclaimed).
@@ -722,14 +822,59 @@ func (s *Service) updateFileStatus(ctx context.Context, fileID int64, status, er
 	}
 }
 
-func (s *Service) buildFileMap(ctx context.Context, instanceID int) (*TorrentFileMap, []string, error) {
+// buildFileMap builds the set of torrent files for an instance and returns its scan roots.
+// Torrents of shared instances are added to the map (after path mapping) so files they
+// own under the same storage are never flagged, but they do not contribute scan roots.
+// Fails if any shared instance is disabled or unreachable, since a partial map would
+// mark that instance's files as orphans.
+func (s *Service) buildFileMap(ctx context.Context, instanceID int, shared []models.OrphanScanSharedInstance) (*TorrentFileMap, []string, error) {
 	// Add timeout to prevent indefinite blocking if qBittorrent is unresponsive
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
+	tfm := NewTorrentFileMap()
+	scanRoots := make(map[string]struct{})
+
+	if err := s.addInstanceFiles(ctx, tfm, instanceID, nil, scanRoots); err != nil {
+		return nil, nil, err
+	}
+
+	for _, member := range shared {
+		if member.InstanceID == instanceID {
+			continue
+		}
+
+		inst, err := s.instanceStore.Get(ctx, member.InstanceID)
+		if err != nil {
+			if errors.Is(err, models.ErrInstanceNotFound) {
+				log.Warn().Int("instance", instanceID).Int("shared", member.InstanceID).Msg("orphanscan: shared instance no longer exists, skipping")
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to load shared instance %d: %w", member.InstanceID, err)
+		}
+		if !inst.IsActive {
+			return nil, nil, fmt.Errorf("%w: %s is disabled", ErrSharedInstanceUnavailable, inst.Name)
+		}
+
+		if err := s.addInstanceFiles(ctx, tfm, member.InstanceID, member.PathMappings, nil); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrSharedInstanceUnavailable, inst.Name, err)
+		}
+	}
+
+	roots := make([]string, 0, len(scanRoots))
+	for r := range scanRoots {
+		roots = append(roots, r)
+	}
+
+	return tfm, roots, nil
+}
+
+// addInstanceFiles adds every torrent file of an instance to tfm, rewriting save paths
+// through mappings. When scanRoots is non-nil the instance's save paths are recorded in it.
+func (s *Service) addInstanceFiles(ctx context.Context, tfm *TorrentFileMap, instanceID int, mappings []models.OrphanScanPathMapping, scanRoots map[string]struct{}) error {
 	torrents, err := s.syncManager.GetAllTorrents(ctx, instanceID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get torrents: %w", err)
+		return fmt.Errorf("failed to get torrents: %w", err)
 	}
 
 	// Build hash→torrent lookup with both original and canonical forms
@@ -745,23 +890,22 @@ func (s *Service) buildFileMap(ctx context.Context, instanceID int) (*TorrentFil
 
 	filesByHash, err := s.syncManager.GetTorrentFilesBatch(ctx, instanceID, hashes)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get torrent files: %w", err)
+		return fmt.Errorf("failed to get torrent files: %w", err)
 	}
 
-	tfm := NewTorrentFileMap()
-	scanRoots := make(map[string]struct{})
-
 	// Iterate the returned map - keys are canonical (lowercase)
 	for hash, files := range filesByHash {
 		t, ok := hashToTorrent[hash]
 		if !ok {
 			continue // Shouldn't happen given double-keying
 		}
-		savePath := filepath.Clean(t.SavePath)
+		savePath := mapPath(filepath.Clean(t.SavePath), mappings)
 		if !filepath.IsAbs(savePath) {
 			continue // Skip non-absolute paths
 		}
-		scanRoots[savePath] = struct{}{}
+		if scanRoots != nil {
+			scanRoots[savePath] = struct{}{}
+		}
 
 		for _, f := range files {
 			fullPath := filepath.Join(savePath, f.Name)
@@ -769,12 +913,7 @@ func (s *Service) buildFileMap(ctx context.Context, instanceID int) (*TorrentFil
 		}
 	}
 
-	roots := make([]string, 0, len(scanRoots))
-	for r := range scanRoots {
-		roots = append(roots, r)
-	}
-
-	return tfm, roots, nil
+	return nil
 }
 
 // findScanRoot finds the scan root that contains the given path.
@@ -23,6 +23,19 @@ var ErrCannotCancelDuringDeletion = errors.New("cannot cancel run while deletion
 // ErrRunAlreadyFinished is returned when attempting to modify a completed/failed/canceled run.
 var ErrRunAlreadyFinished = errors.New("run already finished")
 
+// ErrFileNotQuarantined is returned when restoring a file that is not held in quarantine.
+var ErrFileNotQuarantined = errors.New("file is not quarantined")
+
+// ErrFileNotFound is returned when a file cannot be found in a run.
+var ErrFileNotFound = errors.New("file not found")
+
+// ErrRestoreTargetExists is returned when a file already exists at a quarantined file's original path.
+var ErrRestoreTargetExists = errors.New("a file already exists at the original path")
+
+// ErrSharedInstanceUnavailable is returned when an instance sharing storage with the scanned
+// instance cannot be queried, so its files cannot be protected.
+var ErrSharedInstanceUnavailable = errors.New("shared instance unavailable")
+
 // RunStatus represents the status of an orphan scan run.
 type RunStatus string
 
@@ -52,6 +65,23 @@ const (
 	FileStatusDeleted FileStatus = "deleted"
 	FileStatusSkipped FileStatus = "skipped"
 	FileStatusFailed  FileStatus = "failed"
+
+	// FileStatusQuarantined files were moved to quarantine and await purge or restore.
+	FileStatusQuarantined FileStatus = "quarantined"
+	FileStatusRestored    FileStatus = "restored"
+)
+
+// LinkClass describes how an orphan's data is shared through hardlinks.
+type LinkClass string
+
+const (
+	// LinkClassUnique files have no other links; deleting them frees their size.
+	LinkClassUnique LinkClass = "unique"
+	// LinkClassHardlinkedOrphan files are only linked from other orphans in the same scan.
+	LinkClassHardlinkedOrphan LinkClass = "hardlinked_orphan"
+	// LinkClassHardlinkedElsewhere files are also linked outside the orphan set, typically
+	// from a media library, so deleting them frees nothing.
+	LinkClassHardlinkedElsewhere LinkClass = "hardlinked_elsewhere"
 )
 
 // OrphanFile represents a file found during an orphan scan.
@@ -63,38 +93,51 @@ type OrphanFile struct {
 	ModifiedAt   time.Time
 	Status       FileStatus
 	ErrorMessage string
+	LinkClass    LinkClass
+
+	// Physical file identity, used to classify hardlinks
+	fileID string
+	nlink  uint64
 }
 
 // Settings represents orphan scan settings for an instance.
 type Settings struct {
-	ID                  int64
-	InstanceID          int
-	Enabled             bool
-	GracePeriodMinutes  int
-	IgnorePaths         []string
-	ScanIntervalHours   int
-	MaxFilesPerRun      int
-	AutoCleanupEnabled  bool
-	AutoCleanupMaxFiles int
-	CreatedAt           time.Time
-	UpdatedAt           time.Time
+	ID                             int64
+	InstanceID                     int
+	Enabled                        bool
+	GracePeriodMinutes             int
+	IgnorePaths                    []string
+	ScanIntervalHours              int
+	MaxFilesPerRun                 int
+	AutoCleanupEnabled             bool
+	AutoCleanupMaxFiles            int
+	AutoCleanupUnique              bool
+	AutoCleanupHardlinkedOrphans   bool
+	AutoCleanupHardlinkedElsewhere bool
+	QuarantineEnabled              bool
+	// QuarantineRetentionDays is how long quarantined files are kept before purge.
+	QuarantineRetentionDays int
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
 }
 
 // Run represents an orphan scan run.
 type Run struct {
-	ID             int64
-	InstanceID     int
-	Status         RunStatus
-	TriggeredBy    TriggerType
-	ScanPaths      []string
-	FilesFound     int
-	FilesDeleted   int
-	FoldersDeleted int
-	BytesReclaimed int64
-	Truncated      bool
-	ErrorMessage   string
-	StartedAt      time.Time
-	CompletedAt    *time.Time
+	ID               int64
+	InstanceID       int
+	Status           RunStatus
+	TriggeredBy      TriggerType
+	ScanPaths        []string
+	FilesFound       int
+	FilesDeleted     int
+	FoldersDeleted   int
+	BytesReclaimed   int64
+	FilesQuarantined int
+	BytesQuarantined int64
+	Truncated        bool
+	ErrorMessage     string
+	StartedAt        time.Time
+	CompletedAt      *time.Time
 }
 
 // ScanResult holds the results of a directory scan.
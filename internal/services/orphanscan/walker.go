@@ -46,6 +46,10 @@ func walkScanRoot(ctx context.Context, root string, tfm *TorrentFileMap,
 
 		// Skip directories entirely - they're not orphans, only files are
 		if d.IsDir() {
+			// Quarantined orphans are managed separately, never rescanned
+			if d.Name() == quarantineDirName {
+				return fs.SkipDir
+			}
 			// But check ignore paths to skip entire subtrees
 			if isIgnoredPath(path, ignorePaths) {
 				return fs.SkipDir
@@ -79,11 +83,14 @@ func walkScanRoot(ctx context.Context, root string, tfm *TorrentFileMap,
 			return fs.SkipAll
 		}
 
+		fileID, nlink := fileLinkInfo(info, path)
 		orphans = append(orphans, OrphanFile{
 			Path:       path,
 			Size:       info.Size(),
 			ModifiedAt: info.ModTime(),
 			Status:     FileStatusPending,
+			fileID:     fileID,
+			nlink:      nlink,
 		})
 		return nil
 	})
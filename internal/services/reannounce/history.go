@@ -0,0 +1,196 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package reannounce
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// TrackerStats aggregates persisted reannounce outcomes for a single tracker domain.
+type TrackerStats struct {
+	Tracker     string  `json:"tracker"`
+	Attempts    int     `json:"attempts"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	SuccessRate float64 `json:"successRate"`
+	// AvgTimeToWorkingSeconds is the average torrent age at its first successful
+	// reannounce, i.e. how long the tracker took to register new torrents.
+	AvgTimeToWorkingSeconds *int64    `json:"avgTimeToWorkingSeconds,omitempty"`
+	LastAttemptAt           time.Time `json:"lastAttemptAt"`
+}
+
+// SetHistoryStore enables persisting succeeded and failed reannounce events.
+func (s *Service) SetHistoryStore(store *models.ReannounceHistoryStore) {
+	s.historyStore = store
+}
+
+// GetTrackerStats returns per-tracker statistics for reannounces recorded since the given time,
+// ordered by attempts descending. Returns nil when no history store is configured.
+func (s *Service) GetTrackerStats(ctx context.Context, instanceID int, since time.Time) ([]TrackerStats, error) {
+	if s == nil || s.historyStore == nil || instanceID == 0 {
+		return nil, nil
+	}
+	entries, err := s.historyStore.ListSince(ctx, instanceID, since)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateTrackerStats(entries), nil
+}
+
+func (s *Service) persistActivity(event ActivityEvent) {
+	if s.historyStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entry := &models.ReannounceHistoryEntry{
+		InstanceID:        event.InstanceID,
+		Hash:              event.Hash,
+		TorrentName:       event.TorrentName,
+		Trackers:          event.Trackers,
+		Outcome:           string(event.Outcome),
+		Reason:            event.Reason,
+		TorrentAgeSeconds: s.torrentAge(ctx, event.InstanceID, event.Hash, event.Timestamp),
+		CreatedAt:         event.Timestamp,
+	}
+	if err := s.historyStore.Create(ctx, entry); err != nil {
+		log.Warn().Err(err).Int("instanceID", event.InstanceID).Str("hash", event.Hash).Msg("reannounce: failed to persist history")
+	}
+}
+
+// torrentAge returns the seconds between the torrent being added and now, if known.
+func (s *Service) torrentAge(ctx context.Context, instanceID int, hash string, now time.Time) *int64 {
+	torrent, ok := s.lookupTorrents(ctx, instanceID, []string{hash})[hash]
+	if !ok || torrent.AddedOn <= 0 {
+		return nil
+	}
+	age := max(now.Unix()-torrent.AddedOn, 0)
+	return &age
+}
+
+// loadPersistedActivity returns persisted events converted for GetActivity, oldest first.
+// The second return value is false when no history store is configured or loading failed.
+func (s *Service) loadPersistedActivity(ctx context.Context, instanceID int, limit int) ([]ActivityEvent, bool) {
+	if s.historyStore == nil {
+		return nil, false
+	}
+
+	if limit <= 0 {
+		limit = s.historyCap * 4
+		if limit <= 0 {
+			limit = defaultHistorySize * 4
+		}
+	}
+
+	entries, err := s.historyStore.ListByInstance(ctx, instanceID, limit)
+	if err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Msg("reannounce: failed to load persisted history, using memory")
+		return nil, false
+	}
+
+	events := make([]ActivityEvent, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		events = append(events, ActivityEvent{
+			InstanceID:  e.InstanceID,
+			Hash:        e.Hash,
+			TorrentName: e.TorrentName,
+			Trackers:    e.Trackers,
+			Outcome:     ActivityOutcome(e.Outcome),
+			Reason:      e.Reason,
+			Timestamp:   e.CreatedAt,
+		})
+	}
+	return events, true
+}
+
+func (s *Service) pruneHistory(ctx context.Context) {
+	if s.historyStore == nil {
+		return
+	}
+	cutoff := s.currentTime().Add(-s.cfg.HistoryRetention)
+	pruned, err := s.historyStore.Prune(ctx, cutoff)
+	if err != nil {
+		log.Warn().Err(err).Msg("reannounce: failed to prune history")
+		return
+	}
+	if pruned > 0 {
+		log.Info().Int64("count", pruned).Msg("reannounce: pruned old history")
+	}
+}
+
+// aggregateTrackerStats groups entries (oldest first) by tracker domain. Entries listing
+// several trackers count towards each of them.
+func aggregateTrackerStats(entries []*models.ReannounceHistoryEntry) []TrackerStats {
+	type accumulator struct {
+		stats      TrackerStats
+		ageTotal   int64
+		ageSamples int64
+		working    map[string]struct{}
+	}
+
+	byTracker := make(map[string]*accumulator)
+	for _, entry := range entries {
+		outcome := ActivityOutcome(entry.Outcome)
+		if outcome != ActivityOutcomeSucceeded && outcome != ActivityOutcomeFailed {
+			continue
+		}
+		for _, tracker := range splitTags(entry.Trackers) {
+			key := strings.ToLower(tracker)
+			acc, ok := byTracker[key]
+			if !ok {
+				acc = &accumulator{stats: TrackerStats{Tracker: tracker}, working: make(map[string]struct{})}
+				byTracker[key] = acc
+			}
+
+			acc.stats.Attempts++
+			if entry.CreatedAt.After(acc.stats.LastAttemptAt) {
+				acc.stats.LastAttemptAt = entry.CreatedAt
+			}
+			if outcome == ActivityOutcomeFailed {
+				acc.stats.Failed++
+				continue
+			}
+
+			acc.stats.Succeeded++
+			// Only the first success for a torrent reflects time-to-working after add
+			if _, seen := acc.working[entry.Hash]; seen || entry.TorrentAgeSeconds == nil {
+				continue
+			}
+			acc.working[entry.Hash] = struct{}{}
+			acc.ageTotal += *entry.TorrentAgeSeconds
+			acc.ageSamples++
+		}
+	}
+
+	result := make([]TrackerStats, 0, len(byTracker))
+	for _, acc := range byTracker {
+		stats := acc.stats
+		if stats.Attempts > 0 {
+			stats.SuccessRate = float64(stats.Succeeded) / float64(stats.Attempts)
+		}
+		if acc.ageSamples > 0 {
+			avg := acc.ageTotal / acc.ageSamples
+			stats.AvgTimeToWorkingSeconds = &avg
+		}
+		result = append(result, stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Attempts != result[j].Attempts {
+			return result[i].Attempts > result[j].Attempts
+		}
+		return strings.ToLower(result[i].Tracker) < strings.ToLower(result[j].Tracker)
+	})
+	return result
+}
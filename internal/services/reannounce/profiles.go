@@ -0,0 +1,101 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package reannounce
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// customizationsTTL bounds how stale cached tracker display names may be.
+const customizationsTTL = time.Minute
+
+// SetTrackerCustomizationStore enables matching tracker profiles by display name.
+func (s *Service) SetTrackerCustomizationStore(store *models.TrackerCustomizationStore) {
+	s.customizationStore = store
+}
+
+// settingsForDomains returns the effective settings for a torrent on the given tracker
+// domains and the name of the applied profile, if any.
+func (s *Service) settingsForDomains(ctx context.Context, settings *models.InstanceReannounceSettings, domains []string) (*models.InstanceReannounceSettings, string) {
+	if settings == nil || len(settings.TrackerProfiles) == 0 || len(domains) == 0 {
+		return settings, ""
+	}
+	profile := matchTrackerProfile(settings.TrackerProfiles, domains, s.trackerCustomizations(ctx))
+	if profile == nil {
+		return settings, ""
+	}
+	return settings.WithProfile(profile), profile.Tracker
+}
+
+// settingsForTrackers resolves the effective settings from a torrent's tracker list.
+func (s *Service) settingsForTrackers(ctx context.Context, settings *models.InstanceReannounceSettings, trackers []qbt.TorrentTracker) (*models.InstanceReannounceSettings, string) {
+	if settings == nil || len(settings.TrackerProfiles) == 0 {
+		return settings, ""
+	}
+	domains := make([]string, 0, len(trackers))
+	for _, tracker := range trackers {
+		if tracker.Status == qbt.TrackerStatusDisabled {
+			continue
+		}
+		if domain := s.extractTrackerDomain(tracker.Url); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return s.settingsForDomains(ctx, settings, domains)
+}
+
+// settingsForTorrent resolves the effective settings for a torrent, falling back to its
+// current tracker when the tracker list was not fetched.
+func (s *Service) settingsForTorrent(ctx context.Context, settings *models.InstanceReannounceSettings, torrent qbt.Torrent) (*models.InstanceReannounceSettings, string) {
+	if len(torrent.Trackers) == 0 && torrent.Tracker != "" {
+		return s.settingsForDomains(ctx, settings, []string{s.extractTrackerDomain(torrent.Tracker)})
+	}
+	return s.settingsForTrackers(ctx, settings, torrent.Trackers)
+}
+
+// matchTrackerProfile returns the profile for the first domain that matches one, either
+// directly or through a tracker customization display name.
+func matchTrackerProfile(profiles []models.ReannounceTrackerProfile, domains []string, customizations []*models.TrackerCustomization) *models.ReannounceTrackerProfile {
+	for _, domain := range domains {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
+		}
+		displayName := models.ResolveTrackerDisplayName(domain, "", customizations)
+		for i := range profiles {
+			if strings.EqualFold(profiles[i].Tracker, domain) || strings.EqualFold(profiles[i].Tracker, displayName) {
+				return &profiles[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) trackerCustomizations(ctx context.Context) []*models.TrackerCustomization {
+	if s.customizationStore == nil {
+		return nil
+	}
+
+	s.customizationsMu.Lock()
+	defer s.customizationsMu.Unlock()
+
+	if !s.customizationsLoaded.IsZero() && time.Since(s.customizationsLoaded) < customizationsTTL {
+		return s.customizations
+	}
+	customizations, err := s.customizationStore.List(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("reannounce: failed to load tracker customizations for profiles")
+		return s.customizations
+	}
+	s.customizations = customizations
+	s.customizationsLoaded = time.Now()
+	return customizations
+}
@@ -15,6 +15,7 @@ import (
 	qbt "github.com/autobrr/go-qbittorrent"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/events"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
 )
@@ -24,6 +25,8 @@ type Config struct {
 	ScanInterval   time.Duration
 	DebounceWindow time.Duration
 	HistorySize    int
+	// HistoryRetention controls how long persisted succeeded/failed events are kept.
+	HistoryRetention time.Duration
 }
 
 // Service monitors torrents with unhealthy trackers and reannounces them conservatively.
@@ -41,12 +44,21 @@ type Service struct {
 	now           func() time.Time
 	runJob        func(context.Context, int, string, string, string)
 	spawn         func(func())
+	// torrentMap replaces the sync manager's torrent lookup in tests
+	torrentMap func(context.Context, int, qbt.TorrentFilterOptions) map[string]qbt.Torrent
 	// Separate history buffers per outcome type to prevent skipped events from pushing out succeeded/failed
 	historySucceeded map[int][]ActivityEvent
 	historyFailed    map[int][]ActivityEvent
 	historySkipped   map[int][]ActivityEvent
 	historyMu        sync.RWMutex
 	historyCap       int
+	historyStore     *models.ReannounceHistoryStore
+	eventBus         *events.Bus
+	// Tracker customizations resolve profile display names; cached for customizationsTTL
+	customizationStore   *models.TrackerCustomizationStore
+	customizations       []*models.TrackerCustomization
+	customizationsLoaded time.Time
+	customizationsMu     sync.Mutex
 }
 
 type reannounceJob struct {
@@ -75,7 +87,10 @@ type ActivityEvent struct {
 	Timestamp   time.Time       `json:"timestamp"`
 }
 
-const defaultHistorySize = 50
+const (
+	defaultHistorySize      = 50
+	defaultHistoryRetention = 30 * 24 * time.Hour
+)
 
 // MonitoredTorrentState describes the current monitoring state for a torrent.
 type MonitoredTorrentState string
@@ -99,14 +114,17 @@ type MonitoredTorrent struct {
 	State             MonitoredTorrentState `json:"state"`
 	HasTrackerProblem bool                  `json:"hasTrackerProblem"`
 	WaitingForInitial bool                  `json:"waitingForInitial"`
+	// Profile is the tracker profile applied to this torrent, if any.
+	Profile string `json:"profile,omitempty"`
 }
 
 // DefaultConfig returns sane defaults.
 func DefaultConfig() Config {
 	return Config{
-		ScanInterval:   7 * time.Second,
-		DebounceWindow: 2 * time.Minute,
-		HistorySize:    defaultHistorySize,
+		ScanInterval:     7 * time.Second,
+		DebounceWindow:   2 * time.Minute,
+		HistorySize:      defaultHistorySize,
+		HistoryRetention: defaultHistoryRetention,
 	}
 }
 
@@ -121,6 +139,9 @@ func NewService(cfg Config, instanceStore *models.InstanceStore, settingsStore *
 	if cfg.HistorySize <= 0 {
 		cfg.HistorySize = DefaultConfig().HistorySize
 	}
+	if cfg.HistoryRetention <= 0 {
+		cfg.HistoryRetention = DefaultConfig().HistoryRetention
+	}
 	svc := &Service{
 		cfg:              cfg,
 		instanceStore:    instanceStore,
@@ -150,6 +171,7 @@ func (s *Service) Start(ctx context.Context) {
 		s.settingsCache.StartAutoRefresh(ctx, 2*time.Minute)
 	}
 	go func() {
+		s.pruneHistory(ctx)
 		s.scanInstances(ctx)
 		s.loop(ctx)
 	}()
@@ -168,7 +190,8 @@ func (s *Service) RequestReannounce(ctx context.Context, instanceID int, hashes
 	torrents := s.lookupTorrents(ctx, instanceID, upperHashes)
 	var handled []string
 	for hash, torrent := range torrents {
-		if !s.torrentMeetsCriteria(torrent, settings) {
+		effective, _ := s.settingsForTorrent(ctx, settings, torrent)
+		if !s.torrentMeetsCriteria(torrent, effective) {
 			continue
 		}
 		if s.hasHealthyTracker(torrent.Trackers) {
@@ -186,12 +209,18 @@ func (s *Service) loop(ctx context.Context) {
 	ticker := time.NewTicker(s.cfg.ScanInterval)
 	defer ticker.Stop()
 
+	lastPrune := time.Now()
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			s.scanInstances(ctx)
+			// Prune persisted history hourly
+			if time.Since(lastPrune) > time.Hour {
+				s.pruneHistory(ctx)
+				lastPrune = time.Now()
+			}
 		}
 	}
 }
@@ -247,7 +276,8 @@ func (s *Service) scanInstance(ctx context.Context, instanceID int, settings *mo
 	}
 
 	for _, torrent := range torrents {
-		if !s.torrentMeetsCriteria(torrent, settings) {
+		effective, _ := s.settingsForTorrent(ctx, settings, torrent)
+		if !s.torrentMeetsCriteria(torrent, effective) {
 			continue
 		}
 		// Skip if we have tracker data and it shows healthy.
@@ -285,12 +315,14 @@ func (s *Service) GetMonitoredTorrents(ctx context.Context, instanceID int) []Mo
 		return nil
 	}
 
+	// Warm the customization cache so profile lookups don't query the database under jobsMu
+	s.trackerCustomizations(ctx)
+
 	s.jobsMu.Lock()
 	defer s.jobsMu.Unlock()
 
 	now := s.currentTime()
 	instJobs := s.j[instanceID]
-	debounceWindow := s.effectiveDebounceWindow(settings)
 
 	var result []MonitoredTorrent
 	for _, torrent := range torrents {
@@ -300,8 +332,11 @@ func (s *Service) GetMonitoredTorrents(ctx context.Context, instanceID int) []Mo
 			continue
 		}
 
+		effective, profile := s.settingsForTorrent(ctx, settings, torrent)
+		debounceWindow := s.effectiveDebounceWindow(effective)
+
 		// Check if torrent is still in initial wait period
-		inInitialWait := settings.InitialWaitSeconds > 0 && torrent.TimeActive < int64(settings.InitialWaitSeconds)
+		inInitialWait := effective.InitialWaitSeconds > 0 && torrent.TimeActive < int64(effective.InitialWaitSeconds)
 
 		healthy := s.hasHealthyTracker(torrent.Trackers)
 		updating := s.trackersUpdating(torrent.Trackers)
@@ -342,6 +377,7 @@ func (s *Service) GetMonitoredTorrents(ctx context.Context, instanceID int) []Mo
 			State:             state,
 			HasTrackerProblem: hasProblem,
 			WaitingForInitial: inInitialWait || waitingForTrackers,
+			Profile:           profile,
 		})
 	}
 
@@ -359,6 +395,11 @@ func (s *Service) enqueue(instanceID int, hash string, torrentName string, track
 		return false
 	}
 
+	// Resolved before taking jobsMu, since profile lookups may query the database
+	settings, _ := s.settingsForDomains(baseCtx, s.getSettings(baseCtx, instanceID), splitTags(trackers))
+	isAggressive := settings != nil && settings.Aggressive
+	debounceWindow := s.effectiveDebounceWindow(settings)
+
 	s.jobsMu.Lock()
 	defer s.jobsMu.Unlock()
 	instJobs, ok := s.j[instanceID]
@@ -378,10 +419,6 @@ func (s *Service) enqueue(instanceID int, hash string, torrentName string, track
 		return true
 	}
 
-	settings := s.getSettings(baseCtx, instanceID)
-	isAggressive := settings != nil && settings.Aggressive
-	debounceWindow := s.effectiveDebounceWindow(settings)
-
 	if !job.lastCompleted.IsZero() && debounceWindow > 0 {
 		if elapsed := now.Sub(job.lastCompleted); elapsed < debounceWindow {
 			reason := "debounced during cooldown window"
@@ -434,6 +471,11 @@ func (s *Service) executeJob(parentCtx context.Context, instanceID int, hash str
 		s.recordActivity(instanceID, hash, torrentName, healthyTrackers, ActivityOutcomeSkipped, "tracker healthy")
 		return
 	}
+	settings, profile := s.settingsForTrackers(ctx, settings, trackerList)
+	if s.inProfileInitialWait(ctx, instanceID, hash, settings, profile) {
+		s.recordActivity(instanceID, hash, torrentName, initialTrackers, ActivityOutcomeSkipped, fmt.Sprintf("initial wait of tracker profile %s", profile))
+		return
+	}
 	// No healthy tracker - proceed with reannounce (trackers may be updating or have errors)
 	freshTrackers := s.getProblematicTrackers(trackerList)
 	if freshTrackers == "" {
@@ -452,6 +494,17 @@ func (s *Service) executeJob(parentCtx context.Context, instanceID int, hash str
 	s.recordActivity(instanceID, hash, torrentName, freshTrackers, ActivityOutcomeSucceeded, "reannounce requested")
 }
 
+// inProfileInitialWait reports whether a torrent is younger than its tracker profile's
+// initial wait. Scans on older qBittorrent lack tracker data, so the wait is enforced
+// again once the job has loaded the trackers.
+func (s *Service) inProfileInitialWait(ctx context.Context, instanceID int, hash string, settings *models.InstanceReannounceSettings, profile string) bool {
+	if profile == "" || settings == nil || settings.InitialWaitSeconds <= 0 {
+		return false
+	}
+	torrent, ok := s.lookupTorrents(ctx, instanceID, []string{hash})[hash]
+	return ok && torrent.TimeActive < int64(settings.InitialWaitSeconds)
+}
+
 func (s *Service) finishJob(instanceID int, hash string) {
 	s.jobsMu.Lock()
 	defer s.jobsMu.Unlock()
@@ -492,18 +545,29 @@ func (s *Service) lookupTorrents(ctx context.Context, instanceID int, hashes []s
 	if len(hashes) == 0 {
 		return result
 	}
+	// Jobs use uppercase hashes, but the filter matches qBittorrent's lowercase ones exactly
+	filter := qbt.TorrentFilterOptions{Hashes: make([]string, 0, len(hashes))}
+	for _, hash := range hashes {
+		filter.Hashes = append(filter.Hashes, strings.ToLower(hash))
+	}
+	for hash, torrent := range s.getTorrentMap(ctx, instanceID, filter) {
+		result[strings.ToUpper(hash)] = torrent
+	}
+	return result
+}
+
+func (s *Service) getTorrentMap(ctx context.Context, instanceID int, filter qbt.TorrentFilterOptions) map[string]qbt.Torrent {
+	if s.torrentMap != nil {
+		return s.torrentMap(ctx, instanceID, filter)
+	}
 	if s.syncManager == nil {
-		return result
+		return nil
 	}
 	sync, err := s.syncManager.GetQBittorrentSyncManager(ctx, instanceID)
 	if err != nil || sync == nil {
-		return result
-	}
-	filter := qbt.TorrentFilterOptions{Hashes: hashes}
-	for hash, torrent := range sync.GetTorrentMap(filter) {
-		result[strings.ToUpper(hash)] = torrent
+		return nil
 	}
-	return result
+	return sync.GetTorrentMap(filter)
 }
 
 func (s *Service) getSettings(ctx context.Context, instanceID int) *models.InstanceReannounceSettings {
@@ -789,10 +853,41 @@ func (s *Service) DebugState() string {
 	return fmt.Sprintf("instances=%d", len(s.j))
 }
 
+// SetEventBus sets the bus succeeded and failed reannounces are published to.
+func (s *Service) SetEventBus(bus *events.Bus) {
+	s.eventBus = bus
+}
+
 func (s *Service) recordActivity(instanceID int, hash string, torrentName string, trackers string, outcome ActivityOutcome, reason string) {
 	if s == nil || instanceID == 0 {
 		return
 	}
+
+	event := ActivityEvent{
+		InstanceID:  instanceID,
+		Hash:        strings.ToUpper(strings.TrimSpace(hash)),
+		TorrentName: torrentName,
+		Trackers:    strings.TrimSpace(trackers),
+		Outcome:     outcome,
+		Reason:      strings.TrimSpace(reason),
+		Timestamp:   s.currentTime(),
+	}
+
+	if outcome != ActivityOutcomeSkipped {
+		s.eventBus.Publish(events.Event{
+			Type:       events.TorrentReannounced,
+			InstanceID: instanceID,
+			Payload: events.Reannounce{
+				Hash:      event.Hash,
+				Name:      torrentName,
+				Trackers:  event.Trackers,
+				Succeeded: outcome == ActivityOutcomeSucceeded,
+				Reason:    event.Reason,
+			},
+		})
+		s.persistActivity(event)
+	}
+
 	s.historyMu.Lock()
 	defer s.historyMu.Unlock()
 
@@ -812,16 +907,6 @@ func (s *Service) recordActivity(instanceID int, hash string, torrentName string
 		limit = defaultHistorySize
 	}
 
-	event := ActivityEvent{
-		InstanceID:  instanceID,
-		Hash:        strings.ToUpper(strings.TrimSpace(hash)),
-		TorrentName: torrentName,
-		Trackers:    strings.TrimSpace(trackers),
-		Outcome:     outcome,
-		Reason:      strings.TrimSpace(reason),
-		Timestamp:   s.currentTime(),
-	}
-
 	// Store in the appropriate buffer based on outcome.
 	// Succeeded/failed keep 2x limit entries, skipped keeps 1x limit.
 	switch outcome {
@@ -844,18 +929,27 @@ func (s *Service) recordActivity(instanceID int, hash string, torrentName string
 }
 
 // GetActivity returns the most recent activity events for an instance, newest last.
-// Events from all outcome types are merged and sorted by timestamp.
-func (s *Service) GetActivity(instanceID int, limit int) []ActivityEvent {
+// Events from all outcome types are merged and sorted by timestamp. When a history
+// store is configured, succeeded and failed events come from persisted history so
+// they survive restarts; skipped events are always served from memory.
+func (s *Service) GetActivity(ctx context.Context, instanceID int, limit int) []ActivityEvent {
 	if s == nil || instanceID == 0 {
 		return nil
 	}
+
+	persisted, persistedOK := s.loadPersistedActivity(ctx, instanceID, limit)
+
 	s.historyMu.RLock()
 	defer s.historyMu.RUnlock()
 
 	// Merge all three buffers
 	var all []ActivityEvent
-	all = append(all, s.historySucceeded[instanceID]...)
-	all = append(all, s.historyFailed[instanceID]...)
+	if persistedOK {
+		all = append(all, persisted...)
+	} else {
+		all = append(all, s.historySucceeded[instanceID]...)
+		all = append(all, s.historyFailed[instanceID]...)
+	}
 	all = append(all, s.historySkipped[instanceID]...)
 
 	if len(all) == 0 {
@@ -3,6 +3,8 @@ package reannounce
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/autobrr/qui/internal/database"
 	"github.com/autobrr/qui/internal/models"
 )
 
@@ -475,13 +478,13 @@ func TestServiceRecordActivityLimit(t *testing.T) {
 		svc.recordActivity(1, fmt.Sprintf("hash%d", i), fmt.Sprintf("Torrent %d", i), "tracker.example.com", ActivityOutcomeSucceeded, "ok")
 	}
 
-	events := svc.GetActivity(1, 0)
+	events := svc.GetActivity(context.Background(), 1, 0)
 	require.Len(t, events, 4)
 	require.Equal(t, "HASH2", events[0].Hash) // oldest kept
 	require.Equal(t, "HASH5", events[3].Hash) // newest
 
 	// Test GetActivity limit parameter
-	limited := svc.GetActivity(1, 2)
+	limited := svc.GetActivity(context.Background(), 1, 2)
 	require.Len(t, limited, 2)
 	require.Equal(t, events[2:], limited) // last 2 events
 
@@ -491,7 +494,7 @@ func TestServiceRecordActivityLimit(t *testing.T) {
 		svc.recordActivity(1, fmt.Sprintf("skipped%d", i), fmt.Sprintf("Skipped %d", i), "tracker.example.com", ActivityOutcomeSkipped, "healthy")
 	}
 
-	allEvents := svc.GetActivity(1, 0)
+	allEvents := svc.GetActivity(context.Background(), 1, 0)
 	// 4 succeeded + 2 skipped = 6 total
 	require.Len(t, allEvents, 6)
 
@@ -510,7 +513,7 @@ func TestServiceRecordActivityLimit(t *testing.T) {
 		svc.recordActivity(1, fmt.Sprintf("failed%d", i), fmt.Sprintf("Failed %d", i), "tracker.example.com", ActivityOutcomeFailed, "error")
 	}
 
-	allEvents = svc.GetActivity(1, 0)
+	allEvents = svc.GetActivity(context.Background(), 1, 0)
 	// 4 succeeded + 2 skipped + 4 failed = 10 total
 	require.Len(t, allEvents, 10)
 
@@ -523,3 +526,154 @@ func TestServiceRecordActivityLimit(t *testing.T) {
 	}
 	require.Equal(t, 4, failedCount)
 }
+
+func TestAggregateTrackerStats(t *testing.T) {
+	base := time.Unix(1_700_000_000, 0)
+	age := func(v int64) *int64 { return &v }
+	entries := []*models.ReannounceHistoryEntry{
+		{Hash: "A", Trackers: "slow.example", Outcome: "failed", CreatedAt: base},
+		{Hash: "A", Trackers: "slow.example", Outcome: "succeeded", TorrentAgeSeconds: age(300), CreatedAt: base.Add(time.Minute)},
+		// A later success for the same torrent doesn't count towards time-to-working
+		{Hash: "A", Trackers: "slow.example", Outcome: "succeeded", TorrentAgeSeconds: age(9000), CreatedAt: base.Add(2 * time.Minute)},
+		{Hash: "B", Trackers: "slow.example, fast.example", Outcome: "succeeded", TorrentAgeSeconds: age(100), CreatedAt: base.Add(3 * time.Minute)},
+		{Hash: "C", Trackers: "fast.example", Outcome: "skipped", CreatedAt: base.Add(4 * time.Minute)},
+	}
+
+	stats := aggregateTrackerStats(entries)
+	require.Len(t, stats, 2)
+
+	slow := stats[0]
+	require.Equal(t, "slow.example", slow.Tracker)
+	require.Equal(t, 4, slow.Attempts)
+	require.Equal(t, 3, slow.Succeeded)
+	require.Equal(t, 1, slow.Failed)
+	require.InDelta(t, 0.75, slow.SuccessRate, 0.0001)
+	require.NotNil(t, slow.AvgTimeToWorkingSeconds)
+	require.Equal(t, int64(200), *slow.AvgTimeToWorkingSeconds)
+	require.Equal(t, base.Add(3*time.Minute), slow.LastAttemptAt)
+
+	fast := stats[1]
+	require.Equal(t, "fast.example", fast.Tracker)
+	require.Equal(t, 1, fast.Attempts)
+	require.InDelta(t, 1.0, fast.SuccessRate, 0.0001)
+	require.Equal(t, int64(100), *fast.AvgTimeToWorkingSeconds)
+}
+
+func TestMatchTrackerProfile(t *testing.T) {
+	profiles := []models.ReannounceTrackerProfile{
+		{Tracker: "Slow Tracker"},
+		{Tracker: "fast.example"},
+	}
+	customizations := []*models.TrackerCustomization{
+		{DisplayName: "Slow Tracker", Domains: []string{"announce.slow.example", "slow.example"}},
+	}
+
+	profile := matchTrackerProfile(profiles, []string{"slow.example"}, customizations)
+	require.NotNil(t, profile)
+	require.Equal(t, "Slow Tracker", profile.Tracker, "display name resolves through customization")
+
+	profile = matchTrackerProfile(profiles, []string{"other.example", "FAST.example"}, customizations)
+	require.NotNil(t, profile)
+	require.Equal(t, "fast.example", profile.Tracker)
+
+	require.Nil(t, matchTrackerProfile(profiles, []string{"other.example"}, customizations))
+}
+
+func TestServiceEnqueue_TrackerProfileOverridesCooldown(t *testing.T) {
+	now := time.Unix(0, 0)
+	svc := newTestServiceForDebounce(time.Minute, func() time.Time { return now })
+	svc.settingsCache = &SettingsCache{data: make(map[int]*models.InstanceReannounceSettings)}
+
+	quick := true
+	interval := 5
+	svc.settingsCache.Replace(&models.InstanceReannounceSettings{
+		InstanceID: 1,
+		Enabled:    true,
+		TrackerProfiles: []models.ReannounceTrackerProfile{
+			{Tracker: "fast.example", Aggressive: &quick, ReannounceIntervalSeconds: &interval},
+		},
+	})
+
+	started := 0
+	svc.runJob = func(ctx context.Context, instanceID int, hash string, torrentName string, trackers string) {
+		started++
+	}
+
+	require.True(t, svc.enqueue(1, "FAST", "Fast", "fast.example"))
+	require.True(t, svc.enqueue(1, "SLOW", "Slow", "slow.example"))
+	require.Equal(t, 2, started)
+	svc.finishJob(1, "FAST")
+	svc.finishJob(1, "SLOW")
+
+	now = now.Add(10 * time.Second)
+	require.True(t, svc.enqueue(1, "FAST", "Fast", "fast.example"))
+	require.Equal(t, 3, started, "profile retry interval should govern cooldown")
+	require.True(t, svc.enqueue(1, "SLOW", "Slow", "slow.example"))
+	require.Equal(t, 3, started, "instance debounce window still applies without a profile")
+}
+
+// fakeTorrentMap filters like go-qbittorrent, matching hashes exactly against
+// qBittorrent's lowercase ones.
+func fakeTorrentMap(torrents ...qbt.Torrent) func(context.Context, int, qbt.TorrentFilterOptions) map[string]qbt.Torrent {
+	return func(_ context.Context, _ int, filter qbt.TorrentFilterOptions) map[string]qbt.Torrent {
+		result := make(map[string]qbt.Torrent)
+		for _, torrent := range torrents {
+			if slices.Contains(filter.Hashes, torrent.Hash) {
+				result[torrent.Hash] = torrent
+			}
+		}
+		return result
+	}
+}
+
+func TestPersistActivity_StoresTorrentAge(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.New(filepath.Join(t.TempDir(), "reannounce.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	instances, err := models.NewInstanceStore(db, make([]byte, 32))
+	require.NoError(t, err)
+	instance, err := instances.Create(ctx, "test", "http://localhost:8080", "admin", "password", nil, nil, false, nil)
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	svc := newTestServiceForDebounce(time.Minute, func() time.Time { return now })
+	svc.SetHistoryStore(models.NewReannounceHistoryStore(db))
+	svc.torrentMap = fakeTorrentMap(qbt.Torrent{Hash: "abcdef", Name: "Test", AddedOn: now.Unix() - 90})
+
+	svc.persistActivity(ActivityEvent{
+		InstanceID:  instance.ID,
+		Hash:        "ABCDEF",
+		TorrentName: "Test",
+		Trackers:    "tracker.example",
+		Outcome:     ActivityOutcomeSucceeded,
+		Timestamp:   now,
+	})
+
+	entries, err := models.NewReannounceHistoryStore(db).ListSince(ctx, instance.ID, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].TorrentAgeSeconds, "torrent is found by its lowercase hash")
+	require.Equal(t, int64(90), *entries[0].TorrentAgeSeconds)
+
+	stats := aggregateTrackerStats(entries)
+	require.Len(t, stats, 1)
+	require.NotNil(t, stats[0].AvgTimeToWorkingSeconds)
+}
+
+func TestInProfileInitialWait(t *testing.T) {
+	svc := newTestServiceForDebounce(time.Minute, nil)
+	svc.torrentMap = fakeTorrentMap(
+		qbt.Torrent{Hash: "aaaaaa", TimeActive: 30},
+		qbt.Torrent{Hash: "bbbbbb", TimeActive: 600},
+	)
+	settings := &models.InstanceReannounceSettings{InstanceID: 1, InitialWaitSeconds: 120}
+	ctx := context.Background()
+
+	require.True(t, svc.inProfileInitialWait(ctx, 1, "AAAAAA", settings, "slow.example"), "young torrent is found by its lowercase hash")
+	require.False(t, svc.inProfileInitialWait(ctx, 1, "BBBBBB", settings, "slow.example"))
+	require.False(t, svc.inProfileInitialWait(ctx, 1, "AAAAAA", settings, ""), "only profiles are checked again")
+	require.False(t, svc.inProfileInitialWait(ctx, 1, "CCCCCC", settings, "slow.example"))
+}
@@ -105,5 +105,6 @@ func cloneSettings(src *models.InstanceReannounceSettings) *models.InstanceReann
 	clone.Categories = append([]string{}, src.Categories...)
 	clone.Tags = append([]string{}, src.Tags...)
 	clone.Trackers = append([]string{}, src.Trackers...)
+	clone.TrackerProfiles = append([]models.ReannounceTrackerProfile{}, src.TrackerProfiles...)
 	return &clone
 }
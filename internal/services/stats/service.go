@@ -0,0 +1,233 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package stats samples per-instance and per-tracker transfer statistics into
+// a downsampled history.
+package stats
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+const sampleTimeout = 30 * time.Second
+
+// Config controls sampling and retention. A zero retention keeps that
+// resolution forever.
+type Config struct {
+	Interval        time.Duration
+	RawRetention    time.Duration
+	HourlyRetention time.Duration
+	DailyRetention  time.Duration
+	PruneInterval   time.Duration
+}
+
+// DefaultConfig returns the default sampling configuration.
+func DefaultConfig() Config {
+	return Config{
+		Interval:        time.Minute,
+		RawRetention:    7 * 24 * time.Hour,
+		HourlyRetention: 90 * 24 * time.Hour,
+		DailyRetention:  0,
+		PruneInterval:   time.Hour,
+	}
+}
+
+type statsSource interface {
+	GetTorrentCounts(ctx context.Context, instanceID int) (*qbittorrent.TorrentCounts, error)
+	GetServerState(ctx context.Context, instanceID int) (qbt.ServerState, error)
+}
+
+type statsStore interface {
+	Record(ctx context.Context, sample models.StatsSample, rawInterval time.Duration) error
+	Prune(ctx context.Context, resolution models.StatsResolution, before time.Time) (int64, error)
+}
+
+type instanceLister interface {
+	List(ctx context.Context) ([]*models.Instance, error)
+}
+
+// counters are the cumulative totals a sample's transfer is measured against.
+type counters struct {
+	uploaded   int64
+	downloaded int64
+	trackers   map[string]qbittorrent.TrackerTransferStats
+}
+
+// Service periodically samples every active instance.
+type Service struct {
+	cfg       Config
+	instances instanceLister
+	store     statsStore
+	source    statsSource
+
+	mu       sync.Mutex
+	previous map[int]counters
+}
+
+// NewService creates a stats sampling service.
+func NewService(cfg Config, instanceStore *models.InstanceStore, store *models.StatsStore, syncManager *qbittorrent.SyncManager) *Service {
+	defaults := DefaultConfig()
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaults.Interval
+	}
+	if cfg.PruneInterval <= 0 {
+		cfg.PruneInterval = defaults.PruneInterval
+	}
+	return &Service{
+		cfg:       cfg,
+		instances: instanceStore,
+		store:     store,
+		source:    syncManager,
+		previous:  make(map[int]counters),
+	}
+}
+
+// RawInterval returns the width of raw buckets.
+func (s *Service) RawInterval() time.Duration {
+	return s.cfg.Interval
+}
+
+// Start starts the background sampler.
+func (s *Service) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	go s.loop(ctx)
+}
+
+func (s *Service) loop(ctx context.Context) {
+	sampleTicker := time.NewTicker(s.cfg.Interval)
+	defer sampleTicker.Stop()
+	pruneTicker := time.NewTicker(s.cfg.PruneInterval)
+	defer pruneTicker.Stop()
+
+	s.prune(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-sampleTicker.C:
+			s.sampleAll(ctx, now)
+		case now := <-pruneTicker.C:
+			s.prune(ctx, now)
+		}
+	}
+}
+
+func (s *Service) sampleAll(ctx context.Context, now time.Time) {
+	instances, err := s.instances.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("stats: failed to list instances")
+		return
+	}
+
+	for _, instance := range instances {
+		if !instance.IsActive {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		sampleCtx, cancel := context.WithTimeout(ctx, sampleTimeout)
+		if err := s.sampleInstance(sampleCtx, instance.ID, now); err != nil {
+			log.Debug().Err(err).Int("instanceID", instance.ID).Msg("stats: failed to sample instance")
+		}
+		cancel()
+	}
+}
+
+// sampleInstance records one sample. The first sample after startup only
+// establishes a baseline, so its transfer is zero.
+func (s *Service) sampleInstance(ctx context.Context, instanceID int, now time.Time) error {
+	counts, err := s.source.GetTorrentCounts(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	state, err := s.source.GetServerState(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	sample := models.StatsSample{
+		InstanceID:    instanceID,
+		Time:          now,
+		UploadSpeed:   state.UpInfoSpeed,
+		DownloadSpeed: state.DlInfoSpeed,
+		Torrents:      counts.Total,
+		Seeding:       counts.Status["seeding"],
+		Downloading:   counts.Status["downloading"],
+	}
+	if ratio, err := strconv.ParseFloat(state.GlobalRatio, 64); err == nil {
+		sample.Ratio = ratio
+	}
+
+	current := counters{
+		uploaded:   state.AlltimeUl,
+		downloaded: state.AlltimeDl,
+		trackers:   counts.TrackerTransfers,
+	}
+
+	s.mu.Lock()
+	previous, hasPrevious := s.previous[instanceID]
+	s.previous[instanceID] = current
+	s.mu.Unlock()
+
+	if hasPrevious {
+		sample.Uploaded = increase(previous.uploaded, current.uploaded)
+		sample.Downloaded = increase(previous.downloaded, current.downloaded)
+	}
+
+	// Tracker totals are sums over the torrents currently on that tracker, so a
+	// removed torrent shrinks them. Shrinking is clamped to zero rather than
+	// counted as negative transfer; trackers seen for the first time start a
+	// baseline.
+	sample.Trackers = make([]models.TrackerStatsSample, 0, len(current.trackers))
+	for domain, transfer := range current.trackers {
+		entry := models.TrackerStatsSample{Tracker: domain, Torrents: transfer.Count}
+		if old, ok := previous.trackers[domain]; ok {
+			entry.Uploaded = increase(old.Uploaded, transfer.Uploaded)
+			entry.Downloaded = increase(old.Downloaded, transfer.Downloaded)
+		}
+		sample.Trackers = append(sample.Trackers, entry)
+	}
+
+	return s.store.Record(ctx, sample, s.cfg.Interval)
+}
+
+func increase(previous, current int64) int64 {
+	if current < previous {
+		return 0
+	}
+	return current - previous
+}
+
+func (s *Service) prune(ctx context.Context, now time.Time) {
+	retention := map[models.StatsResolution]time.Duration{
+		models.StatsResolutionRaw:  s.cfg.RawRetention,
+		models.StatsResolutionHour: s.cfg.HourlyRetention,
+		models.StatsResolutionDay:  s.cfg.DailyRetention,
+	}
+	for resolution, keep := range retention {
+		if keep <= 0 {
+			continue
+		}
+		deleted, err := s.store.Prune(ctx, resolution, now.Add(-keep))
+		if err != nil {
+			log.Error().Err(err).Str("resolution", string(resolution)).Msg("stats: failed to prune history")
+			continue
+		}
+		if deleted > 0 {
+			log.Debug().Int64("deleted", deleted).Str("resolution", string(resolution)).Msg("stats: pruned history")
+		}
+	}
+}
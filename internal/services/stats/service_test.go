@@ -0,0 +1,109 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package stats
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+type fakeSource struct {
+	counts *qbittorrent.TorrentCounts
+	state  qbt.ServerState
+}
+
+func (f *fakeSource) GetTorrentCounts(context.Context, int) (*qbittorrent.TorrentCounts, error) {
+	return f.counts, nil
+}
+
+func (f *fakeSource) GetServerState(context.Context, int) (qbt.ServerState, error) {
+	return f.state, nil
+}
+
+type fakeStore struct {
+	samples []models.StatsSample
+	pruned  map[models.StatsResolution]time.Time
+}
+
+func (f *fakeStore) Record(_ context.Context, sample models.StatsSample, _ time.Duration) error {
+	slices.SortFunc(sample.Trackers, func(a, b models.TrackerStatsSample) int { return strings.Compare(a.Tracker, b.Tracker) })
+	f.samples = append(f.samples, sample)
+	return nil
+}
+
+func (f *fakeStore) Prune(_ context.Context, resolution models.StatsResolution, before time.Time) (int64, error) {
+	if f.pruned == nil {
+		f.pruned = make(map[models.StatsResolution]time.Time)
+	}
+	f.pruned[resolution] = before
+	return 0, nil
+}
+
+func TestSampleInstance_ComputesTransferSinceBaseline(t *testing.T) {
+	source := &fakeSource{
+		counts: &qbittorrent.TorrentCounts{
+			Total:  3,
+			Status: map[string]int{"seeding": 2, "downloading": 1},
+			TrackerTransfers: map[string]qbittorrent.TrackerTransferStats{
+				"a.example": {Uploaded: 1000, Downloaded: 100, Count: 2},
+				"b.example": {Uploaded: 500, Count: 1},
+			},
+		},
+		state: qbt.ServerState{AlltimeUl: 10_000, AlltimeDl: 2_000, UpInfoSpeed: 50, DlInfoSpeed: 5, GlobalRatio: "4.25"},
+	}
+	store := &fakeStore{}
+	svc := &Service{cfg: DefaultConfig(), store: store, source: source, previous: make(map[int]counters)}
+	ctx := context.Background()
+	now := time.Date(2025, 3, 4, 13, 0, 0, 0, time.UTC)
+
+	require.NoError(t, svc.sampleInstance(ctx, 1, now))
+	require.Len(t, store.samples, 1)
+	first := store.samples[0]
+	assert.Zero(t, first.Uploaded, "first sample only sets the baseline")
+	assert.Equal(t, int64(50), first.UploadSpeed)
+	assert.Equal(t, 2, first.Seeding)
+	assert.InDelta(t, 4.25, first.Ratio, 0.0001)
+	require.Len(t, first.Trackers, 2)
+	assert.Zero(t, first.Trackers[0].Uploaded)
+
+	source.state.AlltimeUl = 10_600
+	source.state.AlltimeDl = 2_050
+	source.counts.TrackerTransfers = map[string]qbittorrent.TrackerTransferStats{
+		"a.example": {Uploaded: 1400, Downloaded: 150, Count: 2},
+		"b.example": {Uploaded: 0, Count: 0}, // torrent removed
+		"c.example": {Uploaded: 900, Count: 1},
+	}
+
+	require.NoError(t, svc.sampleInstance(ctx, 1, now.Add(time.Minute)))
+	second := store.samples[1]
+	assert.Equal(t, int64(600), second.Uploaded)
+	assert.Equal(t, int64(50), second.Downloaded)
+	assert.Equal(t, []models.TrackerStatsSample{
+		{Tracker: "a.example", Uploaded: 400, Downloaded: 50, Torrents: 2},
+		{Tracker: "b.example"},
+		{Tracker: "c.example", Torrents: 1},
+	}, second.Trackers)
+}
+
+func TestPrune_SkipsUnlimitedRetention(t *testing.T) {
+	store := &fakeStore{}
+	svc := &Service{cfg: DefaultConfig(), store: store}
+	now := time.Date(2025, 3, 4, 13, 0, 0, 0, time.UTC)
+
+	svc.prune(context.Background(), now)
+
+	assert.Equal(t, now.Add(-7*24*time.Hour), store.pruned[models.StatsResolutionRaw])
+	assert.Equal(t, now.Add(-90*24*time.Hour), store.pruned[models.StatsResolutionHour])
+	assert.NotContains(t, store.pruned, models.StatsResolutionDay)
+}
@@ -0,0 +1,484 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package trackerrotation rewrites tracker URLs, such as a reset passkey, across every
+// instance and the cached backup torrents.
+package trackerrotation
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/backups"
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+// editBatchSize bounds how many torrents are edited per tracker edit call so progress
+// advances steadily on large instances.
+const editBatchSize = 50
+
+// maxJobs bounds how many finished jobs are kept in memory.
+const maxJobs = 10
+
+var (
+	// ErrJobRunning is returned when a rotation is started while another is in progress.
+	ErrJobRunning = errors.New("a tracker rotation is already running")
+	// ErrJobNotFound is returned for unknown job IDs.
+	ErrJobNotFound = errors.New("tracker rotation job not found")
+)
+
+type instanceLister interface {
+	List(ctx context.Context) ([]*models.Instance, error)
+}
+
+type torrentSource interface {
+	GetTrackerHashes(ctx context.Context, instanceID int) (map[string][]string, error)
+	GetAllTorrents(ctx context.Context, instanceID int) ([]qbt.Torrent, error)
+	BulkEditTrackers(ctx context.Context, instanceID int, hashes []string, oldURL, newURL string) error
+}
+
+type blobRewriter interface {
+	RewriteTrackerURLs(ctx context.Context, rewrite func(string) (string, bool), dryRun bool, progress func(done, total int)) (*backups.TrackerRewriteResult, error)
+}
+
+// Spec describes a rotation. Every tracker URL containing Find, optionally limited to
+// hosts on Domain, has Find replaced with Replace. Find can be a full announce URL
+// prefix or just the old passkey.
+type Spec struct {
+	Domain         string `json:"domain,omitempty"`
+	Find           string `json:"find"`
+	Replace        string `json:"replace"`
+	RewriteBackups bool   `json:"rewriteBackups"`
+}
+
+// Validate returns a user-facing error when the spec cannot be applied.
+func (s Spec) Validate() error {
+	switch {
+	case strings.TrimSpace(s.Find) == "":
+		return errors.New("the old URL or passkey is required")
+	case strings.TrimSpace(s.Replace) == "":
+		return errors.New("the new URL or passkey is required")
+	case s.Find == s.Replace:
+		return errors.New("the new value must differ from the old value")
+	default:
+		return nil
+	}
+}
+
+// Rewrite returns the rotated form of trackerURL and whether the spec applies to it.
+func (s Spec) Rewrite(trackerURL string) (string, bool) {
+	if s.Find == "" || !strings.Contains(trackerURL, s.Find) {
+		return "", false
+	}
+	if domain := strings.ToLower(strings.TrimSpace(s.Domain)); domain != "" {
+		parsed, err := url.Parse(trackerURL)
+		if err != nil {
+			return "", false
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			return "", false
+		}
+	}
+
+	rewritten := strings.ReplaceAll(trackerURL, s.Find, s.Replace)
+	if rewritten == trackerURL {
+		return "", false
+	}
+	return rewritten, true
+}
+
+// TrackerChange is a single tracker URL that will be or was rewritten.
+type TrackerChange struct {
+	OldURL string `json:"oldUrl"`
+	NewURL string `json:"newUrl"`
+}
+
+// TorrentMatch is a torrent announcing to at least one matching tracker.
+type TorrentMatch struct {
+	Hash     string          `json:"hash"`
+	Name     string          `json:"name"`
+	Trackers []TrackerChange `json:"trackers"`
+}
+
+// InstancePreview lists the torrents on one instance a rotation would change.
+type InstancePreview struct {
+	InstanceID   int            `json:"instanceId"`
+	InstanceName string         `json:"instanceName"`
+	Torrents     []TorrentMatch `json:"torrents"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// Preview is the dry-run result of a rotation.
+type Preview struct {
+	Instances     []InstancePreview             `json:"instances"`
+	TotalTorrents int                           `json:"totalTorrents"`
+	Backups       *backups.TrackerRewriteResult `json:"backups,omitempty"`
+}
+
+// JobStatus is the lifecycle state of a rotation job.
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Job phases reported in Progress.
+const (
+	PhaseTorrents = "torrents"
+	PhaseBackups  = "backups"
+)
+
+// Progress reports how far the current phase of a job has advanced.
+type Progress struct {
+	Phase     string `json:"phase"`
+	Processed int    `json:"processed"`
+	Total     int    `json:"total"`
+}
+
+// InstanceResult is the outcome of a rotation on one instance.
+type InstanceResult struct {
+	InstanceID   int    `json:"instanceId"`
+	InstanceName string `json:"instanceName"`
+	Matched      int    `json:"matched"`
+	Updated      int    `json:"updated"`
+	Failed       int    `json:"failed"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Job is a rotation applied in the background.
+type Job struct {
+	ID          int64                         `json:"id"`
+	Spec        Spec                          `json:"spec"`
+	Status      JobStatus                     `json:"status"`
+	Progress    Progress                      `json:"progress"`
+	Instances   []InstanceResult              `json:"instances"`
+	Backups     *backups.TrackerRewriteResult `json:"backups,omitempty"`
+	Error       string                        `json:"error,omitempty"`
+	StartedAt   time.Time                     `json:"startedAt"`
+	CompletedAt *time.Time                    `json:"completedAt,omitempty"`
+}
+
+// Service previews and applies tracker rotations. Jobs are kept in memory only.
+type Service struct {
+	instances instanceLister
+	source    torrentSource
+	backups   blobRewriter
+
+	mu     sync.Mutex
+	jobs   []*Job
+	nextID int64
+}
+
+// NewService creates a tracker rotation service. backupService may be nil, in which
+// case backup blobs are never rewritten.
+func NewService(instanceStore *models.InstanceStore, syncManager *qbittorrent.SyncManager, backupService *backups.Service) *Service {
+	s := &Service{
+		instances: instanceStore,
+		source:    syncManager,
+	}
+	if backupService != nil {
+		s.backups = backupService
+	}
+	return s
+}
+
+// Preview lists the torrents on every active instance and, when requested, the backup
+// blobs that the spec would change.
+func (s *Service) Preview(ctx context.Context, spec Spec) (*Preview, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	instances, err := s.activeInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &Preview{Instances: make([]InstancePreview, 0, len(instances))}
+	for _, instance := range instances {
+		entry := InstancePreview{InstanceID: instance.ID, InstanceName: instance.Name}
+		matches, err := s.matchInstance(ctx, instance.ID, spec)
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Torrents = matches
+			preview.TotalTorrents += len(matches)
+		}
+		preview.Instances = append(preview.Instances, entry)
+	}
+
+	if spec.RewriteBackups && s.backups != nil {
+		result, err := s.backups.RewriteTrackerURLs(ctx, spec.Rewrite, true, nil)
+		if err != nil {
+			return nil, fmt.Errorf("scan backups: %w", err)
+		}
+		preview.Backups = result
+	}
+
+	return preview, nil
+}
+
+// Start validates spec and applies it in the background, returning a snapshot of the new job.
+func (s *Service) Start(spec Spec) (*Job, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		if job.Status == JobStatusRunning {
+			s.mu.Unlock()
+			return nil, ErrJobRunning
+		}
+	}
+	s.nextID++
+	job := &Job{
+		ID:        s.nextID,
+		Spec:      spec,
+		Status:    JobStatusRunning,
+		Progress:  Progress{Phase: PhaseTorrents},
+		Instances: []InstanceResult{},
+		StartedAt: time.Now().UTC(),
+	}
+	s.jobs = append(s.jobs, job)
+	if len(s.jobs) > maxJobs {
+		s.jobs = s.jobs[len(s.jobs)-maxJobs:]
+	}
+	snapshot := cloneJob(job)
+	s.mu.Unlock()
+
+	go s.run(context.Background(), job)
+
+	return snapshot, nil
+}
+
+// GetJob returns a snapshot of a job.
+func (s *Service) GetJob(id int64) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if job.ID == id {
+			return cloneJob(job), nil
+		}
+	}
+	return nil, ErrJobNotFound
+}
+
+// ListJobs returns snapshots of recent jobs, newest first.
+func (s *Service) ListJobs() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, cloneJob(s.jobs[i]))
+	}
+	return jobs
+}
+
+func (s *Service) run(ctx context.Context, job *Job) {
+	err := s.apply(ctx, job)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	job.CompletedAt = &now
+	if err != nil {
+		job.Status = JobStatusFailed
+		job.Error = err.Error()
+		log.Error().Err(err).Int64("jobID", job.ID).Msg("trackerrotation: job failed")
+		return
+	}
+	job.Status = JobStatusCompleted
+	log.Info().Int64("jobID", job.ID).Msg("trackerrotation: job completed")
+}
+
+func (s *Service) apply(ctx context.Context, job *Job) error {
+	instances, err := s.activeInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("list instances: %w", err)
+	}
+
+	type plannedInstance struct {
+		instance *models.Instance
+		matched  int
+		batches  []editBatch
+		err      error
+	}
+
+	planned := make([]plannedInstance, 0, len(instances))
+	total := 0
+	for _, instance := range instances {
+		matches, err := s.matchInstance(ctx, instance.ID, job.Spec)
+		batches := editBatches(matches)
+		planned = append(planned, plannedInstance{instance: instance, matched: len(matches), batches: batches, err: err})
+		for _, batch := range batches {
+			total += len(batch.hashes)
+		}
+	}
+	s.update(job, func(j *Job) { j.Progress.Total = total })
+
+	processed := 0
+	for _, plan := range planned {
+		result := InstanceResult{
+			InstanceID:   plan.instance.ID,
+			InstanceName: plan.instance.Name,
+			Matched:      plan.matched,
+		}
+		if plan.err != nil {
+			result.Error = plan.err.Error()
+			s.update(job, func(j *Job) { j.Instances = append(j.Instances, result) })
+			continue
+		}
+
+		var lastErr error
+		for _, batch := range plan.batches {
+			if err := s.source.BulkEditTrackers(ctx, plan.instance.ID, batch.hashes, batch.change.OldURL, batch.change.NewURL); err != nil {
+				log.Warn().Err(err).Int("instanceID", plan.instance.ID).Str("oldURL", batch.change.OldURL).Msg("trackerrotation: failed to edit trackers")
+				result.Failed += len(batch.hashes)
+				lastErr = err
+			} else {
+				result.Updated += len(batch.hashes)
+			}
+			processed += len(batch.hashes)
+			s.update(job, func(j *Job) { j.Progress.Processed = processed })
+		}
+		if lastErr != nil {
+			result.Error = lastErr.Error()
+		}
+		s.update(job, func(j *Job) { j.Instances = append(j.Instances, result) })
+	}
+
+	if !job.Spec.RewriteBackups || s.backups == nil {
+		return nil
+	}
+
+	s.update(job, func(j *Job) { j.Progress = Progress{Phase: PhaseBackups} })
+	result, err := s.backups.RewriteTrackerURLs(ctx, job.Spec.Rewrite, false, func(done, total int) {
+		s.update(job, func(j *Job) { j.Progress.Processed, j.Progress.Total = done, total })
+	})
+	s.update(job, func(j *Job) { j.Backups = result })
+	if err != nil {
+		return fmt.Errorf("rewrite backups: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) update(job *Job, fn func(*Job)) {
+	s.mu.Lock()
+	fn(job)
+	s.mu.Unlock()
+}
+
+func (s *Service) activeInstances(ctx context.Context) ([]*models.Instance, error) {
+	instances, err := s.instances.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(instances, func(instance *models.Instance) bool {
+		return instance == nil || !instance.IsActive
+	}), nil
+}
+
+// matchInstance returns the torrents on an instance with at least one tracker the spec
+// rewrites, ordered by name.
+func (s *Service) matchInstance(ctx context.Context, instanceID int, spec Spec) ([]TorrentMatch, error) {
+	trackers, err := s.source.GetTrackerHashes(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	byHash := make(map[string]*TorrentMatch)
+	for trackerURL, hashes := range trackers {
+		newURL, ok := spec.Rewrite(trackerURL)
+		if !ok {
+			continue
+		}
+		for _, hash := range hashes {
+			match, ok := byHash[hash]
+			if !ok {
+				match = &TorrentMatch{Hash: hash}
+				byHash[hash] = match
+			}
+			match.Trackers = append(match.Trackers, TrackerChange{OldURL: trackerURL, NewURL: newURL})
+		}
+	}
+	if len(byHash) == 0 {
+		return []TorrentMatch{}, nil
+	}
+
+	torrents, err := s.source.GetAllTorrents(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, torrent := range torrents {
+		if match, ok := byHash[torrent.Hash]; ok {
+			match.Name = torrent.Name
+		}
+	}
+
+	matches := make([]TorrentMatch, 0, len(byHash))
+	for _, match := range byHash {
+		slices.SortFunc(match.Trackers, func(a, b TrackerChange) int { return cmp.Compare(a.OldURL, b.OldURL) })
+		matches = append(matches, *match)
+	}
+	slices.SortFunc(matches, func(a, b TorrentMatch) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), cmp.Compare(a.Hash, b.Hash))
+	})
+	return matches, nil
+}
+
+type editBatch struct {
+	change TrackerChange
+	hashes []string
+}
+
+// editBatches groups matched torrents by tracker change, since a tracker edit takes a
+// single old and new URL, and splits the groups into editBatchSize chunks.
+func editBatches(matches []TorrentMatch) []editBatch {
+	grouped := make(map[TrackerChange][]string)
+	var order []TrackerChange
+	for _, match := range matches {
+		for _, change := range match.Trackers {
+			if _, ok := grouped[change]; !ok {
+				order = append(order, change)
+			}
+			grouped[change] = append(grouped[change], match.Hash)
+		}
+	}
+
+	var batches []editBatch
+	for _, change := range order {
+		for chunk := range slices.Chunk(grouped[change], editBatchSize) {
+			batches = append(batches, editBatch{change: change, hashes: chunk})
+		}
+	}
+	return batches
+}
+
+func cloneJob(job *Job) *Job {
+	clone := *job
+	clone.Instances = slices.Clone(job.Instances)
+	if job.Backups != nil {
+		backupsCopy := *job.Backups
+		clone.Backups = &backupsCopy
+	}
+	if job.CompletedAt != nil {
+		completedAt := *job.CompletedAt
+		clone.CompletedAt = &completedAt
+	}
+	return &clone
+}
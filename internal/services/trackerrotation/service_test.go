@@ -0,0 +1,223 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package trackerrotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/backups"
+	"github.com/autobrr/qui/internal/models"
+)
+
+type fakeInstances []*models.Instance
+
+func (f fakeInstances) List(context.Context) ([]*models.Instance, error) {
+	return f, nil
+}
+
+type editCall struct {
+	instanceID int
+	hashes     []string
+	oldURL     string
+	newURL     string
+}
+
+type fakeSource struct {
+	trackers map[int]map[string][]string
+	torrents map[int][]qbt.Torrent
+	failOn   map[int]error
+
+	mu    sync.Mutex
+	edits []editCall
+}
+
+func (f *fakeSource) GetTrackerHashes(_ context.Context, instanceID int) (map[string][]string, error) {
+	if err := f.failOn[instanceID]; err != nil {
+		return nil, err
+	}
+	return f.trackers[instanceID], nil
+}
+
+func (f *fakeSource) GetAllTorrents(_ context.Context, instanceID int) ([]qbt.Torrent, error) {
+	return f.torrents[instanceID], nil
+}
+
+func (f *fakeSource) BulkEditTrackers(_ context.Context, instanceID int, hashes []string, oldURL, newURL string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.edits = append(f.edits, editCall{instanceID: instanceID, hashes: hashes, oldURL: oldURL, newURL: newURL})
+	return nil
+}
+
+type fakeBlobs struct {
+	dryRuns int
+	applied int
+}
+
+func (f *fakeBlobs) RewriteTrackerURLs(_ context.Context, _ func(string) (string, bool), dryRun bool, progress func(done, total int)) (*backups.TrackerRewriteResult, error) {
+	if dryRun {
+		f.dryRuns++
+		return &backups.TrackerRewriteResult{BlobsScanned: 3, BlobsMatched: 2}, nil
+	}
+	f.applied++
+	if progress != nil {
+		progress(3, 3)
+	}
+	return &backups.TrackerRewriteResult{BlobsScanned: 3, BlobsMatched: 2, BlobsRewritten: 2, ItemsUpdated: 4}, nil
+}
+
+func TestSpecRewrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    Spec
+		url     string
+		want    string
+		matches bool
+	}{
+		{
+			name:    "passkey on domain",
+			spec:    Spec{Domain: "tracker.example", Find: "oldkey", Replace: "newkey"},
+			url:     "https://tracker.example/oldkey/announce",
+			want:    "https://tracker.example/newkey/announce",
+			matches: true,
+		},
+		{
+			name:    "subdomain matches domain",
+			spec:    Spec{Domain: "example", Find: "oldkey", Replace: "newkey"},
+			url:     "https://tracker.example/announce?passkey=oldkey",
+			want:    "https://tracker.example/announce?passkey=newkey",
+			matches: true,
+		},
+		{
+			name: "other domain ignored",
+			spec: Spec{Domain: "tracker.example", Find: "oldkey", Replace: "newkey"},
+			url:  "https://other.example/oldkey/announce",
+		},
+		{
+			name:    "url prefix without domain",
+			spec:    Spec{Find: "https://old.example/abc", Replace: "https://new.example/xyz"},
+			url:     "https://old.example/abc/announce",
+			want:    "https://new.example/xyz/announce",
+			matches: true,
+		},
+		{
+			name: "no match",
+			spec: Spec{Find: "oldkey", Replace: "newkey"},
+			url:  "https://tracker.example/announce",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.spec.Rewrite(tt.url)
+			assert.Equal(t, tt.matches, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	require.Error(t, Spec{Replace: "new"}.Validate())
+	require.Error(t, Spec{Find: "old"}.Validate())
+	require.Error(t, Spec{Find: "same", Replace: "same"}.Validate())
+	require.NoError(t, Spec{Find: "old", Replace: "new"}.Validate())
+}
+
+func newTestService(source *fakeSource, blobs *fakeBlobs) *Service {
+	return &Service{
+		instances: fakeInstances{
+			{ID: 1, Name: "seedbox", IsActive: true},
+			{ID: 2, Name: "home", IsActive: true},
+			{ID: 3, Name: "disabled", IsActive: false},
+		},
+		source:  source,
+		backups: blobs,
+	}
+}
+
+func TestPreview(t *testing.T) {
+	source := &fakeSource{
+		trackers: map[int]map[string][]string{
+			1: {
+				"https://tracker.example/oldkey/announce": {"aaa", "bbb"},
+				"https://other.example/oldkey/announce":   {"ccc"},
+			},
+			2: {"https://tracker.example/oldkey/announce": {"ddd"}},
+			3: {"https://tracker.example/oldkey/announce": {"eee"}},
+		},
+		torrents: map[int][]qbt.Torrent{
+			1: {{Hash: "aaa", Name: "Beta"}, {Hash: "bbb", Name: "Alpha"}, {Hash: "ccc", Name: "Gamma"}},
+			2: {{Hash: "ddd", Name: "Delta"}},
+		},
+	}
+	blobs := &fakeBlobs{}
+	svc := newTestService(source, blobs)
+
+	preview, err := svc.Preview(context.Background(), Spec{Domain: "tracker.example", Find: "oldkey", Replace: "newkey", RewriteBackups: true})
+	require.NoError(t, err)
+
+	require.Len(t, preview.Instances, 2, "inactive instances are skipped")
+	assert.Equal(t, 3, preview.TotalTorrents)
+	require.Len(t, preview.Instances[0].Torrents, 2)
+	assert.Equal(t, "Alpha", preview.Instances[0].Torrents[0].Name)
+	assert.Equal(t, "https://tracker.example/newkey/announce", preview.Instances[0].Torrents[0].Trackers[0].NewURL)
+	require.NotNil(t, preview.Backups)
+	assert.Equal(t, 2, preview.Backups.BlobsMatched)
+	assert.Equal(t, 1, blobs.dryRuns)
+	assert.Empty(t, source.edits, "preview must not edit trackers")
+}
+
+func TestStartAppliesRotation(t *testing.T) {
+	hashes := make([]string, editBatchSize+5)
+	for i := range hashes {
+		hashes[i] = fmt.Sprintf("hash%03d", i)
+	}
+	source := &fakeSource{
+		trackers: map[int]map[string][]string{
+			1: {"https://tracker.example/oldkey/announce": hashes},
+		},
+		failOn: map[int]error{2: errors.New("instance offline")},
+	}
+	blobs := &fakeBlobs{}
+	svc := newTestService(source, blobs)
+
+	started, err := svc.Start(Spec{Find: "oldkey", Replace: "newkey", RewriteBackups: true})
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusRunning, started.Status)
+
+	var job *Job
+	require.Eventually(t, func() bool {
+		job, err = svc.GetJob(started.ID)
+		return err == nil && job.Status != JobStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, JobStatusCompleted, job.Status)
+	require.Len(t, job.Instances, 2)
+	assert.Equal(t, len(hashes), job.Instances[0].Updated)
+	assert.Equal(t, "instance offline", job.Instances[1].Error)
+	require.NotNil(t, job.Backups)
+	assert.Equal(t, 2, job.Backups.BlobsRewritten)
+	assert.Equal(t, Progress{Phase: PhaseBackups, Processed: 3, Total: 3}, job.Progress)
+
+	require.Len(t, source.edits, 2, "edits are split into batches")
+	assert.Len(t, source.edits[0].hashes, editBatchSize)
+	assert.Equal(t, "https://tracker.example/newkey/announce", source.edits[0].newURL)
+}
+
+func TestStartRejectsConcurrentJobs(t *testing.T) {
+	svc := newTestService(&fakeSource{}, &fakeBlobs{})
+	svc.jobs = []*Job{{ID: 1, Status: JobStatusRunning}}
+
+	_, err := svc.Start(Spec{Find: "old", Replace: "new"})
+	require.ErrorIs(t, err, ErrJobRunning)
+}
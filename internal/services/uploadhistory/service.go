@@ -0,0 +1,338 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package uploadhistory records per-torrent upload snapshots and derives
+// recent-upload metrics from them.
+package uploadhistory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+const (
+	snapshotTimeout = time.Minute
+
+	week  = 7 * 24 * time.Hour
+	month = 30 * 24 * time.Hour
+)
+
+// Config controls how often snapshots are taken and how long they are kept.
+type Config struct {
+	Interval      time.Duration
+	Retention     time.Duration
+	PruneInterval time.Duration
+}
+
+// DefaultConfig returns the default snapshot configuration.
+func DefaultConfig() Config {
+	return Config{
+		Interval:      15 * time.Minute,
+		Retention:     month + 24*time.Hour,
+		PruneInterval: 6 * time.Hour,
+	}
+}
+
+type torrentSource interface {
+	GetAllTorrents(ctx context.Context, instanceID int) ([]qbt.Torrent, error)
+}
+
+type historyStore interface {
+	List(ctx context.Context) ([]models.TorrentUploadSnapshot, error)
+	Record(ctx context.Context, snapshots []models.TorrentUploadSnapshot) error
+	DeleteTorrents(ctx context.Context, instanceID int, hashes []string) (int64, error)
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+type instanceLister interface {
+	List(ctx context.Context) ([]*models.Instance, error)
+}
+
+// snapshot is one stored observation; a torrent keeps at most one per UTC day.
+type snapshot struct {
+	uploaded   int64
+	recordedAt int64
+}
+
+// Service snapshots every active instance's torrents and serves the derived
+// upload history to the sync manager.
+type Service struct {
+	cfg       Config
+	instances instanceLister
+	store     historyStore
+	source    torrentSource
+
+	mu      sync.RWMutex
+	history map[int]map[string][]snapshot
+}
+
+// NewService creates an upload history service.
+func NewService(cfg Config, instanceStore *models.InstanceStore, store *models.TorrentUploadHistoryStore, syncManager *qbittorrent.SyncManager) *Service {
+	defaults := DefaultConfig()
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaults.Interval
+	}
+	if cfg.Retention < month {
+		cfg.Retention = defaults.Retention
+	}
+	if cfg.PruneInterval <= 0 {
+		cfg.PruneInterval = defaults.PruneInterval
+	}
+	return &Service{
+		cfg:       cfg,
+		instances: instanceStore,
+		store:     store,
+		source:    syncManager,
+		history:   make(map[int]map[string][]snapshot),
+	}
+}
+
+// Start loads the stored history and starts the background snapshotter.
+func (s *Service) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	go s.loop(ctx)
+}
+
+func (s *Service) loop(ctx context.Context) {
+	if err := s.load(ctx); err != nil {
+		log.Error().Err(err).Msg("uploadhistory: failed to load stored history")
+	}
+
+	snapshotTicker := time.NewTicker(s.cfg.Interval)
+	defer snapshotTicker.Stop()
+	pruneTicker := time.NewTicker(s.cfg.PruneInterval)
+	defer pruneTicker.Stop()
+
+	s.prune(ctx, time.Now())
+	s.snapshotAll(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-snapshotTicker.C:
+			s.snapshotAll(ctx, now)
+		case now := <-pruneTicker.C:
+			s.prune(ctx, now)
+		}
+	}
+}
+
+func (s *Service) load(ctx context.Context) error {
+	stored, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	history := make(map[int]map[string][]snapshot)
+	for _, row := range stored {
+		torrents := history[row.InstanceID]
+		if torrents == nil {
+			torrents = make(map[string][]snapshot)
+			history[row.InstanceID] = torrents
+		}
+		torrents[row.Hash] = append(torrents[row.Hash], snapshot{uploaded: row.Uploaded, recordedAt: row.RecordedAt.Unix()})
+	}
+
+	s.mu.Lock()
+	s.history = history
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) snapshotAll(ctx context.Context, now time.Time) {
+	instances, err := s.instances.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("uploadhistory: failed to list instances")
+		return
+	}
+
+	known := make(map[int]struct{}, len(instances))
+	for _, instance := range instances {
+		known[instance.ID] = struct{}{}
+		if !instance.IsActive {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		snapshotCtx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+		if err := s.snapshotInstance(snapshotCtx, instance.ID, now); err != nil {
+			log.Debug().Err(err).Int("instanceID", instance.ID).Msg("uploadhistory: failed to snapshot instance")
+		}
+		cancel()
+	}
+
+	// Rows of deleted instances go with the instance; drop them from memory too.
+	s.mu.Lock()
+	for instanceID := range s.history {
+		if _, ok := known[instanceID]; !ok {
+			delete(s.history, instanceID)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// snapshotInstance records the uploaded counter of every torrent whose counter
+// changed since its last snapshot, and forgets torrents that are gone.
+func (s *Service) snapshotInstance(ctx context.Context, instanceID int, now time.Time) error {
+	torrents, err := s.source.GetAllTorrents(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	day := models.UploadHistoryDay(now)
+	var changed []models.TorrentUploadSnapshot
+	var removed []string
+
+	s.mu.Lock()
+	history := s.history[instanceID]
+	if history == nil {
+		history = make(map[string][]snapshot)
+		s.history[instanceID] = history
+	}
+
+	present := make(map[string]struct{}, len(torrents))
+	for _, torrent := range torrents {
+		present[torrent.Hash] = struct{}{}
+
+		snapshots := history[torrent.Hash]
+		if n := len(snapshots); n > 0 && snapshots[n-1].uploaded == torrent.Uploaded {
+			continue
+		}
+
+		next := snapshot{uploaded: torrent.Uploaded, recordedAt: now.Unix()}
+		if n := len(snapshots); n > 0 && models.UploadHistoryDay(time.Unix(snapshots[n-1].recordedAt, 0)) == day {
+			snapshots[n-1] = next
+		} else {
+			snapshots = append(snapshots, next)
+		}
+		history[torrent.Hash] = snapshots
+
+		changed = append(changed, models.TorrentUploadSnapshot{
+			InstanceID: instanceID,
+			Hash:       torrent.Hash,
+			Uploaded:   torrent.Uploaded,
+			RecordedAt: now,
+		})
+	}
+
+	// An empty list is more likely a client hiccup than every torrent being
+	// removed, so keep the history until torrents show up again.
+	if len(torrents) > 0 {
+		for hash := range history {
+			if _, ok := present[hash]; !ok {
+				removed = append(removed, hash)
+				delete(history, hash)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.store.Record(ctx, changed); err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		if _, err := s.store.DeleteTorrents(ctx, instanceID, removed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prune drops snapshots older than the retention, keeping each torrent's
+// newest snapshot before the cutoff as the anchor for the longest window.
+func (s *Service) prune(ctx context.Context, now time.Time) {
+	cutoff := now.Add(-s.cfg.Retention)
+
+	deleted, err := s.store.Prune(ctx, cutoff)
+	if err != nil {
+		log.Error().Err(err).Msg("uploadhistory: failed to prune history")
+	} else if deleted > 0 {
+		log.Debug().Int64("deleted", deleted).Msg("uploadhistory: pruned history")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, torrents := range s.history {
+		for hash, snapshots := range torrents {
+			if anchor := lastBefore(snapshots, cutoff.Unix()); anchor > 0 {
+				torrents[hash] = append([]snapshot(nil), snapshots[anchor:]...)
+			}
+		}
+	}
+}
+
+// UploadHistory implements qbittorrent.UploadHistoryProvider.
+func (s *Service) UploadHistory(instanceID int, torrent qbt.Torrent, now time.Time) (qbittorrent.UploadHistory, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := s.history[instanceID][torrent.Hash]
+	if len(snapshots) == 0 {
+		return qbittorrent.UploadHistory{}, false
+	}
+	return summarize(snapshots, torrent, now), true
+}
+
+// summarize derives upload metrics from a torrent's snapshots. Windows are
+// measured against the newest snapshot taken before the window started, so
+// they are accurate to the snapshot interval, or to a day when later changes
+// on that day replaced it.
+func summarize(snapshots []snapshot, torrent qbt.Torrent, now time.Time) qbittorrent.UploadHistory {
+	history := qbittorrent.UploadHistory{
+		TrackedSince: snapshots[0].recordedAt,
+	}
+	for i := 1; i < len(snapshots); i++ {
+		if snapshots[i].uploaded > snapshots[i-1].uploaded {
+			history.LastUpload = snapshots[i].recordedAt
+		}
+	}
+	// The live counter moved past the last snapshot, so it uploaded since then.
+	if torrent.Uploaded > snapshots[len(snapshots)-1].uploaded {
+		history.LastUpload = now.Unix()
+	}
+
+	history.UploadedLast7d = uploadedSince(snapshots, torrent, now.Add(-week))
+	history.UploadedLast30d = uploadedSince(snapshots, torrent, now.Add(-month))
+	return history
+}
+
+// uploadedSince returns the bytes uploaded after cutoff, or nil when the
+// history doesn't reach back that far.
+func uploadedSince(snapshots []snapshot, torrent qbt.Torrent, cutoff time.Time) *int64 {
+	var base int64
+	switch {
+	case torrent.AddedOn >= cutoff.Unix():
+		// Everything the torrent uploaded happened inside the window.
+	case snapshots[0].recordedAt <= cutoff.Unix():
+		base = snapshots[lastBefore(snapshots, cutoff.Unix())].uploaded
+	default:
+		return nil
+	}
+
+	uploaded := max(torrent.Uploaded-base, 0)
+	return &uploaded
+}
+
+// lastBefore returns the index of the newest snapshot recorded at or before
+// cutoff, or 0 when there is none.
+func lastBefore(snapshots []snapshot, cutoff int64) int {
+	index := 0
+	for i, snap := range snapshots {
+		if snap.recordedAt > cutoff {
+			break
+		}
+		index = i
+	}
+	return index
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package uploadhistory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type fakeSource struct {
+	torrents []qbt.Torrent
+}
+
+func (f *fakeSource) GetAllTorrents(context.Context, int) ([]qbt.Torrent, error) {
+	return f.torrents, nil
+}
+
+type fakeStore struct {
+	recorded []models.TorrentUploadSnapshot
+	deleted  []string
+}
+
+func (f *fakeStore) List(context.Context) ([]models.TorrentUploadSnapshot, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) Record(_ context.Context, snapshots []models.TorrentUploadSnapshot) error {
+	f.recorded = append(f.recorded, snapshots...)
+	return nil
+}
+
+func (f *fakeStore) DeleteTorrents(_ context.Context, _ int, hashes []string) (int64, error) {
+	f.deleted = append(f.deleted, hashes...)
+	return int64(len(hashes)), nil
+}
+
+func (f *fakeStore) Prune(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestService(source *fakeSource, store *fakeStore) *Service {
+	return &Service{
+		cfg:     DefaultConfig(),
+		store:   store,
+		source:  source,
+		history: make(map[int]map[string][]snapshot),
+	}
+}
+
+func TestSnapshotInstance_RecordsOnlyChanges(t *testing.T) {
+	source := &fakeSource{torrents: []qbt.Torrent{{Hash: "a", Uploaded: 100}, {Hash: "b", Uploaded: 0}}}
+	store := &fakeStore{}
+	svc := newTestService(source, store)
+	ctx := context.Background()
+	day1 := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, svc.snapshotInstance(ctx, 1, day1))
+	assert.Len(t, store.recorded, 2, "first sighting records a baseline")
+
+	source.torrents[0].Uploaded = 150
+	require.NoError(t, svc.snapshotInstance(ctx, 1, day1.Add(time.Hour)))
+	require.Len(t, store.recorded, 3)
+	assert.Equal(t, "a", store.recorded[2].Hash)
+	assert.Len(t, svc.history[1]["a"], 1, "same-day change replaces the day's snapshot")
+
+	source.torrents[0].Uploaded = 175
+	require.NoError(t, svc.snapshotInstance(ctx, 1, day1.Add(24*time.Hour)))
+	assert.Len(t, svc.history[1]["a"], 2)
+
+	source.torrents = source.torrents[:1]
+	require.NoError(t, svc.snapshotInstance(ctx, 1, day1.Add(25*time.Hour)))
+	assert.Equal(t, []string{"b"}, store.deleted)
+	assert.NotContains(t, svc.history[1], "b")
+
+	source.torrents = nil
+	require.NoError(t, svc.snapshotInstance(ctx, 1, day1.Add(26*time.Hour)))
+	assert.Contains(t, svc.history[1], "a", "an empty torrent list keeps the history")
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) int64 { return now.Add(-time.Duration(days) * 24 * time.Hour).Unix() }
+
+	snapshots := []snapshot{
+		{uploaded: 1000, recordedAt: daysAgo(40)},
+		{uploaded: 1500, recordedAt: daysAgo(20)},
+		{uploaded: 1800, recordedAt: daysAgo(5)},
+	}
+	torrent := qbt.Torrent{Uploaded: 1800, AddedOn: daysAgo(60)}
+
+	history := summarize(snapshots, torrent, now)
+	require.NotNil(t, history.UploadedLast7d)
+	require.NotNil(t, history.UploadedLast30d)
+	assert.Equal(t, int64(300), *history.UploadedLast7d)
+	assert.Equal(t, int64(800), *history.UploadedLast30d)
+	assert.Equal(t, daysAgo(5), history.LastUpload)
+	assert.Equal(t, daysAgo(40), history.TrackedSince)
+
+	t.Run("live counter ahead of snapshots", func(t *testing.T) {
+		history := summarize(snapshots, qbt.Torrent{Uploaded: 1900, AddedOn: daysAgo(60)}, now)
+		assert.Equal(t, now.Unix(), history.LastUpload)
+		assert.Equal(t, int64(400), *history.UploadedLast7d)
+	})
+
+	t.Run("window not yet covered", func(t *testing.T) {
+		recent := []snapshot{{uploaded: 1000, recordedAt: daysAgo(10)}}
+		history := summarize(recent, qbt.Torrent{Uploaded: 1200, AddedOn: daysAgo(60)}, now)
+		assert.Equal(t, int64(200), *history.UploadedLast7d)
+		assert.Nil(t, history.UploadedLast30d)
+		assert.Equal(t, now.Unix(), history.LastUpload)
+	})
+
+	t.Run("torrent younger than window", func(t *testing.T) {
+		recent := []snapshot{{uploaded: 400, recordedAt: daysAgo(2)}}
+		history := summarize(recent, qbt.Torrent{Uploaded: 400, AddedOn: daysAgo(3)}, now)
+		assert.Equal(t, int64(400), *history.UploadedLast7d)
+		assert.Equal(t, int64(400), *history.UploadedLast30d)
+		assert.Zero(t, history.LastUpload, "no upload seen since tracking began")
+	})
+}
+
+func TestPrune_KeepsAnchor(t *testing.T) {
+	svc := newTestService(&fakeSource{}, &fakeStore{})
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) int64 { return now.Add(-time.Duration(days) * 24 * time.Hour).Unix() }
+	svc.history[1] = map[string][]snapshot{
+		"a": {
+			{uploaded: 1, recordedAt: daysAgo(50)},
+			{uploaded: 2, recordedAt: daysAgo(40)},
+			{uploaded: 3, recordedAt: daysAgo(10)},
+		},
+	}
+
+	svc.prune(context.Background(), now)
+
+	assert.Equal(t, []snapshot{
+		{uploaded: 2, recordedAt: daysAgo(40)},
+		{uploaded: 3, recordedAt: daysAgo(10)},
+	}, svc.history[1]["a"])
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package watchfolder
+
+import "time"
+
+// Config holds the service configuration.
+type Config struct {
+	// SettleDelay is how long a file must stop changing before it is processed,
+	// so partially written .torrent files are not picked up.
+	SettleDelay time.Duration
+
+	// RescanInterval is how often watched folders are re-read from the database and
+	// scanned for files whose events were missed (e.g. network shares).
+	RescanInterval time.Duration
+}
+
+// DefaultConfig returns the default service configuration.
+func DefaultConfig() Config {
+	return Config{
+		SettleDelay:    2 * time.Second,
+		RescanInterval: time.Minute,
+	}
+}
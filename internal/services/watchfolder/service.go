@@ -0,0 +1,439 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package watchfolder ingests .torrent files dropped into watched directories.
+package watchfolder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/crossseed"
+)
+
+const (
+	// ProcessedDir is the subfolder successfully ingested files are moved into.
+	ProcessedDir = "processed"
+	// FailedDir is the subfolder files that could not be ingested are moved into.
+	FailedDir = "failed"
+
+	reasonFileSuffix = ".reason.txt"
+	torrentExt       = ".torrent"
+)
+
+type folderProvider interface {
+	List(ctx context.Context) ([]*models.WatchFolder, error)
+}
+
+type torrentAdder interface {
+	AddTorrent(ctx context.Context, instanceID int, fileContent []byte, options map[string]string) error
+}
+
+type crossSeeder interface {
+	CrossSeed(ctx context.Context, req *crossseed.CrossSeedRequest) (*crossseed.CrossSeedResponse, error)
+}
+
+// Service watches configured folders and ingests .torrent files.
+type Service struct {
+	cfg         Config
+	store       folderProvider
+	adder       torrentAdder
+	crossSeeder crossSeeder
+
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher
+	folders map[string]*models.WatchFolder // keyed by cleaned folder path
+	pending map[string]*time.Timer         // debounce timers keyed by file path
+	active  map[string]struct{}            // files currently being processed
+	// Files that were ingested but could not be moved out of the watched folder,
+	// keyed by file path. They are only moved, never ingested again, while their
+	// content is unchanged.
+	unmoved map[string]unmovedFile
+
+	reload chan struct{}
+}
+
+type unmovedFile struct {
+	hash    string
+	destDir string
+	reason  string
+}
+
+// NewService creates a new watch folder service.
+func NewService(cfg Config, store *models.WatchFolderStore, adder torrentAdder, crossSeedService *crossseed.Service) *Service {
+	if cfg.SettleDelay <= 0 {
+		cfg.SettleDelay = DefaultConfig().SettleDelay
+	}
+	if cfg.RescanInterval <= 0 {
+		cfg.RescanInterval = DefaultConfig().RescanInterval
+	}
+
+	s := &Service{
+		cfg:     cfg,
+		store:   store,
+		adder:   adder,
+		folders: make(map[string]*models.WatchFolder),
+		pending: make(map[string]*time.Timer),
+		active:  make(map[string]struct{}),
+		unmoved: make(map[string]unmovedFile),
+		reload:  make(chan struct{}, 1),
+	}
+	if crossSeedService != nil {
+		s.crossSeeder = crossSeedService
+	}
+	return s
+}
+
+// Start starts watching in the background until ctx is canceled.
+func (s *Service) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Error().Err(err).Msg("watchfolder: failed to create watcher")
+		return
+	}
+
+	s.mu.Lock()
+	s.watcher = watcher
+	s.mu.Unlock()
+
+	go s.loop(ctx)
+}
+
+// Reload re-reads watch folder configuration. It is safe to call before Start.
+func (s *Service) Reload() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.reload <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) loop(ctx context.Context) {
+	defer func() {
+		s.mu.Lock()
+		for path, timer := range s.pending {
+			timer.Stop()
+			delete(s.pending, path)
+		}
+		if err := s.watcher.Close(); err != nil {
+			log.Debug().Err(err).Msg("watchfolder: failed to close watcher")
+		}
+		s.mu.Unlock()
+	}()
+
+	s.sync(ctx)
+
+	ticker := time.NewTicker(s.cfg.RescanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.reload:
+			s.sync(ctx)
+		case <-ticker.C:
+			s.sync(ctx)
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) || event.Has(fsnotify.Rename) {
+				s.schedule(ctx, event.Name)
+			}
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Warn().Err(err).Msg("watchfolder: watcher error")
+		}
+	}
+}
+
+// sync reconciles watched directories with the database and queues existing files.
+func (s *Service) sync(ctx context.Context) {
+	folders, err := s.store.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("watchfolder: failed to list watch folders")
+		return
+	}
+
+	desired := make(map[string]*models.WatchFolder, len(folders))
+	for _, folder := range folders {
+		if !folder.Enabled {
+			continue
+		}
+		desired[filepath.Clean(folder.Path)] = folder
+	}
+
+	s.mu.Lock()
+	for path := range s.folders {
+		if _, keep := desired[path]; !keep {
+			if err := s.watcher.Remove(path); err != nil {
+				log.Debug().Err(err).Str("path", path).Msg("watchfolder: failed to remove watch")
+			}
+			delete(s.folders, path)
+		}
+	}
+	for path, folder := range desired {
+		if _, watching := s.folders[path]; !watching {
+			if err := s.watcher.Add(path); err != nil {
+				log.Warn().Err(err).Str("path", path).Int("instanceID", folder.InstanceID).Msg("watchfolder: failed to watch folder")
+				continue
+			}
+			log.Info().Str("path", path).Int("instanceID", folder.InstanceID).Str("mode", string(folder.Mode)).Msg("watchfolder: watching folder")
+		}
+		s.folders[path] = folder
+	}
+	watched := make([]string, 0, len(s.folders))
+	for path := range s.folders {
+		watched = append(watched, path)
+	}
+	s.mu.Unlock()
+
+	// Pick up files dropped while qui was down or whose events were missed.
+	for _, dir := range watched {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			log.Debug().Err(err).Str("path", dir).Msg("watchfolder: failed to read folder")
+			continue
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				s.schedule(ctx, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+}
+
+// schedule debounces processing of a file until it stops changing.
+func (s *Service) schedule(ctx context.Context, path string) {
+	if !strings.EqualFold(filepath.Ext(path), torrentExt) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.folders[filepath.Dir(path)]; !ok {
+		return
+	}
+	if _, busy := s.active[path]; busy {
+		return
+	}
+	if timer, ok := s.pending[path]; ok {
+		timer.Reset(s.cfg.SettleDelay)
+		return
+	}
+
+	s.pending[path] = time.AfterFunc(s.cfg.SettleDelay, func() {
+		s.mu.Lock()
+		delete(s.pending, path)
+		folder := s.folders[filepath.Dir(path)]
+		if folder == nil {
+			s.mu.Unlock()
+			return
+		}
+		s.active[path] = struct{}{}
+		s.mu.Unlock()
+
+		defer func() {
+			s.mu.Lock()
+			delete(s.active, path)
+			s.mu.Unlock()
+		}()
+
+		if ctx.Err() != nil {
+			return
+		}
+		s.processFile(ctx, folder, path)
+	})
+}
+
+// processFile ingests a single .torrent file and moves it out of the watched folder.
+func (s *Service) processFile(ctx context.Context, folder *models.WatchFolder, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn().Err(err).Str("path", path).Msg("watchfolder: failed to read torrent file")
+		}
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if pending, ok := s.unmovedFile(path); ok && pending.hash == hash {
+		s.move(folder, path, hash, pending.destDir, pending.reason)
+		return
+	}
+
+	reason, ingestErr := s.ingest(ctx, folder, data)
+	if ctx.Err() != nil {
+		// Leave the file in place so it is retried on the next start.
+		return
+	}
+
+	destDir := ProcessedDir
+	if ingestErr != nil {
+		destDir = FailedDir
+		reason = ingestErr.Error()
+		log.Warn().Err(ingestErr).Str("path", path).Int("instanceID", folder.InstanceID).Msg("watchfolder: failed to ingest torrent")
+	} else {
+		log.Info().Str("path", path).Int("instanceID", folder.InstanceID).Str("result", reason).Msg("watchfolder: ingested torrent")
+	}
+
+	s.move(folder, path, hash, destDir, reason)
+}
+
+// move moves an ingested file into destDir. When the file cannot be moved it is
+// remembered with its content hash, so later scans retry the move instead of
+// ingesting it again.
+func (s *Service) move(folder *models.WatchFolder, path, hash, destDir, reason string) {
+	err := moveWithReason(path, filepath.Join(folder.Path, destDir), folder, reason)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.unmoved == nil {
+		s.unmoved = make(map[string]unmovedFile)
+	}
+
+	if err == nil {
+		delete(s.unmoved, path)
+		return
+	}
+	log.Error().Err(err).Str("path", path).Msg("watchfolder: failed to move torrent file")
+	if _, statErr := os.Stat(path); statErr == nil {
+		s.unmoved[path] = unmovedFile{hash: hash, destDir: destDir, reason: reason}
+	} else {
+		// The file left the folder and only the reason file failed
+		delete(s.unmoved, path)
+	}
+}
+
+func (s *Service) unmovedFile(path string) (unmovedFile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, ok := s.unmoved[path]
+	return file, ok
+}
+
+// ingest adds the torrent according to the folder mode and returns a human-readable result.
+func (s *Service) ingest(ctx context.Context, folder *models.WatchFolder, data []byte) (string, error) {
+	switch folder.Mode {
+	case models.WatchFolderModeCrossSeed:
+		return s.ingestCrossSeed(ctx, folder, data)
+	case models.WatchFolderModeAdd, "":
+		return s.ingestAdd(ctx, folder, data)
+	default:
+		return "", fmt.Errorf("unsupported watch folder mode %q", folder.Mode)
+	}
+}
+
+func (s *Service) ingestAdd(ctx context.Context, folder *models.WatchFolder, data []byte) (string, error) {
+	if s.adder == nil {
+		return "", errors.New("torrent adder not configured")
+	}
+
+	options := make(map[string]string)
+	if folder.Category != "" {
+		options["category"] = folder.Category
+	}
+	if len(folder.Tags) > 0 {
+		options["tags"] = strings.Join(folder.Tags, ",")
+	}
+	if folder.StartPaused {
+		options["paused"] = "true"
+		options["stopped"] = "true"
+	}
+
+	if err := s.adder.AddTorrent(ctx, folder.InstanceID, data, options); err != nil {
+		return "", fmt.Errorf("add torrent: %w", err)
+	}
+	return "added", nil
+}
+
+func (s *Service) ingestCrossSeed(ctx context.Context, folder *models.WatchFolder, data []byte) (string, error) {
+	if s.crossSeeder == nil {
+		return "", errors.New("cross-seed service not configured")
+	}
+
+	startPaused := folder.StartPaused
+	skipIfExists := true
+	resp, err := s.crossSeeder.CrossSeed(ctx, &crossseed.CrossSeedRequest{
+		TorrentData:       base64.StdEncoding.EncodeToString(data),
+		TargetInstanceIDs: []int{folder.InstanceID},
+		Category:          folder.Category,
+		Tags:              append([]string(nil), folder.Tags...),
+		StartPaused:       &startPaused,
+		SkipIfExists:      &skipIfExists,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cross-seed: %w", err)
+	}
+
+	for _, result := range resp.Results {
+		if result.InstanceID != folder.InstanceID {
+			continue
+		}
+		message := result.Status
+		if result.Message != "" {
+			message += ": " + result.Message
+		}
+		// An existing torrent means the work is already done; treat it as processed.
+		if result.Success || result.Status == "exists" {
+			return "cross-seed " + message, nil
+		}
+		return "", errors.New("cross-seed " + message)
+	}
+
+	if resp.Success {
+		return "cross-seed added", nil
+	}
+	return "", errors.New("cross-seed: no matching torrent on instance")
+}
+
+// moveWithReason moves src into destDir and writes a sibling reason file.
+// Existing files are never overwritten; a timestamp suffix is added instead.
+func moveWithReason(src, destDir string, folder *models.WatchFolder, reason string) error {
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", destDir, err)
+	}
+
+	base := filepath.Base(src)
+	dest := filepath.Join(destDir, base)
+	if _, err := os.Stat(dest); err == nil {
+		ext := filepath.Ext(base)
+		dest = filepath.Join(destDir, fmt.Sprintf("%s.%s%s", strings.TrimSuffix(base, ext), time.Now().UTC().Format("20060102T150405.000"), ext))
+	}
+
+	if err := os.Rename(src, dest); err != nil {
+		return fmt.Errorf("move to %s: %w", dest, err)
+	}
+
+	content := fmt.Sprintf("time: %s\ninstance: %d\nmode: %s\nresult: %s\n",
+		time.Now().UTC().Format(time.RFC3339), folder.InstanceID, folder.Mode, reason)
+	if err := os.WriteFile(dest+reasonFileSuffix, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write reason file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2025, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package watchfolder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/crossseed"
+)
+
+type fakeAdder struct {
+	calls      int
+	instanceID int
+	options    map[string]string
+	err        error
+}
+
+func (f *fakeAdder) AddTorrent(_ context.Context, instanceID int, _ []byte, options map[string]string) error {
+	f.calls++
+	f.instanceID = instanceID
+	f.options = options
+	return f.err
+}
+
+type fakeCrossSeeder struct {
+	req  *crossseed.CrossSeedRequest
+	resp *crossseed.CrossSeedResponse
+}
+
+func (f *fakeCrossSeeder) CrossSeed(_ context.Context, req *crossseed.CrossSeedRequest) (*crossseed.CrossSeedResponse, error) {
+	f.req = req
+	return f.resp, nil
+}
+
+func writeTorrent(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("d4:infod4:name4:testee"), 0o600))
+	return path
+}
+
+func TestProcessFile_AddModeMovesToProcessed(t *testing.T) {
+	dir := t.TempDir()
+	adder := &fakeAdder{}
+	s := &Service{adder: adder}
+	folder := &models.WatchFolder{
+		InstanceID:  3,
+		Path:        dir,
+		Mode:        models.WatchFolderModeAdd,
+		Category:    "movies",
+		Tags:        []string{"a", "b"},
+		StartPaused: true,
+	}
+
+	path := writeTorrent(t, dir, "release.torrent")
+	s.processFile(context.Background(), folder, path)
+
+	assert.Equal(t, 3, adder.instanceID)
+	assert.Equal(t, "movies", adder.options["category"])
+	assert.Equal(t, "a,b", adder.options["tags"])
+	assert.Equal(t, "true", adder.options["paused"])
+
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, filepath.Join(dir, ProcessedDir, "release.torrent"))
+	reason, err := os.ReadFile(filepath.Join(dir, ProcessedDir, "release.torrent"+reasonFileSuffix))
+	require.NoError(t, err)
+	assert.Contains(t, string(reason), "result: added")
+
+	// A second file with the same name must not overwrite the first.
+	path = writeTorrent(t, dir, "release.torrent")
+	s.processFile(context.Background(), folder, path)
+	entries, err := os.ReadDir(filepath.Join(dir, ProcessedDir))
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+}
+
+func TestProcessFile_AddFailureMovesToFailed(t *testing.T) {
+	dir := t.TempDir()
+	s := &Service{adder: &fakeAdder{err: errors.New("instance offline")}}
+	folder := &models.WatchFolder{InstanceID: 1, Path: dir, Mode: models.WatchFolderModeAdd}
+
+	path := writeTorrent(t, dir, "broken.torrent")
+	s.processFile(context.Background(), folder, path)
+
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, filepath.Join(dir, FailedDir, "broken.torrent"))
+	reason, err := os.ReadFile(filepath.Join(dir, FailedDir, "broken.torrent"+reasonFileSuffix))
+	require.NoError(t, err)
+	assert.Contains(t, string(reason), "instance offline")
+}
+
+func TestProcessFile_UnmovableFileIsNotAddedAgain(t *testing.T) {
+	dir := t.TempDir()
+	adder := &fakeAdder{}
+	s := &Service{adder: adder}
+	folder := &models.WatchFolder{InstanceID: 1, Path: dir, Mode: models.WatchFolderModeAdd}
+
+	// A regular file where the processed folder should be makes the move fail.
+	blocker := filepath.Join(dir, ProcessedDir)
+	require.NoError(t, os.WriteFile(blocker, nil, 0o600))
+
+	path := writeTorrent(t, dir, "stuck.torrent")
+	s.processFile(context.Background(), folder, path)
+	s.processFile(context.Background(), folder, path)
+
+	assert.Equal(t, 1, adder.calls)
+	assert.FileExists(t, path)
+
+	// Once the destination is writable the file is moved without adding it again.
+	require.NoError(t, os.Remove(blocker))
+	s.processFile(context.Background(), folder, path)
+
+	assert.Equal(t, 1, adder.calls)
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, filepath.Join(dir, ProcessedDir, "stuck.torrent"))
+
+	// A new file dropped under the same name is ingested as usual.
+	path = writeTorrent(t, dir, "stuck.torrent")
+	s.processFile(context.Background(), folder, path)
+	assert.Equal(t, 2, adder.calls)
+}
+
+func TestProcessFile_CrossSeedMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  crossseed.InstanceCrossSeedResult
+		wantDir string
+	}{
+		{
+			name:    "added",
+			result:  crossseed.InstanceCrossSeedResult{InstanceID: 2, Success: true, Status: "added"},
+			wantDir: ProcessedDir,
+		},
+		{
+			name:    "already exists",
+			result:  crossseed.InstanceCrossSeedResult{InstanceID: 2, Status: "exists"},
+			wantDir: ProcessedDir,
+		},
+		{
+			name:    "no match",
+			result:  crossseed.InstanceCrossSeedResult{InstanceID: 2, Status: "no_match", Message: "no matching torrent"},
+			wantDir: FailedDir,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			seeder := &fakeCrossSeeder{resp: &crossseed.CrossSeedResponse{
+				Results: []crossseed.InstanceCrossSeedResult{tt.result},
+			}}
+			s := &Service{crossSeeder: seeder}
+			folder := &models.WatchFolder{InstanceID: 2, Path: dir, Mode: models.WatchFolderModeCrossSeed, Category: "xseed"}
+
+			path := writeTorrent(t, dir, "match.torrent")
+			s.processFile(context.Background(), folder, path)
+
+			require.NotNil(t, seeder.req)
+			assert.Equal(t, []int{2}, seeder.req.TargetInstanceIDs)
+			assert.Equal(t, "xseed", seeder.req.Category)
+			assert.FileExists(t, filepath.Join(dir, tt.wantDir, "match.torrent"))
+			assert.FileExists(t, filepath.Join(dir, tt.wantDir, "match.torrent"+reasonFileSuffix))
+		})
+	}
+}
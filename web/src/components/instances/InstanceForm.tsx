@@ -29,6 +29,7 @@ const DEFAULT_REANNOUNCE_SETTINGS: InstanceReannounceSettings = {
   tags: [],
   excludeTrackers: false,
   trackers: [],
+  trackerProfiles: [],
 }
 
 // URL validation schema
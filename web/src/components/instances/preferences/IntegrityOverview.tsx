@@ -0,0 +1,333 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { Accordion, AccordionContent, AccordionItem, AccordionTrigger } from "@/components/ui/accordion"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Progress } from "@/components/ui/progress"
+import { Switch } from "@/components/ui/switch"
+import { Tooltip, TooltipContent, TooltipTrigger } from "@/components/ui/tooltip"
+import { useInstances } from "@/hooks/useInstances"
+import {
+  useCancelIntegrityCheck,
+  useIntegrityChecks,
+  useIntegritySettings,
+  useTriggerIntegrityCheck,
+  useUpdateIntegritySettings
+} from "@/hooks/useIntegrity"
+import { cn, formatBytes, formatRelativeTime } from "@/lib/utils"
+import type { Instance, IntegrityCheck, IntegrityCheckStatus, IntegritySettings } from "@/types"
+import { AlertTriangle, ChevronDown as ChevronDownIcon, Info, Loader2, Play, X } from "lucide-react"
+import { useEffect, useMemo, useState } from "react"
+import { toast } from "sonner"
+
+interface IntegrityOverviewProps {
+  expandedInstances?: string[]
+  onExpandedInstancesChange?: (values: string[]) => void
+}
+
+function getStatusBadge(status: IntegrityCheckStatus) {
+  switch (status) {
+    case "running":
+      return { className: "bg-blue-500/10 text-blue-500 border-blue-500/20", label: "Verifying..." }
+    case "passed":
+      return { className: "bg-emerald-500/10 text-emerald-500 border-emerald-500/20", label: "Passed" }
+    case "failed":
+      return { className: "bg-destructive/10 text-destructive border-destructive/30", label: "Failed" }
+    case "error":
+      return { className: "bg-yellow-500/10 text-yellow-500 border-yellow-500/20", label: "Error" }
+    case "canceled":
+      return { className: "bg-muted text-muted-foreground border-border/60", label: "Canceled" }
+    default:
+      return { className: "", label: status }
+  }
+}
+
+type SettingsDraft = Pick<IntegritySettings, "intervalMinutes" | "torrentsPerRun" | "recheckAfterDays" | "maxReadMiBPerSecond" | "failureTag">
+
+function CheckRow({ check, onCancel, canceling }: { check: IntegrityCheck; onCancel: () => void; canceling: boolean }) {
+  const badge = getStatusBadge(check.status)
+  const progress = check.piecesTotal > 0 ? (check.piecesChecked / check.piecesTotal) * 100 : 0
+
+  return (
+    <div className="rounded-md border p-3 space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <div className="min-w-0">
+          <p className="text-sm font-medium truncate">{check.torrentName || check.hash}</p>
+          <p className="text-xs text-muted-foreground">
+            {check.triggeredBy} · {formatRelativeTime(new Date(check.startedAt))} · {formatBytes(check.bytesChecked)} read
+          </p>
+        </div>
+        <div className="flex items-center gap-2 shrink-0">
+          <Badge variant="outline" className={cn("text-xs", badge.className)}>{badge.label}</Badge>
+          {check.status === "running" && (
+            <Button variant="ghost" size="icon" className="h-7 w-7" onClick={onCancel} disabled={canceling}>
+              <X className="h-4 w-4" />
+            </Button>
+          )}
+        </div>
+      </div>
+      {check.status === "running" && <Progress value={progress} className="h-1.5" />}
+      {check.status === "failed" && (
+        <div className="text-xs space-y-1">
+          <p className="text-destructive">
+            {check.corruptPieces} corrupt, {check.missingPieces} missing of {check.piecesChecked} pieces checked
+          </p>
+          <ul className="text-muted-foreground space-y-0.5">
+            {check.badFiles.map((file) => (
+              <li key={file.path} className="truncate" title={file.path}>
+                {file.path}
+                {file.corruptPieces > 0 && ` · ${file.corruptPieces} corrupt`}
+                {file.missingPieces > 0 && ` · ${file.missingPieces} missing`}
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+      {check.errorMessage && <p className="text-xs text-muted-foreground">{check.errorMessage}</p>}
+    </div>
+  )
+}
+
+function InstanceIntegrityItem({ instance }: { instance: Instance }) {
+  const hasLocalAccess = instance.hasLocalFilesystemAccess
+  const settingsQuery = useIntegritySettings(instance.id, { enabled: hasLocalAccess })
+  const checksQuery = useIntegrityChecks(instance.id, { limit: 10, enabled: hasLocalAccess })
+  const updateSettingsMutation = useUpdateIntegritySettings(instance.id)
+  const triggerMutation = useTriggerIntegrityCheck(instance.id)
+  const cancelMutation = useCancelIntegrityCheck(instance.id)
+  const [hash, setHash] = useState("")
+  const [draft, setDraft] = useState<SettingsDraft | null>(null)
+
+  const settings = settingsQuery.data
+  const checks = checksQuery.data ?? []
+  const latestCheck = checks[0]
+  const isRunning = checks.some((check) => check.status === "running")
+
+  useEffect(() => {
+    if (settings) {
+      setDraft({
+        intervalMinutes: settings.intervalMinutes,
+        torrentsPerRun: settings.torrentsPerRun,
+        recheckAfterDays: settings.recheckAfterDays,
+        maxReadMiBPerSecond: settings.maxReadMiBPerSecond,
+        failureTag: settings.failureTag,
+      })
+    }
+  }, [settings])
+
+  const saveSettings = (update: Partial<IntegritySettings>, message: string) => {
+    updateSettingsMutation.mutate(update, {
+      onSuccess: () => toast.success(message, { description: instance.name }),
+      onError: (error) => {
+        toast.error("Update failed", {
+          description: error instanceof Error ? error.message : "Unable to update settings",
+        })
+      },
+    })
+  }
+
+  const handleTrigger = () => {
+    triggerMutation.mutate(hash.trim(), {
+      onSuccess: () => {
+        setHash("")
+        toast.success("Verification started", { description: instance.name })
+      },
+      onError: (error) => {
+        toast.error("Failed to start verification", {
+          description: error instanceof Error ? error.message : "Unknown error",
+        })
+      },
+    })
+  }
+
+  if (!hasLocalAccess) {
+    return (
+      <AccordionItem value={String(instance.id)} disabled>
+        <div className="px-6 py-4 flex items-center justify-between opacity-60">
+          <div className="flex items-center gap-3">
+            <span className="font-medium">{instance.name}</span>
+            <Badge variant="outline" className="text-xs">No Local Access</Badge>
+          </div>
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <AlertTriangle className="h-4 w-4 text-muted-foreground cursor-help" />
+            </TooltipTrigger>
+            <TooltipContent className="max-w-[250px]">
+              <p>qui must be able to read the torrent data. Enable "Local Filesystem Access" in instance settings to verify torrents.</p>
+            </TooltipContent>
+          </Tooltip>
+        </div>
+      </AccordionItem>
+    )
+  }
+
+  const latestBadge = latestCheck ? getStatusBadge(latestCheck.status) : null
+  const isEnabled = settings?.enabled ?? false
+
+  return (
+    <AccordionItem value={String(instance.id)} className="group/item">
+      <div className="grid grid-cols-[1fr_auto] items-center px-6">
+        <AccordionTrigger className="py-4 pr-4 hover:no-underline [&>svg]:hidden">
+          <div className="flex items-center gap-3 min-w-0">
+            <span className="font-medium truncate">{instance.name}</span>
+            {latestBadge && (
+              <Badge variant="outline" className={cn("text-xs", latestBadge.className)}>
+                {latestBadge.label}
+              </Badge>
+            )}
+          </div>
+        </AccordionTrigger>
+        <div className="flex items-center gap-4 py-4">
+          <div className="flex items-center gap-2" onClick={(e) => e.stopPropagation()}>
+            <span className={cn("text-xs font-medium", isEnabled ? "text-emerald-500" : "text-muted-foreground")}>
+              {isEnabled ? "On" : "Off"}
+            </span>
+            <Switch
+              checked={isEnabled}
+              onCheckedChange={(enabled) => saveSettings({ enabled }, enabled ? "Scheduled verification enabled" : "Scheduled verification disabled")}
+              disabled={updateSettingsMutation.isPending}
+              className="scale-90"
+            />
+          </div>
+          <ChevronDownIcon className="h-4 w-4 shrink-0 text-muted-foreground transition-transform duration-200 group-data-[state=open]/item:rotate-180" />
+        </div>
+      </div>
+
+      <AccordionContent className="px-6 pb-4">
+        <div className="space-y-4">
+          {draft && (
+            <div className="grid gap-3 sm:grid-cols-3 p-3 rounded-lg bg-muted/40 border">
+              <div className="space-y-1">
+                <Label className="text-xs">Interval (minutes)</Label>
+                <Input type="number" min={5} value={draft.intervalMinutes} onChange={(e) => setDraft({ ...draft, intervalMinutes: Number(e.target.value) })} />
+              </div>
+              <div className="space-y-1">
+                <Label className="text-xs">Torrents per pass</Label>
+                <Input type="number" min={1} value={draft.torrentsPerRun} onChange={(e) => setDraft({ ...draft, torrentsPerRun: Number(e.target.value) })} />
+              </div>
+              <div className="space-y-1">
+                <Label className="text-xs">Recheck after (days)</Label>
+                <Input type="number" min={1} value={draft.recheckAfterDays} onChange={(e) => setDraft({ ...draft, recheckAfterDays: Number(e.target.value) })} />
+              </div>
+              <div className="space-y-1">
+                <Label className="text-xs">Read limit (MiB/s, 0 = unlimited)</Label>
+                <Input type="number" min={0} value={draft.maxReadMiBPerSecond} onChange={(e) => setDraft({ ...draft, maxReadMiBPerSecond: Number(e.target.value) })} />
+              </div>
+              <div className="space-y-1">
+                <Label className="text-xs">Failure tag</Label>
+                <Input value={draft.failureTag} placeholder="Leave empty to disable" onChange={(e) => setDraft({ ...draft, failureTag: e.target.value })} />
+              </div>
+              <div className="flex items-end">
+                <Button
+                  variant="outline"
+                  size="sm"
+                  className="h-9 w-full"
+                  disabled={updateSettingsMutation.isPending}
+                  onClick={() => saveSettings(draft, "Verification settings saved")}
+                >
+                  Save
+                </Button>
+              </div>
+            </div>
+          )}
+
+          <div className="flex items-center gap-2">
+            <Input
+              placeholder="Torrent hash"
+              value={hash}
+              onChange={(e) => setHash(e.target.value)}
+              className="h-8 font-mono text-xs"
+            />
+            <Button
+              variant="outline"
+              size="sm"
+              className="h-8 shrink-0"
+              onClick={handleTrigger}
+              disabled={hash.trim() === "" || isRunning || triggerMutation.isPending}
+            >
+              {triggerMutation.isPending ? <Loader2 className="h-4 w-4 animate-spin" /> : <><Play className="h-4 w-4 mr-2" />Verify</>}
+            </Button>
+          </div>
+
+          {checks.length === 0 ? (
+            <p className="text-xs text-muted-foreground">No torrents verified yet.</p>
+          ) : (
+            <div className="space-y-2">
+              {checks.map((check) => (
+                <CheckRow
+                  key={check.id}
+                  check={check}
+                  canceling={cancelMutation.isPending}
+                  onCancel={() => cancelMutation.mutate(check.id)}
+                />
+              ))}
+            </div>
+          )}
+        </div>
+      </AccordionContent>
+    </AccordionItem>
+  )
+}
+
+export function IntegrityOverview({
+  expandedInstances: controlledExpanded,
+  onExpandedInstancesChange,
+}: IntegrityOverviewProps) {
+  const { instances } = useInstances()
+  const [internalExpanded, setInternalExpanded] = useState<string[]>([])
+
+  const expandedInstances = controlledExpanded ?? internalExpanded
+  const setExpandedInstances = onExpandedInstancesChange ?? setInternalExpanded
+
+  const activeInstances = useMemo(
+    () => (instances ?? []).filter((inst) => inst.isActive),
+    [instances]
+  )
+
+  if (!instances || instances.length === 0) {
+    return null
+  }
+
+  return (
+    <Card>
+      <CardHeader className="space-y-2">
+        <div className="flex items-center gap-2">
+          <CardTitle className="text-lg font-semibold">Data Integrity</CardTitle>
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <Info className="h-4 w-4 text-muted-foreground cursor-help" />
+            </TooltipTrigger>
+            <TooltipContent className="max-w-[300px]">
+              <p>
+                Hashes downloaded pieces straight from disk without pausing the torrent in qBittorrent.
+                Requires local filesystem access to be enabled for each instance.
+              </p>
+            </TooltipContent>
+          </Tooltip>
+        </div>
+        <CardDescription>
+          Verifies torrent data against its piece hashes and tags torrents with corrupt or missing data.
+        </CardDescription>
+      </CardHeader>
+
+      <CardContent className="p-0">
+        <Accordion
+          type="multiple"
+          value={expandedInstances}
+          onValueChange={setExpandedInstances}
+          className="border-t"
+        >
+          {activeInstances.map((instance) => (
+            <InstanceIntegrityItem key={instance.id} instance={instance} />
+          ))}
+        </Accordion>
+      </CardContent>
+    </Card>
+  )
+}
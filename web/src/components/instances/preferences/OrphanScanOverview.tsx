@@ -16,12 +16,13 @@ import {
   useCancelOrphanScanRun,
   useOrphanScanRuns,
   useOrphanScanSettings,
+  useRestoreOrphanScanRun,
   useTriggerOrphanScan,
   useUpdateOrphanScanSettings,
 } from "@/hooks/useOrphanScan"
 import { cn, copyTextToClipboard, formatBytes, formatRelativeTime } from "@/lib/utils"
 import type { Instance, OrphanScanRunStatus } from "@/types"
-import { AlertTriangle, ChevronDown as ChevronDownIcon, Copy, Eye, Files, Info, Loader2, Play, Settings2, X } from "lucide-react"
+import { AlertTriangle, ChevronDown as ChevronDownIcon, Copy, Eye, Files, Info, Loader2, Play, Settings2, Undo2, X } from "lucide-react"
 import { useMemo, useState } from "react"
 import { toast } from "sonner"
 
@@ -70,12 +71,15 @@ function InstanceOrphanScanItem({
   const triggerMutation = useTriggerOrphanScan(instance.id)
   const updateSettingsMutation = useUpdateOrphanScanSettings(instance.id)
   const cancelMutation = useCancelOrphanScanRun(instance.id)
+  const restoreMutation = useRestoreOrphanScanRun(instance.id)
   const [previewOpen, setPreviewOpen] = useState(false)
 
   const settings = settingsQuery.data
   const runs = runsQuery.data ?? []
   const latestRun = runs[0]
 
+  const quarantinedRuns = runs.filter((run) => run.filesQuarantined > 0)
+
   const isEnabled = settings?.enabled ?? false
   const isActiveRun = latestRun && ["pending", "scanning", "deleting"].includes(latestRun.status)
 
@@ -123,6 +127,25 @@ function InstanceOrphanScanItem({
     })
   }
 
+  const handleRestoreRun = (runId: number) => {
+    restoreMutation.mutate(runId, {
+      onSuccess: (result) => {
+        if (result.failed > 0) {
+          toast.warning(`Restored ${result.restored} file(s), ${result.failed} failed`, {
+            description: "Files whose original path is occupied stay in quarantine",
+          })
+          return
+        }
+        toast.success(`Restored ${result.restored} file(s)`, { description: instance.name })
+      },
+      onError: (error) => {
+        toast.error("Failed to restore", {
+          description: error instanceof Error ? error.message : "Unknown error",
+        })
+      },
+    })
+  }
+
   // Compute status badge once for reuse in header
   const latestRunBadge = latestRun ? getStatusBadge(latestRun.status, latestRun.filesFound) : null
 
@@ -219,6 +242,9 @@ function InstanceOrphanScanItem({
                 {settings?.autoCleanupEnabled
                   ? `Auto-cleanup enabled (≤${settings.autoCleanupMaxFiles} files)`
                   : "Auto-cleanup disabled"}
+                {settings?.quarantineEnabled && (
+                  <> · Quarantine {settings.quarantineRetentionDays}d</>
+                )}
                 {settings?.ignorePaths && settings.ignorePaths.length > 0 && (
                   <> · {settings.ignorePaths.length} path{settings.ignorePaths.length !== 1 ? "s" : ""} ignored</>
                 )}
@@ -284,6 +310,7 @@ function InstanceOrphanScanItem({
                   </p>
                   <p className="text-xs text-muted-foreground">
                     Total size: {formatBytes(latestRun.bytesReclaimed || 0)}
+                    {latestRun.bytesReclaimable !== latestRun.bytesReclaimed && ` · ${formatBytes(latestRun.bytesReclaimable || 0)} reclaimable`}
                     {latestRun.truncated && " (scan was truncated, more files may exist)"}
                   </p>
                 </div>
@@ -312,6 +339,39 @@ function InstanceOrphanScanItem({
             </div>
           )}
 
+          {/* Quarantined files awaiting purge */}
+          {quarantinedRuns.map((run) => (
+            <div key={run.id} className="flex items-center justify-between p-3 rounded-lg border bg-muted/40">
+              <div className="space-y-0.5">
+                <p className="text-sm font-medium">
+                  {run.filesQuarantined} file{run.filesQuarantined !== 1 ? "s" : ""} in quarantine ({formatBytes(run.bytesQuarantined)})
+                </p>
+                <p className="text-xs text-muted-foreground">
+                  {run.completedAt
+                    ? `Quarantined ${formatRelativeTime(new Date(run.completedAt))}`
+                    : "Quarantined"}
+                  {settings && ` · purged after ${settings.quarantineRetentionDays} day${settings.quarantineRetentionDays !== 1 ? "s" : ""}`}
+                </p>
+              </div>
+              <Button
+                variant="outline"
+                size="sm"
+                onClick={() => handleRestoreRun(run.id)}
+                disabled={restoreMutation.isPending}
+                className="h-8"
+              >
+                {restoreMutation.isPending ? (
+                  <Loader2 className="h-4 w-4 animate-spin" />
+                ) : (
+                  <>
+                    <Undo2 className="h-4 w-4 mr-2" />
+                    Restore
+                  </>
+                )}
+              </Button>
+            </div>
+          ))}
+
           {latestRun?.status === "preview_ready" && latestRun.filesFound > 0 && (
             <OrphanScanPreviewDialog
               open={previewOpen}
@@ -358,6 +418,7 @@ function InstanceOrphanScanItem({
                         {run.status === "completed" && run.filesFound > 0 && (
                           <span>
                             {run.filesDeleted} deleted · {formatBytes(run.bytesReclaimed)}
+                            {run.filesQuarantined > 0 && ` · ${run.filesQuarantined} quarantined`}
                           </span>
                         )}
                         {run.startedAt && (
@@ -3,12 +3,13 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+import { Badge } from "@/components/ui/badge"
 import { Dialog, DialogContent, DialogDescription, DialogFooter, DialogHeader, DialogTitle } from "@/components/ui/dialog"
 import { Button } from "@/components/ui/button"
 import { TruncatedText } from "@/components/ui/truncated-text"
 import { useConfirmOrphanScanDeletion, useOrphanScanRun } from "@/hooks/useOrphanScan"
 import { formatBytes } from "@/lib/utils"
-import type { OrphanScanFile } from "@/types"
+import type { OrphanScanFile, OrphanScanLinkClass } from "@/types"
 import { Loader2, Trash2 } from "lucide-react"
 import { useEffect, useMemo, useState } from "react"
 import { toast } from "sonner"
@@ -22,6 +23,12 @@ interface OrphanScanPreviewDialogProps {
 
 const PAGE_SIZE = 200
 
+const LINK_CLASS_LABELS: Record<OrphanScanLinkClass, string> = {
+  unique: "Unique",
+  hardlinked_orphan: "Orphan links",
+  hardlinked_elsewhere: "Linked elsewhere",
+}
+
 export function OrphanScanPreviewDialog({
   open,
   onOpenChange,
@@ -100,6 +107,7 @@ export function OrphanScanPreviewDialog({
         {run && (
           <div className="text-sm text-muted-foreground">
             {run.filesFound} file{run.filesFound !== 1 ? "s" : ""} · {formatBytes(totalSize)}
+            {run.bytesReclaimable !== totalSize && ` · ${formatBytes(run.bytesReclaimable)} reclaimable`}
             {run.truncated && " (truncated)"}
           </div>
         )}
@@ -110,6 +118,7 @@ export function OrphanScanPreviewDialog({
               <thead className="sticky top-0">
                 <tr className="border-b">
                   <th className="text-left p-2 font-medium bg-muted">Path</th>
+                  <th className="text-left p-2 font-medium bg-muted">Links</th>
                   <th className="text-right p-2 font-medium bg-muted">Size</th>
                   <th className="text-right p-2 font-medium bg-muted">Modified</th>
                 </tr>
@@ -120,6 +129,11 @@ export function OrphanScanPreviewDialog({
                     <td className="p-2 max-w-[520px]">
                       <TruncatedText className="block">{f.filePath}</TruncatedText>
                     </td>
+                    <td className="p-2 whitespace-nowrap">
+                      <Badge variant="outline" className={f.linkClass === "hardlinked_elsewhere" ? "text-yellow-500 border-yellow-500/40" : undefined}>
+                        {LINK_CLASS_LABELS[f.linkClass] ?? f.linkClass}
+                      </Badge>
+                    </td>
                     <td className="p-2 text-right font-mono text-muted-foreground whitespace-nowrap">
                       {formatBytes(f.fileSize)}
                     </td>
@@ -130,7 +144,7 @@ export function OrphanScanPreviewDialog({
                 ))}
                 {runQuery.isLoading && files.length === 0 && (
                   <tr>
-                    <td colSpan={3} className="p-6 text-center text-muted-foreground">
+                    <td colSpan={4} className="p-6 text-center text-muted-foreground">
                       <Loader2 className="h-4 w-4 animate-spin inline-block mr-2" />
                       Loading…
                     </td>
@@ -138,7 +152,7 @@ export function OrphanScanPreviewDialog({
                 )}
                 {!runQuery.isLoading && files.length === 0 && (
                   <tr>
-                    <td colSpan={3} className="p-6 text-center text-muted-foreground">
+                    <td colSpan={4} className="p-6 text-center text-muted-foreground">
                       No files to display.
                     </td>
                   </tr>
@@ -11,8 +11,9 @@ import { Separator } from "@/components/ui/separator"
 import { Switch } from "@/components/ui/switch"
 import { Textarea } from "@/components/ui/textarea"
 import { Tooltip, TooltipContent, TooltipTrigger } from "@/components/ui/tooltip"
+import { useInstances } from "@/hooks/useInstances"
 import { useOrphanScanSettings, useUpdateOrphanScanSettings } from "@/hooks/useOrphanScan"
-import type { OrphanScanSettings, OrphanScanSettingsUpdate } from "@/types"
+import type { OrphanScanPathMapping, OrphanScanSettings, OrphanScanSettingsUpdate } from "@/types"
 import { Info, Loader2 } from "lucide-react"
 import { useEffect, useState } from "react"
 import { toast } from "sonner"
@@ -32,6 +33,24 @@ const DEFAULT_SETTINGS: Omit<OrphanScanSettings, "id" | "instanceId" | "createdA
   ignorePaths: [],
   autoCleanupEnabled: false,
   autoCleanupMaxFiles: 100,
+  autoCleanupUnique: true,
+  autoCleanupHardlinkedOrphans: true,
+  autoCleanupHardlinkedElsewhere: false,
+  quarantineEnabled: false,
+  quarantineRetentionDays: 7,
+  sharedInstances: [],
+}
+
+function formatPathMappings(mappings: OrphanScanPathMapping[]): string {
+  return mappings.map(m => `${m.from} => ${m.to}`).join("\n")
+}
+
+function parsePathMappings(text: string): OrphanScanPathMapping[] {
+  return text
+    .split("\n")
+    .map(line => line.split("=>").map(part => part.trim()))
+    .filter(parts => parts.length === 2 && parts[0] && parts[1])
+    .map(([from, to]) => ({ from, to }))
 }
 
 export function OrphanScanSettingsForm({
@@ -44,6 +63,10 @@ export function OrphanScanSettingsForm({
 
   const [settings, setSettings] = useState<typeof DEFAULT_SETTINGS>(() => ({ ...DEFAULT_SETTINGS }))
   const [ignorePathsText, setIgnorePathsText] = useState("")
+  // Path mapping text per shared instance ID, one "from => to" per line
+  const [sharedMappingsText, setSharedMappingsText] = useState<Record<number, string>>({})
+  const { instances } = useInstances()
+  const otherInstances = (instances ?? []).filter(instance => instance.id !== instanceId)
 
   // Reset settings when query data changes
   useEffect(() => {
@@ -56,8 +79,17 @@ export function OrphanScanSettingsForm({
         ignorePaths: [...settingsQuery.data.ignorePaths],
         autoCleanupEnabled: settingsQuery.data.autoCleanupEnabled,
         autoCleanupMaxFiles: settingsQuery.data.autoCleanupMaxFiles,
+        autoCleanupUnique: settingsQuery.data.autoCleanupUnique,
+        autoCleanupHardlinkedOrphans: settingsQuery.data.autoCleanupHardlinkedOrphans,
+        autoCleanupHardlinkedElsewhere: settingsQuery.data.autoCleanupHardlinkedElsewhere,
+        quarantineEnabled: settingsQuery.data.quarantineEnabled,
+        quarantineRetentionDays: settingsQuery.data.quarantineRetentionDays,
+        sharedInstances: settingsQuery.data.sharedInstances ?? [],
       })
       setIgnorePathsText(settingsQuery.data.ignorePaths.join("\n"))
+      setSharedMappingsText(Object.fromEntries(
+        (settingsQuery.data.sharedInstances ?? []).map(shared => [shared.instanceId, formatPathMappings(shared.pathMappings)])
+      ))
     }
   }, [settingsQuery.data])
 
@@ -70,6 +102,12 @@ export function OrphanScanSettingsForm({
       ignorePaths: nextSettings.ignorePaths.map(p => p.trim()).filter(Boolean),
       autoCleanupEnabled: nextSettings.autoCleanupEnabled,
       autoCleanupMaxFiles: Math.max(1, nextSettings.autoCleanupMaxFiles),
+      autoCleanupUnique: nextSettings.autoCleanupUnique,
+      autoCleanupHardlinkedOrphans: nextSettings.autoCleanupHardlinkedOrphans,
+      autoCleanupHardlinkedElsewhere: nextSettings.autoCleanupHardlinkedElsewhere,
+      quarantineEnabled: nextSettings.quarantineEnabled,
+      quarantineRetentionDays: Math.max(1, nextSettings.quarantineRetentionDays),
+      sharedInstances: nextSettings.sharedInstances,
     }
 
     updateMutation.mutate(payload, {
@@ -88,7 +126,20 @@ export function OrphanScanSettingsForm({
   const handleSubmit = (event: React.FormEvent<HTMLFormElement>) => {
     event.preventDefault()
     const ignorePaths = ignorePathsText.split("\n").map(p => p.trim()).filter(Boolean)
-    persistSettings({ ...settings, ignorePaths })
+    const sharedInstances = settings.sharedInstances.map(shared => ({
+      instanceId: shared.instanceId,
+      pathMappings: parsePathMappings(sharedMappingsText[shared.instanceId] ?? ""),
+    }))
+    persistSettings({ ...settings, ignorePaths, sharedInstances })
+  }
+
+  const handleToggleShared = (sharedId: number, shared: boolean) => {
+    setSettings(prev => ({
+      ...prev,
+      sharedInstances: shared
+        ? [...prev.sharedInstances, { instanceId: sharedId, pathMappings: [] }]
+        : prev.sharedInstances.filter(s => s.instanceId !== sharedId),
+    }))
   }
 
   const handleToggleEnabled = (enabled: boolean) => {
@@ -289,6 +340,73 @@ export function OrphanScanSettingsForm({
                   <p className="text-xs text-muted-foreground">
                     If more files are found, manual review will be required instead
                   </p>
+
+                  <div className="space-y-2 pt-2">
+                    <Label className="text-sm font-medium">Delete Automatically</Label>
+                    {([
+                      ["autoCleanupUnique", "Unique files", "No other hardlinks exist"],
+                      ["autoCleanupHardlinkedOrphans", "Hardlinked orphans", "Only linked to other orphans found in the same scan"],
+                      ["autoCleanupHardlinkedElsewhere", "Hardlinked elsewhere", "Still linked from outside the scan (e.g. a media library). Deleting frees no space."],
+                    ] as const).map(([key, label, description]) => (
+                      <div key={key} className="flex items-center justify-between gap-3">
+                        <div className="space-y-0.5">
+                          <Label htmlFor={`auto-cleanup-${key}`} className="text-sm cursor-pointer">{label}</Label>
+                          <p className="text-xs text-muted-foreground">{description}</p>
+                        </div>
+                        <Switch
+                          id={`auto-cleanup-${key}`}
+                          checked={settings[key]}
+                          onCheckedChange={(checked) => setSettings(prev => ({ ...prev, [key]: checked }))}
+                        />
+                      </div>
+                    ))}
+                  </div>
+                </div>
+              )}
+
+              <div className="flex items-center justify-between p-3 bg-muted/30 rounded-lg border">
+                <div className="space-y-0.5">
+                  <div className="flex items-center gap-2">
+                    <Label htmlFor="quarantine-enabled" className="text-sm font-medium cursor-pointer">
+                      Quarantine Instead of Delete
+                    </Label>
+                    <Tooltip>
+                      <TooltipTrigger asChild>
+                        <Info className="h-3.5 w-3.5 text-muted-foreground/70 cursor-help" />
+                      </TooltipTrigger>
+                      <TooltipContent className="max-w-[300px]">
+                        <p>Confirmed orphans are moved into a .qui-quarantine folder inside their scan root, where they can be restored. Files must be on the same filesystem as the scan root.</p>
+                      </TooltipContent>
+                    </Tooltip>
+                  </div>
+                  <p className="text-xs text-muted-foreground">
+                    Keep removed files recoverable until they are purged
+                  </p>
+                </div>
+                <Switch
+                  id="quarantine-enabled"
+                  checked={settings.quarantineEnabled}
+                  onCheckedChange={(checked) => setSettings(prev => ({ ...prev, quarantineEnabled: checked }))}
+                />
+              </div>
+
+              {settings.quarantineEnabled && (
+                <div className="space-y-2 pl-3 border-l-2 border-muted">
+                  <Label htmlFor="quarantine-retention" className="text-sm font-medium">Purge After</Label>
+                  <div className="flex items-center gap-2">
+                    <Input
+                      id="quarantine-retention"
+                      type="number"
+                      min={1}
+                      value={settings.quarantineRetentionDays}
+                      onChange={(e) => setSettings(prev => ({ ...prev, quarantineRetentionDays: Number(e.target.value) || 1 }))}
+                      className="h-9 w-24"
+                    />
+                    <span className="text-sm text-muted-foreground">days</span>
+                  </div>
+                  <p className="text-xs text-muted-foreground">
+                    Space is only reclaimed once quarantined files are purged
+                  </p>
                 </div>
               )}
             </div>
@@ -326,6 +444,55 @@ export function OrphanScanSettingsForm({
             </div>
           </div>
 
+          {otherInstances.length > 0 && (
+            <div className="space-y-4">
+              <div className="flex items-center gap-2">
+                <h3 className="text-sm font-medium text-muted-foreground uppercase tracking-wider">Shared Storage</h3>
+                <Separator className="flex-1" />
+              </div>
+
+              <p className="text-xs text-muted-foreground">
+                Instances downloading to the same disk. Their torrents are loaded during scans so their files are never flagged, and scans fail if any of them is unreachable.
+              </p>
+
+              <div className="space-y-3">
+                {otherInstances.map(instance => {
+                  const isShared = settings.sharedInstances.some(s => s.instanceId === instance.id)
+                  return (
+                    <div key={instance.id} className="space-y-2">
+                      <div className="flex items-center justify-between p-3 bg-muted/30 rounded-lg border">
+                        <Label htmlFor={`shared-instance-${instance.id}`} className="text-sm font-medium cursor-pointer">
+                          {instance.name}
+                        </Label>
+                        <Switch
+                          id={`shared-instance-${instance.id}`}
+                          checked={isShared}
+                          onCheckedChange={(checked) => handleToggleShared(instance.id, checked)}
+                        />
+                      </div>
+                      {isShared && (
+                        <div className="space-y-2 pl-3 border-l-2 border-muted">
+                          <Label htmlFor={`shared-mappings-${instance.id}`} className="text-sm font-medium">Path Mappings</Label>
+                          <Textarea
+                            id={`shared-mappings-${instance.id}`}
+                            value={sharedMappingsText[instance.id] ?? ""}
+                            onChange={(e) => setSharedMappingsText(prev => ({ ...prev, [instance.id]: e.target.value }))}
+                            placeholder="/downloads => /mnt/shared"
+                            rows={2}
+                            className="font-mono text-sm"
+                          />
+                          <p className="text-xs text-muted-foreground">
+                            Only needed when {instance.name} sees the disk under a different path. One mapping per line.
+                          </p>
+                        </div>
+                      )}
+                    </div>
+                  )
+                })}
+              </div>
+            </div>
+          )}
+
       {!formId && (
         <div className="flex justify-end pt-4">
           <Button type="submit" disabled={updateMutation.isPending}>
@@ -137,6 +137,9 @@ export function ReannounceOverview({
     parts.push(`Retry ${settings.reannounceIntervalSeconds}s`)
     parts.push(`Max ${settings.maxRetries}x`)
     if (settings.aggressive) parts.push("Quick")
+    if (settings.trackerProfiles?.length) {
+      parts.push(`${settings.trackerProfiles.length} profile${settings.trackerProfiles.length !== 1 ? "s" : ""}`)
+    }
     return parts.join(" · ")
   }
 
@@ -23,13 +23,15 @@ import { useInstanceTrackers } from "@/hooks/useInstanceTrackers"
 import { useTrackerCustomizations } from "@/hooks/useTrackerCustomizations"
 import { useTrackerIcons } from "@/hooks/useTrackerIcons"
 import { api } from "@/lib/api"
-import { cn, copyTextToClipboard, formatErrorReason } from "@/lib/utils"
-import { REANNOUNCE_CONSTRAINTS, type InstanceFormData, type InstanceReannounceActivity, type InstanceReannounceSettings } from "@/types"
+import { cn, copyTextToClipboard, formatDurationCompact, formatErrorReason } from "@/lib/utils"
+import { REANNOUNCE_CONSTRAINTS, type InstanceFormData, type InstanceReannounceActivity, type InstanceReannounceSettings, type ReannounceTrackerProfile } from "@/types"
 import { useQuery } from "@tanstack/react-query"
-import { Copy, HardDrive, Info, RefreshCcw } from "lucide-react"
+import { Copy, HardDrive, Info, Plus, RefreshCcw, Trash2 } from "lucide-react"
 import { useEffect, useMemo, useState } from "react"
 import { toast } from "sonner"
 
+const STATS_WINDOW_DAYS = 7
+
 interface TrackerReannounceFormProps {
   instanceId: number
   onInstanceChange?: (instanceId: number) => void
@@ -54,6 +56,7 @@ const DEFAULT_SETTINGS: InstanceReannounceSettings = {
   tags: [],
   excludeTrackers: false,
   trackers: [],
+  trackerProfiles: [],
 }
 
 const GLOBAL_SCAN_INTERVAL_SECONDS = 7
@@ -197,6 +200,13 @@ export function TrackerReannounceForm({ instanceId, onInstanceChange, onSuccess,
       .sort((a, b) => a.label.localeCompare(b.label, undefined, { sensitivity: "base" }))
   }, [tagsQuery.data])
 
+  const updateProfile = (index: number, patch: Partial<ReannounceTrackerProfile>) => {
+    setSettings((prev) => ({
+      ...prev,
+      trackerProfiles: prev.trackerProfiles.map((profile, i) => (i === index ? { ...profile, ...patch } : profile)),
+    }))
+  }
+
   const appendUniqueValue = (field: MonitorScopeField, rawValue: string) => {
     const trimmed = rawValue.trim()
     if (!trimmed) return
@@ -268,6 +278,13 @@ export function TrackerReannounceForm({ instanceId, onInstanceChange, onSuccess,
     refetchInterval: activeTab === "activity" ? 5000 : false,
   })
 
+  const statsQuery = useQuery({
+    queryKey: ["instance-reannounce-stats", instanceId, STATS_WINDOW_DAYS],
+    queryFn: () => api.getInstanceReannounceStats(instanceId, STATS_WINDOW_DAYS),
+    enabled: variant !== "embedded" && Boolean(instance) && activeTab === "activity",
+    refetchInterval: activeTab === "activity" ? 60000 : false,
+  })
+
   if (!instance) {
     return <p className="text-sm text-muted-foreground">Instance not found. Please close and reopen the dialog.</p>
   }
@@ -558,6 +575,97 @@ export function TrackerReannounceForm({ instanceId, onInstanceChange, onSuccess,
                     </div>
                   </div>
 
+                  <div className="space-y-4">
+                    <div className="flex items-center gap-2">
+                      <h3 className="text-sm font-medium text-muted-foreground uppercase tracking-wider">Tracker Profiles</h3>
+                      <Tooltip>
+                        <TooltipTrigger asChild>
+                          <Info className="h-4 w-4 text-muted-foreground cursor-help" />
+                        </TooltipTrigger>
+                        <TooltipContent className="max-w-[300px]">
+                          <p>Override timing for torrents on specific trackers. Match by tracker domain or by the display name from Tracker Customizations. Empty fields use the values above. When a torrent has several trackers, the first one with a profile wins.</p>
+                        </TooltipContent>
+                      </Tooltip>
+                      <Separator className="flex-1" />
+                    </div>
+
+                    {settings.trackerProfiles.map((profile, index) => (
+                      <div key={index} className="grid gap-3 rounded-lg border p-3 bg-muted/40 md:grid-cols-[2fr_1fr_1fr_1fr_1fr_auto] md:items-end">
+                        <div className="space-y-1">
+                          <Label className="text-xs">Tracker</Label>
+                          <Input
+                            value={profile.tracker}
+                            placeholder="tracker.example.com"
+                            list="reannounce-profile-trackers"
+                            onChange={(e) => updateProfile(index, { tracker: e.target.value })}
+                          />
+                        </div>
+                        <ProfileNumberInput
+                          label="Initial Wait"
+                          value={profile.initialWaitSeconds}
+                          fallback={settings.initialWaitSeconds}
+                          min={0}
+                          onChange={(value) => updateProfile(index, { initialWaitSeconds: value })}
+                        />
+                        <ProfileNumberInput
+                          label="Retry Interval"
+                          value={profile.reannounceIntervalSeconds}
+                          fallback={settings.reannounceIntervalSeconds}
+                          min={REANNOUNCE_CONSTRAINTS.MIN_INTERVAL}
+                          onChange={(value) => updateProfile(index, { reannounceIntervalSeconds: value })}
+                        />
+                        <ProfileNumberInput
+                          label="Max Retries"
+                          value={profile.maxRetries}
+                          fallback={settings.maxRetries}
+                          min={REANNOUNCE_CONSTRAINTS.MIN_MAX_RETRIES}
+                          max={REANNOUNCE_CONSTRAINTS.MAX_MAX_RETRIES}
+                          onChange={(value) => updateProfile(index, { maxRetries: value })}
+                        />
+                        <div className="space-y-1">
+                          <Label className="text-xs">Quick Retry</Label>
+                          <Select
+                            value={profile.aggressive === undefined ? "inherit" : profile.aggressive ? "on" : "off"}
+                            onValueChange={(value) => updateProfile(index, { aggressive: value === "inherit" ? undefined : value === "on" })}
+                          >
+                            <SelectTrigger>
+                              <SelectValue />
+                            </SelectTrigger>
+                            <SelectContent>
+                              <SelectItem value="inherit">Inherit</SelectItem>
+                              <SelectItem value="on">On</SelectItem>
+                              <SelectItem value="off">Off</SelectItem>
+                            </SelectContent>
+                          </Select>
+                        </div>
+                        <Button
+                          type="button"
+                          variant="ghost"
+                          size="icon"
+                          onClick={() => setSettings((prev) => ({ ...prev, trackerProfiles: prev.trackerProfiles.filter((_, i) => i !== index) }))}
+                          title="Remove profile"
+                        >
+                          <Trash2 className="h-4 w-4" />
+                        </Button>
+                      </div>
+                    ))}
+                    <datalist id="reannounce-profile-trackers">
+                      {trackerOptions.map((option) => (
+                        <option key={option.value} value={option.label} />
+                      ))}
+                    </datalist>
+
+                    <Button
+                      type="button"
+                      variant="outline"
+                      size="sm"
+                      onClick={() => setSettings((prev) => ({ ...prev, trackerProfiles: [...prev.trackerProfiles, { tracker: "" }] }))}
+                    >
+                      <Plus className="h-4 w-4 mr-2" />
+                      Add Profile
+                    </Button>
+                  </div>
+
       {!formId && (
         <div className="flex justify-end pt-4">
           <Button type="submit" disabled={isUpdating}>
@@ -568,8 +676,47 @@ export function TrackerReannounceForm({ instanceId, onInstanceChange, onSuccess,
     </div>
   )
 
+  const trackerStats = statsQuery.data ?? []
+
   const activityContent = (
     <div className="space-y-4">
+              {trackerStats.length > 0 && (
+                <div className="space-y-2">
+                  <div className="space-y-1">
+                    <h3 className="text-sm font-medium leading-none">Tracker Statistics</h3>
+                    <p className="text-sm text-muted-foreground">
+                      Reannounce results over the last {STATS_WINDOW_DAYS} days. Time to working is how long after being added a torrent's first reannounce succeeded.
+                    </p>
+                  </div>
+                  <div className="rounded-md border overflow-x-auto">
+                    <table className="w-full text-sm">
+                      <thead>
+                        <tr className="border-b bg-muted/40">
+                          <th className="text-left p-2 font-medium">Tracker</th>
+                          <th className="text-right p-2 font-medium">Attempts</th>
+                          <th className="text-right p-2 font-medium">Success Rate</th>
+                          <th className="text-right p-2 font-medium">Time to Working</th>
+                        </tr>
+                      </thead>
+                      <tbody>
+                        {trackerStats.map((stats) => (
+                          <tr key={stats.tracker} className="border-b last:border-0">
+                            <td className="p-2 break-all">{stats.tracker}</td>
+                            <td className="p-2 text-right font-mono">{stats.attempts}</td>
+                            <td className={cn("p-2 text-right font-mono", stats.successRate < 0.5 && "text-destructive")}>
+                              {Math.round(stats.successRate * 100)}%
+                            </td>
+                            <td className="p-2 text-right font-mono text-muted-foreground">
+                              {stats.avgTimeToWorkingSeconds !== undefined ? formatDurationCompact(stats.avgTimeToWorkingSeconds) : "-"}
+                            </td>
+                          </tr>
+                        ))}
+                      </tbody>
+                    </table>
+                  </div>
+                </div>
+              )}
+
               <div className="flex items-center justify-between">
                 <div className="space-y-1">
                   <h3 className="text-sm font-medium leading-none">Recent Activity</h3>
@@ -812,6 +959,42 @@ function NumberField({ id, label, description, tooltip, value, min, max, onChang
   )
 }
 
+interface ProfileNumberInputProps {
+  label: string
+  value?: number
+  fallback: number
+  min: number
+  max?: number
+  onChange: (value: number | undefined) => void
+}
+
+// ProfileNumberInput edits an optional override; clearing it inherits the instance value.
+function ProfileNumberInput({ label, value, fallback, min, max, onChange }: ProfileNumberInputProps) {
+  return (
+    <div className="space-y-1">
+      <Label className="text-xs">{label}</Label>
+      <Input
+        type="number"
+        inputMode="numeric"
+        min={min}
+        max={max}
+        placeholder={String(fallback)}
+        value={value ?? ""}
+        onChange={(event) => {
+          const raw = event.target.value
+          const parsed = Math.floor(Number(raw))
+          if (!raw.trim() || !Number.isFinite(parsed)) {
+            onChange(undefined)
+            return
+          }
+          onChange(max !== undefined ? Math.min(max, Math.max(min, parsed)) : Math.max(min, parsed))
+        }}
+        className="h-9"
+      />
+    </div>
+  )
+}
+
 function cloneSettings(settings?: InstanceReannounceSettings): InstanceReannounceSettings {
   if (!settings) {
     return { ...DEFAULT_SETTINGS }
@@ -829,6 +1012,7 @@ function cloneSettings(settings?: InstanceReannounceSettings): InstanceReannounc
     tags: [...settings.tags],
     excludeTrackers: settings.excludeTrackers,
     trackers: [...settings.trackers],
+    trackerProfiles: (settings.trackerProfiles ?? []).map((profile) => ({ ...profile })),
     aggressive: settings.aggressive,
   }
 }
@@ -854,6 +1038,9 @@ function sanitizeSettings(settings: InstanceReannounceSettings): InstanceReannou
     tags: normalizeList(settings.tags),
     excludeTrackers: settings.excludeTrackers,
     trackers: normalizeList(settings.trackers),
+    trackerProfiles: settings.trackerProfiles
+      .map((profile) => ({ ...profile, tracker: profile.tracker.trim() }))
+      .filter((profile) => profile.tracker !== ""),
     aggressive: settings.aggressive,
   }
 }
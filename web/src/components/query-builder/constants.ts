@@ -18,6 +18,8 @@ export const CONDITION_FIELDS = {
   UPLOADED: { label: "Uploaded", type: "bytes" as const, description: "Total uploaded" },
   AMOUNT_LEFT: { label: "Amount Left", type: "bytes" as const, description: "Remaining to download" },
   FREE_SPACE: { label: "Free Space", type: "bytes" as const, description: "Free space on the instance's filesystem" },
+  UPLOADED_LAST_7D: { label: "Uploaded (7 Days)", type: "bytes" as const, description: "Uploaded in the last 7 days" },
+  UPLOADED_LAST_30D: { label: "Uploaded (30 Days)", type: "bytes" as const, description: "Uploaded in the last 30 days" },
 
   // Duration fields (seconds)
   SEEDING_TIME: { label: "Seeding Time", type: "duration" as const, description: "Time spent seeding" },
@@ -25,6 +27,7 @@ export const CONDITION_FIELDS = {
   ADDED_ON_AGE: { label: "Added Age", type: "duration" as const, description: "Time since torrent was added" },
   COMPLETION_ON_AGE: { label: "Completed Age", type: "duration" as const, description: "Time since download completed" },
   LAST_ACTIVITY_AGE: { label: "Inactive Time", type: "duration" as const, description: "Time since last activity" },
+  LAST_UPLOAD_AGE: { label: "Last Upload Age", type: "duration" as const, description: "Time since the torrent last uploaded" },
 
   // Float fields
   RATIO: { label: "Ratio", type: "float" as const, description: "Upload/download ratio" },
@@ -170,11 +173,11 @@ export const FIELD_GROUPS = [
   },
   {
     label: "Size",
-    fields: ["SIZE", "TOTAL_SIZE", "DOWNLOADED", "UPLOADED", "AMOUNT_LEFT", "FREE_SPACE"],
+    fields: ["SIZE", "TOTAL_SIZE", "DOWNLOADED", "UPLOADED", "AMOUNT_LEFT", "FREE_SPACE", "UPLOADED_LAST_7D", "UPLOADED_LAST_30D"],
   },
   {
     label: "Time",
-    fields: ["SEEDING_TIME", "TIME_ACTIVE", "ADDED_ON_AGE", "COMPLETION_ON_AGE", "LAST_ACTIVITY_AGE"],
+    fields: ["SEEDING_TIME", "TIME_ACTIVE", "ADDED_ON_AGE", "COMPLETION_ON_AGE", "LAST_ACTIVITY_AGE", "LAST_UPLOAD_AGE"],
   },
   {
     label: "Progress",
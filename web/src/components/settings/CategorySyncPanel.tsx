@@ -0,0 +1,380 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle
+} from "@/components/ui/alert-dialog"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Switch } from "@/components/ui/switch"
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow
+} from "@/components/ui/table"
+import { useInstances } from "@/hooks/useInstances"
+import { api } from "@/lib/api"
+import type { CategoryDriftStatus, CategorySyncDrift, CategorySyncReconcileResult } from "@/types"
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
+import { Loader2, Plus, RefreshCw, Trash2 } from "lucide-react"
+import { useEffect, useState } from "react"
+import { toast } from "sonner"
+
+interface CategoryRow {
+  name: string
+  savePath: string
+}
+
+const DRIFT_LABELS: Record<CategoryDriftStatus, string> = {
+  missing: "Missing",
+  different: "Different path",
+  extra: "Not in templates",
+}
+
+function summarizeResults(results: CategorySyncReconcileResult[]): string {
+  const created = results.reduce((sum, r) => sum + r.categoriesCreated + r.tagsCreated, 0)
+  const updated = results.reduce((sum, r) => sum + r.categoriesUpdated, 0)
+  const removed = results.reduce((sum, r) => sum + r.categoriesRemoved + r.tagsRemoved, 0)
+  return `${created} created, ${updated} updated, ${removed} removed`
+}
+
+function DriftCard({
+  report,
+  reconciling,
+  onReconcile,
+}: {
+  report: CategorySyncDrift
+  reconciling: boolean
+  onReconcile: () => void
+}) {
+  return (
+    <div className="rounded-md border p-3 space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <div className="min-w-0">
+          <span className="font-medium">{report.instanceName || `Instance ${report.instanceId}`}</span>
+          {report.savePathPrefix && (
+            <span className="ml-2 text-xs text-muted-foreground">{report.savePathPrefix}</span>
+          )}
+        </div>
+        <div className="flex items-center gap-2">
+          {report.error ? (
+            <Badge variant="destructive">{report.error}</Badge>
+          ) : report.inSync ? (
+            <Badge variant="secondary">In sync</Badge>
+          ) : (
+            <Badge variant="outline">Drifted</Badge>
+          )}
+          <Button
+            size="sm"
+            variant="outline"
+            disabled={report.inSync || !!report.error || reconciling}
+            onClick={onReconcile}
+          >
+            Reconcile
+          </Button>
+        </div>
+      </div>
+      {report.categories.length > 0 && (
+        <ul className="text-xs text-muted-foreground space-y-1">
+          {report.categories.map((category) => (
+            <li key={category.name} className="truncate">
+              <span className="text-foreground">{category.name}</span>
+              {" — "}
+              {DRIFT_LABELS[category.status]}
+              {category.status === "different" && `: ${category.actualSavePath || "(default)"} → ${category.expectedSavePath || "(default)"}`}
+              {category.status === "missing" && category.expectedSavePath && `: ${category.expectedSavePath}`}
+            </li>
+          ))}
+        </ul>
+      )}
+      {(report.missingTags.length > 0 || report.extraTags.length > 0) && (
+        <div className="flex flex-wrap gap-1">
+          {report.missingTags.map((tag) => (
+            <Badge key={`missing-${tag}`} variant="outline">+ {tag}</Badge>
+          ))}
+          {report.extraTags.map((tag) => (
+            <Badge key={`extra-${tag}`} variant="outline" className="text-muted-foreground">− {tag}</Badge>
+          ))}
+        </div>
+      )}
+    </div>
+  )
+}
+
+export function CategorySyncPanel() {
+  const queryClient = useQueryClient()
+  const { instances } = useInstances()
+
+  const [categories, setCategories] = useState<CategoryRow[]>([])
+  const [tags, setTags] = useState("")
+  const [targets, setTargets] = useState<Record<number, string>>({})
+  const [removeExtra, setRemoveExtra] = useState(false)
+  const [confirmIds, setConfirmIds] = useState<number[] | null>(null)
+
+  const { data: templates } = useQuery({
+    queryKey: ["category-sync", "templates"],
+    queryFn: () => api.getCategoryTemplates(),
+  })
+
+  const { data: savedTargets } = useQuery({
+    queryKey: ["category-sync", "targets"],
+    queryFn: () => api.getCategorySyncTargets(),
+  })
+
+  const { data: drift, isFetching: driftLoading, refetch: refetchDrift } = useQuery({
+    queryKey: ["category-sync", "drift"],
+    queryFn: () => api.getCategorySyncDrift(),
+  })
+
+  useEffect(() => {
+    if (!templates) return
+    setCategories(templates.categories.map(({ name, savePath }) => ({ name, savePath })))
+    setTags(templates.tags.join(", "))
+  }, [templates])
+
+  useEffect(() => {
+    if (!savedTargets) return
+    setTargets(Object.fromEntries(savedTargets.map((target) => [target.instanceId, target.savePathPrefix])))
+  }, [savedTargets])
+
+  const invalidate = () => queryClient.invalidateQueries({ queryKey: ["category-sync"] })
+
+  const saveTemplatesMutation = useMutation({
+    mutationFn: () => api.updateCategoryTemplates({
+      categories: categories.filter((row) => row.name.trim() !== ""),
+      tags: tags.split(",").map((tag) => tag.trim()).filter(Boolean),
+    }),
+    onSuccess: () => {
+      toast.success("Templates saved")
+      invalidate()
+    },
+    onError: (error: Error) => toast.error(`Failed to save templates: ${error.message}`),
+  })
+
+  const saveTargetsMutation = useMutation({
+    mutationFn: () => api.updateCategorySyncTargets(
+      Object.entries(targets).map(([instanceId, savePathPrefix]) => ({ instanceId: Number(instanceId), savePathPrefix }))
+    ),
+    onSuccess: () => {
+      toast.success("Sync targets saved")
+      invalidate()
+    },
+    onError: (error: Error) => toast.error(`Failed to save sync targets: ${error.message}`),
+  })
+
+  const reconcileMutation = useMutation({
+    mutationFn: (instanceIds: number[]) => api.reconcileCategorySync(instanceIds, removeExtra),
+    onSuccess: (results) => {
+      const failed = results.filter((result) => result.errors && result.errors.length > 0)
+      if (failed.length > 0) {
+        toast.warning(`Reconciled with errors: ${failed.flatMap((result) => result.errors ?? []).join("; ")}`)
+      } else {
+        toast.success(`Reconciled: ${summarizeResults(results)}`)
+      }
+      invalidate()
+    },
+    onError: (error: Error) => toast.error(`Failed to reconcile: ${error.message}`),
+  })
+
+  const reconcile = (instanceIds: number[]) => {
+    if (removeExtra) {
+      setConfirmIds(instanceIds)
+      return
+    }
+    reconcileMutation.mutate(instanceIds)
+  }
+
+  const updateCategory = (index: number, patch: Partial<CategoryRow>) => {
+    setCategories((prev) => prev.map((row, i) => (i === index ? { ...row, ...patch } : row)))
+  }
+
+  const toggleTarget = (instanceId: number, enabled: boolean) => {
+    setTargets((prev) => {
+      const next = { ...prev }
+      if (enabled) {
+        next[instanceId] = prev[instanceId] ?? ""
+      } else {
+        delete next[instanceId]
+      }
+      return next
+    })
+  }
+
+  return (
+    <div className="space-y-8">
+      <section className="space-y-4">
+        <div>
+          <h3 className="text-sm font-medium">Templates</h3>
+          <p className="text-sm text-muted-foreground">
+            Relative save paths are joined onto each target's prefix; absolute paths are used as-is.
+          </p>
+        </div>
+        <div className="space-y-2">
+          {categories.map((row, index) => (
+            <div key={index} className="flex gap-2">
+              <Input
+                aria-label="Category name"
+                placeholder="Category"
+                value={row.name}
+                onChange={(e) => updateCategory(index, { name: e.target.value })}
+              />
+              <Input
+                aria-label="Save path"
+                placeholder="Save path (optional)"
+                value={row.savePath}
+                onChange={(e) => updateCategory(index, { savePath: e.target.value })}
+              />
+              <Button
+                variant="ghost"
+                size="icon"
+                aria-label="Remove category"
+                onClick={() => setCategories((prev) => prev.filter((_, i) => i !== index))}
+              >
+                <Trash2 className="h-4 w-4" />
+              </Button>
+            </div>
+          ))}
+          <Button variant="outline" size="sm" onClick={() => setCategories((prev) => [...prev, { name: "", savePath: "" }])}>
+            <Plus className="h-4 w-4 mr-2" />
+            Add category
+          </Button>
+        </div>
+        <div className="space-y-2">
+          <Label htmlFor="category-sync-tags">Tags</Label>
+          <Input
+            id="category-sync-tags"
+            placeholder="Comma-separated"
+            value={tags}
+            onChange={(e) => setTags(e.target.value)}
+          />
+        </div>
+        <Button disabled={saveTemplatesMutation.isPending} onClick={() => saveTemplatesMutation.mutate()}>
+          {saveTemplatesMutation.isPending && <Loader2 className="h-4 w-4 mr-2 animate-spin" />}
+          Save templates
+        </Button>
+      </section>
+
+      <section className="space-y-4">
+        <div>
+          <h3 className="text-sm font-medium">Sync targets</h3>
+          <p className="text-sm text-muted-foreground">
+            Instances that receive the templates. The prefix is the base directory on that instance's host.
+          </p>
+        </div>
+        <Table>
+          <TableHeader>
+            <TableRow>
+              <TableHead className="w-16">Sync</TableHead>
+              <TableHead>Instance</TableHead>
+              <TableHead>Save path prefix</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {(instances ?? []).map((instance) => {
+              const enabled = instance.id in targets
+              return (
+                <TableRow key={instance.id}>
+                  <TableCell>
+                    <Switch
+                      checked={enabled}
+                      onCheckedChange={(checked) => toggleTarget(instance.id, checked)}
+                      aria-label={`Sync ${instance.name}`}
+                    />
+                  </TableCell>
+                  <TableCell>{instance.name}</TableCell>
+                  <TableCell>
+                    <Input
+                      aria-label={`Save path prefix for ${instance.name}`}
+                      placeholder="/data/torrents"
+                      disabled={!enabled}
+                      value={targets[instance.id] ?? ""}
+                      onChange={(e) => setTargets((prev) => ({ ...prev, [instance.id]: e.target.value }))}
+                    />
+                  </TableCell>
+                </TableRow>
+              )
+            })}
+          </TableBody>
+        </Table>
+        <Button disabled={saveTargetsMutation.isPending} onClick={() => saveTargetsMutation.mutate()}>
+          {saveTargetsMutation.isPending && <Loader2 className="h-4 w-4 mr-2 animate-spin" />}
+          Save targets
+        </Button>
+      </section>
+
+      <section className="space-y-4">
+        <div className="flex items-center justify-between gap-4">
+          <div>
+            <h3 className="text-sm font-medium">Drift</h3>
+            <p className="text-sm text-muted-foreground">Differences between the templates and each target.</p>
+          </div>
+          <div className="flex gap-2">
+            <Button variant="outline" size="sm" disabled={driftLoading} onClick={() => refetchDrift()}>
+              <RefreshCw className={`h-4 w-4 mr-2 ${driftLoading ? "animate-spin" : ""}`} />
+              Refresh
+            </Button>
+            <Button
+              size="sm"
+              disabled={!drift || drift.every((report) => report.inSync || report.error) || reconcileMutation.isPending}
+              onClick={() => reconcile([])}
+            >
+              Reconcile all
+            </Button>
+          </div>
+        </div>
+        <div className="flex items-center gap-2">
+          <Switch id="category-sync-remove-extra" checked={removeExtra} onCheckedChange={setRemoveExtra} />
+          <Label htmlFor="category-sync-remove-extra">Remove categories and tags not in the templates</Label>
+        </div>
+        {drift && drift.length === 0 && (
+          <p className="text-sm text-muted-foreground">No sync targets configured.</p>
+        )}
+        {drift?.map((report) => (
+          <DriftCard
+            key={report.instanceId}
+            report={report}
+            reconciling={reconcileMutation.isPending}
+            onReconcile={() => reconcile([report.instanceId])}
+          />
+        ))}
+      </section>
+
+      <AlertDialog open={confirmIds !== null} onOpenChange={(open) => !open && setConfirmIds(null)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>Remove extra categories and tags?</AlertDialogTitle>
+            <AlertDialogDescription>
+              Categories and tags that are not in the templates will be deleted from the selected instances.
+              Torrents using them will lose that category or tag.
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>Cancel</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={() => {
+                if (confirmIds) reconcileMutation.mutate(confirmIds)
+                setConfirmIds(null)
+              }}
+            >
+              Reconcile
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+    </div>
+  )
+}
@@ -0,0 +1,272 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle
+} from "@/components/ui/alert-dialog"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Label } from "@/components/ui/label"
+import { Switch } from "@/components/ui/switch"
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow
+} from "@/components/ui/table"
+import { api } from "@/lib/api"
+import { formatBytes } from "@/lib/utils"
+import type { DuplicateGroup, DuplicateMember } from "@/types"
+import { useMutation, useQuery } from "@tanstack/react-query"
+import { Link2, Loader2, RefreshCw, Trash2 } from "lucide-react"
+import { useState } from "react"
+import { toast } from "sonner"
+
+const MATCH_LABELS: Record<string, string> = {
+  infohash: "Infohash",
+  content: "Content path",
+  release: "Release",
+}
+
+type PendingAction =
+  | { type: "delete"; member: DuplicateMember; canDeleteFiles: boolean }
+  | { type: "hardlink"; member: DuplicateMember; source: DuplicateMember }
+
+// hardlinkSource picks the member whose data a duplicate would be linked to: a copy
+// other than the member's own that qui can read, preferring the first copy.
+function hardlinkSource(group: DuplicateGroup, member: DuplicateMember): DuplicateMember | undefined {
+  if (!member.localAccess) return undefined
+  return group.members.find((other) => other.storage !== member.storage && other.localAccess)
+}
+
+// ownsData reports whether the member's files may be deleted. The server verifies every
+// file on disk, which needs local filesystem access for all members.
+function ownsData(group: DuplicateGroup, member: DuplicateMember): boolean {
+  return (
+    group.copies > 1 &&
+    group.members.every((other) => other.localAccess) &&
+    group.members.filter((other) => other.storage === member.storage).length === 1
+  )
+}
+
+function GroupCard({
+  group,
+  busy,
+  onAction,
+}: {
+  group: DuplicateGroup
+  busy: boolean
+  onAction: (action: PendingAction) => void
+}) {
+  return (
+    <div className="rounded-md border p-3 space-y-2">
+      <div className="flex flex-wrap items-center justify-between gap-2">
+        <span className="font-medium truncate min-w-0">{group.name}</span>
+        <div className="flex flex-wrap items-center gap-1">
+          {group.matchedBy.map((match) => (
+            <Badge key={match} variant="outline">{MATCH_LABELS[match] ?? match}</Badge>
+          ))}
+          {group.kind === "duplicate" ? (
+            <Badge variant="destructive">
+              {group.copies} copies · {formatBytes(group.reclaimableBytes)} reclaimable
+            </Badge>
+          ) : (
+            <Badge variant="secondary">Cross-seed · shared data</Badge>
+          )}
+        </div>
+      </div>
+      <Table>
+        <TableHeader>
+          <TableRow>
+            <TableHead className="w-16">Copy</TableHead>
+            <TableHead>Instance</TableHead>
+            <TableHead>Location</TableHead>
+            <TableHead className="text-right">Size</TableHead>
+            <TableHead className="w-24" />
+          </TableRow>
+        </TableHeader>
+        <TableBody>
+          {group.members.map((member) => {
+            const source = group.kind === "duplicate" ? hardlinkSource(group, member) : undefined
+            return (
+              <TableRow key={`${member.instanceId}:${member.hash}`}>
+                <TableCell>#{member.storage}</TableCell>
+                <TableCell>
+                  {member.instanceName}
+                  {member.category && <p className="text-xs text-muted-foreground">{member.category}</p>}
+                </TableCell>
+                <TableCell className="max-w-xs">
+                  <p className="truncate text-xs" title={member.contentPath}>{member.contentPath}</p>
+                  {member.tracker && <p className="truncate text-xs text-muted-foreground">{member.tracker}</p>}
+                </TableCell>
+                <TableCell className="text-right">{formatBytes(member.size)}</TableCell>
+                <TableCell>
+                  <div className="flex justify-end gap-1">
+                    {source && (
+                      <Button
+                        variant="ghost"
+                        size="icon"
+                        aria-label={`Hardlink to copy #${source.storage}`}
+                        title={`Replace with hardlinks to copy #${source.storage}`}
+                        disabled={busy}
+                        onClick={() => onAction({ type: "hardlink", member, source })}
+                      >
+                        <Link2 className="h-4 w-4" />
+                      </Button>
+                    )}
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      aria-label="Delete torrent"
+                      disabled={busy}
+                      onClick={() => onAction({ type: "delete", member, canDeleteFiles: ownsData(group, member) })}
+                    >
+                      <Trash2 className="h-4 w-4" />
+                    </Button>
+                  </div>
+                </TableCell>
+              </TableRow>
+            )
+          })}
+        </TableBody>
+      </Table>
+    </div>
+  )
+}
+
+export function DuplicatesPanel() {
+  const [showCrossSeeds, setShowCrossSeeds] = useState(false)
+  const [pending, setPending] = useState<PendingAction | null>(null)
+  const [deleteFiles, setDeleteFiles] = useState(false)
+
+  const { data: report, isFetching, refetch } = useQuery({
+    queryKey: ["duplicates", "report"],
+    queryFn: () => api.getDuplicateReport(),
+    staleTime: 60_000,
+  })
+
+  const actionMutation = useMutation({
+    mutationFn: async (action: PendingAction) => {
+      if (action.type === "hardlink") {
+        const result = await api.hardlinkDuplicate(
+          action.member.instanceId,
+          action.member.hash,
+          action.source.instanceId,
+          action.source.hash
+        )
+        return `Linked ${result.filesLinked} files (${formatBytes(result.bytesLinked)}); recheck started`
+      }
+      await api.deleteDuplicate(action.member.instanceId, action.member.hash, action.canDeleteFiles && deleteFiles)
+      return "Torrent deleted"
+    },
+    onSuccess: (message) => {
+      toast.success(message)
+      refetch()
+    },
+    onError: (error: Error) => toast.error(error.message),
+  })
+
+  const openAction = (action: PendingAction) => {
+    setDeleteFiles(false)
+    setPending(action)
+  }
+
+  const groups = report?.groups.filter((group) => showCrossSeeds || group.kind === "duplicate") ?? []
+
+  return (
+    <div className="space-y-4">
+      <div className="flex flex-wrap items-center justify-between gap-4">
+        <div className="text-sm">
+          {report ? (
+            <>
+              <span className="font-medium">{formatBytes(report.reclaimableBytes)}</span> reclaimable across{" "}
+              {report.duplicateGroups} duplicate group{report.duplicateGroups === 1 ? "" : "s"};{" "}
+              {report.crossSeedGroups} cross-seed group{report.crossSeedGroups === 1 ? "" : "s"} share data
+            </>
+          ) : (
+            <span className="text-muted-foreground">Scanning instances…</span>
+          )}
+        </div>
+        <div className="flex items-center gap-4">
+          <div className="flex items-center gap-2">
+            <Switch id="duplicates-cross-seeds" checked={showCrossSeeds} onCheckedChange={setShowCrossSeeds} />
+            <Label htmlFor="duplicates-cross-seeds">Show cross-seeds</Label>
+          </div>
+          <Button variant="outline" size="sm" disabled={isFetching} onClick={() => refetch()}>
+            <RefreshCw className={`h-4 w-4 mr-2 ${isFetching ? "animate-spin" : ""}`} />
+            Rescan
+          </Button>
+        </div>
+      </div>
+
+      {report?.errors?.map((error) => (
+        <p key={error.instanceId} className="text-sm text-destructive">
+          {error.instanceName}: {error.error}
+        </p>
+      ))}
+
+      {report && groups.length === 0 && (
+        <p className="text-sm text-muted-foreground">No duplicates found.</p>
+      )}
+
+      {groups.map((group) => (
+        <GroupCard key={group.id} group={group} busy={actionMutation.isPending} onAction={openAction} />
+      ))}
+
+      <AlertDialog open={pending !== null} onOpenChange={(open) => !open && setPending(null)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>
+              {pending?.type === "hardlink" ? "Convert to hardlinked cross-seed?" : "Delete torrent?"}
+            </AlertDialogTitle>
+            <AlertDialogDescription>
+              {pending?.type === "hardlink" ? (
+                <>
+                  The files of {pending.member.name} on {pending.member.instanceName} will be replaced with hardlinks to
+                  copy #{pending.source.storage} on {pending.source.instanceName}, freeing{" "}
+                  {formatBytes(pending.member.size)}. The torrent is paused and rechecked. Both copies must be on the
+                  same filesystem.
+                </>
+              ) : pending ? (
+                <>
+                  {pending.member.name} will be removed from {pending.member.instanceName}.
+                  {!pending.canDeleteFiles &&
+                    " Its files will be kept, because they are shared with other torrents or not every instance in the group has local filesystem access."}
+                </>
+              ) : null}
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          {pending?.type === "delete" && pending.canDeleteFiles && (
+            <div className="flex items-center gap-2">
+              <Switch id="duplicates-delete-files" checked={deleteFiles} onCheckedChange={setDeleteFiles} />
+              <Label htmlFor="duplicates-delete-files">Also delete its files ({formatBytes(pending.member.size)})</Label>
+            </div>
+          )}
+          <AlertDialogFooter>
+            <AlertDialogCancel>Cancel</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={() => {
+                if (pending) actionMutation.mutate(pending)
+                setPending(null)
+              }}
+            >
+              {actionMutation.isPending && <Loader2 className="h-4 w-4 mr-2 animate-spin" />}
+              {pending?.type === "hardlink" ? "Convert" : "Delete"}
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+    </div>
+  )
+}
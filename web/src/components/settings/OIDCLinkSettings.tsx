@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { useAuth } from "@/hooks/useAuth"
+import { api } from "@/lib/api"
+import { useMutation } from "@tanstack/react-query"
+import { toast } from "sonner"
+
+export function OIDCLinkSettings() {
+  const { user } = useAuth()
+
+  const linkMutation = useMutation({
+    mutationFn: () => api.getOIDCLinkConfig(),
+    onSuccess: (config) => {
+      window.location.href = config.authorizationUrl
+    },
+    onError: (error) => toast.error(error.message || "Failed to start OIDC login"),
+  })
+
+  if (user?.oidc) {
+    return (
+      <div className="flex items-center gap-2 text-sm">
+        <Badge variant="secondary">Linked</Badge>
+        <span className="text-muted-foreground">You can sign in to this account with OIDC.</span>
+      </div>
+    )
+  }
+
+  return (
+    <div className="space-y-3">
+      <p className="text-sm text-muted-foreground">
+        Sign in with your identity provider to link it to this account. Afterwards you can use either login.
+      </p>
+      <Button onClick={() => linkMutation.mutate()} disabled={linkMutation.isPending}>
+        Link OIDC login
+      </Button>
+    </div>
+  )
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { useAuth } from "@/hooks/useAuth"
+import { useDateTimeFormatters } from "@/hooks/useDateTimeFormatters"
+import { api } from "@/lib/api"
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
+import { Trash2 } from "lucide-react"
+import { toast } from "sonner"
+
+export function SessionsManager() {
+  const queryClient = useQueryClient()
+  const { formatDate } = useDateTimeFormatters()
+  const { setIsAuthenticated } = useAuth()
+
+  const { data: sessions, isLoading } = useQuery({
+    queryKey: ["auth", "sessions"],
+    queryFn: () => api.getSessions(),
+  })
+
+  const revokeMutation = useMutation({
+    mutationFn: (id: string) => api.revokeSession(id),
+    onSuccess: () => {
+      toast.success("Session signed out")
+      queryClient.invalidateQueries({ queryKey: ["auth", "sessions"] })
+    },
+    onError: (error) => toast.error(error.message || "Failed to sign out session"),
+  })
+
+  const revokeAllMutation = useMutation({
+    mutationFn: (keepCurrent: boolean) => api.revokeAllSessions(keepCurrent),
+    onSuccess: (_, keepCurrent) => {
+      if (keepCurrent) {
+        toast.success("Signed out of all other sessions")
+        queryClient.invalidateQueries({ queryKey: ["auth", "sessions"] })
+        return
+      }
+      setIsAuthenticated(false)
+    },
+    onError: (error) => toast.error(error.message || "Failed to sign out sessions"),
+  })
+
+  if (isLoading) {
+    return <p className="text-sm text-muted-foreground">Loading...</p>
+  }
+
+  return (
+    <div className="space-y-4">
+      <div className="space-y-2">
+        {sessions?.map((session) => (
+          <div
+            key={session.id}
+            className="flex items-center bg-muted/40 justify-between rounded-lg border p-4"
+          >
+            <div className="space-y-1 min-w-0">
+              <div className="flex items-center gap-2">
+                <span className="font-medium">{session.ip || "Unknown address"}</span>
+                {session.current && <Badge variant="outline" className="text-xs">This session</Badge>}
+                {session.authMethod && <Badge variant="secondary" className="text-xs">{session.authMethod}</Badge>}
+              </div>
+              <p className="text-sm text-muted-foreground truncate">{session.userAgent || "Unknown client"}</p>
+              <p className="text-sm text-muted-foreground">
+                {session.createdAt && <>Signed in: {formatDate(new Date(session.createdAt))}</>}
+                {session.lastSeenAt && <> • Last seen: {formatDate(new Date(session.lastSeenAt))}</>}
+              </p>
+            </div>
+            {!session.current && (
+              <Button
+                size="icon"
+                variant="ghost"
+                onClick={() => revokeMutation.mutate(session.id)}
+                disabled={revokeMutation.isPending}
+              >
+                <Trash2 className="h-4 w-4" />
+              </Button>
+            )}
+          </div>
+        ))}
+      </div>
+
+      <div className="flex flex-wrap gap-2">
+        <Button
+          variant="outline"
+          onClick={() => revokeAllMutation.mutate(true)}
+          disabled={revokeAllMutation.isPending}
+        >
+          Sign out other sessions
+        </Button>
+        <Button
+          variant="destructive"
+          onClick={() => revokeAllMutation.mutate(false)}
+          disabled={revokeAllMutation.isPending}
+        >
+          Log out everywhere
+        </Button>
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle
+} from "@/components/ui/alert-dialog"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Progress } from "@/components/ui/progress"
+import { Switch } from "@/components/ui/switch"
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow
+} from "@/components/ui/table"
+import { api } from "@/lib/api"
+import type { TrackerRotationBackupResult, TrackerRotationPreview, TrackerRotationSpec } from "@/types"
+import { useMutation, useQuery } from "@tanstack/react-query"
+import { Loader2, Search } from "lucide-react"
+import { useState } from "react"
+import { toast } from "sonner"
+
+// Torrents listed per instance in the preview; the rest are only counted
+const PREVIEW_SAMPLE_SIZE = 10
+
+function BackupSummary({ result, applied }: { result: TrackerRotationBackupResult; applied: boolean }) {
+  return (
+    <p className="text-sm text-muted-foreground">
+      Backups: {result.blobsMatched} of {result.blobsScanned} cached torrent files match
+      {applied && `, ${result.blobsRewritten} rewritten (${result.itemsUpdated} backup items updated)`}
+      {result.blobsFailed > 0 && `, ${result.blobsFailed} failed`}
+    </p>
+  )
+}
+
+export function TrackerRotationPanel() {
+  const [spec, setSpec] = useState<TrackerRotationSpec>({ domain: "", find: "", replace: "", rewriteBackups: true })
+  const [preview, setPreview] = useState<TrackerRotationPreview | null>(null)
+  const [jobId, setJobId] = useState<number | null>(null)
+  const [confirmOpen, setConfirmOpen] = useState(false)
+
+  const updateSpec = (patch: Partial<TrackerRotationSpec>) => {
+    setSpec(prev => ({ ...prev, ...patch }))
+    setPreview(null)
+  }
+
+  const payload: TrackerRotationSpec = {
+    ...spec,
+    domain: spec.domain?.trim() || undefined,
+  }
+  const canSubmit = spec.find.trim() !== "" && spec.replace.trim() !== "" && spec.find !== spec.replace
+
+  const previewMutation = useMutation({
+    mutationFn: () => api.previewTrackerRotation(payload),
+    onSuccess: setPreview,
+    onError: (error: Error) => toast.error(`Failed to preview rotation: ${error.message}`),
+  })
+
+  const startMutation = useMutation({
+    mutationFn: () => api.startTrackerRotation(payload),
+    onSuccess: (job) => {
+      setJobId(job.id)
+      setPreview(null)
+      toast.success("Tracker rotation started")
+    },
+    onError: (error: Error) => toast.error(`Failed to start rotation: ${error.message}`),
+  })
+
+  const { data: job } = useQuery({
+    queryKey: ["tracker-rotation-job", jobId],
+    queryFn: () => api.getTrackerRotationJob(jobId!),
+    enabled: jobId !== null,
+    refetchInterval: (query) => query.state.data?.status === "running" ? 1000 : false,
+  })
+
+  const running = job?.status === "running"
+  const progressValue = job && job.progress.total > 0 ? (job.progress.processed / job.progress.total) * 100 : 0
+
+  return (
+    <div className="space-y-6">
+      <div className="grid gap-4 sm:grid-cols-3">
+        <div className="space-y-2">
+          <Label htmlFor="rotation-domain">Tracker domain</Label>
+          <Input
+            id="rotation-domain"
+            placeholder="tracker.example (optional)"
+            value={spec.domain ?? ""}
+            onChange={(e) => updateSpec({ domain: e.target.value })}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label htmlFor="rotation-find">Old URL or passkey</Label>
+          <Input
+            id="rotation-find"
+            value={spec.find}
+            onChange={(e) => updateSpec({ find: e.target.value })}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label htmlFor="rotation-replace">New URL or passkey</Label>
+          <Input
+            id="rotation-replace"
+            value={spec.replace}
+            onChange={(e) => updateSpec({ replace: e.target.value })}
+          />
+        </div>
+      </div>
+
+      <div className="flex items-center justify-between gap-4">
+        <div className="flex items-center gap-2">
+          <Switch
+            id="rotation-backups"
+            checked={spec.rewriteBackups}
+            onCheckedChange={(checked) => updateSpec({ rewriteBackups: checked })}
+          />
+          <Label htmlFor="rotation-backups">Also rewrite cached backup torrents</Label>
+        </div>
+        <div className="flex gap-2">
+          <Button
+            variant="outline"
+            disabled={!canSubmit || previewMutation.isPending}
+            onClick={() => previewMutation.mutate()}
+          >
+            {previewMutation.isPending ? <Loader2 className="h-4 w-4 mr-2 animate-spin" /> : <Search className="h-4 w-4 mr-2" />}
+            Preview
+          </Button>
+          <Button
+            disabled={!preview || preview.totalTorrents + (preview.backups?.blobsMatched ?? 0) === 0 || running || startMutation.isPending}
+            onClick={() => setConfirmOpen(true)}
+          >
+            Apply
+          </Button>
+        </div>
+      </div>
+
+      {preview && (
+        <div className="space-y-3">
+          <p className="text-sm">
+            {preview.totalTorrents} torrent{preview.totalTorrents === 1 ? "" : "s"} across {preview.instances.length} instance{preview.instances.length === 1 ? "" : "s"} would be updated.
+          </p>
+          {preview.backups && <BackupSummary result={preview.backups} applied={false} />}
+          {preview.instances.map((instance) => (
+            <div key={instance.instanceId} className="rounded-md border p-3 space-y-2">
+              <div className="flex items-center justify-between">
+                <span className="font-medium">{instance.instanceName}</span>
+                {instance.error ? (
+                  <Badge variant="destructive">{instance.error}</Badge>
+                ) : (
+                  <Badge variant="secondary">{instance.torrents?.length ?? 0} torrents</Badge>
+                )}
+              </div>
+              {instance.torrents && instance.torrents.length > 0 && (
+                <ul className="text-xs text-muted-foreground space-y-1">
+                  {instance.torrents.slice(0, PREVIEW_SAMPLE_SIZE).map((torrent) => (
+                    <li key={torrent.hash} className="truncate">
+                      <span className="text-foreground">{torrent.name || torrent.hash}</span>
+                      {" — "}
+                      {torrent.trackers.map((change) => change.newUrl).join(", ")}
+                    </li>
+                  ))}
+                  {instance.torrents.length > PREVIEW_SAMPLE_SIZE && (
+                    <li>…and {instance.torrents.length - PREVIEW_SAMPLE_SIZE} more</li>
+                  )}
+                </ul>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+
+      {job && (
+        <div className="space-y-3">
+          <div className="flex items-center justify-between text-sm">
+            <span>
+              {running ? `Updating ${job.progress.phase}: ${job.progress.processed} / ${job.progress.total}` : `Rotation ${job.status}`}
+            </span>
+            <Badge variant={job.status === "failed" ? "destructive" : job.status === "completed" ? "default" : "secondary"}>
+              {job.status}
+            </Badge>
+          </div>
+          {running && <Progress value={progressValue} className="h-2" />}
+          {job.error && <p className="text-sm text-destructive">{job.error}</p>}
+          {job.instances.length > 0 && (
+            <Table>
+              <TableHeader>
+                <TableRow>
+                  <TableHead>Instance</TableHead>
+                  <TableHead className="text-right">Matched</TableHead>
+                  <TableHead className="text-right">Updated</TableHead>
+                  <TableHead className="text-right">Failed</TableHead>
+                </TableRow>
+              </TableHeader>
+              <TableBody>
+                {job.instances.map((result) => (
+                  <TableRow key={result.instanceId}>
+                    <TableCell>
+                      {result.instanceName}
+                      {result.error && <p className="text-xs text-destructive">{result.error}</p>}
+                    </TableCell>
+                    <TableCell className="text-right">{result.matched}</TableCell>
+                    <TableCell className="text-right">{result.updated}</TableCell>
+                    <TableCell className="text-right">{result.failed}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          )}
+          {job.backups && <BackupSummary result={job.backups} applied />}
+        </div>
+      )}
+
+      <AlertDialog open={confirmOpen} onOpenChange={setConfirmOpen}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>Apply tracker rotation?</AlertDialogTitle>
+            <AlertDialogDescription>
+              Tracker URLs will be edited on {preview?.totalTorrents ?? 0} torrents across all active instances
+              {spec.rewriteBackups && " and in matching cached backup torrents"}. This cannot be undone automatically.
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>Cancel</AlertDialogCancel>
+            <AlertDialogAction onClick={() => startMutation.mutate()}>Apply</AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+    </div>
+  )
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { api } from "@/lib/api"
+import { copyTextToClipboard } from "@/lib/utils"
+import type { TwoFactorEnrollment } from "@/types"
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
+import { Copy } from "lucide-react"
+import { useState } from "react"
+import { toast } from "sonner"
+
+export function TwoFactorSettings() {
+  const queryClient = useQueryClient()
+  const [enrollment, setEnrollment] = useState<TwoFactorEnrollment | null>(null)
+  const [recoveryCodes, setRecoveryCodes] = useState<string[] | null>(null)
+  const [code, setCode] = useState("")
+
+  const { data: status, isLoading } = useQuery({
+    queryKey: ["auth", "2fa"],
+    queryFn: () => api.getTwoFactorStatus(),
+  })
+
+  const refresh = () => {
+    setCode("")
+    queryClient.invalidateQueries({ queryKey: ["auth", "2fa"] })
+    queryClient.invalidateQueries({ queryKey: ["auth", "user"] })
+  }
+
+  const setupMutation = useMutation({
+    mutationFn: () => api.setupTwoFactor(),
+    onSuccess: (data) => {
+      setRecoveryCodes(null)
+      setEnrollment(data)
+    },
+    onError: (error) => toast.error(error.message || "Failed to start two-factor setup"),
+  })
+
+  const enableMutation = useMutation({
+    mutationFn: (value: string) => api.enableTwoFactor(value),
+    onSuccess: (data) => {
+      setEnrollment(null)
+      setRecoveryCodes(data.recoveryCodes)
+      toast.success("Two-factor authentication enabled")
+      refresh()
+    },
+    onError: (error) => toast.error(error.message || "Invalid two-factor code"),
+  })
+
+  const disableMutation = useMutation({
+    mutationFn: (value: string) => api.disableTwoFactor(value),
+    onSuccess: () => {
+      setRecoveryCodes(null)
+      toast.success("Two-factor authentication disabled")
+      refresh()
+    },
+    onError: (error) => toast.error(error.message || "Invalid two-factor code"),
+  })
+
+  const regenerateMutation = useMutation({
+    mutationFn: (value: string) => api.regenerateRecoveryCodes(value),
+    onSuccess: (data) => {
+      setRecoveryCodes(data.recoveryCodes)
+      refresh()
+    },
+    onError: (error) => toast.error(error.message || "Invalid two-factor code"),
+  })
+
+  if (isLoading || !status) {
+    return <p className="text-sm text-muted-foreground">Loading...</p>
+  }
+
+  const codeInput = (
+    <div className="space-y-2">
+      <Label htmlFor="twoFactorSettingsCode">Authenticator or recovery code</Label>
+      <Input
+        id="twoFactorSettingsCode"
+        autoComplete="one-time-code"
+        value={code}
+        onChange={(e) => setCode(e.target.value)}
+        placeholder="123456"
+      />
+    </div>
+  )
+
+  return (
+    <div className="space-y-4">
+      <div className="flex items-center gap-2">
+        <span className="text-sm">Status:</span>
+        <Badge variant={status.enabled ? "default" : "outline"}>
+          {status.enabled ? "Enabled" : "Disabled"}
+        </Badge>
+        {status.enabled && (
+          <span className="text-sm text-muted-foreground">
+            {status.recoveryCodesRemaining} recovery codes left
+          </span>
+        )}
+      </div>
+
+      {recoveryCodes && (
+        <div className="space-y-2 rounded-lg border bg-muted/40 p-4">
+          <p className="text-sm font-medium">
+            Save these recovery codes somewhere safe. Each works once and they won't be shown again.
+          </p>
+          <pre className="font-mono text-sm">{recoveryCodes.join("\n")}</pre>
+          <Button
+            size="sm"
+            variant="outline"
+            onClick={async () => {
+              await copyTextToClipboard(recoveryCodes.join("\n"))
+              toast.success("Recovery codes copied to clipboard")
+            }}
+          >
+            <Copy className="mr-2 h-4 w-4" />
+            Copy
+          </Button>
+        </div>
+      )}
+
+      {!status.enabled && !enrollment && (
+        <Button onClick={() => setupMutation.mutate()} disabled={setupMutation.isPending}>
+          Set up two-factor authentication
+        </Button>
+      )}
+
+      {!status.enabled && enrollment && (
+        <div className="space-y-4">
+          <p className="text-sm text-muted-foreground">
+            Scan the QR code with your authenticator app, or enter the secret manually, then confirm with a code.
+          </p>
+          <img src={enrollment.qrCode} alt="Two-factor QR code" className="h-48 w-48 rounded bg-white p-2" />
+          <code className="block break-all text-sm">{enrollment.secret}</code>
+          {codeInput}
+          <Button
+            onClick={() => enableMutation.mutate(code.trim())}
+            disabled={!code.trim() || enableMutation.isPending}
+          >
+            Enable
+          </Button>
+        </div>
+      )}
+
+      {status.enabled && (
+        <div className="space-y-4">
+          {codeInput}
+          <div className="flex gap-2">
+            <Button
+              variant="outline"
+              onClick={() => regenerateMutation.mutate(code.trim())}
+              disabled={!code.trim() || regenerateMutation.isPending}
+            >
+              New recovery codes
+            </Button>
+            <Button
+              variant="destructive"
+              onClick={() => disableMutation.mutate(code.trim())}
+              disabled={!code.trim() || disableMutation.isPending}
+            >
+              Disable
+            </Button>
+          </div>
+        </div>
+      )}
+    </div>
+  )
+}
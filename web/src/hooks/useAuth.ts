@@ -7,6 +7,7 @@ import { api } from "@/lib/api"
 import type { User } from "@/types"
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
 import { useNavigate } from "@tanstack/react-router"
+import { useState } from "react"
 
 export function useAuth() {
   const navigate = useNavigate()
@@ -19,15 +20,37 @@ export function useAuth() {
     staleTime: Infinity,
   })
 
+  // Set after a correct password for accounts with two-factor enabled
+  const [twoFactorRequired, setTwoFactorRequired] = useState(false)
+
   const loginMutation = useMutation({
     mutationFn: ({ username, password, rememberMe = false }: { username: string; password: string; rememberMe?: boolean }) =>
       api.login(username, password, rememberMe),
     onSuccess: (data) => {
+      if (data.twoFactorRequired) {
+        setTwoFactorRequired(true)
+        return
+      }
       queryClient.setQueryData(["auth", "user"], data.user)
       navigate({ to: "/dashboard" })
     },
   })
 
+  const twoFactorMutation = useMutation({
+    mutationFn: (code: string) => api.loginTwoFactor(code),
+    onSuccess: () => {
+      setTwoFactorRequired(false)
+      queryClient.invalidateQueries({ queryKey: ["auth", "user"] })
+      navigate({ to: "/dashboard" })
+    },
+    onError: (error) => {
+      // Expired or exhausted logins have to start over with the password
+      if (error.message?.includes("sign in again")) {
+        setTwoFactorRequired(false)
+      }
+    },
+  })
+
   const setupMutation = useMutation({
     mutationFn: ({ username, password }: { username: string; password: string }) =>
       api.setup(username, password),
@@ -61,6 +84,11 @@ export function useAuth() {
     isLoading,
     error,
     login: loginMutation.mutate,
+    twoFactorRequired,
+    verifyTwoFactor: twoFactorMutation.mutate,
+    isVerifyingTwoFactor: twoFactorMutation.isPending,
+    twoFactorError: twoFactorMutation.error,
+    cancelTwoFactor: () => setTwoFactorRequired(false),
     setup: setupMutation.mutate,
     logout: logoutMutation.mutate,
     isLoggingIn: loginMutation.isPending,
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useQuery, useQueryClient } from "@tanstack/react-query"
+import { useCallback } from "react"
+import { toast } from "sonner"
+
+import { api } from "@/lib/api"
+import type { BulkJob } from "@/types"
+
+// Selections at or above this size run as background jobs instead of a single request.
+export const BULK_JOB_THRESHOLD = 1000
+
+const POLL_INTERVAL_MS = 1_000
+
+export function useBulkJobs(instanceId: number, options?: { enabled?: boolean }) {
+  const shouldEnable = (options?.enabled ?? true) && instanceId > 0
+
+  return useQuery({
+    queryKey: ["bulk-jobs", instanceId],
+    queryFn: () => api.listBulkJobs(instanceId),
+    enabled: shouldEnable,
+    refetchInterval: (query) => {
+      const jobs = query.state.data as BulkJob[] | undefined
+      return jobs?.some((job) => job.status === "running") ? 2_000 : false
+    },
+  })
+}
+
+function describeProgress(job: BulkJob): string {
+  const { processed, total } = job.progress
+  const failed = job.failed > 0 ? `, ${job.failed} failed` : ""
+  return `${processed} of ${total} processed${failed}`
+}
+
+/**
+ * Returns a function that follows a submitted job in a toast until it finishes,
+ * offering cancel while it runs and undo once it is done.
+ */
+export function useTrackBulkJob(instanceId: number) {
+  const queryClient = useQueryClient()
+
+  const refreshTorrents = useCallback(() => {
+    queryClient.refetchQueries({ queryKey: ["torrents-list", instanceId], exact: false, type: "active" })
+    queryClient.refetchQueries({ queryKey: ["torrent-counts", instanceId], exact: false, type: "active" })
+    queryClient.invalidateQueries({ queryKey: ["bulk-jobs", instanceId] })
+  }, [instanceId, queryClient])
+
+  const track = useCallback((job: BulkJob, label: string) => {
+    const toastId = `bulk-job-${instanceId}-${job.id}`
+
+    const showFinished = (finished: BulkJob) => {
+      refreshTorrents()
+      const summary = `${label}: ${finished.succeeded} updated${finished.failed > 0 ? `, ${finished.failed} failed` : ""}`
+      const firstError = finished.errors?.[0]
+      const options = {
+        id: toastId,
+        description: firstError ? `${firstError.hash.slice(0, 8)}: ${firstError.error}` : undefined,
+        duration: 10_000,
+        action: finished.undoable ? {
+          label: "Undo",
+          onClick: () => {
+            api.undoBulkJob(instanceId, finished.id)
+              .then((undoJob) => track(undoJob, "Undo"))
+              .catch((error: Error) => toast.error("Failed to undo", { description: error.message }))
+          },
+        } : undefined,
+      }
+
+      if (finished.status === "canceled") {
+        toast.warning(`${label} canceled after ${finished.progress.processed} of ${finished.progress.total}`, options)
+      } else if (finished.status === "failed") {
+        toast.error(summary, options)
+      } else if (finished.failed > 0) {
+        toast.warning(summary, options)
+      } else {
+        toast.success(summary, options)
+      }
+    }
+
+    const poll = async () => {
+      let current: BulkJob
+      try {
+        current = await api.getBulkJob(instanceId, job.id)
+      } catch (error) {
+        toast.error(`${label}: lost track of background job`, {
+          id: toastId,
+          description: error instanceof Error ? error.message : undefined,
+        })
+        return
+      }
+
+      if (current.status !== "running") {
+        showFinished(current)
+        return
+      }
+
+      toast.loading(label, {
+        id: toastId,
+        description: describeProgress(current),
+        action: {
+          label: "Cancel",
+          onClick: () => {
+            api.cancelBulkJob(instanceId, current.id).catch((error: Error) => {
+              toast.error("Failed to cancel", { description: error.message })
+            })
+          },
+        },
+      })
+      setTimeout(poll, POLL_INTERVAL_MS)
+    }
+
+    toast.loading(label, { id: toastId, description: describeProgress(job) })
+    setTimeout(poll, POLL_INTERVAL_MS)
+  }, [instanceId, refreshTorrents])
+
+  return track
+}
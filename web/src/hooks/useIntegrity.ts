@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2025, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
+
+import { api } from "@/lib/api"
+import type { IntegrityCheck, IntegritySettings, IntegritySettingsUpdate } from "@/types"
+
+export function useIntegritySettings(instanceId: number, options?: { enabled?: boolean }) {
+  const shouldEnable = (options?.enabled ?? true) && instanceId > 0
+
+  return useQuery({
+    queryKey: ["integrity", instanceId, "settings"],
+    queryFn: () => api.getIntegritySettings(instanceId),
+    enabled: shouldEnable,
+    staleTime: 30_000,
+  })
+}
+
+export function useUpdateIntegritySettings(instanceId: number) {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: (data: IntegritySettingsUpdate) => api.updateIntegritySettings(instanceId, data),
+    onSuccess: (settings: IntegritySettings) => {
+      queryClient.setQueryData<IntegritySettings>(["integrity", instanceId, "settings"], settings)
+    },
+  })
+}
+
+export function useIntegrityChecks(
+  instanceId: number,
+  options?: { limit?: number; enabled?: boolean }
+) {
+  const limit = options?.limit
+  const shouldEnable = (options?.enabled ?? true) && instanceId > 0
+
+  return useQuery({
+    queryKey: ["integrity", instanceId, "checks", limit ?? null],
+    queryFn: () =>
+      api.listIntegrityChecks(instanceId, {
+        ...(limit !== undefined ? { limit } : {}),
+      }),
+    enabled: shouldEnable,
+    refetchInterval: (query) => {
+      const checks = query.state.data as IntegrityCheck[] | undefined
+      if (!checks) {
+        return 5_000
+      }
+      return checks.some((check) => check.status === "running") ? 2_000 : 30_000
+    },
+  })
+}
+
+export function useTriggerIntegrityCheck(instanceId: number) {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: (hash: string) => api.triggerIntegrityCheck(instanceId, hash),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ["integrity", instanceId, "checks"] })
+    },
+  })
+}
+
+export function useCancelIntegrityCheck(instanceId: number) {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: (checkId: number) => api.cancelIntegrityCheck(instanceId, checkId),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ["integrity", instanceId, "checks"] })
+    },
+  })
+}
@@ -134,3 +134,28 @@ export function useCancelOrphanScanRun(instanceId: number) {
     },
   })
 }
+
+export function useRestoreOrphanScanRun(instanceId: number) {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: (runId: number) => api.restoreOrphanScanRun(instanceId, runId),
+    onSuccess: (_data, runId) => {
+      queryClient.invalidateQueries({ queryKey: ["orphan-scan", instanceId, "runs"] })
+      queryClient.invalidateQueries({ queryKey: ["orphan-scan", instanceId, "run", runId] })
+    },
+  })
+}
+
+export function useRestoreOrphanScanFile(instanceId: number) {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: ({ runId, fileId }: { runId: number; fileId: number }) =>
+      api.restoreOrphanScanFile(instanceId, runId, fileId),
+    onSuccess: (_data, { runId }) => {
+      queryClient.invalidateQueries({ queryKey: ["orphan-scan", instanceId, "runs"] })
+      queryClient.invalidateQueries({ queryKey: ["orphan-scan", instanceId, "run", runId] })
+    },
+  })
+}
@@ -3,9 +3,10 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+import { BULK_JOB_THRESHOLD, useTrackBulkJob } from "@/hooks/useBulkJobs"
 import { usePersistedDeleteFiles } from "@/hooks/usePersistedDeleteFiles"
 import { api } from "@/lib/api"
-import type { Torrent, TorrentFilters } from "@/types"
+import type { BulkActionRequest, BulkJob, Torrent, TorrentFilters } from "@/types"
 import { useMutation, useQueryClient } from "@tanstack/react-query"
 import { useCallback, useState } from "react"
 import { toast } from "sonner"
@@ -77,6 +78,7 @@ interface ClientMeta {
 
 export function useTorrentActions({ instanceId, onActionComplete }: UseTorrentActionsProps) {
   const queryClient = useQueryClient()
+  const trackBulkJob = useTrackBulkJob(instanceId)
 
   // Dialog states
   const [showDeleteDialog, setShowDeleteDialog] = useState(false)
@@ -109,7 +111,7 @@ export function useTorrentActions({ instanceId, onActionComplete }: UseTorrentAc
   const [contextTorrents, setContextTorrents] = useState<Torrent[]>([])
 
   const mutation = useMutation({
-    mutationFn: (data: TorrentActionData) => {
+    mutationFn: (data: TorrentActionData): Promise<BulkJob | void> => {
       const { clientHashes, clientCount, ...payload } = data
       void clientHashes
       void clientCount
@@ -119,7 +121,7 @@ export function useTorrentActions({ instanceId, onActionComplete }: UseTorrentAc
         excludeCategories: payload.filters.expandedExcludeCategories ?? payload.filters.excludeCategories ?? [],
       }: undefined
 
-      return api.bulkAction(instanceId, {
+      const request: BulkActionRequest = {
         hashes: payload.hashes,
         action: payload.action,
         deleteFiles: payload.deleteFiles,
@@ -136,9 +138,15 @@ export function useTorrentActions({ instanceId, onActionComplete }: UseTorrentAc
         filters: effectiveFilters,
         search: payload.search,
         excludeHashes: payload.excludeHashes,
-      })
+      }
+
+      // Large selections run in the background so they can report progress and be undone
+      if (payload.selectAll || payload.hashes.length >= BULK_JOB_THRESHOLD) {
+        return api.submitBulkJob(instanceId, request)
+      }
+      return api.bulkAction(instanceId, request)
     },
-    onSuccess: async (_, variables) => {
+    onSuccess: async (job, variables) => {
       // Handle delete operations with optimistic updates
       if (variables.action === "delete") {
         // Clear selection and context
@@ -209,15 +217,19 @@ export function useTorrentActions({ instanceId, onActionComplete }: UseTorrentAc
         setContextTorrents([])
       }
 
-      // Show success toast
-      let toastCount = variables.hashes.length
-      if (variables.clientHashes && variables.clientHashes.length > 0) {
-        toastCount = variables.clientHashes.length
-      }
-      if (typeof variables.clientCount === "number") {
-        toastCount = variables.clientCount
+      // Show success toast, or follow the background job until it finishes
+      if (job) {
+        trackBulkJob(job, `Bulk ${variables.action}`)
+      } else {
+        let toastCount = variables.hashes.length
+        if (variables.clientHashes && variables.clientHashes.length > 0) {
+          toastCount = variables.clientHashes.length
+        }
+        if (typeof variables.clientCount === "number") {
+          toastCount = variables.clientCount
+        }
+        showSuccessToast(variables.action, Math.max(1, toastCount), variables.deleteFiles, variables.enable)
       }
-      showSuccessToast(variables.action, Math.max(1, toastCount), variables.deleteFiles, variables.enable)
 
       // Close dialogs after successful action
       if (variables.action === "delete") {
@@ -5,6 +5,8 @@
 
 import type {
   AddTorrentResponse,
+  ApiKeyOptions,
+  ApiKeyScope,
   AppPreferences,
   AsyncIndexerFilteringState,
   AuthResponse,
@@ -17,7 +19,13 @@ import type {
   BackupRun,
   BackupRunsResponse,
   BackupSettings,
+  BulkActionRequest,
+  BulkJob,
   Category,
+  CategorySyncDrift,
+  CategorySyncReconcileResult,
+  CategorySyncTarget,
+  CategoryTemplates,
   CrossInstanceTorrent,
   CrossSeedApplyResponse,
   CrossSeedAutomationSettings,
@@ -35,6 +43,8 @@ import type {
   DashboardSettings,
   DashboardSettingsInput,
   DiscoverJackettResponse,
+  DuplicateHardlinkResult,
+  DuplicateReport,
   DuplicateTorrentMatch,
   ExternalProgram,
   ExternalProgramCreate,
@@ -46,14 +56,19 @@ import type {
   InstanceCapabilities,
   InstanceCrossSeedCompletionSettings,
   InstanceFormData,
+  IntegrityCheck,
+  IntegritySettings,
+  IntegritySettingsUpdate,
   LocalCrossSeedMatch,
   InstanceReannounceActivity,
+  InstanceReannounceTrackerStats,
   InstanceReannounceCandidate,
   InstanceResponse,
   LogExclusions,
   LogExclusionsInput,
   LogSettings,
   LogSettingsUpdate,
+  OrphanScanRestoreResult,
   OrphanScanRun,
   OrphanScanRunWithFiles,
   OrphanScanSettings,
@@ -64,6 +79,7 @@ import type {
   RestorePlan,
   RestoreResult,
   SearchHistoryResponse,
+  Session,
   SortedPeersResponse,
   TorrentCreationParams,
   TorrentCreationTask,
@@ -86,6 +102,11 @@ import type {
   TorznabSearchResult,
   TrackerCustomization,
   TrackerCustomizationInput,
+  TrackerRotationJob,
+  TrackerRotationPreview,
+  TrackerRotationSpec,
+  TwoFactorEnrollment,
+  TwoFactorStatus,
   User,
   WebSeed
 } from "@/types"
@@ -251,6 +272,13 @@ class ApiClient {
     })
   }
 
+  async loginTwoFactor(code: string): Promise<AuthResponse> {
+    return this.request<AuthResponse>("/auth/login/2fa", {
+      method: "POST",
+      body: JSON.stringify({ code }),
+    })
+  }
+
   async logout(): Promise<void> {
     return this.request("/auth/logout", { method: "POST" })
   }
@@ -284,6 +312,17 @@ class ApiClient {
     }
   }
 
+  // Starts an OIDC login that links the identity to the signed-in account
+  async getOIDCLinkConfig(): Promise<{
+    enabled: boolean
+    authorizationUrl: string
+    state: string
+    disableBuiltInLogin: boolean
+    issuerUrl: string
+  }> {
+    return this.request("/auth/oidc/link")
+  }
+
   // Instance endpoints
   async getInstances(): Promise<InstanceResponse[]> {
     return this.request<InstanceResponse[]>("/instances")
@@ -336,6 +375,14 @@ class ApiClient {
     return this.request<InstanceReannounceActivity[]>(`/instances/${instanceId}/reannounce/activity${query}`)
   }
 
+  async getInstanceReannounceStats(
+    instanceId: number,
+    days?: number
+  ): Promise<InstanceReannounceTrackerStats[]> {
+    const query = typeof days === "number" ? `?days=${days}` : ""
+    return this.request<InstanceReannounceTrackerStats[]>(`/instances/${instanceId}/reannounce/stats${query}`)
+  }
+
   async getInstanceReannounceCandidates(
     instanceId: number
   ): Promise<InstanceReannounceCandidate[]> {
@@ -656,36 +703,40 @@ class ApiClient {
   }
 
 
-  async bulkAction(
-    instanceId: number,
-    data: {
-      hashes: string[]
-      action: "pause" | "resume" | "delete" | "recheck" | "reannounce" | "increasePriority" | "decreasePriority" | "topPriority" | "bottomPriority" | "setCategory" | "addTags" | "removeTags" | "setTags" | "toggleAutoTMM" | "forceStart" | "setShareLimit" | "setUploadLimit" | "setDownloadLimit" | "setLocation" | "editTrackers" | "addTrackers" | "removeTrackers" | "toggleSequentialDownload"
-      deleteFiles?: boolean
-      category?: string
-      tags?: string  // Comma-separated tags string
-      enable?: boolean  // For toggleAutoTMM
-      selectAll?: boolean  // When true, apply to all torrents matching filters
-      filters?: TorrentFilters
-      search?: string  // Search query when selectAll is true
-      excludeHashes?: string[]  // Hashes to exclude when selectAll is true
-      ratioLimit?: number  // For setShareLimit action
-      seedingTimeLimit?: number  // For setShareLimit action (minutes)
-      inactiveSeedingTimeLimit?: number  // For setShareLimit action (minutes)
-      uploadLimit?: number  // For setUploadLimit action (KB/s)
-      downloadLimit?: number  // For setDownloadLimit action (KB/s)
-      location?: string  // For setLocation action
-      trackerOldURL?: string  // For editTrackers action
-      trackerNewURL?: string  // For editTrackers action
-      trackerURLs?: string  // For addTrackers/removeTrackers actions (newline-separated)
-    }
-  ): Promise<void> {
+  async bulkAction(instanceId: number, data: BulkActionRequest): Promise<void> {
     return this.request(`/instances/${instanceId}/torrents/bulk-action`, {
       method: "POST",
       body: JSON.stringify(data),
     })
   }
 
+  async submitBulkJob(instanceId: number, data: BulkActionRequest): Promise<BulkJob> {
+    return this.request<BulkJob>(`/instances/${instanceId}/torrents/bulk-jobs`, {
+      method: "POST",
+      body: JSON.stringify(data),
+    })
+  }
+
+  async listBulkJobs(instanceId: number): Promise<BulkJob[]> {
+    return this.request<BulkJob[]>(`/instances/${instanceId}/torrents/bulk-jobs`)
+  }
+
+  async getBulkJob(instanceId: number, jobId: number): Promise<BulkJob> {
+    return this.request<BulkJob>(`/instances/${instanceId}/torrents/bulk-jobs/${jobId}`)
+  }
+
+  async cancelBulkJob(instanceId: number, jobId: number): Promise<void> {
+    return this.request(`/instances/${instanceId}/torrents/bulk-jobs/${jobId}`, {
+      method: "DELETE",
+    })
+  }
+
+  async undoBulkJob(instanceId: number, jobId: number): Promise<BulkJob> {
+    return this.request<BulkJob>(`/instances/${instanceId}/torrents/bulk-jobs/${jobId}/undo`, {
+      method: "POST",
+    })
+  }
+
   async analyzeTorrentForCrossSeedSearch(
     instanceId: number,
     hash: string
@@ -1422,6 +1473,49 @@ class ApiClient {
     })
   }
 
+  // Sessions
+  async getSessions(): Promise<Session[]> {
+    return this.request("/auth/sessions")
+  }
+
+  async revokeSession(id: string): Promise<void> {
+    return this.request(`/auth/sessions/${encodeURIComponent(id)}`, { method: "DELETE" })
+  }
+
+  async revokeAllSessions(keepCurrent: boolean): Promise<{ revoked: number }> {
+    return this.request(`/auth/sessions${keepCurrent ? "?others=true" : ""}`, { method: "DELETE" })
+  }
+
+  // Two-factor authentication
+  async getTwoFactorStatus(): Promise<TwoFactorStatus> {
+    return this.request("/auth/2fa")
+  }
+
+  async setupTwoFactor(): Promise<TwoFactorEnrollment> {
+    return this.request("/auth/2fa/setup", { method: "POST" })
+  }
+
+  async enableTwoFactor(code: string): Promise<{ recoveryCodes: string[] }> {
+    return this.request("/auth/2fa/enable", {
+      method: "POST",
+      body: JSON.stringify({ code }),
+    })
+  }
+
+  async disableTwoFactor(code: string): Promise<void> {
+    return this.request("/auth/2fa/disable", {
+      method: "POST",
+      body: JSON.stringify({ code }),
+    })
+  }
+
+  async regenerateRecoveryCodes(code: string): Promise<{ recoveryCodes: string[] }> {
+    return this.request("/auth/2fa/recovery-codes", {
+      method: "POST",
+      body: JSON.stringify({ code }),
+    })
+  }
+
   // API Key endpoints
   async getApiKeys(): Promise<{
     id: number
@@ -1429,14 +1523,19 @@ class ApiClient {
     key?: string
     createdAt: string
     lastUsedAt?: string
+    lastUsedIp?: string
+    scopes: ApiKeyScope[]
+    instanceIds: number[]
+    allowedIps: string[]
+    expiresAt?: string | null
   }[]> {
     return this.request("/api-keys")
   }
 
-  async createApiKey(name: string): Promise<{ id: number; key: string; name: string }> {
+  async createApiKey(name: string, options: ApiKeyOptions = {}): Promise<{ id: number; key: string; name: string }> {
     return this.request("/api-keys", {
       method: "POST",
-      body: JSON.stringify({ name }),
+      body: JSON.stringify({ name, ...options }),
     })
   }
 
@@ -1623,6 +1722,85 @@ class ApiClient {
     })
   }
 
+  // Tracker rotation endpoints
+  async previewTrackerRotation(spec: TrackerRotationSpec): Promise<TrackerRotationPreview> {
+    return this.request<TrackerRotationPreview>("/tracker-rotation/preview", {
+      method: "POST",
+      body: JSON.stringify(spec),
+    })
+  }
+
+  async startTrackerRotation(spec: TrackerRotationSpec): Promise<TrackerRotationJob> {
+    return this.request<TrackerRotationJob>("/tracker-rotation/jobs", {
+      method: "POST",
+      body: JSON.stringify(spec),
+    })
+  }
+
+  async getTrackerRotationJob(jobId: number): Promise<TrackerRotationJob> {
+    return this.request<TrackerRotationJob>(`/tracker-rotation/jobs/${jobId}`)
+  }
+
+  // Category sync endpoints
+  async getCategoryTemplates(): Promise<CategoryTemplates> {
+    return this.request<CategoryTemplates>("/category-sync/templates")
+  }
+
+  async updateCategoryTemplates(templates: Pick<CategoryTemplates, "tags"> & {
+    categories: { name: string; savePath: string }[]
+  }): Promise<CategoryTemplates> {
+    return this.request<CategoryTemplates>("/category-sync/templates", {
+      method: "PUT",
+      body: JSON.stringify(templates),
+    })
+  }
+
+  async getCategorySyncTargets(): Promise<CategorySyncTarget[]> {
+    return this.request<CategorySyncTarget[]>("/category-sync/targets")
+  }
+
+  async updateCategorySyncTargets(targets: { instanceId: number; savePathPrefix: string }[]): Promise<CategorySyncTarget[]> {
+    return this.request<CategorySyncTarget[]>("/category-sync/targets", {
+      method: "PUT",
+      body: JSON.stringify({ targets }),
+    })
+  }
+
+  async getCategorySyncDrift(): Promise<CategorySyncDrift[]> {
+    return this.request<CategorySyncDrift[]>("/category-sync/drift")
+  }
+
+  async reconcileCategorySync(instanceIds: number[], removeExtra: boolean): Promise<CategorySyncReconcileResult[]> {
+    return this.request<CategorySyncReconcileResult[]>("/category-sync/reconcile", {
+      method: "POST",
+      body: JSON.stringify({ instanceIds, removeExtra }),
+    })
+  }
+
+  // Duplicate finder endpoints
+  async getDuplicateReport(): Promise<DuplicateReport> {
+    return this.request<DuplicateReport>("/duplicates")
+  }
+
+  async deleteDuplicate(instanceId: number, hash: string, deleteFiles: boolean): Promise<void> {
+    return this.request<void>("/duplicates/delete", {
+      method: "POST",
+      body: JSON.stringify({ instanceId, hash, deleteFiles }),
+    })
+  }
+
+  async hardlinkDuplicate(
+    instanceId: number,
+    hash: string,
+    sourceInstanceId: number,
+    sourceHash: string
+  ): Promise<DuplicateHardlinkResult> {
+    return this.request<DuplicateHardlinkResult>("/duplicates/hardlink", {
+      method: "POST",
+      body: JSON.stringify({ instanceId, hash, sourceInstanceId, sourceHash }),
+    })
+  }
+
   // Tracker Customization endpoints
   async listTrackerCustomizations(): Promise<TrackerCustomization[]> {
     return this.request<TrackerCustomization[]>("/tracker-customizations")
@@ -1884,6 +2062,27 @@ class ApiClient {
     )
   }
 
+  async restoreOrphanScanRun(
+    instanceId: number,
+    runId: number
+  ): Promise<OrphanScanRestoreResult> {
+    return this.request<OrphanScanRestoreResult>(
+      `/instances/${instanceId}/orphan-scan/runs/${runId}/restore`,
+      { method: "POST" }
+    )
+  }
+
+  async restoreOrphanScanFile(
+    instanceId: number,
+    runId: number,
+    fileId: number
+  ): Promise<{ status: string }> {
+    return this.request<{ status: string }>(
+      `/instances/${instanceId}/orphan-scan/runs/${runId}/files/${fileId}/restore`,
+      { method: "POST" }
+    )
+  }
+
   async cancelOrphanScanRun(
     instanceId: number,
     runId: number
@@ -1894,6 +2093,48 @@ class ApiClient {
     )
   }
 
+  // Integrity check endpoints
+  async getIntegritySettings(instanceId: number): Promise<IntegritySettings> {
+    return this.request<IntegritySettings>(`/instances/${instanceId}/integrity/settings`)
+  }
+
+  async updateIntegritySettings(
+    instanceId: number,
+    payload: IntegritySettingsUpdate
+  ): Promise<IntegritySettings> {
+    return this.request<IntegritySettings>(`/instances/${instanceId}/integrity/settings`, {
+      method: "PUT",
+      body: JSON.stringify(payload),
+    })
+  }
+
+  async listIntegrityChecks(instanceId: number, params?: { limit?: number }): Promise<IntegrityCheck[]> {
+    const search = new URLSearchParams()
+    if (params?.limit !== undefined) search.set("limit", params.limit.toString())
+
+    const query = search.toString()
+    const suffix = query ? `?${query}` : ""
+    return this.request<IntegrityCheck[]>(`/instances/${instanceId}/integrity/checks${suffix}`)
+  }
+
+  async triggerIntegrityCheck(instanceId: number, hash: string): Promise<{ checkId: number }> {
+    return this.request<{ checkId: number }>(`/instances/${instanceId}/integrity/checks`, {
+      method: "POST",
+      body: JSON.stringify({ hash }),
+    })
+  }
+
+  async getIntegrityCheck(instanceId: number, checkId: number): Promise<IntegrityCheck> {
+    return this.request<IntegrityCheck>(`/instances/${instanceId}/integrity/checks/${checkId}`)
+  }
+
+  async cancelIntegrityCheck(instanceId: number, checkId: number): Promise<{ status: string }> {
+    return this.request<{ status: string }>(
+      `/instances/${instanceId}/integrity/checks/${checkId}`,
+      { method: "DELETE" }
+    )
+  }
+
   // ARR Instance endpoints
   async listArrInstances(): Promise<ArrInstance[]> {
     return this.request<ArrInstance[]>("/arr/instances")
@@ -3,6 +3,7 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+import { IntegrityOverview } from "@/components/instances/preferences/IntegrityOverview"
 import { OrphanScanOverview } from "@/components/instances/preferences/OrphanScanOverview"
 import { OrphanScanSettingsDialog } from "@/components/instances/preferences/OrphanScanSettingsDialog"
 import { ReannounceOverview } from "@/components/instances/preferences/ReannounceOverview"
@@ -48,7 +49,7 @@ export function Automations() {
         </div>
       </div>
 
-      {/* Workflows full width, then Reannounce + Orphan Scan side by side */}
+      {/* Workflows full width, then Reannounce + Orphan Scan side by side, then Integrity */}
       <div className="space-y-6">
         <WorkflowsOverview
           expandedInstances={getExpandedForCard('workflows')}
@@ -66,6 +67,10 @@ export function Automations() {
             onConfigureInstance={setConfigureOrphanScanId}
           />
         </div>
+        <IntegrityOverview
+          expandedInstances={getExpandedForCard('integrity')}
+          onExpandedInstancesChange={handleAccordionChange('integrity')}
+        />
       </div>
 
       {instances && instances.length === 0 && (
@@ -19,12 +19,23 @@ import { useQuery } from "@tanstack/react-query"
 import { useForm } from "@tanstack/react-form"
 import { useNavigate } from "@tanstack/react-router"
 import { Fingerprint } from "lucide-react"
-import { useEffect } from "react"
+import { useEffect, useState } from "react"
 import { toast } from "sonner"
 
 export function Login() {
   const navigate = useNavigate()
-  const { login, isLoggingIn, loginError, setIsAuthenticated } = useAuth()
+  const {
+    login,
+    isLoggingIn,
+    loginError,
+    setIsAuthenticated,
+    twoFactorRequired,
+    verifyTwoFactor,
+    isVerifyingTwoFactor,
+    twoFactorError,
+    cancelTwoFactor,
+  } = useAuth()
+  const [twoFactorCode, setTwoFactorCode] = useState("")
 
   // Query to check if setup is required
   const { data: setupRequired } = useQuery({
@@ -116,7 +127,57 @@ export function Login() {
             </CardDescription>
           </CardHeader>
           <CardContent className="pt-6">
-            {showBuiltInLogin && (
+            {twoFactorRequired && (
+              <form
+                onSubmit={(e) => {
+                  e.preventDefault()
+                  verifyTwoFactor(twoFactorCode.trim())
+                }}
+                className="space-y-4"
+              >
+                <div className="space-y-2">
+                  <Label htmlFor="twoFactorCode">Two-factor code</Label>
+                  <Input
+                    id="twoFactorCode"
+                    type="text"
+                    inputMode="text"
+                    autoComplete="one-time-code"
+                    autoFocus
+                    value={twoFactorCode}
+                    onChange={(e) => setTwoFactorCode(e.target.value)}
+                    placeholder="6-digit code or recovery code"
+                  />
+                </div>
+
+                {twoFactorError && (
+                  <div className="bg-destructive/10 border border-destructive/20 text-destructive px-4 py-3 rounded-md text-sm">
+                    {twoFactorError.message || "Invalid two-factor code"}
+                  </div>
+                )}
+
+                <Button
+                  type="submit"
+                  className="w-full"
+                  size="lg"
+                  disabled={!twoFactorCode.trim() || isVerifyingTwoFactor}
+                >
+                  {isVerifyingTwoFactor ? "Verifying..." : "Verify"}
+                </Button>
+                <Button
+                  type="button"
+                  variant="ghost"
+                  className="w-full"
+                  onClick={() => {
+                    setTwoFactorCode("")
+                    cancelTwoFactor()
+                  }}
+                >
+                  Back to sign in
+                </Button>
+              </form>
+            )}
+
+            {showBuiltInLogin && !twoFactorRequired && (
               <form
                 onSubmit={(e) => {
                   e.preventDefault()
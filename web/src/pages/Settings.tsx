@@ -8,10 +8,16 @@ import { InstanceCard } from "@/components/instances/InstanceCard"
 import { InstanceForm } from "@/components/instances/InstanceForm"
 import { PasswordIssuesBanner } from "@/components/instances/PasswordIssuesBanner"
 import { ArrInstancesManager } from "@/components/settings/ArrInstancesManager"
+import { CategorySyncPanel } from "@/components/settings/CategorySyncPanel"
+import { DuplicatesPanel } from "@/components/settings/DuplicatesPanel"
 import { ClientApiKeysManager } from "@/components/settings/ClientApiKeysManager"
 import { DateTimePreferencesForm } from "@/components/settings/DateTimePreferencesForm"
 import { ExternalProgramsManager } from "@/components/settings/ExternalProgramsManager"
 import { LogSettingsPanel } from "@/components/settings/LogSettingsPanel"
+import { OIDCLinkSettings } from "@/components/settings/OIDCLinkSettings"
+import { SessionsManager } from "@/components/settings/SessionsManager"
+import { TrackerRotationPanel } from "@/components/settings/TrackerRotationPanel"
+import { TwoFactorSettings } from "@/components/settings/TwoFactorSettings"
 import { LicenseManager } from "@/components/themes/LicenseManager.tsx"
 import { ThemeSelector } from "@/components/themes/ThemeSelector"
 import {
@@ -53,7 +59,7 @@ import type { SettingsSearch } from "@/routes/_authenticated/settings"
 import type { Instance, TorznabSearchCacheStats } from "@/types"
 import { useForm } from "@tanstack/react-form"
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
-import { Clock, Copy, Database, ExternalLink, FileText, Key, Layers, Link2, Loader2, Palette, Plus, RefreshCw, Server, Share2, Shield, Terminal, Trash2 } from "lucide-react"
+import { Clock, Copy, Files, FolderSync, Database, ExternalLink, FileText, Key, KeyRound, Layers, Link2, Loader2, Palette, Plus, RefreshCw, Server, Share2, Shield, Terminal, Trash2 } from "lucide-react"
 import type { FormEvent } from "react"
 import { useEffect, useMemo, useState } from "react"
 import { toast } from "sonner"
@@ -381,6 +387,10 @@ function ApiKeysManager() {
                     {key.lastUsedAt && (
                       <> • Last used: {formatDate(new Date(key.lastUsedAt))}</>
                     )}
+                    {key.lastUsedIp && <> from {key.lastUsedIp}</>}
+                    {key.expiresAt && (
+                      <> • Expires: {formatDate(new Date(key.expiresAt))}</>
+                    )}
                   </p>
                 </div>
                 <Button
@@ -697,6 +707,11 @@ interface SettingsProps {
 
 export function Settings({ search, onSearchChange }: SettingsProps) {
   const activeTab: SettingsTab = search.tab ?? "instances"
+  const { data: oidcConfig } = useQuery({
+    queryKey: ["oidc-config"],
+    queryFn: () => api.getOIDCConfig(),
+    enabled: activeTab === "security",
+  })
 
   const handleTabChange = (tab: SettingsTab) => {
     onSearchChange({ tab })
@@ -763,6 +778,24 @@ export function Settings({ search, onSearchChange }: SettingsProps) {
                 External Programs
               </div>
             </SelectItem>
+            <SelectItem value="tracker-rotation">
+              <div className="flex items-center">
+                <KeyRound className="w-4 h-4 mr-2" />
+                Tracker Rotation
+              </div>
+            </SelectItem>
+            <SelectItem value="category-sync">
+              <div className="flex items-center">
+                <FolderSync className="w-4 h-4 mr-2" />
+                Category Sync
+              </div>
+            </SelectItem>
+            <SelectItem value="duplicates">
+              <div className="flex items-center">
+                <Files className="w-4 h-4 mr-2" />
+                Duplicates
+              </div>
+            </SelectItem>
             <SelectItem value="datetime">
               <div className="flex items-center">
                 <Clock className="w-4 h-4 mr-2" />
@@ -858,6 +891,33 @@ export function Settings({ search, onSearchChange }: SettingsProps) {
               <Terminal className="w-4 h-4 mr-2" />
               External Programs
             </button>
+            <button
+              onClick={() => handleTabChange("tracker-rotation")}
+              className={`w-full flex items-center px-3 py-2 text-sm font-medium rounded-md transition-colors ${
+                activeTab === "tracker-rotation"? "bg-accent text-accent-foreground": "text-muted-foreground hover:bg-accent/50 hover:text-accent-foreground"
+              }`}
+            >
+              <KeyRound className="w-4 h-4 mr-2" />
+              Tracker Rotation
+            </button>
+            <button
+              onClick={() => handleTabChange("category-sync")}
+              className={`w-full flex items-center px-3 py-2 text-sm font-medium rounded-md transition-colors ${
+                activeTab === "category-sync"? "bg-accent text-accent-foreground": "text-muted-foreground hover:bg-accent/50 hover:text-accent-foreground"
+              }`}
+            >
+              <FolderSync className="w-4 h-4 mr-2" />
+              Category Sync
+            </button>
+            <button
+              onClick={() => handleTabChange("duplicates")}
+              className={`w-full flex items-center px-3 py-2 text-sm font-medium rounded-md transition-colors ${
+                activeTab === "duplicates"? "bg-accent text-accent-foreground": "text-muted-foreground hover:bg-accent/50 hover:text-accent-foreground"
+              }`}
+            >
+              <Files className="w-4 h-4 mr-2" />
+              Duplicates
+            </button>
             <button
               onClick={() => handleTabChange("datetime")}
               className={`w-full flex items-center px-3 py-2 text-sm font-medium rounded-md transition-colors ${
@@ -1006,6 +1066,54 @@ export function Settings({ search, onSearchChange }: SettingsProps) {
             </div>
           )}
 
+          {activeTab === "tracker-rotation" && (
+            <div className="space-y-4">
+              <Card>
+                <CardHeader>
+                  <CardTitle>Tracker Rotation</CardTitle>
+                  <CardDescription>
+                    Replace an old announce URL or passkey on every instance and in cached backups
+                  </CardDescription>
+                </CardHeader>
+                <CardContent>
+                  <TrackerRotationPanel />
+                </CardContent>
+              </Card>
+            </div>
+          )}
+
+          {activeTab === "category-sync" && (
+            <div className="space-y-4">
+              <Card>
+                <CardHeader>
+                  <CardTitle>Category Sync</CardTitle>
+                  <CardDescription>
+                    Keep categories and tags identical across instances from one set of templates
+                  </CardDescription>
+                </CardHeader>
+                <CardContent>
+                  <CategorySyncPanel />
+                </CardContent>
+              </Card>
+            </div>
+          )}
+
+          {activeTab === "duplicates" && (
+            <div className="space-y-4">
+              <Card>
+                <CardHeader>
+                  <CardTitle>Duplicates</CardTitle>
+                  <CardDescription>
+                    Find torrents holding the same content across instances and reclaim space used by separate copies
+                  </CardDescription>
+                </CardHeader>
+                <CardContent>
+                  <DuplicatesPanel />
+                </CardContent>
+              </Card>
+            </div>
+          )}
+
           {activeTab === "datetime" && (
             <div className="space-y-4">
               <Card>
@@ -1042,6 +1150,41 @@ export function Settings({ search, onSearchChange }: SettingsProps) {
                   <ChangePasswordForm />
                 </CardContent>
               </Card>
+              <Card>
+                <CardHeader>
+                  <CardTitle>Two-Factor Authentication</CardTitle>
+                  <CardDescription>
+                    Require a code from an authenticator app when signing in with your password
+                  </CardDescription>
+                </CardHeader>
+                <CardContent>
+                  <TwoFactorSettings />
+                </CardContent>
+              </Card>
+              {oidcConfig?.enabled && (
+                <Card>
+                  <CardHeader>
+                    <CardTitle>OIDC Login</CardTitle>
+                    <CardDescription>
+                      Sign in to this account through your identity provider
+                    </CardDescription>
+                  </CardHeader>
+                  <CardContent>
+                    <OIDCLinkSettings />
+                  </CardContent>
+                </Card>
+              )}
+              <Card>
+                <CardHeader>
+                  <CardTitle>Sessions</CardTitle>
+                  <CardDescription>
+                    Devices signed in to your account. Changing your password signs out all other sessions.
+                  </CardDescription>
+                </CardHeader>
+                <CardContent>
+                  <SessionsManager />
+                </CardContent>
+              </Card>
             </div>
           )}
 
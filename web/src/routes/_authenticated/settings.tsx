@@ -16,6 +16,9 @@ const settingsSearchSchema = z.object({
     "client-api",
     "api",
     "external-programs",
+    "tracker-rotation",
+    "category-sync",
+    "duplicates",
     "datetime",
     "themes",
     "security",
@@ -3,17 +3,63 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+export type UserRole = "admin" | "operator" | "read_only"
+
 export interface User {
   id?: number
   username: string
+  role?: UserRole
+  allInstances?: boolean
+  instanceIds?: number[]
+  oidc?: boolean
+  twoFactorEnabled?: boolean
   createdAt?: string
   updatedAt?: string
   auth_method?: string
 }
 
+export interface UserRequest {
+  username: string
+  password?: string
+  role: UserRole
+  allInstances: boolean
+  instanceIds: number[]
+}
+
+export type ApiKeyScope = "read" | "torrents:write" | "crossseed:apply" | "backups" | "admin"
+
+export interface ApiKeyOptions {
+  scopes?: ApiKeyScope[]
+  instanceIds?: number[]
+  allowedIps?: string[]
+  expiresAt?: string
+}
+
 export interface AuthResponse {
-  user: User
+  user?: User
   message?: string
+  twoFactorRequired?: boolean
+}
+
+export interface TwoFactorStatus {
+  enabled: boolean
+  recoveryCodesRemaining: number
+}
+
+export interface Session {
+  id: string
+  authMethod?: string
+  createdAt?: string
+  lastSeenAt?: string
+  ip?: string
+  userAgent?: string
+  current: boolean
+}
+
+export interface TwoFactorEnrollment {
+  secret: string
+  url: string
+  qrCode: string
 }
 
 export interface Instance {
@@ -67,6 +113,17 @@ export interface InstanceReannounceSettings {
   tags: string[]
   excludeTrackers: boolean
   trackers: string[]
+  trackerProfiles: ReannounceTrackerProfile[]
+}
+
+// Overrides instance reannounce timing for a tracker domain or tracker display name.
+// Omitted fields inherit the instance setting.
+export interface ReannounceTrackerProfile {
+  tracker: string
+  initialWaitSeconds?: number
+  reannounceIntervalSeconds?: number
+  maxRetries?: number
+  aggressive?: boolean
 }
 
 // Reannounce settings constraints - shared across components
@@ -118,6 +175,16 @@ export interface InstanceReannounceActivity {
   timestamp: string
 }
 
+export interface InstanceReannounceTrackerStats {
+  tracker: string
+  attempts: number
+  succeeded: number
+  failed: number
+  successRate: number
+  avgTimeToWorkingSeconds?: number
+  lastAttemptAt: string
+}
+
 export interface InstanceReannounceCandidate {
   instanceId: number
   hash: string
@@ -129,6 +196,7 @@ export interface InstanceReannounceCandidate {
   state: "watching" | "reannouncing" | "cooldown"
   hasTrackerProblem: boolean
   waitingForInitial: boolean
+  profile?: string
 }
 
 export interface InstanceError {
@@ -168,6 +236,10 @@ export type ConditionField =
   | "ADDED_ON_AGE"
   | "COMPLETION_ON_AGE"
   | "LAST_ACTIVITY_AGE"
+  // Upload history fields
+  | "UPLOADED_LAST_7D"
+  | "UPLOADED_LAST_30D"
+  | "LAST_UPLOAD_AGE"
   // Numeric fields (float64)
   | "RATIO"
   | "PROGRESS"
@@ -243,6 +315,7 @@ export interface PauseAction {
 export interface DeleteAction {
   enabled: boolean
   mode?: "delete" | "deleteWithFiles" | "deleteWithFilesPreserveCrossSeeds"
+  ignoreHnr?: boolean
   condition?: RuleCondition
 }
 
@@ -468,6 +541,25 @@ export interface TorrentFile {
   size: number
 }
 
+export interface TorrentUploadHistory {
+  uploaded_last_7d?: number
+  uploaded_last_30d?: number
+  // Absent when no upload has been seen since tracked_since
+  last_upload?: number
+  tracked_since: number
+}
+
+export type HnRStatus = "exempt" | "compliant" | "downloading" | "pending" | "at_risk" | "violated"
+
+export interface TorrentHnRCompliance {
+  status: HnRStatus
+  profile_id: number
+  profile_name: string
+  seed_time_remaining?: number
+  ratio_remaining?: number
+  deadline?: number
+}
+
 export interface Torrent {
   added_on: number
   amount_left: number
@@ -521,6 +613,8 @@ export interface Torrent {
   trackers_count: number
   trackers?: TorrentTracker[]
   tracker_health?: "unregistered" | "tracker_down"
+  upload_history?: TorrentUploadHistory
+  hnr?: TorrentHnRCompliance
   up_limit: number
   uploaded: number
   uploaded_session: number
@@ -1840,7 +1934,8 @@ export type OrphanScanRunStatus =
 
 export type OrphanScanTriggerType = "manual" | "scheduled"
 
-export type OrphanScanFileStatus = "pending" | "deleted" | "skipped" | "failed"
+export type OrphanScanFileStatus = "pending" | "deleted" | "skipped" | "failed" | "quarantined" | "restored"
+export type OrphanScanLinkClass = "unique" | "hardlinked_orphan" | "hardlinked_elsewhere"
 
 export interface OrphanScanSettings {
   id?: number
@@ -1852,10 +1947,26 @@ export interface OrphanScanSettings {
   maxFilesPerRun: number
   autoCleanupEnabled: boolean
   autoCleanupMaxFiles: number
+  autoCleanupUnique: boolean
+  autoCleanupHardlinkedOrphans: boolean
+  autoCleanupHardlinkedElsewhere: boolean
+  quarantineEnabled: boolean
+  quarantineRetentionDays: number
+  sharedInstances: OrphanScanSharedInstance[]
   createdAt?: string
   updatedAt?: string
 }
 
+export interface OrphanScanPathMapping {
+  from: string
+  to: string
+}
+
+export interface OrphanScanSharedInstance {
+  instanceId: number
+  pathMappings: OrphanScanPathMapping[]
+}
+
 export interface OrphanScanSettingsUpdate {
   enabled?: boolean
   gracePeriodMinutes?: number
@@ -1864,6 +1975,53 @@ export interface OrphanScanSettingsUpdate {
   maxFilesPerRun?: number
   autoCleanupEnabled?: boolean
   autoCleanupMaxFiles?: number
+  autoCleanupUnique?: boolean
+  autoCleanupHardlinkedOrphans?: boolean
+  autoCleanupHardlinkedElsewhere?: boolean
+  quarantineEnabled?: boolean
+  quarantineRetentionDays?: number
+  sharedInstances?: OrphanScanSharedInstance[]
+}
+
+export interface IntegritySettings {
+  id?: number
+  instanceId: number
+  enabled: boolean
+  intervalMinutes: number
+  torrentsPerRun: number
+  recheckAfterDays: number
+  maxReadMiBPerSecond: number
+  failureTag: string
+  createdAt?: string
+  updatedAt?: string
+}
+
+export type IntegritySettingsUpdate = Partial<Omit<IntegritySettings, "id" | "instanceId" | "createdAt" | "updatedAt">>
+
+export type IntegrityCheckStatus = "running" | "passed" | "failed" | "error" | "canceled"
+
+export interface IntegrityBadFile {
+  path: string
+  corruptPieces: number
+  missingPieces: number
+}
+
+export interface IntegrityCheck {
+  id: number
+  instanceId: number
+  hash: string
+  torrentName: string
+  status: IntegrityCheckStatus
+  triggeredBy: "manual" | "scheduled"
+  piecesTotal: number
+  piecesChecked: number
+  corruptPieces: number
+  missingPieces: number
+  bytesChecked: number
+  badFiles: IntegrityBadFile[]
+  errorMessage?: string
+  startedAt: string
+  completedAt?: string
 }
 
 export interface OrphanScanRun {
@@ -1876,6 +2034,9 @@ export interface OrphanScanRun {
   filesDeleted: number
   foldersDeleted: number
   bytesReclaimed: number
+  bytesReclaimable: number
+  filesQuarantined: number
+  bytesQuarantined: number
   truncated: boolean
   errorMessage?: string | null
   startedAt: string
@@ -1887,9 +2048,16 @@ export interface OrphanScanFile {
   runId: number
   filePath: string
   fileSize: number
+  linkClass: OrphanScanLinkClass
   modifiedAt?: string | null
   status: OrphanScanFileStatus
   errorMessage?: string | null
+  quarantinePath?: string
+}
+
+export interface OrphanScanRestoreResult {
+  restored: number
+  failed: number
 }
 
 export interface OrphanScanRunWithFiles extends OrphanScanRun {
@@ -1912,3 +2080,229 @@ export interface LogSettingsUpdate {
   maxSize?: number
   maxBackups?: number
 }
+
+export interface TrackerRotationSpec {
+  domain?: string
+  find: string
+  replace: string
+  rewriteBackups: boolean
+}
+
+export interface TrackerRotationChange {
+  oldUrl: string
+  newUrl: string
+}
+
+export interface TrackerRotationTorrent {
+  hash: string
+  name: string
+  trackers: TrackerRotationChange[]
+}
+
+export interface TrackerRotationBackupResult {
+  blobsScanned: number
+  blobsMatched: number
+  blobsRewritten: number
+  blobsFailed: number
+  itemsUpdated: number
+}
+
+export interface TrackerRotationPreview {
+  instances: {
+    instanceId: number
+    instanceName: string
+    torrents?: TrackerRotationTorrent[]
+    error?: string
+  }[]
+  totalTorrents: number
+  backups?: TrackerRotationBackupResult
+}
+
+export type BulkActionType =
+  | "pause"
+  | "resume"
+  | "delete"
+  | "recheck"
+  | "reannounce"
+  | "increasePriority"
+  | "decreasePriority"
+  | "topPriority"
+  | "bottomPriority"
+  | "setCategory"
+  | "addTags"
+  | "removeTags"
+  | "setTags"
+  | "toggleAutoTMM"
+  | "forceStart"
+  | "setShareLimit"
+  | "setUploadLimit"
+  | "setDownloadLimit"
+  | "setLocation"
+  | "editTrackers"
+  | "addTrackers"
+  | "removeTrackers"
+  | "toggleSequentialDownload"
+
+export interface BulkActionRequest {
+  hashes: string[]
+  action: BulkActionType
+  deleteFiles?: boolean
+  category?: string
+  tags?: string  // Comma-separated tags string
+  enable?: boolean  // For toggleAutoTMM
+  selectAll?: boolean  // When true, apply to all torrents matching filters
+  filters?: TorrentFilters
+  search?: string  // Search query when selectAll is true
+  excludeHashes?: string[]  // Hashes to exclude when selectAll is true
+  ratioLimit?: number  // For setShareLimit action
+  seedingTimeLimit?: number  // For setShareLimit action (minutes)
+  inactiveSeedingTimeLimit?: number  // For setShareLimit action (minutes)
+  uploadLimit?: number  // For setUploadLimit action (KB/s)
+  downloadLimit?: number  // For setDownloadLimit action (KB/s)
+  location?: string  // For setLocation action
+  trackerOldURL?: string  // For editTrackers action
+  trackerNewURL?: string  // For editTrackers action
+  trackerURLs?: string  // For addTrackers/removeTrackers actions (newline-separated)
+}
+
+export type BulkJobStatus = "running" | "completed" | "failed" | "canceled"
+
+export interface BulkJob {
+  id: number
+  instanceId: number
+  spec: Omit<BulkActionRequest, "hashes" | "selectAll" | "filters" | "search" | "excludeHashes">
+  status: BulkJobStatus
+  progress: {
+    processed: number
+    total: number
+  }
+  succeeded: number
+  failed: number
+  errors?: { hash: string; error: string }[]
+  undoable: boolean
+  undoOf?: number
+  undoneBy?: number
+  startedAt: string
+  completedAt?: string
+}
+
+export type TrackerRotationJobStatus = "running" | "completed" | "failed"
+
+export interface TrackerRotationInstanceResult {
+  instanceId: number
+  instanceName: string
+  matched: number
+  updated: number
+  failed: number
+  error?: string
+}
+
+export interface TrackerRotationJob {
+  id: number
+  spec: TrackerRotationSpec
+  status: TrackerRotationJobStatus
+  progress: {
+    phase: "torrents" | "backups"
+    processed: number
+    total: number
+  }
+  instances: TrackerRotationInstanceResult[]
+  backups?: TrackerRotationBackupResult
+  error?: string
+  startedAt: string
+  completedAt?: string
+}
+
+export interface CategoryTemplate {
+  id?: number
+  name: string
+  savePath: string
+  createdAt?: string
+  updatedAt?: string
+}
+
+export interface CategoryTemplates {
+  categories: CategoryTemplate[]
+  tags: string[]
+}
+
+export interface CategorySyncTarget {
+  instanceId: number
+  savePathPrefix: string
+  createdAt?: string
+  updatedAt?: string
+}
+
+export type CategoryDriftStatus = "missing" | "different" | "extra"
+
+export interface CategoryDrift {
+  name: string
+  status: CategoryDriftStatus
+  expectedSavePath: string
+  actualSavePath: string
+}
+
+export interface CategorySyncDrift {
+  instanceId: number
+  instanceName: string
+  savePathPrefix: string
+  categories: CategoryDrift[]
+  missingTags: string[]
+  extraTags: string[]
+  inSync: boolean
+  error?: string
+}
+
+export interface CategorySyncReconcileResult {
+  instanceId: number
+  categoriesCreated: number
+  categoriesUpdated: number
+  categoriesRemoved: number
+  tagsCreated: number
+  tagsRemoved: number
+  errors?: string[]
+  drift?: CategorySyncDrift
+}
+
+export type DuplicateMatch = "infohash" | "content" | "release"
+
+export interface DuplicateMember {
+  instanceId: number
+  instanceName: string
+  hash: string
+  name: string
+  size: number
+  savePath: string
+  contentPath: string
+  category: string
+  tags: string
+  tracker: string
+  state: string
+  storage: number
+  localAccess: boolean
+}
+
+export interface DuplicateGroup {
+  id: string
+  name: string
+  kind: "cross-seed" | "duplicate"
+  matchedBy: DuplicateMatch[]
+  size: number
+  copies: number
+  reclaimableBytes: number
+  members: DuplicateMember[]
+}
+
+export interface DuplicateReport {
+  groups: DuplicateGroup[]
+  duplicateGroups: number
+  crossSeedGroups: number
+  reclaimableBytes: number
+  errors?: { instanceId: number; instanceName: string; error: string }[]
+  generatedAt: string
+}
+
+export interface DuplicateHardlinkResult {
+  filesLinked: number
+  bytesLinked: number
+}